@@ -0,0 +1,169 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchorstatuscmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	tlsutils "github.com/trustbloc/edge-core/pkg/utils/tls"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+)
+
+const (
+	urlFlagName  = "url"
+	urlFlagUsage = "The URL of the anchor status endpoint (e.g. https://orb.domain1.com/anchor/status)." +
+		" Alternatively, this can be set with the following environment variable: " + urlEnvKey
+	urlEnvKey = "ORB_CLI_URL"
+
+	idFlagName  = "id"
+	idFlagUsage = "The ID or hashlink of the anchor credential." +
+		" Alternatively, this can be set with the following environment variable: " + idEnvKey
+	idEnvKey = "ORB_CLI_ID"
+
+	suffixFlagName  = "suffix"
+	suffixFlagUsage = "The unique suffix of a DID. The status of the anchor that includes the DID's most recent" +
+		" operation is returned. Alternatively, this can be set with the following environment variable: " +
+		suffixEnvKey
+	suffixEnvKey = "ORB_CLI_SUFFIX"
+
+	tlsSystemCertPoolFlagName  = "tls-systemcertpool"
+	tlsSystemCertPoolFlagUsage = "Use system certificate pool." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + tlsSystemCertPoolEnvKey
+	tlsSystemCertPoolEnvKey = "ORB_CLI_TLS_SYSTEMCERTPOOL"
+
+	tlsCACertsFlagName  = "tls-cacerts"
+	tlsCACertsFlagUsage = "Comma-Separated list of ca certs path." +
+		" Alternatively, this can be set with the following environment variable: " + tlsCACertsEnvKey
+	tlsCACertsEnvKey = "ORB_CLI_TLS_CACERTS"
+
+	authTokenFlagName  = "auth-token"
+	authTokenFlagUsage = "Auth token." +
+		" Alternatively, this can be set with the following environment variable: " + authTokenEnvKey
+	authTokenEnvKey = "ORB_CLI_AUTH_TOKEN" //nolint:gosec
+)
+
+const (
+	idParam     = "id"
+	suffixParam = "suffix"
+)
+
+// GetCmd returns the Cobra anchor status command.
+func GetCmd() *cobra.Command {
+	cmd := cmd()
+
+	createFlags(cmd)
+
+	return cmd
+}
+
+func cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "anchor status",
+		Long: "Returns the status of an anchor credential along with the witnesses that were offered the anchor " +
+			"credential, the proofs received so far and the result of evaluating the witness policy.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootCAs, err := getRootCAs(cmd)
+			if err != nil {
+				return err
+			}
+
+			httpClient := &http.Client{
+				Transport: &http.Transport{
+					TLSClientConfig: &tls.Config{
+						RootCAs:    rootCAs,
+						MinVersion: tls.VersionTLS12,
+					},
+				},
+			}
+
+			statusURL, err := cmdutils.GetUserSetVarFromString(cmd, urlFlagName, urlEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			id := cmdutils.GetUserSetOptionalVarFromString(cmd, idFlagName, idEnvKey)
+			suffix := cmdutils.GetUserSetOptionalVarFromString(cmd, suffixFlagName, suffixEnvKey)
+
+			if (id == "") == (suffix == "") {
+				return fmt.Errorf("either %s or %s must be specified", idFlagName, suffixFlagName)
+			}
+
+			authToken := cmdutils.GetUserSetOptionalVarFromString(cmd, authTokenFlagName, authTokenEnvKey)
+
+			params := url.Values{}
+
+			if id != "" {
+				params.Set(idParam, id)
+			} else {
+				params.Set(suffixParam, suffix)
+			}
+
+			headers := make(map[string]string)
+			if authToken != "" {
+				headers["Authorization"] = "Bearer " + authToken
+			}
+
+			resp, err := common.SendRequest(httpClient, nil, headers, http.MethodGet,
+				statusURL+"?"+params.Encode())
+			if err != nil {
+				return fmt.Errorf("failed to send http request: %w", err)
+			}
+
+			var status bytes.Buffer
+
+			err = json.Indent(&status, resp, "", "  ")
+			if err != nil {
+				return fmt.Errorf("invalid anchor status response: %w", err)
+			}
+
+			fmt.Println(status.String())
+
+			return nil
+		},
+	}
+}
+
+func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
+	tlsSystemCertPoolString := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
+		tlsSystemCertPoolEnvKey)
+
+	tlsSystemCertPool := false
+
+	if tlsSystemCertPoolString != "" {
+		var err error
+		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tlsCACerts := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
+		tlsCACertsEnvKey)
+
+	return tlsutils.GetCertPool(tlsSystemCertPool, tlsCACerts)
+}
+
+func createFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "", tlsSystemCertPoolFlagUsage)
+	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	startCmd.Flags().StringP(urlFlagName, "", "", urlFlagUsage)
+	startCmd.Flags().StringP(idFlagName, "", "", idFlagUsage)
+	startCmd.Flags().StringP(suffixFlagName, "", "", suffixFlagUsage)
+	startCmd.Flags().StringP(authTokenFlagName, "", "", authTokenFlagUsage)
+}
@@ -0,0 +1,159 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchorstatuscmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	flag = "--"
+)
+
+func TestTLSSystemCertPoolInvalidArgsEnvVar(t *testing.T) {
+	startCmd := GetCmd()
+
+	require.NoError(t, os.Setenv(tlsSystemCertPoolEnvKey, "wrongvalue"))
+	defer os.Clearenv()
+
+	err := startCmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid syntax")
+}
+
+func TestStartCmdWithMissingArg(t *testing.T) {
+	t.Run("test missing url arg", func(t *testing.T) {
+		startCmd := GetCmd()
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither url (command line flag) nor ORB_CLI_URL (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test missing id and suffix args", func(t *testing.T) {
+		startCmd := GetCmd()
+
+		startCmd.SetArgs(statusURL("localhost:8080"))
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t, "either id or suffix must be specified", err.Error())
+	})
+
+	t.Run("test both id and suffix args", func(t *testing.T) {
+		startCmd := GetCmd()
+
+		var args []string
+		args = append(args, statusURL("localhost:8080")...)
+		args = append(args, id("id")...)
+		args = append(args, suffix("suffix")...)
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t, "either id or suffix must be specified", err.Error())
+	})
+}
+
+func TestAnchorStatus(t *testing.T) {
+	var query string
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+
+		_, err := fmt.Fprint(w, `{"anchorCredentialId":"https://orb.domain1.com/vc/1234","status":"in-process"}`)
+		require.NoError(t, err)
+	}))
+	defer serv.Close()
+
+	t.Run("success - by ID", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetCmd()
+
+		var args []string
+		args = append(args, statusURL(serv.URL)...)
+		args = append(args, id("https://orb.domain1.com/vc/1234")...)
+		args = append(args, authToken("ADMIN_TOKEN")...)
+		cmd.SetArgs(args)
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, "id=https%3A%2F%2Forb.domain1.com%2Fvc%2F1234", query)
+	})
+
+	t.Run("success - by suffix", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetCmd()
+
+		var args []string
+		args = append(args, statusURL(serv.URL)...)
+		args = append(args, suffix("EiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA")...)
+		cmd.SetArgs(args)
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, "suffix=EiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA", query)
+	})
+
+	t.Run("failed to send request", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetCmd()
+
+		var args []string
+		args = append(args, statusURL("wrongurl")...)
+		args = append(args, id("id")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to send http request")
+	})
+
+	t.Run("invalid response", func(t *testing.T) {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := fmt.Fprint(w, "{")
+			require.NoError(t, err)
+		}))
+		defer s.Close()
+
+		os.Clearenv()
+		cmd := GetCmd()
+
+		var args []string
+		args = append(args, statusURL(s.URL)...)
+		args = append(args, id("id")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid anchor status response")
+	})
+}
+
+func statusURL(value string) []string {
+	return []string{flag + urlFlagName, value}
+}
+
+func id(value string) []string {
+	return []string{flag + idFlagName, value}
+}
+
+func suffix(value string) []string {
+	return []string{flag + suffixFlagName, value}
+}
+
+func authToken(value string) []string {
+	return []string{flag + authTokenFlagName, value}
+}
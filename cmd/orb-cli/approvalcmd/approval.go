@@ -0,0 +1,211 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package approvalcmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	tlsutils "github.com/trustbloc/edge-core/pkg/utils/tls"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
+)
+
+const (
+	urlFlagName  = "url"
+	urlFlagUsage = "The URL of the pending approvals endpoint (e.g. https://orb.domain1.com/services/orb/pendingapprovals)." +
+		" Alternatively, this can be set with the following environment variable: " + urlEnvKey
+	urlEnvKey = "ORB_CLI_URL"
+
+	activityIDFlagName  = "activity-id"
+	activityIDFlagUsage = "The ID of the 'Follow' or 'InviteWitness' activity that is pending approval." +
+		" Alternatively, this can be set with the following environment variable: " + activityIDEnvKey
+	activityIDEnvKey = "ORB_CLI_ACTIVITY_ID"
+
+	tlsSystemCertPoolFlagName  = "tls-systemcertpool"
+	tlsSystemCertPoolFlagUsage = "Use system certificate pool." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + tlsSystemCertPoolEnvKey
+	tlsSystemCertPoolEnvKey = "ORB_CLI_TLS_SYSTEMCERTPOOL"
+
+	tlsCACertsFlagName  = "tls-cacerts"
+	tlsCACertsFlagUsage = "Comma-Separated list of ca certs path." +
+		" Alternatively, this can be set with the following environment variable: " + tlsCACertsEnvKey
+	tlsCACertsEnvKey = "ORB_CLI_TLS_CACERTS"
+
+	authTokenFlagName  = "auth-token"
+	authTokenFlagUsage = "Auth token." +
+		" Alternatively, this can be set with the following environment variable: " + authTokenEnvKey
+	authTokenEnvKey = "ORB_CLI_AUTH_TOKEN" //nolint:gosec
+)
+
+// GetListCmd returns the Cobra command that lists the requests that are pending approval.
+func GetListCmd() *cobra.Command {
+	cmd := listCmd()
+
+	createFlags(cmd, false)
+
+	return cmd
+}
+
+// GetApproveCmd returns the Cobra command that approves a request that is pending approval.
+func GetApproveCmd() *cobra.Command {
+	cmd := actionCmd("approve", resthandler.ApprovalActionApprove,
+		"Approves the 'Follow' or 'InviteWitness' request with the given activity ID. The actor is added to "+
+			"the followers (or witnessing) collection and an 'Accept' activity is sent to the actor.")
+
+	createFlags(cmd, true)
+
+	return cmd
+}
+
+// GetRejectCmd returns the Cobra command that rejects a request that is pending approval.
+func GetRejectCmd() *cobra.Command {
+	cmd := actionCmd("reject", resthandler.ApprovalActionReject,
+		"Rejects the 'Follow' or 'InviteWitness' request with the given activity ID. A 'Reject' activity is "+
+			"sent to the actor.")
+
+	createFlags(cmd, true)
+
+	return cmd
+}
+
+func listCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "list pending approvals",
+		Long: "Lists the 'Follow' and 'InviteWitness' requests that are pending approval along with the details " +
+			"of the actors that sent them.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			httpClient, approvalsURL, headers, err := getRequestParams(cmd)
+			if err != nil {
+				return err
+			}
+
+			resp, err := common.SendRequest(httpClient, nil, headers, http.MethodGet, approvalsURL)
+			if err != nil {
+				return fmt.Errorf("failed to send http request: %w", err)
+			}
+
+			var pending bytes.Buffer
+
+			err = json.Indent(&pending, resp, "", "  ")
+			if err != nil {
+				return fmt.Errorf("invalid pending approvals response: %w", err)
+			}
+
+			fmt.Println(pending.String())
+
+			return nil
+		},
+	}
+}
+
+func actionCmd(use string, action resthandler.ApprovalAction, long string) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: use + " pending request",
+		Long:  long,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			httpClient, approvalsURL, headers, err := getRequestParams(cmd)
+			if err != nil {
+				return err
+			}
+
+			activityID, err := cmdutils.GetUserSetVarFromString(cmd, activityIDFlagName, activityIDEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			reqBytes, err := json.Marshal(&resthandler.ApprovalRequest{
+				ActivityID: activityID,
+				Action:     action,
+			})
+			if err != nil {
+				return fmt.Errorf("marshal approval request: %w", err)
+			}
+
+			_, err = common.SendRequest(httpClient, reqBytes, headers, http.MethodPost, approvalsURL)
+			if err != nil {
+				return fmt.Errorf("failed to send http request: %w", err)
+			}
+
+			fmt.Printf("success: %s %s\n", action, activityID)
+
+			return nil
+		},
+	}
+}
+
+func getRequestParams(cmd *cobra.Command) (*http.Client, string, map[string]string, error) {
+	rootCAs, err := getRootCAs(cmd)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs:    rootCAs,
+				MinVersion: tls.VersionTLS12,
+			},
+		},
+	}
+
+	approvalsURL, err := cmdutils.GetUserSetVarFromString(cmd, urlFlagName, urlEnvKey, false)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	authToken := cmdutils.GetUserSetOptionalVarFromString(cmd, authTokenFlagName, authTokenEnvKey)
+
+	headers := make(map[string]string)
+	if authToken != "" {
+		headers["Authorization"] = "Bearer " + authToken
+	}
+
+	return httpClient, approvalsURL, headers, nil
+}
+
+func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
+	tlsSystemCertPoolString := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
+		tlsSystemCertPoolEnvKey)
+
+	tlsSystemCertPool := false
+
+	if tlsSystemCertPoolString != "" {
+		var err error
+		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tlsCACerts := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
+		tlsCACertsEnvKey)
+
+	return tlsutils.GetCertPool(tlsSystemCertPool, tlsCACerts)
+}
+
+func createFlags(startCmd *cobra.Command, withActivityID bool) {
+	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "", tlsSystemCertPoolFlagUsage)
+	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	startCmd.Flags().StringP(urlFlagName, "", "", urlFlagUsage)
+	startCmd.Flags().StringP(authTokenFlagName, "", "", authTokenFlagUsage)
+
+	if withActivityID {
+		startCmd.Flags().StringP(activityIDFlagName, "", "", activityIDFlagUsage)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package approvalcmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
+)
+
+const (
+	flag = "--"
+
+	activityID = "https://orb.domain2.com/services/orb/activities/123"
+)
+
+func TestTLSSystemCertPoolInvalidArgsEnvVar(t *testing.T) {
+	startCmd := GetListCmd()
+
+	require.NoError(t, os.Setenv(tlsSystemCertPoolEnvKey, "wrongvalue"))
+	defer os.Clearenv()
+
+	err := startCmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid syntax")
+}
+
+func TestCmdWithMissingArg(t *testing.T) {
+	t.Run("test missing url arg", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetListCmd()
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither url (command line flag) nor ORB_CLI_URL (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test missing activity-id arg", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetApproveCmd()
+
+		startCmd.SetArgs(urlArg("localhost:8080"))
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither activity-id (command line flag) nor ORB_CLI_ACTIVITY_ID (environment variable) have been set.",
+			err.Error())
+	})
+}
+
+func TestApprovals(t *testing.T) {
+	var (
+		authHeader string
+		request    *resthandler.ApprovalRequest
+	)
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+
+		if r.Method == http.MethodGet {
+			_, err := w.Write([]byte(`[{"activity":{"id":"` + activityID + `","type":"Follow"}}]`))
+			require.NoError(t, err)
+
+			return
+		}
+
+		request = &resthandler.ApprovalRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(request))
+
+		if request.ActivityID != activityID {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer serv.Close()
+
+	t.Run("list - success", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetListCmd()
+
+		var args []string
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, authTokenArg("ADMIN_TOKEN")...)
+		cmd.SetArgs(args)
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, "Bearer ADMIN_TOKEN", authHeader)
+	})
+
+	t.Run("approve - success", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetApproveCmd()
+
+		var args []string
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, activityIDArg(activityID)...)
+		cmd.SetArgs(args)
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, resthandler.ApprovalActionApprove, request.Action)
+		require.Equal(t, activityID, request.ActivityID)
+	})
+
+	t.Run("reject - success", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetRejectCmd()
+
+		var args []string
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, activityIDArg(activityID)...)
+		cmd.SetArgs(args)
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, resthandler.ApprovalActionReject, request.Action)
+	})
+
+	t.Run("reject - not pending", func(t *testing.T) {
+		os.Clearenv()
+
+		cmd := GetRejectCmd()
+
+		var args []string
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, activityIDArg("https://orb.domain2.com/services/orb/activities/456")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "status '404'")
+	})
+}
+
+func urlArg(value string) []string {
+	return []string{flag + urlFlagName, value}
+}
+
+func activityIDArg(value string) []string {
+	return []string{flag + activityIDFlagName, value}
+}
+
+func authTokenArg(value string) []string {
+	return []string{flag + authTokenFlagName, value}
+}
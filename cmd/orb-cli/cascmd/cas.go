@@ -0,0 +1,199 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cascmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	tlsutils "github.com/trustbloc/edge-core/pkg/utils/tls"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+)
+
+const (
+	urlFlagName  = "url"
+	urlFlagUsage = "The URL of the CAS administrative endpoint (e.g. https://orb.domain1.com/cas/gc or" +
+		" https://orb.domain1.com/cas/scrub)." +
+		" Alternatively, this can be set with the following environment variable: " + urlEnvKey
+	urlEnvKey = "ORB_CLI_URL"
+
+	dryRunFlagName  = "dry-run"
+	dryRunFlagUsage = "Set to false to modify the CAS. Otherwise the affected content is only reported." +
+		" Possible values [true] [false]. Defaults to true if not set." +
+		" Alternatively, this can be set with the following environment variable: " + dryRunEnvKey
+	dryRunEnvKey = "ORB_CLI_DRY_RUN"
+
+	tlsSystemCertPoolFlagName  = "tls-systemcertpool"
+	tlsSystemCertPoolFlagUsage = "Use system certificate pool." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + tlsSystemCertPoolEnvKey
+	tlsSystemCertPoolEnvKey = "ORB_CLI_TLS_SYSTEMCERTPOOL"
+
+	tlsCACertsFlagName  = "tls-cacerts"
+	tlsCACertsFlagUsage = "Comma-Separated list of ca certs path." +
+		" Alternatively, this can be set with the following environment variable: " + tlsCACertsEnvKey
+	tlsCACertsEnvKey = "ORB_CLI_TLS_CACERTS"
+
+	authTokenFlagName  = "auth-token"
+	authTokenFlagUsage = "Auth token." +
+		" Alternatively, this can be set with the following environment variable: " + authTokenEnvKey
+	authTokenEnvKey = "ORB_CLI_AUTH_TOKEN" //nolint:gosec
+)
+
+const dryRunParam = "dryrun"
+
+// GetGCCmd returns the Cobra command that runs CAS garbage collection.
+func GetGCCmd() *cobra.Command {
+	cmd := newCmd("gc", "collect CAS garbage",
+		"Deletes CAS content which isn't referenced by any anchor and which is older than the grace period "+
+			"configured on the server. The unreferenced content is only reported unless --dry-run is false.",
+		func(cmd *cobra.Command, httpClient *http.Client, endpointURL string, headers map[string]string) error {
+			params, err := getDryRunParam(cmd)
+			if err != nil {
+				return err
+			}
+
+			return send(httpClient, headers, http.MethodPost, endpointURL+"?"+params.Encode())
+		},
+	)
+
+	createFlags(cmd)
+	cmd.Flags().StringP(dryRunFlagName, "", "", dryRunFlagUsage)
+
+	return cmd
+}
+
+// GetScrubCmd returns the Cobra command that verifies the integrity of the CAS.
+func GetScrubCmd() *cobra.Command {
+	cmd := newCmd("scrub", "verify CAS integrity",
+		"Recomputes the hash of each piece of content in the local CAS. Corrupt content is moved to quarantine and, "+
+			"if repair is configured on the server, a good copy is retrieved from a WebCAS peer or IPFS. "+
+			"The corrupt content is only reported unless --dry-run is false.",
+		func(cmd *cobra.Command, httpClient *http.Client, endpointURL string, headers map[string]string) error {
+			params, err := getDryRunParam(cmd)
+			if err != nil {
+				return err
+			}
+
+			return send(httpClient, headers, http.MethodPost, endpointURL+"?"+params.Encode())
+		},
+	)
+
+	createFlags(cmd)
+	cmd.Flags().StringP(dryRunFlagName, "", "", dryRunFlagUsage)
+
+	return cmd
+}
+
+type runFunc func(cmd *cobra.Command, httpClient *http.Client, endpointURL string, headers map[string]string) error
+
+func newCmd(use, short, long string, run runFunc) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long:  long,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootCAs, err := getRootCAs(cmd)
+			if err != nil {
+				return err
+			}
+
+			httpClient := &http.Client{
+				Transport: &http.Transport{
+					TLSClientConfig: &tls.Config{
+						RootCAs:    rootCAs,
+						MinVersion: tls.VersionTLS12,
+					},
+				},
+			}
+
+			endpointURL, err := cmdutils.GetUserSetVarFromString(cmd, urlFlagName, urlEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			authToken := cmdutils.GetUserSetOptionalVarFromString(cmd, authTokenFlagName, authTokenEnvKey)
+
+			headers := make(map[string]string)
+			if authToken != "" {
+				headers["Authorization"] = "Bearer " + authToken
+			}
+
+			return run(cmd, httpClient, endpointURL, headers)
+		},
+	}
+}
+
+func send(httpClient *http.Client, headers map[string]string, method, endpointURL string) error {
+	resp, err := common.SendRequest(httpClient, nil, headers, method, endpointURL)
+	if err != nil {
+		return fmt.Errorf("failed to send http request: %w", err)
+	}
+
+	var out bytes.Buffer
+
+	err = json.Indent(&out, resp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("invalid CAS response: %w", err)
+	}
+
+	fmt.Println(out.String())
+
+	return nil
+}
+
+func getDryRunParam(cmd *cobra.Command) (url.Values, error) {
+	params := url.Values{}
+
+	dryRunStr := cmdutils.GetUserSetOptionalVarFromString(cmd, dryRunFlagName, dryRunEnvKey)
+	if dryRunStr != "" {
+		dryRun, err := strconv.ParseBool(dryRunStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", dryRunFlagName, err)
+		}
+
+		params.Set(dryRunParam, strconv.FormatBool(dryRun))
+	}
+
+	return params, nil
+}
+
+func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
+	tlsSystemCertPoolString := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
+		tlsSystemCertPoolEnvKey)
+
+	tlsSystemCertPool := false
+
+	if tlsSystemCertPoolString != "" {
+		var err error
+		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tlsCACerts := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
+		tlsCACertsEnvKey)
+
+	return tlsutils.GetCertPool(tlsSystemCertPool, tlsCACerts)
+}
+
+func createFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "", tlsSystemCertPoolFlagUsage)
+	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	startCmd.Flags().StringP(urlFlagName, "", "", urlFlagUsage)
+	startCmd.Flags().StringP(authTokenFlagName, "", "", authTokenFlagUsage)
+}
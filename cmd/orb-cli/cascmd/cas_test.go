@@ -0,0 +1,131 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cascmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const flag = "--"
+
+func TestTLSSystemCertPoolInvalidArgsEnvVar(t *testing.T) {
+	startCmd := GetGCCmd()
+
+	require.NoError(t, os.Setenv(tlsSystemCertPoolEnvKey, "wrongvalue"))
+	defer os.Clearenv()
+
+	err := startCmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid syntax")
+}
+
+func TestCmdWithMissingArg(t *testing.T) {
+	t.Run("test missing url arg", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetGCCmd()
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither url (command line flag) nor ORB_CLI_URL (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test invalid dry-run arg", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetScrubCmd()
+
+		startCmd.SetArgs([]string{flag + urlFlagName, "localhost:8080", flag + dryRunFlagName, "xxx"})
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for dry-run")
+	})
+}
+
+func TestCAS(t *testing.T) {
+	var (
+		authHeader string
+		method     string
+		path       string
+		query      url.Values
+	)
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		method = r.Method
+		path = r.URL.Path
+		query = r.URL.Query()
+
+		if r.URL.Path == "/invalid" {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		_, err := w.Write([]byte(`{"dryRun":true,"scanned":3,"referenced":2,"unreferenced":["hash1"]}`))
+		require.NoError(t, err)
+	}))
+	defer serv.Close()
+
+	t.Run("dry run", func(t *testing.T) {
+		cmd := GetGCCmd()
+
+		cmd.SetArgs([]string{
+			flag + urlFlagName, serv.URL + "/cas/gc",
+			flag + dryRunFlagName, "true",
+			flag + authTokenFlagName, "ADMIN_TOKEN",
+		})
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, http.MethodPost, method)
+		require.Equal(t, "/cas/gc", path)
+		require.Equal(t, "true", query.Get(dryRunParam))
+		require.Equal(t, "Bearer ADMIN_TOKEN", authHeader)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		cmd := GetGCCmd()
+
+		cmd.SetArgs([]string{
+			flag + urlFlagName, serv.URL + "/cas/gc",
+			flag + dryRunFlagName, "false",
+		})
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, "false", query.Get(dryRunParam))
+	})
+
+	t.Run("scrub", func(t *testing.T) {
+		cmd := GetScrubCmd()
+
+		cmd.SetArgs([]string{flag + urlFlagName, serv.URL + "/cas/scrub"})
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, http.MethodPost, method)
+		require.Equal(t, "/cas/scrub", path)
+		require.Empty(t, query.Get(dryRunParam))
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		cmd := GetGCCmd()
+
+		cmd.SetArgs([]string{flag + urlFlagName, serv.URL + "/invalid"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,123 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+// ErrActivityNotFound is returned by FindOutboxActivity if no matching activity was found in the outbox.
+var ErrActivityNotFound = errors.New("activity not found in outbox")
+
+type activitiesPage struct {
+	OrderedItems []json.RawMessage `json:"orderedItems,omitempty"`
+	Items        []json.RawMessage `json:"items,omitempty"`
+}
+
+// FindOutboxActivity pages through the outbox at the given URL and returns the first activity for which the given
+// match function returns true. Activities that have already been undone (i.e. an 'Undo' for the activity exists
+// in the outbox) are ignored. ErrActivityNotFound is returned if no matching activity was found.
+func FindOutboxActivity(httpClient *http.Client, headers map[string]string, outboxURL string,
+	match func(activity *vocab.ActivityType) bool) (*vocab.ActivityType, error) {
+	respBytes, err := SendRequest(httpClient, nil, headers, http.MethodGet, outboxURL)
+	if err != nil {
+		return nil, fmt.Errorf("get outbox: %w", err)
+	}
+
+	coll := &vocab.OrderedCollectionType{}
+
+	err = json.Unmarshal(respBytes, coll)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal outbox: %w", err)
+	}
+
+	if !coll.Type().Is(vocab.TypeOrderedCollection) {
+		return nil, fmt.Errorf("unmarshal outbox: expecting OrderedCollection but got %s", coll.Type())
+	}
+
+	undone := make(map[string]bool)
+
+	var matches []*vocab.ActivityType
+
+	for next := coll.First(); next != nil; {
+		pageBytes, e := SendRequest(httpClient, nil, headers, http.MethodGet, next.String())
+		if e != nil {
+			return nil, fmt.Errorf("get outbox page: %w", e)
+		}
+
+		page := &vocab.OrderedCollectionPageType{}
+
+		if e := json.Unmarshal(pageBytes, page); e != nil {
+			return nil, fmt.Errorf("unmarshal outbox page: %w", e)
+		}
+
+		activities, e := unmarshalActivities(pageBytes)
+		if e != nil {
+			return nil, fmt.Errorf("unmarshal outbox page: %w", e)
+		}
+
+		for _, activity := range activities {
+			switch {
+			case activity.Type().Is(vocab.TypeUndo):
+				if a := activity.Object().Activity(); a != nil && a.ID() != nil {
+					undone[a.ID().String()] = true
+				}
+			case match(activity):
+				matches = append(matches, activity)
+			}
+		}
+
+		next = page.Next()
+	}
+
+	for _, activity := range matches {
+		if !undone[activity.ID().String()] {
+			return activity, nil
+		}
+	}
+
+	return nil, ErrActivityNotFound
+}
+
+// unmarshalActivities unmarshals the items of the given collection page directly into activities since
+// some activity types (e.g. 'Undo') lose their fields when unmarshalled as generic objects.
+func unmarshalActivities(pageBytes []byte) ([]*vocab.ActivityType, error) {
+	page := &activitiesPage{}
+
+	if err := json.Unmarshal(pageBytes, page); err != nil {
+		return nil, err
+	}
+
+	var activities []*vocab.ActivityType
+
+	for _, raw := range append(page.OrderedItems, page.Items...) {
+		activity := &vocab.ActivityType{}
+
+		if err := json.Unmarshal(raw, activity); err != nil {
+			// Not an activity.
+			continue
+		}
+
+		if activity.ID() == nil {
+			continue
+		}
+
+		activities = append(activities, activity)
+	}
+
+	return activities, nil
+}
+
+// SameIRI returns true if the given IRIs are both non-nil and equal.
+func SameIRI(iri1, iri2 *url.URL) bool {
+	return iri1 != nil && iri2 != nil && iri1.String() == iri2.String()
+}
@@ -0,0 +1,136 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package common
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+func TestFindOutboxActivity(t *testing.T) {
+	actorIRI := mustParseURL(t, "https://orb.domain1.com/services/orb")
+	toIRI := mustParseURL(t, "https://orb.domain2.com/services/orb")
+
+	follow1 := vocab.NewFollowActivity(vocab.NewObjectProperty(vocab.WithIRI(toIRI)),
+		vocab.WithID(mustParseURL(t, "https://orb.domain1.com/services/orb/activities/follow1")),
+		vocab.WithActor(actorIRI),
+	)
+
+	follow2 := vocab.NewFollowActivity(vocab.NewObjectProperty(vocab.WithIRI(toIRI)),
+		vocab.WithID(mustParseURL(t, "https://orb.domain1.com/services/orb/activities/follow2")),
+		vocab.WithActor(actorIRI),
+	)
+
+	undo := vocab.NewUndoActivity(vocab.NewObjectProperty(vocab.WithActivity(follow1)),
+		vocab.WithID(mustParseURL(t, "https://orb.domain1.com/services/orb/activities/undo1")),
+		vocab.WithActor(actorIRI),
+	)
+
+	var authHeader string
+
+	mux := http.NewServeMux()
+
+	serv := httptest.NewServer(mux)
+	defer serv.Close()
+
+	page2URL := mustParseURL(t, serv.URL+"/outbox/page2")
+
+	mux.HandleFunc("/outbox", func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+
+		writeJSON(t, w, vocab.NewOrderedCollection(nil,
+			vocab.WithFirst(mustParseURL(t, serv.URL+"/outbox/page1")),
+		))
+	})
+
+	mux.HandleFunc("/outbox/page1", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, vocab.NewOrderedCollectionPage(
+			[]*vocab.ObjectProperty{
+				vocab.NewObjectProperty(vocab.WithActivity(undo)),
+			},
+			vocab.WithNext(page2URL),
+		))
+	})
+
+	mux.HandleFunc("/outbox/page2", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, vocab.NewOrderedCollectionPage(
+			[]*vocab.ObjectProperty{
+				vocab.NewObjectProperty(vocab.WithActivity(follow1)),
+				vocab.NewObjectProperty(vocab.WithActivity(follow2)),
+			},
+		))
+	})
+
+	matchFollow := func(activity *vocab.ActivityType) bool {
+		return activity.Type().Is(vocab.TypeFollow) &&
+			SameIRI(activity.Actor(), actorIRI) &&
+			SameIRI(activity.Object().IRI(), toIRI)
+	}
+
+	t.Run("success - undone activity is skipped", func(t *testing.T) {
+		activity, err := FindOutboxActivity(&http.Client{},
+			map[string]string{"Authorization": "Bearer token"}, serv.URL+"/outbox", matchFollow)
+		require.NoError(t, err)
+		require.Equal(t, follow2.ID().String(), activity.ID().String())
+		require.Equal(t, "Bearer token", authHeader)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := FindOutboxActivity(&http.Client{}, nil, serv.URL+"/outbox",
+			func(activity *vocab.ActivityType) bool {
+				return activity.Type().Is(vocab.TypeInvite)
+			},
+		)
+		require.ErrorIs(t, err, ErrActivityNotFound)
+	})
+
+	t.Run("outbox error", func(t *testing.T) {
+		_, err := FindOutboxActivity(&http.Client{}, nil, serv.URL+"/invalid", matchFollow)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get outbox")
+	})
+
+	t.Run("invalid outbox", func(t *testing.T) {
+		_, err := FindOutboxActivity(&http.Client{}, nil, serv.URL+"/outbox/page1", matchFollow)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal outbox")
+	})
+}
+
+func TestSameIRI(t *testing.T) {
+	iri := mustParseURL(t, "https://orb.domain1.com/services/orb")
+
+	require.True(t, SameIRI(iri, mustParseURL(t, iri.String())))
+	require.False(t, SameIRI(iri, mustParseURL(t, "https://orb.domain2.com/services/orb")))
+	require.False(t, SameIRI(iri, nil))
+	require.False(t, SameIRI(nil, nil))
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, obj interface{}) {
+	t.Helper()
+
+	b, err := json.Marshal(obj)
+	require.NoError(t, err)
+
+	_, err = w.Write(b)
+	require.NoError(t, err)
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+
+	return u
+}
@@ -0,0 +1,275 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deadlettercmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	tlsutils "github.com/trustbloc/edge-core/pkg/utils/tls"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+)
+
+const (
+	urlFlagName  = "url"
+	urlFlagUsage = "The URL of the dead-letter endpoint (e.g. https://orb.domain1.com/deadletter)." +
+		" Alternatively, this can be set with the following environment variable: " + urlEnvKey
+	urlEnvKey = "ORB_CLI_URL"
+
+	idFlagName  = "id"
+	idFlagUsage = "The ID of the dead-letter entry." +
+		" Alternatively, this can be set with the following environment variable: " + idEnvKey
+	idEnvKey = "ORB_CLI_ID"
+
+	targetFlagName  = "target"
+	targetFlagUsage = "Only include entries for the given target inbox URL." +
+		" Alternatively, this can be set with the following environment variable: " + targetEnvKey
+	targetEnvKey = "ORB_CLI_TARGET"
+
+	typeFlagName  = "type"
+	typeFlagUsage = "Only include entries for the given activity type (e.g. Announce)." +
+		" Alternatively, this can be set with the following environment variable: " + typeEnvKey
+	typeEnvKey = "ORB_CLI_TYPE"
+
+	allFlagName  = "all"
+	allFlagUsage = "Set to true to purge all entries when no other criteria are specified." +
+		" Alternatively, this can be set with the following environment variable: " + allEnvKey
+	allEnvKey = "ORB_CLI_ALL"
+
+	tlsSystemCertPoolFlagName  = "tls-systemcertpool"
+	tlsSystemCertPoolFlagUsage = "Use system certificate pool." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + tlsSystemCertPoolEnvKey
+	tlsSystemCertPoolEnvKey = "ORB_CLI_TLS_SYSTEMCERTPOOL"
+
+	tlsCACertsFlagName  = "tls-cacerts"
+	tlsCACertsFlagUsage = "Comma-Separated list of ca certs path." +
+		" Alternatively, this can be set with the following environment variable: " + tlsCACertsEnvKey
+	tlsCACertsEnvKey = "ORB_CLI_TLS_CACERTS"
+
+	authTokenFlagName  = "auth-token"
+	authTokenFlagUsage = "Auth token." +
+		" Alternatively, this can be set with the following environment variable: " + authTokenEnvKey
+	authTokenEnvKey = "ORB_CLI_AUTH_TOKEN" //nolint:gosec
+)
+
+const (
+	idParam     = "id"
+	targetParam = "target"
+	typeParam   = "type"
+	allParam    = "all"
+
+	replayPath = "/replay"
+)
+
+// GetListCmd returns the Cobra command that lists dead-letter entries.
+func GetListCmd() *cobra.Command {
+	cmd := newCmd("list", "list dead-letter entries",
+		"Lists the activities that could not be delivered, optionally filtered by target inbox and activity type.",
+		func(cmd *cobra.Command, httpClient *http.Client, deadLetterURL string, headers map[string]string) error {
+			return send(httpClient, headers, http.MethodGet, deadLetterURL+"?"+getCriteria(cmd).Encode())
+		},
+	)
+
+	createFlags(cmd)
+	createCriteriaFlags(cmd)
+
+	return cmd
+}
+
+// GetGetCmd returns the Cobra command that returns a single dead-letter entry.
+func GetGetCmd() *cobra.Command {
+	cmd := newCmd("get", "get dead-letter entry",
+		"Returns the dead-letter entry with the given ID, including the activity that could not be delivered.",
+		func(cmd *cobra.Command, httpClient *http.Client, deadLetterURL string, headers map[string]string) error {
+			id, err := cmdutils.GetUserSetVarFromString(cmd, idFlagName, idEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			return send(httpClient, headers, http.MethodGet,
+				strings.TrimSuffix(deadLetterURL, "/")+"/"+url.PathEscape(id))
+		},
+	)
+
+	createFlags(cmd)
+	cmd.Flags().StringP(idFlagName, "", "", idFlagUsage)
+
+	return cmd
+}
+
+// GetReplayCmd returns the Cobra command that replays dead-letter entries.
+func GetReplayCmd() *cobra.Command {
+	cmd := newCmd("replay", "replay dead-letter entries",
+		"Re-publishes the activities of the matching dead-letter entries through the outbox. If no criteria are "+
+			"specified then all entries are replayed.",
+		func(cmd *cobra.Command, httpClient *http.Client, deadLetterURL string, headers map[string]string) error {
+			return send(httpClient, headers, http.MethodPost,
+				strings.TrimSuffix(deadLetterURL, "/")+replayPath+"?"+getCriteria(cmd).Encode())
+		},
+	)
+
+	createFlags(cmd)
+	createCriteriaFlags(cmd)
+
+	return cmd
+}
+
+// GetPurgeCmd returns the Cobra command that purges dead-letter entries.
+func GetPurgeCmd() *cobra.Command {
+	cmd := newCmd("purge", "purge dead-letter entries",
+		"Deletes the matching dead-letter entries. If no criteria are specified then --all must be set to true.",
+		func(cmd *cobra.Command, httpClient *http.Client, deadLetterURL string, headers map[string]string) error {
+			params := getCriteria(cmd)
+
+			allStr := cmdutils.GetUserSetOptionalVarFromString(cmd, allFlagName, allEnvKey)
+			if allStr != "" {
+				all, err := strconv.ParseBool(allStr)
+				if err != nil {
+					return fmt.Errorf("invalid value for %s: %w", allFlagName, err)
+				}
+
+				params.Set(allParam, strconv.FormatBool(all))
+			}
+
+			if len(params) == 0 {
+				return fmt.Errorf("either %s, %s, %s or %s must be specified",
+					idFlagName, targetFlagName, typeFlagName, allFlagName)
+			}
+
+			return send(httpClient, headers, http.MethodDelete, deadLetterURL+"?"+params.Encode())
+		},
+	)
+
+	createFlags(cmd)
+	createCriteriaFlags(cmd)
+	cmd.Flags().StringP(allFlagName, "", "", allFlagUsage)
+
+	return cmd
+}
+
+type runFunc func(cmd *cobra.Command, httpClient *http.Client, deadLetterURL string, headers map[string]string) error
+
+func newCmd(use, short, long string, run runFunc) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long:  long,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootCAs, err := getRootCAs(cmd)
+			if err != nil {
+				return err
+			}
+
+			httpClient := &http.Client{
+				Transport: &http.Transport{
+					TLSClientConfig: &tls.Config{
+						RootCAs:    rootCAs,
+						MinVersion: tls.VersionTLS12,
+					},
+				},
+			}
+
+			deadLetterURL, err := cmdutils.GetUserSetVarFromString(cmd, urlFlagName, urlEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			authToken := cmdutils.GetUserSetOptionalVarFromString(cmd, authTokenFlagName, authTokenEnvKey)
+
+			headers := make(map[string]string)
+			if authToken != "" {
+				headers["Authorization"] = "Bearer " + authToken
+			}
+
+			return run(cmd, httpClient, deadLetterURL, headers)
+		},
+	}
+}
+
+func send(httpClient *http.Client, headers map[string]string, method, endpointURL string) error {
+	resp, err := common.SendRequest(httpClient, nil, headers, method, endpointURL)
+	if err != nil {
+		return fmt.Errorf("failed to send http request: %w", err)
+	}
+
+	var out bytes.Buffer
+
+	err = json.Indent(&out, resp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("invalid dead-letter response: %w", err)
+	}
+
+	fmt.Println(out.String())
+
+	return nil
+}
+
+func getCriteria(cmd *cobra.Command) url.Values {
+	params := url.Values{}
+
+	for _, p := range []struct {
+		flagName, envKey, param string
+	}{
+		{idFlagName, idEnvKey, idParam},
+		{targetFlagName, targetEnvKey, targetParam},
+		{typeFlagName, typeEnvKey, typeParam},
+	} {
+		if cmd.Flags().Lookup(p.flagName) == nil {
+			continue
+		}
+
+		if value := cmdutils.GetUserSetOptionalVarFromString(cmd, p.flagName, p.envKey); value != "" {
+			params.Set(p.param, value)
+		}
+	}
+
+	return params
+}
+
+func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
+	tlsSystemCertPoolString := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
+		tlsSystemCertPoolEnvKey)
+
+	tlsSystemCertPool := false
+
+	if tlsSystemCertPoolString != "" {
+		var err error
+		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tlsCACerts := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
+		tlsCACertsEnvKey)
+
+	return tlsutils.GetCertPool(tlsSystemCertPool, tlsCACerts)
+}
+
+func createFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "", tlsSystemCertPoolFlagUsage)
+	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	startCmd.Flags().StringP(urlFlagName, "", "", urlFlagUsage)
+	startCmd.Flags().StringP(authTokenFlagName, "", "", authTokenFlagUsage)
+}
+
+func createCriteriaFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(idFlagName, "", "", idFlagUsage)
+	startCmd.Flags().StringP(targetFlagName, "", "", targetFlagUsage)
+	startCmd.Flags().StringP(typeFlagName, "", "", typeFlagUsage)
+}
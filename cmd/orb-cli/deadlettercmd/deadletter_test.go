@@ -0,0 +1,181 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deadlettercmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	flag = "--"
+
+	inbox = "https://orb.domain2.com/services/orb/inbox"
+)
+
+func TestTLSSystemCertPoolInvalidArgsEnvVar(t *testing.T) {
+	startCmd := GetListCmd()
+
+	require.NoError(t, os.Setenv(tlsSystemCertPoolEnvKey, "wrongvalue"))
+	defer os.Clearenv()
+
+	err := startCmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid syntax")
+}
+
+func TestCmdWithMissingArg(t *testing.T) {
+	t.Run("test missing url arg", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetListCmd()
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither url (command line flag) nor ORB_CLI_URL (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test missing id arg", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetGetCmd()
+
+		startCmd.SetArgs([]string{flag + urlFlagName, "localhost:8080"})
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither id (command line flag) nor ORB_CLI_ID (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test purge without criteria", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetPurgeCmd()
+
+		startCmd.SetArgs([]string{flag + urlFlagName, "localhost:8080"})
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "must be specified")
+	})
+
+	t.Run("test purge invalid all arg", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetPurgeCmd()
+
+		startCmd.SetArgs([]string{flag + urlFlagName, "localhost:8080", flag + allFlagName, "xxx"})
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for all")
+	})
+}
+
+func TestDeadLetter(t *testing.T) {
+	var (
+		authHeader string
+		method     string
+		path       string
+		query      url.Values
+	)
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		method = r.Method
+		path = r.URL.Path
+		query = r.URL.Query()
+
+		switch {
+		case r.URL.Path == "/invalid":
+			w.WriteHeader(http.StatusInternalServerError)
+		case r.Method == http.MethodGet && r.URL.Path == "/deadletter":
+			_, err := w.Write([]byte(`[{"id":"123","target":"` + inbox + `","activityType":"Follow"}]`))
+			require.NoError(t, err)
+		case r.Method == http.MethodGet:
+			_, err := w.Write([]byte(`{"id":"123","target":"` + inbox + `","activityType":"Follow"}`))
+			require.NoError(t, err)
+		default:
+			_, err := w.Write([]byte(`{"succeeded":1,"failed":0}`))
+			require.NoError(t, err)
+		}
+	}))
+	defer serv.Close()
+
+	deadLetterURL := serv.URL + "/deadletter"
+
+	t.Run("list", func(t *testing.T) {
+		cmd := GetListCmd()
+
+		cmd.SetArgs([]string{
+			flag + urlFlagName, deadLetterURL,
+			flag + targetFlagName, inbox,
+			flag + typeFlagName, "Follow",
+			flag + authTokenFlagName, "ADMIN_TOKEN",
+		})
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, http.MethodGet, method)
+		require.Equal(t, "/deadletter", path)
+		require.Equal(t, inbox, query.Get(targetParam))
+		require.Equal(t, "Follow", query.Get(typeParam))
+		require.Equal(t, "Bearer ADMIN_TOKEN", authHeader)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		cmd := GetGetCmd()
+
+		cmd.SetArgs([]string{flag + urlFlagName, deadLetterURL, flag + idFlagName, "123"})
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, http.MethodGet, method)
+		require.Equal(t, "/deadletter/123", path)
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		cmd := GetReplayCmd()
+
+		cmd.SetArgs([]string{flag + urlFlagName, deadLetterURL, flag + idFlagName, "123"})
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, http.MethodPost, method)
+		require.Equal(t, "/deadletter/replay", path)
+		require.Equal(t, "123", query.Get(idParam))
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		cmd := GetPurgeCmd()
+
+		cmd.SetArgs([]string{flag + urlFlagName, deadLetterURL, flag + allFlagName, "true"})
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, http.MethodDelete, method)
+		require.Equal(t, "/deadletter", path)
+		require.Equal(t, "true", query.Get(allParam))
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		cmd := GetListCmd()
+
+		cmd.SetArgs([]string{flag + urlFlagName, serv.URL + "/invalid"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+}
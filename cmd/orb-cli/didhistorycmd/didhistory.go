@@ -0,0 +1,148 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didhistorycmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	tlsutils "github.com/trustbloc/edge-core/pkg/utils/tls"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+)
+
+const (
+	urlFlagName  = "url"
+	urlFlagUsage = "The URL of the DID history endpoint (e.g. https://orb.domain1.com/history)." +
+		" Alternatively, this can be set with the following environment variable: " + urlEnvKey
+	urlEnvKey = "ORB_CLI_URL"
+
+	didFlagName  = "did"
+	didFlagUsage = "The DID (or the unique suffix of the DID) whose operation history is returned." +
+		" Alternatively, this can be set with the following environment variable: " + didEnvKey
+	didEnvKey = "ORB_CLI_DID"
+
+	tlsSystemCertPoolFlagName  = "tls-systemcertpool"
+	tlsSystemCertPoolFlagUsage = "Use system certificate pool." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + tlsSystemCertPoolEnvKey
+	tlsSystemCertPoolEnvKey = "ORB_CLI_TLS_SYSTEMCERTPOOL"
+
+	tlsCACertsFlagName  = "tls-cacerts"
+	tlsCACertsFlagUsage = "Comma-Separated list of ca certs path." +
+		" Alternatively, this can be set with the following environment variable: " + tlsCACertsEnvKey
+	tlsCACertsEnvKey = "ORB_CLI_TLS_CACERTS"
+
+	authTokenFlagName  = "auth-token"
+	authTokenFlagUsage = "Auth token." +
+		" Alternatively, this can be set with the following environment variable: " + authTokenEnvKey
+	authTokenEnvKey = "ORB_CLI_AUTH_TOKEN" //nolint:gosec
+)
+
+// GetCmd returns the Cobra DID history command.
+func GetCmd() *cobra.Command {
+	cmd := cmd()
+
+	createFlags(cmd)
+
+	return cmd
+}
+
+func cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "history",
+		Short: "DID operation history",
+		Long: "Returns the ordered operation log of a DID. Each entry contains the operation type, the hashlink " +
+			"and origin of the anchor that included the operation, the witnesses of the anchor and timestamps.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootCAs, err := getRootCAs(cmd)
+			if err != nil {
+				return err
+			}
+
+			httpClient := &http.Client{
+				Transport: &http.Transport{
+					TLSClientConfig: &tls.Config{
+						RootCAs:    rootCAs,
+						MinVersion: tls.VersionTLS12,
+					},
+				},
+			}
+
+			historyURL, err := cmdutils.GetUserSetVarFromString(cmd, urlFlagName, urlEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			did, err := cmdutils.GetUserSetVarFromString(cmd, didFlagName, didEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			authToken := cmdutils.GetUserSetOptionalVarFromString(cmd, authTokenFlagName, authTokenEnvKey)
+
+			headers := make(map[string]string)
+			if authToken != "" {
+				headers["Authorization"] = "Bearer " + authToken
+			}
+
+			resp, err := common.SendRequest(httpClient, nil, headers, http.MethodGet,
+				strings.TrimSuffix(historyURL, "/")+"/"+url.PathEscape(did))
+			if err != nil {
+				return fmt.Errorf("failed to send http request: %w", err)
+			}
+
+			var history bytes.Buffer
+
+			err = json.Indent(&history, resp, "", "  ")
+			if err != nil {
+				return fmt.Errorf("invalid DID history response: %w", err)
+			}
+
+			fmt.Println(history.String())
+
+			return nil
+		},
+	}
+}
+
+func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
+	tlsSystemCertPoolString := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
+		tlsSystemCertPoolEnvKey)
+
+	tlsSystemCertPool := false
+
+	if tlsSystemCertPoolString != "" {
+		var err error
+		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tlsCACerts := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
+		tlsCACertsEnvKey)
+
+	return tlsutils.GetCertPool(tlsSystemCertPool, tlsCACerts)
+}
+
+func createFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "", tlsSystemCertPoolFlagUsage)
+	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	startCmd.Flags().StringP(urlFlagName, "", "", urlFlagUsage)
+	startCmd.Flags().StringP(didFlagName, "", "", didFlagUsage)
+	startCmd.Flags().StringP(authTokenFlagName, "", "", authTokenFlagUsage)
+}
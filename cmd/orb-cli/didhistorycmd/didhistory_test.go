@@ -0,0 +1,103 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didhistorycmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	flag = "--"
+
+	did = "did:orb:uEiD1X_FvZvQzYCZrldtvj-wB12AxBUMGrkpLOAWY9s_RFA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+)
+
+func TestTLSSystemCertPoolInvalidArgsEnvVar(t *testing.T) {
+	startCmd := GetCmd()
+
+	require.NoError(t, os.Setenv(tlsSystemCertPoolEnvKey, "wrongvalue"))
+	defer os.Clearenv()
+
+	err := startCmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid syntax")
+}
+
+func TestCmdWithMissingArg(t *testing.T) {
+	t.Run("test missing url arg", func(t *testing.T) {
+		startCmd := GetCmd()
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither url (command line flag) nor ORB_CLI_URL (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test missing did arg", func(t *testing.T) {
+		startCmd := GetCmd()
+
+		startCmd.SetArgs([]string{flag + urlFlagName, "localhost:8080"})
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither did (command line flag) nor ORB_CLI_DID (environment variable) have been set.",
+			err.Error())
+	})
+}
+
+func TestDIDHistory(t *testing.T) {
+	var (
+		path       string
+		authHeader string
+	)
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		authHeader = r.Header.Get("Authorization")
+
+		if r.URL.Path == "/invalid/"+did {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, err := fmt.Fprint(w, `[{"versionId":"EiB1","type":"create","anchorTime":"2021-08-01T10:00:00Z"}]`)
+		require.NoError(t, err)
+	}))
+	defer serv.Close()
+
+	t.Run("success", func(t *testing.T) {
+		cmd := GetCmd()
+
+		cmd.SetArgs([]string{
+			flag + urlFlagName, serv.URL + "/history",
+			flag + didFlagName, did,
+			flag + authTokenFlagName, "ADMIN_TOKEN",
+		})
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, "/history/"+did, path)
+		require.Equal(t, "Bearer ADMIN_TOKEN", authHeader)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		cmd := GetCmd()
+
+		cmd.SetArgs([]string{flag + urlFlagName, serv.URL + "/invalid", flag + didFlagName, did})
+
+		require.Error(t, cmd.Execute())
+	})
+}
@@ -60,7 +60,8 @@ const (
 	authTokenEnvKey = "ORB_CLI_AUTH_TOKEN" //nolint:gosec
 
 	followIDFlagName  = "follow-id"
-	followIDFlagUsage = "follow id required for undo action." +
+	followIDFlagUsage = "follow id for undo action. If not set then the ID of the 'Follow' activity" +
+		" is looked up in the outbox." +
 		" Alternatively, this can be set with the following environment variable: " + followIDEnvKey
 	followIDEnvKey = "ORB_CLI_FOLLOW_ID"
 )
@@ -136,6 +137,11 @@ func createCmd() *cobra.Command { //nolint:funlen,gocyclo,cyclop,gocognit
 			authToken := cmdutils.GetUserSetOptionalVarFromString(cmd, authTokenFlagName,
 				authTokenEnvKey)
 
+			headers := make(map[string]string)
+			if authToken != "" {
+				headers["Authorization"] = "Bearer " + authToken
+			}
+
 			var reqBytes []byte
 
 			switch action {
@@ -151,15 +157,9 @@ func createCmd() *cobra.Command { //nolint:funlen,gocyclo,cyclop,gocognit
 					return err
 				}
 			case undoAction:
-				followID, errGet := cmdutils.GetUserSetVarFromString(cmd, followIDFlagName,
-					followIDEnvKey, false)
-				if errGet != nil {
-					return errGet
-				}
-
-				followIRI, e := url.Parse(followID)
+				followIRI, e := getFollowIRI(cmd, httpClient, headers, outboxURL, actorIRI, toIRI)
 				if e != nil {
-					return fmt.Errorf("parse 'followID' URL %s: %w", followID, e)
+					return e
 				}
 
 				undo := vocab.NewUndoActivity(
@@ -182,11 +182,6 @@ func createCmd() *cobra.Command { //nolint:funlen,gocyclo,cyclop,gocognit
 				return fmt.Errorf("action %s not supported", action)
 			}
 
-			headers := make(map[string]string)
-			if authToken != "" {
-				headers["Authorization"] = "Bearer " + authToken
-			}
-
 			resp, err := common.SendRequest(httpClient, reqBytes, headers, http.MethodPost,
 				outboxURL)
 			if err != nil {
@@ -200,6 +195,33 @@ func createCmd() *cobra.Command { //nolint:funlen,gocyclo,cyclop,gocognit
 	}
 }
 
+func getFollowIRI(cmd *cobra.Command, httpClient *http.Client, headers map[string]string,
+	outboxURL string, actorIRI, toIRI *url.URL) (*url.URL, error) {
+	followID := cmdutils.GetUserSetOptionalVarFromString(cmd, followIDFlagName, followIDEnvKey)
+
+	if followID != "" {
+		followIRI, err := url.Parse(followID)
+		if err != nil {
+			return nil, fmt.Errorf("parse 'followID' URL %s: %w", followID, err)
+		}
+
+		return followIRI, nil
+	}
+
+	follow, err := common.FindOutboxActivity(httpClient, headers, outboxURL,
+		func(activity *vocab.ActivityType) bool {
+			return activity.Type().Is(vocab.TypeFollow) &&
+				common.SameIRI(activity.Actor(), actorIRI) &&
+				common.SameIRI(activity.Object().IRI(), toIRI)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("find 'Follow' activity to %s in outbox: %w", toIRI, err)
+	}
+
+	return follow.ID().URL(), nil
+}
+
 func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
 	tlsSystemCertPoolString := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
 		tlsSystemCertPoolEnvKey)
@@ -6,13 +6,17 @@ SPDX-License-Identifier: Apache-2.0
 package followcmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 )
 
 const (
@@ -123,7 +127,7 @@ func TestStartCmdWithMissingArg(t *testing.T) {
 			err.Error())
 	})
 
-	t.Run("test missing followID arg", func(t *testing.T) {
+	t.Run("test followID not found in outbox", func(t *testing.T) {
 		startCmd := GetCmd()
 
 		var args []string
@@ -137,9 +141,7 @@ func TestStartCmdWithMissingArg(t *testing.T) {
 		err := startCmd.Execute()
 
 		require.Error(t, err)
-		require.Equal(t,
-			"Neither follow-id (command line flag) nor ORB_CLI_FOLLOW_ID (environment variable) have been set.",
-			err.Error())
+		require.Contains(t, err.Error(), "find 'Follow' activity to to in outbox")
 	})
 
 	t.Run("test invalid 'followID' arg", func(t *testing.T) {
@@ -221,6 +223,62 @@ func TestCreateDID(t *testing.T) {
 	})
 }
 
+func TestUndoFollow(t *testing.T) {
+	const (
+		actorIRI = "https://orb.domain1.com/services/orb"
+		toIRI    = "https://orb.domain2.com/services/orb"
+		followID = "https://orb.domain1.com/services/orb/activities/follow1"
+	)
+
+	var undo *vocab.ActivityType
+
+	mux := http.NewServeMux()
+
+	serv := httptest.NewServer(mux)
+	defer serv.Close()
+
+	mux.HandleFunc("/outbox", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			undo = &vocab.ActivityType{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(undo))
+
+			return
+		}
+
+		writeJSON(t, w, vocab.NewOrderedCollection(nil,
+			vocab.WithFirst(mustParseURL(t, serv.URL+"/outbox/page1")),
+		))
+	})
+
+	mux.HandleFunc("/outbox/page1", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, vocab.NewOrderedCollectionPage(
+			[]*vocab.ObjectProperty{
+				vocab.NewObjectProperty(vocab.WithActivity(
+					vocab.NewFollowActivity(
+						vocab.NewObjectProperty(vocab.WithIRI(mustParseURL(t, toIRI))),
+						vocab.WithID(mustParseURL(t, followID)),
+						vocab.WithActor(mustParseURL(t, actorIRI)),
+					),
+				)),
+			},
+		))
+	})
+
+	cmd := GetCmd()
+
+	var args []string
+	args = append(args, outboxURL(serv.URL+"/outbox")...)
+	args = append(args, actor(actorIRI)...)
+	args = append(args, to(toIRI)...)
+	args = append(args, action("Undo")...)
+
+	cmd.SetArgs(args)
+
+	require.NoError(t, cmd.Execute())
+	require.NotNil(t, undo)
+	require.Equal(t, followID, undo.Object().Activity().ID().String())
+}
+
 func outboxURL(value string) []string {
 	return []string{flag + outboxURLFlagName, value}
 }
@@ -240,3 +298,22 @@ func action(value string) []string {
 func followID(value string) []string {
 	return []string{flag + followIDFlagName, value}
 }
+
+func writeJSON(t *testing.T, w http.ResponseWriter, obj interface{}) {
+	t.Helper()
+
+	b, err := json.Marshal(obj)
+	require.NoError(t, err)
+
+	_, err = w.Write(b)
+	require.NoError(t, err)
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+
+	return u
+}
@@ -8,6 +8,7 @@ require (
 	github.com/hyperledger/aries-framework-go v0.1.7-0.20210811135743-532e65035d3b
 	github.com/hyperledger/aries-framework-go-ext/component/vdr/orb v0.0.0-20210813123233-e22ddceee0b1
 	github.com/hyperledger/aries-framework-go-ext/component/vdr/sidetree v0.0.0-20210813115605-bcae6a85979c
+	github.com/hyperledger/aries-framework-go/component/storageutil v0.0.0-20210807121559-b41545a4f1e8
 	github.com/ipfs/go-ipfs-api v0.2.0
 	github.com/ipfs/go-ipfs-files v0.0.8
 	github.com/libp2p/go-libp2p-core v0.8.0
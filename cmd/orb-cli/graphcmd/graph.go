@@ -0,0 +1,359 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package graphcmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/ld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	ldstore "github.com/hyperledger/aries-framework-go/pkg/store/ld"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/web"
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	tlsutils "github.com/trustbloc/edge-core/pkg/utils/tls"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/ldcontext"
+	"github.com/trustbloc/orb/pkg/anchor/graph"
+	"github.com/trustbloc/orb/pkg/hashlink"
+)
+
+const (
+	hashlinkFlagName  = "hashlink"
+	hashlinkFlagUsage = "The hashlink of the anchor from which to start walking the anchor graph." +
+		" Alternatively, this can be set with the following environment variable: " + hashlinkEnvKey
+	hashlinkEnvKey = "ORB_CLI_HASHLINK"
+
+	casURLFlagName  = "cas-url"
+	casURLFlagUsage = "The URL of the CAS endpoint from which to read anchors (e.g. https://orb.domain1.com/cas)." +
+		" If not set then anchors are read from the CAS links contained in the hashlinks." +
+		" Alternatively, this can be set with the following environment variable: " + casURLEnvKey
+	casURLEnvKey = "ORB_CLI_CAS_URL"
+
+	disableProofCheckFlagName  = "disable-proof-check"
+	disableProofCheckFlagUsage = "Disables verification of anchor credential proofs." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + disableProofCheckEnvKey
+	disableProofCheckEnvKey = "ORB_CLI_DISABLE_PROOF_CHECK"
+
+	formatFlagName  = "format"
+	formatFlagUsage = "The export format. Possible values [jsonl] [dot]. Defaults to jsonl if not set." +
+		" Alternatively, this can be set with the following environment variable: " + formatEnvKey
+	formatEnvKey = "ORB_CLI_FORMAT"
+
+	outputFlagName  = "output"
+	outputFlagUsage = "The file to which the anchor graph is exported. Defaults to stdout if not set." +
+		" Alternatively, this can be set with the following environment variable: " + outputEnvKey
+	outputEnvKey = "ORB_CLI_OUTPUT"
+
+	tlsSystemCertPoolFlagName  = "tls-systemcertpool"
+	tlsSystemCertPoolFlagUsage = "Use system certificate pool." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + tlsSystemCertPoolEnvKey
+	tlsSystemCertPoolEnvKey = "ORB_CLI_TLS_SYSTEMCERTPOOL"
+
+	tlsCACertsFlagName  = "tls-cacerts"
+	tlsCACertsFlagUsage = "Comma-Separated list of ca certs path." +
+		" Alternatively, this can be set with the following environment variable: " + tlsCACertsEnvKey
+	tlsCACertsEnvKey = "ORB_CLI_TLS_CACERTS"
+
+	authTokenFlagName  = "auth-token"
+	authTokenFlagUsage = "Auth token." +
+		" Alternatively, this can be set with the following environment variable: " + authTokenEnvKey
+	authTokenEnvKey = "ORB_CLI_AUTH_TOKEN" //nolint:gosec
+)
+
+const (
+	httpPrefix  = "http://"
+	httpsPrefix = "https://"
+)
+
+// GetExportCmd returns the Cobra anchor graph export command.
+func GetExportCmd() *cobra.Command {
+	cmd := exportCmd()
+
+	createFlags(cmd)
+
+	cmd.Flags().StringP(formatFlagName, "", "", formatFlagUsage)
+	cmd.Flags().StringP(outputFlagName, "", "", outputFlagUsage)
+
+	return cmd
+}
+
+// GetVerifyCmd returns the Cobra anchor graph verify command.
+func GetVerifyCmd() *cobra.Command {
+	cmd := verifyCmd()
+
+	createFlags(cmd)
+
+	return cmd
+}
+
+func exportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export",
+		Short: "export anchor graph",
+		Long: "Walks the anchor graph starting at the given anchor and following all previous anchor links back " +
+			"to genesis and exports the graph as JSON Lines or Graphviz DOT. The results of verifying each anchor " +
+			"are included in the export.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			walker, hl, err := newWalker(cmd)
+			if err != nil {
+				return err
+			}
+
+			format := cmdutils.GetUserSetOptionalVarFromString(cmd, formatFlagName, formatEnvKey)
+			if format == "" {
+				format = string(graph.ExportFormatJSONL)
+			}
+
+			if format != string(graph.ExportFormatJSONL) && format != string(graph.ExportFormatDOT) {
+				return fmt.Errorf("invalid %s [%s]: possible values [%s] [%s]", formatFlagName, format,
+					graph.ExportFormatJSONL, graph.ExportFormatDOT)
+			}
+
+			var out io.Writer = os.Stdout
+
+			output := cmdutils.GetUserSetOptionalVarFromString(cmd, outputFlagName, outputEnvKey)
+			if output != "" {
+				f, err := os.Create(output) //nolint:gosec
+				if err != nil {
+					return fmt.Errorf("create output file: %w", err)
+				}
+
+				defer func() {
+					if errClose := f.Close(); errClose != nil {
+						fmt.Printf("failed to close output file: %s\n", errClose)
+					}
+				}()
+
+				out = f
+			}
+
+			return walker.Export(hl, graph.ExportFormat(format), out)
+		},
+	}
+}
+
+func verifyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify",
+		Short: "verify anchor graph",
+		Long: "Walks the anchor graph starting at the given anchor and following all previous anchor links back " +
+			"to genesis. The content of each anchor is verified against its hashlink and the proofs of each " +
+			"anchor credential are verified.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			walker, hl, err := newWalker(cmd)
+			if err != nil {
+				return err
+			}
+
+			result, err := walker.Verify(hl)
+			if err != nil {
+				return fmt.Errorf("verify anchor graph: %w", err)
+			}
+
+			resultBytes, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				return fmt.Errorf("marshal verification result: %w", err)
+			}
+
+			fmt.Println(string(resultBytes))
+
+			if len(result.Failed) > 0 {
+				return fmt.Errorf("%d of %d anchors failed verification", len(result.Failed), result.Total)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newWalker(cmd *cobra.Command) (*graph.Walker, string, error) {
+	rootCAs, err := getRootCAs(cmd)
+	if err != nil {
+		return nil, "", err
+	}
+
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs:    rootCAs,
+				MinVersion: tls.VersionTLS12,
+			},
+		},
+	}
+
+	hl, err := cmdutils.GetUserSetVarFromString(cmd, hashlinkFlagName, hashlinkEnvKey, false)
+	if err != nil {
+		return nil, "", err
+	}
+
+	casURL := cmdutils.GetUserSetOptionalVarFromString(cmd, casURLFlagName, casURLEnvKey)
+
+	disableProofCheck, err := getBool(cmd, disableProofCheckFlagName, disableProofCheckEnvKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	authToken := cmdutils.GetUserSetOptionalVarFromString(cmd, authTokenFlagName, authTokenEnvKey)
+
+	headers := make(map[string]string)
+	if authToken != "" {
+		headers["Authorization"] = "Bearer " + authToken
+	}
+
+	docLoader, err := newDocumentLoader()
+	if err != nil {
+		return nil, "", err
+	}
+
+	pkf := verifiable.NewVDRKeyResolver(vdr.New(vdr.WithVDR(&webVDR{
+		http: httpClient,
+		VDR:  web.New(),
+	}))).PublicKeyFetcher()
+
+	reader := &casReader{
+		httpClient: httpClient,
+		headers:    headers,
+		casURL:     strings.TrimSuffix(casURL, "/"),
+		hl:         hashlink.New(),
+	}
+
+	return graph.NewWalker(reader,
+		graph.WithJSONLDDocumentLoader(docLoader),
+		graph.WithPublicKeyFetcher(pkf),
+		graph.WithDisableProofCheck(disableProofCheck),
+	), hl, nil
+}
+
+// casReader reads anchors over HTTP from either the given CAS URL or from the CAS links in the hashlink.
+type casReader struct {
+	httpClient *http.Client
+	headers    map[string]string
+	casURL     string
+	hl         *hashlink.HashLink
+}
+
+func (r *casReader) Read(hl string) ([]byte, error) {
+	info, err := r.hl.ParseHashLink(hl)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.casURL != "" {
+		return common.SendRequest(r.httpClient, nil, r.headers, http.MethodGet, r.casURL+"/"+info.ResourceHash)
+	}
+
+	for _, link := range info.Links {
+		if strings.HasPrefix(link, httpsPrefix) || strings.HasPrefix(link, httpPrefix) {
+			return common.SendRequest(r.httpClient, nil, r.headers, http.MethodGet, link)
+		}
+	}
+
+	return nil, fmt.Errorf("hashlink [%s] does not contain a CAS link and %s is not set", hl, casURLFlagName)
+}
+
+type webVDR struct {
+	http *http.Client
+	*web.VDR
+}
+
+func (w *webVDR) Read(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+	return w.VDR.Read(didID, append(opts, vdrapi.WithOption(web.HTTPClientOpt, w.http))...)
+}
+
+type ldStoreProvider struct {
+	ContextStore        ldstore.ContextStore
+	RemoteProviderStore ldstore.RemoteProviderStore
+}
+
+func (p *ldStoreProvider) JSONLDContextStore() ldstore.ContextStore {
+	return p.ContextStore
+}
+
+func (p *ldStoreProvider) JSONLDRemoteProviderStore() ldstore.RemoteProviderStore {
+	return p.RemoteProviderStore
+}
+
+func newDocumentLoader() (*ld.DocumentLoader, error) {
+	contextStore, err := ldstore.NewContextStore(mem.NewProvider())
+	if err != nil {
+		return nil, fmt.Errorf("create JSON-LD context store: %w", err)
+	}
+
+	remoteProviderStore, err := ldstore.NewRemoteProviderStore(mem.NewProvider())
+	if err != nil {
+		return nil, fmt.Errorf("create remote provider store: %w", err)
+	}
+
+	docLoader, err := ld.NewDocumentLoader(&ldStoreProvider{
+		ContextStore:        contextStore,
+		RemoteProviderStore: remoteProviderStore,
+	}, ld.WithExtraContexts(ldcontext.MustGetAll()...))
+	if err != nil {
+		return nil, fmt.Errorf("create document loader: %w", err)
+	}
+
+	return docLoader, nil
+}
+
+func getBool(cmd *cobra.Command, flagName, envKey string) (bool, error) {
+	value := cmdutils.GetUserSetOptionalVarFromString(cmd, flagName, envKey)
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s [%s]: %w", flagName, value, err)
+	}
+
+	return b, nil
+}
+
+func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
+	tlsSystemCertPoolString := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
+		tlsSystemCertPoolEnvKey)
+
+	tlsSystemCertPool := false
+
+	if tlsSystemCertPoolString != "" {
+		var err error
+		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tlsCACerts := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
+		tlsCACertsEnvKey)
+
+	return tlsutils.GetCertPool(tlsSystemCertPool, tlsCACerts)
+}
+
+func createFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "", tlsSystemCertPoolFlagUsage)
+	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	startCmd.Flags().StringP(hashlinkFlagName, "", "", hashlinkFlagUsage)
+	startCmd.Flags().StringP(casURLFlagName, "", "", casURLFlagUsage)
+	startCmd.Flags().StringP(disableProofCheckFlagName, "", "", disableProofCheckFlagUsage)
+	startCmd.Flags().StringP(authTokenFlagName, "", "", authTokenFlagUsage)
+}
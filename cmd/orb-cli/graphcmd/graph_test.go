@@ -0,0 +1,278 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package graphcmd
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/util"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/anchor/activity"
+	"github.com/trustbloc/orb/pkg/anchor/subject"
+	"github.com/trustbloc/orb/pkg/hashlink"
+)
+
+const (
+	flag = "--"
+)
+
+func TestTLSSystemCertPoolInvalidArgsEnvVar(t *testing.T) {
+	startCmd := GetVerifyCmd()
+
+	require.NoError(t, os.Setenv(tlsSystemCertPoolEnvKey, "wrongvalue"))
+	defer os.Clearenv()
+
+	err := startCmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid syntax")
+}
+
+func TestCmdWithMissingArg(t *testing.T) {
+	t.Run("test missing hashlink arg", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetVerifyCmd()
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither hashlink (command line flag) nor ORB_CLI_HASHLINK (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test invalid disable-proof-check arg", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetVerifyCmd()
+
+		var args []string
+		args = append(args, hashlinkArg("hl:xyz")...)
+		args = append(args, flag+disableProofCheckFlagName, "xyz")
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for disable-proof-check")
+	})
+
+	t.Run("test invalid format arg", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetExportCmd()
+
+		var args []string
+		args = append(args, hashlinkArg("hl:xyz")...)
+		args = append(args, flag+formatFlagName, "xml")
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid format [xml]")
+	})
+}
+
+func TestGraph(t *testing.T) {
+	content := make(map[string][]byte)
+
+	hl1, resourceHash1 := newAnchor(t, content, map[string]string{"a": ""})
+	hl2, _ := newAnchor(t, content, map[string]string{"a": hl1})
+
+	var authHeader string
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+
+		b, ok := content[strings.TrimPrefix(r.URL.Path, "/cas/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, err := w.Write(b)
+		require.NoError(t, err)
+	}))
+	defer serv.Close()
+
+	t.Run("verify - success", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetVerifyCmd()
+
+		var args []string
+		args = append(args, hashlinkArg(hl2)...)
+		args = append(args, casURLArg(serv.URL+"/cas")...)
+		args = append(args, authTokenArg("READ_TOKEN")...)
+		cmd.SetArgs(args)
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, "Bearer READ_TOKEN", authHeader)
+	})
+
+	t.Run("verify - anchor not found", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetVerifyCmd()
+
+		original := content[resourceHash1]
+		delete(content, resourceHash1)
+
+		defer func() {
+			content[resourceHash1] = original
+		}()
+
+		var args []string
+		args = append(args, hashlinkArg(hl2)...)
+		args = append(args, casURLArg(serv.URL+"/cas")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Equal(t, "1 of 2 anchors failed verification", err.Error())
+	})
+
+	t.Run("verify - no CAS link", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetVerifyCmd()
+
+		cmd.SetArgs(hashlinkArg(hl2))
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Equal(t, "1 of 1 anchors failed verification", err.Error())
+	})
+
+	t.Run("export - DOT to file", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetExportCmd()
+
+		output := filepath.Join(t.TempDir(), "graph.dot")
+
+		var args []string
+		args = append(args, hashlinkArg(hl2)...)
+		args = append(args, casURLArg(serv.URL+"/cas/")...)
+		args = append(args, flag+formatFlagName, "dot")
+		args = append(args, flag+outputFlagName, output)
+		cmd.SetArgs(args)
+
+		require.NoError(t, cmd.Execute())
+
+		dot, err := ioutil.ReadFile(output) //nolint:gosec
+		require.NoError(t, err)
+		require.Contains(t, string(dot), "digraph anchors {")
+		require.Equal(t, 1, strings.Count(string(dot), "->"))
+	})
+
+	t.Run("export - JSON Lines to stdout", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetExportCmd()
+
+		var args []string
+		args = append(args, hashlinkArg(hl2)...)
+		args = append(args, casURLArg(serv.URL+"/cas")...)
+		cmd.SetArgs(args)
+
+		require.NoError(t, cmd.Execute())
+	})
+
+	t.Run("export - invalid output file", func(t *testing.T) {
+		os.Clearenv()
+		cmd := GetExportCmd()
+
+		var args []string
+		args = append(args, hashlinkArg(hl2)...)
+		args = append(args, flag+outputFlagName, filepath.Join(t.TempDir(), "missing", "graph.dot"))
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create output file")
+	})
+}
+
+func TestCASReader(t *testing.T) {
+	content := make(map[string][]byte)
+
+	_, resourceHash := newAnchor(t, content, map[string]string{"a": ""})
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write(content[strings.TrimPrefix(r.URL.Path, "/cas/")])
+		require.NoError(t, err)
+	}))
+	defer serv.Close()
+
+	hl, err := hashlink.New().CreateHashLink(content[resourceHash],
+		[]string{"ipfs://xyz", serv.URL + "/cas/" + resourceHash})
+	require.NoError(t, err)
+
+	r := &casReader{httpClient: &http.Client{}, hl: hashlink.New()}
+
+	t.Run("success - CAS link in hashlink", func(t *testing.T) {
+		b, err := r.Read(hl)
+		require.NoError(t, err)
+		require.Equal(t, content[resourceHash], b)
+	})
+
+	t.Run("invalid hashlink", func(t *testing.T) {
+		_, err := r.Read("xyz")
+		require.Error(t, err)
+	})
+}
+
+func newAnchor(t *testing.T, content map[string][]byte, previousAnchors map[string]string) (string, string) {
+	t.Helper()
+
+	act, err := activity.BuildActivityFromPayload(&subject.Payload{
+		OperationCount:  1,
+		CoreIndex:       "coreIndex",
+		Namespace:       "did:orb",
+		Version:         1,
+		PreviousAnchors: previousAnchors,
+	})
+	require.NoError(t, err)
+
+	vc := &verifiable.Credential{
+		Types:   []string{"VerifiableCredential"},
+		Context: []string{"https://www.w3.org/2018/credentials/v1"},
+		Subject: act,
+		Issuer: verifiable.Issuer{
+			ID: "http://orb.domain.com",
+		},
+		Issued: &util.TimeWithTrailingZeroMsec{Time: time.Now()},
+	}
+
+	vcBytes, err := vc.MarshalJSON()
+	require.NoError(t, err)
+
+	resourceHash, err := hashlink.New().CreateResourceHash(vcBytes)
+	require.NoError(t, err)
+
+	content[resourceHash] = vcBytes
+
+	return hashlink.GetHashLinkFromResourceHash(resourceHash), resourceHash
+}
+
+func hashlinkArg(value string) []string {
+	return []string{flag + hashlinkFlagName, value}
+}
+
+func casURLArg(value string) []string {
+	return []string{flag + casURLFlagName, value}
+}
+
+func authTokenArg(value string) []string {
+	return []string{flag + authTokenFlagName, value}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyscmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	tlsutils "github.com/trustbloc/edge-core/pkg/utils/tls"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+)
+
+const (
+	urlFlagName  = "url"
+	urlFlagUsage = "The URL of the key rotation endpoint (e.g. https://orb.domain1.com/keys/rotate)." +
+		" Alternatively, this can be set with the following environment variable: " + urlEnvKey
+	urlEnvKey = "ORB_CLI_URL"
+
+	retireFlagName  = "retire"
+	retireFlagUsage = "The time (in RFC3339 format, e.g. 2021-12-31T00:00:00Z) at which the previous key is retired." +
+		" If not set then the previous key is retired after the overlap period configured on the server." +
+		" Alternatively, this can be set with the following environment variable: " + retireEnvKey
+	retireEnvKey = "ORB_CLI_RETIRE"
+
+	tlsSystemCertPoolFlagName  = "tls-systemcertpool"
+	tlsSystemCertPoolFlagUsage = "Use system certificate pool." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + tlsSystemCertPoolEnvKey
+	tlsSystemCertPoolEnvKey = "ORB_CLI_TLS_SYSTEMCERTPOOL"
+
+	tlsCACertsFlagName  = "tls-cacerts"
+	tlsCACertsFlagUsage = "Comma-Separated list of ca certs path." +
+		" Alternatively, this can be set with the following environment variable: " + tlsCACertsEnvKey
+	tlsCACertsEnvKey = "ORB_CLI_TLS_CACERTS"
+
+	authTokenFlagName  = "auth-token"
+	authTokenFlagUsage = "Auth token." +
+		" Alternatively, this can be set with the following environment variable: " + authTokenEnvKey
+	authTokenEnvKey = "ORB_CLI_AUTH_TOKEN" //nolint:gosec
+)
+
+const retireParam = "retire"
+
+// GetRotateCmd returns the Cobra command that rotates the signing key.
+func GetRotateCmd() *cobra.Command {
+	cmd := newCmd("rotate", "rotate the signing key",
+		"Creates a new signing key on the server which is used to sign new anchor credentials and HTTP requests. "+
+			"The previous key continues to be published in the server's DID document and ActivityPub service "+
+			"until it's retired. The published keys are returned.",
+		func(cmd *cobra.Command, httpClient *http.Client, endpointURL string, headers map[string]string) error {
+			params, err := getRetireParam(cmd)
+			if err != nil {
+				return err
+			}
+
+			return send(httpClient, headers, http.MethodPost, endpointURL+"?"+params.Encode())
+		},
+	)
+
+	createFlags(cmd)
+	cmd.Flags().StringP(retireFlagName, "", "", retireFlagUsage)
+
+	return cmd
+}
+
+type runFunc func(cmd *cobra.Command, httpClient *http.Client, endpointURL string, headers map[string]string) error
+
+func newCmd(use, short, long string, run runFunc) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long:  long,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootCAs, err := getRootCAs(cmd)
+			if err != nil {
+				return err
+			}
+
+			httpClient := &http.Client{
+				Transport: &http.Transport{
+					TLSClientConfig: &tls.Config{
+						RootCAs:    rootCAs,
+						MinVersion: tls.VersionTLS12,
+					},
+				},
+			}
+
+			endpointURL, err := cmdutils.GetUserSetVarFromString(cmd, urlFlagName, urlEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			authToken := cmdutils.GetUserSetOptionalVarFromString(cmd, authTokenFlagName, authTokenEnvKey)
+
+			headers := make(map[string]string)
+			if authToken != "" {
+				headers["Authorization"] = "Bearer " + authToken
+			}
+
+			return run(cmd, httpClient, endpointURL, headers)
+		},
+	}
+}
+
+func send(httpClient *http.Client, headers map[string]string, method, endpointURL string) error {
+	resp, err := common.SendRequest(httpClient, nil, headers, method, endpointURL)
+	if err != nil {
+		return fmt.Errorf("failed to send http request: %w", err)
+	}
+
+	var out bytes.Buffer
+
+	err = json.Indent(&out, resp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("invalid key rotation response: %w", err)
+	}
+
+	fmt.Println(out.String())
+
+	return nil
+}
+
+func getRetireParam(cmd *cobra.Command) (url.Values, error) {
+	params := url.Values{}
+
+	retire := cmdutils.GetUserSetOptionalVarFromString(cmd, retireFlagName, retireEnvKey)
+	if retire != "" {
+		if _, err := time.Parse(time.RFC3339, retire); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", retireFlagName, err)
+		}
+
+		params.Set(retireParam, retire)
+	}
+
+	return params, nil
+}
+
+func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
+	tlsSystemCertPoolString := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
+		tlsSystemCertPoolEnvKey)
+
+	tlsSystemCertPool := false
+
+	if tlsSystemCertPoolString != "" {
+		var err error
+		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tlsCACerts := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
+		tlsCACertsEnvKey)
+
+	return tlsutils.GetCertPool(tlsSystemCertPool, tlsCACerts)
+}
+
+func createFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "", tlsSystemCertPoolFlagUsage)
+	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	startCmd.Flags().StringP(urlFlagName, "", "", urlFlagUsage)
+	startCmd.Flags().StringP(authTokenFlagName, "", "", authTokenFlagUsage)
+}
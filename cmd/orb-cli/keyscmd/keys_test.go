@@ -0,0 +1,118 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyscmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const flag = "--"
+
+func TestTLSSystemCertPoolInvalidArgsEnvVar(t *testing.T) {
+	startCmd := GetRotateCmd()
+
+	require.NoError(t, os.Setenv(tlsSystemCertPoolEnvKey, "wrongvalue"))
+	defer os.Clearenv()
+
+	err := startCmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid syntax")
+}
+
+func TestCmdWithMissingArg(t *testing.T) {
+	t.Run("test missing url arg", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetRotateCmd()
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither url (command line flag) nor ORB_CLI_URL (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test invalid retire arg", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetRotateCmd()
+
+		startCmd.SetArgs([]string{flag + urlFlagName, "localhost:8080", flag + retireFlagName, "xxx"})
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for retire")
+	})
+}
+
+func TestRotate(t *testing.T) {
+	var (
+		authHeader string
+		method     string
+		path       string
+		query      url.Values
+	)
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		method = r.Method
+		path = r.URL.Path
+		query = r.URL.Query()
+
+		if r.URL.Path == "/invalid" {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		_, err := w.Write([]byte(`[{"id":"key2","publicKey":"a2V5Mg=="},{"id":"key1","publicKey":"a2V5MQ==",` +
+			`"retireTime":"2021-12-31T00:00:00Z"}]`))
+		require.NoError(t, err)
+	}))
+	defer serv.Close()
+
+	t.Run("retire time", func(t *testing.T) {
+		cmd := GetRotateCmd()
+
+		cmd.SetArgs([]string{
+			flag + urlFlagName, serv.URL + "/keys/rotate",
+			flag + retireFlagName, "2021-12-31T00:00:00Z",
+			flag + authTokenFlagName, "ADMIN_TOKEN",
+		})
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, http.MethodPost, method)
+		require.Equal(t, "/keys/rotate", path)
+		require.Equal(t, "2021-12-31T00:00:00Z", query.Get(retireParam))
+		require.Equal(t, "Bearer ADMIN_TOKEN", authHeader)
+	})
+
+	t.Run("default retire time", func(t *testing.T) {
+		cmd := GetRotateCmd()
+
+		cmd.SetArgs([]string{flag + urlFlagName, serv.URL + "/keys/rotate"})
+
+		require.NoError(t, cmd.Execute())
+		require.Empty(t, query.Get(retireParam))
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		cmd := GetRotateCmd()
+
+		cmd.SetArgs([]string{flag + urlFlagName, serv.URL + "/invalid"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+}
@@ -10,13 +10,21 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/trustbloc/edge-core/pkg/log"
 
+	"github.com/trustbloc/orb/cmd/orb-cli/anchorstatuscmd"
+	"github.com/trustbloc/orb/cmd/orb-cli/approvalcmd"
+	"github.com/trustbloc/orb/cmd/orb-cli/cascmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/createdidcmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/deactivatedidcmd"
+	"github.com/trustbloc/orb/cmd/orb-cli/deadlettercmd"
+	"github.com/trustbloc/orb/cmd/orb-cli/didhistorycmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/followcmd"
+	"github.com/trustbloc/orb/cmd/orb-cli/graphcmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/ipfskeygencmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/ipnshostmetagencmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/ipnshostmetauploadcmd"
+	"github.com/trustbloc/orb/cmd/orb-cli/keyscmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/recoverdidcmd"
+	"github.com/trustbloc/orb/cmd/orb-cli/tokencmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/updatedidcmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/witnesscmd"
 )
@@ -45,6 +53,78 @@ func main() {
 		},
 	}
 
+	anchorCmd := &cobra.Command{
+		Use: "anchor",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	graphCmd := &cobra.Command{
+		Use: "graph",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	approvalCmd := &cobra.Command{
+		Use: "approval",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	deadLetterCmd := &cobra.Command{
+		Use: "deadletter",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	casCmd := &cobra.Command{
+		Use: "cas",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	keysCmd := &cobra.Command{
+		Use: "keys",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	tokenCmd := &cobra.Command{
+		Use: "token",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	anchorCmd.AddCommand(anchorstatuscmd.GetCmd())
+
+	approvalCmd.AddCommand(approvalcmd.GetListCmd())
+	approvalCmd.AddCommand(approvalcmd.GetApproveCmd())
+	approvalCmd.AddCommand(approvalcmd.GetRejectCmd())
+
+	deadLetterCmd.AddCommand(deadlettercmd.GetListCmd())
+	deadLetterCmd.AddCommand(deadlettercmd.GetGetCmd())
+	deadLetterCmd.AddCommand(deadlettercmd.GetReplayCmd())
+	deadLetterCmd.AddCommand(deadlettercmd.GetPurgeCmd())
+
+	casCmd.AddCommand(cascmd.GetGCCmd())
+	casCmd.AddCommand(cascmd.GetScrubCmd())
+
+	keysCmd.AddCommand(keyscmd.GetRotateCmd())
+
+	tokenCmd.AddCommand(tokencmd.GetCreateCmd())
+	tokenCmd.AddCommand(tokencmd.GetListCmd())
+	tokenCmd.AddCommand(tokencmd.GetRevokeCmd())
+
+	graphCmd.AddCommand(graphcmd.GetExportCmd())
+	graphCmd.AddCommand(graphcmd.GetVerifyCmd())
+
 	ipfsCmd.AddCommand(ipfskeygencmd.GetCmd())
 	ipfsCmd.AddCommand(ipnshostmetagencmd.GetCmd())
 	ipfsCmd.AddCommand(ipnshostmetauploadcmd.GetCmd())
@@ -53,11 +133,19 @@ func main() {
 	didCmd.AddCommand(updatedidcmd.GetUpdateDIDCmd())
 	didCmd.AddCommand(recoverdidcmd.GetRecoverDIDCmd())
 	didCmd.AddCommand(deactivatedidcmd.GetDeactivateDIDCmd())
+	didCmd.AddCommand(didhistorycmd.GetCmd())
 
 	rootCmd.AddCommand(didCmd)
 	rootCmd.AddCommand(ipfsCmd)
+	rootCmd.AddCommand(anchorCmd)
+	rootCmd.AddCommand(graphCmd)
 	rootCmd.AddCommand(followcmd.GetCmd())
 	rootCmd.AddCommand(witnesscmd.GetCmd())
+	rootCmd.AddCommand(approvalCmd)
+	rootCmd.AddCommand(deadLetterCmd)
+	rootCmd.AddCommand(casCmd)
+	rootCmd.AddCommand(keysCmd)
+	rootCmd.AddCommand(tokenCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		logger.Fatalf("Failed to run orb-cli: %s", err.Error())
@@ -0,0 +1,252 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tokencmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	tlsutils "github.com/trustbloc/edge-core/pkg/utils/tls"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+)
+
+const (
+	urlFlagName  = "url"
+	urlFlagUsage = "The URL of the API token endpoint (e.g. https://orb.domain1.com/tokens)." +
+		" Alternatively, this can be set with the following environment variable: " + urlEnvKey
+	urlEnvKey = "ORB_CLI_URL"
+
+	nameFlagName  = "name"
+	nameFlagUsage = "A descriptive name of the token, e.g. the name of the client to which it's issued." +
+		" Alternatively, this can be set with the following environment variable: " + nameEnvKey
+	nameEnvKey = "ORB_CLI_NAME"
+
+	scopeFlagName  = "scope"
+	scopeFlagUsage = "The scopes of the token. A scope is the name of a token in the server's auth-tokens-def" +
+		" configuration (e.g. admin or read) and grants the same access as the static token of that name." +
+		" Alternatively, this can be set with the following environment variable (comma-separated): " + scopeEnvKey
+	scopeEnvKey = "ORB_CLI_SCOPE"
+
+	expiryFlagName  = "expiry"
+	expiryFlagUsage = "The time (in RFC3339 format, e.g. 2021-12-31T00:00:00Z) after which the token is no longer" +
+		" accepted. If not set then the token doesn't expire." +
+		" Alternatively, this can be set with the following environment variable: " + expiryEnvKey
+	expiryEnvKey = "ORB_CLI_EXPIRY"
+
+	idFlagName  = "id"
+	idFlagUsage = "The ID of the token." +
+		" Alternatively, this can be set with the following environment variable: " + idEnvKey
+	idEnvKey = "ORB_CLI_ID"
+
+	tlsSystemCertPoolFlagName  = "tls-systemcertpool"
+	tlsSystemCertPoolFlagUsage = "Use system certificate pool." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + tlsSystemCertPoolEnvKey
+	tlsSystemCertPoolEnvKey = "ORB_CLI_TLS_SYSTEMCERTPOOL"
+
+	tlsCACertsFlagName  = "tls-cacerts"
+	tlsCACertsFlagUsage = "Comma-Separated list of ca certs path." +
+		" Alternatively, this can be set with the following environment variable: " + tlsCACertsEnvKey
+	tlsCACertsEnvKey = "ORB_CLI_TLS_CACERTS"
+
+	authTokenFlagName  = "auth-token"
+	authTokenFlagUsage = "Auth token." +
+		" Alternatively, this can be set with the following environment variable: " + authTokenEnvKey
+	authTokenEnvKey = "ORB_CLI_AUTH_TOKEN" //nolint:gosec
+)
+
+const revokePath = "/revoke"
+
+type createRequest struct {
+	Name   string     `json:"name,omitempty"`
+	Scopes []string   `json:"scopes"`
+	Expiry *time.Time `json:"expiry,omitempty"`
+}
+
+// GetCreateCmd returns the Cobra command that creates an API token.
+func GetCreateCmd() *cobra.Command {
+	cmd := newCmd("create", "create an API token",
+		"Issues a new API token with the given scopes and optional expiry. The response contains the token "+
+			"value which must be passed to the client. The value can't be retrieved again.",
+		func(cmd *cobra.Command, httpClient *http.Client, tokensURL string, headers map[string]string) error {
+			req, err := getCreateRequest(cmd)
+			if err != nil {
+				return err
+			}
+
+			reqBytes, err := json.Marshal(req)
+			if err != nil {
+				return fmt.Errorf("marshal request: %w", err)
+			}
+
+			headers["Content-Type"] = "application/json"
+
+			return send(httpClient, reqBytes, headers, http.MethodPost, tokensURL)
+		},
+	)
+
+	createFlags(cmd)
+	cmd.Flags().StringP(nameFlagName, "", "", nameFlagUsage)
+	cmd.Flags().StringArrayP(scopeFlagName, "", []string{}, scopeFlagUsage)
+	cmd.Flags().StringP(expiryFlagName, "", "", expiryFlagUsage)
+
+	return cmd
+}
+
+// GetListCmd returns the Cobra command that lists API tokens.
+func GetListCmd() *cobra.Command {
+	cmd := newCmd("list", "list API tokens",
+		"Lists the issued API tokens, including expired and revoked tokens. Token values are not included.",
+		func(cmd *cobra.Command, httpClient *http.Client, tokensURL string, headers map[string]string) error {
+			return send(httpClient, nil, headers, http.MethodGet, tokensURL)
+		},
+	)
+
+	createFlags(cmd)
+
+	return cmd
+}
+
+// GetRevokeCmd returns the Cobra command that revokes an API token.
+func GetRevokeCmd() *cobra.Command {
+	cmd := newCmd("revoke", "revoke an API token",
+		"Revokes the API token with the given ID. The token is no longer accepted by the server.",
+		func(cmd *cobra.Command, httpClient *http.Client, tokensURL string, headers map[string]string) error {
+			id, err := cmdutils.GetUserSetVarFromString(cmd, idFlagName, idEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			return send(httpClient, nil, headers, http.MethodPost,
+				strings.TrimSuffix(tokensURL, "/")+"/"+url.PathEscape(id)+revokePath)
+		},
+	)
+
+	createFlags(cmd)
+	cmd.Flags().StringP(idFlagName, "", "", idFlagUsage)
+
+	return cmd
+}
+
+type runFunc func(cmd *cobra.Command, httpClient *http.Client, tokensURL string, headers map[string]string) error
+
+func newCmd(use, short, long string, run runFunc) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long:  long,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootCAs, err := getRootCAs(cmd)
+			if err != nil {
+				return err
+			}
+
+			httpClient := &http.Client{
+				Transport: &http.Transport{
+					TLSClientConfig: &tls.Config{
+						RootCAs:    rootCAs,
+						MinVersion: tls.VersionTLS12,
+					},
+				},
+			}
+
+			tokensURL, err := cmdutils.GetUserSetVarFromString(cmd, urlFlagName, urlEnvKey, false)
+			if err != nil {
+				return err
+			}
+
+			authToken := cmdutils.GetUserSetOptionalVarFromString(cmd, authTokenFlagName, authTokenEnvKey)
+
+			headers := make(map[string]string)
+			if authToken != "" {
+				headers["Authorization"] = "Bearer " + authToken
+			}
+
+			return run(cmd, httpClient, tokensURL, headers)
+		},
+	}
+}
+
+func send(httpClient *http.Client, req []byte, headers map[string]string, method, endpointURL string) error {
+	resp, err := common.SendRequest(httpClient, req, headers, method, endpointURL)
+	if err != nil {
+		return fmt.Errorf("failed to send http request: %w", err)
+	}
+
+	var out bytes.Buffer
+
+	err = json.Indent(&out, resp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("invalid token response: %w", err)
+	}
+
+	fmt.Println(out.String())
+
+	return nil
+}
+
+func getCreateRequest(cmd *cobra.Command) (*createRequest, error) {
+	scopes, err := cmdutils.GetUserSetVarFromArrayString(cmd, scopeFlagName, scopeEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &createRequest{
+		Name:   cmdutils.GetUserSetOptionalVarFromString(cmd, nameFlagName, nameEnvKey),
+		Scopes: scopes,
+	}
+
+	expiry := cmdutils.GetUserSetOptionalVarFromString(cmd, expiryFlagName, expiryEnvKey)
+	if expiry != "" {
+		t, err := time.Parse(time.RFC3339, expiry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", expiryFlagName, err)
+		}
+
+		req.Expiry = &t
+	}
+
+	return req, nil
+}
+
+func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
+	tlsSystemCertPoolString := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
+		tlsSystemCertPoolEnvKey)
+
+	tlsSystemCertPool := false
+
+	if tlsSystemCertPoolString != "" {
+		var err error
+		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tlsCACerts := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName,
+		tlsCACertsEnvKey)
+
+	return tlsutils.GetCertPool(tlsSystemCertPool, tlsCACerts)
+}
+
+func createFlags(startCmd *cobra.Command) {
+	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "", tlsSystemCertPoolFlagUsage)
+	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
+	startCmd.Flags().StringP(urlFlagName, "", "", urlFlagUsage)
+	startCmd.Flags().StringP(authTokenFlagName, "", "", authTokenFlagUsage)
+}
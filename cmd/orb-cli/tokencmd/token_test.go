@@ -0,0 +1,178 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tokencmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const flag = "--"
+
+func TestTLSSystemCertPoolInvalidArgsEnvVar(t *testing.T) {
+	startCmd := GetListCmd()
+
+	require.NoError(t, os.Setenv(tlsSystemCertPoolEnvKey, "wrongvalue"))
+	defer os.Clearenv()
+
+	err := startCmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid syntax")
+}
+
+func TestCmdWithMissingArg(t *testing.T) {
+	t.Run("test missing url arg", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetListCmd()
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither url (command line flag) nor ORB_CLI_URL (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test missing scope arg", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetCreateCmd()
+
+		startCmd.SetArgs([]string{flag + urlFlagName, "localhost:8080"})
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither scope (command line flag) nor ORB_CLI_SCOPE (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test invalid expiry arg", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetCreateCmd()
+
+		startCmd.SetArgs([]string{
+			flag + urlFlagName, "localhost:8080",
+			flag + scopeFlagName, "admin",
+			flag + expiryFlagName, "xxx",
+		})
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for expiry")
+	})
+
+	t.Run("test missing id arg", func(t *testing.T) {
+		os.Clearenv()
+
+		startCmd := GetRevokeCmd()
+
+		startCmd.SetArgs([]string{flag + urlFlagName, "localhost:8080"})
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither id (command line flag) nor ORB_CLI_ID (environment variable) have been set.",
+			err.Error())
+	})
+}
+
+func TestToken(t *testing.T) {
+	var (
+		authHeader string
+		method     string
+		path       string
+		request    *createRequest
+	)
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		method = r.Method
+		path = r.URL.Path
+
+		switch {
+		case r.URL.Path == "/invalid":
+			w.WriteHeader(http.StatusInternalServerError)
+		case r.Method == http.MethodGet:
+			_, err := w.Write([]byte(`[{"id":"123","name":"client1","scopes":["admin"]}]`))
+			require.NoError(t, err)
+		case r.URL.Path == "/tokens":
+			request = &createRequest{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(request))
+
+			_, err := w.Write([]byte(`{"id":"123","name":"client1","scopes":["admin"],"token":"123.secret"}`))
+			require.NoError(t, err)
+		default:
+			_, err := w.Write([]byte(`{"id":"123","name":"client1","scopes":["admin"],"revoked":"2021-12-31T00:00:00Z"}`))
+			require.NoError(t, err)
+		}
+	}))
+	defer serv.Close()
+
+	tokensURL := serv.URL + "/tokens"
+
+	t.Run("create", func(t *testing.T) {
+		cmd := GetCreateCmd()
+
+		cmd.SetArgs([]string{
+			flag + urlFlagName, tokensURL,
+			flag + nameFlagName, "client1",
+			flag + scopeFlagName, "admin",
+			flag + scopeFlagName, "read",
+			flag + expiryFlagName, "2031-12-31T00:00:00Z",
+			flag + authTokenFlagName, "ADMIN_TOKEN",
+		})
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, http.MethodPost, method)
+		require.Equal(t, "/tokens", path)
+		require.Equal(t, "Bearer ADMIN_TOKEN", authHeader)
+		require.NotNil(t, request)
+		require.Equal(t, "client1", request.Name)
+		require.Equal(t, []string{"admin", "read"}, request.Scopes)
+		require.NotNil(t, request.Expiry)
+		require.Equal(t, 2031, request.Expiry.Year())
+	})
+
+	t.Run("list", func(t *testing.T) {
+		cmd := GetListCmd()
+
+		cmd.SetArgs([]string{flag + urlFlagName, tokensURL})
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, http.MethodGet, method)
+		require.Equal(t, "/tokens", path)
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		cmd := GetRevokeCmd()
+
+		cmd.SetArgs([]string{flag + urlFlagName, tokensURL, flag + idFlagName, "123"})
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, http.MethodPost, method)
+		require.Equal(t, "/tokens/123/revoke", path)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		cmd := GetListCmd()
+
+		cmd.SetArgs([]string{flag + urlFlagName, serv.URL + "/invalid"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+	})
+}
@@ -60,7 +60,8 @@ const (
 	authTokenEnvKey = "ORB_CLI_AUTH_TOKEN" //nolint:gosec
 
 	inviteWitnessFlagName  = "invite-witness-id"
-	inviteWitnessFlagUsage = "Invite witness id required for undo action." +
+	inviteWitnessFlagUsage = "Invite witness id for undo action. If not set then the ID of the 'InviteWitness'" +
+		" activity is looked up in the outbox." +
 		" Alternatively, this can be set with the following environment variable: " + inviteWitnessEnvKey
 	inviteWitnessEnvKey = "ORB_CLI_INVITE_WITNESS_ID"
 )
@@ -136,6 +137,11 @@ func cmd() *cobra.Command { //nolint:funlen,gocyclo,cyclop,gocognit
 			authToken := cmdutils.GetUserSetOptionalVarFromString(cmd, authTokenFlagName,
 				authTokenEnvKey)
 
+			headers := make(map[string]string)
+			if authToken != "" {
+				headers["Authorization"] = "Bearer " + authToken
+			}
+
 			var reqBytes []byte
 
 			switch action {
@@ -153,15 +159,9 @@ func cmd() *cobra.Command { //nolint:funlen,gocyclo,cyclop,gocognit
 				}
 
 			case undoAction:
-				inviteWitness, errGet := cmdutils.GetUserSetVarFromString(cmd, inviteWitnessFlagName,
-					inviteWitnessEnvKey, false)
-				if errGet != nil {
-					return errGet
-				}
-
-				inviteWitnessIRI, e := url.Parse(inviteWitness)
+				inviteWitnessIRI, e := getInviteWitnessIRI(cmd, httpClient, headers, outboxURL, actorIRI, toIRI)
 				if e != nil {
-					return fmt.Errorf("parse 'witnessID' URL %s: %w", inviteWitness, e)
+					return e
 				}
 
 				undo := vocab.NewUndoActivity(
@@ -186,11 +186,6 @@ func cmd() *cobra.Command { //nolint:funlen,gocyclo,cyclop,gocognit
 				return fmt.Errorf("action %s not supported", action)
 			}
 
-			headers := make(map[string]string)
-			if authToken != "" {
-				headers["Authorization"] = "Bearer " + authToken
-			}
-
 			resp, err := common.SendRequest(httpClient, reqBytes, headers, http.MethodPost,
 				outboxURL)
 			if err != nil {
@@ -204,6 +199,34 @@ func cmd() *cobra.Command { //nolint:funlen,gocyclo,cyclop,gocognit
 	}
 }
 
+func getInviteWitnessIRI(cmd *cobra.Command, httpClient *http.Client, headers map[string]string,
+	outboxURL string, actorIRI, toIRI *url.URL) (*url.URL, error) {
+	inviteWitness := cmdutils.GetUserSetOptionalVarFromString(cmd, inviteWitnessFlagName, inviteWitnessEnvKey)
+
+	if inviteWitness != "" {
+		inviteWitnessIRI, err := url.Parse(inviteWitness)
+		if err != nil {
+			return nil, fmt.Errorf("parse 'witnessID' URL %s: %w", inviteWitness, err)
+		}
+
+		return inviteWitnessIRI, nil
+	}
+
+	invite, err := common.FindOutboxActivity(httpClient, headers, outboxURL,
+		func(activity *vocab.ActivityType) bool {
+			return activity.Type().Is(vocab.TypeInvite) &&
+				common.SameIRI(activity.Actor(), actorIRI) &&
+				common.SameIRI(activity.Object().IRI(), vocab.AnchorWitnessTargetIRI) &&
+				common.SameIRI(activity.Target().IRI(), toIRI)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("find 'InviteWitness' activity to %s in outbox: %w", toIRI, err)
+	}
+
+	return invite.ID().URL(), nil
+}
+
 func getRootCAs(cmd *cobra.Command) (*x509.CertPool, error) {
 	tlsSystemCertPoolString := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
 		tlsSystemCertPoolEnvKey)
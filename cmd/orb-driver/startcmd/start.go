@@ -7,6 +7,7 @@ package startcmd
 
 import (
 	"crypto/tls"
+	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
@@ -50,8 +51,10 @@ const (
 	tlsKeyEnvKey = "ORB_DRIVER_TLS_KEY"
 
 	domainFlagName  = "domain"
-	domainFlagUsage = "discovery endpoint domain"
-	domainEnvKey    = "ORB_DRIVER_DOMAIN"
+	domainFlagUsage = "discovery endpoint domain (e.g. https://orb.domain1.com). If set then the resolution" +
+		" endpoint of the domain is used to resolve DIDs, which supports the versionId and versionTime" +
+		" resolution options. Alternatively, this can be set with the following environment variable: " + domainEnvKey
+	domainEnvKey = "ORB_DRIVER_DOMAIN"
 
 	sidetreeTokenFlagName  = "sidetree-write-token"
 	sidetreeTokenEnvKey    = "ORB_DRIVER_SIDETREE_TOKEN" //nolint: gosec
@@ -185,16 +188,21 @@ func startDriver(parameters *parameters) error {
 		return err
 	}
 
+	tlsConfig := &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
+
 	orbVDR, err := orb.New(nil, orb.WithAuthToken(parameters.sidetreeToken),
 		orb.WithDomain(parameters.discoveryDomain),
-		orb.WithTLSConfig(&tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}))
+		orb.WithTLSConfig(tlsConfig))
 	if err != nil {
 		return err
 	}
 
 	// create driver rest api
 	endpointDiscoveryOp := driverrest.New(&driverrest.Config{
-		OrbVDR: orbVDR,
+		OrbVDR:     orbVDR,
+		Domain:     parameters.discoveryDomain,
+		HTTPClient: &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}},
+		AuthToken:  parameters.sidetreeToken,
 	})
 
 	handlers := make([]restcommon.HTTPHandler, 0)
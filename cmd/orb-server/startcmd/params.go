@@ -17,7 +17,12 @@ import (
 	"github.com/spf13/cobra"
 	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
 
+	s3cas "github.com/trustbloc/orb/pkg/cas/s3"
 	"github.com/trustbloc/orb/pkg/httpserver/auth"
+	"github.com/trustbloc/orb/pkg/httpserver/auth/jwtauth"
+	versioncommon "github.com/trustbloc/orb/pkg/protocolversion/common"
+	"github.com/trustbloc/orb/pkg/protocolversion/factoryregistry"
+	"github.com/trustbloc/orb/pkg/vcsigner"
 )
 
 const (
@@ -26,6 +31,8 @@ const (
 	defaultActivityPubPageSize          = 50
 	defaultNodeInfoRefreshInterval      = 15 * time.Second
 	defaultIPFSTimeout                  = 20 * time.Second
+	defaultCASGCGracePeriod             = 24 * time.Hour
+	defaultKeyRotationOverlapPeriod     = 30 * 24 * time.Hour
 	mqDefaultMaxConnectionSubscriptions = 1000
 
 	commonEnvVarUsageText = "Alternatively, this can be set with the following environment variable: "
@@ -69,6 +76,18 @@ const (
 	privateKeyFlagUsage = "Private Key base64 (ED25519Type)." +
 		" Alternatively, this can be set with the following environment variable: " + privateKeyEnvKey
 
+	remoteSignerURLFlagName  = "remote-signer-url"
+	remoteSignerURLEnvKey    = "ORB_REMOTE_SIGNER_URL"
+	remoteSignerURLFlagUsage = "The URL of a remote signing service (e.g. one that's backed by an HSM). If set, " +
+		"signing keys are created in the remote service and anchor credentials and HTTP signatures are signed " +
+		"by the service so that the private keys never leave it. The BbsBlsSignature2020 anchor credential signature " +
+		"suite isn't supported with a remote signer. " + commonEnvVarUsageText + remoteSignerURLEnvKey
+
+	remoteSignerAuthTokenFlagName  = "remote-signer-auth-token"
+	remoteSignerAuthTokenEnvKey    = "ORB_REMOTE_SIGNER_AUTH_TOKEN"
+	remoteSignerAuthTokenFlagUsage = "The bearer token that's included in requests to the remote signing service. " +
+		commonEnvVarUsageText + remoteSignerAuthTokenEnvKey
+
 	secretLockKeyPathFlagName  = "secret-lock-key-path"
 	secretLockKeyPathEnvKey    = "ORB_SECRET_LOCK_KEY_PATH"
 	secretLockKeyPathFlagUsage = "The path to the file with key to be used by local secret lock. If missing noop " +
@@ -109,9 +128,46 @@ const (
 	casTypeFlagShorthand = "c"
 	casTypeEnvKey        = "CAS_TYPE"
 	casTypeFlagUsage     = "The type of the Content Addressable Storage (CAS). " +
-		"Supported options: local, ipfs. For local, the storage provider specified by " + databaseTypeFlagName +
+		"Supported options: local, ipfs, disk, s3. For local, the storage provider specified by " + databaseTypeFlagName +
 		" will be used. For ipfs, the node specified by " + ipfsURLFlagName +
-		" will be used. This is a required parameter. " + commonEnvVarUsageText + casTypeEnvKey
+		" will be used. For disk, content is stored as files in the directory specified by " + casDiskPathFlagName +
+		". For s3, content is stored in the S3-compatible bucket specified by " + casS3BucketFlagName +
+		". This is a required parameter. " + commonEnvVarUsageText + casTypeEnvKey
+
+	casDiskPathFlagName  = "cas-disk-path"
+	casDiskPathEnvKey    = "CAS_DISK_PATH"
+	casDiskPathFlagUsage = "The directory in which content is stored if " + casTypeFlagName + " is set to disk. " +
+		"The directory is created if it doesn't exist. " + commonEnvVarUsageText + casDiskPathEnvKey
+
+	casS3EndpointFlagName  = "cas-s3-endpoint"
+	casS3EndpointEnvKey    = "CAS_S3_ENDPOINT"
+	casS3EndpointFlagUsage = "The URL of the S3-compatible object storage service if " + casTypeFlagName +
+		" is set to s3, e.g. https://s3.us-east-1.amazonaws.com. " + commonEnvVarUsageText + casS3EndpointEnvKey
+
+	casS3RegionFlagName  = "cas-s3-region"
+	casS3RegionEnvKey    = "CAS_S3_REGION"
+	casS3RegionFlagUsage = "The region of the S3 bucket. Defaults to us-east-1 if not set. " +
+		commonEnvVarUsageText + casS3RegionEnvKey
+
+	casS3BucketFlagName  = "cas-s3-bucket"
+	casS3BucketEnvKey    = "CAS_S3_BUCKET"
+	casS3BucketFlagUsage = "The name of the S3 bucket in which content is stored if " + casTypeFlagName +
+		" is set to s3. The bucket must already exist. " + commonEnvVarUsageText + casS3BucketEnvKey
+
+	casS3KeyPrefixFlagName  = "cas-s3-key-prefix"
+	casS3KeyPrefixEnvKey    = "CAS_S3_KEY_PREFIX"
+	casS3KeyPrefixFlagUsage = "An optional prefix for the keys of the objects stored in the S3 bucket. " +
+		commonEnvVarUsageText + casS3KeyPrefixEnvKey
+
+	casS3AccessKeyIDFlagName  = "cas-s3-access-key-id"
+	casS3AccessKeyIDEnvKey    = "CAS_S3_ACCESS_KEY_ID"
+	casS3AccessKeyIDFlagUsage = "The access key ID used to sign requests to the S3 service. " +
+		commonEnvVarUsageText + casS3AccessKeyIDEnvKey
+
+	casS3SecretAccessKeyFlagName  = "cas-s3-secret-access-key"
+	casS3SecretAccessKeyEnvKey    = "CAS_S3_SECRET_ACCESS_KEY" //nolint: gosec
+	casS3SecretAccessKeyFlagUsage = "The secret access key used to sign requests to the S3 service. " +
+		commonEnvVarUsageText + casS3SecretAccessKeyEnvKey
 
 	ipfsURLFlagName      = "ipfs-url"
 	ipfsURLFlagShorthand = "r"
@@ -213,6 +269,8 @@ const (
 	anchorCredentialSignatureSuiteEnvKey        = "ANCHOR_CREDENTIAL_SIGNATURE_SUITE"
 	anchorCredentialSignatureSuiteFlagShorthand = "z"
 	anchorCredentialSignatureSuiteFlagUsage     = "Anchor credential signature suite (required). " +
+		"Supported suites: Ed25519Signature2018, Ed25519Signature2020, JsonWebSignature2020, " +
+		"EcdsaSecp256k1Signature2019 and BbsBlsSignature2020. " +
 		commonEnvVarUsageText + anchorCredentialSignatureSuiteEnvKey
 
 	anchorCredentialDomainFlagName      = "anchor-credential-domain"
@@ -231,6 +289,72 @@ const (
 	maxWitnessDelayFlagShorthand = "w"
 	maxWitnessDelayFlagUsage     = "Maximum witness response time (in seconds). " + commonEnvVarUsageText + maxWitnessDelayEnvKey
 
+	witnessTimeoutActionsFlagName  = "witness-timeout-actions"
+	witnessTimeoutActionsEnvKey    = "WITNESS_TIMEOUT_ACTIONS"
+	witnessTimeoutActionsFlagUsage = "The actions to take, in order, each time the witness policy for an anchor " +
+		"credential is not satisfied within the maximum witness delay. Supported actions are 're-offer', " +
+		"'escalate' and 'fail'. If not set then no action is taken. " + commonEnvVarUsageText + witnessTimeoutActionsEnvKey
+
+	witnessEscalationWitnessesFlagName  = "witness-escalation-witnesses"
+	witnessEscalationWitnessesEnvKey    = "WITNESS_ESCALATION_WITNESSES"
+	witnessEscalationWitnessesFlagUsage = "The witnesses (service IRIs) that an anchor credential is offered to " +
+		"by the 'escalate' witness time-out action. " + commonEnvVarUsageText + witnessEscalationWitnessesEnvKey
+
+	anchorSyncPeersFlagName  = "anchor-sync-peers"
+	anchorSyncPeersEnvKey    = "ANCHOR_SYNC_PEERS"
+	anchorSyncPeersFlagUsage = "The peers (service IRIs) whose anchor history is synchronized on startup. The outbox " +
+		"of each peer is paged through from the oldest to the newest activity and every anchor credential is " +
+		"retrieved and processed so that all of the peer's DIDs may be resolved. Progress is checkpointed so that synchronization resumes after a restart. " +
+		commonEnvVarUsageText + anchorSyncPeersEnvKey
+
+	followAuthModeFlagName  = "follow-auth-mode"
+	followAuthModeEnvKey    = "FOLLOW_AUTH_MODE"
+	followAuthModeFlagUsage = "The mode for authorizing 'Follow' requests that pass the actor authorization checks. " +
+		"Supported modes are 'accept' (the request is accepted immediately) and 'pending' (the request is parked " +
+		"until it is approved or rejected via the pending approvals endpoint). Defaults to 'accept'. " +
+		commonEnvVarUsageText + followAuthModeEnvKey
+
+	inviteWitnessAuthModeFlagName  = "invite-witness-auth-mode"
+	inviteWitnessAuthModeEnvKey    = "INVITE_WITNESS_AUTH_MODE"
+	inviteWitnessAuthModeFlagUsage = "The mode for authorizing 'InviteWitness' requests that pass the actor " +
+		"authorization checks. Supported modes are 'accept' (the request is accepted immediately) and 'pending' " +
+		"(the request is parked until it is approved or rejected via the pending approvals endpoint). " +
+		"Defaults to 'accept'. " + commonEnvVarUsageText + inviteWitnessAuthModeEnvKey
+
+	actorAuthAllowedDomainsFlagName  = "actor-auth-allowed-domains"
+	actorAuthAllowedDomainsEnvKey    = "ACTOR_AUTH_ALLOWED_DOMAINS"
+	actorAuthAllowedDomainsFlagUsage = "The domains from which 'Follow' and 'InviteWitness' requests are allowed. " +
+		"A domain may be prefixed with '*.' to match all of its sub-domains. If not set then all domains are " +
+		"allowed. " + commonEnvVarUsageText + actorAuthAllowedDomainsEnvKey
+
+	actorAuthDeniedDomainsFlagName  = "actor-auth-denied-domains"
+	actorAuthDeniedDomainsEnvKey    = "ACTOR_AUTH_DENIED_DOMAINS"
+	actorAuthDeniedDomainsFlagUsage = "The domains from which 'Follow' and 'InviteWitness' requests are always " +
+		"rejected. A domain may be prefixed with '*.' to match all of its sub-domains. " +
+		commonEnvVarUsageText + actorAuthDeniedDomainsEnvKey
+
+	actorAuthRequireHostMetaFlagName  = "actor-auth-require-host-meta"
+	actorAuthRequireHostMetaEnvKey    = "ACTOR_AUTH_REQUIRE_HOST_META"
+	actorAuthRequireHostMetaFlagUsage = `Set to "true" to reject 'Follow' and 'InviteWitness' requests from actors ` +
+		"whose domain does not publish a host-meta document with an ActivityPub link to the actor. Defaults to false. " +
+		commonEnvVarUsageText + actorAuthRequireHostMetaEnvKey
+
+	protocolGenesisTimesFlagName  = "protocol-genesis-times"
+	protocolGenesisTimesEnvKey    = "PROTOCOL_GENESIS_TIMES"
+	protocolGenesisTimesFlagUsage = "The activation times of protocol versions in the format version=time, " +
+		"where time is in seconds since the Unix epoch (e.g. 1.1=1767225600). New operations are created using " +
+		"the latest version whose activation time has passed. All nodes in a network must be configured with the " +
+		"same activation times. The activation time of version 1.0 may not be changed and the activation times " +
+		"must increase with the version. " +
+		commonEnvVarUsageText + protocolGenesisTimesEnvKey
+
+	protocolConfigFileFlagName  = "protocol-config-file"
+	protocolConfigFileEnvKey    = "PROTOCOL_CONFIG_FILE"
+	protocolConfigFileFlagUsage = "The path to a JSON or YAML file (with a .yaml or .yml extension) that contains the " +
+		"parameters of one or more protocol versions. Parameters that are not specified are set to the defaults " +
+		"of the version. If not set then the default parameters are used for all versions. " +
+		commonEnvVarUsageText + protocolConfigFileEnvKey
+
 	signWithLocalWitnessFlagName      = "sign-with-local-witness"
 	signWithLocalWitnessEnvKey        = "SIGN_WITH_LOCAL_WITNESS"
 	signWithLocalWitnessFlagShorthand = "f"
@@ -276,6 +400,36 @@ const (
 	authTokensFlagUsage     = "Authorization tokens."
 	authTokensEnvKey        = "ORB_AUTH_TOKENS"
 
+	dynamicAuthTokensFlagName  = "dynamic-auth-tokens"
+	dynamicAuthTokensEnvKey    = "ORB_DYNAMIC_AUTH_TOKENS"
+	dynamicAuthTokensFlagUsage = "The names of the tokens in auth-tokens-def that have no static token in " +
+		"auth-tokens since they are only granted as scopes of API tokens or JWT access tokens. " +
+		"Startup fails if any other token name in auth-tokens-def has no static token. " +
+		commonEnvVarUsageText + dynamicAuthTokensEnvKey
+
+	jwtJWKSURLFlagName  = "jwt-jwks-url"
+	jwtJWKSURLEnvKey    = "ORB_JWT_JWKS_URL"
+	jwtJWKSURLFlagUsage = "The URL (http, https or file) or file path of the JSON Web Key Set with which JWT " +
+		"(OAuth2) access tokens are verified. If set, a signed JWT may be used as the bearer token for endpoints " +
+		"that are protected by auth-tokens-def. " + commonEnvVarUsageText + jwtJWKSURLEnvKey
+
+	jwtIssuerFlagName  = "jwt-issuer"
+	jwtIssuerEnvKey    = "ORB_JWT_ISSUER"
+	jwtIssuerFlagUsage = "The required issuer ('iss' claim) of JWT access tokens. Required if " + jwtJWKSURLFlagName +
+		" is set. " + commonEnvVarUsageText + jwtIssuerEnvKey
+
+	jwtAudienceFlagName  = "jwt-audience"
+	jwtAudienceEnvKey    = "ORB_JWT_AUDIENCE"
+	jwtAudienceFlagUsage = "The audience that must be included in the 'aud' claim of JWT access tokens. Required if " +
+		jwtJWKSURLFlagName + " is set. " + commonEnvVarUsageText + jwtAudienceEnvKey
+
+	jwtScopesFlagName  = "jwt-scopes"
+	jwtScopesEnvKey    = "ORB_JWT_SCOPES"
+	jwtScopesFlagUsage = "Maps a scope in the 'scope' (or 'scp') claim of JWT access tokens to the token names in " +
+		"auth-tokens-def to which it grants access, e.g. orb:write=admin or orb:read=read&admin. " +
+		"If not set then each scope grants access to the token name of the same name. " +
+		commonEnvVarUsageText + jwtScopesEnvKey
+
 	activityPubPageSizeFlagName      = "activitypub-page-size"
 	activityPubPageSizeFlagShorthand = "P"
 	activityPubPageSizeEnvKey        = "ACTIVITYPUB_PAGE_SIZE"
@@ -299,6 +453,40 @@ const (
 	ipfsTimeoutFlagUsage     = "The timeout for IPFS requests. For example, '30s' for a 30 second timeout. " +
 		commonEnvVarUsageText + ipfsTimeoutEnvKey
 
+	casGCGracePeriodFlagName  = "cas-gc-grace-period"
+	casGCGracePeriodEnvKey    = "CAS_GC_GRACE_PERIOD"
+	casGCGracePeriodFlagUsage = "The minimum age of CAS content before it may be deleted by garbage collection " +
+		"if it isn't referenced by any anchor. For example, '48h'. Defaults to 24h. " +
+		commonEnvVarUsageText + casGCGracePeriodEnvKey
+
+	casGCIntervalFlagName  = "cas-gc-interval"
+	casGCIntervalEnvKey    = "CAS_GC_INTERVAL"
+	casGCIntervalFlagUsage = "The interval at which CAS garbage collection is run in the background, for example '24h'. " +
+		"If not set then garbage collection is only run when triggered by the /cas/gc admin endpoint. " +
+		"Garbage collection is supported for the local and disk CAS types. " +
+		commonEnvVarUsageText + casGCIntervalEnvKey
+
+	casScrubIntervalFlagName  = "cas-scrub-interval"
+	casScrubIntervalEnvKey    = "CAS_SCRUB_INTERVAL"
+	casScrubIntervalFlagUsage = "The interval at which the integrity of the CAS is verified in the background, " +
+		"for example '24h'. Corrupt content is moved to quarantine. If not set then the CAS is only verified when " +
+		"triggered by the /cas/scrub admin endpoint. Scrubbing is supported for the local and disk CAS types. " +
+		commonEnvVarUsageText + casScrubIntervalEnvKey
+
+	casScrubRepairDomainsFlagName  = "cas-scrub-repair-domains"
+	casScrubRepairDomainsEnvKey    = "CAS_SCRUB_REPAIR_DOMAINS"
+	casScrubRepairDomainsFlagUsage = "The domains (e.g. orb.domain2.com) from whose WebCAS endpoints a good copy " +
+		"of corrupt CAS content is retrieved. The domains are tried in order. If ipfs-url is set then IPFS is tried " +
+		"last. " + commonEnvVarUsageText + casScrubRepairDomainsEnvKey
+
+	keyRotationOverlapPeriodFlagName  = "key-rotation-overlap-period"
+	keyRotationOverlapPeriodEnvKey    = "KEY_ROTATION_OVERLAP_PERIOD"
+	keyRotationOverlapPeriodFlagUsage = "The amount of time that the previous signing key continues to be " +
+		"published after the key is rotated with the /keys/rotate admin endpoint (unless a retirement time is " +
+		"specified in the request). Proofs and HTTP signatures made with the previous key may be verified until the " +
+		"key is retired. For example, '720h'. Defaults to 720h. " +
+		commonEnvVarUsageText + keyRotationOverlapPeriodEnvKey
+
 	// TODO: Add verification method
 
 )
@@ -309,6 +497,8 @@ type orbParameters struct {
 	vctURL                         string
 	keyID                          string
 	privateKeyBase64               string
+	remoteSignerURL                string
+	remoteSignerAuthToken          string
 	secretLockKeyPath              string
 	kmsEndpoint                    string
 	kmsStoreEndpoint               string
@@ -318,6 +508,8 @@ type orbParameters struct {
 	didAliases                     []string
 	batchWriterTimeout             time.Duration
 	casType                        string
+	casDiskPath                    string
+	casS3Config                    *s3cas.Config
 	ipfsURL                        string
 	localCASReplicateInIPFSEnabled bool
 	cidVersion                     int
@@ -335,6 +527,16 @@ type orbParameters struct {
 	discoveryVctDomains            []string
 	discoveryMinimumResolvers      int
 	maxWitnessDelay                time.Duration
+	witnessTimeoutActions          []string
+	witnessEscalationWitnesses     []*url.URL
+	anchorSyncPeers                []*url.URL
+	followAuthMode                 string
+	inviteWitnessAuthMode          string
+	actorAuthAllowedDomains        []string
+	actorAuthDeniedDomains         []string
+	actorAuthRequireHostMeta       bool
+	protocolGenesisTimes           map[string]uint64
+	protocolConfigFile             string
 	syncTimeout                    uint64
 	signWithLocalWitness           bool
 	httpSignaturesEnabled          bool
@@ -342,11 +544,18 @@ type orbParameters struct {
 	createDocumentStoreEnabled     bool
 	authTokenDefinitions           []*auth.TokenDef
 	authTokens                     map[string]string
+	dynamicAuthTokens              []string
+	jwtAuth                        *jwtauth.Config
 	opQueuePoolSize                uint
 	activityPubPageSize            int
 	enableDevMode                  bool
 	nodeInfoRefreshInterval        time.Duration
 	ipfsTimeout                    time.Duration
+	casGCGracePeriod               time.Duration
+	casGCInterval                  time.Duration
+	casScrubInterval               time.Duration
+	casScrubRepairDomains          []string
+	keyRotationOverlapPeriod       time.Duration
 }
 
 type anchorCredentialParams struct {
@@ -385,6 +594,14 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 	keyID := cmdutils.GetUserSetOptionalVarFromString(cmd, keyIDFlagName, keyIDEnvKey)
 	privateKeyBase64 := cmdutils.GetUserSetOptionalVarFromString(cmd, privateKeyFlagName, privateKeyEnvKey)
 	secretLockKeyPath, _ := cmdutils.GetUserSetVarFromString(cmd, secretLockKeyPathFlagName, secretLockKeyPathEnvKey, true) // nolint: errcheck,lll
+	remoteSignerURL := cmdutils.GetUserSetOptionalVarFromString(cmd, remoteSignerURLFlagName, remoteSignerURLEnvKey)
+	remoteSignerAuthToken := cmdutils.GetUserSetOptionalVarFromString(cmd, remoteSignerAuthTokenFlagName,
+		remoteSignerAuthTokenEnvKey)
+
+	if remoteSignerURL != "" && privateKeyBase64 != "" {
+		return nil, fmt.Errorf("%s may not be set if %s is set since keys can't be imported into the remote signer",
+			privateKeyFlagName, remoteSignerURLFlagName)
+	}
 
 	externalEndpoint, err := cmdutils.GetUserSetVarFromString(cmd, externalEndpointFlagName, externalEndpointEnvKey, true)
 	if err != nil {
@@ -415,6 +632,20 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		return nil, err
 	}
 
+	casDiskPath, err := cmdutils.GetUserSetVarFromString(cmd, casDiskPathFlagName, casDiskPathEnvKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(casType, "disk") && casDiskPath == "" {
+		return nil, fmt.Errorf("%s must be set if CAS type is disk", casDiskPathFlagName)
+	}
+
+	casS3Config, err := getS3CASParameters(cmd, strings.EqualFold(casType, "s3"))
+	if err != nil {
+		return nil, err
+	}
+
 	ipfsURL, err := cmdutils.GetUserSetVarFromString(cmd, ipfsURLFlagName, ipfsURLEnvKey, true)
 	if err != nil {
 		return nil, err
@@ -501,6 +732,47 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		maxWitnessDelay = time.Duration(delay) * time.Second
 	}
 
+	witnessTimeoutActions := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, witnessTimeoutActionsFlagName,
+		witnessTimeoutActionsEnvKey)
+
+	witnessEscalationWitnesses, err := getWitnessEscalationWitnesses(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	anchorSyncPeers, err := getAnchorSyncPeers(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	followAuthMode := cmdutils.GetUserSetOptionalVarFromString(cmd, followAuthModeFlagName, followAuthModeEnvKey)
+	inviteWitnessAuthMode := cmdutils.GetUserSetOptionalVarFromString(cmd, inviteWitnessAuthModeFlagName,
+		inviteWitnessAuthModeEnvKey)
+
+	actorAuthAllowedDomains := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, actorAuthAllowedDomainsFlagName,
+		actorAuthAllowedDomainsEnvKey)
+	actorAuthDeniedDomains := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, actorAuthDeniedDomainsFlagName,
+		actorAuthDeniedDomainsEnvKey)
+
+	actorAuthRequireHostMetaStr := cmdutils.GetUserSetOptionalVarFromString(cmd, actorAuthRequireHostMetaFlagName,
+		actorAuthRequireHostMetaEnvKey)
+
+	actorAuthRequireHostMeta := false
+	if actorAuthRequireHostMetaStr != "" {
+		actorAuthRequireHostMeta, err = strconv.ParseBool(actorAuthRequireHostMetaStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %s", actorAuthRequireHostMetaFlagName, err)
+		}
+	}
+
+	protocolGenesisTimes, err := getProtocolGenesisTimes(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	protocolConfigFile := cmdutils.GetUserSetOptionalVarFromString(cmd, protocolConfigFileFlagName,
+		protocolConfigFileEnvKey)
+
 	signWithLocalWitnessStr, err := cmdutils.GetUserSetVarFromString(cmd, signWithLocalWitnessFlagName, signWithLocalWitnessEnvKey, true)
 	if err != nil {
 		return nil, err
@@ -605,6 +877,12 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		return nil, err
 	}
 
+	if remoteSignerURL != "" && anchorCredentialParams.signatureSuite == vcsigner.BbsBlsSignature2020 {
+		return nil, fmt.Errorf("%s may not be set to %s if %s is set since the remote signer doesn't "+
+			"support multi-message signatures", anchorCredentialSignatureSuiteFlagName,
+			vcsigner.BbsBlsSignature2020, remoteSignerURLFlagName)
+	}
+
 	allowedOrigins, err := cmdutils.GetUserSetVarFromArrayString(cmd, allowedOriginsFlagName, allowedOriginsEnvKey, true)
 	if err != nil {
 		return nil, err
@@ -635,6 +913,16 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		return nil, fmt.Errorf("authorization tokens: %w", err)
 	}
 
+	dynamicAuthTokens, err := getDynamicAuthTokens(cmd, authTokenDefs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", dynamicAuthTokensFlagName, err)
+	}
+
+	jwtAuth, err := getJWTAuthConfig(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("JWT authorization: %w", err)
+	}
+
 	activityPubPageSize, err := getActivityPubPageSize(cmd)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", activityPubPageSizeFlagName, err)
@@ -650,6 +938,32 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		return nil, fmt.Errorf("%s: %w", ipfsTimeoutFlagName, err)
 	}
 
+	casGCGracePeriod, err := getDuration(cmd, casGCGracePeriodFlagName, casGCGracePeriodEnvKey, defaultCASGCGracePeriod)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", casGCGracePeriodFlagName, err)
+	}
+
+	casGCInterval, err := getDuration(cmd, casGCIntervalFlagName, casGCIntervalEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", casGCIntervalFlagName, err)
+	}
+
+	casScrubInterval, err := getDuration(cmd, casScrubIntervalFlagName, casScrubIntervalEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", casScrubIntervalFlagName, err)
+	}
+
+	casScrubRepairDomains, err := getCASScrubRepairDomains(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", casScrubRepairDomainsFlagName, err)
+	}
+
+	keyRotationOverlapPeriod, err := getDuration(cmd, keyRotationOverlapPeriodFlagName,
+		keyRotationOverlapPeriodEnvKey, defaultKeyRotationOverlapPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", keyRotationOverlapPeriodFlagName, err)
+	}
+
 	return &orbParameters{
 		hostURL:                        hostURL,
 		hostMetricsURL:                 hostMetricsURL,
@@ -657,6 +971,8 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		kmsEndpoint:                    kmsEndpoint,
 		keyID:                          keyID,
 		privateKeyBase64:               privateKeyBase64,
+		remoteSignerURL:                remoteSignerURL,
+		remoteSignerAuthToken:          remoteSignerAuthToken,
 		secretLockKeyPath:              secretLockKeyPath,
 		kmsStoreEndpoint:               kmsStoreEndpoint,
 		discoveryDomain:                discoveryDomain,
@@ -667,6 +983,8 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		didAliases:                     didAliases,
 		allowedOrigins:                 allowedOrigins,
 		casType:                        casType,
+		casDiskPath:                    casDiskPath,
+		casS3Config:                    casS3Config,
 		ipfsURL:                        ipfsURL,
 		localCASReplicateInIPFSEnabled: localCASReplicateInIPFSEnabled,
 		cidVersion:                     cidVersion,
@@ -681,6 +999,16 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		discoveryVctDomains:            discoveryVctDomains,
 		discoveryMinimumResolvers:      discoveryMinimumResolvers,
 		maxWitnessDelay:                maxWitnessDelay,
+		witnessTimeoutActions:          witnessTimeoutActions,
+		witnessEscalationWitnesses:     witnessEscalationWitnesses,
+		anchorSyncPeers:                anchorSyncPeers,
+		followAuthMode:                 followAuthMode,
+		inviteWitnessAuthMode:          inviteWitnessAuthMode,
+		actorAuthAllowedDomains:        actorAuthAllowedDomains,
+		actorAuthDeniedDomains:         actorAuthDeniedDomains,
+		actorAuthRequireHostMeta:       actorAuthRequireHostMeta,
+		protocolGenesisTimes:           protocolGenesisTimes,
+		protocolConfigFile:             protocolConfigFile,
 		syncTimeout:                    syncTimeout,
 		signWithLocalWitness:           signWithLocalWitness,
 		httpSignaturesEnabled:          httpSignaturesEnabled,
@@ -688,13 +1016,96 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		createDocumentStoreEnabled:     createDocumentStoreEnabled,
 		authTokenDefinitions:           authTokenDefs,
 		authTokens:                     authTokens,
+		dynamicAuthTokens:              dynamicAuthTokens,
+		jwtAuth:                        jwtAuth,
 		activityPubPageSize:            activityPubPageSize,
 		enableDevMode:                  enableDevMode,
 		nodeInfoRefreshInterval:        nodeInfoRefreshInterval,
 		ipfsTimeout:                    ipfsTimeout,
+		casGCGracePeriod:               casGCGracePeriod,
+		casGCInterval:                  casGCInterval,
+		casScrubInterval:               casScrubInterval,
+		casScrubRepairDomains:          casScrubRepairDomains,
+		keyRotationOverlapPeriod:       keyRotationOverlapPeriod,
 	}, nil
 }
 
+func getProtocolGenesisTimes(cmd *cobra.Command) (map[string]uint64, error) {
+	genesisTimesStr := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, protocolGenesisTimesFlagName,
+		protocolGenesisTimesEnvKey)
+
+	genesisTimes := make(map[string]uint64)
+
+	for _, keyValStr := range genesisTimesStr {
+		keyVal := strings.Split(keyValStr, "=")
+
+		if len(keyVal) != 2 {
+			return nil, fmt.Errorf("invalid value for %s [%s]: expecting version=time",
+				protocolGenesisTimesFlagName, keyValStr)
+		}
+
+		if keyVal[0] == factoryregistry.V1_0 {
+			return nil, fmt.Errorf("invalid value for %s [%s]: the genesis time of version %s may not be changed",
+				protocolGenesisTimesFlagName, keyValStr, factoryregistry.V1_0)
+		}
+
+		genesisTime, err := strconv.ParseUint(keyVal[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s [%s]: %w", protocolGenesisTimesFlagName, keyValStr, err)
+		}
+
+		genesisTimes[keyVal[0]] = genesisTime
+	}
+
+	// The genesis times must increase with the version, starting from the initial version (at 0).
+	allGenesisTimes := map[string]uint64{factoryregistry.V1_0: 0}
+
+	for version, genesisTime := range genesisTimes {
+		allGenesisTimes[version] = genesisTime
+	}
+
+	if err := versioncommon.ValidateGenesisTimes(allGenesisTimes); err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", protocolGenesisTimesFlagName, err)
+	}
+
+	return genesisTimes, nil
+}
+
+func getWitnessEscalationWitnesses(cmd *cobra.Command) ([]*url.URL, error) {
+	witnesses := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, witnessEscalationWitnessesFlagName,
+		witnessEscalationWitnessesEnvKey)
+
+	witnessesIRI := make([]*url.URL, len(witnesses))
+
+	for i, w := range witnesses {
+		witnessIRI, err := url.Parse(w)
+		if err != nil {
+			return nil, fmt.Errorf("invalid escalation witness [%s]: %w", w, err)
+		}
+
+		witnessesIRI[i] = witnessIRI
+	}
+
+	return witnessesIRI, nil
+}
+
+func getAnchorSyncPeers(cmd *cobra.Command) ([]*url.URL, error) {
+	peers := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, anchorSyncPeersFlagName, anchorSyncPeersEnvKey)
+
+	peerIRIs := make([]*url.URL, len(peers))
+
+	for i, p := range peers {
+		peerIRI, err := url.Parse(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid anchor sync peer [%s]: %w", p, err)
+		}
+
+		peerIRIs[i] = peerIRI
+	}
+
+	return peerIRIs, nil
+}
+
 func getAnchorCredentialParameters(cmd *cobra.Command) (*anchorCredentialParams, error) {
 	domain, err := cmdutils.GetUserSetVarFromString(cmd, anchorCredentialDomainFlagName, anchorCredentialDomainEnvKey, false)
 	if err != nil {
@@ -726,6 +1137,48 @@ func getAnchorCredentialParameters(cmd *cobra.Command) (*anchorCredentialParams,
 	}, nil
 }
 
+func getS3CASParameters(cmd *cobra.Command, required bool) (*s3cas.Config, error) {
+	endpoint, err := cmdutils.GetUserSetVarFromString(cmd, casS3EndpointFlagName, casS3EndpointEnvKey, !required)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket, err := cmdutils.GetUserSetVarFromString(cmd, casS3BucketFlagName, casS3BucketEnvKey, !required)
+	if err != nil {
+		return nil, err
+	}
+
+	region, err := cmdutils.GetUserSetVarFromString(cmd, casS3RegionFlagName, casS3RegionEnvKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPrefix, err := cmdutils.GetUserSetVarFromString(cmd, casS3KeyPrefixFlagName, casS3KeyPrefixEnvKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	accessKeyID, err := cmdutils.GetUserSetVarFromString(cmd, casS3AccessKeyIDFlagName, casS3AccessKeyIDEnvKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	secretAccessKey, err := cmdutils.GetUserSetVarFromString(cmd, casS3SecretAccessKeyFlagName,
+		casS3SecretAccessKeyEnvKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3cas.Config{
+		Endpoint:        endpoint,
+		Region:          region,
+		Bucket:          bucket,
+		KeyPrefix:       keyPrefix,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+	}, nil
+}
+
 func getDBParameters(cmd *cobra.Command, kmOptional bool) (*dbParameters, error) {
 	databaseType, err := cmdutils.GetUserSetVarFromString(cmd, databaseTypeFlagName,
 		databaseTypeEnvKey, false)
@@ -850,6 +1303,72 @@ func getAuthTokens(cmd *cobra.Command) (map[string]string, error) {
 	return authTokens, nil
 }
 
+func getDynamicAuthTokens(cmd *cobra.Command, authTokenDefs []*auth.TokenDef) ([]string, error) {
+	names, err := cmdutils.GetUserSetVarFromArrayString(cmd, dynamicAuthTokensFlagName, dynamicAuthTokensEnvKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	names = filterEmptyTokens(names)
+
+	definedNames := make(map[string]struct{})
+
+	for _, name := range getAuthTokenNames(authTokenDefs) {
+		definedNames[name] = struct{}{}
+	}
+
+	for _, name := range names {
+		if _, ok := definedNames[name]; !ok {
+			return nil, fmt.Errorf("token name [%s] is not used in %s", name, authTokensDefFlagName)
+		}
+	}
+
+	return names, nil
+}
+
+func getJWTAuthConfig(cmd *cobra.Command) (*jwtauth.Config, error) {
+	jwksURL := cmdutils.GetUserSetOptionalVarFromString(cmd, jwtJWKSURLFlagName, jwtJWKSURLEnvKey)
+	if jwksURL == "" {
+		return nil, nil
+	}
+
+	issuer, err := cmdutils.GetUserSetVarFromString(cmd, jwtIssuerFlagName, jwtIssuerEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	audience, err := cmdutils.GetUserSetVarFromString(cmd, jwtAudienceFlagName, jwtAudienceEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	scopesStr, err := cmdutils.GetUserSetVarFromArrayString(cmd, jwtScopesFlagName, jwtScopesEnvKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	scopeMapping := make(map[string][]string)
+
+	for _, keyValStr := range scopesStr {
+		keyVal := strings.Split(keyValStr, "=")
+
+		if len(keyVal) != 2 || keyVal[0] == "" {
+			return nil, fmt.Errorf("invalid JWT scope mapping [%s]", keyValStr)
+		}
+
+		scopeMapping[keyVal[0]] = append(scopeMapping[keyVal[0]], filterEmptyTokens(strings.Split(keyVal[1], "&"))...)
+	}
+
+	logger.Debugf("JWT scope mapping: %s", scopeMapping)
+
+	return &jwtauth.Config{
+		JWKSURL:      jwksURL,
+		Issuer:       issuer,
+		Audience:     audience,
+		ScopeMapping: scopeMapping,
+	}, nil
+}
+
 func getActivityPubPageSize(cmd *cobra.Command) (int, error) {
 	activityPubPageSizeStr, err := cmdutils.GetUserSetVarFromString(cmd, activityPubPageSizeFlagName, activityPubPageSizeEnvKey, true)
 	if err != nil {
@@ -909,6 +1428,53 @@ func getIPFSTimeout(cmd *cobra.Command) (time.Duration, error) {
 	return ipfsTimeout, nil
 }
 
+func getDuration(cmd *cobra.Command, flagName, envKey string, defaultDuration time.Duration) (time.Duration, error) {
+	durationStr, err := cmdutils.GetUserSetVarFromString(cmd, flagName, envKey, true)
+	if err != nil {
+		return 0, err
+	}
+
+	if durationStr == "" {
+		return defaultDuration, nil
+	}
+
+	duration, err := time.ParseDuration(durationStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value [%s]: %w", durationStr, err)
+	}
+
+	return duration, nil
+}
+
+func getCASScrubRepairDomains(cmd *cobra.Command) ([]string, error) {
+	values := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, casScrubRepairDomainsFlagName,
+		casScrubRepairDomainsEnvKey)
+
+	var domains []string
+
+	for _, value := range values {
+		domain := value
+
+		// Accept a URL (e.g. https://orb.domain2.com) in which case the host is used.
+		if strings.Contains(value, "://") {
+			u, err := url.Parse(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid domain [%s]: %w", value, err)
+			}
+
+			domain = u.Host
+		}
+
+		if domain == "" || strings.ContainsAny(domain, "/?#") {
+			return nil, fmt.Errorf("invalid domain [%s]", value)
+		}
+
+		domains = append(domains, domain)
+	}
+
+	return domains, nil
+}
+
 func getMQParameters(cmd *cobra.Command) (mqURL string, mqOpPoolSize int, mqMaxConnectionSubscriptions int, err error) {
 	mqURL, err = cmdutils.GetUserSetVarFromString(cmd, mqURLFlagName, mqURLEnvKey, true)
 	if err != nil {
@@ -956,6 +1522,8 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().String(kmsEndpointFlagName, "", kmsEndpointFlagUsage)
 	startCmd.Flags().String(keyIDFlagName, "", keyIDFlagUsage)
 	startCmd.Flags().String(privateKeyFlagName, "", privateKeyFlagUsage)
+	startCmd.Flags().String(remoteSignerURLFlagName, "", remoteSignerURLFlagUsage)
+	startCmd.Flags().String(remoteSignerAuthTokenFlagName, "", remoteSignerAuthTokenFlagUsage)
 	startCmd.Flags().String(secretLockKeyPathFlagName, "", secretLockKeyPathFlagUsage)
 	startCmd.Flags().StringP(externalEndpointFlagName, externalEndpointFlagShorthand, "", externalEndpointFlagUsage)
 	startCmd.Flags().String(discoveryDomainFlagName, "", discoveryDomainFlagUsage)
@@ -963,11 +1531,28 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(tlsKeyFlagName, tlsKeyFlagShorthand, "", tlsKeyFlagUsage)
 	startCmd.Flags().StringP(batchWriterTimeoutFlagName, batchWriterTimeoutFlagShorthand, "", batchWriterTimeoutFlagUsage)
 	startCmd.Flags().StringP(maxWitnessDelayFlagName, maxWitnessDelayFlagShorthand, "", maxWitnessDelayFlagUsage)
+	startCmd.Flags().StringArrayP(witnessTimeoutActionsFlagName, "", []string{}, witnessTimeoutActionsFlagUsage)
+	startCmd.Flags().StringArrayP(witnessEscalationWitnessesFlagName, "", []string{}, witnessEscalationWitnessesFlagUsage)
+	startCmd.Flags().StringArrayP(anchorSyncPeersFlagName, "", []string{}, anchorSyncPeersFlagUsage)
+	startCmd.Flags().String(followAuthModeFlagName, "", followAuthModeFlagUsage)
+	startCmd.Flags().String(inviteWitnessAuthModeFlagName, "", inviteWitnessAuthModeFlagUsage)
+	startCmd.Flags().StringArrayP(actorAuthAllowedDomainsFlagName, "", []string{}, actorAuthAllowedDomainsFlagUsage)
+	startCmd.Flags().StringArrayP(actorAuthDeniedDomainsFlagName, "", []string{}, actorAuthDeniedDomainsFlagUsage)
+	startCmd.Flags().String(actorAuthRequireHostMetaFlagName, "", actorAuthRequireHostMetaFlagUsage)
+	startCmd.Flags().StringArrayP(protocolGenesisTimesFlagName, "", []string{}, protocolGenesisTimesFlagUsage)
+	startCmd.Flags().String(protocolConfigFileFlagName, "", protocolConfigFileFlagUsage)
 	startCmd.Flags().StringP(signWithLocalWitnessFlagName, signWithLocalWitnessFlagShorthand, "", signWithLocalWitnessFlagUsage)
 	startCmd.Flags().StringP(httpSignaturesEnabledFlagName, httpSignaturesEnabledShorthand, "", httpSignaturesEnabledUsage)
 	startCmd.Flags().String(enableDidDiscoveryFlagName, "", enableDidDiscoveryUsage)
 	startCmd.Flags().String(enableCreateDocumentStoreFlagName, "", enableCreateDocumentStoreUsage)
 	startCmd.Flags().StringP(casTypeFlagName, casTypeFlagShorthand, "", casTypeFlagUsage)
+	startCmd.Flags().String(casDiskPathFlagName, "", casDiskPathFlagUsage)
+	startCmd.Flags().String(casS3EndpointFlagName, "", casS3EndpointFlagUsage)
+	startCmd.Flags().String(casS3RegionFlagName, "", casS3RegionFlagUsage)
+	startCmd.Flags().String(casS3BucketFlagName, "", casS3BucketFlagUsage)
+	startCmd.Flags().String(casS3KeyPrefixFlagName, "", casS3KeyPrefixFlagUsage)
+	startCmd.Flags().String(casS3AccessKeyIDFlagName, "", casS3AccessKeyIDFlagUsage)
+	startCmd.Flags().String(casS3SecretAccessKeyFlagName, "", casS3SecretAccessKeyFlagUsage)
 	startCmd.Flags().StringP(ipfsURLFlagName, ipfsURLFlagShorthand, "", ipfsURLFlagUsage)
 	startCmd.Flags().StringP(localCASReplicateInIPFSFlagName, "", "false", localCASReplicateInIPFSFlagUsage)
 	startCmd.Flags().StringP(mqURLFlagName, mqURLFlagShorthand, "", mqURLFlagUsage)
@@ -995,8 +1580,18 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(discoveryMinimumResolversFlagName, "", "", discoveryMinimumResolversFlagUsage)
 	startCmd.Flags().StringArrayP(authTokensDefFlagName, authTokensDefFlagShorthand, nil, authTokensDefFlagUsage)
 	startCmd.Flags().StringArrayP(authTokensFlagName, authTokensFlagShorthand, nil, authTokensFlagUsage)
+	startCmd.Flags().StringArray(dynamicAuthTokensFlagName, nil, dynamicAuthTokensFlagUsage)
+	startCmd.Flags().String(jwtJWKSURLFlagName, "", jwtJWKSURLFlagUsage)
+	startCmd.Flags().String(jwtIssuerFlagName, "", jwtIssuerFlagUsage)
+	startCmd.Flags().String(jwtAudienceFlagName, "", jwtAudienceFlagUsage)
+	startCmd.Flags().StringArray(jwtScopesFlagName, nil, jwtScopesFlagUsage)
 	startCmd.Flags().StringP(activityPubPageSizeFlagName, activityPubPageSizeFlagShorthand, "", activityPubPageSizeFlagUsage)
 	startCmd.Flags().String(devModeEnabledFlagName, "false", devModeEnabledUsage)
 	startCmd.Flags().StringP(nodeInfoRefreshIntervalFlagName, nodeInfoRefreshIntervalFlagShorthand, "", nodeInfoRefreshIntervalFlagUsage)
 	startCmd.Flags().StringP(ipfsTimeoutFlagName, ipfsTimeoutFlagShorthand, "", ipfsTimeoutFlagUsage)
+	startCmd.Flags().String(casGCGracePeriodFlagName, "", casGCGracePeriodFlagUsage)
+	startCmd.Flags().String(casGCIntervalFlagName, "", casGCIntervalFlagUsage)
+	startCmd.Flags().String(casScrubIntervalFlagName, "", casScrubIntervalFlagUsage)
+	startCmd.Flags().StringArray(casScrubRepairDomainsFlagName, []string{}, casScrubRepairDomainsFlagUsage)
+	startCmd.Flags().String(keyRotationOverlapPeriodFlagName, "", keyRotationOverlapPeriodFlagUsage)
 }
@@ -10,10 +10,8 @@ import (
 	"context"
 	"crypto/ed25519"
 	"crypto/tls"
-	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
-	"encoding/pem"
 	"errors"
 	"fmt"
 	"net/http"
@@ -66,23 +64,36 @@ import (
 	"github.com/trustbloc/orb/pkg/activitypub/httpsig"
 	aphandler "github.com/trustbloc/orb/pkg/activitypub/resthandler"
 	apservice "github.com/trustbloc/orb/pkg/activitypub/service"
+	"github.com/trustbloc/orb/pkg/activitypub/service/actorauth"
+	"github.com/trustbloc/orb/pkg/activitypub/service/deadletter"
+	deadletterhandler "github.com/trustbloc/orb/pkg/activitypub/service/deadletter/resthandler"
 	"github.com/trustbloc/orb/pkg/activitypub/service/monitoring"
 	apspi "github.com/trustbloc/orb/pkg/activitypub/service/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/service/vct"
 	apariesstore "github.com/trustbloc/orb/pkg/activitypub/store/ariesstore"
 	apmemstore "github.com/trustbloc/orb/pkg/activitypub/store/memstore"
 	activitypubspi "github.com/trustbloc/orb/pkg/activitypub/store/spi"
-	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 	"github.com/trustbloc/orb/pkg/anchor/builder"
 	"github.com/trustbloc/orb/pkg/anchor/graph"
 	"github.com/trustbloc/orb/pkg/anchor/handler/credential"
 	"github.com/trustbloc/orb/pkg/anchor/handler/proof"
+	"github.com/trustbloc/orb/pkg/anchor/handler/undo"
 	"github.com/trustbloc/orb/pkg/anchor/policy"
 	policyhandler "github.com/trustbloc/orb/pkg/anchor/policy/resthandler"
+	anchorhandler "github.com/trustbloc/orb/pkg/anchor/resthandler"
+	"github.com/trustbloc/orb/pkg/anchor/resync"
 	"github.com/trustbloc/orb/pkg/anchor/writer"
+	"github.com/trustbloc/orb/pkg/apitoken"
+	apitokenhandler "github.com/trustbloc/orb/pkg/apitoken/resthandler"
 	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
+	filesystemcas "github.com/trustbloc/orb/pkg/cas/filesystem"
+	casgc "github.com/trustbloc/orb/pkg/cas/gc"
+	casgchandler "github.com/trustbloc/orb/pkg/cas/gc/resthandler"
 	ipfscas "github.com/trustbloc/orb/pkg/cas/ipfs"
 	"github.com/trustbloc/orb/pkg/cas/resolver"
+	s3cas "github.com/trustbloc/orb/pkg/cas/s3"
+	casscrub "github.com/trustbloc/orb/pkg/cas/scrub"
+	casscrubhandler "github.com/trustbloc/orb/pkg/cas/scrub/resthandler"
 	"github.com/trustbloc/orb/pkg/config"
 	sidetreecontext "github.com/trustbloc/orb/pkg/context"
 	"github.com/trustbloc/orb/pkg/context/common"
@@ -93,23 +104,35 @@ import (
 	discoveryclient "github.com/trustbloc/orb/pkg/discovery/endpoint/client"
 	discoveryrest "github.com/trustbloc/orb/pkg/discovery/endpoint/restapi"
 	"github.com/trustbloc/orb/pkg/document/resolvehandler"
+	docresthandler "github.com/trustbloc/orb/pkg/document/resthandler"
 	"github.com/trustbloc/orb/pkg/document/updatehandler"
+	"github.com/trustbloc/orb/pkg/document/versionresolver"
 	"github.com/trustbloc/orb/pkg/httpserver"
 	"github.com/trustbloc/orb/pkg/httpserver/auth"
+	"github.com/trustbloc/orb/pkg/httpserver/auth/jwtauth"
+	"github.com/trustbloc/orb/pkg/keyrotation"
+	keyrotationhandler "github.com/trustbloc/orb/pkg/keyrotation/resthandler"
 	"github.com/trustbloc/orb/pkg/ldcontextrest"
 	"github.com/trustbloc/orb/pkg/metrics"
 	"github.com/trustbloc/orb/pkg/nodeinfo"
 	"github.com/trustbloc/orb/pkg/observer"
+	"github.com/trustbloc/orb/pkg/orbclient"
+	versioncommon "github.com/trustbloc/orb/pkg/protocolversion/common"
 	"github.com/trustbloc/orb/pkg/protocolversion/factoryregistry"
+	"github.com/trustbloc/orb/pkg/protocolversion/fileconfig"
 	"github.com/trustbloc/orb/pkg/pubsub/amqp"
 	"github.com/trustbloc/orb/pkg/pubsub/mempubsub"
 	"github.com/trustbloc/orb/pkg/pubsub/spi"
+	"github.com/trustbloc/orb/pkg/remotesigner"
 	"github.com/trustbloc/orb/pkg/resolver/resource"
 	"github.com/trustbloc/orb/pkg/resolver/resource/registry"
 	"github.com/trustbloc/orb/pkg/resolver/resource/registry/didanchorinfo"
 	casstore "github.com/trustbloc/orb/pkg/store/cas"
+	casrefstore "github.com/trustbloc/orb/pkg/store/casref"
+	deadletterstore "github.com/trustbloc/orb/pkg/store/deadletter"
 	didanchorstore "github.com/trustbloc/orb/pkg/store/didanchor"
 	"github.com/trustbloc/orb/pkg/store/operation"
+	"github.com/trustbloc/orb/pkg/store/pendinganchor"
 	"github.com/trustbloc/orb/pkg/store/vcstatus"
 	vcstore "github.com/trustbloc/orb/pkg/store/verifiable"
 	proofstore "github.com/trustbloc/orb/pkg/store/witness"
@@ -150,8 +173,9 @@ const (
 	kmsKeyType             = kms.ED25519Type
 	verificationMethodType = "Ed25519VerificationKey2018"
 
-	webKeyStoreKey = "web-key-store"
-	kidKey         = "kid"
+	webKeyStoreKey         = "web-key-store"
+	kidKey                 = "kid"
+	anchorCredentialKIDKey = "anchor-credential-kid"
 )
 
 type pubSub interface {
@@ -260,7 +284,7 @@ func createKMSAndCrypto(parameters *orbParameters, client *http.Client,
 	return km, cr, nil
 }
 
-func createKID(km kms.KeyManager, parameters *orbParameters, cfg storage.Store) error {
+func createKID(km keyManager, parameters *orbParameters, cfg storage.Store) error {
 	return getOrInit(cfg, kidKey, &parameters.keyID, func() (interface{}, error) {
 		keyID, _, err := km.Create(kmsKeyType)
 
@@ -268,6 +292,39 @@ func createKID(km kms.KeyManager, parameters *orbParameters, cfg storage.Store)
 	}, parameters.syncTimeout)
 }
 
+// createAssertionKey returns the key which is used to sign anchor credentials with a signature suite that
+// requires a key type other than the type of the signing key. The key is created on first use.
+func createAssertionKey(km keyManager, parameters *orbParameters, cfg storage.Store,
+	keyType kms.KeyType) (*discoveryrest.AssertionKey, error) {
+	verificationMethodType, err := vcsigner.VerificationMethodType(parameters.anchorCredentialParams.signatureSuite)
+	if err != nil {
+		return nil, err
+	}
+
+	var keyID string
+
+	// The key ID is stored per key type so that a different key is used if the signature suite is changed.
+	err = getOrInit(cfg, fmt.Sprintf("%s-%s", anchorCredentialKIDKey, keyType), &keyID, func() (interface{}, error) {
+		kid, _, e := km.Create(keyType)
+
+		return kid, e
+	}, parameters.syncTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("get or init: %w", err)
+	}
+
+	pubKey, err := km.ExportPubKeyBytes(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("export public key: %w", err)
+	}
+
+	return &discoveryrest.AssertionKey{
+		ID:        keyID,
+		Type:      verificationMethodType,
+		PublicKey: pubKey,
+	}, nil
+}
+
 func importPrivateKey(km kms.KeyManager, parameters *orbParameters, cfg storage.Store) error {
 	return getOrInit(cfg, kidKey, &parameters.keyID, func() (interface{}, error) {
 		keyBytes, err := base64.RawStdEncoding.DecodeString(parameters.privateKeyBase64)
@@ -315,6 +372,21 @@ func startOrbServices(parameters *orbParameters) error {
 		return err
 	}
 
+	// Signing keys are managed by the KMS unless a remote signer is configured, in which case the keys are
+	// created in (and never leave) the remote signing service.
+	var keys keyManager = km
+
+	var remoteSigner *remotesigner.Client
+
+	if parameters.remoteSignerURL != "" {
+		remoteSigner = remotesigner.New(parameters.remoteSignerURL,
+			remotesigner.WithHTTPClient(httpClient),
+			remotesigner.WithAuthToken(parameters.remoteSignerAuthToken),
+		)
+
+		keys = remoteSigner
+	}
+
 	casIRI := mustParseURL(parameters.externalEndpoint, casPath)
 
 	var coreCASClient extendedcasclient.Client
@@ -347,8 +419,45 @@ func startOrbServices(parameters *orbParameters) error {
 			}
 		}
 
+	case strings.EqualFold(parameters.casType, "disk"):
+		logger.Infof("Initializing Orb CAS with directory [%s].", parameters.casDiskPath)
+
+		casOpts := []filesystemcas.Option{
+			filesystemcas.WithCacheSize(defaultCasCacheSize),
+			filesystemcas.WithCIDFormatOptions(extendedcasclient.WithCIDVersion(parameters.cidVersion)),
+		}
+
+		if parameters.localCASReplicateInIPFSEnabled {
+			logger.Infof("Disk CAS writes will be replicated in IPFS.")
+
+			casOpts = append(casOpts, filesystemcas.WithIPFSReplication(
+				ipfscas.New(parameters.ipfsURL, parameters.ipfsTimeout, defaultCasCacheSize, metrics.Get(),
+					extendedcasclient.WithCIDVersion(parameters.cidVersion))),
+			)
+		}
+
+		var err error
+
+		coreCASClient, err = filesystemcas.New(parameters.casDiskPath, casIRI.String(), metrics.Get(), casOpts...)
+		if err != nil {
+			return err
+		}
+
+	case strings.EqualFold(parameters.casType, "s3"):
+		logger.Infof("Initializing Orb CAS with S3 bucket [%s] at [%s].",
+			parameters.casS3Config.Bucket, parameters.casS3Config.Endpoint)
+
+		var err error
+
+		coreCASClient, err = s3cas.New(parameters.casS3Config, casIRI.String(), metrics.Get(),
+			s3cas.WithCacheSize(defaultCasCacheSize),
+			s3cas.WithCIDFormatOptions(extendedcasclient.WithCIDVersion(parameters.cidVersion)))
+		if err != nil {
+			return err
+		}
+
 	default:
-		return fmt.Errorf("%s is not a valid CAS type. It must be either local or ipfs", parameters.casType)
+		return fmt.Errorf("%s is not a valid CAS type. It must be one of local, ipfs, disk or s3", parameters.casType)
 	}
 
 	didAnchors, err := didanchorstore.New(storeProviders.provider)
@@ -398,7 +507,7 @@ func startOrbServices(parameters *orbParameters) error {
 	)
 
 	if parameters.keyID == "" {
-		if err = createKID(km, parameters, configStore); err != nil {
+		if err = createKID(keys, parameters, configStore); err != nil {
 			return fmt.Errorf("create kid: %w", err)
 		}
 	}
@@ -409,10 +518,26 @@ func startOrbServices(parameters *orbParameters) error {
 		}
 	}
 
+	signingKeys, err := keyrotation.New(configStore, keys, kmsKeyType, parameters.keyID,
+		keyrotation.WithOverlapPeriod(parameters.keyRotationOverlapPeriod))
+	if err != nil {
+		return fmt.Errorf("create signing key manager: %w", err)
+	}
+
+	apServiceIRI := mustParseURL(parameters.externalEndpoint, activityPubServicesPath)
+
 	apServicePublicKeyIRI := mustParseURL(parameters.externalEndpoint,
 		fmt.Sprintf("%s/keys/%s", activityPubServicesPath, aphandler.MainKeyID))
 
-	apGetSigner, apPostSigner := getActivityPubSigners(parameters, km, cr)
+	apPublicKeys := keyrotation.NewActivityPubKeys(signingKeys, apServiceIRI, parameters.keyID, aphandler.MainKeyID)
+
+	var apSignerOpts []httpsig.SignerOpt
+
+	if remoteSigner != nil {
+		apSignerOpts = append(apSignerOpts, httpsig.WithKeySigner(remoteSigner))
+	}
+
+	apGetSigner, apPostSigner := getActivityPubSigners(parameters, km, cr, apPublicKeys, apSignerOpts...)
 
 	t := transport.New(httpClient, apServicePublicKeyIRI, apGetSigner, apPostSigner)
 
@@ -440,7 +565,12 @@ func startOrbServices(parameters *orbParameters) error {
 	anchorGraph := graph.New(graphProviders)
 
 	// get protocol client provider
-	pcp, err := getProtocolClientProvider(parameters, coreCASClient, casResolver, opStore, anchorGraph)
+	protocolCfg, err := loadProtocolConfig(parameters)
+	if err != nil {
+		return err
+	}
+
+	pcp, protocolVersions, err := getProtocolClientProvider(parameters, protocolCfg, coreCASClient, casResolver, opStore, anchorGraph)
 	if err != nil {
 		return fmt.Errorf("failed to create protocol client provider: %s", err.Error())
 	}
@@ -462,10 +592,47 @@ func startOrbServices(parameters *orbParameters) error {
 	}
 
 	signingProviders := &vcsigner.Providers{
-		KeyManager: km,
-		Crypto:     cr,
-		DocLoader:  orbDocumentLoader,
-		Metrics:    metrics.Get(),
+		KeyManager:    km,
+		Crypto:        cr,
+		DocLoader:     orbDocumentLoader,
+		Metrics:       metrics.Get(),
+		KeyIDProvider: signingKeys,
+	}
+
+	if remoteSigner != nil {
+		signingProviders.KeySigner = remoteSigner
+	}
+
+	var assertionKeys []*discoveryrest.AssertionKey
+
+	anchorCredentialKeyType, err := vcsigner.KeyType(parameters.anchorCredentialParams.signatureSuite)
+	if err != nil {
+		return fmt.Errorf("anchor credential signature suite: %w", err)
+	}
+
+	// The signing key is also used for HTTP signatures and must therefore remain an Ed25519 key. A separate
+	// (non-rotating) key is created for signature suites which require a different key type.
+	if anchorCredentialKeyType != kmsKeyType {
+		assertionKey, e := createAssertionKey(keys, parameters, configStore, anchorCredentialKeyType)
+		if e != nil {
+			return fmt.Errorf("create anchor credential key: %w", e)
+		}
+
+		signingParams.VerificationMethod = "did:web:" + u.Host + "#" + assertionKey.ID
+		signingProviders.KeyIDProvider = nil
+
+		assertionKeys = append(assertionKeys, assertionKey)
+	}
+
+	signingKeyVMType := verificationMethodType
+
+	if parameters.anchorCredentialParams.signatureSuite == vcsigner.Ed25519Signature2020 {
+		// The signing key signs the anchor credentials so it's published under the verification method
+		// type of the suite.
+		signingKeyVMType, err = vcsigner.VerificationMethodType(vcsigner.Ed25519Signature2020)
+		if err != nil {
+			return fmt.Errorf("anchor credential signature suite: %w", err)
+		}
 	}
 
 	vcSigner, err := vcsigner.New(signingProviders, signingParams)
@@ -498,6 +665,11 @@ func startOrbServices(parameters *orbParameters) error {
 		return fmt.Errorf("failed to create vc status store: %s", err.Error())
 	}
 
+	pendingAnchorStore, err := pendinganchor.New(storeProviders.provider)
+	if err != nil {
+		return fmt.Errorf("failed to create pending anchor store: %s", err.Error())
+	}
+
 	opProcessor := processor.New(parameters.didNamespace, opStore, pc)
 
 	didAnchoringInfoProvider := didanchorinfo.New(parameters.didNamespace, didAnchors, opProcessor)
@@ -506,8 +678,6 @@ func startOrbServices(parameters *orbParameters) error {
 	resourceRegistry := registry.New(registry.WithResourceInfoProvider(didAnchoringInfoProvider))
 	logger.Debugf("started resource registry: %+v", resourceRegistry)
 
-	apServiceIRI := mustParseURL(parameters.externalEndpoint, activityPubServicesPath)
-
 	var pubSub pubSub
 
 	if parameters.mqURL != "" {
@@ -531,16 +701,6 @@ func startOrbServices(parameters *orbParameters) error {
 		return err
 	}
 
-	pubKey, err := km.ExportPubKeyBytes(parameters.keyID)
-	if err != nil {
-		return fmt.Errorf("failed to export pub key: %w", err)
-	}
-
-	publicKey, err := getActivityPubPublicKey(pubKey, apServiceIRI, apServicePublicKeyIRI)
-	if err != nil {
-		return fmt.Errorf("get public key: %w", err)
-	}
-
 	// TODO: Pass config from startup params
 	apClient := client.New(client.Config{}, t)
 
@@ -592,31 +752,94 @@ func startOrbServices(parameters *orbParameters) error {
 		Metrics:                metrics.Get(),
 	}
 
-	o, err := observer.New(providers, observer.WithDiscoveryDomain(parameters.discoveryDomain))
+	casRefStore, err := casrefstore.New(storeProviders.provider)
+	if err != nil {
+		return fmt.Errorf("failed to create CAS reference store: %w", err)
+	}
+
+	o, err := observer.New(providers, observer.WithDiscoveryDomain(parameters.discoveryDomain),
+		observer.WithReferenceTracker(casgc.NewReferenceTracker(casResolver, casRefStore)))
 	if err != nil {
 		return fmt.Errorf("failed to create observer: %s", err.Error())
 	}
 
 	o.Start()
 
+	var casCollector *casgc.Collector
+
+	if contentStore, ok := coreCASClient.(casContentStore); ok {
+		casCollector = casgc.New(contentStore, casRefStore,
+			casgc.WithGracePeriod(parameters.casGCGracePeriod),
+			casgc.WithInterval(parameters.casGCInterval))
+	} else {
+		logger.Infof("CAS garbage collection is not supported for CAS type [%s].", parameters.casType)
+	}
+
+	var casScrubber *casscrub.Scrubber
+
+	if integrityStore, ok := coreCASClient.(casIntegrityStore); ok {
+		scrubOpts := []casscrub.Option{casscrub.WithInterval(parameters.casScrubInterval)}
+
+		if len(parameters.casScrubRepairDomains) > 0 || ipfsReader != nil {
+			scrubOpts = append(scrubOpts,
+				casscrub.WithRepair(casResolver, parameters.casScrubRepairDomains, ipfsReader != nil))
+		}
+
+		casScrubber = casscrub.New(integrityStore, metrics.Get(), scrubOpts...)
+	} else {
+		logger.Infof("CAS scrubbing is not supported for CAS type [%s].", parameters.casType)
+	}
+
 	resourceResolver := resource.New(httpClient, ipfsReader)
 
+	followerAuth, err := actorauth.New("follow", &actorauth.Config{
+		Mode:            actorauth.Mode(parameters.followAuthMode),
+		AllowedDomains:  parameters.actorAuthAllowedDomains,
+		DeniedDomains:   parameters.actorAuthDeniedDomains,
+		RequireHostMeta: parameters.actorAuthRequireHostMeta,
+	}, resourceResolver)
+	if err != nil {
+		return fmt.Errorf("failed to create follower authorizer: %s", err.Error())
+	}
+
+	inviteWitnessAuth, err := actorauth.New("invite-witness", &actorauth.Config{
+		Mode:            actorauth.Mode(parameters.inviteWitnessAuthMode),
+		AllowedDomains:  parameters.actorAuthAllowedDomains,
+		DeniedDomains:   parameters.actorAuthDeniedDomains,
+		RequireHostMeta: parameters.actorAuthRequireHostMeta,
+	}, resourceResolver)
+	if err != nil {
+		return fmt.Errorf("failed to create witness invitation authorizer: %s", err.Error())
+	}
+
+	deadLetterStore, err := deadletterstore.New(storeProviders.provider)
+	if err != nil {
+		return fmt.Errorf("failed to create dead-letter store: %s", err.Error())
+	}
+
+	deadLetterHandler := deadletter.New(deadLetterStore, metrics.Get())
+
 	activityPubService, err := apservice.New(apConfig,
 		apStore, t, apSigVerifier, pubSub, apClient, resourceResolver, metrics.Get(),
 		apspi.WithProofHandler(proofHandler),
 		apspi.WithWitness(witness),
+		apspi.WithUndoHandler(undo.New(witnessProofStore, proofHandler)),
 		apspi.WithAnchorCredentialHandler(credential.New(
 			o.Publisher(), casResolver, orbDocumentLoader, monitoringSvc, parameters.maxWitnessDelay,
 		)),
-		// TODO: Define the following ActivityPub handlers.
-		// apspi.WithWitnessInvitationAuth(inviteWitnessAuth),
-		// apspi.WithFollowerAuth(followerAuth),
-		// apspi.WithUndeliverableHandler(undeliverableHandler),
+		apspi.WithWitnessInvitationAuth(inviteWitnessAuth),
+		apspi.WithFollowerAuth(followerAuth),
+		apspi.WithUndeliverableHandler(deadLetterHandler),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create ActivityPub service: %s", err.Error())
 	}
 
+	opQueue, err := opqueue.New(opqueue.Config{PoolSize: parameters.opQueuePoolSize}, pubSub, metrics.Get())
+	if err != nil {
+		return fmt.Errorf("failed to create operation queue: %s", err.Error())
+	}
+
 	anchorWriterProviders := &writer.Providers{
 		AnchorGraph:   anchorGraph,
 		DidAnchors:    didAnchors,
@@ -631,6 +854,10 @@ func startOrbServices(parameters *orbParameters) error {
 		ActivityStore: apStore,
 		WitnessStore:  witnessProofStore,
 		WFClient:      wfClient,
+
+		PendingAnchorStore: pendingAnchorStore,
+		OpQueue:            opQueue,
+		ProtocolClient:     pc,
 	}
 
 	anchorWriter, err := writer.New(parameters.didNamespace,
@@ -640,16 +867,13 @@ func startOrbServices(parameters *orbParameters) error {
 		parameters.maxWitnessDelay,
 		parameters.signWithLocalWitness,
 		orbDocumentLoader, resourceResolver,
-		metrics.Get())
+		metrics.Get(),
+		writer.WithWitnessTimeoutActions(getWitnessTimeoutActions(parameters.witnessTimeoutActions)...),
+		writer.WithEscalationWitnesses(parameters.witnessEscalationWitnesses...))
 	if err != nil {
 		return fmt.Errorf("failed to create writer: %s", err.Error())
 	}
 
-	opQueue, err := opqueue.New(opqueue.Config{PoolSize: parameters.opQueuePoolSize}, pubSub, metrics.Get())
-	if err != nil {
-		return fmt.Errorf("failed to create operation queue: %s", err.Error())
-	}
-
 	// create new batch writer
 	batchWriter, err := batch.New(parameters.didNamespace,
 		sidetreecontext.New(pc, anchorWriter, opQueue),
@@ -674,9 +898,28 @@ func startOrbServices(parameters *orbParameters) error {
 		dochandler.WithLabel(unpublishedDIDLabel),
 	)
 
+	apiTokens, err := apitoken.New(storeProviders.provider,
+		apitoken.WithScopes(getAuthTokenNames(parameters.authTokenDefinitions)...))
+	if err != nil {
+		return fmt.Errorf("failed to create API token manager: %s", err.Error())
+	}
+
+	tokenResolvers := []auth.TokenResolver{apiTokens}
+
+	if parameters.jwtAuth != nil {
+		jwtVerifier, e := jwtauth.New(parameters.jwtAuth, jwtauth.WithHTTPClient(httpClient))
+		if e != nil {
+			return fmt.Errorf("failed to create JWT verifier: %s", e.Error())
+		}
+
+		tokenResolvers = append(tokenResolvers, jwtVerifier)
+	}
+
 	authCfg := auth.Config{
-		AuthTokensDef: parameters.authTokenDefinitions,
-		AuthTokens:    parameters.authTokens,
+		AuthTokensDef:  parameters.authTokenDefinitions,
+		AuthTokens:     parameters.authTokens,
+		TokenResolvers: tokenResolvers,
+		DynamicTokens:  parameters.dynamicAuthTokens,
 	}
 
 	apEndpointCfg := &aphandler.Config{
@@ -690,6 +933,7 @@ func startOrbServices(parameters *orbParameters) error {
 	var resolveHandlerOpts []resolvehandler.Option
 	resolveHandlerOpts = append(resolveHandlerOpts, resolvehandler.WithUnpublishedDIDLabel(unpublishedDIDLabel))
 	resolveHandlerOpts = append(resolveHandlerOpts, resolvehandler.WithEnableDIDDiscovery(parameters.didDiscoveryEnabled))
+	resolveHandlerOpts = append(resolveHandlerOpts, resolvehandler.WithDIDAnchors(didAnchors))
 
 	var updateHandlerOpts []updatehandler.Option
 
@@ -703,10 +947,24 @@ func startOrbServices(parameters *orbParameters) error {
 		updateHandlerOpts = append(updateHandlerOpts, updatehandler.WithCreateDocumentStore(store))
 	}
 
+	// The Orb client of the discovery client must use the same protocol versions as this server.
+	var orbClientOpts []orbclient.Option
+
+	if protocolCfg != nil {
+		for _, v := range protocolCfg.Versions {
+			orbClientOpts = append(orbClientOpts, orbclient.WithProtocolConfig(v.Version, v.Protocol))
+		}
+	}
+
+	for version, genesisTime := range parameters.protocolGenesisTimes {
+		orbClientOpts = append(orbClientOpts, orbclient.WithGenesisTime(version, genesisTime))
+	}
+
 	discoveryClient, err := discoveryclient.New(orbDocumentLoader,
 		&discoveryCAS{resolver: casResolver},
 		discoveryclient.WithNamespace(parameters.didNamespace),
 		discoveryclient.WithHTTPClient(httpClient),
+		discoveryclient.WithOrbClientOptions(orbClientOpts...),
 	)
 
 	didDiscovery := localdiscovery.New(parameters.didNamespace, o.Publisher(), discoveryClient)
@@ -724,9 +982,9 @@ func startOrbServices(parameters *orbParameters) error {
 
 	// create discovery rest api
 	endpointDiscoveryOp, err := discoveryrest.New(&discoveryrest.Config{
-		PubKey:                    pubKey,
-		VerificationMethodType:    verificationMethodType,
-		KID:                       parameters.keyID,
+		VerificationMethodType:    signingKeyVMType,
+		SigningKeys:               signingKeys,
+		AssertionKeys:             assertionKeys,
 		ResolutionPath:            baseResolvePath,
 		OperationPath:             baseUpdatePath,
 		WebCASPath:                casPath,
@@ -746,16 +1004,31 @@ func startOrbServices(parameters *orbParameters) error {
 		return fmt.Errorf("ldcontext rest: %w", err)
 	}
 
-	nodeInfoService := nodeinfo.NewService(apStore, apServiceIRI, parameters.nodeInfoRefreshInterval)
+	nodeInfoService := nodeinfo.NewService(apStore, apServiceIRI, parameters.nodeInfoRefreshInterval,
+		nodeinfo.WithProtocolVersions(protocolVersions))
+
+	anchorSyncService, err := resync.New(apServiceIRI, parameters.anchorSyncPeers, &resync.Providers{
+		ActivityPubClient: apClient,
+		CASResolver:       casResolver,
+		AnchorPublisher:   o.Publisher(),
+		ActivityStore:     apStore,
+		StoreProvider:     storeProviders.provider,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create anchor sync service: %w", err)
+	}
 
 	handlers := make([]restcommon.HTTPHandler, 0)
 
 	handlers = append(handlers,
 		auth.NewHandlerWrapper(authCfg, diddochandler.NewUpdateHandler(baseUpdatePath, orbDocUpdateHandler, pc)),
-		auth.NewHandlerWrapper(authCfg, diddochandler.NewResolveHandler(baseResolvePath, orbDocResolveHandler)),
+		auth.NewHandlerWrapper(authCfg, docresthandler.NewResolveHandler(baseResolvePath, orbDocResolveHandler,
+			versionresolver.New(parameters.didNamespace, pc, opStore))),
+		auth.NewHandlerWrapper(authCfg, docresthandler.NewBatchResolveHandler(baseResolvePath, orbDocResolveHandler,
+			docresthandler.DefaultMaxBatchSize)),
 		activityPubService.InboxHTTPHandler(),
-		aphandler.NewServices(apEndpointCfg, apStore, publicKey),
-		aphandler.NewPublicKeys(apEndpointCfg, apStore, publicKey),
+		aphandler.NewServices(apEndpointCfg, apStore, nil, aphandler.WithPublicKeyProvider(apPublicKeys)),
+		aphandler.NewPublicKeys(apEndpointCfg, apStore, nil, aphandler.WithPublicKeyProvider(apPublicKeys)),
 		aphandler.NewFollowers(apEndpointCfg, apStore, apSigVerifier),
 		aphandler.NewFollowing(apEndpointCfg, apStore, apSigVerifier),
 		aphandler.NewOutbox(apEndpointCfg, apStore, apSigVerifier),
@@ -766,14 +1039,46 @@ func startOrbServices(parameters *orbParameters) error {
 		aphandler.NewLikes(apEndpointCfg, apStore, apSigVerifier),
 		aphandler.NewShares(apEndpointCfg, apStore, apSigVerifier),
 		aphandler.NewPostOutbox(apEndpointCfg, activityPubService.Outbox(), apStore, apSigVerifier),
+		aphandler.NewPendingApproval(apEndpointCfg, activityPubService.PendingApprovals(), apStore, apSigVerifier),
+		aphandler.NewPendingApprovals(apEndpointCfg, apStore, apSigVerifier),
 		aphandler.NewActivity(apEndpointCfg, apStore, apSigVerifier),
 		webcas.New(apEndpointCfg, apStore, apSigVerifier, coreCASClient),
 		auth.NewHandlerWrapper(authCfg, policyhandler.New(configStore)),
+		auth.NewHandlerWrapper(authCfg, policyhandler.NewEvaluator(configStore, witnessProofStore)),
+		auth.NewHandlerWrapper(authCfg, anchorhandler.NewStatusHandler(&anchorhandler.Providers{
+			VCStatusStore:      vcStatusStore,
+			WitnessStore:       witnessProofStore,
+			VCStore:            vcStore,
+			AnchorGraph:        anchorGraph,
+			PendingAnchorStore: pendingAnchorStore,
+			DidAnchors:         didAnchors,
+			ConfigStore:        configStore,
+		})),
+		auth.NewHandlerWrapper(authCfg, docresthandler.NewHistoryHandler(opStore, anchorGraph)),
+		auth.NewHandlerWrapper(authCfg, deadletterhandler.NewList(deadLetterHandler), auth.WithAuthRequired()),
+		auth.NewHandlerWrapper(authCfg, deadletterhandler.NewGet(deadLetterHandler), auth.WithAuthRequired()),
+		auth.NewHandlerWrapper(authCfg, deadletterhandler.NewReplay(deadLetterHandler, activityPubService.Redeliverer()),
+			auth.WithAuthRequired()),
+		auth.NewHandlerWrapper(authCfg, deadletterhandler.NewPurge(deadLetterHandler), auth.WithAuthRequired()),
 		ctxRest,
 		auth.NewHandlerWrapper(authCfg, nodeinfo.NewHandler(nodeinfo.V2_0, nodeInfoService)),
 		auth.NewHandlerWrapper(authCfg, nodeinfo.NewHandler(nodeinfo.V2_1, nodeInfoService)),
+		auth.NewHandlerWrapper(authCfg, keyrotationhandler.NewRotate(signingKeys), auth.WithAuthRequired()),
+		auth.NewHandlerWrapper(authCfg, apitokenhandler.NewCreate(apiTokens), auth.WithAuthRequired()),
+		auth.NewHandlerWrapper(authCfg, apitokenhandler.NewList(apiTokens), auth.WithAuthRequired()),
+		auth.NewHandlerWrapper(authCfg, apitokenhandler.NewRevoke(apiTokens), auth.WithAuthRequired()),
 	)
 
+	if casCollector != nil {
+		handlers = append(handlers, auth.NewHandlerWrapper(authCfg, casgchandler.NewCollect(casCollector),
+			auth.WithAuthRequired()))
+	}
+
+	if casScrubber != nil {
+		handlers = append(handlers, auth.NewHandlerWrapper(authCfg, casscrubhandler.NewScrub(casScrubber),
+			auth.WithAuthRequired()))
+	}
+
 	handlers = append(handlers,
 		endpointDiscoveryOp.GetRESTHandlers()...)
 
@@ -793,6 +1098,18 @@ func startOrbServices(parameters *orbParameters) error {
 
 	nodeInfoService.Start()
 
+	if len(parameters.anchorSyncPeers) > 0 {
+		anchorSyncService.Start()
+	}
+
+	if casCollector != nil {
+		casCollector.Start()
+	}
+
+	if casScrubber != nil {
+		casScrubber.Start()
+	}
+
 	err = metricsHttpServer.Start()
 	if err != nil {
 		return fmt.Errorf("start metrics HTTP server at %s: %w", parameters.hostMetricsURL, err)
@@ -809,8 +1126,20 @@ func startOrbServices(parameters *orbParameters) error {
 
 	nodeInfoService.Stop()
 
+	anchorSyncService.Stop()
+
+	if casCollector != nil {
+		casCollector.Stop()
+	}
+
+	if casScrubber != nil {
+		casScrubber.Stop()
+	}
+
 	batchWriter.Stop()
 
+	anchorWriter.Stop()
+
 	o.Stop()
 
 	activityPubService.Stop()
@@ -824,31 +1153,76 @@ func startOrbServices(parameters *orbParameters) error {
 	return nil
 }
 
-func getProtocolClientProvider(parameters *orbParameters, casClient casapi.Client, casResolver common.CASResolver, opStore common.OperationStore, anchorGraph common.AnchorGraph) (*orbpcp.ClientProvider, error) {
-	versions := []string{"1.0"}
+// loadProtocolConfig loads the protocol versions from the protocol config file (or returns nil if there's no file).
+func loadProtocolConfig(parameters *orbParameters) (*fileconfig.Config, error) {
+	if parameters.protocolConfigFile == "" {
+		return nil, nil
+	}
+
+	return fileconfig.Load(parameters.protocolConfigFile)
+}
 
+func getProtocolClientProvider(parameters *orbParameters, protocolCfg *fileconfig.Config, casClient casapi.Client, casResolver common.CASResolver, opStore common.OperationStore, anchorGraph common.AnchorGraph) (*orbpcp.ClientProvider, []protocol.Version, error) {
 	sidetreeCfg := config.Sidetree{
 		MethodContext: parameters.methodContext,
 		EnableBase:    parameters.baseEnabled,
 		AnchorOrigins: parameters.allowedOrigins,
 	}
 
-	registry := factoryregistry.New()
+	var registryOpts []factoryregistry.Option
+
+	if protocolCfg != nil {
+		for _, v := range protocolCfg.Versions {
+			registryOpts = append(registryOpts, factoryregistry.WithProtocolConfig(v.Version, v.Protocol))
+		}
+	}
+
+	for version, genesisTime := range parameters.protocolGenesisTimes {
+		registryOpts = append(registryOpts, factoryregistry.WithGenesisTime(version, genesisTime))
+	}
+
+	registry := factoryregistry.New(registryOpts...)
+
+	supportedVersions := make(map[string]struct{})
+	for _, version := range registry.Versions() {
+		supportedVersions[version] = struct{}{}
+	}
+
+	for version := range parameters.protocolGenesisTimes {
+		if _, ok := supportedVersions[version]; !ok {
+			return nil, nil, fmt.Errorf("genesis time specified for unsupported protocol version [%s]", version)
+		}
+	}
+
+	genesisTimes := make(map[string]uint64)
 
 	var protocolVersions []protocol.Version
-	for _, version := range versions {
+	for _, version := range registry.Versions() {
 		pv, err := registry.CreateProtocolVersion(version, casClient, casResolver, opStore, anchorGraph, sidetreeCfg)
 		if err != nil {
-			return nil, fmt.Errorf("error creating protocol version [%s]: %s", version, err)
+			return nil, nil, fmt.Errorf("error creating protocol version [%s]: %s", version, err)
+		}
+
+		// The parameters of each version are advertised by NodeInfo so make sure that they're valid.
+		p := pv.Protocol()
+		if err := fileconfig.Validate(&p); err != nil {
+			return nil, nil, fmt.Errorf("invalid parameters for protocol version [%s]: %w", version, err)
 		}
 
 		protocolVersions = append(protocolVersions, pv)
+		genesisTimes[version] = p.GenesisTime
+	}
+
+	// The genesis times from the command line take precedence over those in the protocol config file
+	// so make sure that the resulting genesis times still increase with the version.
+	if err := versioncommon.ValidateGenesisTimes(genesisTimes); err != nil {
+		return nil, nil, fmt.Errorf("invalid protocol genesis times: %w", err)
 	}
 
 	pcp := orbpcp.New()
 	pcp.Add(parameters.didNamespace, orbpc.New(protocolVersions))
 
-	return pcp, nil
+	return pcp, protocolVersions, nil
 }
 
 func createActivityPubStore(parameters *orbParameters, serviceEndpoint string) (activitypubspi.Store, error) {
@@ -892,6 +1266,21 @@ func createActivityPubStore(parameters *orbParameters, serviceEndpoint string) (
 	return apStore, nil
 }
 
+// casContentStore is implemented by the CAS clients whose content may be enumerated and deleted
+// (and therefore garbage collected).
+type casContentStore interface {
+	ForEach(fn func(resourceHash string, created time.Time) error) error
+	Delete(resourceHash string) error
+}
+
+// casIntegrityStore is implemented by the CAS clients whose content may be enumerated and quarantined
+// (and therefore scrubbed).
+type casIntegrityStore interface {
+	ForEach(fn func(resourceHash string, created time.Time) error) error
+	ReadRaw(resourceHash string) ([]byte, error)
+	Quarantine(resourceHash string) error
+}
+
 type discoveryCAS struct {
 	resolver common.CASResolver
 }
@@ -932,7 +1321,7 @@ type storageProviders struct {
 	kmsSecretsProvider storage.Provider
 }
 
-//nolint: gocyclo
+// nolint: gocyclo
 func createStoreProviders(parameters *orbParameters) (*storageProviders, error) {
 	var edgeServiceProvs storageProviders
 
@@ -1070,22 +1459,29 @@ func mustParseURL(basePath, relativePath string) *url.URL {
 	return u
 }
 
-func getActivityPubPublicKey(pubKey []byte, apServiceIRI, apServicePublicKeyIRI *url.URL) (*vocab.PublicKeyType, error) {
-	pubDerKey, err := x509.MarshalPKIXPublicKey(ed25519.PublicKey(pubKey))
-	if err != nil {
-		return nil, fmt.Errorf("marshal pub key: %w", err)
-	}
+// keyManager creates signing keys and exports their public keys.
+type keyManager interface {
+	Create(kt kms.KeyType) (string, interface{}, error)
+	ExportPubKeyBytes(keyID string) ([]byte, error)
+}
 
-	pemBytes := pem.EncodeToMemory(&pem.Block{
-		Type:  "PUBLIC KEY",
-		Bytes: pubDerKey,
-	})
+// getAuthTokenNames returns the names of the tokens in the given definitions. These are the scopes
+// that may be granted to an issued API token.
+func getAuthTokenNames(defs []*auth.TokenDef) []string {
+	var names []string
 
-	return vocab.NewPublicKey(
-		vocab.WithID(apServicePublicKeyIRI),
-		vocab.WithOwner(apServiceIRI),
-		vocab.WithPublicKeyPem(string(pemBytes)),
-	), nil
+	added := make(map[string]struct{})
+
+	for _, def := range defs {
+		for _, name := range append(append([]string{}, def.ReadTokens...), def.WriteTokens...) {
+			if _, ok := added[name]; !ok {
+				added[name] = struct{}{}
+				names = append(names, name)
+			}
+		}
+	}
+
+	return names
 }
 
 type signer interface {
@@ -1096,11 +1492,13 @@ type signatureVerifier interface {
 	VerifyRequest(req *http.Request) (bool, *url.URL, error)
 }
 
-func getActivityPubSigners(parameters *orbParameters, km kms.KeyManager,
-	cr acrypto.Crypto) (getSigner signer, postSigner signer) {
+func getActivityPubSigners(parameters *orbParameters, km kms.KeyManager, cr acrypto.Crypto,
+	signingKeys *keyrotation.ActivityPubKeys, opts ...httpsig.SignerOpt) (getSigner signer, postSigner signer) {
 	if parameters.httpSignaturesEnabled {
-		getSigner = httpsig.NewSigner(httpsig.DefaultGetSignerConfig(), cr, km, parameters.keyID)
-		postSigner = httpsig.NewSigner(httpsig.DefaultPostSignerConfig(), cr, km, parameters.keyID)
+		opts = append(opts, httpsig.WithSigningKeyProvider(signingKeys))
+
+		getSigner = httpsig.NewSigner(httpsig.DefaultGetSignerConfig(), cr, km, parameters.keyID, opts...)
+		postSigner = httpsig.NewSigner(httpsig.DefaultPostSignerConfig(), cr, km, parameters.keyID, opts...)
 	} else {
 		getSigner = &transport.NoOpSigner{}
 		postSigner = &transport.NoOpSigner{}
@@ -1142,3 +1540,13 @@ func (p *ldStoreProvider) JSONLDContextStore() ldstore.ContextStore {
 func (p *ldStoreProvider) JSONLDRemoteProviderStore() ldstore.RemoteProviderStore {
 	return p.RemoteProviderStore
 }
+
+func getWitnessTimeoutActions(actions []string) []writer.TimeoutAction {
+	timeoutActions := make([]writer.TimeoutAction, len(actions))
+
+	for i, action := range actions {
+		timeoutActions[i] = writer.TimeoutAction(action)
+	}
+
+	return timeoutActions
+}
@@ -32,11 +32,13 @@ require (
 	github.com/prometheus/client_golang v1.11.0
 	github.com/rs/cors v1.7.0
 	github.com/sirupsen/logrus v1.7.0
+	github.com/square/go-jose/v3 v3.0.0-20200630053402-0a67ce9b0693
 	github.com/stretchr/testify v1.7.0
 	github.com/trustbloc/edge-core v0.1.7-0.20210812092729-6c61997fa9dd
 	github.com/trustbloc/sidetree-core-go v0.6.1-0.20210813104923-05c0f29c66ae
 	github.com/trustbloc/vct v0.1.3-0.20210812104204-d8ddd5781928
 	golang.org/x/net v0.0.0-20210525063256-abc453219eb5 // indirect
+	sigs.k8s.io/yaml v1.2.0
 )
 
 go 1.16
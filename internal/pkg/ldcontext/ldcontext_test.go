@@ -16,7 +16,8 @@ import (
 
 func TestMustGetAll(t *testing.T) {
 	res := ldcontext.MustGetAll()
-	require.Len(t, res, 2)
+	require.Len(t, res, 3)
 	require.Equal(t, "https://w3id.org/activityanchors/v1", res[0].URL)
 	require.Equal(t, "https://www.w3.org/ns/activitystreams", res[1].URL)
+	require.Equal(t, "https://w3id.org/security/suites/ed25519-2020/v1", res[2].URL)
 }
@@ -38,6 +38,25 @@ type ReferenceIterator interface {
 	TotalItems() int
 }
 
+// Order specifies the order in which the pages (and the items of each page) of a collection are traversed.
+type Order string
+
+const (
+	// Forward traverses the collection from the first page, following the 'next' links.
+	Forward Order = "forward"
+
+	// Reverse traverses the collection from the last page, following the 'prev' links. The items of each
+	// page are also returned in reverse order. For a collection that's sorted in descending order (such as
+	// an outbox) the oldest item is returned first.
+	Reverse Order = "reverse"
+)
+
+// ActivityIterator iterates over all of the activities in a result set.
+type ActivityIterator interface {
+	Next() (*vocab.ActivityType, error)
+	TotalItems() int
+}
+
 type httpTransport interface {
 	Get(ctx context.Context, req *transport.Request) (*http.Response, error)
 }
@@ -172,6 +191,32 @@ func (c *Client) GetReferences(iri *url.URL) (ReferenceIterator, error) {
 	return newIterator(items, firstPage, totalItems, c.get), nil
 }
 
+// GetActivities returns an iterator that reads all activities at the given IRI in the given order. The IRI
+// must resolve to an ActivityPub collection or ordered collection of activities (such as an outbox).
+func (c *Client) GetActivities(iri *url.URL, order Order) (ActivityIterator, error) {
+	respBytes, err := c.get(iri)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response from %s: %w", iri, err)
+	}
+
+	logger.Debugf("Got response from %s: %s", iri, respBytes)
+
+	firstPage, lastPage, totalItems, err := unmarshalCollection(respBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarsalling response from %s: %w", iri, err)
+	}
+
+	if order == Reverse {
+		if lastPage == nil && totalItems > 0 {
+			return nil, fmt.Errorf("collection %s has no last page so it can't be traversed in reverse order", iri)
+		}
+
+		return newActivityIterator(lastPage, totalItems, order, c.get), nil
+	}
+
+	return newActivityIterator(firstPage, totalItems, order, c.get), nil
+}
+
 func (c *Client) get(iri *url.URL) ([]byte, error) {
 	resp, err := c.Get(context.Background(), transport.NewRequest(iri,
 		transport.WithHeader(transport.AcceptHeader, transport.ActivityStreamsContentType)))
@@ -268,6 +313,117 @@ func (it *referenceIterator) getNextPage() error {
 	return nil
 }
 
+type activityIterator struct {
+	totalItems   int
+	currentItems []*vocab.ActivityType
+	currentIndex int
+	nextPage     *url.URL
+	order        Order
+	get          getFunc
+}
+
+func newActivityIterator(nextPage *url.URL, totalItems int, order Order, retrieve getFunc) *activityIterator {
+	return &activityIterator{
+		totalItems: totalItems,
+		nextPage:   nextPage,
+		order:      order,
+		get:        retrieve,
+	}
+}
+
+func (it *activityIterator) Next() (*vocab.ActivityType, error) {
+	// Pages may be empty so keep retrieving pages until an item is found or there are no more pages.
+	for it.currentIndex >= len(it.currentItems) {
+		err := it.getNextPage()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	item := it.currentItems[it.currentIndex]
+
+	it.currentIndex++
+
+	return item, nil
+}
+
+func (it *activityIterator) TotalItems() int {
+	return it.totalItems
+}
+
+func (it *activityIterator) getNextPage() error {
+	if it.nextPage == nil {
+		logger.Debugf("No more pages")
+
+		return ErrNotFound
+	}
+
+	logger.Debugf("Retrieving next page %s", it.nextPage)
+
+	respBytes, err := it.get(it.nextPage)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %w", it.nextPage, err)
+	}
+
+	logger.Debugf("Got response from %s: %s", it.nextPage, respBytes)
+
+	_, nextPage, prevPage, err := unmarshalPage(respBytes)
+	if err != nil {
+		return err
+	}
+
+	activities, err := unmarshalActivities(respBytes)
+	if err != nil {
+		return err
+	}
+
+	if it.order == Reverse {
+		nextPage = prevPage
+
+		for i, j := 0, len(activities)-1; i < j; i, j = i+1, j-1 {
+			activities[i], activities[j] = activities[j], activities[i]
+		}
+	}
+
+	logger.Debugf("Got page %s with %d activities. Next page: %s", it.nextPage, len(activities), nextPage)
+
+	it.currentItems = activities
+	it.currentIndex = 0
+	it.nextPage = nextPage
+
+	return nil
+}
+
+// unmarshalActivities returns the activities in the given collection page. The activities are unmarshalled
+// from the raw items since the object property retains only the common object fields for some activity types
+// (such as 'Create' and 'Announce'). Items that aren't activities (such as IRIs) are ignored.
+func unmarshalActivities(respBytes []byte) ([]*vocab.ActivityType, error) {
+	page := &struct {
+		Items        []json.RawMessage `json:"items,omitempty"`
+		OrderedItems []json.RawMessage `json:"orderedItems,omitempty"`
+	}{}
+
+	if err := json.Unmarshal(respBytes, page); err != nil {
+		return nil, fmt.Errorf("invalid collection page in response: %w", err)
+	}
+
+	var activities []*vocab.ActivityType
+
+	for _, item := range append(page.Items, page.OrderedItems...) {
+		activity := &vocab.ActivityType{}
+
+		if err := json.Unmarshal(item, activity); err != nil || activity.Type() == nil {
+			logger.Warnf("expecting activity item for collection but got %s", item)
+
+			continue
+		}
+
+		activities = append(activities, activity)
+	}
+
+	return activities, nil
+}
+
 func unmarshalReference(respBytes []byte) (items []*url.URL, nextPage *url.URL, totalCount int, err error) {
 	obj := &vocab.ObjectType{}
 
@@ -305,16 +461,66 @@ func unmarshalReference(respBytes []byte) (items []*url.URL, nextPage *url.URL,
 	}
 }
 
-func unmarshalCollectionPage(respBytes []byte) ([]*url.URL, *url.URL, error) {
+func unmarshalCollection(respBytes []byte) (firstPage, lastPage *url.URL, totalCount int, err error) {
 	obj := &vocab.ObjectType{}
 
 	if err := json.Unmarshal(respBytes, &obj); err != nil {
+		return nil, nil, 0, err
+	}
+
+	switch {
+	case obj.Type().Is(vocab.TypeCollection):
+		coll := &vocab.CollectionType{}
+		if err := json.Unmarshal(respBytes, coll); err != nil {
+			return nil, nil, 0, fmt.Errorf("invalid collection in response: %w", err)
+		}
+
+		return coll.First(), coll.Last(), coll.TotalItems(), nil
+
+	case obj.Type().Is(vocab.TypeOrderedCollection):
+		coll := &vocab.OrderedCollectionType{}
+		if err := json.Unmarshal(respBytes, coll); err != nil {
+			return nil, nil, 0, fmt.Errorf("invalid ordered collection in response: %w", err)
+		}
+
+		return coll.First(), coll.Last(), coll.TotalItems(), nil
+
+	default:
+		return nil, nil, 0, fmt.Errorf("expecting Collection or OrderedCollection in response payload")
+	}
+}
+
+func unmarshalCollectionPage(respBytes []byte) ([]*url.URL, *url.URL, error) {
+	items, next, _, err := unmarshalPage(respBytes)
+	if err != nil {
 		return nil, nil, err
 	}
 
+	var refs []*url.URL
+
+	for _, item := range items {
+		if item.IRI() != nil {
+			logger.Debugf("Adding %s to the recipient list", item.IRI())
+
+			refs = append(refs, item.IRI())
+		} else {
+			logger.Warnf("expecting IRI item for collection but got %s", item.Type())
+		}
+	}
+
+	return refs, next, nil
+}
+
+func unmarshalPage(respBytes []byte) ([]*vocab.ObjectProperty, *url.URL, *url.URL, error) {
+	obj := &vocab.ObjectType{}
+
+	if err := json.Unmarshal(respBytes, &obj); err != nil {
+		return nil, nil, nil, err
+	}
+
 	var items []*vocab.ObjectProperty
 
-	var next *url.URL
+	var next, prev *url.URL
 
 	switch {
 	case obj.Type().Is(vocab.TypeCollectionPage):
@@ -322,10 +528,11 @@ func unmarshalCollectionPage(respBytes []byte) ([]*url.URL, *url.URL, error) {
 
 		err := json.Unmarshal(respBytes, coll)
 		if err != nil {
-			return nil, nil, fmt.Errorf("invalid collection page in response: %w", err)
+			return nil, nil, nil, fmt.Errorf("invalid collection page in response: %w", err)
 		}
 
 		next = coll.Next()
+		prev = coll.Prev()
 		items = coll.Items()
 
 	case obj.Type().Is(vocab.TypeOrderedCollectionPage):
@@ -333,27 +540,16 @@ func unmarshalCollectionPage(respBytes []byte) ([]*url.URL, *url.URL, error) {
 
 		err := json.Unmarshal(respBytes, coll)
 		if err != nil {
-			return nil, nil, fmt.Errorf("invalid ordered collection page in response: %w", err)
+			return nil, nil, nil, fmt.Errorf("invalid ordered collection page in response: %w", err)
 		}
 
 		next = coll.Next()
+		prev = coll.Prev()
 		items = coll.Items()
 
 	default:
-		return nil, nil, fmt.Errorf("expecting CollectionPage or OrderedCollectionPage in response payload")
+		return nil, nil, nil, fmt.Errorf("expecting CollectionPage or OrderedCollectionPage in response payload")
 	}
 
-	var refs []*url.URL
-
-	for _, item := range items {
-		if item.IRI() != nil {
-			logger.Debugf("Adding %s to the recipient list", item.IRI())
-
-			refs = append(refs, item.IRI())
-		} else {
-			logger.Warnf("expecting IRI item for collection but got %s", item.Type())
-		}
-	}
-
-	return refs, next, nil
+	return items, next, prev, nil
 }
@@ -498,6 +498,242 @@ func TestClient_GetReferences(t *testing.T) {
 	})
 }
 
+func TestClient_GetActivities(t *testing.T) {
+	serviceIRI := testutil.MustParseURL("https://example.com/services/service1")
+	outboxIRI := testutil.NewMockID(serviceIRI, "/outbox")
+
+	first := testutil.NewMockID(outboxIRI, "?page=true")
+
+	activities := aptestutil.NewMockCreateActivities(3)
+
+	t.Run("Success", func(t *testing.T) {
+		orderedCollBytes, e := json.Marshal(aptestutil.NewMockOrderedCollection(outboxIRI, first, len(activities)))
+		require.NoError(t, e)
+
+		page1Bytes, e := json.Marshal(vocab.NewOrderedCollectionPage(
+			[]*vocab.ObjectProperty{
+				vocab.NewObjectProperty(vocab.WithActivity(activities[0])),
+				vocab.NewObjectProperty(vocab.WithActivity(activities[1])),
+				vocab.NewObjectProperty(vocab.WithIRI(serviceIRI)),
+			},
+			vocab.WithContext(vocab.ContextActivityStreams),
+			vocab.WithID(testutil.NewMockID(outboxIRI, "?page=0")),
+			vocab.WithPartOf(outboxIRI),
+			vocab.WithNext(testutil.NewMockID(outboxIRI, "?page=1")),
+			vocab.WithTotalItems(len(activities)),
+		))
+		require.NoError(t, e)
+
+		page2Bytes, e := json.Marshal(vocab.NewCollectionPage(
+			[]*vocab.ObjectProperty{
+				vocab.NewObjectProperty(vocab.WithActivity(activities[2])),
+			},
+			vocab.WithContext(vocab.ContextActivityStreams),
+			vocab.WithID(testutil.NewMockID(outboxIRI, "?page=1")),
+			vocab.WithPartOf(outboxIRI),
+			vocab.WithTotalItems(len(activities)),
+		))
+		require.NoError(t, e)
+
+		httpClient := &mocks.HTTPTransport{}
+
+		var results []*http.Response
+
+		for i, b := range [][]byte{orderedCollBytes, page1Bytes, page2Bytes} {
+			rw := httptest.NewRecorder()
+
+			_, e = rw.Write(b)
+			require.NoError(t, e)
+
+			result := rw.Result()
+
+			httpClient.GetReturnsOnCall(i, result, nil)
+
+			results = append(results, result)
+		}
+
+		c := New(Config{}, httpClient)
+		require.NotNil(t, t, c)
+
+		it, e := c.GetActivities(outboxIRI, Forward)
+		require.NoError(t, e)
+		require.NotNil(t, it)
+		require.Equal(t, len(activities), it.TotalItems())
+
+		items, e := ReadActivities(it, -1)
+		require.NoError(t, e)
+		require.Len(t, items, len(activities))
+		require.Equal(t, activities[0].ID().String(), items[0].ID().String())
+		require.Equal(t, activities[1].ID().String(), items[1].ID().String())
+		require.Equal(t, activities[2].ID().String(), items[2].ID().String())
+		require.NotNil(t, items[0].Object().AnchorCredentialReference())
+
+		for _, result := range results {
+			require.NoError(t, result.Body.Close())
+		}
+	})
+
+	t.Run("Success - reverse order", func(t *testing.T) {
+		page0 := testutil.NewMockID(outboxIRI, "?page=true&page-num=0")
+		page1 := testutil.NewMockID(outboxIRI, "?page=true&page-num=1")
+
+		// The outbox is sorted in descending order, so the last page contains the oldest activities.
+		orderedCollBytes, e := json.Marshal(vocab.NewOrderedCollection(nil,
+			vocab.WithContext(vocab.ContextActivityStreams),
+			vocab.WithID(outboxIRI),
+			vocab.WithFirst(page1),
+			vocab.WithLast(page0),
+			vocab.WithTotalItems(len(activities)),
+		))
+		require.NoError(t, e)
+
+		page0Bytes, e := json.Marshal(vocab.NewOrderedCollectionPage(
+			[]*vocab.ObjectProperty{
+				vocab.NewObjectProperty(vocab.WithActivity(activities[1])),
+				vocab.NewObjectProperty(vocab.WithActivity(activities[0])),
+			},
+			vocab.WithContext(vocab.ContextActivityStreams),
+			vocab.WithID(page0),
+			vocab.WithPartOf(outboxIRI),
+			vocab.WithPrev(page1),
+			vocab.WithTotalItems(len(activities)),
+		))
+		require.NoError(t, e)
+
+		page1Bytes, e := json.Marshal(vocab.NewOrderedCollectionPage(
+			[]*vocab.ObjectProperty{
+				vocab.NewObjectProperty(vocab.WithActivity(activities[2])),
+			},
+			vocab.WithContext(vocab.ContextActivityStreams),
+			vocab.WithID(page1),
+			vocab.WithPartOf(outboxIRI),
+			vocab.WithNext(page0),
+			vocab.WithTotalItems(len(activities)),
+		))
+		require.NoError(t, e)
+
+		httpClient := &mocks.HTTPTransport{}
+
+		var results []*http.Response
+
+		for i, b := range [][]byte{orderedCollBytes, page0Bytes, page1Bytes} {
+			rw := httptest.NewRecorder()
+
+			_, e = rw.Write(b)
+			require.NoError(t, e)
+
+			result := rw.Result()
+
+			httpClient.GetReturnsOnCall(i, result, nil)
+
+			results = append(results, result)
+		}
+
+		c := New(Config{}, httpClient)
+		require.NotNil(t, t, c)
+
+		it, e := c.GetActivities(outboxIRI, Reverse)
+		require.NoError(t, e)
+		require.NotNil(t, it)
+
+		items, e := ReadActivities(it, -1)
+		require.NoError(t, e)
+		require.Len(t, items, len(activities))
+		require.Equal(t, activities[0].ID().String(), items[0].ID().String())
+		require.Equal(t, activities[1].ID().String(), items[1].ID().String())
+		require.Equal(t, activities[2].ID().String(), items[2].ID().String())
+
+		_, req := httpClient.GetArgsForCall(1)
+		require.Equal(t, page0.String(), req.URL.String())
+
+		_, req = httpClient.GetArgsForCall(2)
+		require.Equal(t, page1.String(), req.URL.String())
+
+		for _, result := range results {
+			require.NoError(t, result.Body.Close())
+		}
+	})
+
+	t.Run("HTTP client error", func(t *testing.T) {
+		errExpected := fmt.Errorf("injected HTTP client error")
+
+		httpClient := &mocks.HTTPTransport{}
+
+		httpClient.GetReturns(nil, errExpected)
+
+		c := New(Config{}, httpClient)
+		require.NotNil(t, t, c)
+
+		it, e := c.GetActivities(outboxIRI, Forward)
+		require.Error(t, e)
+		require.Contains(t, e.Error(), errExpected.Error())
+		require.Nil(t, it)
+	})
+
+	t.Run("Invalid collection error", func(t *testing.T) {
+		serviceBytes, e := json.Marshal(aptestutil.NewMockService(serviceIRI))
+		require.NoError(t, e)
+
+		rw := httptest.NewRecorder()
+
+		_, e = rw.Write(serviceBytes)
+		require.NoError(t, e)
+
+		httpClient := &mocks.HTTPTransport{}
+
+		result := rw.Result()
+
+		httpClient.GetReturns(result, nil)
+
+		c := New(Config{}, httpClient)
+		require.NotNil(t, t, c)
+
+		it, e := c.GetActivities(outboxIRI, Forward)
+		require.Error(t, e)
+		require.Contains(t, e.Error(), "expecting Collection or OrderedCollection in response payload")
+		require.Nil(t, it)
+
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Invalid collection page error", func(t *testing.T) {
+		collBytes, e := json.Marshal(aptestutil.NewMockCollection(outboxIRI, first, len(activities)))
+		require.NoError(t, e)
+
+		httpClient := &mocks.HTTPTransport{}
+
+		rw1 := httptest.NewRecorder()
+
+		_, e = rw1.Write(collBytes)
+		require.NoError(t, e)
+
+		rw2 := httptest.NewRecorder()
+
+		_, e = rw2.Write([]byte("{"))
+		require.NoError(t, e)
+
+		result1 := rw1.Result()
+		result2 := rw2.Result()
+
+		httpClient.GetReturnsOnCall(0, result1, nil)
+		httpClient.GetReturnsOnCall(1, result2, nil)
+
+		c := New(Config{}, httpClient)
+		require.NotNil(t, t, c)
+
+		it, e := c.GetActivities(outboxIRI, Forward)
+		require.NoError(t, e)
+
+		items, e := ReadActivities(it, -1)
+		require.Error(t, e)
+		require.Contains(t, e.Error(), "unexpected end of JSON input")
+		require.Empty(t, items)
+
+		require.NoError(t, result1.Body.Close())
+		require.NoError(t, result2.Body.Close())
+	})
+}
+
 func TestClient_GetPublicKey(t *testing.T) {
 	serviceIRI := testutil.MustParseURL("https://example.com/services/service1")
 	keyIRI := testutil.NewMockID(serviceIRI, "/keys/main-key")
@@ -30,11 +30,17 @@ type keyResolver interface {
 	Resolve(keyID string) (*ariesverifier.PublicKey, error)
 }
 
+type keySigner interface {
+	// Sign signs the given message with the key of the given ID.
+	Sign(keyID string, msg []byte) ([]byte, error)
+}
+
 // SignatureHashAlgorithm is a custom httpsignatures.SignatureHashAlgorithm that uses KMS to sign HTTP requests.
 type SignatureHashAlgorithm struct {
 	Crypto      crypto.Crypto
 	KMS         kms.KeyManager
 	keyResolver keyResolver
+	keySigner   keySigner
 	keyID       string
 }
 
@@ -47,6 +53,15 @@ func NewSignerAlgorithm(c crypto.Crypto, km kms.KeyManager, keyID string) *Signa
 	}
 }
 
+// NewKeySignerAlgorithm returns a new SignatureHashAlgorithm which uses the given key signer (e.g. a remote
+// signing service) to sign HTTP requests.
+func NewKeySignerAlgorithm(ks keySigner, keyID string) *SignatureHashAlgorithm {
+	return &SignatureHashAlgorithm{
+		keySigner: ks,
+		keyID:     keyID,
+	}
+}
+
 // NewVerifierAlgorithm returns a new SignatureHashAlgorithm which is used to verify the signature
 // in the HTTP request header.
 func NewVerifierAlgorithm(c crypto.Crypto, km kms.KeyManager, keyResolver keyResolver) *SignatureHashAlgorithm {
@@ -64,6 +79,15 @@ func (a *SignatureHashAlgorithm) Algorithm() string {
 
 // Create signs data with the secret.
 func (a *SignatureHashAlgorithm) Create(secret httpsig.Secret, data []byte) ([]byte, error) {
+	if a.keySigner != nil {
+		sig, err := a.keySigner.Sign(a.keyID, data)
+		if err != nil {
+			return nil, fmt.Errorf("sign data with key signer: %w", err)
+		}
+
+		return sig, nil
+	}
+
 	kh, err := a.KMS.Get(a.keyID)
 	if err != nil {
 		return nil, fmt.Errorf("get key handle: %w", err)
@@ -46,38 +46,90 @@ type signer interface {
 	Sign(secretKeyID string, r *http.Request) error
 }
 
+type signingKeyProvider interface {
+	// SigningKey returns the ID of the current signing key in the KMS along with the ID of
+	// the corresponding public key.
+	SigningKey() (keyID, publicKeyID string, err error)
+}
+
+// SignerOpt is a signer option.
+type SignerOpt func(s *Signer)
+
+// WithSigningKeyProvider sets a provider of the signing key. If set, requests are signed with the provider's
+// current key (and the public key ID passed to SignRequest is replaced with the ID of the current public key)
+// so that a rotated key takes effect immediately.
+func WithSigningKeyProvider(p signingKeyProvider) SignerOpt {
+	return func(s *Signer) {
+		s.keyProvider = p
+	}
+}
+
+// WithKeySigner sets a key signer (e.g. a remote signing service) which is used to sign requests instead
+// of the KMS, so that the private key doesn't need to be available locally.
+func WithKeySigner(ks keySigner) SignerOpt {
+	return func(s *Signer) {
+		s.keySigner = ks
+	}
+}
+
 // Signer signs HTTP requests.
 type Signer struct {
 	SignerConfig
-	signer func() signer
+	signer      func(keyID string) signer
+	keyID       string
+	keyProvider signingKeyProvider
+	keySigner   keySigner
 }
 
 // NewSigner returns a new signer.
-func NewSigner(cfg SignerConfig, cr crypto.Crypto, km kms.KeyManager, keyID string) *Signer {
-	algo := NewSignerAlgorithm(cr, km, keyID)
+func NewSigner(cfg SignerConfig, cr crypto.Crypto, km kms.KeyManager, keyID string, opts ...SignerOpt) *Signer {
 	secretRetriever := &SecretRetriever{}
 
-	return &Signer{
+	s := &Signer{
 		SignerConfig: cfg,
-		signer: func() signer {
-			// Return a new instance for each signature since the HTTP signature
-			// implementation is not thread safe.
-			hs := httpsig.NewHTTPSignatures(secretRetriever)
-			hs.SetDefaultSignatureHeaders(cfg.Headers)
-			hs.SetSignatureHashAlgorithm(algo)
-
-			return hs
-		},
+		keyID:        keyID,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.signer = func(keyID string) signer {
+		// Return a new instance for each signature since the HTTP signature
+		// implementation is not thread safe.
+		hs := httpsig.NewHTTPSignatures(secretRetriever)
+		hs.SetDefaultSignatureHeaders(cfg.Headers)
+
+		if s.keySigner != nil {
+			hs.SetSignatureHashAlgorithm(NewKeySignerAlgorithm(s.keySigner, keyID))
+		} else {
+			hs.SetSignatureHashAlgorithm(NewSignerAlgorithm(cr, km, keyID))
+		}
+
+		return hs
 	}
+
+	return s
 }
 
 // SignRequest signs an HTTP request.
 func (s *Signer) SignRequest(pubKeyID string, req *http.Request) error {
+	keyID := s.keyID
+
+	if s.keyProvider != nil {
+		var err error
+
+		keyID, pubKeyID, err = s.keyProvider.SigningKey()
+		if err != nil {
+			return fmt.Errorf("get signing key: %w", err)
+		}
+	}
+
 	req.Header.Add(dateHeader, date())
 
 	logger.Debugf("Signing request for %s. Public key ID [%s]. Headers: %s", req.RequestURI, pubKeyID, req.Header)
 
-	if err := s.signer().Sign(pubKeyID, req); err != nil {
+	if err := s.signer(keyID).Sign(pubKeyID, req); err != nil {
 		return fmt.Errorf("sign request with public key ID [%s]: %w", pubKeyID, err)
 	}
 
@@ -47,6 +47,44 @@ func TestSigner(t *testing.T) {
 		require.NotEmpty(t, req.Header["Signature"])
 	})
 
+	t.Run("Signing key provider", func(t *testing.T) {
+		s := NewSigner(DefaultGetSignerConfig(), &mockcrypto.Crypto{}, &mockkms.KeyManager{}, keyID,
+			WithSigningKeyProvider(&mockSigningKeyProvider{keyID: "key2", publicKeyID: "https://domain1.com/keys/key2"}),
+		)
+
+		req, err := http.NewRequest(http.MethodGet, "https://domain1.com", nil)
+		require.NoError(t, err)
+
+		require.NoError(t, s.SignRequest("pubKeyID", req))
+		require.Contains(t, req.Header.Get("Signature"), `keyId="https://domain1.com/keys/key2"`)
+	})
+
+	t.Run("Signing key provider error", func(t *testing.T) {
+		s := NewSigner(DefaultGetSignerConfig(), &mockcrypto.Crypto{}, &mockkms.KeyManager{}, keyID,
+			WithSigningKeyProvider(&mockSigningKeyProvider{err: errors.New("injected key error")}),
+		)
+
+		req, err := http.NewRequest(http.MethodGet, "https://domain1.com", nil)
+		require.NoError(t, err)
+
+		err = s.SignRequest("pubKeyID", req)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected key error")
+	})
+
+	t.Run("Key signer error", func(t *testing.T) {
+		s := NewSigner(DefaultGetSignerConfig(), &mockcrypto.Crypto{}, &mockkms.KeyManager{}, keyID,
+			WithKeySigner(&mockKeySigner{err: errors.New("injected key signer error")}),
+		)
+
+		req, err := http.NewRequest(http.MethodGet, "https://domain1.com", nil)
+		require.NoError(t, err)
+
+		err = s.SignRequest("pubKeyID", req)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected key signer error")
+	})
+
 	t.Run("Signer error", func(t *testing.T) {
 		errExpected := errors.New("injected KMS error")
 
@@ -62,3 +100,21 @@ func TestSigner(t *testing.T) {
 		require.Contains(t, err.Error(), err.Error())
 	})
 }
+
+type mockSigningKeyProvider struct {
+	keyID       string
+	publicKeyID string
+	err         error
+}
+
+func (m *mockSigningKeyProvider) SigningKey() (string, string, error) {
+	return m.keyID, m.publicKeyID, m.err
+}
+
+type mockKeySigner struct {
+	err error
+}
+
+func (m *mockKeySigner) Sign(string, []byte) ([]byte, error) {
+	return nil, m.err
+}
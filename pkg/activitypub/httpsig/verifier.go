@@ -105,15 +105,16 @@ func (v *Verifier) VerifyRequest(req *http.Request) (bool, *url.URL, error) {
 		return false, nil, fmt.Errorf("get actor [%s]: %w", publicKey.Owner, err)
 	}
 
-	if actor.PublicKey() == nil {
+	if len(actor.PublicKeys()) == 0 {
 		logger.Debugf("nil public key on actor [%s] in request %s: %s", actor.ID(), req.URL)
 
 		return false, nil, nil
 	}
 
-	if actor.PublicKey().ID.String() != publicKey.ID.String() {
-		logger.Debugf("public key [%s] of actor [%s] does not match the provided public key ID [%s] in request %s",
-			actor.PublicKey().ID, actor.ID(), publicKey.ID, req.URL)
+	// The actor may publish more than one key (e.g. the previous key during a key rotation).
+	if !containsKey(actor.PublicKeys(), publicKey.ID.String()) {
+		logger.Debugf("public keys of actor [%s] do not contain the provided public key ID [%s] in request %s",
+			actor.ID(), publicKey.ID, req.URL)
 
 		return false, nil, nil
 	}
@@ -123,6 +124,16 @@ func (v *Verifier) VerifyRequest(req *http.Request) (bool, *url.URL, error) {
 	return true, actor.ID().URL(), nil
 }
 
+func containsKey(publicKeys []*vocab.PublicKeyType, keyID string) bool {
+	for _, publicKey := range publicKeys {
+		if publicKey.ID.String() == keyID {
+			return true
+		}
+	}
+
+	return false
+}
+
 func getKeyIDFromSignatureHeader(req *http.Request) string {
 	signatureHeader, ok := req.Header["Signature"]
 	if !ok || len(signatureHeader) == 0 {
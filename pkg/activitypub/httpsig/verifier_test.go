@@ -14,8 +14,10 @@ import (
 	"encoding/pem"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
 	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	"github.com/stretchr/testify/require"
@@ -25,6 +27,8 @@ import (
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 	"github.com/trustbloc/orb/pkg/internal/aptestutil"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/remotesigner"
+	remotesignermocks "github.com/trustbloc/orb/pkg/remotesigner/mocks"
 )
 
 //go:generate counterfeiter -o ../servicemocks/httpsigverifier.gen.go --fake-name HTTPSignatureVerifier . verifier
@@ -204,6 +208,34 @@ func TestVerifier_VerifyRequest(t *testing.T) {
 		require.Nil(t, actorID)
 	})
 
+	t.Run("Previous actor key", func(t *testing.T) {
+		currentPublicKey := vocab.NewPublicKey(
+			vocab.WithID(testutil.NewMockID(actorIRI, "/keys/key-2")),
+			vocab.WithOwner(actorIRI),
+			vocab.WithPublicKeyPem(string(pubKeyPem)),
+		)
+
+		v := &Verifier{
+			actorRetriever: servicemocks.NewActorRetriever().
+				WithPublicKey(publicKey).
+				WithActor(vocab.NewService(actorIRI,
+					vocab.WithPublicKey(currentPublicKey),
+					vocab.WithPublicKeys(currentPublicKey, publicKey),
+				)),
+			verifier: func() verifier { return &mocks.HTTPSignatureVerifier{} },
+		}
+
+		req, err := http.NewRequest(http.MethodPost, "https://domain1.com", bytes.NewBuffer(payload))
+		require.NoError(t, err)
+
+		require.NoError(t, signer.SignRequest(publicKey.ID.String(), req))
+
+		ok, actorID, err := v.VerifyRequest(req)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, actorIRI.String(), actorID.String())
+	})
+
 	t.Run("Actor key mismatch -> error", func(t *testing.T) {
 		actorPublicKey := vocab.NewPublicKey(
 			vocab.WithID(testutil.NewMockID(actorIRI, "/keys/key-1")),
@@ -230,6 +262,49 @@ func TestVerifier_VerifyRequest(t *testing.T) {
 	})
 }
 
+func TestVerifier_VerifyRequest_KeySigner(t *testing.T) {
+	actorIRI := testutil.MustParseURL("https://example.com/services/orb")
+	pubKeyIRI := testutil.NewMockID(actorIRI, "/keys/main-key")
+
+	svc := httptest.NewServer(remotesignermocks.NewSignerService())
+	defer svc.Close()
+
+	ks := remotesigner.New(svc.URL, remotesigner.WithHTTPClient(svc.Client()))
+
+	keyID, _, err := ks.Create(kms.ED25519Type)
+	require.NoError(t, err)
+
+	pubKey, err := ks.ExportPubKeyBytes(keyID)
+	require.NoError(t, err)
+
+	pubKeyPem, err := getPublicKeyPem(ed25519.PublicKey(pubKey))
+	require.NoError(t, err)
+
+	publicKey := vocab.NewPublicKey(
+		vocab.WithID(pubKeyIRI),
+		vocab.WithOwner(actorIRI),
+		vocab.WithPublicKeyPem(string(pubKeyPem)),
+	)
+
+	retriever := servicemocks.NewActorRetriever().
+		WithPublicKey(publicKey).
+		WithActor(aptestutil.NewMockService(actorIRI, aptestutil.WithPublicKey(publicKey)))
+
+	// The KMS isn't used to sign since the key is only available in the signing service.
+	signer := NewSigner(DefaultPostSignerConfig(), &mockcrypto.Crypto{}, &mockkms.KeyManager{}, keyID,
+		WithKeySigner(ks))
+
+	req, err := http.NewRequest(http.MethodPost, "https://domain1.com", bytes.NewBuffer([]byte("payload")))
+	require.NoError(t, err)
+
+	require.NoError(t, signer.SignRequest(publicKey.ID.String(), req))
+
+	ok, actorID, err := NewVerifier(retriever, &mockcrypto.Crypto{}, &mockkms.KeyManager{}).VerifyRequest(req)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, actorIRI.String(), actorID.String())
+}
+
 func getPublicKeyPem(pubKey interface{}) ([]byte, error) {
 	keyBytes, err := x509.MarshalPKIXPublicKey(pubKey)
 	if err != nil {
@@ -0,0 +1,317 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/store/storeutil"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+// ApprovalAction specifies the action to take on a request that is pending approval.
+type ApprovalAction string
+
+const (
+	// ApprovalActionApprove approves the request and replies to the actor with an 'Accept' activity.
+	ApprovalActionApprove ApprovalAction = "approve"
+
+	// ApprovalActionReject rejects the request and replies to the actor with a 'Reject' activity.
+	ApprovalActionReject ApprovalAction = "reject"
+)
+
+// ApprovalRequest contains the ID of the 'Follow' or 'InviteWitness' activity that is pending approval
+// and the action to take.
+type ApprovalRequest struct {
+	ActivityID string         `json:"activityId"`
+	Action     ApprovalAction `json:"action"`
+}
+
+// PendingApprovalInfo contains a 'Follow' or 'InviteWitness' activity that is pending approval along with the
+// details of the actor that sent it.
+type PendingApprovalInfo struct {
+	Activity *vocab.ActivityType `json:"activity"`
+	Actor    *vocab.ActorType    `json:"actor,omitempty"`
+}
+
+type pendingApprovals interface {
+	ApprovePending(activityID *url.URL) error
+	RejectPending(activityID *url.URL) error
+}
+
+// PendingApproval implements a REST handler that approves or rejects 'Follow' and 'InviteWitness'
+// requests which are pending approval.
+type PendingApproval struct {
+	*Config
+	*AuthHandler
+
+	endpoint  string
+	approvals pendingApprovals
+}
+
+// NewPendingApproval returns a new REST handler to approve or reject requests that are pending approval.
+func NewPendingApproval(cfg *Config, approvals pendingApprovals, s store.Store,
+	verifier signatureVerifier) *PendingApproval {
+	h := &PendingApproval{
+		Config:    cfg,
+		endpoint:  fmt.Sprintf("%s%s", cfg.BasePath, PendingApprovalsPath),
+		approvals: approvals,
+	}
+
+	h.AuthHandler = NewAuthHandler(cfg, PendingApprovalsPath, http.MethodPost, s, verifier, h.authorizeActor)
+
+	return h
+}
+
+// Method returns the HTTP method, which is always POST.
+func (h *PendingApproval) Method() string {
+	return http.MethodPost
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *PendingApproval) Path() string {
+	return h.endpoint
+}
+
+// Handler returns the handler that should be invoked when an HTTP POST is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *PendingApproval) Handler() common.HTTPRequestHandler {
+	return h.handlePost
+}
+
+func (h *PendingApproval) handlePost(w http.ResponseWriter, req *http.Request) {
+	ok, _, err := h.Authorize(req)
+	if err != nil {
+		logger.Errorf("[%s] Error authorizing request: %s", h.endpoint, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if !ok {
+		logger.Infof("[%s] Unauthorized", h.endpoint)
+
+		h.writeResponse(w, http.StatusUnauthorized, []byte(unauthorizedResponse))
+
+		return
+	}
+
+	reqBytes, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		logger.Errorf("[%s] Error reading request body: %s", h.endpoint, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	activityID, action, err := unmarshalApprovalRequest(reqBytes)
+	if err != nil {
+		logger.Debugf("[%s] Invalid request: %s", h.endpoint, err)
+
+		h.writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	if action == ApprovalActionApprove {
+		err = h.approvals.ApprovePending(activityID)
+	} else {
+		err = h.approvals.RejectPending(activityID)
+	}
+
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			logger.Debugf("[%s] Activity [%s] is not pending approval: %s", h.endpoint, activityID, err)
+
+			h.writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+		case orberrors.IsBadRequest(err):
+			logger.Debugf("[%s] Error processing '%s' for activity [%s]: %s", h.endpoint, action, activityID, err)
+
+			h.writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+		default:
+			logger.Errorf("[%s] Error processing '%s' for activity [%s]: %s", h.endpoint, action, activityID, err)
+
+			h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+		}
+
+		return
+	}
+
+	logger.Infof("[%s] Processed '%s' for activity [%s]", h.endpoint, action, activityID)
+
+	h.writeResponse(w, http.StatusOK, nil)
+}
+
+func (h *PendingApproval) authorizeActor(actorIRI *url.URL) (bool, error) {
+	// Only the local service is allowed to approve or reject requests.
+	return actorIRI.String() == h.ObjectIRI.String(), nil
+}
+
+func unmarshalApprovalRequest(reqBytes []byte) (*url.URL, ApprovalAction, error) {
+	r := &ApprovalRequest{}
+
+	err := json.Unmarshal(reqBytes, r)
+	if err != nil {
+		return nil, "", fmt.Errorf("unmarshal request: %w", err)
+	}
+
+	if r.Action != ApprovalActionApprove && r.Action != ApprovalActionReject {
+		return nil, "", fmt.Errorf("unsupported action: %s", r.Action)
+	}
+
+	if r.ActivityID == "" {
+		return nil, "", fmt.Errorf("activity ID is required")
+	}
+
+	activityID, err := url.Parse(r.ActivityID)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid activity ID: %w", err)
+	}
+
+	return activityID, r.Action, nil
+}
+
+// PendingApprovals implements a REST handler that lists the 'Follow' and 'InviteWitness' requests
+// which are pending approval.
+type PendingApprovals struct {
+	*Config
+	*AuthHandler
+
+	endpoint      string
+	activityStore store.Store
+}
+
+// NewPendingApprovals returns a new REST handler that lists the requests that are pending approval.
+func NewPendingApprovals(cfg *Config, s store.Store, verifier signatureVerifier) *PendingApprovals {
+	h := &PendingApprovals{
+		Config:        cfg,
+		endpoint:      fmt.Sprintf("%s%s", cfg.BasePath, PendingApprovalsPath),
+		activityStore: s,
+	}
+
+	h.AuthHandler = NewAuthHandler(cfg, PendingApprovalsPath, http.MethodGet, s, verifier, h.authorizeActor)
+
+	return h
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *PendingApprovals) Method() string {
+	return http.MethodGet
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *PendingApprovals) Path() string {
+	return h.endpoint
+}
+
+// Handler returns the handler that should be invoked when an HTTP GET is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *PendingApprovals) Handler() common.HTTPRequestHandler {
+	return h.handleGet
+}
+
+func (h *PendingApprovals) handleGet(w http.ResponseWriter, req *http.Request) {
+	ok, _, err := h.Authorize(req)
+	if err != nil {
+		logger.Errorf("[%s] Error authorizing request: %s", h.endpoint, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if !ok {
+		logger.Infof("[%s] Unauthorized", h.endpoint)
+
+		h.writeResponse(w, http.StatusUnauthorized, []byte(unauthorizedResponse))
+
+		return
+	}
+
+	pending, err := h.getPendingApprovals()
+	if err != nil {
+		logger.Errorf("[%s] Error retrieving pending approvals: %s", h.endpoint, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	respBytes, err := json.Marshal(pending)
+	if err != nil {
+		logger.Errorf("[%s] Unable to marshal pending approvals: %s", h.endpoint, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	h.writeResponse(w, http.StatusOK, respBytes)
+}
+
+func (h *PendingApprovals) getPendingApprovals() ([]*PendingApprovalInfo, error) {
+	it, err := h.activityStore.QueryReferences(store.PendingApproval,
+		store.NewCriteria(store.WithObjectIRI(h.ObjectIRI)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query pending approval references: %w", err)
+	}
+
+	defer func() {
+		err = it.Close()
+		if err != nil {
+			logger.Errorf("failed to close iterator: %s", err.Error())
+		}
+	}()
+
+	activityIDs, err := storeutil.ReadReferences(it, -1)
+	if err != nil {
+		return nil, fmt.Errorf("read pending approval references: %w", err)
+	}
+
+	pending := make([]*PendingApprovalInfo, 0, len(activityIDs))
+
+	for _, activityID := range activityIDs {
+		activity, err := h.activityStore.GetActivity(activityID)
+		if err != nil {
+			return nil, fmt.Errorf("get activity [%s]: %w", activityID, err)
+		}
+
+		actor, err := h.activityStore.GetActor(activity.Actor())
+		if err != nil {
+			if !errors.Is(err, store.ErrNotFound) {
+				return nil, fmt.Errorf("get actor [%s]: %w", activity.Actor(), err)
+			}
+
+			logger.Debugf("[%s] Actor [%s] not found for activity [%s]", h.endpoint, activity.Actor(), activityID)
+		}
+
+		pending = append(pending, &PendingApprovalInfo{
+			Activity: activity,
+			Actor:    actor,
+		})
+	}
+
+	return pending, nil
+}
+
+func (h *PendingApprovals) authorizeActor(actorIRI *url.URL) (bool, error) {
+	// Only the local service is allowed to view the pending approvals.
+	return actorIRI.String() == h.ObjectIRI.String(), nil
+}
@@ -0,0 +1,361 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/httpserver/auth"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+func TestNewPendingApproval(t *testing.T) {
+	cfg := &Config{
+		BasePath:  "/services/orb",
+		ObjectIRI: serviceIRI,
+	}
+
+	h := NewPendingApproval(cfg, &mockPendingApprovals{}, memstore.New(""), &mocks.SignatureVerifier{})
+
+	require.NotNil(t, h.Handler())
+	require.Equal(t, http.MethodPost, h.Method())
+	require.Equal(t, "/services/orb/pendingapprovals", h.Path())
+}
+
+func TestPendingApproval_Handler(t *testing.T) {
+	const approvalsURL = "https://example1.com/services/orb/pendingapprovals"
+
+	activityID := testutil.NewMockID(service2IRI, "/activities/123456789")
+
+	cfg := &Config{
+		BasePath:  "/services/orb",
+		ObjectIRI: serviceIRI,
+		Config: auth.Config{
+			AuthTokensDef: []*auth.TokenDef{
+				{
+					EndpointExpression: "/services/orb/pendingapprovals",
+					ReadTokens:         []string{"admin"},
+					WriteTokens:        []string{"admin"},
+				},
+			},
+			AuthTokens: map[string]string{
+				"admin": "ADMIN_TOKEN",
+			},
+		},
+	}
+
+	activityStore := memstore.New("")
+
+	newRequest := func(t *testing.T, activityID string, action ApprovalAction) *http.Request {
+		t.Helper()
+
+		reqBytes, err := json.Marshal(&ApprovalRequest{ActivityID: activityID, Action: action})
+		require.NoError(t, err)
+
+		return httptest.NewRequest(http.MethodPost, approvalsURL, bytes.NewBuffer(reqBytes))
+	}
+
+	t.Run("Approve -> success", func(t *testing.T) {
+		verifier := &mocks.SignatureVerifier{}
+		verifier.VerifyRequestReturns(true, serviceIRI, nil)
+
+		approvals := &mockPendingApprovals{}
+
+		h := NewPendingApproval(cfg, approvals, activityStore, verifier)
+
+		rw := httptest.NewRecorder()
+
+		h.handlePost(rw, newRequest(t, activityID.String(), ApprovalActionApprove))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, activityID.String(), approvals.approved.String())
+		require.Nil(t, approvals.rejected)
+	})
+
+	t.Run("Reject -> success", func(t *testing.T) {
+		verifier := &mocks.SignatureVerifier{}
+		verifier.VerifyRequestReturns(true, serviceIRI, nil)
+
+		approvals := &mockPendingApprovals{}
+
+		h := NewPendingApproval(cfg, approvals, activityStore, verifier)
+
+		rw := httptest.NewRecorder()
+
+		h.handlePost(rw, newRequest(t, activityID.String(), ApprovalActionReject))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, activityID.String(), approvals.rejected.String())
+		require.Nil(t, approvals.approved)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		verifier := &mocks.SignatureVerifier{}
+		verifier.VerifyRequestReturns(true, service2IRI, nil)
+
+		h := NewPendingApproval(cfg, &mockPendingApprovals{}, activityStore, verifier)
+
+		rw := httptest.NewRecorder()
+
+		h.handlePost(rw, newRequest(t, activityID.String(), ApprovalActionApprove))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusUnauthorized, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("HTTP signature verifier error", func(t *testing.T) {
+		verifier := &mocks.SignatureVerifier{}
+		verifier.VerifyRequestReturns(false, nil, errors.New("injected signature verifier error"))
+
+		h := NewPendingApproval(cfg, &mockPendingApprovals{}, activityStore, verifier)
+
+		rw := httptest.NewRecorder()
+
+		h.handlePost(rw, newRequest(t, activityID.String(), ApprovalActionApprove))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Bad request", func(t *testing.T) {
+		verifier := &mocks.SignatureVerifier{}
+		verifier.VerifyRequestReturns(true, serviceIRI, nil)
+
+		h := NewPendingApproval(cfg, &mockPendingApprovals{}, activityStore, verifier)
+
+		for _, req := range []*http.Request{
+			httptest.NewRequest(http.MethodPost, approvalsURL, bytes.NewBufferString("{")),
+			newRequest(t, activityID.String(), "xxx"),
+			newRequest(t, "", ApprovalActionApprove),
+			newRequest(t, string([]byte{0x0}), ApprovalActionApprove),
+		} {
+			rw := httptest.NewRecorder()
+
+			h.handlePost(rw, req)
+
+			result := rw.Result()
+			require.Equal(t, http.StatusBadRequest, result.StatusCode)
+			require.NoError(t, result.Body.Close())
+		}
+	})
+
+	t.Run("Approval errors", func(t *testing.T) {
+		verifier := &mocks.SignatureVerifier{}
+		verifier.VerifyRequestReturns(true, serviceIRI, nil)
+
+		for err, status := range map[error]int{
+			fmt.Errorf("not pending: %w", store.ErrNotFound):        http.StatusNotFound,
+			orberrors.NewBadRequest(errors.New("unsupported type")): http.StatusBadRequest,
+			errors.New("injected error"):                            http.StatusInternalServerError,
+		} {
+			h := NewPendingApproval(cfg, &mockPendingApprovals{err: err}, activityStore, verifier)
+
+			rw := httptest.NewRecorder()
+
+			h.handlePost(rw, newRequest(t, activityID.String(), ApprovalActionReject))
+
+			result := rw.Result()
+			require.Equal(t, status, result.StatusCode)
+			require.NoError(t, result.Body.Close())
+		}
+	})
+}
+
+func TestNewPendingApprovals(t *testing.T) {
+	cfg := &Config{
+		BasePath:  "/services/orb",
+		ObjectIRI: serviceIRI,
+	}
+
+	h := NewPendingApprovals(cfg, memstore.New(""), &mocks.SignatureVerifier{})
+
+	require.NotNil(t, h.Handler())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.Equal(t, "/services/orb/pendingapprovals", h.Path())
+}
+
+func TestPendingApprovals_Handler(t *testing.T) {
+	const approvalsURL = "https://example1.com/services/orb/pendingapprovals"
+
+	service3IRI := testutil.MustParseURL("https://example3.com/services/orb")
+
+	cfg := &Config{
+		BasePath:  "/services/orb",
+		ObjectIRI: serviceIRI,
+		Config: auth.Config{
+			AuthTokensDef: []*auth.TokenDef{
+				{
+					EndpointExpression: "/services/orb/pendingapprovals",
+					ReadTokens:         []string{"admin"},
+					WriteTokens:        []string{"admin"},
+				},
+			},
+			AuthTokens: map[string]string{
+				"admin": "ADMIN_TOKEN",
+			},
+		},
+	}
+
+	follow := vocab.NewFollowActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(serviceIRI)),
+		vocab.WithID(testutil.NewMockID(service2IRI, "/activities/follow")),
+		vocab.WithActor(service2IRI),
+		vocab.WithTo(serviceIRI),
+	)
+
+	invite := vocab.NewInviteActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI)),
+		vocab.WithID(testutil.NewMockID(service3IRI, "/activities/invite")),
+		vocab.WithActor(service3IRI),
+		vocab.WithTo(serviceIRI),
+		vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(serviceIRI))),
+	)
+
+	activityStore := memstore.New("")
+
+	require.NoError(t, activityStore.PutActor(vocab.NewService(service2IRI)))
+
+	for _, a := range []*vocab.ActivityType{follow, invite} {
+		require.NoError(t, activityStore.AddActivity(a))
+		require.NoError(t, activityStore.AddReference(store.PendingApproval, serviceIRI, a.ID().URL()))
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		verifier := &mocks.SignatureVerifier{}
+		verifier.VerifyRequestReturns(true, serviceIRI, nil)
+
+		h := NewPendingApprovals(cfg, activityStore, verifier)
+
+		rw := httptest.NewRecorder()
+
+		h.handleGet(rw, httptest.NewRequest(http.MethodGet, approvalsURL, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		var pending []*PendingApprovalInfo
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&pending))
+		require.NoError(t, result.Body.Close())
+
+		require.Len(t, pending, 2)
+
+		for _, p := range pending {
+			switch p.Activity.ID().String() {
+			case follow.ID().String():
+				require.NotNil(t, p.Actor)
+				require.Equal(t, service2IRI.String(), p.Actor.ID().String())
+			case invite.ID().String():
+				// The actor for the invitation was not stored.
+				require.Nil(t, p.Actor)
+			default:
+				t.Fatalf("unexpected activity: %s", p.Activity.ID())
+			}
+		}
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		verifier := &mocks.SignatureVerifier{}
+		verifier.VerifyRequestReturns(true, service2IRI, nil)
+
+		h := NewPendingApprovals(cfg, activityStore, verifier)
+
+		rw := httptest.NewRecorder()
+
+		h.handleGet(rw, httptest.NewRequest(http.MethodGet, approvalsURL, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusUnauthorized, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("HTTP signature verifier error", func(t *testing.T) {
+		verifier := &mocks.SignatureVerifier{}
+		verifier.VerifyRequestReturns(false, nil, errors.New("injected signature verifier error"))
+
+		h := NewPendingApprovals(cfg, activityStore, verifier)
+
+		rw := httptest.NewRecorder()
+
+		h.handleGet(rw, httptest.NewRequest(http.MethodGet, approvalsURL, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Store errors", func(t *testing.T) {
+		verifier := &mocks.SignatureVerifier{}
+		verifier.VerifyRequestReturns(true, serviceIRI, nil)
+
+		errExpected := errors.New("injected store error")
+
+		refStore := memstore.New("")
+		require.NoError(t, refStore.AddReference(store.PendingApproval, serviceIRI, follow.ID().URL()))
+
+		s1 := &mocks.ActivityStore{}
+		s1.QueryReferencesReturns(nil, errExpected)
+
+		s2 := &mocks.ActivityStore{}
+		s2.QueryReferencesStub = refStore.QueryReferences
+		s2.GetActivityReturns(nil, errExpected)
+
+		s3 := &mocks.ActivityStore{}
+		s3.QueryReferencesStub = refStore.QueryReferences
+		s3.GetActivityReturns(follow, nil)
+		s3.GetActorReturns(nil, errExpected)
+
+		for _, s := range []*mocks.ActivityStore{s1, s2, s3} {
+			h := NewPendingApprovals(cfg, s, verifier)
+
+			rw := httptest.NewRecorder()
+
+			h.handleGet(rw, httptest.NewRequest(http.MethodGet, approvalsURL, nil))
+
+			result := rw.Result()
+			require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+			require.NoError(t, result.Body.Close())
+		}
+	})
+}
+
+type mockPendingApprovals struct {
+	approved *url.URL
+	rejected *url.URL
+	err      error
+}
+
+func (m *mockPendingApprovals) ApprovePending(activityID *url.URL) error {
+	m.approved = activityID
+
+	return m.err
+}
+
+func (m *mockPendingApprovals) RejectPending(activityID *url.URL) error {
+	m.rejected = activityID
+
+	return m.err
+}
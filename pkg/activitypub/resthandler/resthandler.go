@@ -47,6 +47,8 @@ const (
 	LikesPath = "/likes"
 	// ActivitiesPath specifies the object's 'activities' endpoint.
 	ActivitiesPath = "/activities/{id}"
+	// PendingApprovalsPath specifies the service's 'pending approvals' (administrative) endpoint.
+	PendingApprovalsPath = "/pendingapprovals"
 )
 
 const (
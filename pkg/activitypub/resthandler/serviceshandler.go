@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package resthandler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -18,30 +19,57 @@ import (
 // MainKeyID is the ID of the service's public key.
 const MainKeyID = "main-key"
 
+type publicKeyProvider interface {
+	// PublicKeys returns the service's public keys. The first key is the current key.
+	PublicKeys() ([]*vocab.PublicKeyType, error)
+}
+
+// ServicesOpt is an option for the 'services' and public keys REST handlers.
+type ServicesOpt func(h *Services)
+
+// WithPublicKeyProvider sets a provider of the service's public keys. If set then the keys from the provider
+// (e.g. the current key and the previous key during a key rotation) are published instead of the given
+// public key.
+func WithPublicKeyProvider(p publicKeyProvider) ServicesOpt {
+	return func(h *Services) {
+		h.publicKeyProvider = p
+	}
+}
+
 // Services implements the 'services' REST handler to retrieve a given ActivityPub service (actor).
 type Services struct {
 	*handler
 
-	publicKey *vocab.PublicKeyType
+	publicKey         *vocab.PublicKeyType
+	publicKeyProvider publicKeyProvider
 }
 
 // NewServices returns a new 'services' REST handler.
-func NewServices(cfg *Config, activityStore spi.Store, publicKey *vocab.PublicKeyType) *Services {
+func NewServices(cfg *Config, activityStore spi.Store, publicKey *vocab.PublicKeyType, opts ...ServicesOpt) *Services {
 	h := &Services{
 		publicKey: publicKey,
 	}
 
+	for _, opt := range opts {
+		opt(h)
+	}
+
 	h.handler = newHandler("", cfg, activityStore, h.handle, nil)
 
 	return h
 }
 
 // NewPublicKeys returns a new public keys REST handler.
-func NewPublicKeys(cfg *Config, activityStore spi.Store, publicKey *vocab.PublicKeyType) *Services {
+func NewPublicKeys(cfg *Config, activityStore spi.Store, publicKey *vocab.PublicKeyType,
+	opts ...ServicesOpt) *Services {
 	h := &Services{
 		publicKey: publicKey,
 	}
 
+	for _, opt := range opts {
+		opt(h)
+	}
+
 	h.handler = newHandler(PublicKeysPath, cfg, activityStore, h.handlePublicKey, nil)
 
 	return h
@@ -92,9 +120,16 @@ func (h *Services) handlePublicKey(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	// Currently only one key is supported. In the future we may wish to have
-	// multiple keys per service.
-	if keyID != MainKeyID {
+	publicKey, err := h.getPublicKey(keyID)
+	if err != nil {
+		logger.Errorf("[%s] Unable to get public key [%s] for [%s]: %s", h.endpoint, keyID, h.ObjectIRI, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if publicKey == nil {
 		logger.Infof("[%s] Public key [%s] not found for [%s]", h.endpoint, h.ObjectIRI, keyID)
 
 		h.writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
@@ -102,7 +137,7 @@ func (h *Services) handlePublicKey(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	publicKeyBytes, err := h.marshal(h.publicKey)
+	publicKeyBytes, err := h.marshal(publicKey)
 	if err != nil {
 		logger.Errorf("[%s] Unable to marshal public key [%s]: %s", h.endpoint, h.ObjectIRI, err)
 
@@ -162,8 +197,18 @@ func (h *Services) newService() (*vocab.ActorType, error) {
 		return nil, err
 	}
 
-	return vocab.NewService(h.ObjectIRI,
-		vocab.WithPublicKey(h.publicKey),
+	publicKeys, err := h.getPublicKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []vocab.Opt{vocab.WithPublicKey(publicKeys[0])}
+
+	if len(publicKeys) > 1 {
+		opts = append(opts, vocab.WithPublicKeys(publicKeys...))
+	}
+
+	return vocab.NewService(h.ObjectIRI, append(opts,
 		vocab.WithInbox(inbox),
 		vocab.WithOutbox(outbox),
 		vocab.WithFollowers(followers),
@@ -173,7 +218,53 @@ func (h *Services) newService() (*vocab.ActorType, error) {
 		vocab.WithLiked(liked),
 		vocab.WithLikes(likes),
 		vocab.WithShares(shares),
-	), nil
+	)...), nil
+}
+
+func (h *Services) getPublicKeys() ([]*vocab.PublicKeyType, error) {
+	if h.publicKeyProvider == nil {
+		return []*vocab.PublicKeyType{h.publicKey}, nil
+	}
+
+	publicKeys, err := h.publicKeyProvider.PublicKeys()
+	if err != nil {
+		return nil, fmt.Errorf("get public keys: %w", err)
+	}
+
+	if len(publicKeys) == 0 {
+		return nil, errors.New("no public keys for service")
+	}
+
+	return publicKeys, nil
+}
+
+// getPublicKey returns the public key with the given ID or nil if the key isn't found.
+func (h *Services) getPublicKey(keyID string) (*vocab.PublicKeyType, error) {
+	if h.publicKeyProvider == nil {
+		if keyID != MainKeyID {
+			return nil, nil
+		}
+
+		return h.publicKey, nil
+	}
+
+	publicKeys, err := h.getPublicKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	keyIRI, err := newID(h.ObjectIRI, "/keys/"+keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, publicKey := range publicKeys {
+		if publicKey.ID.String() == keyIRI.String() {
+			return publicKey, nil
+		}
+	}
+
+	return nil, nil
 }
 
 func newID(iri fmt.Stringer, path string) (*url.URL, error) {
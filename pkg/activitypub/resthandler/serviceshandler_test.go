@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package resthandler
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -39,6 +41,12 @@ var publicKey = vocab.NewPublicKey(
 	vocab.WithPublicKeyPem(keyPem),
 )
 
+var publicKey2 = vocab.NewPublicKey(
+	vocab.WithID(testutil.NewMockID(serviceIRI, "/keys/key2")),
+	vocab.WithOwner(serviceIRI),
+	vocab.WithPublicKeyPem(keyPem),
+)
+
 func TestNewServices(t *testing.T) {
 	cfg := &Config{
 		BasePath:  basePath,
@@ -150,6 +158,63 @@ func TestServices_Handler(t *testing.T) {
 	})
 }
 
+func TestServices_PublicKeyProvider(t *testing.T) {
+	cfg := &Config{
+		BasePath:  basePath,
+		ObjectIRI: serviceIRI,
+		PageSize:  4,
+	}
+
+	activityStore := memstore.New("")
+
+	t.Run("Success", func(t *testing.T) {
+		h := NewServices(cfg, activityStore, nil,
+			WithPublicKeyProvider(&mockPublicKeyProvider{keys: []*vocab.PublicKeyType{publicKey2, publicKey}}),
+		)
+
+		rw := httptest.NewRecorder()
+
+		h.handle(rw, httptest.NewRequest(http.MethodGet, serviceIRI.String(), nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		service := &vocab.ActorType{}
+		require.NoError(t, json.NewDecoder(result.Body).Decode(service))
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, publicKey2.ID.String(), service.PublicKey().ID.String())
+		require.Len(t, service.PublicKeys(), 2)
+		require.Equal(t, publicKeyIRI.String(), service.PublicKeys()[1].ID.String())
+	})
+
+	t.Run("Provider error", func(t *testing.T) {
+		h := NewServices(cfg, activityStore, nil,
+			WithPublicKeyProvider(&mockPublicKeyProvider{err: errors.New("injected error")}),
+		)
+
+		rw := httptest.NewRecorder()
+
+		h.handle(rw, httptest.NewRequest(http.MethodGet, serviceIRI.String(), nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("No keys", func(t *testing.T) {
+		h := NewServices(cfg, activityStore, nil, WithPublicKeyProvider(&mockPublicKeyProvider{}))
+
+		rw := httptest.NewRecorder()
+
+		h.handle(rw, httptest.NewRequest(http.MethodGet, serviceIRI.String(), nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+}
+
 func TestPublicKeys_Handler(t *testing.T) {
 	cfg := &Config{
 		BasePath:  basePath,
@@ -236,6 +301,60 @@ func TestPublicKeys_Handler(t *testing.T) {
 		require.NoError(t, result.Body.Close())
 	})
 
+	t.Run("Public key provider", func(t *testing.T) {
+		h := NewPublicKeys(cfg, activityStore, nil,
+			WithPublicKeyProvider(&mockPublicKeyProvider{keys: []*vocab.PublicKeyType{publicKey2, publicKey}}),
+		)
+		require.NotNil(t, h)
+
+		for _, keyID := range []string{"key2", MainKeyID} {
+			rw := httptest.NewRecorder()
+
+			restoreID := setIDParam(keyID)
+
+			h.handlePublicKey(rw, httptest.NewRequest(http.MethodGet, serviceIRI.String(), nil))
+
+			restoreID()
+
+			result := rw.Result()
+			require.Equal(t, http.StatusOK, result.StatusCode)
+
+			respKey := &vocab.PublicKeyType{}
+			require.NoError(t, json.NewDecoder(result.Body).Decode(respKey))
+			require.NoError(t, result.Body.Close())
+			require.Equal(t, testutil.NewMockID(serviceIRI, "/keys/"+keyID).String(), respKey.ID.String())
+		}
+
+		rw := httptest.NewRecorder()
+
+		restoreID := setIDParam("key3")
+		defer restoreID()
+
+		h.handlePublicKey(rw, httptest.NewRequest(http.MethodGet, serviceIRI.String(), nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusNotFound, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Public key provider error", func(t *testing.T) {
+		h := NewPublicKeys(cfg, activityStore, nil,
+			WithPublicKeyProvider(&mockPublicKeyProvider{err: errors.New("injected error")}),
+		)
+		require.NotNil(t, h)
+
+		rw := httptest.NewRecorder()
+
+		restoreID := setIDParam(MainKeyID)
+		defer restoreID()
+
+		h.handlePublicKey(rw, httptest.NewRequest(http.MethodGet, serviceIRI.String(), nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
 	t.Run("Unauthorized", func(t *testing.T) {
 		cfg := &Config{
 			BasePath:  basePath,
@@ -272,6 +391,15 @@ func TestPublicKeys_Handler(t *testing.T) {
 	})
 }
 
+type mockPublicKeyProvider struct {
+	keys []*vocab.PublicKeyType
+	err  error
+}
+
+func (m *mockPublicKeyProvider) PublicKeys() ([]*vocab.PublicKeyType, error) {
+	return m.keys, m.err
+}
+
 const (
 	serviceJSON = `{
   "@context": [
@@ -62,9 +62,10 @@ type handler struct {
 	client            activityPubClient
 	undoFollow        undoFunc
 	undoInviteWitness undoFunc
+	undoHandler       service.UndoHandler
 }
 
-func newHandler(cfg *Config, s store.Store, activityPubClient activityPubClient,
+func newHandler(cfg *Config, s store.Store, activityPubClient activityPubClient, undoHandler service.UndoHandler,
 	undoFollow, undoInviteWitness undoFunc) *handler {
 	if cfg.BufferSize == 0 {
 		cfg.BufferSize = defaultBufferSize
@@ -80,6 +81,7 @@ func newHandler(cfg *Config, s store.Store, activityPubClient activityPubClient,
 		client:            activityPubClient,
 		undoFollow:        undoFollow,
 		undoInviteWitness: undoInviteWitness,
+		undoHandler:       undoHandler,
 	}
 
 	h.Lifecycle = lifecycle.New(cfg.ServiceName, lifecycle.WithStop(h.stop))
@@ -179,9 +181,20 @@ func defaultOptions() *service.Handlers {
 		FollowerAuth:            &acceptAllActorsAuth{},
 		WitnessInvitationAuth:   &acceptAllActorsAuth{},
 		ProofHandler:            &noOpProofHandler{},
+		UndoHandler:             &noOpUndoHandler{},
 	}
 }
 
+// cleanUpUndo notifies the undo handler that the given actor was removed from the given collection
+// so that any dependent state is cleaned up.
+func (h *handler) cleanUpUndo(refType store.ReferenceType, actorIRI *url.URL) error {
+	if err := h.undoHandler.HandleUndo(refType, actorIRI); err != nil {
+		return fmt.Errorf("clean up state for %s %s: %w", refType, actorIRI, err)
+	}
+
+	return nil
+}
+
 func containsIRI(iris []*url.URL, iri fmt.Stringer) bool {
 	for _, f := range iris {
 		if f.String() == iri.String() {
@@ -79,6 +79,10 @@ func TestNoOpProofHandler_HandleProof(t *testing.T) {
 	require.Nil(t, (&noOpProofHandler{}).HandleProof(nil, "", time.Now(), nil))
 }
 
+func TestNoOpUndoHandler_HandleUndo(t *testing.T) {
+	require.Nil(t, (&noOpUndoHandler{}).HandleUndo(store.Witness, nil))
+}
+
 func TestHandler_HandleUnsupportedActivity(t *testing.T) {
 	cfg := &Config{
 		ServiceName: "service1",
@@ -513,6 +517,125 @@ func TestHandler_HandleFollowActivity(t *testing.T) {
 	})
 }
 
+func TestHandler_PendingApproval(t *testing.T) {
+	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
+	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
+	service3IRI := testutil.MustParseURL("http://localhost:8303/services/service3")
+
+	cfg := &Config{
+		ServiceName: "service1",
+		ServiceIRI:  service1IRI,
+	}
+
+	ob := mocks.NewOutbox()
+	as := memstore.New(cfg.ServiceName)
+
+	apClient := mocks.NewActorRetriever().
+		WithActor(vocab.NewService(service2IRI)).
+		WithActor(vocab.NewService(service3IRI))
+
+	actorAuth := mocks.NewActorAuth().WithError(spi.ErrAuthorizationPending)
+
+	h := NewInbox(cfg, as, ob, apClient, spi.WithFollowerAuth(actorAuth), spi.WithWitnessInvitationAuth(actorAuth))
+	require.NotNil(t, h)
+
+	h.Start()
+	defer h.Stop()
+
+	// handlePending handles the activity and adds it to the store, as is done by the inbox.
+	handlePending := func(t *testing.T, activity *vocab.ActivityType) {
+		t.Helper()
+
+		require.NoError(t, h.HandleActivity(activity))
+		require.NoError(t, as.AddActivity(activity))
+
+		pending, err := h.hasReference(service1IRI, activity.ID().URL(), store.PendingApproval)
+		require.NoError(t, err)
+		require.True(t, pending)
+
+		actor, err := as.GetActor(activity.Actor())
+		require.NoError(t, err)
+		require.Equal(t, activity.Actor().String(), actor.ID().String())
+	}
+
+	t.Run("Approve Follow", func(t *testing.T) {
+		follow := vocab.NewFollowActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(service1IRI)),
+			vocab.WithID(newActivityID(service2IRI)),
+			vocab.WithActor(service2IRI),
+			vocab.WithTo(service1IRI),
+		)
+
+		handlePending(t, follow)
+
+		require.Empty(t, ob.Activities().QueryByType(vocab.TypeAccept))
+
+		require.NoError(t, h.ApprovePending(follow.ID().URL()))
+
+		hasFollower, err := h.hasReference(service1IRI, service2IRI, store.Follower)
+		require.NoError(t, err)
+		require.True(t, hasFollower)
+
+		require.Len(t, ob.Activities().QueryByType(vocab.TypeAccept), 1)
+
+		err = h.ApprovePending(follow.ID().URL())
+		require.Error(t, err)
+		require.True(t, errors.Is(err, store.ErrNotFound))
+	})
+
+	t.Run("Reject InviteWitness", func(t *testing.T) {
+		invite := vocab.NewInviteActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI)),
+			vocab.WithID(newActivityID(service3IRI)),
+			vocab.WithActor(service3IRI),
+			vocab.WithTo(service1IRI),
+			vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(service1IRI))),
+		)
+
+		handlePending(t, invite)
+
+		require.NoError(t, h.RejectPending(invite.ID().URL()))
+
+		hasWitnessing, err := h.hasReference(service1IRI, service3IRI, store.Witnessing)
+		require.NoError(t, err)
+		require.False(t, hasWitnessing)
+
+		require.Len(t, ob.Activities().QueryByType(vocab.TypeReject), 1)
+
+		err = h.RejectPending(invite.ID().URL())
+		require.Error(t, err)
+		require.True(t, errors.Is(err, store.ErrNotFound))
+	})
+
+	t.Run("Unsupported activity type", func(t *testing.T) {
+		like := vocab.NewLikeActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(service1IRI)),
+			vocab.WithID(newActivityID(service2IRI)),
+			vocab.WithActor(service2IRI),
+		)
+
+		require.NoError(t, as.AddActivity(like))
+		require.NoError(t, as.AddReference(store.PendingApproval, service1IRI, like.ID().URL()))
+
+		err := h.ApprovePending(like.ID().URL())
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		s := &mocks.ActivityStore{}
+		s.QueryReferencesReturns(nil, errExpected)
+
+		ibHandler := NewInbox(cfg, s, ob, apClient)
+
+		err := ibHandler.ApprovePending(service2IRI)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+	})
+}
+
 func TestHandler_HandleInviteWitnessActivity(t *testing.T) {
 	log.SetLevel("activitypub_service", log.DEBUG)
 
@@ -2588,6 +2711,74 @@ func TestHandler_HandleUndoInviteWitnessActivity(t *testing.T) {
 	})
 }
 
+func TestHandler_UndoHandler(t *testing.T) {
+	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
+	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
+
+	invite := vocab.NewInviteActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI)),
+		vocab.WithID(newActivityID(service2IRI)),
+		vocab.WithActor(service2IRI),
+		vocab.WithTo(service1IRI),
+		vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(service1IRI))),
+	)
+
+	newUndo := func() *vocab.ActivityType {
+		return vocab.NewUndoActivity(
+			vocab.NewObjectProperty(vocab.WithActivity(invite)),
+			vocab.WithID(newActivityID(service2IRI)),
+			vocab.WithActor(service2IRI),
+			vocab.WithTo(service1IRI),
+		)
+	}
+
+	t.Run("Outbox -> success", func(t *testing.T) {
+		undoHandler := mocks.NewUndoHandler()
+
+		obHandler := NewOutbox(&Config{ServiceName: "outbox1", ServiceIRI: service2IRI},
+			memstore.New("outbox1"), mocks.NewActorRetriever(), spi.WithUndoHandler(undoHandler))
+
+		require.NoError(t, obHandler.store.AddActivity(invite))
+		require.NoError(t, obHandler.store.AddReference(store.Witness, service2IRI, service1IRI))
+
+		require.NoError(t, obHandler.HandleActivity(newUndo()))
+
+		removed := undoHandler.Removed(store.Witness)
+		require.Len(t, removed, 1)
+		require.Equal(t, service1IRI.String(), removed[0].String())
+	})
+
+	t.Run("Inbox -> success", func(t *testing.T) {
+		undoHandler := mocks.NewUndoHandler()
+
+		ibHandler := NewInbox(&Config{ServiceName: "inbox1", ServiceIRI: service1IRI},
+			memstore.New("inbox1"), mocks.NewOutbox(), mocks.NewActorRetriever(), spi.WithUndoHandler(undoHandler))
+
+		require.NoError(t, ibHandler.store.AddActivity(invite))
+		require.NoError(t, ibHandler.store.AddReference(store.Witnessing, service1IRI, service2IRI))
+
+		require.NoError(t, ibHandler.HandleActivity(newUndo()))
+
+		removed := undoHandler.Removed(store.Witnessing)
+		require.Len(t, removed, 1)
+		require.Equal(t, service2IRI.String(), removed[0].String())
+	})
+
+	t.Run("Undo handler error", func(t *testing.T) {
+		errExpected := errors.New("injected undo handler error")
+
+		obHandler := NewOutbox(&Config{ServiceName: "outbox1", ServiceIRI: service2IRI},
+			memstore.New("outbox1"), mocks.NewActorRetriever(),
+			spi.WithUndoHandler(mocks.NewUndoHandler().WithError(errExpected)))
+
+		require.NoError(t, obHandler.store.AddActivity(invite))
+
+		err := obHandler.HandleActivity(newUndo())
+		require.Error(t, err)
+		require.True(t, errors.Is(err, errExpected))
+	})
+}
+
 func TestHandler_AnnounceAnchorCredential(t *testing.T) {
 	log.SetLevel("activitypub_service", log.DEBUG)
 
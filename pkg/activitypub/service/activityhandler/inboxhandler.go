@@ -53,7 +53,7 @@ func NewInbox(cfg *Config, s store.Store, outbox service.Outbox,
 		followersIRI: followersIRI,
 	}
 
-	h.handler = newHandler(cfg, s, activityPubClient,
+	h.handler = newHandler(cfg, s, activityPubClient, options.UndoHandler,
 		func(activity *vocab.ActivityType) error {
 			return h.undoAddReference(activity, store.Follower, func() *url.URL {
 				return activity.Object().IRI()
@@ -164,6 +164,10 @@ func (h *Inbox) handleReferenceActivity(activity *vocab.ActivityType, refType st
 
 	accept, err := auth.AuthorizeActor(actor)
 	if err != nil {
+		if errors.Is(err, service.ErrAuthorizationPending) {
+			return h.addPendingApproval(activity, actor)
+		}
+
 		return fmt.Errorf("authorize actor [%s]: %w", actorIRI, err)
 	}
 
@@ -179,6 +183,100 @@ func (h *Inbox) handleReferenceActivity(activity *vocab.ActivityType, refType st
 	return h.postReject(activity, actorIRI)
 }
 
+func (h *Inbox) addPendingApproval(activity *vocab.ActivityType, actor *vocab.ActorType) error {
+	// Store the actor so that its details are available to the administrator reviewing the request.
+	err := h.store.PutActor(actor)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("unable to store actor [%s]: %w", actor.ID(), err))
+	}
+
+	err = h.store.AddReference(store.PendingApproval, h.ServiceIRI, activity.ID().URL())
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("unable to store pending approval reference: %w", err))
+	}
+
+	logger.Infof("[%s] Request by %s in '%s' activity [%s] is pending approval",
+		h.ServiceName, activity.Actor(), activity.Type(), activity.ID())
+
+	return nil
+}
+
+// ApprovePending accepts the 'Follow' or 'InviteWitness' request with the given activity ID that was parked
+// pending approval. The actor is added to the corresponding collection and an 'Accept' activity is posted.
+func (h *Inbox) ApprovePending(activityID *url.URL) error {
+	activity, refType, err := h.getPendingApproval(activityID)
+	if err != nil {
+		return err
+	}
+
+	actor, err := h.client.GetActor(activity.Actor())
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("unable to retrieve actor [%s]: %w", activity.Actor(), err))
+	}
+
+	logger.Infof("[%s] Pending request by %s in '%s' activity [%s] has been approved",
+		h.ServiceName, actor.ID(), activity.Type(), activity.ID())
+
+	if err := h.acceptActor(activity, actor, refType); err != nil {
+		return err
+	}
+
+	return h.deletePendingApproval(activityID)
+}
+
+// RejectPending rejects the 'Follow' or 'InviteWitness' request with the given activity ID that was parked
+// pending approval. A 'Reject' activity is posted to the actor.
+func (h *Inbox) RejectPending(activityID *url.URL) error {
+	activity, _, err := h.getPendingApproval(activityID)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("[%s] Pending request by %s in '%s' activity [%s] has been rejected",
+		h.ServiceName, activity.Actor(), activity.Type(), activity.ID())
+
+	if err := h.postReject(activity, activity.Actor()); err != nil {
+		return err
+	}
+
+	return h.deletePendingApproval(activityID)
+}
+
+func (h *Inbox) getPendingApproval(activityID *url.URL) (*vocab.ActivityType, store.ReferenceType, error) {
+	pending, err := h.hasReference(h.ServiceIRI, activityID, store.PendingApproval)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !pending {
+		return nil, "", fmt.Errorf("activity [%s] is not pending approval: %w", activityID, store.ErrNotFound)
+	}
+
+	activity, err := h.store.GetActivity(activityID)
+	if err != nil {
+		return nil, "", orberrors.NewTransient(fmt.Errorf("unable to retrieve activity [%s]: %w", activityID, err))
+	}
+
+	switch {
+	case activity.Type().Is(vocab.TypeFollow):
+		return activity, store.Follower, nil
+	case activity.Type().Is(vocab.TypeInvite):
+		return activity, store.Witnessing, nil
+	default:
+		return nil, "", orberrors.NewBadRequest(
+			fmt.Errorf("unsupported activity type for pending approval: %s", activity.Type()))
+	}
+}
+
+func (h *Inbox) deletePendingApproval(activityID *url.URL) error {
+	err := h.store.DeleteReference(store.PendingApproval, h.ServiceIRI, activityID)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("unable to delete pending approval reference: %w", err))
+	}
+
+	return nil
+}
+
 func (h *Inbox) handleFollowActivity(follow *vocab.ActivityType) error {
 	return h.handleReferenceActivity(follow, store.Follower, h.FollowerAuth,
 		func() *url.URL {
@@ -794,7 +892,7 @@ func (h *Inbox) undoAddReference(activity *vocab.ActivityType, refType store.Ref
 	logger.Debugf("[%s] %s (if found) was successfully deleted from %s's collection of %s",
 		h.ServiceIRI, actorIRI, h.ServiceIRI, refType)
 
-	return nil
+	return h.cleanUpUndo(refType, actorIRI)
 }
 
 func (h *Inbox) ensureActivityInOutbox(activity *vocab.ActivityType) (*vocab.ActivityType, error) {
@@ -881,6 +979,12 @@ func (a *acceptAllActorsAuth) AuthorizeActor(*vocab.ActorType) (bool, error) {
 	return true, nil
 }
 
+type noOpUndoHandler struct{}
+
+func (p *noOpUndoHandler) HandleUndo(store.ReferenceType, *url.URL) error {
+	return nil
+}
+
 type noOpProofHandler struct{}
 
 func (p *noOpProofHandler) HandleProof(witness *url.URL, anchorCredID string, endTime time.Time, proof []byte) error { //nolint:lll
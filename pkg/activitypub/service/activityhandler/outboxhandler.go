@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"net/url"
 
+	service "github.com/trustbloc/orb/pkg/activitypub/service/spi"
 	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
@@ -21,10 +22,17 @@ type Outbox struct {
 }
 
 // NewOutbox returns a new ActivityPub outbox activity handler.
-func NewOutbox(cfg *Config, s store.Store, activityPubClient activityPubClient) *Outbox {
+func NewOutbox(cfg *Config, s store.Store, activityPubClient activityPubClient,
+	opts ...service.HandlerOpt) *Outbox {
+	options := defaultOptions()
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	h := &Outbox{}
 
-	h.handler = newHandler(cfg, s, activityPubClient,
+	h.handler = newHandler(cfg, s, activityPubClient, options.UndoHandler,
 		func(follow *vocab.ActivityType) error {
 			return h.undoAddReference(follow, store.Following, func() *url.URL {
 				return follow.Object().IRI()
@@ -102,5 +110,5 @@ func (h *Outbox) undoAddReference(activity *vocab.ActivityType, refType store.Re
 	logger.Debugf("[%s] %s (if found) was successfully deleted from %s's collection of %s",
 		h.ServiceIRI, iri, h.ServiceIRI, refType)
 
-	return nil
+	return h.cleanUpUndo(refType, iri)
 }
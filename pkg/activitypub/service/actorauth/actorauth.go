@@ -0,0 +1,155 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actorauth
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	discoveryrest "github.com/trustbloc/orb/pkg/discovery/endpoint/restapi"
+)
+
+var logger = log.New("activitypub_actorauth")
+
+// Mode specifies what is done with a request from an actor that passes all of the configured checks.
+type Mode string
+
+const (
+	// ModeAccept indicates that a request which passes all checks is accepted immediately.
+	ModeAccept Mode = "accept"
+
+	// ModePending indicates that a request which passes all checks is parked until it is approved or
+	// rejected by an administrator.
+	ModePending Mode = "pending"
+)
+
+// Config holds the configuration for the actor authorizer.
+type Config struct {
+	// Mode specifies what is done with a request that passes all checks. Defaults to ModeAccept.
+	Mode Mode
+
+	// AllowedDomains contains the domains from which requests are allowed. A domain may be prefixed
+	// with "*." in order to match all of its sub-domains. If empty then all domains are allowed
+	// (unless denied).
+	AllowedDomains []string
+
+	// DeniedDomains contains the domains from which requests are always rejected. A domain may be prefixed
+	// with "*." in order to match all of its sub-domains.
+	DeniedDomains []string
+
+	// RequireHostMeta indicates that the actor's domain must publish a host-meta document
+	// with an ActivityPub link that resolves to the actor.
+	RequireHostMeta bool
+}
+
+type hostMetaResolver interface {
+	ResolveHostMetaLink(uri, linkType string) (string, error)
+}
+
+// Authorizer implements an actor authorization handler (for 'Follow' and 'InviteWitness' requests) which
+// checks the actor's domain against allow/deny lists and, optionally, the host-meta document of the domain.
+type Authorizer struct {
+	*Config
+
+	name     string
+	resolver hostMetaResolver
+}
+
+// New returns a new actor authorizer. The given name is used for logging.
+func New(name string, cfg *Config, resolver hostMetaResolver) (*Authorizer, error) {
+	switch cfg.Mode {
+	case "":
+		cfg.Mode = ModeAccept
+	case ModeAccept, ModePending:
+	default:
+		return nil, fmt.Errorf("unsupported actor authorization mode: %s", cfg.Mode)
+	}
+
+	if cfg.RequireHostMeta && resolver == nil {
+		return nil, fmt.Errorf("host-meta resolver is required")
+	}
+
+	return &Authorizer{
+		Config:   cfg,
+		name:     name,
+		resolver: resolver,
+	}, nil
+}
+
+// AuthorizeActor returns true if the request by the given actor is to be accepted and false if it is to be
+// rejected. If the checks pass and the authorizer is in pending mode then spi.ErrAuthorizationPending is returned.
+func (a *Authorizer) AuthorizeActor(actor *vocab.ActorType) (bool, error) {
+	actorIRI := actor.ID().URL()
+	if actorIRI == nil {
+		return false, fmt.Errorf("no ID specified in actor")
+	}
+
+	domain := strings.ToLower(actorIRI.Hostname())
+
+	if matchesDomain(a.DeniedDomains, domain) {
+		logger.Infof("[%s] Rejecting actor [%s] since domain [%s] is denied", a.name, actorIRI, domain)
+
+		return false, nil
+	}
+
+	if len(a.AllowedDomains) > 0 && !matchesDomain(a.AllowedDomains, domain) {
+		logger.Infof("[%s] Rejecting actor [%s] since domain [%s] is not allowed", a.name, actorIRI, domain)
+
+		return false, nil
+	}
+
+	if a.RequireHostMeta {
+		link, err := a.resolver.ResolveHostMetaLink(actorIRI.String(), discoveryrest.ActivityJSONType)
+		if err != nil {
+			logger.Infof("[%s] Rejecting actor [%s] since the host-meta document could not be resolved: %s",
+				a.name, actorIRI, err)
+
+			return false, nil
+		}
+
+		if link != actorIRI.String() {
+			logger.Infof("[%s] Rejecting actor [%s] since the ActivityPub link in the host-meta document [%s]"+
+				" does not match", a.name, actorIRI, link)
+
+			return false, nil
+		}
+	}
+
+	if a.Mode == ModePending {
+		logger.Infof("[%s] Request by actor [%s] requires approval", a.name, actorIRI)
+
+		return false, spi.ErrAuthorizationPending
+	}
+
+	logger.Debugf("[%s] Accepting actor [%s]", a.name, actorIRI)
+
+	return true, nil
+}
+
+func matchesDomain(domains []string, domain string) bool {
+	for _, d := range domains {
+		d = strings.ToLower(d)
+
+		if strings.HasPrefix(d, "*.") {
+			if strings.HasSuffix(domain, d[1:]) {
+				return true
+			}
+
+			continue
+		}
+
+		if d == domain {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,148 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actorauth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("Default mode", func(t *testing.T) {
+		a, err := New("follow", &Config{}, nil)
+		require.NoError(t, err)
+		require.Equal(t, ModeAccept, a.Mode)
+	})
+
+	t.Run("Unsupported mode", func(t *testing.T) {
+		_, err := New("follow", &Config{Mode: "xxx"}, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported actor authorization mode: xxx")
+	})
+
+	t.Run("No host-meta resolver", func(t *testing.T) {
+		_, err := New("follow", &Config{RequireHostMeta: true}, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "host-meta resolver is required")
+	})
+}
+
+func TestAuthorizer_AuthorizeActor(t *testing.T) {
+	actor1 := vocab.NewService(testutil.MustParseURL("https://orb.domain1.com/services/orb"))
+	actor2 := vocab.NewService(testutil.MustParseURL("https://orb.domain2.com/services/orb"))
+	actor3 := vocab.NewService(testutil.MustParseURL("https://sub.orb.domain3.com/services/orb"))
+
+	t.Run("Accept all", func(t *testing.T) {
+		a, err := New("follow", &Config{}, nil)
+		require.NoError(t, err)
+
+		for _, actor := range []*vocab.ActorType{actor1, actor2, actor3} {
+			accept, err := a.AuthorizeActor(actor)
+			require.NoError(t, err)
+			require.True(t, accept)
+		}
+	})
+
+	t.Run("Allowed domains", func(t *testing.T) {
+		a, err := New("follow", &Config{AllowedDomains: []string{"ORB.domain1.com", "*.domain3.com"}}, nil)
+		require.NoError(t, err)
+
+		accept, err := a.AuthorizeActor(actor1)
+		require.NoError(t, err)
+		require.True(t, accept)
+
+		accept, err = a.AuthorizeActor(actor2)
+		require.NoError(t, err)
+		require.False(t, accept)
+
+		accept, err = a.AuthorizeActor(actor3)
+		require.NoError(t, err)
+		require.True(t, accept)
+	})
+
+	t.Run("Denied domains", func(t *testing.T) {
+		a, err := New("follow", &Config{
+			AllowedDomains: []string{"*.com"},
+			DeniedDomains:  []string{"orb.domain2.com"},
+		}, nil)
+		require.NoError(t, err)
+
+		accept, err := a.AuthorizeActor(actor1)
+		require.NoError(t, err)
+		require.True(t, accept)
+
+		accept, err = a.AuthorizeActor(actor2)
+		require.NoError(t, err)
+		require.False(t, accept)
+	})
+
+	t.Run("Host-meta", func(t *testing.T) {
+		resolver := &mockHostMetaResolver{
+			links: map[string]string{
+				actor1.ID().String(): actor1.ID().String(),
+				actor2.ID().String(): "https://orb.domain2.com/services/other",
+			},
+		}
+
+		a, err := New("follow", &Config{RequireHostMeta: true}, resolver)
+		require.NoError(t, err)
+
+		accept, err := a.AuthorizeActor(actor1)
+		require.NoError(t, err)
+		require.True(t, accept)
+
+		accept, err = a.AuthorizeActor(actor2)
+		require.NoError(t, err)
+		require.False(t, accept)
+
+		accept, err = a.AuthorizeActor(actor3)
+		require.NoError(t, err)
+		require.False(t, accept)
+	})
+
+	t.Run("Pending approval", func(t *testing.T) {
+		a, err := New("follow", &Config{Mode: ModePending, DeniedDomains: []string{"orb.domain2.com"}}, nil)
+		require.NoError(t, err)
+
+		accept, err := a.AuthorizeActor(actor1)
+		require.True(t, errors.Is(err, spi.ErrAuthorizationPending))
+		require.False(t, accept)
+
+		// A denied domain is rejected without requiring approval.
+		accept, err = a.AuthorizeActor(actor2)
+		require.NoError(t, err)
+		require.False(t, accept)
+	})
+
+	t.Run("No actor ID", func(t *testing.T) {
+		a, err := New("follow", &Config{}, nil)
+		require.NoError(t, err)
+
+		_, err = a.AuthorizeActor(vocab.NewService(nil))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no ID specified in actor")
+	})
+}
+
+type mockHostMetaResolver struct {
+	links map[string]string
+}
+
+func (m *mockHostMetaResolver) ResolveHostMetaLink(uri, _ string) (string, error) {
+	link, ok := m.links[uri]
+	if !ok {
+		return "", errors.New("not found")
+	}
+
+	return link, nil
+}
@@ -0,0 +1,204 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deadletter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	service "github.com/trustbloc/orb/pkg/activitypub/service/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	store "github.com/trustbloc/orb/pkg/store/deadletter"
+)
+
+var logger = log.New("activitypub_deadletter")
+
+// Criteria selects dead-letter entries. If ID is specified then only the entry with the given ID is
+// selected, otherwise entries are selected by target inbox and/or activity type. Empty fields match all entries.
+type Criteria struct {
+	ID           string
+	Target       string
+	ActivityType string
+}
+
+// Result contains the result of a replay or purge operation.
+type Result struct {
+	// Succeeded is the number of entries that were replayed or purged.
+	Succeeded int `json:"succeeded"`
+
+	// Failed is the number of entries that could not be replayed or purged.
+	Failed int `json:"failed"`
+}
+
+type deadLetterStore interface {
+	Put(activity *vocab.ActivityType, target string) (*store.Entry, error)
+	Get(id string) (*store.Entry, error)
+	Query(criteria *store.Criteria) ([]*store.Entry, error)
+	Delete(id string) error
+	Count() (int, error)
+}
+
+type metricsProvider interface {
+	DeadLetterDepth(value int)
+}
+
+// Handler stores undeliverable activities in a dead-letter store so that they may later be
+// inspected, replayed or purged.
+type Handler struct {
+	store   deadLetterStore
+	metrics metricsProvider
+}
+
+// New returns a new dead-letter handler.
+func New(s deadLetterStore, metrics metricsProvider) *Handler {
+	h := &Handler{
+		store:   s,
+		metrics: metrics,
+	}
+
+	h.updateDepth()
+
+	return h
+}
+
+// HandleUndeliverableActivity adds the given activity, which could not be delivered to the given inbox,
+// to the dead-letter store.
+func (h *Handler) HandleUndeliverableActivity(activity *vocab.ActivityType, toURL string) {
+	entry, err := h.store.Put(activity, toURL)
+	if err != nil {
+		logger.Errorf("Error adding activity [%s] to [%s] to the dead-letter store: %s", activity.ID(), toURL, err)
+
+		return
+	}
+
+	logger.Warnf("Activity [%s] to [%s] was added to the dead-letter store with ID [%s]",
+		activity.ID(), toURL, entry.ID)
+
+	h.updateDepth()
+}
+
+// Get returns the dead-letter entry for the given ID.
+func (h *Handler) Get(id string) (*store.Entry, error) {
+	return h.store.Get(id)
+}
+
+// Query returns the dead-letter entries that match the given criteria.
+func (h *Handler) Query(criteria *Criteria) ([]*store.Entry, error) {
+	if criteria.ID != "" {
+		entry, err := h.store.Get(criteria.ID)
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return nil, nil
+			}
+
+			return nil, err
+		}
+
+		return []*store.Entry{entry}, nil
+	}
+
+	return h.store.Query(&store.Criteria{
+		Target:       criteria.Target,
+		ActivityType: criteria.ActivityType,
+	})
+}
+
+// Replay redelivers the activities of the entries that match the given criteria using the given redeliverer.
+// An entry is removed from the store once its activity has been handed off for delivery. If delivery fails
+// again then the activity is added back to the store as a new entry.
+func (h *Handler) Replay(criteria *Criteria, r service.Redeliverer) (*Result, error) {
+	entries, err := h.Query(criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	defer h.updateDepth()
+
+	result := &Result{}
+
+	for _, entry := range entries {
+		if err := replay(entry, r); err != nil {
+			logger.Warnf("Error replaying dead-letter entry [%s] for activity [%s] to [%s]: %s",
+				entry.ID, entry.ActivityID, entry.Target, err)
+
+			result.Failed++
+
+			continue
+		}
+
+		if err := h.store.Delete(entry.ID); err != nil {
+			logger.Warnf("Error deleting replayed dead-letter entry [%s]: %s", entry.ID, err)
+		}
+
+		logger.Infof("Replayed dead-letter entry [%s] for activity [%s] to [%s]",
+			entry.ID, entry.ActivityID, entry.Target)
+
+		result.Succeeded++
+	}
+
+	return result, nil
+}
+
+// Purge deletes the entries that match the given criteria.
+func (h *Handler) Purge(criteria *Criteria) (*Result, error) {
+	entries, err := h.Query(criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	defer h.updateDepth()
+
+	result := &Result{}
+
+	for _, entry := range entries {
+		if err := h.store.Delete(entry.ID); err != nil {
+			logger.Warnf("Error purging dead-letter entry [%s]: %s", entry.ID, err)
+
+			result.Failed++
+
+			continue
+		}
+
+		logger.Infof("Purged dead-letter entry [%s] for activity [%s] to [%s]",
+			entry.ID, entry.ActivityID, entry.Target)
+
+		result.Succeeded++
+	}
+
+	return result, nil
+}
+
+func (h *Handler) updateDepth() {
+	count, err := h.store.Count()
+	if err != nil {
+		logger.Warnf("Error getting the number of dead-letter entries: %s", err)
+
+		return
+	}
+
+	h.metrics.DeadLetterDepth(count)
+}
+
+func replay(entry *store.Entry, r service.Redeliverer) error {
+	activity := &vocab.ActivityType{}
+
+	err := json.Unmarshal(entry.Activity, activity)
+	if err != nil {
+		return fmt.Errorf("unmarshal activity: %w", err)
+	}
+
+	inboxURL, err := url.Parse(entry.Target)
+	if err != nil {
+		return fmt.Errorf("parse target: %w", err)
+	}
+
+	return r.Redeliver(activity, inboxURL)
+}
@@ -0,0 +1,213 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deadletter
+
+import (
+	"errors"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	store "github.com/trustbloc/orb/pkg/store/deadletter"
+)
+
+const (
+	inbox1 = "https://orb.domain2.com/services/orb/inbox"
+	inbox2 = "https://orb.domain3.com/services/orb/inbox"
+)
+
+func TestHandler(t *testing.T) {
+	serviceIRI := testutil.MustParseURL("https://orb.domain1.com/services/orb")
+
+	follow := vocab.NewFollowActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("https://orb.domain2.com/services/orb"))),
+		vocab.WithID(testutil.NewMockID(serviceIRI, "/activities/follow")),
+		vocab.WithActor(serviceIRI),
+	)
+
+	announce := vocab.NewAnnounceActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("https://orb.domain1.com/vc/1"))),
+		vocab.WithID(testutil.NewMockID(serviceIRI, "/activities/announce")),
+		vocab.WithActor(serviceIRI),
+	)
+
+	newHandler := func(t *testing.T) (*Handler, *mockMetrics) {
+		t.Helper()
+
+		s, err := store.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		metrics := &mockMetrics{}
+
+		h := New(s, metrics)
+		require.NotNil(t, h)
+
+		h.HandleUndeliverableActivity(follow, inbox1)
+		h.HandleUndeliverableActivity(announce, inbox1)
+		h.HandleUndeliverableActivity(announce, inbox2)
+
+		require.Equal(t, 3, metrics.Depth())
+
+		return h, metrics
+	}
+
+	t.Run("Query", func(t *testing.T) {
+		h, _ := newHandler(t)
+
+		entries, err := h.Query(&Criteria{})
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+
+		entries, err = h.Query(&Criteria{Target: inbox1})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+
+		entry, err := h.Get(entries[0].ID)
+		require.NoError(t, err)
+		require.Equal(t, entries[0].ActivityID, entry.ActivityID)
+
+		entries, err = h.Query(&Criteria{ID: entry.ID})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+
+		entries, err = h.Query(&Criteria{ID: "unknown"})
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		h, metrics := newHandler(t)
+
+		r := &mockRedeliverer{}
+
+		result, err := h.Replay(&Criteria{ActivityType: string(vocab.TypeAnnounce)}, r)
+		require.NoError(t, err)
+		require.Equal(t, 2, result.Succeeded)
+		require.Equal(t, 0, result.Failed)
+		require.Len(t, r.redelivered, 2)
+		require.Equal(t, 1, metrics.Depth())
+
+		r.err = errors.New("injected redeliver error")
+
+		result, err = h.Replay(&Criteria{}, r)
+		require.NoError(t, err)
+		require.Equal(t, 0, result.Succeeded)
+		require.Equal(t, 1, result.Failed)
+		require.Equal(t, 1, metrics.Depth())
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		h, metrics := newHandler(t)
+
+		result, err := h.Purge(&Criteria{Target: inbox1})
+		require.NoError(t, err)
+		require.Equal(t, 2, result.Succeeded)
+		require.Equal(t, 1, metrics.Depth())
+
+		entries, err := h.Query(&Criteria{})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, inbox2, entries[0].Target)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		s := &mockStore{err: errExpected}
+		metrics := &mockMetrics{}
+
+		h := New(s, metrics)
+
+		h.HandleUndeliverableActivity(follow, inbox1)
+		require.Equal(t, 0, metrics.Depth())
+
+		_, err := h.Query(&Criteria{ID: "id"})
+		require.True(t, errors.Is(err, errExpected))
+
+		_, err = h.Replay(&Criteria{}, &mockRedeliverer{})
+		require.True(t, errors.Is(err, errExpected))
+
+		_, err = h.Purge(&Criteria{})
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Invalid entry", func(t *testing.T) {
+		h := New(&mockStore{entries: []*store.Entry{{ID: "id", Activity: []byte("{")}}}, &mockMetrics{})
+
+		result, err := h.Replay(&Criteria{}, &mockRedeliverer{})
+		require.NoError(t, err)
+		require.Equal(t, 1, result.Failed)
+
+		result, err = h.Purge(&Criteria{})
+		require.NoError(t, err)
+		require.Equal(t, 1, result.Succeeded)
+	})
+}
+
+type mockRedeliverer struct {
+	redelivered []*vocab.ActivityType
+	err         error
+}
+
+func (m *mockRedeliverer) Redeliver(activity *vocab.ActivityType, _ *url.URL) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.redelivered = append(m.redelivered, activity)
+
+	return nil
+}
+
+type mockMetrics struct {
+	mutex sync.Mutex
+	depth int
+}
+
+func (m *mockMetrics) DeadLetterDepth(value int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.depth = value
+}
+
+func (m *mockMetrics) Depth() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.depth
+}
+
+type mockStore struct {
+	entries []*store.Entry
+	err     error
+}
+
+func (m *mockStore) Put(*vocab.ActivityType, string) (*store.Entry, error) {
+	return nil, m.err
+}
+
+func (m *mockStore) Get(string) (*store.Entry, error) {
+	return nil, m.err
+}
+
+func (m *mockStore) Query(*store.Criteria) ([]*store.Entry, error) {
+	return m.entries, m.err
+}
+
+func (m *mockStore) Delete(string) error {
+	return m.err
+}
+
+func (m *mockStore) Count() (int, error) {
+	return len(m.entries), m.err
+}
@@ -0,0 +1,281 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/deadletter"
+	service "github.com/trustbloc/orb/pkg/activitypub/service/spi"
+	store "github.com/trustbloc/orb/pkg/store/deadletter"
+)
+
+const (
+	endpoint       = "/deadletter"
+	entryEndpoint  = endpoint + "/{" + idPathVariable + "}"
+	replayEndpoint = endpoint + "/replay"
+
+	idPathVariable = "id"
+
+	idParam           = "id"
+	targetParam       = "target"
+	activityTypeParam = "type"
+	allParam          = "all"
+)
+
+const (
+	badRequestResponse          = "Bad Request."
+	notFoundResponse            = "Not Found."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+var logger = log.New("deadletter-rest-handler")
+
+type deadLetterHandler interface {
+	Get(id string) (*store.Entry, error)
+	Query(criteria *deadletter.Criteria) ([]*store.Entry, error)
+	Replay(criteria *deadletter.Criteria, r service.Redeliverer) (*deadletter.Result, error)
+	Purge(criteria *deadletter.Criteria) (*deadletter.Result, error)
+}
+
+// List returns the dead-letter entries, optionally filtered by target inbox and activity type.
+type List struct {
+	handler deadLetterHandler
+}
+
+// NewList returns a new handler that lists dead-letter entries.
+func NewList(handler deadLetterHandler) *List {
+	return &List{handler: handler}
+}
+
+// Path returns the HTTP REST endpoint for the list service.
+func (h *List) Path() string {
+	return endpoint
+}
+
+// Method returns the HTTP REST method for the list service.
+func (h *List) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the list service.
+func (h *List) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *List) handle(w http.ResponseWriter, req *http.Request) {
+	entries, err := h.handler.Query(getCriteria(req))
+	if err != nil {
+		logger.Errorf("[%s] Error querying dead-letter entries: %s", endpoint, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if entries == nil {
+		entries = []*store.Entry{}
+	}
+
+	writeJSONResponse(w, entries)
+}
+
+// Get returns the dead-letter entry for a given ID.
+type Get struct {
+	handler deadLetterHandler
+}
+
+// NewGet returns a new handler that returns a dead-letter entry.
+func NewGet(handler deadLetterHandler) *Get {
+	return &Get{handler: handler}
+}
+
+// Path returns the HTTP REST endpoint for the get service.
+func (h *Get) Path() string {
+	return entryEndpoint
+}
+
+// Method returns the HTTP REST method for the get service.
+func (h *Get) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the get service.
+func (h *Get) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Get) handle(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)[idPathVariable]
+
+	entry, err := h.handler.Get(id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			logger.Debugf("[%s] Dead-letter entry [%s] not found", entryEndpoint, id)
+
+			writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error retrieving dead-letter entry [%s]: %s", entryEndpoint, id, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeJSONResponse(w, entry)
+}
+
+// Replay redelivers the activities of the dead-letter entries that match the criteria in the request.
+type Replay struct {
+	handler     deadLetterHandler
+	redeliverer service.Redeliverer
+}
+
+// NewReplay returns a new handler that replays dead-letter entries through the given redeliverer (outbox).
+func NewReplay(handler deadLetterHandler, r service.Redeliverer) *Replay {
+	return &Replay{handler: handler, redeliverer: r}
+}
+
+// Path returns the HTTP REST endpoint for the replay service.
+func (h *Replay) Path() string {
+	return replayEndpoint
+}
+
+// Method returns the HTTP REST method for the replay service.
+func (h *Replay) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the replay service.
+func (h *Replay) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Replay) handle(w http.ResponseWriter, req *http.Request) {
+	result, err := h.handler.Replay(getCriteria(req), h.redeliverer)
+	if err != nil {
+		logger.Errorf("[%s] Error replaying dead-letter entries: %s", replayEndpoint, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	logger.Infof("[%s] Replayed %d dead-letter entries, %d failed", replayEndpoint, result.Succeeded, result.Failed)
+
+	writeJSONResponse(w, result)
+}
+
+// Purge deletes the dead-letter entries that match the criteria in the request. At least one criterion
+// must be specified unless the 'all' parameter is set to true.
+type Purge struct {
+	handler deadLetterHandler
+}
+
+// NewPurge returns a new handler that purges dead-letter entries.
+func NewPurge(handler deadLetterHandler) *Purge {
+	return &Purge{handler: handler}
+}
+
+// Path returns the HTTP REST endpoint for the purge service.
+func (h *Purge) Path() string {
+	return endpoint
+}
+
+// Method returns the HTTP REST method for the purge service.
+func (h *Purge) Method() string {
+	return http.MethodDelete
+}
+
+// Handler returns the HTTP REST handle for the purge service.
+func (h *Purge) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Purge) handle(w http.ResponseWriter, req *http.Request) {
+	criteria := getCriteria(req)
+
+	if *criteria == (deadletter.Criteria{}) {
+		all, err := strconv.ParseBool(getParam(req, allParam))
+		if err != nil || !all {
+			logger.Infof("[%s] Either a criterion or '%s=true' must be specified", endpoint, allParam)
+
+			writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+			return
+		}
+	}
+
+	result, err := h.handler.Purge(criteria)
+	if err != nil {
+		logger.Errorf("[%s] Error purging dead-letter entries: %s", endpoint, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	logger.Infof("[%s] Purged %d dead-letter entries, %d failed", endpoint, result.Succeeded, result.Failed)
+
+	writeJSONResponse(w, result)
+}
+
+func getCriteria(req *http.Request) *deadletter.Criteria {
+	return &deadletter.Criteria{
+		ID:           getParam(req, idParam),
+		Target:       getParam(req, targetParam),
+		ActivityType: getParam(req, activityTypeParam),
+	}
+}
+
+func getParam(req *http.Request, name string) string {
+	values := req.URL.Query()[name]
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+func writeJSONResponse(w http.ResponseWriter, v interface{}) {
+	respBytes, err := json.Marshal(v)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling response: %s", endpoint, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", endpoint, err)
+
+			return
+		}
+
+		logger.Debugf("[%s] Wrote response: %s", endpoint, body)
+	}
+}
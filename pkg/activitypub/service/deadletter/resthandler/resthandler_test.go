@@ -0,0 +1,244 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/deadletter"
+	service "github.com/trustbloc/orb/pkg/activitypub/service/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+	store "github.com/trustbloc/orb/pkg/store/deadletter"
+)
+
+const (
+	inbox1 = "https://orb.domain2.com/services/orb/inbox"
+	inbox2 = "https://orb.domain3.com/services/orb/inbox"
+)
+
+func TestNew(t *testing.T) {
+	h := &mockHandler{}
+
+	list := NewList(h)
+	require.Equal(t, endpoint, list.Path())
+	require.Equal(t, http.MethodGet, list.Method())
+	require.NotNil(t, list.Handler())
+
+	get := NewGet(h)
+	require.Equal(t, "/deadletter/{id}", get.Path())
+	require.Equal(t, http.MethodGet, get.Method())
+	require.NotNil(t, get.Handler())
+
+	replay := NewReplay(h, &mockRedeliverer{})
+	require.Equal(t, "/deadletter/replay", replay.Path())
+	require.Equal(t, http.MethodPost, replay.Method())
+	require.NotNil(t, replay.Handler())
+
+	purge := NewPurge(h)
+	require.Equal(t, endpoint, purge.Path())
+	require.Equal(t, http.MethodDelete, purge.Method())
+	require.NotNil(t, purge.Handler())
+}
+
+func TestHandlers(t *testing.T) {
+	serviceIRI := testutil.MustParseURL("https://orb.domain1.com/services/orb")
+
+	follow := vocab.NewFollowActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("https://orb.domain2.com/services/orb"))),
+		vocab.WithID(testutil.NewMockID(serviceIRI, "/activities/follow")),
+		vocab.WithActor(serviceIRI),
+	)
+
+	newHandler := func(t *testing.T) *deadletter.Handler {
+		t.Helper()
+
+		s, err := store.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		h := deadletter.New(s, &orbmocks.MetricsProvider{})
+
+		h.HandleUndeliverableActivity(follow, inbox1)
+		h.HandleUndeliverableActivity(follow, inbox2)
+
+		return h
+	}
+
+	t.Run("List", func(t *testing.T) {
+		h := newHandler(t)
+
+		rw := httptest.NewRecorder()
+
+		NewList(h).handle(rw, httptest.NewRequest(http.MethodGet, endpoint+"?target="+url.QueryEscape(inbox1), nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		var entries []*store.Entry
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&entries))
+		require.NoError(t, result.Body.Close())
+		require.Len(t, entries, 1)
+		require.Equal(t, inbox1, entries[0].Target)
+	})
+
+	t.Run("List - empty", func(t *testing.T) {
+		h := newHandler(t)
+
+		rw := httptest.NewRecorder()
+
+		NewList(h).handle(rw, httptest.NewRequest(http.MethodGet, endpoint+"?type=Announce", nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		respBytes := rw.Body.String()
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, "[]", respBytes)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		h := newHandler(t)
+
+		entries, err := h.Query(&deadletter.Criteria{})
+		require.NoError(t, err)
+		require.NotEmpty(t, entries)
+
+		rw := httptest.NewRecorder()
+
+		req := httptest.NewRequest(http.MethodGet, endpoint+"/"+entries[0].ID, nil)
+		req = mux.SetURLVars(req, map[string]string{idPathVariable: entries[0].ID})
+
+		NewGet(h).handle(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		entry := &store.Entry{}
+		require.NoError(t, json.NewDecoder(result.Body).Decode(entry))
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, entries[0].ID, entry.ID)
+	})
+
+	t.Run("Get - not found", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		req := httptest.NewRequest(http.MethodGet, endpoint+"/unknown", nil)
+		req = mux.SetURLVars(req, map[string]string{idPathVariable: "unknown"})
+
+		NewGet(newHandler(t)).handle(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusNotFound, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		h := newHandler(t)
+		r := &mockRedeliverer{}
+
+		rw := httptest.NewRecorder()
+
+		NewReplay(h, r).handle(rw, httptest.NewRequest(http.MethodPost, replayEndpoint, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		res := &deadletter.Result{}
+		require.NoError(t, json.NewDecoder(result.Body).Decode(res))
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, 2, res.Succeeded)
+		require.Len(t, r.inboxes, 2)
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		h := newHandler(t)
+
+		rw := httptest.NewRecorder()
+
+		NewPurge(h).handle(rw, httptest.NewRequest(http.MethodDelete, endpoint+"?all=true", nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		res := &deadletter.Result{}
+		require.NoError(t, json.NewDecoder(result.Body).Decode(res))
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, 2, res.Succeeded)
+	})
+
+	t.Run("Purge - no criteria", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		NewPurge(newHandler(t)).handle(rw, httptest.NewRequest(http.MethodDelete, endpoint, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Handler errors", func(t *testing.T) {
+		h := &mockHandler{err: errors.New("injected error")}
+
+		for _, test := range []struct {
+			handle func(w http.ResponseWriter, req *http.Request)
+			req    *http.Request
+		}{
+			{NewList(h).handle, httptest.NewRequest(http.MethodGet, endpoint, nil)},
+			{NewGet(h).handle, httptest.NewRequest(http.MethodGet, endpoint+"/id", nil)},
+			{NewReplay(h, &mockRedeliverer{}).handle, httptest.NewRequest(http.MethodPost, replayEndpoint, nil)},
+			{NewPurge(h).handle, httptest.NewRequest(http.MethodDelete, endpoint+"?id=id", nil)},
+		} {
+			rw := httptest.NewRecorder()
+
+			test.handle(rw, test.req)
+
+			result := rw.Result()
+			require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+			require.NoError(t, result.Body.Close())
+		}
+	})
+}
+
+type mockRedeliverer struct {
+	inboxes []*url.URL
+}
+
+func (m *mockRedeliverer) Redeliver(_ *vocab.ActivityType, inboxURL *url.URL) error {
+	m.inboxes = append(m.inboxes, inboxURL)
+
+	return nil
+}
+
+type mockHandler struct {
+	err error
+}
+
+func (m *mockHandler) Get(string) (*store.Entry, error) {
+	return nil, m.err
+}
+
+func (m *mockHandler) Query(*deadletter.Criteria) ([]*store.Entry, error) {
+	return nil, m.err
+}
+
+func (m *mockHandler) Replay(*deadletter.Criteria, service.Redeliverer) (*deadletter.Result, error) {
+	return nil, m.err
+}
+
+func (m *mockHandler) Purge(*deadletter.Criteria) (*deadletter.Result, error) {
+	return nil, m.err
+}
@@ -0,0 +1,57 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocks
+
+import (
+	"net/url"
+	"sync"
+
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+)
+
+// UndoHandler implements a mock undo handler.
+type UndoHandler struct {
+	mutex   sync.Mutex
+	removed map[store.ReferenceType][]*url.URL
+	err     error
+}
+
+// NewUndoHandler returns a mock undo handler.
+func NewUndoHandler() *UndoHandler {
+	return &UndoHandler{
+		removed: make(map[store.ReferenceType][]*url.URL),
+	}
+}
+
+// WithError injects an error.
+func (m *UndoHandler) WithError(err error) *UndoHandler {
+	m.err = err
+
+	return m
+}
+
+// HandleUndo records the removed actor and returns any injected error.
+func (m *UndoHandler) HandleUndo(refType store.ReferenceType, actorIRI *url.URL) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+
+	m.removed[refType] = append(m.removed[refType], actorIRI)
+
+	return nil
+}
+
+// Removed returns the actors that were removed from the given collection.
+func (m *UndoHandler) Removed(refType store.ReferenceType) []*url.URL {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.removed[refType]
+}
@@ -261,6 +261,33 @@ func (h *Outbox) Post(activity *vocab.ActivityType) (*url.URL, error) {
 	return activity.ID().URL(), nil
 }
 
+// Redeliver publishes the given activity to the given inbox. This is used to replay an activity which
+// previously could not be delivered. If delivery fails then the activity is handled in the same way as
+// any other undeliverable activity.
+func (h *Outbox) Redeliver(activity *vocab.ActivityType, inboxURL *url.URL) error {
+	if h.State() != lifecycle.StateStarted {
+		return lifecycle.ErrNotStarted
+	}
+
+	if activity.ID() == nil {
+		return orberrors.NewBadRequest(fmt.Errorf("activity ID is required"))
+	}
+
+	activityBytes, err := h.jsonMarshal(activity)
+	if err != nil {
+		return orberrors.NewBadRequest(fmt.Errorf("marshal: %w", err))
+	}
+
+	logger.Debugf("[%s] Redelivering activity [%s] to [%s]", h.ServiceName, activity.ID(), inboxURL)
+
+	err = h.publish(activity.ID().String(), activityBytes, inboxURL)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("unable to publish activity to inbox %s: %w", inboxURL, err))
+	}
+
+	return nil
+}
+
 func (h *Outbox) storeActivity(activity *vocab.ActivityType) error {
 	if err := h.activityStore.AddActivity(activity); err != nil {
 		return fmt.Errorf("store activity: %w", err)
@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strconv"
 	"sync"
@@ -547,6 +548,65 @@ func TestOutbox_PostError(t *testing.T) {
 	})
 }
 
+func TestOutbox_Redeliver(t *testing.T) {
+	service1URL := testutil.MustParseURL("http://localhost:8002/services/service1")
+
+	var mutex sync.RWMutex
+
+	activitiesReceived := make(map[string]*vocab.ActivityType)
+
+	inboxServer := httptest.NewServer(http.HandlerFunc(mockInboxHandler(t, func(activity *vocab.ActivityType) {
+		mutex.Lock()
+		activitiesReceived[activity.ID().String()] = activity
+		mutex.Unlock()
+	})))
+	defer inboxServer.Close()
+
+	cfg := &Config{
+		ServiceName: "service1",
+		ServiceIRI:  service1URL,
+		Topic:       "activities",
+	}
+
+	ob, err := New(cfg, memstore.New(cfg.ServiceName), mocks.NewPubSub(), transport.Default(),
+		&mocks.ActivityHandler{}, client.New(client.Config{}, transport.Default()), &mocks.WebFingerResolver{},
+		&orbmocks.MetricsProvider{})
+	require.NoError(t, err)
+
+	activity := vocab.NewFollowActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL(inboxServer.URL))),
+		vocab.WithID(testutil.NewMockID(service1URL, "/activities/follow")),
+		vocab.WithActor(service1URL),
+	)
+
+	inboxURL := testutil.MustParseURL(inboxServer.URL)
+
+	t.Run("Not started", func(t *testing.T) {
+		require.True(t, errors.Is(ob.Redeliver(activity, inboxURL), lifecycle.ErrNotStarted))
+	})
+
+	ob.Start()
+	defer ob.Stop()
+
+	t.Run("Success", func(t *testing.T) {
+		require.NoError(t, ob.Redeliver(activity, inboxURL))
+
+		time.Sleep(250 * time.Millisecond)
+
+		mutex.RLock()
+		_, ok := activitiesReceived[activity.ID().String()]
+		mutex.RUnlock()
+
+		require.True(t, ok)
+	})
+
+	t.Run("No activity ID", func(t *testing.T) {
+		err := ob.Redeliver(vocab.NewFollowActivity(vocab.NewObjectProperty(vocab.WithIRI(inboxURL))), inboxURL)
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+	})
+}
+
 func TestDeduplicate(t *testing.T) {
 	service1URL := testutil.MustParseURL("http://localhost:8002/services/service1")
 	service2URL := testutil.MustParseURL("http://localhost:8002/services/service2")
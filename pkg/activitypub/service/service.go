@@ -61,6 +61,7 @@ type Service struct {
 	inbox           *inbox.Inbox
 	outbox          *outbox.Outbox
 	activityHandler spi.ActivityHandler
+	approvals       spi.PendingApprovals
 }
 
 type httpTransport interface {
@@ -98,7 +99,7 @@ func New(cfg *Config, activityStore store.Store, t httpTransport, sigVerifier si
 			BufferSize:  cfg.ActivityHandlerBufferSize,
 			ServiceIRI:  cfg.ServiceIRI,
 		},
-		activityStore, activityPubClient)
+		activityStore, activityPubClient, handlerOpts...)
 
 	ob, err := outbox.New(
 		&outbox.Config{
@@ -141,6 +142,7 @@ func New(cfg *Config, activityStore store.Store, t httpTransport, sigVerifier si
 		inbox:           ib,
 		outbox:          ob,
 		activityHandler: inboxHandler,
+		approvals:       inboxHandler,
 	}
 
 	s.Lifecycle = lifecycle.New(cfg.ServiceEndpoint,
@@ -168,6 +170,17 @@ func (s *Service) Outbox() spi.Outbox {
 	return s.outbox
 }
 
+// Redeliverer returns the handler that redelivers activities to individual inboxes.
+func (s *Service) Redeliverer() spi.Redeliverer {
+	return s.outbox
+}
+
+// PendingApprovals returns the handler that approves or rejects 'Follow' and 'InviteWitness' requests
+// which are pending approval.
+func (s *Service) PendingApprovals() spi.PendingApprovals {
+	return s.approvals
+}
+
 // InboxHTTPHandler returns the HTTP handler for the inbox which is invoked by the HTTP server.
 // This handler must be registered with an HTTP server.
 func (s *Service) InboxHTTPHandler() common.HTTPHandler {
@@ -7,9 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package spi
 
 import (
+	"errors"
 	"net/url"
 	"time"
 
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 	"github.com/trustbloc/orb/pkg/lifecycle"
 )
@@ -32,6 +34,12 @@ type Outbox interface {
 	Post(activity *vocab.ActivityType) (*url.URL, error)
 }
 
+// Redeliverer redelivers an activity to a single inbox.
+type Redeliverer interface {
+	// Redeliver publishes the given activity to the given inbox.
+	Redeliver(activity *vocab.ActivityType, inboxURL *url.URL) error
+}
+
 // Inbox defines the functions for an ActivityPub inbox.
 type Inbox interface {
 	ServiceLifecycle
@@ -42,12 +50,25 @@ type AnchorCredentialHandler interface {
 	HandleAnchorCredential(id *url.URL, cid string, anchorCred []byte) error
 }
 
+// ErrAuthorizationPending is returned by ActorAuth if the decision of whether or not to accept the request
+// is deferred. The request is parked until it is approved or rejected via PendingApprovals.
+var ErrAuthorizationPending = errors.New("authorization pending approval")
+
 // ActorAuth makes the decision of whether or not a request by the given
 // actor should be accepted.
 type ActorAuth interface {
 	AuthorizeActor(actor *vocab.ActorType) (bool, error)
 }
 
+// PendingApprovals approves or rejects 'Follow' and 'InviteWitness' requests that were parked
+// because the authorization was pending.
+type PendingApprovals interface {
+	// ApprovePending accepts the parked request with the given activity ID.
+	ApprovePending(activityID *url.URL) error
+	// RejectPending rejects the parked request with the given activity ID.
+	RejectPending(activityID *url.URL) error
+}
+
 // WitnessHandler is a handler that witnesses an anchor credential.
 type WitnessHandler interface {
 	Witness(anchorCred []byte) ([]byte, error)
@@ -58,6 +79,12 @@ type ProofHandler interface {
 	HandleProof(witness *url.URL, anchorCredID string, endTime time.Time, proof []byte) error
 }
 
+// UndoHandler is notified after an actor has been removed from one of the local service's collections
+// (e.g. witnesses) as the result of an 'Undo' so that any state which depends on the actor may be cleaned up.
+type UndoHandler interface {
+	HandleUndo(refType store.ReferenceType, actorIRI *url.URL) error
+}
+
 // ActivityHandler defines the functions of an Activity handler.
 type ActivityHandler interface {
 	ServiceLifecycle
@@ -82,6 +109,7 @@ type Handlers struct {
 	WitnessInvitationAuth   ActorAuth
 	Witness                 WitnessHandler
 	ProofHandler            ProofHandler
+	UndoHandler             UndoHandler
 }
 
 // HandlerOpt sets a specific handler.
@@ -128,3 +156,10 @@ func WithProofHandler(handler ProofHandler) HandlerOpt {
 		options.ProofHandler = handler
 	}
 }
+
+// WithUndoHandler sets the handler that cleans up dependent state after a 'Follow' or 'InviteWitness' is undone.
+func WithUndoHandler(handler UndoHandler) HandlerOpt {
+	return func(options *Handlers) {
+		options.UndoHandler = handler
+	}
+}
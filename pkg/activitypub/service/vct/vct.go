@@ -120,7 +120,6 @@ func (c *Client) addProof(anchorCred []byte, timestamp int64) (*verifiable.Crede
 
 	opts := []vcsigner.Opt{
 		vcsigner.WithCreated(time.Unix(0, timestamp)),
-		vcsigner.WithSignatureRepresentation(verifiable.SignatureJWS),
 	}
 
 	if c.endpoint != "" {
@@ -153,9 +152,11 @@ func (c *Client) Witness(anchorCred []byte) ([]byte, error) { // nolint: funlen,
 
 		c.metrics.WitnessAddProofVctNil(time.Since(addProofStartTime))
 
+		proof := vc.Proofs[len(vc.Proofs)-1] // gets the latest proof
+
 		return json.Marshal(Proof{
-			Context: []string{ctxSecurity, ctxJWS},
-			Proof:   vc.Proofs[len(vc.Proofs)-1], // gets the latest proof
+			Context: proofContext(proof),
+			Proof:   proof,
 		})
 	}
 
@@ -227,11 +228,28 @@ func (c *Client) Witness(anchorCred []byte) ([]byte, error) { // nolint: funlen,
 	c.metrics.WitnessVerifyVCTSignature(time.Since(verifyVCTStartTime))
 
 	return json.Marshal(Proof{
-		Context: []string{ctxSecurity, ctxJWS},
+		Context: proofContext(proof),
 		Proof:   proof,
 	})
 }
 
+// proofContext returns the contexts of the witness proof, including the context of the signature suite
+// if it isn't defined by the security contexts.
+func proofContext(proof verifiable.Proof) []string {
+	ctx := []string{ctxSecurity, ctxJWS}
+
+	proofType, ok := proof["type"].(string)
+	if !ok {
+		return ctx
+	}
+
+	if suiteCtx := vcsigner.Context(proofType); suiteCtx != "" {
+		ctx = append(ctx, suiteCtx)
+	}
+
+	return ctx
+}
+
 // Proof represents response.
 type Proof struct {
 	Context []string         `json:"@context"`
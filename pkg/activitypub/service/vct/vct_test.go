@@ -109,6 +109,20 @@ func TestClient_Witness(t *testing.T) {
 
 		require.NotEmpty(t, timestampTime.UnixNano())
 	})
+	t.Run("Success (suite context)", func(t *testing.T) {
+		client := New("", &mockSigner{ProofType: vcsigner.Ed25519Signature2020}, &mocks.MetricsProvider{},
+			WithDocumentLoader(testutil.GetLoader(t)))
+
+		resp, err := client.Witness([]byte(mockVC))
+		require.NoError(t, err)
+
+		var p Proof
+		require.NoError(t, json.Unmarshal(resp, &p))
+
+		require.Len(t, p.Context, 3)
+		require.Equal(t, vcsigner.Context(vcsigner.Ed25519Signature2020), p.Context[2])
+		require.Equal(t, vcsigner.Ed25519Signature2020, p.Proof["type"])
+	})
 	t.Run("Parse credential (error)", func(t *testing.T) {
 		client := New("", &mockSigner{}, &mocks.MetricsProvider{})
 
@@ -232,7 +246,8 @@ func TestClient_Witness(t *testing.T) {
 }
 
 type mockSigner struct {
-	Err error
+	Err       error
+	ProofType string
 }
 
 func (m *mockSigner) Sign(vc *verifiable.Credential, opts ...vcsigner.Opt) (*verifiable.Credential, error) {
@@ -246,10 +261,16 @@ func (m *mockSigner) Sign(vc *verifiable.Credential, opts ...vcsigner.Opt) (*ver
 		opt(ctx)
 	}
 
-	vc.Proofs = append(vc.Proofs, map[string]interface{}{
+	proof := map[string]interface{}{
 		"created": ctx.Created.Format(time.RFC3339Nano),
 		"domain":  ctx.Domain,
-	})
+	}
+
+	if m.ProofType != "" {
+		proof["type"] = m.ProofType
+	}
+
+	vc.Proofs = append(vc.Proofs, proof)
 
 	return vc, nil
 }
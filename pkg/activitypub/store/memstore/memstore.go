@@ -47,6 +47,7 @@ func New(serviceName string) *Store {
 			spi.Liked:            newReferenceStore(),
 			spi.Share:            newReferenceStore(),
 			spi.AnchorCredential: newReferenceStore(),
+			spi.PendingApproval:  newReferenceStore(),
 		},
 		actorStore: make(map[string]*vocab.ActorType),
 	}
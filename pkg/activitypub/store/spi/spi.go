@@ -48,6 +48,9 @@ const (
 	Share ReferenceType = "SHARE"
 	// AnchorCredential indicates that the reference is an anchor credential.
 	AnchorCredential ReferenceType = "ANCHOR_CRED"
+	// PendingApproval indicates that the reference is a 'Follow' or 'InviteWitness' activity in the
+	// service's inbox which is waiting to be approved or rejected.
+	PendingApproval ReferenceType = "PENDING_APPROVAL"
 )
 
 // Store defines the functions of an ActivityPub store.
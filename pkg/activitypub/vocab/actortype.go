@@ -36,16 +36,17 @@ type ActorType struct {
 }
 
 type actorType struct {
-	PublicKey  *PublicKeyType `json:"publicKey"`
-	Inbox      *URLProperty   `json:"inbox"`
-	Outbox     *URLProperty   `json:"outbox"`
-	Followers  *URLProperty   `json:"followers"`
-	Following  *URLProperty   `json:"following"`
-	Witnesses  *URLProperty   `json:"witnesses"`
-	Witnessing *URLProperty   `json:"witnessing"`
-	Liked      *URLProperty   `json:"liked"`
-	Likes      *URLProperty   `json:"likes"`
-	Shares     *URLProperty   `json:"shares"`
+	PublicKey  *PublicKeyType   `json:"publicKey"`
+	PublicKeys []*PublicKeyType `json:"publicKeys,omitempty"`
+	Inbox      *URLProperty     `json:"inbox"`
+	Outbox     *URLProperty     `json:"outbox"`
+	Followers  *URLProperty     `json:"followers"`
+	Following  *URLProperty     `json:"following"`
+	Witnesses  *URLProperty     `json:"witnesses"`
+	Witnessing *URLProperty     `json:"witnessing"`
+	Liked      *URLProperty     `json:"liked"`
+	Likes      *URLProperty     `json:"likes"`
+	Shares     *URLProperty     `json:"shares"`
 }
 
 // PublicKey returns the actor's public key.
@@ -53,6 +54,20 @@ func (t *ActorType) PublicKey() *PublicKeyType {
 	return t.actor.PublicKey
 }
 
+// PublicKeys returns all of the actor's public keys. During a key rotation, the actor publishes the previous
+// key(s) along with the current key (which is returned by PublicKey).
+func (t *ActorType) PublicKeys() []*PublicKeyType {
+	if len(t.actor.PublicKeys) > 0 {
+		return t.actor.PublicKeys
+	}
+
+	if t.actor.PublicKey == nil {
+		return nil
+	}
+
+	return []*PublicKeyType{t.actor.PublicKey}
+}
+
 // Inbox returns the URL of the actor's inbox.
 func (t *ActorType) Inbox() *url.URL {
 	if t.actor.Inbox == nil {
@@ -141,6 +156,7 @@ func NewService(id *url.URL, opts ...Opt) *ActorType {
 		),
 		actor: &actorType{
 			PublicKey:  options.PublicKey,
+			PublicKeys: options.PublicKeys,
 			Inbox:      NewURLProperty(options.Inbox),
 			Outbox:     NewURLProperty(options.Outbox),
 			Followers:  NewURLProperty(options.Followers),
@@ -77,6 +77,7 @@ func TestActor(t *testing.T) {
 		require.Equal(t, keyID.String(), key.ID.String())
 		require.Equal(t, serviceIRI.String(), key.Owner.String())
 		require.Equal(t, keyPem, key.PublicKeyPem)
+		require.Len(t, a.PublicKeys(), 1)
 
 		in := a.Inbox()
 		require.NotNil(t, in)
@@ -107,6 +108,29 @@ func TestActor(t *testing.T) {
 		require.Equal(t, liked.String(), lkd.String())
 	})
 
+	t.Run("Multiple public keys", func(t *testing.T) {
+		publicKey2 := NewPublicKey(
+			WithID(testutil.NewMockID(serviceIRI, "/keys/key2")),
+			WithOwner(serviceIRI),
+			WithPublicKeyPem(keyPem),
+		)
+
+		service := NewService(serviceIRI,
+			WithPublicKey(publicKey2),
+			WithPublicKeys(publicKey2, publicKey),
+		)
+
+		bytes, err := json.Marshal(service)
+		require.NoError(t, err)
+
+		a := &ActorType{}
+		require.NoError(t, json.Unmarshal(bytes, a))
+		require.Equal(t, publicKey2.ID.String(), a.PublicKey().ID.String())
+		require.Len(t, a.PublicKeys(), 2)
+		require.Equal(t, publicKey2.ID.String(), a.PublicKeys()[0].ID.String())
+		require.Equal(t, keyID.String(), a.PublicKeys()[1].ID.String())
+	})
+
 	t.Run("Empty actor", func(t *testing.T) {
 		a := NewService(serviceIRI)
 
@@ -116,6 +140,7 @@ func TestActor(t *testing.T) {
 
 		require.NotNil(t, a.Context())
 		require.Nil(t, a.PublicKey())
+		require.Empty(t, a.PublicKeys())
 		require.Nil(t, a.Inbox())
 		require.Nil(t, a.Outbox())
 		require.Nil(t, a.Followers())
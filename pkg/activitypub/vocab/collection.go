@@ -43,17 +43,17 @@ func (t *CollectionType) Items() []*ObjectProperty {
 
 // Current returns the current item.
 func (t *CollectionType) Current() *url.URL {
-	return t.coll.Current.u
+	return t.coll.Current.URL()
 }
 
 // First returns a URL that may be used to retrieve the first item in the collection.
 func (t *CollectionType) First() *url.URL {
-	return t.coll.First.u
+	return t.coll.First.URL()
 }
 
 // Last returns a URL that may be used to retrieve the last item in the collection.
 func (t *CollectionType) Last() *url.URL {
-	return t.coll.Last.u
+	return t.coll.Last.URL()
 }
 
 // NewCollection returns a new collection.
@@ -258,6 +258,7 @@ func WithResult(result *ObjectProperty) Opt {
 // ActorOptions holds the options for an Activity.
 type ActorOptions struct {
 	PublicKey  *PublicKeyType
+	PublicKeys []*PublicKeyType
 	Inbox      *url.URL
 	Outbox     *url.URL
 	Followers  *url.URL
@@ -276,6 +277,13 @@ func WithPublicKey(publicKey *PublicKeyType) Opt {
 	}
 }
 
+// WithPublicKeys sets the 'publicKeys' property on the actor.
+func WithPublicKeys(publicKeys ...*PublicKeyType) Opt {
+	return func(opts *Options) {
+		opts.PublicKeys = publicKeys
+	}
+}
+
 // WithInbox sets the 'inbox' property on the actor.
 func WithInbox(inbox *url.URL) Opt {
 	return func(opts *Options) {
@@ -17,6 +17,7 @@ import (
 
 	"github.com/trustbloc/orb/pkg/anchor/util"
 	"github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/vcsigner"
 )
 
 var logger = log.New("anchor-graph")
@@ -81,9 +82,19 @@ func (g *Graph) Read(hl string) (*verifiable.Credential, error) {
 
 	logger.Debugf("read anchor[%s]: %s", hl, string(anchorBytes))
 
-	return verifiable.ParseCredential(anchorBytes,
-		verifiable.WithPublicKeyFetcher(g.Pkf),
+	vc, err := verifiable.ParseCredential(anchorBytes,
+		verifiable.WithDisabledProofCheck(),
 		verifiable.WithJSONLDDocumentLoader(g.DocLoader))
+	if err != nil {
+		return nil, err
+	}
+
+	err = vcsigner.VerifyProofs(anchorBytes, g.Pkf, g.DocLoader)
+	if err != nil {
+		return nil, err
+	}
+
+	return vc, nil
 }
 
 // Anchor contains anchor info plus corresponding hl.
@@ -0,0 +1,359 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/piprate/json-gold/ld"
+
+	"github.com/trustbloc/orb/pkg/anchor/util"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/vcsigner"
+)
+
+// ExportFormat specifies the format in which the anchor graph is exported.
+type ExportFormat string
+
+const (
+	// ExportFormatJSONL exports each anchor as a JSON object on a separate line (JSON Lines).
+	ExportFormatJSONL ExportFormat = "jsonl"
+	// ExportFormatDOT exports the anchor graph as a Graphviz DOT digraph.
+	ExportFormatDOT ExportFormat = "dot"
+)
+
+// Node is an anchor in the anchor graph along with the results of verifying the anchor.
+type Node struct {
+	Hashlink     string     `json:"hashlink"`
+	ResourceHash string     `json:"resourceHash,omitempty"`
+	ID           string     `json:"id,omitempty"`
+	Issuer       string     `json:"issuer,omitempty"`
+	Issued       *time.Time `json:"issued,omitempty"`
+	Namespace    string     `json:"namespace,omitempty"`
+	Version      uint64     `json:"version,omitempty"`
+	Suffixes     []string   `json:"suffixes,omitempty"`
+	Previous     []string   `json:"previous,omitempty"`
+	Errors       []string   `json:"errors,omitempty"`
+}
+
+// Verified returns true if the anchor passed all verification checks.
+func (n *Node) Verified() bool {
+	return len(n.Errors) == 0
+}
+
+// VerificationResult contains the results of verifying the anchor graph.
+type VerificationResult struct {
+	Total  int     `json:"total"`
+	Failed []*Node `json:"failed,omitempty"`
+}
+
+type hashlinkReader interface {
+	Read(hl string) ([]byte, error)
+}
+
+// Walker walks the anchor graph starting at an anchor and following all previous anchor links back to genesis.
+// Each anchor is verified along the way, i.e. the content is checked against the resource hash in the hashlink
+// and the proofs of the anchor credential are verified.
+type Walker struct {
+	reader            hashlinkReader
+	hl                *hashlink.HashLink
+	pkf               verifiable.PublicKeyFetcher
+	docLoader         ld.DocumentLoader
+	disableProofCheck bool
+}
+
+// WalkerOption is an option for the anchor graph walker.
+type WalkerOption func(opts *Walker)
+
+// WithPublicKeyFetcher sets the public key fetcher used to verify anchor credential proofs.
+func WithPublicKeyFetcher(pkf verifiable.PublicKeyFetcher) WalkerOption {
+	return func(opts *Walker) {
+		opts.pkf = pkf
+	}
+}
+
+// WithJSONLDDocumentLoader sets the JSON-LD document loader used to parse anchor credentials.
+func WithJSONLDDocumentLoader(docLoader ld.DocumentLoader) WalkerOption {
+	return func(opts *Walker) {
+		opts.docLoader = docLoader
+	}
+}
+
+// WithDisableProofCheck disables verification of anchor credential proofs.
+func WithDisableProofCheck(disableProofCheck bool) WalkerOption {
+	return func(opts *Walker) {
+		opts.disableProofCheck = disableProofCheck
+	}
+}
+
+// NewWalker returns a new anchor graph walker which reads anchors using the given reader.
+func NewWalker(reader hashlinkReader, opts ...WalkerOption) *Walker {
+	w := &Walker{
+		reader: reader,
+		hl:     hashlink.New(),
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// Walk visits every anchor that is reachable from the given hashlink (including the anchor itself)
+// in breadth-first order. Each anchor is visited exactly once. Verification failures are reported in the
+// node and do not stop the walk, although the previous anchors of an anchor that could not be read or
+// parsed can't be followed. The walk stops if the visit function returns an error.
+func (w *Walker) Walk(hl string, visit func(node *Node) error) error {
+	visited := map[string]bool{w.key(hl): true}
+	queue := []string{hl}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		node := w.readNode(cur)
+
+		for _, prev := range node.Previous {
+			key := w.key(prev)
+
+			if !visited[key] {
+				visited[key] = true
+
+				queue = append(queue, prev)
+			}
+		}
+
+		if err := visit(node); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Verify walks the anchor graph starting at the given hashlink and returns the anchors that failed verification.
+func (w *Walker) Verify(hl string) (*VerificationResult, error) {
+	result := &VerificationResult{}
+
+	err := w.Walk(hl, func(node *Node) error {
+		result.Total++
+
+		if !node.Verified() {
+			logger.Debugf("Anchor [%s] failed verification: %s", node.Hashlink, node.Errors)
+
+			result.Failed = append(result.Failed, node)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Export walks the anchor graph starting at the given hashlink and writes the graph to the given writer
+// in the given format.
+func (w *Walker) Export(hl string, format ExportFormat, out io.Writer) error {
+	switch format {
+	case ExportFormatJSONL:
+		return w.exportJSONL(hl, out)
+	case ExportFormatDOT:
+		return w.exportDOT(hl, out)
+	default:
+		return fmt.Errorf("unsupported export format [%s]", format)
+	}
+}
+
+func (w *Walker) exportJSONL(hl string, out io.Writer) error {
+	encoder := json.NewEncoder(out)
+
+	return w.Walk(hl, func(node *Node) error {
+		if err := encoder.Encode(node); err != nil {
+			return fmt.Errorf("write anchor [%s]: %w", node.Hashlink, err)
+		}
+
+		return nil
+	})
+}
+
+func (w *Walker) exportDOT(hl string, out io.Writer) error {
+	if _, err := fmt.Fprintln(out, "digraph anchors {"); err != nil {
+		return fmt.Errorf("write graph header: %w", err)
+	}
+
+	err := w.Walk(hl, func(node *Node) error {
+		id := strconv.Quote(w.key(node.Hashlink))
+
+		attrs := fmt.Sprintf("label=%s", strconv.Quote(node.label()))
+		if !node.Verified() {
+			attrs += ", color=red"
+		}
+
+		if _, err := fmt.Fprintf(out, "  %s [%s];\n", id, attrs); err != nil {
+			return fmt.Errorf("write anchor [%s]: %w", node.Hashlink, err)
+		}
+
+		for _, prev := range node.Previous {
+			if _, err := fmt.Fprintf(out, "  %s -> %s;\n", id, strconv.Quote(w.key(prev))); err != nil {
+				return fmt.Errorf("write link from anchor [%s]: %w", node.Hashlink, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintln(out, "}"); err != nil {
+		return fmt.Errorf("write graph footer: %w", err)
+	}
+
+	return nil
+}
+
+func (w *Walker) readNode(hl string) *Node {
+	node := &Node{Hashlink: hl}
+
+	info, err := w.hl.ParseHashLink(hl)
+	if err != nil {
+		node.addError("parse hashlink: %s", err)
+
+		return node
+	}
+
+	node.ResourceHash = info.ResourceHash
+
+	anchorBytes, err := w.reader.Read(hl)
+	if err != nil {
+		node.addError("read anchor: %s", err)
+
+		return node
+	}
+
+	resourceHash, err := w.hl.CreateResourceHash(anchorBytes)
+	if err != nil {
+		node.addError("create resource hash: %s", err)
+	} else if resourceHash != info.ResourceHash {
+		node.addError("hashlink integrity: resource hash of content [%s] does not match hashlink", resourceHash)
+	}
+
+	vc, err := w.parseCredential(node, anchorBytes)
+	if err != nil {
+		node.addError("parse anchor credential: %s", err)
+
+		return node
+	}
+
+	node.ID = vc.ID
+	node.Issuer = vc.Issuer.ID
+
+	if vc.Issued != nil {
+		issued := vc.Issued.Time
+
+		node.Issued = &issued
+	}
+
+	payload, err := util.GetAnchorSubject(vc)
+	if err != nil {
+		node.addError("get anchor subject: %s", err)
+
+		return node
+	}
+
+	node.Namespace = payload.Namespace
+	node.Version = payload.Version
+	node.Suffixes, node.Previous = getSuffixesAndPrevious(payload.PreviousAnchors)
+
+	return node
+}
+
+// parseCredential parses the anchor credential and verifies its proofs. If proof verification fails then
+// the error is added to the node and the credential is parsed again without a proof check so that
+// the walk can continue to the previous anchors.
+func (w *Walker) parseCredential(node *Node, anchorBytes []byte) (*verifiable.Credential, error) {
+	var opts []verifiable.CredentialOpt
+
+	if w.docLoader != nil {
+		opts = append(opts, verifiable.WithJSONLDDocumentLoader(w.docLoader))
+	}
+
+	vc, err := verifiable.ParseCredential(anchorBytes, append(opts, verifiable.WithDisabledProofCheck())...)
+	if err != nil {
+		return nil, err
+	}
+
+	if w.disableProofCheck {
+		return vc, nil
+	}
+
+	err = vcsigner.VerifyProofs(anchorBytes, w.pkf, w.docLoader)
+	if err != nil {
+		node.addError("verify proof: %s", err)
+	}
+
+	return vc, nil
+}
+
+// key returns the key that uniquely identifies the anchor referenced by the given hashlink. Hashlinks
+// for the same anchor may contain different metadata (links) so the resource hash is used if possible.
+func (w *Walker) key(hl string) string {
+	info, err := w.hl.ParseHashLink(hl)
+	if err != nil {
+		return hl
+	}
+
+	return info.ResourceHash
+}
+
+func (n *Node) addError(format string, args ...interface{}) {
+	n.Errors = append(n.Errors, fmt.Sprintf(format, args...))
+}
+
+func (n *Node) label() string {
+	label := n.ResourceHash
+	if label == "" {
+		label = n.Hashlink
+	}
+
+	if n.ID != "" {
+		label = n.ID + "\n" + label
+	}
+
+	return label
+}
+
+func getSuffixesAndPrevious(previousAnchors map[string]string) ([]string, []string) {
+	var suffixes, previous []string
+
+	added := make(map[string]bool)
+
+	for suffix, prev := range previousAnchors {
+		suffixes = append(suffixes, suffix)
+
+		// An empty previous anchor means that the suffix was created in this anchor.
+		if prev != "" && !added[prev] {
+			added[prev] = true
+
+			previous = append(previous, prev)
+		}
+	}
+
+	sort.Strings(suffixes)
+	sort.Strings(previous)
+
+	return suffixes, previous
+}
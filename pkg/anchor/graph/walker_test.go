@@ -0,0 +1,341 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	"github.com/stretchr/testify/require"
+
+	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
+	"github.com/trustbloc/orb/pkg/anchor/subject"
+	casresolver "github.com/trustbloc/orb/pkg/cas/resolver"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/store/cas"
+	webfingerclient "github.com/trustbloc/orb/pkg/webfinger/client"
+)
+
+func TestWalker_Walk(t *testing.T) {
+	graph, reader := newTestGraph(t)
+
+	hl1 := addAnchor(t, graph, map[string]string{"a": "", "b": ""})
+	hl2 := addAnchor(t, graph, map[string]string{"a": hl1})
+	hl3 := addAnchor(t, graph, map[string]string{"a": hl2, "b": hl1, "c": ""})
+
+	t.Run("success", func(t *testing.T) {
+		w := NewWalker(reader, WithJSONLDDocumentLoader(testutil.GetLoader(t)))
+
+		var nodes []*Node
+
+		require.NoError(t, w.Walk(hl3, func(node *Node) error {
+			nodes = append(nodes, node)
+
+			return nil
+		}))
+
+		require.Len(t, nodes, 3)
+
+		require.Equal(t, hl3, nodes[0].Hashlink)
+		require.Equal(t, []string{"a", "b", "c"}, nodes[0].Suffixes)
+		require.Len(t, nodes[0].Previous, 2)
+		require.Equal(t, testNS, nodes[0].Namespace)
+		require.NotNil(t, nodes[0].Issued)
+
+		require.Equal(t, hl1, nodes[2].Hashlink)
+		require.Empty(t, nodes[2].Previous)
+
+		for _, node := range nodes {
+			require.True(t, node.Verified(), node.Errors)
+		}
+	})
+
+	t.Run("visit error", func(t *testing.T) {
+		w := NewWalker(reader, WithJSONLDDocumentLoader(testutil.GetLoader(t)))
+
+		count := 0
+
+		err := w.Walk(hl3, func(node *Node) error {
+			count++
+
+			return errors.New("injected visit error")
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected visit error")
+		require.Equal(t, 1, count)
+	})
+}
+
+func TestWalker_Verify(t *testing.T) {
+	graph, reader := newTestGraph(t)
+
+	hl1 := addAnchor(t, graph, map[string]string{"a": ""})
+	hl2 := addAnchor(t, graph, map[string]string{"a": hl1})
+
+	t.Run("success", func(t *testing.T) {
+		w := NewWalker(reader, WithJSONLDDocumentLoader(testutil.GetLoader(t)))
+
+		result, err := w.Verify(hl2)
+		require.NoError(t, err)
+		require.Equal(t, 2, result.Total)
+		require.Empty(t, result.Failed)
+	})
+
+	t.Run("hashlink integrity failure", func(t *testing.T) {
+		tamperedReader := &mockReader{
+			reader: reader,
+			modify: map[string]func([]byte) []byte{
+				hl1: func(b []byte) []byte {
+					return bytes.Replace(b, []byte("coreIndex"), []byte("tampered"), 1)
+				},
+			},
+		}
+
+		w := NewWalker(tamperedReader, WithJSONLDDocumentLoader(testutil.GetLoader(t)))
+
+		result, err := w.Verify(hl2)
+		require.NoError(t, err)
+		require.Equal(t, 2, result.Total)
+		require.Len(t, result.Failed, 1)
+		require.Equal(t, hl1, result.Failed[0].Hashlink)
+		require.Contains(t, result.Failed[0].Errors[0], "hashlink integrity")
+	})
+
+	t.Run("proof failure", func(t *testing.T) {
+		proofReader := &mockReader{
+			reader: reader,
+			modify: map[string]func([]byte) []byte{
+				hl2: addProof(t),
+			},
+		}
+
+		pkf := func(issuerID, keyID string) (*verifier.PublicKey, error) {
+			return nil, errors.New("injected key fetcher error")
+		}
+
+		w := NewWalker(proofReader,
+			WithJSONLDDocumentLoader(testutil.GetLoader(t)),
+			WithPublicKeyFetcher(pkf),
+		)
+
+		result, err := w.Verify(hl2)
+		require.NoError(t, err)
+		require.Equal(t, 2, result.Total)
+		require.Len(t, result.Failed, 1)
+		require.Equal(t, hl2, result.Failed[0].Hashlink)
+
+		errs := strings.Join(result.Failed[0].Errors, ",")
+		require.Contains(t, errs, "verify proof")
+		require.Contains(t, errs, "hashlink integrity")
+
+		// The previous anchors are still followed.
+		require.Equal(t, []string{hl1}, result.Failed[0].Previous)
+
+		w = NewWalker(proofReader,
+			WithJSONLDDocumentLoader(testutil.GetLoader(t)),
+			WithPublicKeyFetcher(pkf),
+			WithDisableProofCheck(true),
+		)
+
+		result, err = w.Verify(hl2)
+		require.NoError(t, err)
+		require.Len(t, result.Failed, 1)
+		require.Len(t, result.Failed[0].Errors, 1)
+		require.Contains(t, result.Failed[0].Errors[0], "hashlink integrity")
+	})
+
+	t.Run("previous anchor not found", func(t *testing.T) {
+		hl := addAnchor(t, graph, map[string]string{"a": hashlink.GetHashLinkFromResourceHash(nonExistent)})
+
+		w := NewWalker(reader, WithJSONLDDocumentLoader(testutil.GetLoader(t)))
+
+		result, err := w.Verify(hl)
+		require.NoError(t, err)
+		require.Equal(t, 2, result.Total)
+		require.Len(t, result.Failed, 1)
+		require.Contains(t, result.Failed[0].Errors[0], "read anchor")
+	})
+
+	t.Run("invalid hashlink", func(t *testing.T) {
+		w := NewWalker(reader)
+
+		result, err := w.Verify("invalid")
+		require.NoError(t, err)
+		require.Equal(t, 1, result.Total)
+		require.Len(t, result.Failed, 1)
+		require.Contains(t, result.Failed[0].Errors[0], "parse hashlink")
+	})
+
+	t.Run("invalid anchor credential", func(t *testing.T) {
+		invalidReader := &mockReader{
+			reader: reader,
+			modify: map[string]func([]byte) []byte{
+				hl2: func([]byte) []byte { return []byte("{}") },
+			},
+		}
+
+		w := NewWalker(invalidReader, WithJSONLDDocumentLoader(testutil.GetLoader(t)))
+
+		result, err := w.Verify(hl2)
+		require.NoError(t, err)
+		require.Equal(t, 1, result.Total)
+		require.Len(t, result.Failed, 1)
+		require.Contains(t, strings.Join(result.Failed[0].Errors, ","), "parse anchor credential")
+	})
+}
+
+func TestWalker_Export(t *testing.T) {
+	graph, reader := newTestGraph(t)
+
+	hl1 := addAnchor(t, graph, map[string]string{"a": "", "b": ""})
+	hl2 := addAnchor(t, graph, map[string]string{"a": hl1})
+	hl3 := addAnchor(t, graph, map[string]string{"a": hl2, "b": hl1})
+
+	w := NewWalker(reader, WithJSONLDDocumentLoader(testutil.GetLoader(t)))
+
+	t.Run("JSON Lines", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		require.NoError(t, w.Export(hl3, ExportFormatJSONL, out))
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 3)
+
+		node := &Node{}
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), node))
+		require.Equal(t, hl3, node.Hashlink)
+	})
+
+	t.Run("DOT", func(t *testing.T) {
+		out := &bytes.Buffer{}
+
+		require.NoError(t, w.Export(hl3, ExportFormatDOT, out))
+
+		dot := out.String()
+		require.True(t, strings.HasPrefix(dot, "digraph anchors {\n"))
+		require.True(t, strings.HasSuffix(dot, "}\n"))
+		require.Equal(t, 3, strings.Count(dot, "->"))
+		require.Equal(t, 3, strings.Count(dot, "label="))
+		require.NotContains(t, dot, "color=red")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		err := w.Export(hl3, "xml", &bytes.Buffer{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported export format")
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		err := w.Export(hl3, ExportFormatJSONL, &mockWriter{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected write error")
+
+		err = w.Export(hl3, ExportFormatDOT, &mockWriter{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected write error")
+	})
+}
+
+func newTestGraph(t *testing.T) (*Graph, *casResolverReader) {
+	t.Helper()
+
+	casClient, err := cas.New(mem.NewProvider(), casLink, nil, &metricsProvider{}, 0)
+	require.NoError(t, err)
+
+	resolver := casresolver.New(casClient, nil,
+		casresolver.NewWebCASResolver(
+			&apmocks.HTTPTransport{}, webfingerclient.New(), "https"),
+		&metricsProvider{})
+
+	graph := New(&Providers{
+		CasWriter:   casClient,
+		CasResolver: resolver,
+		Pkf:         pubKeyFetcherFnc,
+		DocLoader:   testutil.GetLoader(t),
+	})
+
+	return graph, &casResolverReader{resolver: resolver}
+}
+
+func addAnchor(t *testing.T, graph *Graph, previousAnchors map[string]string) string {
+	t.Helper()
+
+	c, err := buildCredential(&subject.Payload{
+		OperationCount:  uint64(len(previousAnchors)),
+		CoreIndex:       "coreIndex",
+		Namespace:       testNS,
+		Version:         1,
+		PreviousAnchors: previousAnchors,
+	})
+	require.NoError(t, err)
+
+	hl, err := graph.Add(c)
+	require.NoError(t, err)
+
+	return hl
+}
+
+func addProof(t *testing.T) func([]byte) []byte {
+	t.Helper()
+
+	return func(b []byte) []byte {
+		doc := make(map[string]interface{})
+		require.NoError(t, json.Unmarshal(b, &doc))
+
+		doc["proof"] = map[string]interface{}{
+			"type":               "Ed25519Signature2018",
+			"created":            "2021-08-10T16:52:38.453Z",
+			"proofPurpose":       "assertionMethod",
+			"verificationMethod": "did:web:orb.domain.com#key1",
+			"jws":                "eyJhbGciOiJFZERTQSIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19..invalid",
+		}
+
+		docBytes, err := json.Marshal(doc)
+		require.NoError(t, err)
+
+		return docBytes
+	}
+}
+
+type casResolverReader struct {
+	resolver *casresolver.Resolver
+}
+
+func (r *casResolverReader) Read(hl string) ([]byte, error) {
+	return r.resolver.Resolve(nil, hl, nil)
+}
+
+type mockReader struct {
+	reader *casResolverReader
+	modify map[string]func([]byte) []byte
+}
+
+func (r *mockReader) Read(hl string) ([]byte, error) {
+	b, err := r.reader.Read(hl)
+	if err != nil {
+		return nil, err
+	}
+
+	if modify, ok := r.modify[hl]; ok {
+		return modify(b), nil
+	}
+
+	return b, nil
+}
+
+type mockWriter struct{}
+
+func (w *mockWriter) Write([]byte) (int, error) {
+	return 0, fmt.Errorf("injected write error")
+}
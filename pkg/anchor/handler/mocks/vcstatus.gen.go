@@ -20,6 +20,18 @@ type VCStatusStore struct {
 	addStatusReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ClaimOutcomeStub        func(string, proofa.VCStatus) error
+	claimOutcomeMutex       sync.RWMutex
+	claimOutcomeArgsForCall []struct {
+		arg1 string
+		arg2 proofa.VCStatus
+	}
+	claimOutcomeReturns struct {
+		result1 error
+	}
+	claimOutcomeReturnsOnCall map[int]struct {
+		result1 error
+	}
 	GetStatusStub        func(string) (proofa.VCStatus, error)
 	getStatusMutex       sync.RWMutex
 	getStatusArgsForCall []struct {
@@ -98,6 +110,67 @@ func (fake *VCStatusStore) AddStatusReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *VCStatusStore) ClaimOutcome(arg1 string, arg2 proofa.VCStatus) error {
+	fake.claimOutcomeMutex.Lock()
+	ret, specificReturn := fake.claimOutcomeReturnsOnCall[len(fake.claimOutcomeArgsForCall)]
+	fake.claimOutcomeArgsForCall = append(fake.claimOutcomeArgsForCall, struct {
+		arg1 string
+		arg2 proofa.VCStatus
+	}{arg1, arg2})
+	fake.recordInvocation("ClaimOutcome", []interface{}{arg1, arg2})
+	fake.claimOutcomeMutex.Unlock()
+	if fake.ClaimOutcomeStub != nil {
+		return fake.ClaimOutcomeStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.claimOutcomeReturns
+	return fakeReturns.result1
+}
+
+func (fake *VCStatusStore) ClaimOutcomeCallCount() int {
+	fake.claimOutcomeMutex.RLock()
+	defer fake.claimOutcomeMutex.RUnlock()
+	return len(fake.claimOutcomeArgsForCall)
+}
+
+func (fake *VCStatusStore) ClaimOutcomeCalls(stub func(string, proofa.VCStatus) error) {
+	fake.claimOutcomeMutex.Lock()
+	defer fake.claimOutcomeMutex.Unlock()
+	fake.ClaimOutcomeStub = stub
+}
+
+func (fake *VCStatusStore) ClaimOutcomeArgsForCall(i int) (string, proofa.VCStatus) {
+	fake.claimOutcomeMutex.RLock()
+	defer fake.claimOutcomeMutex.RUnlock()
+	argsForCall := fake.claimOutcomeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *VCStatusStore) ClaimOutcomeReturns(result1 error) {
+	fake.claimOutcomeMutex.Lock()
+	defer fake.claimOutcomeMutex.Unlock()
+	fake.ClaimOutcomeStub = nil
+	fake.claimOutcomeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *VCStatusStore) ClaimOutcomeReturnsOnCall(i int, result1 error) {
+	fake.claimOutcomeMutex.Lock()
+	defer fake.claimOutcomeMutex.Unlock()
+	fake.ClaimOutcomeStub = nil
+	if fake.claimOutcomeReturnsOnCall == nil {
+		fake.claimOutcomeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.claimOutcomeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *VCStatusStore) GetStatus(arg1 string) (proofa.VCStatus, error) {
 	fake.getStatusMutex.Lock()
 	ret, specificReturn := fake.getStatusReturnsOnCall[len(fake.getStatusArgsForCall)]
@@ -166,6 +239,8 @@ func (fake *VCStatusStore) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.addStatusMutex.RLock()
 	defer fake.addStatusMutex.RUnlock()
+	fake.claimOutcomeMutex.RLock()
+	defer fake.claimOutcomeMutex.RUnlock()
 	fake.getStatusMutex.RLock()
 	defer fake.getStatusMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
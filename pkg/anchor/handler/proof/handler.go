@@ -9,6 +9,7 @@ package proof
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"time"
@@ -21,6 +22,8 @@ import (
 	"github.com/trustbloc/orb/pkg/activitypub/service/vct"
 	proofapi "github.com/trustbloc/orb/pkg/anchor/proof"
 	"github.com/trustbloc/orb/pkg/anchor/vcpubsub"
+	"github.com/trustbloc/orb/pkg/store/vcstatus"
+	"github.com/trustbloc/orb/pkg/vcsigner"
 )
 
 var logger = log.New("proof-handler")
@@ -75,6 +78,7 @@ type vcStore interface {
 type vcStatusStore interface {
 	AddStatus(vcID string, status proofapi.VCStatus) error
 	GetStatus(vcID string) (proofapi.VCStatus, error)
+	ClaimOutcome(vcID string, status proofapi.VCStatus) error
 }
 
 type monitoringSvc interface {
@@ -112,6 +116,14 @@ func (h *WitnessProofHandler) HandleProof(witness *url.URL, anchorCredID string,
 		return nil
 	}
 
+	if status == proofapi.VCStatusFailed {
+		logger.Infof("Received proof from [%s] but anchorCredID[%s] has failed and its operations were re-queued",
+			witness, anchorCredID)
+
+		// the anchor credential was abandoned after the witness time-out - nothing to do
+		return nil
+	}
+
 	var witnessProof vct.Proof
 
 	err = json.Unmarshal(proof, &witnessProof)
@@ -119,6 +131,13 @@ func (h *WitnessProofHandler) HandleProof(witness *url.URL, anchorCredID string,
 		return fmt.Errorf("failed to unmarshal incoming witness proof for anchor credential[%s]: %w", anchorCredID, err)
 	}
 
+	proofType, _ := witnessProof.Proof["type"].(string)
+
+	if !vcsigner.IsSupported(proofType) {
+		return fmt.Errorf("unsupported signature suite [%s] in witness proof for anchor credential[%s]",
+			proofType, anchorCredID)
+	}
+
 	vc, err := h.VCStore.Get(anchorCredID)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve anchor credential[%s]: %w", anchorCredID, err)
@@ -137,6 +156,29 @@ func (h *WitnessProofHandler) HandleProof(witness *url.URL, anchorCredID string,
 	return h.handleWitnessPolicy(vc)
 }
 
+// EvaluateWitnessPolicy re-evaluates the witness policy for the given anchor credential (for example, after
+// the pending offer to a removed witness was deleted) and, if the policy is now satisfied, publishes the anchor
+// credential.
+func (h *WitnessProofHandler) EvaluateWitnessPolicy(anchorCredID string) error {
+	status, err := h.VCStatusStore.GetStatus(anchorCredID)
+	if err != nil {
+		return fmt.Errorf("failed to get status for anchor credential[%s]: %w", anchorCredID, err)
+	}
+
+	if status == proofapi.VCStatusCompleted || status == proofapi.VCStatusFailed {
+		logger.Debugf("Anchor credential[%s] has status '%s' - nothing to do", anchorCredID, status)
+
+		return nil
+	}
+
+	vc, err := h.VCStore.Get(anchorCredID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve anchor credential[%s]: %w", anchorCredID, err)
+	}
+
+	return h.handleWitnessPolicy(vc)
+}
+
 func (h *WitnessProofHandler) setupMonitoring(wp vct.Proof, vc *verifiable.Credential, endTime time.Time) error {
 	var created string
 	if createdVal, ok := wp.Proof["created"].(string); ok {
@@ -198,8 +240,28 @@ func (h *WitnessProofHandler) handleWitnessPolicy(vc *verifiable.Credential) err
 		return nil
 	}
 
+	if status == proofapi.VCStatusFailed {
+		logger.Infof("VC status has been marked as failed for [%s] - not publishing", vc.ID)
+
+		return nil
+	}
+
+	// Claim the 'witnessed' outcome before publishing so that the witness time-out 'fail' action (which claims the
+	// 'failed' outcome before re-queuing the operations) doesn't abandon a VC that is being published.
+	err = h.VCStatusStore.ClaimOutcome(vc.ID, proofapi.VCStatusWitnessed)
+	if err != nil {
+		if errors.Is(err, vcstatus.ErrOutcomeClaimed) {
+			logger.Infof("VC [%s] was not published: %s", vc.ID, err)
+
+			return nil
+		}
+
+		return fmt.Errorf("failed to claim '%s' outcome for credential[%s]: %w",
+			proofapi.VCStatusWitnessed, vc.ID, err)
+	}
+
 	// Publish the VC before setting the status to completed since, if the publisher returns a transient error,
-	// then this handler would be invoked on another server instance. So, we want the status to remain in-process,
+	// then this handler would be invoked on another server instance. So, we want the status to remain witnessed,
 	// otherwise the handler on the other instance would not publish the VC because it would think that is has
 	// already been processed.
 	logger.Debugf("Publishing VC [%s]", vc.ID)
@@ -9,6 +9,7 @@ package proof
 import (
 	"fmt"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -159,6 +160,12 @@ func TestWitnessProofHandler(t *testing.T) {
 		err = proofHandler.HandleProof(witnessIRI, anchorVC.ID,
 			expiryTime, []byte(witnessProof))
 		require.NoError(t, err)
+
+		history, err := vcStatusStore.GetStatusHistory(anchorVC.ID)
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+		require.Equal(t, proofapi.VCStatusWitnessed, history[1].Status)
+		require.Equal(t, proofapi.VCStatusCompleted, history[2].Status)
 	})
 
 	t.Run("success - vc status is completed", func(t *testing.T) {
@@ -196,6 +203,41 @@ func TestWitnessProofHandler(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("success - vc status is failed", func(t *testing.T) {
+		vcStore, err := vcstore.New(mem.NewProvider(), testutil.GetLoader(t))
+		require.NoError(t, err)
+
+		anchorVC, err := verifiable.ParseCredential(
+			[]byte(anchorCredTwoProofs),
+			verifiable.WithJSONLDDocumentLoader(testutil.GetLoader(t)),
+			verifiable.WithDisabledProofCheck())
+		require.NoError(t, err)
+
+		err = vcStore.Put(anchorVC)
+		require.NoError(t, err)
+
+		vcStatusStore, err := vcstatus.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		err = vcStatusStore.AddStatus(anchorVC.ID, proofapi.VCStatusFailed)
+		require.NoError(t, err)
+
+		providers := &Providers{
+			VCStore:       vcStore,
+			VCStatusStore: vcStatusStore,
+			MonitoringSvc: &mocks.MonitoringService{},
+			WitnessStore:  &mockWitnessStore{AddProofErr: fmt.Errorf("unexpected call to add proof")},
+			WitnessPolicy: &mockWitnessPolicy{eval: true},
+			Metrics:       &orbmocks.MetricsProvider{},
+		}
+
+		proofHandler := New(providers, ps)
+
+		err = proofHandler.HandleProof(witnessIRI, anchorVC.ID,
+			expiryTime, []byte(witnessProof))
+		require.NoError(t, err)
+	})
+
 	t.Run("success - policy satisfied but some witness proofs are empty", func(t *testing.T) {
 		vcStore, err := vcstore.New(mem.NewProvider(), testutil.GetLoader(t))
 		require.NoError(t, err)
@@ -370,6 +412,101 @@ func TestWitnessProofHandler(t *testing.T) {
 			"failed to change status to 'completed' for credential[%s]: add vc status error", anchorVC.ID))
 	})
 
+	t.Run("success - failed outcome was claimed first", func(t *testing.T) {
+		vcStore, err := vcstore.New(mem.NewProvider(), testutil.GetLoader(t))
+		require.NoError(t, err)
+
+		anchorVC, err := verifiable.ParseCredential(
+			[]byte(anchorCredTwoProofs),
+			verifiable.WithJSONLDDocumentLoader(testutil.GetLoader(t)),
+			verifiable.WithDisabledProofCheck())
+		require.NoError(t, err)
+
+		err = vcStore.Put(anchorVC)
+		require.NoError(t, err)
+
+		witnessStore, err := witness.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		// prepare witness store with 'empty' witness proofs
+		emptyWitnessProofs := []*proofapi.WitnessProof{{Type: proofapi.WitnessTypeSystem, Witness: witnessIRI.String()}}
+		err = witnessStore.Put(anchorVC.ID, emptyWitnessProofs)
+		require.NoError(t, err)
+
+		witnessPolicy, err := policy.New(configStore, defaultPolicyCacheExpiry)
+		require.NoError(t, err)
+
+		// the witness time-out 'fail' action claims the outcome after the status was checked
+		mockVCStatusStore := &mocks.VCStatusStore{}
+		mockVCStatusStore.GetStatusReturns(proofapi.VCStatusTimedOut, nil)
+		mockVCStatusStore.ClaimOutcomeReturns(fmt.Errorf("%w: has outcome 'failed'", vcstatus.ErrOutcomeClaimed))
+
+		providers := &Providers{
+			VCStore:       vcStore,
+			VCStatusStore: mockVCStatusStore,
+			MonitoringSvc: &mocks.MonitoringService{},
+			WitnessStore:  witnessStore,
+			WitnessPolicy: witnessPolicy,
+			Metrics:       &orbmocks.MetricsProvider{},
+		}
+
+		publisher := &mockPublisher{}
+
+		proofHandler := New(providers, ps)
+		proofHandler.publisher = publisher
+
+		err = proofHandler.HandleProof(witnessIRI, anchorVC.ID,
+			expiryTime, []byte(witnessProof))
+		require.NoError(t, err)
+		require.Zero(t, publisher.count)
+		require.Zero(t, mockVCStatusStore.AddStatusCallCount())
+	})
+
+	t.Run("error - claim outcome error", func(t *testing.T) {
+		vcStore, err := vcstore.New(mem.NewProvider(), testutil.GetLoader(t))
+		require.NoError(t, err)
+
+		anchorVC, err := verifiable.ParseCredential(
+			[]byte(anchorCredTwoProofs),
+			verifiable.WithJSONLDDocumentLoader(testutil.GetLoader(t)),
+			verifiable.WithDisabledProofCheck())
+		require.NoError(t, err)
+
+		err = vcStore.Put(anchorVC)
+		require.NoError(t, err)
+
+		witnessStore, err := witness.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		// prepare witness store with 'empty' witness proofs
+		emptyWitnessProofs := []*proofapi.WitnessProof{{Type: proofapi.WitnessTypeSystem, Witness: witnessIRI.String()}}
+		err = witnessStore.Put(anchorVC.ID, emptyWitnessProofs)
+		require.NoError(t, err)
+
+		witnessPolicy, err := policy.New(configStore, defaultPolicyCacheExpiry)
+		require.NoError(t, err)
+
+		mockVCStatusStore := &mocks.VCStatusStore{}
+		mockVCStatusStore.GetStatusReturns(proofapi.VCStatusInProcess, nil)
+		mockVCStatusStore.ClaimOutcomeReturns(fmt.Errorf("claim outcome error"))
+
+		providers := &Providers{
+			VCStore:       vcStore,
+			VCStatusStore: mockVCStatusStore,
+			MonitoringSvc: &mocks.MonitoringService{},
+			WitnessStore:  witnessStore,
+			WitnessPolicy: witnessPolicy,
+			Metrics:       &orbmocks.MetricsProvider{},
+		}
+
+		proofHandler := New(providers, ps)
+
+		err = proofHandler.HandleProof(witnessIRI, anchorVC.ID,
+			expiryTime, []byte(witnessProof))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "claim outcome error")
+	})
+
 	t.Run("VC status already completed", func(t *testing.T) {
 		vcStore, err := vcstore.New(mem.NewProvider(), testutil.GetLoader(t))
 		require.NoError(t, err)
@@ -614,6 +751,33 @@ func TestWitnessProofHandler(t *testing.T) {
 		require.Contains(t, err.Error(), "failed to unmarshal incoming witness proof for anchor credential")
 	})
 
+	t.Run("error - unsupported signature suite", func(t *testing.T) {
+		vcStore, err := vcstore.New(mem.NewProvider(), testutil.GetLoader(t))
+		require.NoError(t, err)
+
+		vcStatusStore, err := vcstatus.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		err = vcStatusStore.AddStatus(vcID, proofapi.VCStatusInProcess)
+		require.NoError(t, err)
+
+		providers := &Providers{
+			VCStore:       vcStore,
+			VCStatusStore: vcStatusStore,
+			MonitoringSvc: &mocks.MonitoringService{},
+			WitnessStore:  &mockWitnessStore{},
+			WitnessPolicy: &mockWitnessPolicy{},
+			Metrics:       &orbmocks.MetricsProvider{},
+		}
+
+		proofHandler := New(providers, ps)
+
+		err = proofHandler.HandleProof(witnessIRI, vcID, expiryTime,
+			[]byte(strings.Replace(witnessProof, "Ed25519Signature2018", "RsaSignature2018", 1)))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported signature suite [RsaSignature2018] in witness proof")
+	})
+
 	t.Run("error - monitoring error", func(t *testing.T) {
 		vcStore, err := vcstore.New(mem.NewProvider(), testutil.GetLoader(t))
 		require.NoError(t, err)
@@ -653,6 +817,101 @@ func TestWitnessProofHandler(t *testing.T) {
 	})
 }
 
+func TestWitnessProofHandler_EvaluateWitnessPolicy(t *testing.T) {
+	ps := mempubsub.New(mempubsub.Config{})
+	defer ps.Stop()
+
+	witnessIRI := testutil.MustParseURL(witnessURL)
+
+	anchorVC, err := verifiable.ParseCredential([]byte(anchorCred),
+		verifiable.WithDisabledProofCheck(),
+		verifiable.WithJSONLDDocumentLoader(testutil.GetLoader(t)),
+	)
+	require.NoError(t, err)
+
+	newProviders := func(t *testing.T, status proofapi.VCStatus, eval bool) *Providers {
+		t.Helper()
+
+		vcStore, err := vcstore.New(mem.NewProvider(), testutil.GetLoader(t))
+		require.NoError(t, err)
+
+		require.NoError(t, vcStore.Put(anchorVC))
+
+		vcStatusStore, err := vcstatus.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		require.NoError(t, vcStatusStore.AddStatus(anchorVC.ID, status))
+
+		witnessStore, err := witness.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		require.NoError(t, witnessStore.Put(anchorVC.ID, []*proofapi.WitnessProof{
+			{Type: proofapi.WitnessTypeSystem, Witness: witnessIRI.String()},
+		}))
+
+		return &Providers{
+			VCStore:       vcStore,
+			VCStatusStore: vcStatusStore,
+			MonitoringSvc: &mocks.MonitoringService{},
+			WitnessStore:  witnessStore,
+			WitnessPolicy: &mockWitnessPolicy{eval: eval},
+			Metrics:       &orbmocks.MetricsProvider{},
+		}
+	}
+
+	t.Run("success - witness policy satisfied", func(t *testing.T) {
+		providers := newProviders(t, proofapi.VCStatusInProcess, true)
+
+		require.NoError(t, New(providers, ps).EvaluateWitnessPolicy(anchorVC.ID))
+
+		status, err := providers.VCStatusStore.GetStatus(anchorVC.ID)
+		require.NoError(t, err)
+		require.Equal(t, proofapi.VCStatusCompleted, status)
+	})
+
+	t.Run("success - witness policy not satisfied", func(t *testing.T) {
+		providers := newProviders(t, proofapi.VCStatusTimedOut, false)
+
+		require.NoError(t, New(providers, ps).EvaluateWitnessPolicy(anchorVC.ID))
+
+		status, err := providers.VCStatusStore.GetStatus(anchorVC.ID)
+		require.NoError(t, err)
+		require.Equal(t, proofapi.VCStatusTimedOut, status)
+	})
+
+	t.Run("success - vc status is failed", func(t *testing.T) {
+		providers := newProviders(t, proofapi.VCStatusFailed, true)
+		providers.VCStore = nil // Should not be called.
+
+		require.NoError(t, New(providers, ps).EvaluateWitnessPolicy(anchorVC.ID))
+
+		status, err := providers.VCStatusStore.GetStatus(anchorVC.ID)
+		require.NoError(t, err)
+		require.Equal(t, proofapi.VCStatusFailed, status)
+	})
+
+	t.Run("error - get vc status error", func(t *testing.T) {
+		providers := newProviders(t, proofapi.VCStatusInProcess, true)
+
+		err := New(providers, ps).EvaluateWitnessPolicy("https://orb.domain1.com/vc/unknown")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to get status")
+	})
+
+	t.Run("error - get vc error", func(t *testing.T) {
+		providers := newProviders(t, proofapi.VCStatusInProcess, true)
+
+		vcStore, err := vcstore.New(mem.NewProvider(), testutil.GetLoader(t))
+		require.NoError(t, err)
+
+		providers.VCStore = vcStore
+
+		err = New(providers, ps).EvaluateWitnessPolicy(anchorVC.ID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to retrieve anchor credential")
+	})
+}
+
 type mockWitnessStore struct {
 	WitnessProof []*proofapi.WitnessProof
 	AddProofErr  error
@@ -776,3 +1035,13 @@ const witnessProof = `{
     "verificationMethod": "did:web:abc.com#2130bhDAK-2jKsOXJiEDG909Jux4rcYEpFsYzVlqdAY"
   }
 }`
+
+type mockPublisher struct {
+	count int
+}
+
+func (p *mockPublisher) Publish(*verifiable.Credential) error {
+	p.count++
+
+	return nil
+}
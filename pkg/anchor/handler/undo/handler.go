@@ -0,0 +1,70 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package undo
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+)
+
+var logger = log.New("undo-handler")
+
+type witnessStore interface {
+	DeletePending(witness string) ([]string, error)
+}
+
+type witnessPolicyEvaluator interface {
+	EvaluateWitnessPolicy(anchorCredID string) error
+}
+
+// Handler cleans up any anchor state that depends on an actor that was removed from one of the
+// local service's collections as a result of an 'Undo' activity.
+type Handler struct {
+	witnessStore    witnessStore
+	policyEvaluator witnessPolicyEvaluator
+}
+
+// New returns a new undo handler.
+func New(witnessStore witnessStore, policyEvaluator witnessPolicyEvaluator) *Handler {
+	return &Handler{
+		witnessStore:    witnessStore,
+		policyEvaluator: policyEvaluator,
+	}
+}
+
+// HandleUndo cleans up state for the given actor that was removed from the given collection.
+// When a witness is removed, all offers to the witness that are still awaiting a proof are deleted
+// so that the witness is no longer taken into account when evaluating the witness policy. The witness policy
+// is then re-evaluated for each of the affected anchor credentials since the policy may now be satisfied.
+func (h *Handler) HandleUndo(refType store.ReferenceType, actorIRI *url.URL) error {
+	if refType != store.Witness {
+		logger.Debugf("Nothing to clean up for %s [%s]", refType, actorIRI)
+
+		return nil
+	}
+
+	vcIDs, err := h.witnessStore.DeletePending(actorIRI.String())
+	if err != nil {
+		return fmt.Errorf("delete pending offers for witness [%s]: %w", actorIRI, err)
+	}
+
+	logger.Infof("Deleted pending offer(s) for %d anchor credential(s) for removed witness [%s]",
+		len(vcIDs), actorIRI)
+
+	for _, vcID := range vcIDs {
+		err = h.policyEvaluator.EvaluateWitnessPolicy(vcID)
+		if err != nil {
+			return fmt.Errorf("evaluate witness policy for anchor credential [%s]: %w", vcID, err)
+		}
+	}
+
+	return nil
+}
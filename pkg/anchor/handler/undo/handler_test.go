@@ -0,0 +1,101 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package undo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/anchor/proof"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	witnessstore "github.com/trustbloc/orb/pkg/store/witness"
+)
+
+func TestHandler_HandleUndo(t *testing.T) {
+	witness1 := testutil.MustParseURL("https://orb.domain1.com/services/orb")
+	witness2 := testutil.MustParseURL("https://orb.domain2.com/services/orb")
+
+	t.Run("witness removed", func(t *testing.T) {
+		s, err := witnessstore.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put("vc1", []*proof.WitnessProof{
+			{Type: proof.WitnessTypeBatch, Witness: witness1.String()},
+			{Type: proof.WitnessTypeSystem, Witness: witness2.String()},
+		}))
+
+		require.NoError(t, s.Put("vc2", []*proof.WitnessProof{
+			{Type: proof.WitnessTypeSystem, Witness: witness2.String()},
+		}))
+
+		evaluator := &mockPolicyEvaluator{}
+
+		h := New(s, evaluator)
+
+		require.NoError(t, h.HandleUndo(store.Witness, witness1))
+
+		witnesses, err := s.Get("vc1")
+		require.NoError(t, err)
+		require.Len(t, witnesses, 1)
+		require.Equal(t, witness2.String(), witnesses[0].Witness)
+
+		// The policy is re-evaluated only for the affected anchor credential.
+		require.Equal(t, []string{"vc1"}, evaluator.vcIDs)
+	})
+
+	t.Run("policy evaluation error", func(t *testing.T) {
+		s, err := witnessstore.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put("vc1", []*proof.WitnessProof{
+			{Type: proof.WitnessTypeBatch, Witness: witness1.String()},
+		}))
+
+		errExpected := errors.New("injected evaluation error")
+
+		err = New(s, &mockPolicyEvaluator{err: errExpected}).HandleUndo(store.Witness, witness1)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("follower removed", func(t *testing.T) {
+		ws := &mockWitnessStore{err: errors.New("should not be called")}
+
+		require.NoError(t, New(ws, &mockPolicyEvaluator{}).HandleUndo(store.Follower, witness1))
+	})
+
+	t.Run("witness store error", func(t *testing.T) {
+		errExpected := errors.New("injected witness store error")
+
+		err := New(&mockWitnessStore{err: errExpected}, &mockPolicyEvaluator{}).HandleUndo(store.Witness, witness1)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, errExpected))
+	})
+}
+
+type mockWitnessStore struct {
+	err error
+}
+
+func (m *mockWitnessStore) DeletePending(string) ([]string, error) {
+	return nil, m.err
+}
+
+type mockPolicyEvaluator struct {
+	vcIDs []string
+	err   error
+}
+
+func (m *mockPolicyEvaluator) EvaluateWitnessPolicy(vcID string) error {
+	m.vcIDs = append(m.vcIDs, vcID)
+
+	return m.err
+}
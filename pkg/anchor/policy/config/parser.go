@@ -23,53 +23,232 @@ type WitnessPolicyConfig struct {
 	Operator operatorFnc
 
 	LogRequired bool
+
+	// Weights contains per-witness (or per-domain) weights. Witnesses that are not listed have a weight of 1.
+	Weights map[string]int
+
+	// Allow contains witnesses (or domains) whose proofs are counted. If empty then all witnesses are allowed.
+	Allow []string
+
+	// Deny contains witnesses (or domains) whose proofs are never counted.
+	Deny []string
+
+	// Expression is set if the policy uses the extended grammar (groups, selectors, MinWeight, MinDomains).
+	// If nil then the policy is evaluated using the batch/system fields above.
+	Expression Expression
+
+	operatorName string
 }
 
 // Gate values.
 const (
 	OutOf       = "OutOf"
 	MinPercent  = "MinPercent"
+	MinWeight   = "MinWeight"
+	MinDomains  = "MinDomains"
 	LogRequired = "LogRequired"
 
 	AND = "AND"
 	OR  = "OR"
 )
 
+// Declaration values.
+const (
+	Weight = "Weight"
+	Allow  = "Allow"
+	Deny   = "Deny"
+)
+
 // Role values.
 const (
 	RoleBatch  = "batch"
 	RoleSystem = "system"
+	RoleAny    = "any"
+	RoleLog    = "log"
 )
 
-const maxPercent = 100
+const (
+	maxPercent = 100
+
+	listSeparator = "|"
+
+	ruleArgsNo = 2
+)
 
 type operatorFnc func(a, b bool) bool
 
+// Expression is a node in the witness policy expression tree. It is either a *Rule or a *Group.
+type Expression interface {
+	String() string
+}
+
+// Selector selects the witnesses that a rule applies to. Either a role (batch, system, any, log)
+// or an explicit list of witnesses (or domains) is specified.
+type Selector struct {
+	Role      string
+	Witnesses []string
+}
+
+func (s *Selector) String() string {
+	if s.Role != "" {
+		return s.Role
+	}
+
+	return strings.Join(s.Witnesses, listSeparator)
+}
+
+// Rule is a leaf of the expression tree, e.g. OutOf(2,batch) or MinDomains(2,any).
+type Rule struct {
+	Name      string
+	Threshold int
+	Selector  *Selector
+}
+
+func (r *Rule) String() string {
+	return fmt.Sprintf("%s(%d,%s)", r.Name, r.Threshold, r.Selector)
+}
+
+// Group combines the operands using the given operator (AND or OR).
+type Group struct {
+	Operator string
+	Operands []Expression
+}
+
+func (g *Group) String() string {
+	operands := make([]string, len(g.Operands))
+
+	for i, o := range g.Operands {
+		operands[i] = o.String()
+	}
+
+	return "(" + strings.Join(operands, " "+g.Operator+" ") + ")"
+}
+
 // Parse parses witness policy from policy string.
+//
+// In addition to the basic rules (e.g. "OutOf(2,system) AND MinPercent(50,batch)"), the policy may contain
+// nested groups, e.g. "(OutOf(2,https://a.com/services/orb|https://b.com/services/orb|https://c.com/services/orb)
+// AND OutOf(1,log)) OR MinWeight(5,any)", and the following declarations:
+//   - Weight(witness,weight) assigns a weight to a witness (or domain), used by MinWeight
+//   - Allow(witness|witness...) only counts proofs from the listed witnesses (or domains)
+//   - Deny(witness|witness...) never counts proofs from the listed witnesses (or domains)
+//   - LogRequired only counts proofs from witnesses that have a log.
+//
+// In both forms, OutOf(n,selector) is satisfied if proofs were collected from at least n of the selected witnesses
+// or from all of the selected witnesses (if there are fewer than n). Excluded witnesses (see Allow and Deny) are
+// counted as witnesses from which no proof was collected. In the extended form, a rule that doesn't select any of
+// the witnesses is not satisfied.
 func Parse(policy string) (*WitnessPolicyConfig, error) {
 	// default policy is 100% batch and 100% system witnesses
 	wp := &WitnessPolicyConfig{
 		MinPercentBatch:  maxPercent,
 		MinPercentSystem: maxPercent,
 		Operator:         and,
+		operatorName:     AND,
 	}
 
 	if policy == "" {
 		return wp, nil
 	}
 
-	tokens := strings.Split(policy, " ")
+	tokens, err := tokenize(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	var exprTokens []string
 
 	for _, token := range tokens {
-		err := wp.processToken(token)
+		isDeclaration, err := wp.processDeclaration(token)
 		if err != nil {
 			return nil, err
 		}
+
+		if !isDeclaration {
+			exprTokens = append(exprTokens, token)
+		}
+	}
+
+	if isBasicPolicy(exprTokens) {
+		for _, token := range exprTokens {
+			err := wp.processToken(token)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return wp, nil
+	}
+
+	p := &exprParser{tokens: exprTokens}
+
+	wp.Expression, err = p.parse()
+	if err != nil {
+		return nil, err
 	}
 
 	return wp, nil
 }
 
+func (wp *WitnessPolicyConfig) processDeclaration(token string) (bool, error) {
+	name, args := splitRule(token)
+
+	switch name {
+	case LogRequired:
+		if args == nil {
+			wp.LogRequired = true
+
+			return true, nil
+		}
+	case Weight:
+		return true, wp.processWeight(args)
+	case Allow:
+		return true, processList(Allow, args, &wp.Allow)
+	case Deny:
+		return true, processList(Deny, args, &wp.Deny)
+	}
+
+	return false, nil
+}
+
+// processWeight processes the weight declaration, e.g. Weight(https://orb.domain1.com/services/orb,3).
+func (wp *WitnessPolicyConfig) processWeight(args []string) error {
+	if len(args) != ruleArgsNo {
+		return fmt.Errorf("expected 2 but got %d arguments for Weight policy", len(args))
+	}
+
+	weight, err := strconv.Atoi(args[1])
+	if err != nil {
+		return fmt.Errorf("second argument for Weight policy must be an integer: %w", err)
+	}
+
+	if weight < 0 {
+		return fmt.Errorf("second argument for Weight policy must not be negative")
+	}
+
+	if args[0] == "" {
+		return fmt.Errorf("first argument for Weight policy must not be empty")
+	}
+
+	if wp.Weights == nil {
+		wp.Weights = make(map[string]int)
+	}
+
+	wp.Weights[args[0]] = weight
+
+	return nil
+}
+
+func processList(name string, args []string, list *[]string) error {
+	if len(args) != 1 || args[0] == "" {
+		return fmt.Errorf("expected a list of witnesses for %s policy", name)
+	}
+
+	*list = append(*list, strings.Split(args[0], listSeparator)...)
+
+	return nil
+}
+
 func (wp *WitnessPolicyConfig) processToken(token string) error {
 	switch t := token; {
 	case strings.HasPrefix(t, OutOf):
@@ -86,8 +265,10 @@ func (wp *WitnessPolicyConfig) processToken(token string) error {
 		wp.LogRequired = true
 	case t == AND:
 		wp.Operator = and
+		wp.operatorName = AND
 	case t == OR:
 		wp.Operator = or
+		wp.operatorName = OR
 	default:
 		return fmt.Errorf("rule not supported: %s", token)
 	}
@@ -168,11 +349,227 @@ func (wp *WitnessPolicyConfig) processMinPercent(token string) error {
 	return nil
 }
 
+// OperatorName returns the name of the operator (AND or OR) that combines the batch and system conditions.
+func (wp *WitnessPolicyConfig) OperatorName() string {
+	if wp.operatorName == "" {
+		return AND
+	}
+
+	return wp.operatorName
+}
+
 func (wp *WitnessPolicyConfig) String() string {
+	if wp.Expression != nil {
+		return fmt.Sprintf("expression:%s, log:%t, weights:%v, allow:%v, deny:%v",
+			wp.Expression, wp.LogRequired, wp.Weights, wp.Allow, wp.Deny)
+	}
+
 	return fmt.Sprintf("minBatch:%d, minSystem:%d, percentBatch:%d, percentSystem:%d, log:%t",
 		wp.MinNumberBatch, wp.MinNumberSystem, wp.MinPercentBatch, wp.MinPercentSystem, wp.LogRequired)
 }
 
+// isBasicPolicy returns true if the expression tokens only contain OutOf and MinPercent rules for
+// the batch and system roles (and no groups). Such policies are evaluated using the original semantics
+// where the batch and system conditions are combined by a single operator.
+func isBasicPolicy(tokens []string) bool {
+	for _, token := range tokens {
+		if token == AND || token == OR {
+			continue
+		}
+
+		name, args := splitRule(token)
+
+		switch name {
+		case OutOf, MinPercent:
+			if len(args) == ruleArgsNo && isExtendedSelector(args[1]) {
+				return false
+			}
+		case MinWeight, MinDomains, "(", ")":
+			return false
+		}
+	}
+
+	return true
+}
+
+func isExtendedSelector(arg string) bool {
+	return arg == RoleAny || arg == RoleLog || strings.Contains(arg, listSeparator) || strings.Contains(arg, ".")
+}
+
+// tokenize splits the policy into tokens. A token is either a rule with its arguments (e.g. "OutOf(2,batch)"),
+// a keyword (e.g. "AND") or a group parenthesis.
+func tokenize(policy string) ([]string, error) {
+	var tokens []string
+
+	for i := 0; i < len(policy); {
+		switch c := policy[i]; {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		default:
+			start := i
+
+			for i < len(policy) && !strings.ContainsRune(" \t\n()", rune(policy[i])) {
+				i++
+			}
+
+			if i < len(policy) && policy[i] == '(' {
+				end := strings.IndexByte(policy[i:], ')')
+				if end < 0 {
+					return nil, fmt.Errorf("missing closing bracket for rule: %s", policy[start:])
+				}
+
+				i += end + 1
+			}
+
+			tokens = append(tokens, policy[start:i])
+		}
+	}
+
+	return tokens, nil
+}
+
+// splitRule splits a rule token (e.g. "OutOf(2,batch)") into its name and arguments. Nil arguments
+// are returned if the token has no brackets.
+func splitRule(token string) (string, []string) {
+	i := strings.IndexByte(token, '(')
+	if i <= 0 || !strings.HasSuffix(token, ")") {
+		return token, nil
+	}
+
+	return token[:i], strings.Split(token[i+1:len(token)-1], ",")
+}
+
+// exprParser parses expression tokens using the following grammar (AND has precedence over OR):
+//
+//	expr   = term { "OR" term }
+//	term   = factor { "AND" factor }
+//	factor = "(" expr ")" | rule
+type exprParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *exprParser) parse() (Expression, error) {
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty policy expression")
+	}
+
+	expr, err := p.parseOperation(OR, p.parseTerm)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected token in policy expression: %s", p.tokens[p.pos])
+	}
+
+	return expr, nil
+}
+
+func (p *exprParser) parseTerm() (Expression, error) {
+	return p.parseOperation(AND, p.parseFactor)
+}
+
+func (p *exprParser) parseOperation(operator string, parseOperand func() (Expression, error)) (Expression, error) {
+	first, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []Expression{first}
+
+	for p.pos < len(p.tokens) && p.tokens[p.pos] == operator {
+		p.pos++
+
+		operand, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		operands = append(operands, operand)
+	}
+
+	if len(operands) == 1 {
+		return first, nil
+	}
+
+	return &Group{Operator: operator, Operands: operands}, nil
+}
+
+func (p *exprParser) parseFactor() (Expression, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of policy expression")
+	}
+
+	token := p.tokens[p.pos]
+	p.pos++
+
+	if token != "(" {
+		return parseRule(token)
+	}
+
+	expr, err := p.parseOperation(OR, p.parseTerm)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos >= len(p.tokens) || p.tokens[p.pos] != ")" {
+		return nil, fmt.Errorf("missing closing bracket in policy expression")
+	}
+
+	p.pos++
+
+	return expr, nil
+}
+
+func parseRule(token string) (*Rule, error) {
+	name, args := splitRule(token)
+
+	switch name {
+	case OutOf, MinPercent, MinWeight, MinDomains:
+	default:
+		return nil, fmt.Errorf("rule not supported: %s", token)
+	}
+
+	if len(args) != ruleArgsNo {
+		return nil, fmt.Errorf("expected 2 but got %d arguments for %s policy", len(args), name)
+	}
+
+	threshold, err := strconv.Atoi(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("first argument for %s policy must be an integer: %w", name, err)
+	}
+
+	if threshold < 0 || (name == MinPercent && threshold > maxPercent) {
+		return nil, fmt.Errorf("first argument for %s policy is out of range: %d", name, threshold)
+	}
+
+	selector, err := parseSelector(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector for %s policy: %w", name, err)
+	}
+
+	return &Rule{Name: name, Threshold: threshold, Selector: selector}, nil
+}
+
+func parseSelector(arg string) (*Selector, error) {
+	switch arg {
+	case RoleBatch, RoleSystem, RoleAny, RoleLog:
+		return &Selector{Role: arg}, nil
+	case "":
+		return nil, fmt.Errorf("empty selector")
+	}
+
+	if !isExtendedSelector(arg) {
+		return nil, fmt.Errorf("role '%s' not supported", arg)
+	}
+
+	return &Selector{Witnesses: strings.Split(arg, listSeparator)}, nil
+}
+
 func and(a, b bool) bool {
 	return a && b
 }
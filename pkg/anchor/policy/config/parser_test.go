@@ -163,3 +163,90 @@ func TestParse_LogRequired(t *testing.T) {
 		require.Equal(t, and(true, false), wp.Operator(true, false))
 	})
 }
+
+func TestParse_Expression(t *testing.T) {
+	t.Run("success - nested groups", func(t *testing.T) {
+		wp, err := Parse("(OutOf(2,https://a.com/services/orb|https://b.com/services/orb|c.com) AND OutOf(1,log)) " +
+			"OR MinPercent(50,system) AND MinDomains(2,any)")
+		require.NoError(t, err)
+		require.NotNil(t, wp)
+		require.NotNil(t, wp.Expression)
+
+		group, ok := wp.Expression.(*Group)
+		require.True(t, ok)
+		require.Equal(t, OR, group.Operator)
+		require.Len(t, group.Operands, 2)
+
+		left, ok := group.Operands[0].(*Group)
+		require.True(t, ok)
+		require.Equal(t, AND, left.Operator)
+
+		outOf, ok := left.Operands[0].(*Rule)
+		require.True(t, ok)
+		require.Equal(t, OutOf, outOf.Name)
+		require.Equal(t, 2, outOf.Threshold)
+		require.Equal(t, []string{"https://a.com/services/orb", "https://b.com/services/orb", "c.com"},
+			outOf.Selector.Witnesses)
+
+		right, ok := group.Operands[1].(*Group)
+		require.True(t, ok)
+		require.Equal(t, AND, right.Operator)
+
+		require.Equal(t, "((OutOf(2,https://a.com/services/orb|https://b.com/services/orb|c.com) AND OutOf(1,log)) OR "+
+			"(MinPercent(50,system) AND MinDomains(2,any)))", wp.Expression.String())
+		require.NotEmpty(t, wp.String())
+	})
+
+	t.Run("success - declarations", func(t *testing.T) {
+		wp, err := Parse("Weight(https://a.com/services/orb,3) Allow(a.com|b.com) Deny(c.com) LogRequired MinWeight(4,any)")
+		require.NoError(t, err)
+		require.NotNil(t, wp)
+
+		require.Equal(t, map[string]int{"https://a.com/services/orb": 3}, wp.Weights)
+		require.Equal(t, []string{"a.com", "b.com"}, wp.Allow)
+		require.Equal(t, []string{"c.com"}, wp.Deny)
+		require.True(t, wp.LogRequired)
+		require.Equal(t, "MinWeight(4,any)", wp.Expression.String())
+	})
+
+	t.Run("success - declarations with basic policy", func(t *testing.T) {
+		wp, err := Parse("Deny(c.com) OutOf(2,system) AND OutOf(1,batch)")
+		require.NoError(t, err)
+		require.NotNil(t, wp)
+
+		require.Nil(t, wp.Expression)
+		require.Equal(t, []string{"c.com"}, wp.Deny)
+		require.Equal(t, 2, wp.MinNumberSystem)
+		require.Equal(t, 1, wp.MinNumberBatch)
+	})
+
+	t.Run("error - invalid expressions", func(t *testing.T) {
+		tests := map[string]string{
+			"(OutOf(1,any)":                         "missing closing bracket in policy expression",
+			"OutOf(1,any))":                         "unexpected token in policy expression: )",
+			"OutOf(1,any) AND":                      "unexpected end of policy expression",
+			"OutOf(1,any) AND Test(1,any)":          "rule not supported: Test(1,any)",
+			"MinWeight(1)":                          "expected 2 but got 1 arguments for MinWeight policy",
+			"MinWeight(a,any)":                      "first argument for MinWeight policy must be an integer",
+			"MinDomains(-1,any)":                    "first argument for MinDomains policy is out of range",
+			"MinPercent(101,any)":                   "first argument for MinPercent policy is out of range",
+			"MinDomains(1,invalid)":                 "invalid selector for MinDomains policy: role 'invalid' not supported",
+			"MinDomains(1,)":                        "invalid selector for MinDomains policy: empty selector",
+			"OutOf(1,any":                           "missing closing bracket for rule",
+			"Weight(a.com)":                         "expected 2 but got 1 arguments for Weight policy",
+			"Weight(a.com,x)":                       "second argument for Weight policy must be an integer",
+			"Weight(a.com,-1)":                      "second argument for Weight policy must not be negative",
+			"Weight(,1)":                            "first argument for Weight policy must not be empty",
+			"Allow()":                               "expected a list of witnesses for Allow policy",
+			"Deny(a.com,b.com)":                     "expected a list of witnesses for Deny policy",
+			"LogRequired (MinDomains(1,any)) AND (": "unexpected end of policy expression",
+		}
+
+		for policy, expectedErr := range tests {
+			wp, err := Parse(policy)
+			require.Errorf(t, err, "expecting error for policy: %s", policy)
+			require.Nil(t, wp)
+			require.Contains(t, err.Error(), expectedErr)
+		}
+	})
+}
@@ -0,0 +1,306 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policy
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/trustbloc/orb/pkg/anchor/policy/config"
+	"github.com/trustbloc/orb/pkg/anchor/proof"
+)
+
+// Evaluation contains the result of evaluating a witness policy against a set of witness proofs.
+type Evaluation struct {
+	Policy    string        `json:"policy"`
+	Satisfied bool          `json:"satisfied"`
+	Result    *ClauseResult `json:"result"`
+}
+
+// ClauseResult contains the result of evaluating a single clause of the witness policy. For a group of clauses
+// (AND/OR) the results of the operands are contained in Clauses.
+type ClauseResult struct {
+	Clause    string          `json:"clause"`
+	Role      string          `json:"role,omitempty"`
+	Satisfied bool            `json:"satisfied"`
+	Total     int             `json:"total"`
+	Collected int             `json:"collected"`
+	Pending   []string        `json:"pending,omitempty"`
+	Clauses   []*ClauseResult `json:"clauses,omitempty"`
+}
+
+// Explain evaluates the given witness policy against the provided witnesses and returns the result
+// for each clause of the policy.
+func Explain(policy string, witnesses []*proof.WitnessProof) (*Evaluation, error) {
+	cfg, err := config.Parse(policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse policy config from policy[%s]: %w", policy, err)
+	}
+
+	evaluation := evaluate(cfg, witnesses)
+	evaluation.Policy = policy
+
+	return evaluation, nil
+}
+
+func evaluate(cfg *config.WitnessPolicyConfig, witnesses []*proof.WitnessProof) *Evaluation {
+	var result *ClauseResult
+
+	if cfg.Expression != nil {
+		result = evaluateExpression(cfg, cfg.Expression, witnesses)
+	} else {
+		result = evaluateBasic(cfg, witnesses)
+	}
+
+	return &Evaluation{
+		Satisfied: result.Satisfied,
+		Result:    result,
+	}
+}
+
+// evaluateBasic evaluates the batch and system conditions of a basic policy and combines them
+// using the policy operator.
+func evaluateBasic(cfg *config.WitnessPolicyConfig, witnesses []*proof.WitnessProof) *ClauseResult {
+	batch := evaluateRole(cfg, config.RoleBatch, proof.WitnessTypeBatch,
+		cfg.MinNumberBatch, cfg.MinPercentBatch, witnesses)
+	system := evaluateRole(cfg, config.RoleSystem, proof.WitnessTypeSystem,
+		cfg.MinNumberSystem, cfg.MinPercentSystem, witnesses)
+
+	return &ClauseResult{
+		Clause:    cfg.OperatorName(),
+		Satisfied: cfg.Operator(batch.Satisfied, system.Satisfied),
+		Total:     batch.Total + system.Total,
+		Collected: batch.Collected + system.Collected,
+		Clauses:   []*ClauseResult{batch, system},
+	}
+}
+
+func evaluateRole(cfg *config.WitnessPolicyConfig, role string, witnessType proof.WitnessType,
+	minNumber, minPercent int, witnesses []*proof.WitnessProof) *ClauseResult {
+	result := &ClauseResult{Role: role}
+
+	for _, w := range witnesses {
+		if w.Type != witnessType {
+			continue
+		}
+
+		result.Total++
+
+		switch {
+		case !isAllowed(cfg, w.Witness):
+		case isCollected(cfg, w):
+			result.Collected++
+		default:
+			result.Pending = append(result.Pending, w.Witness)
+		}
+	}
+
+	result.Satisfied = evaluateThreshold(result.Collected, result.Total, minNumber, minPercent)
+
+	if minNumber == 0 {
+		result.Clause = fmt.Sprintf("%s(%d,%s)", config.MinPercent, minPercent, role)
+	} else {
+		result.Clause = fmt.Sprintf("%s(%d,%s) %s %s(%d,%s)",
+			config.OutOf, minNumber, role, config.OR, config.MinPercent, minPercent, role)
+	}
+
+	return result
+}
+
+// evaluateExpression evaluates the extended policy expression tree.
+func evaluateExpression(cfg *config.WitnessPolicyConfig, expr config.Expression,
+	witnesses []*proof.WitnessProof) *ClauseResult {
+	switch e := expr.(type) {
+	case *config.Group:
+		result := &ClauseResult{
+			Clause:    e.Operator,
+			Satisfied: e.Operator == config.AND,
+		}
+
+		// All operands are evaluated (no short-circuit) so that the result of each clause is reported.
+		for _, operand := range e.Operands {
+			operandResult := evaluateExpression(cfg, operand, witnesses)
+
+			if e.Operator == config.AND {
+				result.Satisfied = result.Satisfied && operandResult.Satisfied
+			} else {
+				result.Satisfied = result.Satisfied || operandResult.Satisfied
+			}
+
+			result.Clauses = append(result.Clauses, operandResult)
+		}
+
+		return result
+
+	case *config.Rule:
+		return evaluateRule(cfg, e, witnesses)
+
+	default:
+		logger.Warnf("unsupported expression type '%T' in witness policy", expr)
+
+		return &ClauseResult{Clause: expr.String()}
+	}
+}
+
+func evaluateRule(cfg *config.WitnessPolicyConfig, rule *config.Rule, witnesses []*proof.WitnessProof) *ClauseResult {
+	result := &ClauseResult{
+		Clause: rule.String(),
+		Role:   rule.Selector.String(),
+	}
+
+	collectedWeight := 0
+	domains := make(map[string]struct{})
+
+	for _, w := range witnesses {
+		if !selects(rule.Selector, w) {
+			continue
+		}
+
+		result.Total++
+
+		if !isAllowed(cfg, w.Witness) {
+			continue
+		}
+
+		if !isCollected(cfg, w) {
+			result.Pending = append(result.Pending, w.Witness)
+
+			continue
+		}
+
+		result.Collected++
+		collectedWeight += getWeight(cfg.Weights, w.Witness)
+		domains[getDomain(w.Witness)] = struct{}{}
+	}
+
+	if result.Total == 0 {
+		// Unlike the basic policy, a rule is never satisfied if none of the witnesses it selects are present.
+		return result
+	}
+
+	switch rule.Name {
+	case config.OutOf:
+		// Same semantics as the basic policy: satisfied if the minimum number of proofs was collected
+		// or if proofs were collected from all of the selected witnesses.
+		result.Satisfied = rule.Threshold == 0 ||
+			evaluateThreshold(result.Collected, result.Total, rule.Threshold, maxPercent)
+	case config.MinPercent:
+		result.Satisfied = evaluateThreshold(result.Collected, result.Total, 0, rule.Threshold)
+	case config.MinWeight:
+		result.Satisfied = collectedWeight >= rule.Threshold
+	case config.MinDomains:
+		result.Satisfied = len(domains) >= rule.Threshold
+	default:
+		logger.Warnf("unsupported rule '%s' in witness policy", rule.Name)
+	}
+
+	return result
+}
+
+func isCollected(cfg *config.WitnessPolicyConfig, w *proof.WitnessProof) bool {
+	return checkLog(cfg.LogRequired, w.HasLog) && w.Proof != nil
+}
+
+func evaluateThreshold(collected, total, minNumber, minPercent int) bool {
+	percentCollected := float64(maxPercent)
+	if total != 0 {
+		percentCollected = float64(collected) / float64(total)
+	}
+
+	return (minNumber != 0 && collected >= minNumber) ||
+		percentCollected >= float64(minPercent)/maxPercent
+}
+
+func selects(selector *config.Selector, w *proof.WitnessProof) bool {
+	switch selector.Role {
+	case config.RoleBatch:
+		return w.Type == proof.WitnessTypeBatch
+	case config.RoleSystem:
+		return w.Type == proof.WitnessTypeSystem
+	case config.RoleAny:
+		return true
+	case config.RoleLog:
+		return w.HasLog
+	}
+
+	return matchesAny(selector.Witnesses, w.Witness)
+}
+
+// isAllowed returns false if the witness is excluded by the Allow or Deny declarations. Proofs from excluded
+// witnesses are never counted, but the witnesses still count towards the total number of witnesses.
+func isAllowed(cfg *config.WitnessPolicyConfig, witness string) bool {
+	if len(cfg.Allow) > 0 && !matchesAny(cfg.Allow, witness) {
+		logger.Debugf("witness [%s] is not in the witness policy allow list", witness)
+
+		return false
+	}
+
+	if matchesAny(cfg.Deny, witness) {
+		logger.Debugf("witness [%s] is in the witness policy deny list", witness)
+
+		return false
+	}
+
+	return true
+}
+
+func matchesAny(entries []string, witness string) bool {
+	for _, entry := range entries {
+		if matches(entry, witness) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches returns true if the entry is the witness IRI or the domain of the witness
+// (either the host or scheme://host).
+func matches(entry, witness string) bool {
+	if entry == witness {
+		return true
+	}
+
+	u, err := url.Parse(witness)
+	if err != nil || u.Host == "" {
+		return false
+	}
+
+	return entry == u.Host || entry == fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+}
+
+func getWeight(weights map[string]int, witness string) int {
+	if weight, ok := weights[witness]; ok {
+		return weight
+	}
+
+	for entry, weight := range weights {
+		if matches(entry, witness) {
+			return weight
+		}
+	}
+
+	return 1
+}
+
+func getDomain(witness string) string {
+	u, err := url.Parse(witness)
+	if err != nil || u.Host == "" {
+		return witness
+	}
+
+	return u.Host
+}
+
+func checkLog(logRequired, hasLog bool) bool {
+	if logRequired {
+		return hasLog
+	}
+
+	// log is not required, witness without log is counted for policy
+	return true
+}
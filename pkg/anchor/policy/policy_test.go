@@ -14,6 +14,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
 	"github.com/stretchr/testify/require"
 
+	"github.com/trustbloc/orb/pkg/anchor/policy/config"
 	"github.com/trustbloc/orb/pkg/anchor/proof"
 	storemocks "github.com/trustbloc/orb/pkg/store/mocks"
 )
@@ -681,6 +682,313 @@ func TestEvaluate(t *testing.T) {
 	})
 }
 
+func TestEvaluate_Expression(t *testing.T) {
+	const (
+		partner1 = "https://orb.partner1.com/services/orb"
+		partner2 = "https://orb.partner2.com/services/orb"
+		partner3 = "https://orb.partner3.com/services/orb"
+		logged   = "https://orb.logged.com/services/orb"
+		other    = "https://orb.other.com/services/orb"
+	)
+
+	consortiumPolicy := fmt.Sprintf("OutOf(2,%s|%s|%s) AND OutOf(1,log)", partner1, partner2, partner3)
+
+	newPolicy := func(t *testing.T, policy string) *WitnessPolicy {
+		t.Helper()
+
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		require.NoError(t, configStore.Put(WitnessPolicyKey, []byte(policy)))
+
+		wp, err := New(configStore, defaultPolicyCacheExpiry)
+		require.NoError(t, err)
+		require.NotNil(t, wp)
+
+		return wp
+	}
+
+	t.Run("success - two of three partners plus one log-backed witness", func(t *testing.T) {
+		wp := newPolicy(t, consortiumPolicy)
+
+		ok, err := wp.Evaluate([]*proof.WitnessProof{
+			{Type: proof.WitnessTypeBatch, Witness: partner1, Proof: []byte("proof")},
+			{Type: proof.WitnessTypeBatch, Witness: partner2},
+			{Type: proof.WitnessTypeSystem, Witness: partner3, Proof: []byte("proof")},
+			{Type: proof.WitnessTypeSystem, Witness: logged, Proof: []byte("proof"), HasLog: true},
+		})
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("failure - only one partner", func(t *testing.T) {
+		wp := newPolicy(t, consortiumPolicy)
+
+		ok, err := wp.Evaluate([]*proof.WitnessProof{
+			{Type: proof.WitnessTypeBatch, Witness: partner1, Proof: []byte("proof")},
+			{Type: proof.WitnessTypeBatch, Witness: partner2},
+			{Type: proof.WitnessTypeSystem, Witness: logged, Proof: []byte("proof"), HasLog: true},
+		})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("failure - no log-backed witness", func(t *testing.T) {
+		wp := newPolicy(t, consortiumPolicy)
+
+		ok, err := wp.Evaluate([]*proof.WitnessProof{
+			{Type: proof.WitnessTypeBatch, Witness: partner1, Proof: []byte("proof")},
+			{Type: proof.WitnessTypeBatch, Witness: partner2, Proof: []byte("proof")},
+			{Type: proof.WitnessTypeSystem, Witness: logged},
+		})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("success - nested groups", func(t *testing.T) {
+		wp := newPolicy(t, "(OutOf(1,batch) AND OutOf(1,system)) OR MinPercent(100,any)")
+
+		ok, err := wp.Evaluate([]*proof.WitnessProof{
+			{Type: proof.WitnessTypeBatch, Witness: partner1, Proof: []byte("proof")},
+			{Type: proof.WitnessTypeSystem, Witness: partner2},
+		})
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		ok, err = wp.Evaluate([]*proof.WitnessProof{
+			{Type: proof.WitnessTypeBatch, Witness: partner1, Proof: []byte("proof")},
+			{Type: proof.WitnessTypeSystem, Witness: partner2, Proof: []byte("proof")},
+		})
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("success - weights", func(t *testing.T) {
+		wp := newPolicy(t, fmt.Sprintf("Weight(%s,3) Weight(orb.partner2.com,2) MinWeight(4,any)", partner1))
+
+		ok, err := wp.Evaluate([]*proof.WitnessProof{
+			{Type: proof.WitnessTypeBatch, Witness: partner1, Proof: []byte("proof")},
+			{Type: proof.WitnessTypeBatch, Witness: other, Proof: []byte("proof")},
+		})
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = wp.Evaluate([]*proof.WitnessProof{
+			{Type: proof.WitnessTypeBatch, Witness: partner2, Proof: []byte("proof")},
+			{Type: proof.WitnessTypeBatch, Witness: other, Proof: []byte("proof")},
+		})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("success - distinct domains", func(t *testing.T) {
+		wp := newPolicy(t, "MinDomains(2,any)")
+
+		ok, err := wp.Evaluate([]*proof.WitnessProof{
+			{Type: proof.WitnessTypeBatch, Witness: partner1, Proof: []byte("proof")},
+			{Type: proof.WitnessTypeSystem, Witness: "https://orb.partner1.com/services/other", Proof: []byte("proof")},
+		})
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		ok, err = wp.Evaluate([]*proof.WitnessProof{
+			{Type: proof.WitnessTypeBatch, Witness: partner1, Proof: []byte("proof")},
+			{Type: proof.WitnessTypeSystem, Witness: partner2, Proof: []byte("proof")},
+		})
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("success - log required applies to extended rules", func(t *testing.T) {
+		wp := newPolicy(t, "OutOf(2,any) LogRequired")
+
+		ok, err := wp.Evaluate([]*proof.WitnessProof{
+			{Type: proof.WitnessTypeBatch, Witness: partner1, Proof: []byte("proof"), HasLog: true},
+			{Type: proof.WitnessTypeSystem, Witness: partner2, Proof: []byte("proof")},
+		})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("success - deny list", func(t *testing.T) {
+		wp := newPolicy(t, "Deny(orb.partner2.com) OutOf(2,any)")
+
+		ok, err := wp.Evaluate([]*proof.WitnessProof{
+			{Type: proof.WitnessTypeBatch, Witness: partner1, Proof: []byte("proof")},
+			{Type: proof.WitnessTypeSystem, Witness: partner2, Proof: []byte("proof")},
+		})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("success - allow list with basic policy", func(t *testing.T) {
+		wp := newPolicy(t, fmt.Sprintf("Allow(%s|https://orb.partner2.com) MinPercent(100,batch)", partner1))
+
+		ok, err := wp.Evaluate([]*proof.WitnessProof{
+			{Type: proof.WitnessTypeBatch, Witness: partner1, Proof: []byte("proof")},
+			{Type: proof.WitnessTypeSystem, Witness: partner2, Proof: []byte("proof")},
+		})
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		// A witness that isn't in the allow list counts as a witness from which no proof was collected.
+		ok, err = wp.Evaluate([]*proof.WitnessProof{
+			{Type: proof.WitnessTypeBatch, Witness: partner1, Proof: []byte("proof")},
+			{Type: proof.WitnessTypeBatch, Witness: other, Proof: []byte("proof")},
+			{Type: proof.WitnessTypeSystem, Witness: partner2, Proof: []byte("proof")},
+		})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("failure - denied witness is not collected", func(t *testing.T) {
+		wp := newPolicy(t, "Deny(orb.partner2.com) MinPercent(100,any)")
+
+		ok, err := wp.Evaluate([]*proof.WitnessProof{
+			{Type: proof.WitnessTypeBatch, Witness: partner1, Proof: []byte("proof")},
+			{Type: proof.WitnessTypeSystem, Witness: partner2, Proof: []byte("proof")},
+		})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("failure - no selected witnesses", func(t *testing.T) {
+		for _, policy := range []string{
+			consortiumPolicy,
+			fmt.Sprintf("OutOf(1,%s) AND OutOf(1,batch)", partner3),
+			"MinPercent(50,log) AND OutOf(1,batch)",
+			"(OutOf(2,system)) AND OutOf(1,batch)",
+			"MinWeight(1,system) AND OutOf(1,batch)",
+			"MinDomains(1,system) AND OutOf(1,batch)",
+		} {
+			wp := newPolicy(t, policy)
+
+			ok, err := wp.Evaluate([]*proof.WitnessProof{
+				{Type: proof.WitnessTypeBatch, Witness: partner1, Proof: []byte("proof")},
+				{Type: proof.WitnessTypeBatch, Witness: partner2, Proof: []byte("proof")},
+			})
+			require.NoError(t, err)
+			require.Falsef(t, ok, "policy: %s", policy)
+		}
+	})
+
+	t.Run("failure - selected witnesses excluded", func(t *testing.T) {
+		wp := newPolicy(t, fmt.Sprintf("Deny(%s) OutOf(1,%s) AND OutOf(1,batch)", partner3, partner3))
+
+		ok, err := wp.Evaluate([]*proof.WitnessProof{
+			{Type: proof.WitnessTypeBatch, Witness: partner1, Proof: []byte("proof")},
+			{Type: proof.WitnessTypeSystem, Witness: partner3, Proof: []byte("proof")},
+		})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func TestEvaluate_OutOf(t *testing.T) {
+	batchWitnesses := func(total, collected int) []*proof.WitnessProof {
+		witnesses := make([]*proof.WitnessProof, total)
+
+		for i := range witnesses {
+			witnesses[i] = &proof.WitnessProof{
+				Type:    proof.WitnessTypeBatch,
+				Witness: fmt.Sprintf("https://orb.domain%d.com/services/orb", i),
+			}
+
+			if i < collected {
+				witnesses[i].Proof = []byte("proof")
+			}
+		}
+
+		return witnesses
+	}
+
+	tests := []struct {
+		name      string
+		minNumber int
+		total     int
+		collected int
+		satisfied bool
+	}{
+		{name: "minimum number collected", minNumber: 2, total: 3, collected: 2, satisfied: true},
+		{name: "minimum number not collected", minNumber: 2, total: 3, collected: 1, satisfied: false},
+		{name: "fewer witnesses than minimum - all collected", minNumber: 2, total: 1, collected: 1, satisfied: true},
+		{name: "fewer witnesses than minimum - none collected", minNumber: 2, total: 1, collected: 0, satisfied: false},
+		{name: "zero minimum", minNumber: 0, total: 2, collected: 0, satisfied: true},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			// The basic and extended (grouped) forms of the policy must have the same semantics.
+			for _, policy := range []string{
+				fmt.Sprintf("OutOf(%d,batch)", tc.minNumber),
+				fmt.Sprintf("(OutOf(%d,batch))", tc.minNumber),
+			} {
+				evaluation, err := Explain(policy, batchWitnesses(tc.total, tc.collected))
+				require.NoError(t, err)
+				require.Equalf(t, tc.satisfied, evaluation.Satisfied, "policy: %s", policy)
+			}
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	witnesses := []*proof.WitnessProof{
+		{Type: proof.WitnessTypeBatch, Witness: "https://orb.domain1.com/services/orb", Proof: []byte("proof")},
+		{Type: proof.WitnessTypeSystem, Witness: "https://orb.domain2.com/services/orb"},
+		{Type: proof.WitnessTypeSystem, Witness: "https://orb.domain3.com/services/orb", Proof: []byte("proof")},
+	}
+
+	t.Run("success - basic policy", func(t *testing.T) {
+		evaluation, err := Explain("OutOf(1,system) OR MinPercent(100,batch)", witnesses)
+		require.NoError(t, err)
+		require.True(t, evaluation.Satisfied)
+		require.Equal(t, config.OR, evaluation.Result.Clause)
+		require.Len(t, evaluation.Result.Clauses, 2)
+
+		batch := evaluation.Result.Clauses[0]
+		require.Equal(t, config.RoleBatch, batch.Role)
+		require.Equal(t, "MinPercent(100,batch)", batch.Clause)
+		require.True(t, batch.Satisfied)
+		require.Equal(t, 1, batch.Total)
+		require.Equal(t, 1, batch.Collected)
+
+		system := evaluation.Result.Clauses[1]
+		require.Equal(t, config.RoleSystem, system.Role)
+		require.Equal(t, "OutOf(1,system) OR MinPercent(100,system)", system.Clause)
+		require.True(t, system.Satisfied)
+		require.Equal(t, 2, system.Total)
+		require.Equal(t, 1, system.Collected)
+		require.Equal(t, []string{"https://orb.domain2.com/services/orb"}, system.Pending)
+	})
+
+	t.Run("success - expression", func(t *testing.T) {
+		evaluation, err := Explain("MinDomains(3,any) OR (OutOf(1,batch) AND MinPercent(100,system))", witnesses)
+		require.NoError(t, err)
+		require.False(t, evaluation.Satisfied)
+		require.Equal(t, config.OR, evaluation.Result.Clause)
+		require.Len(t, evaluation.Result.Clauses, 2)
+
+		require.Equal(t, "MinDomains(3,any)", evaluation.Result.Clauses[0].Clause)
+		require.False(t, evaluation.Result.Clauses[0].Satisfied)
+
+		and := evaluation.Result.Clauses[1]
+		require.Equal(t, config.AND, and.Clause)
+		require.False(t, and.Satisfied)
+		require.Len(t, and.Clauses, 2)
+		require.True(t, and.Clauses[0].Satisfied)
+		require.False(t, and.Clauses[1].Satisfied)
+	})
+
+	t.Run("error - invalid policy", func(t *testing.T) {
+		evaluation, err := Explain("Test(1,2)", witnesses)
+		require.Error(t, err)
+		require.Nil(t, evaluation)
+		require.Contains(t, err.Error(), "rule not supported")
+	})
+}
+
 func TestGetWitnessPolicyConfig(t *testing.T) {
 	t.Run("success - policy config retrieved from the cache", func(t *testing.T) {
 		configStore, err := mem.NewProvider().OpenStore(configStoreName)
@@ -22,6 +22,7 @@ const endpoint = "/policy"
 
 const (
 	badRequestResponse          = "Bad Request."
+	notFoundResponse            = "Not Found."
 	internalServerErrorResponse = "Internal Server Error."
 )
 
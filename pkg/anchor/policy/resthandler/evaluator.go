@@ -0,0 +1,208 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/anchor/policy"
+	"github.com/trustbloc/orb/pkg/anchor/proof"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const evaluateEndpoint = endpoint + "/evaluate"
+
+// EvaluateRequest contains the candidate witness policy and the witness proofs to evaluate it against.
+// If Policy is empty then the currently configured witness policy is used. If AnchorCredentialID is
+// specified then the witnesses (and proofs collected so far) are loaded from the witness store.
+type EvaluateRequest struct {
+	Policy             string          `json:"policy,omitempty"`
+	AnchorCredentialID string          `json:"anchorCredentialId,omitempty"`
+	Witnesses          []*WitnessProof `json:"witnesses,omitempty"`
+}
+
+// WitnessProof contains a witness and, optionally, the proof provided by the witness.
+type WitnessProof struct {
+	Type    proof.WitnessType `json:"type"`
+	Witness string            `json:"witness"`
+	HasLog  bool              `json:"hasLog,omitempty"`
+	Proof   json.RawMessage   `json:"proof,omitempty"`
+}
+
+type witnessStore interface {
+	Get(vcID string) ([]*proof.WitnessProof, error)
+}
+
+// PolicyEvaluator performs a dry-run of a witness policy against a set of witness proofs and explains
+// which clauses of the policy passed or failed.
+type PolicyEvaluator struct {
+	configStore  storage.Store
+	witnessStore witnessStore
+	marshal      func(v interface{}) ([]byte, error)
+}
+
+// NewEvaluator returns a new PolicyEvaluator.
+func NewEvaluator(cfgStore storage.Store, witnessStore witnessStore) *PolicyEvaluator {
+	return &PolicyEvaluator{
+		configStore:  cfgStore,
+		witnessStore: witnessStore,
+		marshal:      json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the PolicyEvaluator service.
+func (pe *PolicyEvaluator) Path() string {
+	return evaluateEndpoint
+}
+
+// Method returns the HTTP REST method for the evaluate policy service.
+func (pe *PolicyEvaluator) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the PolicyEvaluator service.
+func (pe *PolicyEvaluator) Handler() common.HTTPRequestHandler {
+	return pe.handle
+}
+
+func (pe *PolicyEvaluator) handle(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		logger.Errorf("[%s] Error reading request body: %s", evaluateEndpoint, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	request := &EvaluateRequest{}
+
+	err = json.Unmarshal(reqBytes, request)
+	if err != nil {
+		logger.Errorf("[%s] Invalid evaluate policy request: %s", evaluateEndpoint, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	policyStr, err := pe.getPolicy(request)
+	if err != nil {
+		logger.Errorf("[%s] Error retrieving witness policy: %s", evaluateEndpoint, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	witnesses, status, err := pe.getWitnesses(request)
+	if err != nil {
+		logger.Errorf("[%s] Error retrieving witnesses: %s", evaluateEndpoint, err)
+
+		writeResponse(w, status, []byte(statusResponse(status)))
+
+		return
+	}
+
+	evaluation, err := policy.Explain(policyStr, witnesses)
+	if err != nil {
+		logger.Errorf("[%s] Invalid witness policy: %s", evaluateEndpoint, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	respBytes, err := pe.marshal(evaluation)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling policy evaluation: %s", evaluateEndpoint, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+func (pe *PolicyEvaluator) getPolicy(request *EvaluateRequest) (string, error) {
+	if request.Policy != "" {
+		return request.Policy, nil
+	}
+
+	policyBytes, err := pe.configStore.Get(policy.WitnessPolicyKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			// Default policy.
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return string(policyBytes), nil
+}
+
+func (pe *PolicyEvaluator) getWitnesses(request *EvaluateRequest) ([]*proof.WitnessProof, int, error) {
+	if request.AnchorCredentialID == "" {
+		if len(request.Witnesses) == 0 {
+			return nil, http.StatusBadRequest, fmt.Errorf("either witnesses or anchor credential ID must be provided")
+		}
+
+		witnesses := make([]*proof.WitnessProof, len(request.Witnesses))
+
+		for i, w := range request.Witnesses {
+			witnesses[i] = &proof.WitnessProof{
+				Type:    w.Type,
+				Witness: w.Witness,
+				HasLog:  w.HasLog,
+			}
+
+			if len(w.Proof) > 0 && string(w.Proof) != "null" {
+				witnesses[i].Proof = w.Proof
+			}
+		}
+
+		return witnesses, http.StatusOK, nil
+	}
+
+	if len(request.Witnesses) > 0 {
+		return nil, http.StatusBadRequest, fmt.Errorf("witnesses and anchor credential ID are mutually exclusive")
+	}
+
+	witnesses, err := pe.witnessStore.Get(request.AnchorCredentialID)
+	if err != nil {
+		if orberrors.IsTransient(err) {
+			return nil, http.StatusInternalServerError, err
+		}
+
+		return nil, http.StatusNotFound, fmt.Errorf("get witnesses for anchor credential [%s]: %w",
+			request.AnchorCredentialID, err)
+	}
+
+	return witnesses, http.StatusOK, nil
+}
+
+func statusResponse(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return badRequestResponse
+	case http.StatusNotFound:
+		return notFoundResponse
+	default:
+		return internalServerErrorResponse
+	}
+}
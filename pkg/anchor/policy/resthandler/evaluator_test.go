@@ -0,0 +1,222 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/anchor/policy"
+	"github.com/trustbloc/orb/pkg/anchor/proof"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	storemocks "github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const anchorCredID = "https://orb.domain1.com/vc/1234"
+
+func TestNewEvaluator(t *testing.T) {
+	configStore, err := mem.NewProvider().OpenStore(configStoreName)
+	require.NoError(t, err)
+
+	evaluator := NewEvaluator(configStore, &mockWitnessStore{})
+	require.NotNil(t, evaluator)
+	require.Equal(t, evaluateEndpoint, evaluator.Path())
+	require.Equal(t, http.MethodPost, evaluator.Method())
+	require.NotNil(t, evaluator.Handler())
+}
+
+func TestEvaluator(t *testing.T) {
+	witnesses := []*WitnessProof{
+		{Type: proof.WitnessTypeBatch, Witness: "https://orb.domain1.com/services/orb", Proof: []byte(`{}`)},
+		{Type: proof.WitnessTypeSystem, Witness: "https://orb.domain2.com/services/orb", Proof: []byte(`null`)},
+	}
+
+	t.Run("success - candidate policy with witnesses", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		evaluation, status := evaluate(t, NewEvaluator(configStore, &mockWitnessStore{}),
+			&EvaluateRequest{Policy: "OutOf(1,batch) OR OutOf(1,system)", Witnesses: witnesses})
+		require.Equal(t, http.StatusOK, status)
+		require.True(t, evaluation.Satisfied)
+		require.Len(t, evaluation.Result.Clauses, 2)
+		require.Equal(t, "batch", evaluation.Result.Clauses[0].Role)
+		require.True(t, evaluation.Result.Clauses[0].Satisfied)
+		require.Equal(t, "system", evaluation.Result.Clauses[1].Role)
+		require.False(t, evaluation.Result.Clauses[1].Satisfied)
+		require.Equal(t, []string{"https://orb.domain2.com/services/orb"}, evaluation.Result.Clauses[1].Pending)
+	})
+
+	t.Run("success - configured policy with anchor credential ID", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		require.NoError(t, configStore.Put(policy.WitnessPolicyKey, []byte(testPolicy)))
+
+		ws := &mockWitnessStore{
+			witnesses: []*proof.WitnessProof{
+				{Type: proof.WitnessTypeBatch, Witness: "https://orb.domain1.com/services/orb", Proof: []byte(`{}`)},
+				{Type: proof.WitnessTypeSystem, Witness: "https://orb.domain2.com/services/orb"},
+			},
+		}
+
+		evaluation, status := evaluate(t, NewEvaluator(configStore, ws),
+			&EvaluateRequest{AnchorCredentialID: anchorCredID})
+		require.Equal(t, http.StatusOK, status)
+		require.False(t, evaluation.Satisfied)
+		require.Equal(t, testPolicy, evaluation.Policy)
+	})
+
+	t.Run("success - default policy", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		evaluation, status := evaluate(t, NewEvaluator(configStore, &mockWitnessStore{}),
+			&EvaluateRequest{Witnesses: witnesses})
+		require.Equal(t, http.StatusOK, status)
+		require.False(t, evaluation.Satisfied)
+	})
+
+	t.Run("error - reader error", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		rw := httptest.NewRecorder()
+
+		NewEvaluator(configStore, &mockWitnessStore{}).handle(rw,
+			httptest.NewRequest(http.MethodPost, evaluateEndpoint, errReader(0)))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("error - invalid request", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		rw := httptest.NewRecorder()
+
+		NewEvaluator(configStore, &mockWitnessStore{}).handle(rw,
+			httptest.NewRequest(http.MethodPost, evaluateEndpoint, bytes.NewBufferString("{")))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("error - invalid policy", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		_, status := evaluate(t, NewEvaluator(configStore, &mockWitnessStore{}),
+			&EvaluateRequest{Policy: "InvalidPolicy", Witnesses: witnesses})
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("error - no witnesses", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		_, status := evaluate(t, NewEvaluator(configStore, &mockWitnessStore{}), &EvaluateRequest{})
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("error - witnesses and anchor credential ID", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		_, status := evaluate(t, NewEvaluator(configStore, &mockWitnessStore{}),
+			&EvaluateRequest{AnchorCredentialID: anchorCredID, Witnesses: witnesses})
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("error - anchor credential not found", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		ws := &mockWitnessStore{err: fmt.Errorf("not found")}
+
+		_, status := evaluate(t, NewEvaluator(configStore, ws), &EvaluateRequest{AnchorCredentialID: anchorCredID})
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("error - witness store error", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		ws := &mockWitnessStore{err: orberrors.NewTransient(errors.New("injected store error"))}
+
+		_, status := evaluate(t, NewEvaluator(configStore, ws), &EvaluateRequest{AnchorCredentialID: anchorCredID})
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("error - config store error", func(t *testing.T) {
+		configStore := &storemocks.Store{}
+		configStore.GetReturns(nil, fmt.Errorf("get error"))
+
+		_, status := evaluate(t, NewEvaluator(configStore, &mockWitnessStore{}), &EvaluateRequest{Witnesses: witnesses})
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("error - marshal error", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		evaluator := NewEvaluator(configStore, &mockWitnessStore{})
+		evaluator.marshal = func(v interface{}) ([]byte, error) {
+			return nil, errors.New("injected marshal error")
+		}
+
+		_, status := evaluate(t, evaluator, &EvaluateRequest{Witnesses: witnesses})
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+}
+
+func evaluate(t *testing.T, evaluator *PolicyEvaluator, request *EvaluateRequest) (*policy.Evaluation, int) {
+	t.Helper()
+
+	reqBytes, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+
+	evaluator.handle(rw, httptest.NewRequest(http.MethodPost, evaluateEndpoint, bytes.NewBuffer(reqBytes)))
+
+	result := rw.Result()
+
+	respBytes, err := ioutil.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+
+	if result.StatusCode != http.StatusOK {
+		return nil, result.StatusCode
+	}
+
+	evaluation := &policy.Evaluation{}
+	require.NoError(t, json.Unmarshal(respBytes, evaluation))
+
+	return evaluation, result.StatusCode
+}
+
+type mockWitnessStore struct {
+	witnesses []*proof.WitnessProof
+	err       error
+}
+
+func (m *mockWitnessStore) Get(string) ([]*proof.WitnessProof, error) {
+	return m.witnesses, m.err
+}
@@ -6,14 +6,18 @@ SPDX-License-Identifier: Apache-2.0
 
 package proof
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // WitnessProof contains anchor credential witness proof.
 type WitnessProof struct {
-	Type    WitnessType
-	Witness string
-	Proof   []byte
-	HasLog  bool
+	Type     WitnessType
+	Witness  string
+	Proof    []byte
+	HasLog   bool
+	Received time.Time
 }
 
 func (wf *WitnessProof) String() string {
@@ -40,6 +44,22 @@ const (
 	// VCStatusInProcess defines "in-process" status.
 	VCStatusInProcess VCStatus = "in-process"
 
+	// VCStatusWitnessed defines "witnessed" status (witness policy was satisfied and the anchor credential
+	// is being published).
+	VCStatusWitnessed VCStatus = "witnessed"
+
 	// VCStatusCompleted defines "completed" status.
 	VCStatusCompleted VCStatus = "completed"
+
+	// VCStatusTimedOut defines "timed-out" status (witness policy was not satisfied within the maximum witness delay).
+	VCStatusTimedOut VCStatus = "timed-out"
+
+	// VCStatusReOffered defines "re-offered" status (offer was re-sent to the same witnesses after a time-out).
+	VCStatusReOffered VCStatus = "re-offered"
+
+	// VCStatusEscalated defines "escalated" status (offer was sent to additional witnesses after a time-out).
+	VCStatusEscalated VCStatus = "escalated"
+
+	// VCStatusFailed defines "failed" status (anchor credential was abandoned and its operations were re-queued).
+	VCStatusFailed VCStatus = "failed"
 )
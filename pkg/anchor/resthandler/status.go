@@ -0,0 +1,363 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/anchor/policy"
+	"github.com/trustbloc/orb/pkg/anchor/proof"
+	"github.com/trustbloc/orb/pkg/didanchor"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/pendinganchor"
+	"github.com/trustbloc/orb/pkg/store/vcstatus"
+)
+
+const (
+	statusEndpoint = "/anchor/status"
+
+	idParam     = "id"
+	suffixParam = "suffix"
+
+	hashlinkPrefix = "hl:"
+)
+
+const (
+	badRequestResponse          = "Bad Request."
+	notFoundResponse            = "Not Found."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+var logger = log.New("anchor-rest-handler")
+
+// AnchorStatus contains the status of an anchor credential along with the witnesses that were offered
+// the anchor credential, the proofs received so far and the result of evaluating the witness policy.
+type AnchorStatus struct {
+	AnchorCredentialID string                  `json:"anchorCredentialId"`
+	Hashlink           string                  `json:"hashlink,omitempty"`
+	Status             proof.VCStatus          `json:"status"`
+	History            []*vcstatus.StatusEntry `json:"history,omitempty"`
+	Witnesses          []*Witness              `json:"witnesses,omitempty"`
+	Proofs             []verifiable.Proof      `json:"proofs,omitempty"`
+	PolicyEvaluation   *policy.Evaluation      `json:"policyEvaluation,omitempty"`
+}
+
+// Witness contains a witness that was offered the anchor credential and the proof received from
+// the witness (if any).
+type Witness struct {
+	Type     proof.WitnessType `json:"type"`
+	Witness  string            `json:"witness"`
+	HasLog   bool              `json:"hasLog,omitempty"`
+	Proof    json.RawMessage   `json:"proof,omitempty"`
+	Received *time.Time        `json:"received,omitempty"`
+}
+
+// Providers contains the providers required by the status handler.
+type Providers struct {
+	VCStatusStore      vcStatusStore
+	WitnessStore       witnessStore
+	VCStore            vcStore
+	AnchorGraph        anchorGraph
+	PendingAnchorStore pendingAnchorStore
+	DidAnchors         didAnchors
+	ConfigStore        storage.Store
+}
+
+type vcStatusStore interface {
+	GetStatus(vcID string) (proof.VCStatus, error)
+	GetStatusHistory(vcID string) ([]*vcstatus.StatusEntry, error)
+}
+
+type witnessStore interface {
+	Get(vcID string) ([]*proof.WitnessProof, error)
+}
+
+type vcStore interface {
+	Get(id string) (*verifiable.Credential, error)
+}
+
+type anchorGraph interface {
+	Read(hl string) (*verifiable.Credential, error)
+}
+
+type pendingAnchorStore interface {
+	GetBySuffix(suffix string) (*pendinganchor.Entry, error)
+}
+
+type didAnchors interface {
+	Get(suffix string) (string, error)
+}
+
+// StatusHandler returns the status of an anchor credential given the anchor credential ID, the hashlink
+// of the anchor credential or the suffix of a DID whose operation was included in the anchor.
+type StatusHandler struct {
+	*Providers
+
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewStatusHandler returns a new anchor status handler.
+func NewStatusHandler(providers *Providers) *StatusHandler {
+	return &StatusHandler{
+		Providers: providers,
+		marshal:   json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the anchor status service.
+func (h *StatusHandler) Path() string {
+	return statusEndpoint
+}
+
+// Method returns the HTTP REST method for the anchor status service.
+func (h *StatusHandler) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the anchor status service.
+func (h *StatusHandler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *StatusHandler) handle(w http.ResponseWriter, req *http.Request) {
+	id := getParam(req, idParam)
+	suffix := getParam(req, suffixParam)
+
+	if (id == "") == (suffix == "") {
+		logger.Infof("[%s] Either '%s' or '%s' must be specified", statusEndpoint, idParam, suffixParam)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	var (
+		status *AnchorStatus
+		err    error
+	)
+
+	if suffix != "" {
+		status, err = h.getStatusForSuffix(suffix)
+	} else {
+		status, err = h.getStatus(id)
+	}
+
+	if err != nil {
+		if orberrors.IsTransient(err) {
+			logger.Errorf("[%s] Error retrieving anchor status: %s", statusEndpoint, err)
+
+			writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+		} else {
+			logger.Debugf("[%s] Anchor status not found: %s", statusEndpoint, err)
+
+			writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+		}
+
+		return
+	}
+
+	respBytes, err := h.marshal(status)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling anchor status: %s", statusEndpoint, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+// getStatusForSuffix returns the status of the anchor that includes the most recent operation for the
+// given suffix. If the anchor is still pending then it is retrieved from the pending anchor store,
+// otherwise the latest anchor for the suffix is retrieved from the DID anchor store.
+func (h *StatusHandler) getStatusForSuffix(suffix string) (*AnchorStatus, error) {
+	entry, err := h.PendingAnchorStore.GetBySuffix(suffix)
+	if err == nil {
+		return h.getStatus(entry.VCID)
+	}
+
+	if !errors.Is(err, pendinganchor.ErrNotFound) {
+		return nil, err
+	}
+
+	hl, err := h.DidAnchors.Get(suffix)
+	if err != nil {
+		if errors.Is(err, didanchor.ErrDataNotFound) {
+			return nil, fmt.Errorf("anchor not found for suffix [%s]", suffix)
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("get anchor for suffix [%s]: %w", suffix, err))
+	}
+
+	return h.getStatus(hl)
+}
+
+func (h *StatusHandler) getStatus(id string) (*AnchorStatus, error) {
+	status := &AnchorStatus{AnchorCredentialID: id}
+
+	var vc *verifiable.Credential
+
+	if strings.HasPrefix(id, hashlinkPrefix) {
+		var err error
+
+		vc, err = h.AnchorGraph.Read(id)
+		if err != nil {
+			return nil, fmt.Errorf("read anchor credential for hashlink [%s]: %w", id, err)
+		}
+
+		status.Hashlink = id
+		status.AnchorCredentialID = vc.ID
+	} else {
+		vc = h.getCredential(id)
+	}
+
+	err := h.setStatus(status)
+	if err != nil {
+		return nil, err
+	}
+
+	witnesses, err := h.WitnessStore.Get(status.AnchorCredentialID)
+	if err != nil {
+		if orberrors.IsTransient(err) {
+			return nil, err
+		}
+
+		// The witnesses are deleted once the anchor has been published.
+		logger.Debugf("No witnesses found for anchor credential [%s]: %s", status.AnchorCredentialID, err)
+	}
+
+	status.Witnesses = toWitnesses(witnesses)
+
+	if vc != nil {
+		status.Proofs = vc.Proofs
+	}
+
+	// The policy can only be evaluated for pending anchors.
+	if len(witnesses) > 0 {
+		status.PolicyEvaluation, err = h.evaluatePolicy(witnesses)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return status, nil
+}
+
+func (h *StatusHandler) setStatus(status *AnchorStatus) error {
+	vcStatus, err := h.VCStatusStore.GetStatus(status.AnchorCredentialID)
+	if err != nil {
+		if orberrors.IsTransient(err) || status.Hashlink == "" {
+			return err
+		}
+
+		// Anchors are only added to the anchor graph after they have been witnessed, so an anchor that
+		// was created by another server (and therefore has no local status) is complete.
+		status.Status = proof.VCStatusCompleted
+
+		return nil
+	}
+
+	status.Status = vcStatus
+
+	status.History, err = h.VCStatusStore.GetStatusHistory(status.AnchorCredentialID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (h *StatusHandler) getCredential(vcID string) *verifiable.Credential {
+	vc, err := h.VCStore.Get(vcID)
+	if err != nil {
+		// The anchor credential may not be stored locally (for example if it was anchored by another server).
+		logger.Debugf("Unable to retrieve anchor credential [%s]: %s", vcID, err)
+
+		return nil
+	}
+
+	return vc
+}
+
+func (h *StatusHandler) evaluatePolicy(witnesses []*proof.WitnessProof) (*policy.Evaluation, error) {
+	policyStr := ""
+
+	policyBytes, err := h.ConfigStore.Get(policy.WitnessPolicyKey)
+	if err != nil {
+		if !errors.Is(err, storage.ErrDataNotFound) {
+			return nil, orberrors.NewTransient(fmt.Errorf("get witness policy: %w", err))
+		}
+
+		// Default policy.
+	} else {
+		policyStr = string(policyBytes)
+	}
+
+	evaluation, err := policy.Explain(policyStr, witnesses)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("evaluate witness policy: %w", err))
+	}
+
+	return evaluation, nil
+}
+
+func toWitnesses(witnessProofs []*proof.WitnessProof) []*Witness {
+	witnesses := make([]*Witness, len(witnessProofs))
+
+	for i, wp := range witnessProofs {
+		witnesses[i] = &Witness{
+			Type:    wp.Type,
+			Witness: wp.Witness,
+			HasLog:  wp.HasLog,
+			Proof:   wp.Proof,
+		}
+
+		if !wp.Received.IsZero() {
+			received := wp.Received
+
+			witnesses[i].Received = &received
+		}
+	}
+
+	return witnesses
+}
+
+func getParam(req *http.Request, name string) string {
+	values := req.URL.Query()[name]
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", statusEndpoint, err)
+
+			return
+		}
+
+		logger.Debugf("[%s] Wrote response: %s", statusEndpoint, body)
+	}
+}
@@ -0,0 +1,346 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/anchor/policy"
+	"github.com/trustbloc/orb/pkg/anchor/proof"
+	"github.com/trustbloc/orb/pkg/didanchor/memdidanchor"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	storemocks "github.com/trustbloc/orb/pkg/store/mocks"
+	"github.com/trustbloc/orb/pkg/store/pendinganchor"
+	"github.com/trustbloc/orb/pkg/store/vcstatus"
+	witnessstore "github.com/trustbloc/orb/pkg/store/witness"
+)
+
+const (
+	vcID     = "https://orb.domain1.com/vc/1234"
+	hl       = "hl:uEiDaapVGeNi9YFCzCoOBTV7x5UlKmxWw1SHtTKwjVv5UYw"
+	suffix   = "EiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA"
+	witness1 = "https://orb.domain2.com/services/orb"
+	witness2 = "https://orb.domain3.com/services/orb"
+)
+
+func TestNewStatusHandler(t *testing.T) {
+	h := NewStatusHandler(&Providers{})
+	require.NotNil(t, h)
+	require.Equal(t, statusEndpoint, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+}
+
+func TestStatusHandler(t *testing.T) {
+	vc := &verifiable.Credential{
+		ID:     vcID,
+		Proofs: []verifiable.Proof{{"created": "2021-08-12T17:00:00Z", "domain": "https://orb.domain2.com"}},
+	}
+
+	t.Run("success - pending anchor by ID", func(t *testing.T) {
+		providers := newProviders(t)
+
+		require.NoError(t, providers.VCStatusStore.(*vcstatus.Store).AddStatus(vcID, proof.VCStatusInProcess))
+
+		ws := providers.WitnessStore.(*witnessstore.Store)
+
+		require.NoError(t, ws.Put(vcID, []*proof.WitnessProof{
+			{Type: proof.WitnessTypeBatch, Witness: witness1},
+			{Type: proof.WitnessTypeSystem, Witness: witness2},
+		}))
+		require.NoError(t, ws.AddProof(vcID, witness1, []byte(`{"created":"2021-08-12T17:00:00Z"}`)))
+
+		providers.VCStore = &mockVCStore{vc: vc}
+
+		status, code := getStatus(t, NewStatusHandler(providers), idParam, vcID)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, vcID, status.AnchorCredentialID)
+		require.Equal(t, proof.VCStatusInProcess, status.Status)
+		require.Len(t, status.History, 1)
+		require.Len(t, status.Witnesses, 2)
+		require.Len(t, status.Proofs, 1)
+
+		for _, w := range status.Witnesses {
+			if w.Witness == witness1 {
+				require.NotNil(t, w.Received)
+				require.NotEmpty(t, w.Proof)
+			} else {
+				require.Nil(t, w.Received)
+				require.Empty(t, w.Proof)
+			}
+		}
+
+		require.NotNil(t, status.PolicyEvaluation)
+		require.False(t, status.PolicyEvaluation.Satisfied)
+	})
+
+	t.Run("success - pending anchor by suffix", func(t *testing.T) {
+		providers := newProviders(t)
+
+		require.NoError(t, providers.VCStatusStore.(*vcstatus.Store).AddStatus(vcID, proof.VCStatusInProcess))
+		require.NoError(t, providers.VCStatusStore.(*vcstatus.Store).AddStatus(vcID, proof.VCStatusTimedOut))
+		require.NoError(t, providers.PendingAnchorStore.(*pendinganchor.Store).Put(&pendinganchor.Entry{
+			VCID:     vcID,
+			Suffixes: []string{suffix},
+		}))
+
+		status, code := getStatus(t, NewStatusHandler(providers), suffixParam, suffix)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, vcID, status.AnchorCredentialID)
+		require.Equal(t, proof.VCStatusTimedOut, status.Status)
+		require.Len(t, status.History, 2)
+		require.Empty(t, status.Witnesses)
+		require.Nil(t, status.PolicyEvaluation)
+	})
+
+	t.Run("success - completed anchor by suffix", func(t *testing.T) {
+		providers := newProviders(t)
+
+		require.NoError(t, providers.VCStatusStore.(*vcstatus.Store).AddStatus(vcID, proof.VCStatusCompleted))
+		require.NoError(t, providers.DidAnchors.(*memdidanchor.DidAnchor).PutBulk([]string{suffix}, hl))
+
+		providers.AnchorGraph = &mockAnchorGraph{vc: vc}
+
+		status, code := getStatus(t, NewStatusHandler(providers), suffixParam, suffix)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, vcID, status.AnchorCredentialID)
+		require.Equal(t, hl, status.Hashlink)
+		require.Equal(t, proof.VCStatusCompleted, status.Status)
+		require.Len(t, status.Proofs, 1)
+	})
+
+	t.Run("success - anchor from another server by hashlink", func(t *testing.T) {
+		providers := newProviders(t)
+		providers.AnchorGraph = &mockAnchorGraph{vc: vc}
+
+		status, code := getStatus(t, NewStatusHandler(providers), idParam, hl)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, vcID, status.AnchorCredentialID)
+		require.Equal(t, proof.VCStatusCompleted, status.Status)
+		require.Empty(t, status.History)
+	})
+
+	t.Run("error - no parameters", func(t *testing.T) {
+		_, code := getStatus(t, NewStatusHandler(newProviders(t)), "", "")
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("error - ID and suffix", func(t *testing.T) {
+		h := NewStatusHandler(newProviders(t))
+
+		rw := httptest.NewRecorder()
+
+		h.handle(rw, httptest.NewRequest(http.MethodGet,
+			fmt.Sprintf("%s?%s=%s&%s=%s", statusEndpoint, idParam, url.QueryEscape(vcID), suffixParam, suffix), nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("error - anchor credential not found", func(t *testing.T) {
+		_, code := getStatus(t, NewStatusHandler(newProviders(t)), idParam, vcID)
+		require.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("error - suffix not found", func(t *testing.T) {
+		_, code := getStatus(t, NewStatusHandler(newProviders(t)), suffixParam, suffix)
+		require.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("error - hashlink not found", func(t *testing.T) {
+		providers := newProviders(t)
+		providers.AnchorGraph = &mockAnchorGraph{err: errors.New("not found")}
+
+		_, code := getStatus(t, NewStatusHandler(providers), idParam, hl)
+		require.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("error - pending anchor store error", func(t *testing.T) {
+		providers := newProviders(t)
+		providers.PendingAnchorStore = &mockPendingAnchorStore{
+			err: orberrors.NewTransient(errors.New("injected store error")),
+		}
+
+		_, code := getStatus(t, NewStatusHandler(providers), suffixParam, suffix)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("error - DID anchor store error", func(t *testing.T) {
+		providers := newProviders(t)
+		providers.DidAnchors = &mockDidAnchors{err: errors.New("injected store error")}
+
+		_, code := getStatus(t, NewStatusHandler(providers), suffixParam, suffix)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("error - witness store error", func(t *testing.T) {
+		providers := newProviders(t)
+
+		require.NoError(t, providers.VCStatusStore.(*vcstatus.Store).AddStatus(vcID, proof.VCStatusInProcess))
+
+		providers.WitnessStore = &mockWitnessStore{err: orberrors.NewTransient(errors.New("injected store error"))}
+
+		_, code := getStatus(t, NewStatusHandler(providers), idParam, vcID)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("error - config store error", func(t *testing.T) {
+		providers := newProviders(t)
+
+		require.NoError(t, providers.VCStatusStore.(*vcstatus.Store).AddStatus(vcID, proof.VCStatusInProcess))
+
+		configStore := &storemocks.Store{}
+		configStore.GetReturns(nil, errors.New("injected get error"))
+
+		providers.ConfigStore = configStore
+		providers.WitnessStore = &mockWitnessStore{
+			witnesses: []*proof.WitnessProof{{Type: proof.WitnessTypeBatch, Witness: witness1}},
+		}
+
+		_, code := getStatus(t, NewStatusHandler(providers), idParam, vcID)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("error - invalid policy", func(t *testing.T) {
+		providers := newProviders(t)
+
+		require.NoError(t, providers.VCStatusStore.(*vcstatus.Store).AddStatus(vcID, proof.VCStatusInProcess))
+		require.NoError(t, providers.ConfigStore.Put(policy.WitnessPolicyKey, []byte("InvalidPolicy")))
+
+		providers.WitnessStore = &mockWitnessStore{
+			witnesses: []*proof.WitnessProof{{Type: proof.WitnessTypeBatch, Witness: witness1}},
+		}
+
+		_, code := getStatus(t, NewStatusHandler(providers), idParam, vcID)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("error - marshal error", func(t *testing.T) {
+		providers := newProviders(t)
+
+		require.NoError(t, providers.VCStatusStore.(*vcstatus.Store).AddStatus(vcID, proof.VCStatusInProcess))
+
+		h := NewStatusHandler(providers)
+		h.marshal = func(v interface{}) ([]byte, error) {
+			return nil, errors.New("injected marshal error")
+		}
+
+		_, code := getStatus(t, h, idParam, vcID)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+func newProviders(t *testing.T) *Providers {
+	t.Helper()
+
+	provider := mem.NewProvider()
+
+	vcStatusStore, err := vcstatus.New(provider)
+	require.NoError(t, err)
+
+	ws, err := witnessstore.New(provider)
+	require.NoError(t, err)
+
+	pendingStore, err := pendinganchor.New(provider)
+	require.NoError(t, err)
+
+	configStore, err := provider.OpenStore("orb-config")
+	require.NoError(t, err)
+
+	return &Providers{
+		VCStatusStore:      vcStatusStore,
+		WitnessStore:       ws,
+		VCStore:            &mockVCStore{err: errors.New("not found")},
+		AnchorGraph:        &mockAnchorGraph{err: errors.New("not found")},
+		PendingAnchorStore: pendingStore,
+		DidAnchors:         memdidanchor.New(),
+		ConfigStore:        configStore,
+	}
+}
+
+func getStatus(t *testing.T, h *StatusHandler, param, value string) (*AnchorStatus, int) {
+	t.Helper()
+
+	target := statusEndpoint
+	if param != "" {
+		target = fmt.Sprintf("%s?%s=%s", statusEndpoint, param, url.QueryEscape(value))
+	}
+
+	rw := httptest.NewRecorder()
+
+	h.handle(rw, httptest.NewRequest(http.MethodGet, target, nil))
+
+	result := rw.Result()
+
+	respBytes, err := ioutil.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+
+	if result.StatusCode != http.StatusOK {
+		return nil, result.StatusCode
+	}
+
+	status := &AnchorStatus{}
+	require.NoError(t, json.Unmarshal(respBytes, status))
+
+	return status, result.StatusCode
+}
+
+type mockVCStore struct {
+	vc  *verifiable.Credential
+	err error
+}
+
+func (m *mockVCStore) Get(string) (*verifiable.Credential, error) {
+	return m.vc, m.err
+}
+
+type mockAnchorGraph struct {
+	vc  *verifiable.Credential
+	err error
+}
+
+func (m *mockAnchorGraph) Read(string) (*verifiable.Credential, error) {
+	return m.vc, m.err
+}
+
+type mockPendingAnchorStore struct {
+	err error
+}
+
+func (m *mockPendingAnchorStore) GetBySuffix(string) (*pendinganchor.Entry, error) {
+	return nil, m.err
+}
+
+type mockDidAnchors struct {
+	err error
+}
+
+func (m *mockDidAnchors) Get(string) (string, error) {
+	return "", m.err
+}
+
+type mockWitnessStore struct {
+	witnesses []*proof.WitnessProof
+	err       error
+}
+
+func (m *mockWitnessStore) Get(string) ([]*proof.WitnessProof, error) {
+	return m.witnesses, m.err
+}
@@ -0,0 +1,440 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/orb/pkg/activitypub/client"
+	apstore "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	anchorinfo "github.com/trustbloc/orb/pkg/anchor/info"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/lifecycle"
+)
+
+const namespace = "anchorsync"
+
+var logger = log.New("anchor-resync")
+
+// ErrNotFound is returned if a checkpoint is not found for the peer.
+var ErrNotFound = errors.New("checkpoint not found")
+
+// ErrStopped is returned by Resync if the service was stopped before synchronization completed.
+var ErrStopped = errors.New("anchor synchronization stopped")
+
+// Checkpoint contains the progress of synchronizing the anchor history of a peer. The checkpoint is
+// saved after each activity so that synchronization may be resumed after a restart.
+type Checkpoint struct {
+	Peer                string     `json:"peer"`
+	Outbox              string     `json:"outbox"`
+	TotalActivities     int        `json:"totalActivities"`
+	ActivitiesProcessed int        `json:"activitiesProcessed"`
+	AnchorsPublished    int        `json:"anchorsPublished"`
+	AnchorsSkipped      int        `json:"anchorsSkipped"`
+	AnchorsFailed       int        `json:"anchorsFailed"`
+	LastActivityID      string     `json:"lastActivityId,omitempty"`
+	Started             time.Time  `json:"started"`
+	Updated             time.Time  `json:"updated"`
+	Completed           *time.Time `json:"completed,omitempty"`
+}
+
+type activityPubClient interface {
+	GetActor(actorIRI *url.URL) (*vocab.ActorType, error)
+	GetActivities(iri *url.URL, order client.Order) (client.ActivityIterator, error)
+}
+
+type casResolver interface {
+	Resolve(webCASURL *url.URL, hl string, data []byte) ([]byte, error)
+}
+
+type anchorPublisher interface {
+	PublishAnchor(anchor *anchorinfo.AnchorInfo) error
+}
+
+type activityStore interface {
+	AddReference(refType apstore.ReferenceType, objectIRI *url.URL, referenceIRI *url.URL) error
+	QueryReferences(refType apstore.ReferenceType, query *apstore.Criteria,
+		opts ...apstore.QueryOpt) (apstore.ReferenceIterator, error)
+}
+
+// Providers contains the providers required by the anchor synchronization service.
+type Providers struct {
+	ActivityPubClient activityPubClient
+	CASResolver       casResolver
+	AnchorPublisher   anchorPublisher
+	ActivityStore     activityStore
+	StoreProvider     storage.Provider
+}
+
+// Service synchronizes the anchor history of one or more peers. The outbox of each peer is paged through and
+// the anchor credential referenced by each 'Create' and 'Announce' activity is retrieved from CAS and published
+// to the observer. This allows a new node to resolve all of the DIDs known to the peer and not just the DIDs
+// announced after the node started following the peer.
+type Service struct {
+	*lifecycle.Lifecycle
+	*Providers
+
+	serviceIRI *url.URL
+	peers      []*url.URL
+	store      storage.Store
+	done       chan struct{}
+}
+
+// New returns a new anchor synchronization service which synchronizes anchors from the given peers
+// when the service is started.
+func New(serviceIRI *url.URL, peers []*url.URL, providers *Providers) (*Service, error) {
+	store, err := providers.StoreProvider.OpenStore(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open anchor sync store: %w", err)
+	}
+
+	s := &Service{
+		Providers:  providers,
+		serviceIRI: serviceIRI,
+		peers:      peers,
+		store:      store,
+		done:       make(chan struct{}),
+	}
+
+	s.Lifecycle = lifecycle.New("anchor-resync",
+		lifecycle.WithStart(s.start),
+		lifecycle.WithStop(s.stop))
+
+	return s, nil
+}
+
+// GetCheckpoint returns the synchronization checkpoint for the given peer.
+func (s *Service) GetCheckpoint(peer *url.URL) (*Checkpoint, error) {
+	cpBytes, err := s.store.Get(peer.String())
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("get checkpoint for peer [%s]: %w", peer, err))
+	}
+
+	cp := &Checkpoint{}
+
+	err = json.Unmarshal(cpBytes, cp)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal checkpoint for peer [%s]: %w", peer, err)
+	}
+
+	return cp, nil
+}
+
+// Resync synchronizes the anchors in the outbox of the given peer, from the oldest to the newest so that the
+// latest anchor of each DID is published last. If a previous synchronization did not complete then it's resumed
+// after the last activity of the previous checkpoint. Anchors that were already synchronized (either by a previous
+// run or through the inbox) are skipped.
+func (s *Service) Resync(peer *url.URL) (*Checkpoint, error) {
+	cp, err := s.loadCheckpoint(peer)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := s.ActivityPubClient.GetActor(peer)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("get actor [%s]: %w", peer, err))
+	}
+
+	if actor.Outbox() == nil {
+		return nil, fmt.Errorf("actor [%s] has no outbox", peer)
+	}
+
+	cp.Outbox = actor.Outbox().String()
+
+	found, err := s.sync(actor.Outbox(), cp, cp.LastActivityID)
+	if err != nil {
+		return cp, err
+	}
+
+	if !found {
+		logger.Warnf("Activity [%s] of the checkpoint for peer [%s] was not found in the outbox. "+
+			"Synchronizing from the start of the outbox.", cp.LastActivityID, peer)
+
+		if _, err := s.sync(actor.Outbox(), cp, ""); err != nil {
+			return cp, err
+		}
+	}
+
+	completed := time.Now()
+	cp.Completed = &completed
+
+	if err := s.saveCheckpoint(cp); err != nil {
+		return cp, err
+	}
+
+	logger.Infof("Completed synchronizing anchors from peer [%s]. Activities: %d, Published: %d, Skipped: %d, "+
+		"Failed: %d", peer, cp.ActivitiesProcessed, cp.AnchorsPublished, cp.AnchorsSkipped, cp.AnchorsFailed)
+
+	return cp, nil
+}
+
+// sync processes the activities in the given outbox from the oldest to the newest. The outbox is sorted in
+// descending order, so it's traversed in reverse. If resumeAfter is set then the activities up to and including
+// the activity with that ID (which were processed by a previous run) are skipped. False is returned if the
+// activity with the ID of resumeAfter was not found.
+func (s *Service) sync(outbox *url.URL, cp *Checkpoint, resumeAfter string) (bool, error) {
+	it, err := s.ActivityPubClient.GetActivities(outbox, client.Reverse)
+	if err != nil {
+		return false, orberrors.NewTransient(fmt.Errorf("get activities from outbox [%s]: %w", outbox, err))
+	}
+
+	cp.TotalActivities = it.TotalItems()
+
+	logger.Infof("Synchronizing anchors from outbox [%s] of peer [%s]. Total activities: %d",
+		cp.Outbox, cp.Peer, cp.TotalActivities)
+
+	resuming := resumeAfter != ""
+
+	for {
+		select {
+		case <-s.done:
+			return true, ErrStopped
+		default:
+		}
+
+		activity, err := it.Next()
+		if err != nil {
+			if errors.Is(err, client.ErrNotFound) {
+				return !resuming, nil
+			}
+
+			return true, orberrors.NewTransient(fmt.Errorf("get next activity from outbox [%s]: %w", cp.Outbox, err))
+		}
+
+		if resuming {
+			if activity.ID().String() == resumeAfter {
+				logger.Debugf("Resuming after activity [%s]", resumeAfter)
+
+				resuming = false
+			}
+
+			continue
+		}
+
+		err = s.processActivity(activity, cp)
+		if err != nil {
+			return true, err
+		}
+
+		cp.ActivitiesProcessed++
+		cp.LastActivityID = activity.ID().String()
+
+		if err := s.saveCheckpoint(cp); err != nil {
+			return true, err
+		}
+	}
+}
+
+func (s *Service) start() {
+	go s.resyncAll()
+
+	logger.Infof("Started anchor synchronization service")
+}
+
+func (s *Service) stop() {
+	close(s.done)
+
+	logger.Infof("Stopped anchor synchronization service")
+}
+
+func (s *Service) resyncAll() {
+	for _, peer := range s.peers {
+		_, err := s.Resync(peer)
+		if err != nil {
+			if errors.Is(err, ErrStopped) {
+				logger.Infof("Anchor synchronization from peer [%s] was stopped and will resume on restart", peer)
+
+				return
+			}
+
+			logger.Errorf("Error synchronizing anchors from peer [%s]: %s", peer, err)
+		}
+	}
+}
+
+func (s *Service) processActivity(activity *vocab.ActivityType, cp *Checkpoint) error {
+	var refs []*vocab.AnchorCredentialReferenceType
+
+	switch {
+	case activity.Type().Is(vocab.TypeCreate):
+		if ref := activity.Object().AnchorCredentialReference(); ref != nil {
+			refs = append(refs, ref)
+		}
+
+	case activity.Type().Is(vocab.TypeAnnounce):
+		refs = getAnnouncedRefs(activity)
+
+	default:
+		logger.Debugf("Ignoring activity [%s] of type %s", activity.ID(), activity.Type())
+
+		return nil
+	}
+
+	for _, ref := range refs {
+		err := s.processAnchorCredentialRef(ref, cp)
+		if err != nil {
+			if orberrors.IsTransient(err) {
+				return err
+			}
+
+			logger.Warnf("Unable to synchronize anchor from activity [%s]: %s", activity.ID(), err)
+
+			cp.AnchorsFailed++
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) processAnchorCredentialRef(ref *vocab.AnchorCredentialReferenceType, cp *Checkpoint) error {
+	target := ref.Target()
+
+	if target == nil || target.Object() == nil || !target.Type().Is(vocab.TypeContentAddressedStorage) {
+		return fmt.Errorf("unsupported target in anchor credential reference [%s]", ref.ID())
+	}
+
+	targetIRI := target.Object().ID().URL()
+	hl := target.Object().CID()
+
+	ok, err := s.hasReference(targetIRI)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		logger.Debugf("Skipping anchor [%s] since it was already synchronized", hl)
+
+		cp.AnchorsSkipped++
+
+		return nil
+	}
+
+	_, err = s.CASResolver.Resolve(targetIRI, hl, nil)
+	if err != nil {
+		return fmt.Errorf("resolve anchor [%s]: %w", hl, err)
+	}
+
+	err = s.AnchorPublisher.PublishAnchor(&anchorinfo.AnchorInfo{Hashlink: hl})
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("publish anchor [%s]: %w", hl, err))
+	}
+
+	err = s.ActivityStore.AddReference(apstore.AnchorCredential, targetIRI, s.serviceIRI)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store anchor credential reference: %w", err))
+	}
+
+	logger.Debugf("Published anchor [%s]", hl)
+
+	cp.AnchorsPublished++
+
+	return nil
+}
+
+func (s *Service) hasReference(targetIRI *url.URL) (bool, error) {
+	it, err := s.ActivityStore.QueryReferences(apstore.AnchorCredential,
+		apstore.NewCriteria(
+			apstore.WithObjectIRI(targetIRI),
+			apstore.WithReferenceIRI(s.serviceIRI),
+		),
+	)
+	if err != nil {
+		return false, orberrors.NewTransient(fmt.Errorf("query references: %w", err))
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Errorf("failed to close iterator: %s", e)
+		}
+	}()
+
+	_, err = it.Next()
+	if err != nil {
+		if errors.Is(err, apstore.ErrNotFound) {
+			return false, nil
+		}
+
+		return false, orberrors.NewTransient(fmt.Errorf("get next reference: %w", err))
+	}
+
+	return true, nil
+}
+
+func (s *Service) loadCheckpoint(peer *url.URL) (*Checkpoint, error) {
+	now := time.Now()
+
+	cp, err := s.GetCheckpoint(peer)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+
+		return &Checkpoint{Peer: peer.String(), Started: now, Updated: now}, nil
+	}
+
+	if cp.Completed != nil {
+		// The previous synchronization completed so start a new one in order to catch up
+		// on any anchors that were missed since then.
+		return &Checkpoint{Peer: peer.String(), Started: now, Updated: now}, nil
+	}
+
+	logger.Infof("Resuming anchor synchronization from peer [%s] after activity [%s]. Activities processed: %d",
+		peer, cp.LastActivityID, cp.ActivitiesProcessed)
+
+	return cp, nil
+}
+
+func (s *Service) saveCheckpoint(cp *Checkpoint) error {
+	cp.Updated = time.Now()
+
+	cpBytes, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("marshal checkpoint: %w", err)
+	}
+
+	err = s.store.Put(cp.Peer, cpBytes)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("save checkpoint for peer [%s]: %w", cp.Peer, err))
+	}
+
+	return nil
+}
+
+func getAnnouncedRefs(announce *vocab.ActivityType) []*vocab.AnchorCredentialReferenceType {
+	var items []*vocab.ObjectProperty
+
+	obj := announce.Object()
+
+	switch {
+	case obj.Collection() != nil:
+		items = obj.Collection().Items()
+	case obj.OrderedCollection() != nil:
+		items = obj.OrderedCollection().Items()
+	}
+
+	var refs []*vocab.AnchorCredentialReferenceType
+
+	for _, item := range items {
+		if ref := item.AnchorCredentialReference(); ref != nil {
+			refs = append(refs, ref)
+		}
+	}
+
+	return refs
+}
@@ -0,0 +1,473 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resync
+
+import (
+	"errors"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/client"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	anchorinfo "github.com/trustbloc/orb/pkg/anchor/info"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/aptestutil"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/lifecycle"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	hl1 = "hl:uEiDaapVGD0bDkA9uH1C4mhAm5N4rJvLGsJZfsJsOQbU0Zw"
+	hl2 = "hl:uEiCJWXtVVmC4MlNw8VPGDKzyRz9xJBnvbx3k8OanDZlE3w"
+	hl3 = "hl:uEiB2Hk7iCvmMHebzVmQxBxFKL5cddRUWgMyzGxqgrBBN3A"
+)
+
+var (
+	serviceIRI = testutil.MustParseURL("https://orb.domain1.com/services/orb")
+	peerIRI    = testutil.MustParseURL("https://orb.domain2.com/services/orb")
+	outboxIRI  = testutil.MustParseURL("https://orb.domain2.com/services/orb/outbox")
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, err := New(serviceIRI, nil, &Providers{StoreProvider: mem.NewProvider()})
+		require.NoError(t, err)
+		require.NotNil(t, s)
+	})
+
+	t.Run("open store error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(nil, errors.New("injected open store error"))
+
+		s, err := New(serviceIRI, nil, &Providers{StoreProvider: p})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open store error")
+		require.Nil(t, s)
+	})
+}
+
+func TestService_Resync(t *testing.T) {
+	// The activities are in the order in which the outbox serves them, i.e. newest first.
+	activities := []*vocab.ActivityType{
+		newAnnounceActivity("https://orb.domain2.com/activities/announce1", hl2, hl3),
+		vocab.NewFollowActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(serviceIRI)),
+			vocab.WithID(testutil.MustParseURL("https://orb.domain2.com/activities/follow1")),
+		),
+		newCreateActivity("https://orb.domain2.com/activities/create1", hl1),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		publisher := &mockPublisher{}
+
+		s, err := New(serviceIRI, []*url.URL{peerIRI}, &Providers{
+			ActivityPubClient: &mockAPClient{activities: activities},
+			CASResolver:       &mockCASResolver{},
+			AnchorPublisher:   publisher,
+			ActivityStore:     memstore.New(""),
+			StoreProvider:     mem.NewProvider(),
+		})
+		require.NoError(t, err)
+
+		cp, err := s.Resync(peerIRI)
+		require.NoError(t, err)
+		require.NotNil(t, cp.Completed)
+		require.Equal(t, outboxIRI.String(), cp.Outbox)
+		require.Equal(t, 3, cp.TotalActivities)
+		require.Equal(t, 3, cp.ActivitiesProcessed)
+		require.Equal(t, 3, cp.AnchorsPublished)
+		require.Equal(t, 0, cp.AnchorsSkipped)
+		require.Equal(t, []string{hl1, hl2, hl3}, publisher.published())
+
+		saved, err := s.GetCheckpoint(peerIRI)
+		require.NoError(t, err)
+		require.Equal(t, cp.AnchorsPublished, saved.AnchorsPublished)
+		require.NotNil(t, saved.Completed)
+
+		// Synchronizing again should skip the anchors that were already published.
+		cp, err = s.Resync(peerIRI)
+		require.NoError(t, err)
+		require.Equal(t, 0, cp.AnchorsPublished)
+		require.Equal(t, 3, cp.AnchorsSkipped)
+		require.Len(t, publisher.published(), 3)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		publisher := &mockPublisher{}
+		apClient := &mockAPClient{activities: activities, nextErr: errors.New("injected next error"), failAt: 2}
+
+		s, err := New(serviceIRI, []*url.URL{peerIRI}, &Providers{
+			ActivityPubClient: apClient,
+			CASResolver:       &mockCASResolver{},
+			AnchorPublisher:   publisher,
+			ActivityStore:     memstore.New(""),
+			StoreProvider:     mem.NewProvider(),
+		})
+		require.NoError(t, err)
+
+		cp, err := s.Resync(peerIRI)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Nil(t, cp.Completed)
+		require.Equal(t, 2, cp.ActivitiesProcessed)
+		require.Equal(t, 1, cp.AnchorsPublished)
+
+		saved, err := s.GetCheckpoint(peerIRI)
+		require.NoError(t, err)
+		require.Nil(t, saved.Completed)
+		require.Equal(t, "https://orb.domain2.com/activities/follow1", saved.LastActivityID)
+
+		apClient.nextErr = nil
+
+		cp, err = s.Resync(peerIRI)
+		require.NoError(t, err)
+		require.NotNil(t, cp.Completed)
+		require.Equal(t, saved.Started.Unix(), cp.Started.Unix())
+		require.Equal(t, 3, cp.ActivitiesProcessed)
+		require.Equal(t, 3, cp.AnchorsPublished)
+		require.Equal(t, 0, cp.AnchorsSkipped)
+		require.Equal(t, []string{hl1, hl2, hl3}, publisher.published())
+	})
+
+	t.Run("resume - checkpoint activity not found", func(t *testing.T) {
+		publisher := &mockPublisher{}
+		storeProvider := mem.NewProvider()
+
+		s, err := New(serviceIRI, []*url.URL{peerIRI}, &Providers{
+			ActivityPubClient: &mockAPClient{activities: activities},
+			CASResolver:       &mockCASResolver{},
+			AnchorPublisher:   publisher,
+			ActivityStore:     memstore.New(""),
+			StoreProvider:     storeProvider,
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, s.saveCheckpoint(&Checkpoint{
+			Peer:           peerIRI.String(),
+			LastActivityID: "https://orb.domain2.com/activities/unknown",
+			Started:        time.Now(),
+		}))
+
+		cp, err := s.Resync(peerIRI)
+		require.NoError(t, err)
+		require.NotNil(t, cp.Completed)
+		require.Equal(t, 3, cp.AnchorsPublished)
+		require.Equal(t, []string{hl1, hl2, hl3}, publisher.published())
+	})
+
+	t.Run("publish order", func(t *testing.T) {
+		publisher := &mockPublisher{}
+
+		// The same DID suffix is anchored by each of these anchors. The latest anchor must be published
+		// last since the observer overwrites the anchor of a suffix.
+		s, err := New(serviceIRI, []*url.URL{peerIRI}, &Providers{
+			ActivityPubClient: &mockAPClient{activities: []*vocab.ActivityType{
+				newCreateActivity("https://orb.domain2.com/activities/create3", hl3),
+				newCreateActivity("https://orb.domain2.com/activities/create2", hl2),
+				newCreateActivity("https://orb.domain2.com/activities/create1", hl1),
+			}},
+			CASResolver:     &mockCASResolver{},
+			AnchorPublisher: publisher,
+			ActivityStore:   memstore.New(""),
+			StoreProvider:   mem.NewProvider(),
+		})
+		require.NoError(t, err)
+
+		cp, err := s.Resync(peerIRI)
+		require.NoError(t, err)
+		require.Equal(t, "https://orb.domain2.com/activities/create3", cp.LastActivityID)
+		require.Equal(t, []string{hl1, hl2, hl3}, publisher.published())
+	})
+
+	t.Run("CAS resolver error", func(t *testing.T) {
+		publisher := &mockPublisher{}
+
+		s, err := New(serviceIRI, []*url.URL{peerIRI}, &Providers{
+			ActivityPubClient: &mockAPClient{activities: activities},
+			CASResolver:       &mockCASResolver{errs: map[string]error{hl2: errors.New("injected resolve error")}},
+			AnchorPublisher:   publisher,
+			ActivityStore:     memstore.New(""),
+			StoreProvider:     mem.NewProvider(),
+		})
+		require.NoError(t, err)
+
+		cp, err := s.Resync(peerIRI)
+		require.NoError(t, err)
+		require.Equal(t, 2, cp.AnchorsPublished)
+		require.Equal(t, 1, cp.AnchorsFailed)
+		require.Equal(t, []string{hl1, hl3}, publisher.published())
+	})
+
+	t.Run("transient CAS resolver error", func(t *testing.T) {
+		s, err := New(serviceIRI, []*url.URL{peerIRI}, &Providers{
+			ActivityPubClient: &mockAPClient{activities: activities},
+			CASResolver: &mockCASResolver{errs: map[string]error{
+				hl1: orberrors.NewTransient(errors.New("injected resolve error")),
+			}},
+			AnchorPublisher: &mockPublisher{},
+			ActivityStore:   memstore.New(""),
+			StoreProvider:   mem.NewProvider(),
+		})
+		require.NoError(t, err)
+
+		_, err = s.Resync(peerIRI)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected resolve error")
+	})
+
+	t.Run("publish error", func(t *testing.T) {
+		s, err := New(serviceIRI, []*url.URL{peerIRI}, &Providers{
+			ActivityPubClient: &mockAPClient{activities: activities},
+			CASResolver:       &mockCASResolver{},
+			AnchorPublisher:   &mockPublisher{err: errors.New("injected publish error")},
+			ActivityStore:     memstore.New(""),
+			StoreProvider:     mem.NewProvider(),
+		})
+		require.NoError(t, err)
+
+		_, err = s.Resync(peerIRI)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected publish error")
+	})
+
+	t.Run("get actor error", func(t *testing.T) {
+		s, err := New(serviceIRI, []*url.URL{peerIRI}, &Providers{
+			ActivityPubClient: &mockAPClient{actorErr: errors.New("injected actor error")},
+			StoreProvider:     mem.NewProvider(),
+		})
+		require.NoError(t, err)
+
+		_, err = s.Resync(peerIRI)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected actor error")
+	})
+
+	t.Run("get activities error", func(t *testing.T) {
+		s, err := New(serviceIRI, []*url.URL{peerIRI}, &Providers{
+			ActivityPubClient: &mockAPClient{activitiesErr: errors.New("injected activities error")},
+			StoreProvider:     mem.NewProvider(),
+		})
+		require.NoError(t, err)
+
+		_, err = s.Resync(peerIRI)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected activities error")
+	})
+
+	t.Run("get checkpoint error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns(nil, errors.New("injected get error"))
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := New(serviceIRI, []*url.URL{peerIRI}, &Providers{StoreProvider: p})
+		require.NoError(t, err)
+
+		_, err = s.Resync(peerIRI)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected get error")
+	})
+
+	t.Run("save checkpoint error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns(nil, storage.ErrDataNotFound)
+		store.PutReturns(errors.New("injected put error"))
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := New(serviceIRI, []*url.URL{peerIRI}, &Providers{
+			ActivityPubClient: &mockAPClient{activities: activities},
+			CASResolver:       &mockCASResolver{},
+			AnchorPublisher:   &mockPublisher{},
+			ActivityStore:     memstore.New(""),
+			StoreProvider:     p,
+		})
+		require.NoError(t, err)
+
+		_, err = s.Resync(peerIRI)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected put error")
+	})
+}
+
+func TestService_StartStop(t *testing.T) {
+	publisher := &mockPublisher{}
+
+	s, err := New(serviceIRI, []*url.URL{peerIRI}, &Providers{
+		ActivityPubClient: &mockAPClient{
+			activities: []*vocab.ActivityType{
+				newCreateActivity("https://orb.domain2.com/activities/create1", hl1),
+			},
+		},
+		CASResolver:     &mockCASResolver{},
+		AnchorPublisher: publisher,
+		ActivityStore:   memstore.New(""),
+		StoreProvider:   mem.NewProvider(),
+	})
+	require.NoError(t, err)
+
+	s.Start()
+	require.Equal(t, lifecycle.StateStarted, s.State())
+
+	require.Eventually(t, func() bool {
+		return len(publisher.published()) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	s.Stop()
+	require.Equal(t, lifecycle.StateStopped, s.State())
+
+	_, err = s.Resync(peerIRI)
+	require.True(t, errors.Is(err, ErrStopped))
+}
+
+func newCreateActivity(id, hl string) *vocab.ActivityType {
+	return vocab.NewCreateActivity(
+		vocab.NewObjectProperty(
+			vocab.WithAnchorCredentialReference(
+				vocab.NewAnchorCredentialReference(
+					testutil.MustParseURL(id+"/ref"),
+					testutil.MustParseURL("https://orb.domain2.com/cas/"+hl),
+					hl),
+			),
+		),
+		vocab.WithID(testutil.MustParseURL(id)),
+	)
+}
+
+func newAnnounceActivity(id string, hls ...string) *vocab.ActivityType {
+	var items []*vocab.ObjectProperty
+
+	for _, hl := range hls {
+		items = append(items, vocab.NewObjectProperty(
+			vocab.WithAnchorCredentialReference(
+				vocab.NewAnchorCredentialReference(
+					testutil.MustParseURL(id+"/"+hl),
+					testutil.MustParseURL("https://orb.domain3.com/cas/"+hl),
+					hl),
+			),
+		))
+	}
+
+	return vocab.NewAnnounceActivity(
+		vocab.NewObjectProperty(vocab.WithCollection(vocab.NewCollection(items))),
+		vocab.WithID(testutil.MustParseURL(id)),
+	)
+}
+
+type mockAPClient struct {
+	activities    []*vocab.ActivityType
+	actorErr      error
+	activitiesErr error
+	nextErr       error
+	failAt        int
+}
+
+func (m *mockAPClient) GetActor(actorIRI *url.URL) (*vocab.ActorType, error) {
+	if m.actorErr != nil {
+		return nil, m.actorErr
+	}
+
+	return aptestutil.NewMockService(actorIRI), nil
+}
+
+func (m *mockAPClient) GetActivities(_ *url.URL, order client.Order) (client.ActivityIterator, error) {
+	if m.activitiesErr != nil {
+		return nil, m.activitiesErr
+	}
+
+	activities := m.activities
+
+	if order == client.Reverse {
+		activities = make([]*vocab.ActivityType, len(m.activities))
+
+		for i, activity := range m.activities {
+			activities[len(m.activities)-1-i] = activity
+		}
+	}
+
+	return &mockActivityIterator{activities: activities, err: m.nextErr, failAt: m.failAt}, nil
+}
+
+type mockActivityIterator struct {
+	activities []*vocab.ActivityType
+	current    int
+	err        error
+	failAt     int
+}
+
+func (it *mockActivityIterator) Next() (*vocab.ActivityType, error) {
+	if it.err != nil && it.current == it.failAt {
+		return nil, it.err
+	}
+
+	if it.current >= len(it.activities) {
+		return nil, client.ErrNotFound
+	}
+
+	activity := it.activities[it.current]
+
+	it.current++
+
+	return activity, nil
+}
+
+func (it *mockActivityIterator) TotalItems() int {
+	return len(it.activities)
+}
+
+type mockCASResolver struct {
+	errs map[string]error
+}
+
+func (m *mockCASResolver) Resolve(_ *url.URL, hl string, _ []byte) ([]byte, error) {
+	if err, ok := m.errs[hl]; ok {
+		return nil, err
+	}
+
+	return []byte("{}"), nil
+}
+
+type mockPublisher struct {
+	mutex   sync.Mutex
+	err     error
+	anchors []string
+}
+
+func (m *mockPublisher) PublishAnchor(anchor *anchorinfo.AnchorInfo) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.anchors = append(m.anchors, anchor.Hashlink)
+
+	return nil
+}
+
+func (m *mockPublisher) published() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.anchors
+}
@@ -0,0 +1,462 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package writer
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+
+	"github.com/trustbloc/orb/pkg/anchor/proof"
+	"github.com/trustbloc/orb/pkg/store/pendinganchor"
+	"github.com/trustbloc/orb/pkg/store/vcstatus"
+)
+
+// TimeoutAction is the action taken by the writer when the witness policy for an anchor credential
+// is not satisfied within the maximum witness delay.
+type TimeoutAction string
+
+const (
+	// TimeoutActionReOffer re-sends the offer to the same witnesses.
+	TimeoutActionReOffer TimeoutAction = "re-offer"
+
+	// TimeoutActionEscalate sends the offer to the configured escalation witnesses.
+	TimeoutActionEscalate TimeoutAction = "escalate"
+
+	// TimeoutActionFail abandons the anchor credential and adds its operations back to the operation queue.
+	TimeoutActionFail TimeoutAction = "fail"
+)
+
+const (
+	defaultTimeoutCheckInterval = 10 * time.Second
+
+	// timeoutLeaseIntervals is the number of check intervals after the deadline of a pending anchor within
+	// which the owner (the instance that created the anchor credential) must take the time-out action.
+	// After that, another instance may claim the pending anchor.
+	timeoutLeaseIntervals = 3
+)
+
+// Option is a writer option.
+type Option func(opts *Writer)
+
+// WithWitnessTimeoutActions sets the actions to take (in order) each time the witness policy for an anchor
+// credential is not satisfied within the maximum witness delay. Witness time-outs are only tracked if
+// a pending anchor store is provided.
+func WithWitnessTimeoutActions(actions ...TimeoutAction) Option {
+	return func(opts *Writer) {
+		opts.timeoutActions = actions
+	}
+}
+
+// WithEscalationWitnesses sets the witnesses that the offer is sent to by the escalate time-out action.
+func WithEscalationWitnesses(witnesses ...*url.URL) Option {
+	return func(opts *Writer) {
+		opts.escalationWitnesses = witnesses
+	}
+}
+
+// WithWitnessTimeoutCheckInterval sets the interval at which expired anchor credentials are checked.
+func WithWitnessTimeoutCheckInterval(interval time.Duration) Option {
+	return func(opts *Writer) {
+		opts.timeoutCheckInterval = interval
+	}
+}
+
+type pendingAnchorStore interface {
+	Put(entry *pendinganchor.Entry) error
+	Delete(vcID string) error
+	Release(vcID string) error
+	GetExpired(from, to time.Time) ([]*pendinganchor.Entry, error)
+	Claim(vcID, owner string, attempt int, lease time.Duration) (*pendinganchor.Entry, error)
+}
+
+type opQueue interface {
+	Add(op *operation.QueuedOperation, protocolGenesisTime uint64) (uint, error)
+}
+
+type protocolClient interface {
+	Get(protocolGenesisTime uint64) (protocol.Version, error)
+}
+
+func validateTimeoutActions(actions []TimeoutAction, escalationWitnesses []*url.URL) error {
+	for _, action := range actions {
+		switch action {
+		case TimeoutActionReOffer, TimeoutActionFail:
+		case TimeoutActionEscalate:
+			if len(escalationWitnesses) == 0 {
+				return fmt.Errorf("escalation witnesses must be provided for the '%s' time-out action", action)
+			}
+		default:
+			return fmt.Errorf("unsupported witness time-out action: %s", action)
+		}
+	}
+
+	return nil
+}
+
+func (c *Writer) addPending(vcID, anchor string, version uint64, batchWitnesses, suffixes []string) error {
+	if c.PendingAnchorStore == nil {
+		return nil
+	}
+
+	err := c.PendingAnchorStore.Put(&pendinganchor.Entry{
+		VCID:                vcID,
+		AnchorString:        anchor,
+		ProtocolGenesisTime: version,
+		BatchWitnesses:      batchWitnesses,
+		Suffixes:            suffixes,
+		Deadline:            time.Now().Add(c.maxWitnessDelay),
+		Owner:               c.instanceID,
+	})
+	if err != nil {
+		return fmt.Errorf("store pending anchor for vc[%s]: %w", vcID, err)
+	}
+
+	return nil
+}
+
+func (c *Writer) deletePending(vcID string) {
+	if c.PendingAnchorStore == nil {
+		return
+	}
+
+	err := c.PendingAnchorStore.Delete(vcID)
+	if err != nil {
+		// this is a clean-up task so no harm if there was an error
+		logger.Warnf("failed to delete pending anchor for vc[%s]: %s", vcID, err.Error())
+	}
+}
+
+// Stop stops the witness time-out monitor.
+func (c *Writer) Stop() {
+	if c.done == nil {
+		return
+	}
+
+	close(c.done)
+
+	logger.Infof("Stopped witness time-out monitor")
+}
+
+func (c *Writer) monitorTimeouts() {
+	for {
+		select {
+		case <-time.After(c.timeoutCheckInterval):
+			c.checkTimeouts()
+		case <-c.done:
+			logger.Debugf("Exiting witness time-out monitor.")
+
+			return
+		}
+	}
+}
+
+func (c *Writer) checkTimeouts() {
+	now := time.Now()
+
+	// All entries are scanned on the first check in order to catch up on the entries that expired while the
+	// server was down. After that, only the entries that expired recently are queried. The look-back period
+	// includes the lease period so that the entries of an instance that has stopped may be claimed.
+	var from time.Time
+
+	if c.timeoutsChecked {
+		from = now.Add(-c.timeoutLease() - 2*c.timeoutCheckInterval)
+	}
+
+	entries, err := c.PendingAnchorStore.GetExpired(from, now)
+	if err != nil {
+		logger.Errorf("failed to retrieve expired pending anchors: %s", err)
+
+		return
+	}
+
+	c.timeoutsChecked = true
+
+	for _, entry := range entries {
+		claimed, err := c.PendingAnchorStore.Claim(entry.VCID, c.instanceID, entry.Attempt, c.timeoutLease())
+		if err != nil {
+			if errors.Is(err, pendinganchor.ErrClaimed) || errors.Is(err, pendinganchor.ErrNotFound) {
+				logger.Debugf("skipping witness time-out for vc[%s]: %s", entry.VCID, err)
+			} else {
+				logger.Warnf("failed to claim pending anchor for vc[%s]: %s", entry.VCID, err)
+			}
+
+			continue
+		}
+
+		err = c.handleTimeout(claimed)
+		if err != nil {
+			logger.Warnf("failed to handle witness time-out for vc[%s]: %s", entry.VCID, err)
+
+			c.retryTimeout(claimed)
+		}
+	}
+}
+
+// retryTimeout moves the deadline of the given entry to the next check so that the time-out is handled again.
+func (c *Writer) retryTimeout(entry *pendinganchor.Entry) {
+	entry.Deadline = time.Now().Add(c.timeoutCheckInterval)
+
+	err := c.PendingAnchorStore.Put(entry)
+	if err != nil {
+		logger.Warnf("failed to update deadline of pending anchor for vc[%s]: %s", entry.VCID, err)
+	}
+}
+
+func (c *Writer) timeoutLease() time.Duration {
+	return timeoutLeaseIntervals * c.timeoutCheckInterval
+}
+
+func (c *Writer) handleTimeout(entry *pendinganchor.Entry) error {
+	status, err := c.VCStatusStore.GetStatus(entry.VCID)
+	if err != nil {
+		return fmt.Errorf("get status: %w", err)
+	}
+
+	if status == proof.VCStatusCompleted {
+		logger.Debugf("anchor credential[%s] has status '%s' - nothing to do", entry.VCID, status)
+
+		c.deletePending(entry.VCID)
+
+		return nil
+	}
+
+	if status == proof.VCStatusFailed {
+		// The entry is deleted after the operations are re-queued, so re-queuing didn't complete.
+		logger.Infof("anchor credential[%s] has status '%s' - retrying re-queue of operations", entry.VCID, status)
+
+		return c.requeue(entry)
+	}
+
+	if status == proof.VCStatusWitnessed {
+		// The proof handler is publishing the anchor credential, so it will be completed.
+		logger.Infof("anchor credential[%s] has status '%s' - waiting for it to be completed", entry.VCID, status)
+
+		return c.releasePending(entry.VCID)
+	}
+
+	if status != proof.VCStatusTimedOut {
+		err = c.VCStatusStore.AddStatus(entry.VCID, proof.VCStatusTimedOut)
+		if err != nil {
+			return fmt.Errorf("add status '%s': %w", proof.VCStatusTimedOut, err)
+		}
+	}
+
+	if entry.Attempt >= len(c.timeoutActions) {
+		logger.Warnf("witness policy for anchor credential[%s] was not satisfied by %s and no further time-out "+
+			"actions are configured", entry.VCID, entry.Deadline)
+
+		return c.releasePending(entry.VCID)
+	}
+
+	action := c.timeoutActions[entry.Attempt]
+
+	logger.Infof("witness policy for anchor credential[%s] was not satisfied by %s - taking action '%s'",
+		entry.VCID, entry.Deadline, action)
+
+	switch action {
+	case TimeoutActionReOffer:
+		err = c.reOffer(entry)
+	case TimeoutActionEscalate:
+		err = c.escalate(entry)
+	case TimeoutActionFail:
+		return c.fail(entry)
+	default:
+		return fmt.Errorf("unsupported witness time-out action: %s", action)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	entry.Attempt++
+	entry.Deadline = time.Now().Add(c.maxWitnessDelay)
+
+	return c.PendingAnchorStore.Put(entry)
+}
+
+// releasePending stops tracking the deadline of the pending anchor. The entry is kept (so that the anchor status
+// may still be looked up by suffix) until the final status is recorded, i.e. when the anchor credential is witnessed.
+func (c *Writer) releasePending(vcID string) error {
+	err := c.PendingAnchorStore.Release(vcID)
+	if err != nil && !errors.Is(err, pendinganchor.ErrNotFound) {
+		return fmt.Errorf("release pending anchor: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Writer) reOffer(entry *pendinganchor.Entry) error {
+	vc, err := c.VCStore.Get(entry.VCID)
+	if err != nil {
+		return fmt.Errorf("get anchor credential: %w", err)
+	}
+
+	batchWitnessesIRI, err := c.getBatchWitnessesIRI(entry.BatchWitnesses)
+	if err != nil {
+		return err
+	}
+
+	witnessesIRI, err := c.getOfferRecipients(batchWitnessesIRI)
+	if err != nil {
+		return err
+	}
+
+	err = c.postOffer(vc, witnessesIRI)
+	if err != nil {
+		return err
+	}
+
+	err = c.VCStatusStore.AddStatus(entry.VCID, proof.VCStatusReOffered)
+	if err != nil {
+		return fmt.Errorf("add status '%s': %w", proof.VCStatusReOffered, err)
+	}
+
+	return nil
+}
+
+func (c *Writer) escalate(entry *pendinganchor.Entry) error {
+	vc, err := c.VCStore.Get(entry.VCID)
+	if err != nil {
+		return fmt.Errorf("get anchor credential: %w", err)
+	}
+
+	existing, err := c.WitnessStore.Get(entry.VCID)
+	if err != nil {
+		return fmt.Errorf("get witnesses: %w", err)
+	}
+
+	var (
+		witnesses    []*proof.WitnessProof
+		witnessesIRI []*url.URL
+	)
+
+	for _, w := range c.escalationWitnesses {
+		if hasWitness(existing, w.String()) {
+			continue
+		}
+
+		hasLog, e := c.WFClient.HasSupportedLedgerType(fmt.Sprintf("%s://%s", w.Scheme, w.Host))
+		if e != nil {
+			return e
+		}
+
+		witnesses = append(witnesses,
+			&proof.WitnessProof{
+				Type:    proof.WitnessTypeSystem,
+				Witness: w.String(),
+				HasLog:  hasLog,
+			})
+
+		witnessesIRI = append(witnessesIRI, w)
+	}
+
+	if len(witnesses) == 0 {
+		logger.Infof("all escalation witnesses were already offered anchor credential[%s]", entry.VCID)
+
+		return nil
+	}
+
+	// store witnesses before posting offers since the proofs may arrive before the witnesses are stored
+	err = c.WitnessStore.Put(entry.VCID, witnesses)
+	if err != nil {
+		return fmt.Errorf("store escalation witnesses: %w", err)
+	}
+
+	err = c.postOffer(vc, witnessesIRI)
+	if err != nil {
+		return err
+	}
+
+	err = c.VCStatusStore.AddStatus(entry.VCID, proof.VCStatusEscalated)
+	if err != nil {
+		return fmt.Errorf("add status '%s': %w", proof.VCStatusEscalated, err)
+	}
+
+	return nil
+}
+
+func (c *Writer) fail(entry *pendinganchor.Entry) error {
+	if c.ProtocolClient == nil || c.OpQueue == nil {
+		return fmt.Errorf("protocol client and operation queue are required to re-queue operations")
+	}
+
+	// The 'failed' outcome is claimed before the operations are re-queued. If the proof handler has already claimed
+	// the 'witnessed' outcome then the anchor credential is being published and its operations must not be re-queued.
+	// Otherwise, the proof handler won't publish the anchor credential once the 'failed' outcome is claimed.
+	err := c.VCStatusStore.ClaimOutcome(entry.VCID, proof.VCStatusFailed)
+	if err != nil {
+		if errors.Is(err, vcstatus.ErrOutcomeClaimed) {
+			logger.Infof("anchor credential[%s] was witnessed before it failed - operations are not re-queued: %s",
+				entry.VCID, err)
+
+			return c.releasePending(entry.VCID)
+		}
+
+		return fmt.Errorf("claim outcome '%s': %w", proof.VCStatusFailed, err)
+	}
+
+	return c.requeue(entry)
+}
+
+func (c *Writer) requeue(entry *pendinganchor.Entry) error {
+	if c.ProtocolClient == nil || c.OpQueue == nil {
+		return fmt.Errorf("protocol client and operation queue are required to re-queue operations")
+	}
+
+	pv, err := c.ProtocolClient.Get(entry.ProtocolGenesisTime)
+	if err != nil {
+		return fmt.Errorf("get protocol version for genesis time %d: %w", entry.ProtocolGenesisTime, err)
+	}
+
+	ops, err := pv.OperationProvider().GetTxnOperations(&txn.SidetreeTxn{
+		AnchorString:        entry.AnchorString,
+		Namespace:           c.namespace,
+		ProtocolGenesisTime: entry.ProtocolGenesisTime,
+	})
+	if err != nil {
+		return fmt.Errorf("get operations for anchor[%s]: %w", entry.AnchorString, err)
+	}
+
+	for _, op := range ops {
+		_, err = c.OpQueue.Add(&operation.QueuedOperation{
+			Namespace:       c.namespace,
+			UniqueSuffix:    op.UniqueSuffix,
+			OperationBuffer: op.OperationBuffer,
+		}, entry.ProtocolGenesisTime)
+		if err != nil {
+			return fmt.Errorf("re-queue operation for suffix[%s]: %w", op.UniqueSuffix, err)
+		}
+	}
+
+	logger.Infof("re-queued %d operations for failed anchor credential[%s]", len(ops), entry.VCID)
+
+	err = c.WitnessStore.Delete(entry.VCID)
+	if err != nil {
+		// this is a clean-up task so no harm if there was an error
+		logger.Warnf("failed to delete witnesses for vc[%s]: %s", entry.VCID, err.Error())
+	}
+
+	c.deletePending(entry.VCID)
+
+	return nil
+}
+
+func hasWitness(witnesses []*proof.WitnessProof, witness string) bool {
+	for _, w := range witnesses {
+		if w.Witness == witness {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,614 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package writer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	coremocks "github.com/trustbloc/sidetree-core-go/pkg/mocks"
+
+	anchormocks "github.com/trustbloc/orb/pkg/anchor/mocks"
+	"github.com/trustbloc/orb/pkg/anchor/proof"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/mocks"
+	"github.com/trustbloc/orb/pkg/pubsub/mempubsub"
+	"github.com/trustbloc/orb/pkg/store/pendinganchor"
+	"github.com/trustbloc/orb/pkg/store/vcstatus"
+	vcstore "github.com/trustbloc/orb/pkg/store/verifiable"
+	wfclient "github.com/trustbloc/orb/pkg/webfinger/client"
+)
+
+const escalationWitness = "https://escalation.com/services/orb"
+
+func TestNew_TimeoutOptions(t *testing.T) {
+	ps := mempubsub.New(mempubsub.Config{})
+	defer ps.Stop()
+
+	apServiceIRI := testutil.MustParseURL(activityPubURL)
+	casIRI := testutil.MustParseURL(casURL)
+
+	t.Run("success", func(t *testing.T) {
+		pendingStore, err := pendinganchor.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		c, err := New(namespace, apServiceIRI, casIRI, &Providers{PendingAnchorStore: pendingStore},
+			&anchormocks.AnchorPublisher{}, ps, testMaxWitnessDelay, signWithLocalWitness, testutil.GetLoader(t), nil,
+			&mocks.MetricsProvider{},
+			WithWitnessTimeoutActions(TimeoutActionReOffer, TimeoutActionEscalate, TimeoutActionFail),
+			WithEscalationWitnesses(testutil.MustParseURL(escalationWitness)),
+			WithWitnessTimeoutCheckInterval(time.Millisecond),
+		)
+		require.NoError(t, err)
+		require.NotNil(t, c)
+
+		time.Sleep(10 * time.Millisecond)
+
+		c.Stop()
+	})
+
+	t.Run("error - no escalation witnesses", func(t *testing.T) {
+		c, err := New(namespace, apServiceIRI, casIRI, &Providers{},
+			&anchormocks.AnchorPublisher{}, ps, testMaxWitnessDelay, signWithLocalWitness, testutil.GetLoader(t), nil,
+			&mocks.MetricsProvider{}, WithWitnessTimeoutActions(TimeoutActionEscalate),
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "escalation witnesses must be provided")
+		require.Nil(t, c)
+	})
+
+	t.Run("error - unsupported action", func(t *testing.T) {
+		c, err := New(namespace, apServiceIRI, casIRI, &Providers{},
+			&anchormocks.AnchorPublisher{}, ps, testMaxWitnessDelay, signWithLocalWitness, testutil.GetLoader(t), nil,
+			&mocks.MetricsProvider{}, WithWitnessTimeoutActions("invalid"),
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported witness time-out action: invalid")
+		require.Nil(t, c)
+	})
+}
+
+func TestWriter_handleTimeout(t *testing.T) {
+	ps := mempubsub.New(mempubsub.Config{})
+	defer ps.Stop()
+
+	apServiceIRI := testutil.MustParseURL(activityPubURL)
+	casIRI := testutil.MustParseURL(casURL)
+
+	wfHTTPClient := httpMock(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			Body:       ioutil.NopCloser(bytes.NewBufferString(webfingerPayload)),
+			StatusCode: http.StatusOK,
+		}, nil
+	})
+
+	anchorVC, err := verifiable.ParseCredential([]byte(anchorCred),
+		verifiable.WithDisabledProofCheck(),
+		verifiable.WithJSONLDDocumentLoader(testutil.GetLoader(t)),
+	)
+	require.NoError(t, err)
+
+	newWriter := func(t *testing.T, statusStore *mockVCStatusStore, opts ...Option) (*Writer, *pendinganchor.Store) {
+		t.Helper()
+
+		vcStore, err := vcstore.New(mem.NewProvider(), testutil.GetLoader(t))
+		require.NoError(t, err)
+
+		require.NoError(t, vcStore.Put(anchorVC))
+
+		pendingStore, err := pendinganchor.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		providers := &Providers{
+			Outbox:             &mockOutbox{},
+			WitnessStore:       &mockWitnessStore{},
+			ActivityStore:      &mockActivityStore{},
+			VCStore:            vcStore,
+			VCStatusStore:      statusStore,
+			WFClient:           wfclient.New(wfclient.WithHTTPClient(wfHTTPClient)),
+			PendingAnchorStore: pendingStore,
+		}
+
+		c, err := New(namespace, apServiceIRI, casIRI, providers, &anchormocks.AnchorPublisher{}, ps,
+			testMaxWitnessDelay, signWithLocalWitness, testutil.GetLoader(t), nil, &mocks.MetricsProvider{},
+			append(opts, WithWitnessTimeoutCheckInterval(time.Hour))...)
+		require.NoError(t, err)
+
+		t.Cleanup(c.Stop)
+
+		return c, pendingStore
+	}
+
+	newEntry := func() *pendinganchor.Entry {
+		return &pendinganchor.Entry{
+			VCID:                anchorVC.ID,
+			AnchorString:        "1.anchor",
+			ProtocolGenesisTime: 1,
+			BatchWitnesses:      []string{"https://abc.com/services/orb"},
+			Deadline:            time.Now().Add(-time.Second),
+		}
+	}
+
+	t.Run("success - re-offer", func(t *testing.T) {
+		statusStore := &mockVCStatusStore{Status: proof.VCStatusInProcess}
+
+		c, pendingStore := newWriter(t, statusStore, WithWitnessTimeoutActions(TimeoutActionReOffer))
+
+		require.NoError(t, pendingStore.Put(newEntry()))
+
+		c.checkTimeouts()
+
+		require.Equal(t, []proof.VCStatus{proof.VCStatusTimedOut, proof.VCStatusReOffered}, statusStore.added())
+
+		entry, err := pendingStore.Get(anchorVC.ID)
+		require.NoError(t, err)
+		require.Equal(t, 1, entry.Attempt)
+		require.True(t, entry.Deadline.After(time.Now()))
+	})
+
+	t.Run("success - escalate", func(t *testing.T) {
+		statusStore := &mockVCStatusStore{Status: proof.VCStatusReOffered}
+
+		c, pendingStore := newWriter(t, statusStore,
+			WithWitnessTimeoutActions(TimeoutActionReOffer, TimeoutActionEscalate),
+			WithEscalationWitnesses(testutil.MustParseURL(escalationWitness)))
+
+		entry := newEntry()
+		entry.Attempt = 1
+
+		require.NoError(t, c.handleTimeout(entry))
+		require.Equal(t, []proof.VCStatus{proof.VCStatusTimedOut, proof.VCStatusEscalated}, statusStore.added())
+
+		entry, err = pendingStore.Get(anchorVC.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, entry.Attempt)
+	})
+
+	t.Run("success - escalation witnesses already offered", func(t *testing.T) {
+		statusStore := &mockVCStatusStore{Status: proof.VCStatusTimedOut}
+
+		c, _ := newWriter(t, statusStore,
+			WithWitnessTimeoutActions(TimeoutActionEscalate),
+			WithEscalationWitnesses(testutil.MustParseURL(escalationWitness)))
+
+		c.WitnessStore = &mockWitnessStore{
+			Witnesses: []*proof.WitnessProof{{Type: proof.WitnessTypeSystem, Witness: escalationWitness}},
+		}
+
+		require.NoError(t, c.handleTimeout(newEntry()))
+		require.Empty(t, statusStore.added())
+	})
+
+	t.Run("success - fail", func(t *testing.T) {
+		statusStore := &mockVCStatusStore{Status: proof.VCStatusInProcess}
+
+		c, pendingStore := newWriter(t, statusStore, WithWitnessTimeoutActions(TimeoutActionFail))
+
+		opProvider := &coremocks.OperationProvider{}
+		opProvider.GetTxnOperationsReturns([]*operation.AnchoredOperation{
+			{UniqueSuffix: "suffix-1", OperationBuffer: []byte("op1")},
+			{UniqueSuffix: "suffix-2", OperationBuffer: []byte("op2")},
+		}, nil)
+
+		pv := &coremocks.ProtocolVersion{}
+		pv.OperationProviderReturns(opProvider)
+
+		pc := &mockProtocolClient{version: pv}
+		opQueue := &coremocks.OperationQueue{}
+
+		c.ProtocolClient = pc
+		c.OpQueue = opQueue
+
+		require.NoError(t, pendingStore.Put(newEntry()))
+
+		c.checkTimeouts()
+
+		require.Equal(t, []proof.VCStatus{proof.VCStatusTimedOut, proof.VCStatusFailed}, statusStore.added())
+		require.Equal(t, 2, opQueue.AddCallCount())
+
+		op, genesisTime := opQueue.AddArgsForCall(1)
+		require.Equal(t, "suffix-2", op.UniqueSuffix)
+		require.Equal(t, namespace, op.Namespace)
+		require.Equal(t, uint64(1), genesisTime)
+
+		_, err := pendingStore.Get(anchorVC.ID)
+		require.True(t, errors.Is(err, pendinganchor.ErrNotFound))
+	})
+
+	t.Run("success - already completed", func(t *testing.T) {
+		statusStore := &mockVCStatusStore{Status: proof.VCStatusCompleted}
+
+		c, pendingStore := newWriter(t, statusStore, WithWitnessTimeoutActions(TimeoutActionReOffer))
+
+		require.NoError(t, pendingStore.Put(newEntry()))
+
+		c.checkTimeouts()
+
+		require.Empty(t, statusStore.added())
+
+		_, err := pendingStore.Get(anchorVC.ID)
+		require.True(t, errors.Is(err, pendinganchor.ErrNotFound))
+	})
+
+	t.Run("success - no more actions", func(t *testing.T) {
+		statusStore := &mockVCStatusStore{Status: proof.VCStatusInProcess}
+
+		c, pendingStore := newWriter(t, statusStore)
+
+		entry := newEntry()
+		entry.Suffixes = []string{"suffix-1"}
+
+		require.NoError(t, pendingStore.Put(entry))
+
+		c.checkTimeouts()
+
+		require.Equal(t, []proof.VCStatus{proof.VCStatusTimedOut}, statusStore.added())
+
+		expired, err := pendingStore.GetExpired(time.Time{}, time.Now())
+		require.NoError(t, err)
+		require.Empty(t, expired)
+
+		// The anchor may still be looked up by suffix since the final status hasn't been recorded.
+		entry, err = pendingStore.GetBySuffix("suffix-1")
+		require.NoError(t, err)
+		require.Equal(t, anchorVC.ID, entry.VCID)
+	})
+
+	t.Run("error - get status", func(t *testing.T) {
+		statusStore := &mockVCStatusStore{GetErr: errors.New("injected get error")}
+
+		c, _ := newWriter(t, statusStore, WithWitnessTimeoutActions(TimeoutActionReOffer))
+
+		err := c.handleTimeout(newEntry())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected get error")
+	})
+
+	t.Run("error - post offer", func(t *testing.T) {
+		statusStore := &mockVCStatusStore{Status: proof.VCStatusInProcess}
+
+		c, pendingStore := newWriter(t, statusStore, WithWitnessTimeoutActions(TimeoutActionReOffer))
+
+		c.Outbox = &mockOutbox{Err: errors.New("injected post error")}
+
+		entry := newEntry()
+
+		require.NoError(t, pendingStore.Put(entry))
+
+		err := c.handleTimeout(entry)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected post error")
+
+		// The entry is kept so that the action is retried.
+		entry, err = pendingStore.Get(anchorVC.ID)
+		require.NoError(t, err)
+		require.Equal(t, 0, entry.Attempt)
+	})
+
+	t.Run("error - fail without operation queue", func(t *testing.T) {
+		statusStore := &mockVCStatusStore{Status: proof.VCStatusInProcess}
+
+		c, _ := newWriter(t, statusStore, WithWitnessTimeoutActions(TimeoutActionFail))
+
+		err := c.handleTimeout(newEntry())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "protocol client and operation queue are required")
+	})
+
+	t.Run("error - re-queue operation", func(t *testing.T) {
+		statusStore := &mockVCStatusStore{Status: proof.VCStatusInProcess}
+
+		c, _ := newWriter(t, statusStore, WithWitnessTimeoutActions(TimeoutActionFail))
+
+		opProvider := &coremocks.OperationProvider{}
+		opProvider.GetTxnOperationsReturns([]*operation.AnchoredOperation{{UniqueSuffix: "suffix-1"}}, nil)
+
+		pv := &coremocks.ProtocolVersion{}
+		pv.OperationProviderReturns(opProvider)
+
+		opQueue := &coremocks.OperationQueue{}
+		opQueue.AddReturns(0, errors.New("injected queue error"))
+
+		c.ProtocolClient = &mockProtocolClient{version: pv}
+		c.OpQueue = opQueue
+
+		err := c.handleTimeout(newEntry())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected queue error")
+		require.Equal(t, []proof.VCStatus{proof.VCStatusTimedOut, proof.VCStatusFailed}, statusStore.added())
+	})
+
+	t.Run("success - retry re-queue after failed status", func(t *testing.T) {
+		statusStore := &mockVCStatusStore{Status: proof.VCStatusFailed}
+
+		c, pendingStore := newWriter(t, statusStore, WithWitnessTimeoutActions(TimeoutActionFail))
+
+		opProvider := &coremocks.OperationProvider{}
+		opProvider.GetTxnOperationsReturns([]*operation.AnchoredOperation{{UniqueSuffix: "suffix-1"}}, nil)
+
+		pv := &coremocks.ProtocolVersion{}
+		pv.OperationProviderReturns(opProvider)
+
+		opQueue := &coremocks.OperationQueue{}
+
+		c.ProtocolClient = &mockProtocolClient{version: pv}
+		c.OpQueue = opQueue
+
+		entry := newEntry()
+		entry.Attempt = 1
+
+		require.NoError(t, pendingStore.Put(entry))
+
+		c.checkTimeouts()
+
+		require.Empty(t, statusStore.added())
+		require.Equal(t, 1, opQueue.AddCallCount())
+
+		_, err := pendingStore.Get(anchorVC.ID)
+		require.True(t, errors.Is(err, pendinganchor.ErrNotFound))
+	})
+
+	t.Run("success - witnessed before failed status", func(t *testing.T) {
+		statusStore := &mockVCStatusStore{
+			Status:   proof.VCStatusTimedOut,
+			ClaimErr: fmt.Errorf("%w: has outcome 'witnessed'", vcstatus.ErrOutcomeClaimed),
+		}
+
+		c, pendingStore := newWriter(t, statusStore, WithWitnessTimeoutActions(TimeoutActionFail))
+
+		opQueue := &coremocks.OperationQueue{}
+
+		c.ProtocolClient = &mockProtocolClient{}
+		c.OpQueue = opQueue
+
+		require.NoError(t, pendingStore.Put(newEntry()))
+
+		c.checkTimeouts()
+
+		require.Empty(t, statusStore.added())
+		require.Zero(t, opQueue.AddCallCount())
+
+		expired, err := pendingStore.GetExpired(time.Time{}, time.Now())
+		require.NoError(t, err)
+		require.Empty(t, expired)
+	})
+
+	t.Run("success - already witnessed", func(t *testing.T) {
+		statusStore := &mockVCStatusStore{Status: proof.VCStatusWitnessed}
+
+		c, pendingStore := newWriter(t, statusStore, WithWitnessTimeoutActions(TimeoutActionFail))
+
+		opQueue := &coremocks.OperationQueue{}
+
+		c.ProtocolClient = &mockProtocolClient{}
+		c.OpQueue = opQueue
+
+		require.NoError(t, pendingStore.Put(newEntry()))
+
+		c.checkTimeouts()
+
+		require.Empty(t, statusStore.added())
+		require.Zero(t, opQueue.AddCallCount())
+
+		expired, err := pendingStore.GetExpired(time.Time{}, time.Now())
+		require.NoError(t, err)
+		require.Empty(t, expired)
+
+		_, err = pendingStore.Get(anchorVC.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("error - claim outcome", func(t *testing.T) {
+		statusStore := &mockVCStatusStore{
+			Status:   proof.VCStatusTimedOut,
+			ClaimErr: errors.New("injected claim error"),
+		}
+
+		c, pendingStore := newWriter(t, statusStore, WithWitnessTimeoutActions(TimeoutActionFail))
+
+		opQueue := &coremocks.OperationQueue{}
+
+		c.ProtocolClient = &mockProtocolClient{}
+		c.OpQueue = opQueue
+
+		require.NoError(t, pendingStore.Put(newEntry()))
+
+		c.checkTimeouts()
+
+		require.Zero(t, opQueue.AddCallCount())
+
+		entry, err := pendingStore.Get(anchorVC.ID)
+		require.NoError(t, err)
+		require.Equal(t, 0, entry.Attempt)
+	})
+
+	t.Run("race - proof handler and fail action", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			statusStore, err := vcstatus.New(mem.NewProvider())
+			require.NoError(t, err)
+
+			require.NoError(t, statusStore.AddStatus(anchorVC.ID, proof.VCStatusInProcess))
+
+			c, pendingStore := newWriter(t, &mockVCStatusStore{}, WithWitnessTimeoutActions(TimeoutActionFail))
+			c.VCStatusStore = statusStore
+
+			opProvider := &coremocks.OperationProvider{}
+			opProvider.GetTxnOperationsReturns([]*operation.AnchoredOperation{{UniqueSuffix: "suffix-1"}}, nil)
+
+			pv := &coremocks.ProtocolVersion{}
+			pv.OperationProviderReturns(opProvider)
+
+			opQueue := &coremocks.OperationQueue{}
+
+			c.ProtocolClient = &mockProtocolClient{version: pv}
+			c.OpQueue = opQueue
+
+			require.NoError(t, pendingStore.Put(newEntry()))
+
+			var (
+				wg        sync.WaitGroup
+				published bool
+			)
+
+			wg.Add(2)
+
+			go func() {
+				defer wg.Done()
+
+				c.checkTimeouts()
+			}()
+
+			go func() {
+				defer wg.Done()
+
+				// This is what the proof handler does before publishing the anchor credential.
+				published = statusStore.ClaimOutcome(anchorVC.ID, proof.VCStatusWitnessed) == nil
+			}()
+
+			wg.Wait()
+
+			requeued := opQueue.AddCallCount() > 0
+
+			require.NotEqualf(t, published, requeued,
+				"anchor credential must be either published or re-queued (published: %t, re-queued: %t)",
+				published, requeued)
+		}
+	})
+
+	t.Run("error - action is retried on next check", func(t *testing.T) {
+		statusStore := &mockVCStatusStore{Status: proof.VCStatusInProcess}
+
+		c, pendingStore := newWriter(t, statusStore, WithWitnessTimeoutActions(TimeoutActionReOffer))
+
+		c.Outbox = &mockOutbox{Err: errors.New("injected post error")}
+
+		require.NoError(t, pendingStore.Put(newEntry()))
+
+		c.checkTimeouts()
+
+		entry, err := pendingStore.Get(anchorVC.ID)
+		require.NoError(t, err)
+		require.Equal(t, 0, entry.Attempt)
+		require.Equal(t, c.instanceID, entry.Owner)
+		require.True(t, entry.Deadline.After(time.Now()))
+	})
+}
+
+func TestWriter_checkTimeouts_Cluster(t *testing.T) {
+	ps := mempubsub.New(mempubsub.Config{})
+	defer ps.Stop()
+
+	anchorVC, err := verifiable.ParseCredential([]byte(anchorCred),
+		verifiable.WithDisabledProofCheck(),
+		verifiable.WithJSONLDDocumentLoader(testutil.GetLoader(t)),
+	)
+	require.NoError(t, err)
+
+	vcStore, err := vcstore.New(mem.NewProvider(), testutil.GetLoader(t))
+	require.NoError(t, err)
+
+	require.NoError(t, vcStore.Put(anchorVC))
+
+	// The pending anchor store is shared by all instances.
+	pendingStore, err := pendinganchor.New(mem.NewProvider())
+	require.NoError(t, err)
+
+	newInstance := func(t *testing.T, outbox *mockOutbox, statusStore *mockVCStatusStore) *Writer {
+		t.Helper()
+
+		wfHTTPClient := httpMock(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body:       ioutil.NopCloser(bytes.NewBufferString(webfingerPayload)),
+				StatusCode: http.StatusOK,
+			}, nil
+		})
+
+		c, err := New(namespace, testutil.MustParseURL(activityPubURL), testutil.MustParseURL(casURL),
+			&Providers{
+				Outbox:             outbox,
+				WitnessStore:       &mockWitnessStore{},
+				ActivityStore:      &mockActivityStore{},
+				VCStore:            vcStore,
+				VCStatusStore:      statusStore,
+				WFClient:           wfclient.New(wfclient.WithHTTPClient(wfHTTPClient)),
+				PendingAnchorStore: pendingStore,
+			}, &anchormocks.AnchorPublisher{}, ps,
+			testMaxWitnessDelay, signWithLocalWitness, testutil.GetLoader(t), nil, &mocks.MetricsProvider{},
+			WithWitnessTimeoutActions(TimeoutActionReOffer), WithWitnessTimeoutCheckInterval(time.Hour))
+		require.NoError(t, err)
+
+		t.Cleanup(c.Stop)
+
+		return c
+	}
+
+	statusStore1 := &mockVCStatusStore{Status: proof.VCStatusInProcess}
+	statusStore2 := &mockVCStatusStore{Status: proof.VCStatusInProcess}
+
+	c1 := newInstance(t, &mockOutbox{}, statusStore1)
+	c2 := newInstance(t, &mockOutbox{}, statusStore2)
+
+	t.Run("only the owner takes the action", func(t *testing.T) {
+		require.NoError(t, pendingStore.Put(&pendinganchor.Entry{
+			VCID:           anchorVC.ID,
+			AnchorString:   "1.anchor",
+			BatchWitnesses: []string{"https://abc.com/services/orb"},
+			Deadline:       time.Now().Add(-time.Second),
+			Owner:          c1.instanceID,
+		}))
+
+		c2.checkTimeouts()
+		require.Empty(t, statusStore2.added())
+
+		c1.checkTimeouts()
+		require.Equal(t, []proof.VCStatus{proof.VCStatusTimedOut, proof.VCStatusReOffered}, statusStore1.added())
+
+		// The action for the attempt was already taken.
+		c2.checkTimeouts()
+		require.Empty(t, statusStore2.added())
+	})
+
+	t.Run("entry is claimed after the owner's lease expires", func(t *testing.T) {
+		require.NoError(t, pendingStore.Put(&pendinganchor.Entry{
+			VCID:           anchorVC.ID,
+			AnchorString:   "1.anchor",
+			BatchWitnesses: []string{"https://abc.com/services/orb"},
+			Deadline:       time.Now().Add(-c2.timeoutLease() - time.Second),
+			Owner:          "stopped-instance",
+		}))
+
+		c2.timeoutsChecked = false
+
+		c2.checkTimeouts()
+		require.Equal(t, []proof.VCStatus{proof.VCStatusTimedOut, proof.VCStatusReOffered}, statusStore2.added())
+
+		entry, err := pendingStore.Get(anchorVC.ID)
+		require.NoError(t, err)
+		require.Equal(t, c2.instanceID, entry.Owner)
+		require.Equal(t, 1, entry.Attempt)
+	})
+}
+
+type mockProtocolClient struct {
+	version *coremocks.ProtocolVersion
+	err     error
+}
+
+func (m *mockProtocolClient) Get(uint64) (protocol.Version, error) {
+	return m.version, m.err
+}
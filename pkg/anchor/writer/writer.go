@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/google/uuid"
 	docutil "github.com/hyperledger/aries-framework-go/pkg/doc/util"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
 	"github.com/piprate/json-gold/ld"
@@ -68,6 +69,12 @@ type Writer struct {
 	signWithLocalWitness bool
 	resourceResolver     *resourceresolver.Resolver
 	metrics              metricsProvider
+	timeoutActions       []TimeoutAction
+	escalationWitnesses  []*url.URL
+	timeoutCheckInterval time.Duration
+	timeoutsChecked      bool
+	instanceID           string
+	done                 chan struct{}
 }
 
 // Providers contains all of the providers required by the client.
@@ -85,6 +92,12 @@ type Providers struct {
 	WitnessStore  witnessStore
 	ActivityStore activityStore
 	WFClient      webfingerClient
+
+	// PendingAnchorStore, OpQueue and ProtocolClient are optional. If PendingAnchorStore is not provided
+	// then witness time-outs are not tracked.
+	PendingAnchorStore pendingAnchorStore
+	OpQueue            opQueue
+	ProtocolClient     protocolClient
 }
 
 type webfingerClient interface {
@@ -97,6 +110,7 @@ type activityStore interface {
 
 type witnessStore interface {
 	Put(vcID string, witnesses []*proof.WitnessProof) error
+	Get(vcID string) ([]*proof.WitnessProof, error)
 	Delete(vcID string) error
 }
 
@@ -139,6 +153,8 @@ type vcStore interface {
 
 type vcStatusStore interface {
 	AddStatus(vcID string, status proof.VCStatus) error
+	GetStatus(vcID string) (proof.VCStatus, error)
+	ClaimOutcome(vcID string, status proof.VCStatus) error
 }
 
 type anchorPublisher interface {
@@ -155,7 +171,7 @@ func New(namespace string, apServiceIRI, casURL *url.URL, providers *Providers,
 	anchorPublisher anchorPublisher, pubSub pubSub,
 	maxWitnessDelay time.Duration, signWithLocalWitness bool,
 	documentLoader ld.DocumentLoader, resourceResolver *resourceresolver.Resolver,
-	metrics metricsProvider) (*Writer, error) {
+	metrics metricsProvider, opts ...Option) (*Writer, error) {
 	w := &Writer{
 		Providers:            providers,
 		anchorPublisher:      anchorPublisher,
@@ -166,6 +182,17 @@ func New(namespace string, apServiceIRI, casURL *url.URL, providers *Providers,
 		signWithLocalWitness: signWithLocalWitness,
 		resourceResolver:     resourceResolver,
 		metrics:              metrics,
+		timeoutCheckInterval: defaultTimeoutCheckInterval,
+		instanceID:           uuid.New().String(),
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	err := validateTimeoutActions(w.timeoutActions, w.escalationWitnesses)
+	if err != nil {
+		return nil, err
 	}
 
 	s, err := vcpubsub.NewSubscriber(pubSub, w.handle, documentLoader)
@@ -175,6 +202,14 @@ func New(namespace string, apServiceIRI, casURL *url.URL, providers *Providers,
 
 	s.Start()
 
+	if providers.PendingAnchorStore != nil {
+		w.done = make(chan struct{})
+
+		go w.monitorTimeouts()
+
+		logger.Infof("Started witness time-out monitor with actions %s", w.timeoutActions)
+	}
+
 	return w, nil
 }
 
@@ -218,9 +253,17 @@ func (c *Writer) WriteAnchor(anchor string, _ []*protocol.AnchorDocument, refs [
 
 	postOfferActivityStartTime := time.Now()
 
+	// track the anchor credential so that an action may be taken if the witness policy is not satisfied in time
+	err = c.addPending(vc.ID, anchor, version, witnesses, getSuffixes(refs))
+	if err != nil {
+		return err
+	}
+
 	// send an offer activity to witnesses (request witnessing anchor credential from non-local witness logs)
 	err = c.postOfferActivity(vc, witnesses)
 	if err != nil {
+		c.deletePending(vc.ID)
+
 		return fmt.Errorf("failed to post new offer activity for vc[%s]: %w", vc.ID, err)
 	}
 
@@ -459,6 +502,8 @@ func (c *Writer) handle(vc *verifiable.Credential) error {
 		logger.Warnf("failed to delete witnesses for vc[%s]: %s", vc.ID, err.Error())
 	}
 
+	c.deletePending(vc.ID)
+
 	return nil
 }
 
@@ -523,18 +568,40 @@ func (c *Writer) postOfferActivity(vc *verifiable.Credential, witnesses []string
 		return err
 	}
 
+	witnessesIRI, err := c.getOfferRecipients(batchWitnessesIRI)
+	if err != nil {
+		return err
+	}
+
+	// store witnesses before posting offers because handlers sometimes get invoked before
+	// witnesses and vc status are stored
+	err = c.storeWitnesses(vc.ID, batchWitnessesIRI)
+	if err != nil {
+		return fmt.Errorf("store witnesses: %w", err)
+	}
+
+	// TODO: If offers were not sent - delete vc status and witness store entries (issue-452)
+	return c.postOffer(vc, witnessesIRI)
+}
+
+// getOfferRecipients returns the batch witnesses plus the system witnesses (activity pub collection).
+func (c *Writer) getOfferRecipients(batchWitnessesIRI []*url.URL) ([]*url.URL, error) {
 	// get system witness IRI
 	systemWitnessesIRI, err := url.Parse(c.apServiceIRI.String() + resthandler.WitnessesPath)
 	if err != nil {
-		return fmt.Errorf("failed to parse system witness path: %w", err)
+		return nil, fmt.Errorf("failed to parse system witness path: %w", err)
 	}
 
 	var witnessesIRI []*url.URL
 
-	// add batch witnesses and system witnesses (activity pub collection)
 	witnessesIRI = append(witnessesIRI, batchWitnessesIRI...)
 	witnessesIRI = append(witnessesIRI, vocab.PublicIRI, systemWitnessesIRI)
 
+	return witnessesIRI, nil
+}
+
+// postOffer posts an offer activity for the anchor credential to the given witnesses.
+func (c *Writer) postOffer(vc *verifiable.Credential, witnessesIRI []*url.URL) error {
 	bytes, err := vc.MarshalJSON()
 	if err != nil {
 		return fmt.Errorf("failed to marshal anchor credential: %w", err)
@@ -556,16 +623,8 @@ func (c *Writer) postOfferActivity(vc *verifiable.Credential, witnesses []string
 		vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI))),
 	)
 
-	// store witnesses before posting offers because handlers sometimes get invoked before
-	// witnesses and vc status are stored
-	err = c.storeWitnesses(vc.ID, batchWitnessesIRI)
-	if err != nil {
-		return fmt.Errorf("store witnesses: %w", err)
-	}
-
 	postID, err := c.Outbox.Post(offer)
 	if err != nil {
-		// TODO: Offers were not sent - delete vc status and witness store entries (issue-452)
 		return fmt.Errorf("failed to post offer for vcID[%s]: %w", vc.ID, err)
 	}
 
@@ -15,6 +15,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"sync"
 	"testing"
 	"time"
 
@@ -1630,7 +1631,9 @@ func (a *mockActivityStore) QueryReferences(refType spi.ReferenceType, query *sp
 
 type mockWitnessStore struct {
 	PutErr    error
+	GetErr    error
 	DeleteErr error
+	Witnesses []*proof.WitnessProof
 }
 
 func (w *mockWitnessStore) Put(vcID string, witnesses []*proof.WitnessProof) error {
@@ -1641,6 +1644,14 @@ func (w *mockWitnessStore) Put(vcID string, witnesses []*proof.WitnessProof) err
 	return nil
 }
 
+func (w *mockWitnessStore) Get(vcID string) ([]*proof.WitnessProof, error) {
+	if w.GetErr != nil {
+		return nil, w.GetErr
+	}
+
+	return w.Witnesses, nil
+}
+
 func (w *mockWitnessStore) Delete(vcID string) error {
 	if w.DeleteErr != nil {
 		return w.DeleteErr
@@ -1650,7 +1661,12 @@ func (w *mockWitnessStore) Delete(vcID string) error {
 }
 
 type mockVCStatusStore struct {
-	Err error
+	Err       error
+	GetErr    error
+	ClaimErr  error
+	Status    proof.VCStatus
+	mutex     sync.Mutex
+	statusAdd []proof.VCStatus
 }
 
 func (ss *mockVCStatusStore) AddStatus(vcID string, status proof.VCStatus) error {
@@ -1658,9 +1674,37 @@ func (ss *mockVCStatusStore) AddStatus(vcID string, status proof.VCStatus) error
 		return ss.Err
 	}
 
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+
+	ss.statusAdd = append(ss.statusAdd, status)
+
 	return nil
 }
 
+func (ss *mockVCStatusStore) GetStatus(vcID string) (proof.VCStatus, error) {
+	if ss.GetErr != nil {
+		return "", ss.GetErr
+	}
+
+	return ss.Status, nil
+}
+
+func (ss *mockVCStatusStore) ClaimOutcome(vcID string, status proof.VCStatus) error {
+	if ss.ClaimErr != nil {
+		return ss.ClaimErr
+	}
+
+	return ss.AddStatus(vcID, status)
+}
+
+func (ss *mockVCStatusStore) added() []proof.VCStatus {
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+
+	return ss.statusAdd
+}
+
 func getOperationReferences(anchorOrigin string) []*operation.Reference {
 	return []*operation.Reference{
 		{
@@ -0,0 +1,371 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package apitoken manages API (bearer) tokens which are issued to clients with a set of scopes, an optional
+// expiry and which may be revoked at any time. A scope is the name of a token in the endpoint definitions
+// of the auth configuration (i.e. a name in the ReadTokens or WriteTokens of a TokenDef) and grants the same
+// access as the static token of that name.
+//
+// The value of an issued token is "<id>.<secret>". Only a hash of the secret is stored, so the value is
+// returned to the caller once (when the token is created) and may not be retrieved afterwards.
+package apitoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bluele/gcache"
+	"github.com/google/uuid"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+var logger = log.New("api-token")
+
+const (
+	namespace = "apitoken"
+
+	tokenTag = "token"
+
+	separator = "."
+
+	secretLength = 32
+
+	defaultCacheExpiry = 30 * time.Second
+	defaultCacheSize   = 1000
+)
+
+var (
+	// ErrNotFound is returned if the token is not found in the store.
+	ErrNotFound = errors.New("token not found")
+
+	// ErrInvalidToken is returned if the token value is malformed, unknown, expired or revoked.
+	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrInvalidScope is returned if no scopes, or a scope that's not configured, is requested for a new token.
+	ErrInvalidScope = errors.New("invalid scope")
+
+	// ErrInvalidExpiry is returned if the expiry requested for a new token isn't in the future.
+	ErrInvalidExpiry = errors.New("invalid expiry")
+)
+
+// Token contains the details of an issued API token. The secret part of the token is not included.
+type Token struct {
+	// ID is the unique ID of the token.
+	ID string `json:"id"`
+
+	// Name is a descriptive name of the token, e.g. the name of the client to which it was issued.
+	Name string `json:"name,omitempty"`
+
+	// Scopes contains the names of the endpoint groups to which the token grants access.
+	Scopes []string `json:"scopes"`
+
+	Created time.Time `json:"created"`
+
+	// Expiry is the time after which the token is no longer accepted. It is nil if the token doesn't expire.
+	Expiry *time.Time `json:"expiry,omitempty"`
+
+	// Revoked is the time at which the token was revoked. It is nil if the token hasn't been revoked.
+	Revoked *time.Time `json:"revoked,omitempty"`
+}
+
+func (t *Token) isValid(now time.Time) bool {
+	return t.Revoked == nil && (t.Expiry == nil || now.Before(*t.Expiry))
+}
+
+type tokenRecord struct {
+	*Token
+
+	// Hash is the SHA-256 hash of the token's secret.
+	Hash []byte `json:"hash"`
+}
+
+type gCache interface {
+	Get(key interface{}) (interface{}, error)
+	Remove(key interface{}) bool
+}
+
+// Option is a token manager option.
+type Option func(m *Manager)
+
+// WithScopes sets the scopes that may be granted to a token. If not set then any scope may be granted.
+func WithScopes(scopes ...string) Option {
+	return func(m *Manager) {
+		m.scopes = make(map[string]struct{})
+
+		for _, scope := range scopes {
+			m.scopes[scope] = struct{}{}
+		}
+	}
+}
+
+// WithCacheExpiry sets the expiry time of the token cache. A token that's revoked on another server
+// instance may still be accepted by this instance for up to this amount of time.
+func WithCacheExpiry(value time.Duration) Option {
+	return func(m *Manager) {
+		m.cacheExpiry = value
+	}
+}
+
+// Manager issues, validates and revokes API tokens.
+type Manager struct {
+	store       storage.Store
+	scopes      map[string]struct{}
+	cacheExpiry time.Duration
+	cache       gCache
+}
+
+// New returns a new API token manager.
+func New(provider storage.Provider, opts ...Option) (*Manager, error) {
+	store, err := provider.OpenStore(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open API token store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(namespace, storage.StoreConfiguration{TagNames: []string{tokenTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	m := &Manager{
+		store:       store,
+		cacheExpiry: defaultCacheExpiry,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	m.cache = gcache.New(defaultCacheSize).ARC().LoaderExpireFunc(
+		func(id interface{}) (interface{}, *time.Duration, error) {
+			record, err := m.get(id.(string))
+			if err != nil {
+				return nil, nil, err
+			}
+
+			return record, &m.cacheExpiry, nil
+		},
+	).Build()
+
+	return m, nil
+}
+
+// Create issues a new token with the given name and scopes. If expiry is zero then the token doesn't expire.
+// The token details are returned along with the token value, which must be passed to the client since
+// it can't be retrieved later.
+func (m *Manager) Create(name string, scopes []string, expiry time.Time) (*Token, string, error) {
+	if err := m.validateScopes(scopes); err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+
+	if !expiry.IsZero() && !expiry.After(now) {
+		return nil, "", fmt.Errorf("%w: [%s] must be in the future", ErrInvalidExpiry,
+			expiry.Format(time.RFC3339))
+	}
+
+	secret := make([]byte, secretLength)
+
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("generate token secret: %w", err)
+	}
+
+	token := &Token{
+		ID:      uuid.New().String(),
+		Name:    name,
+		Scopes:  scopes,
+		Created: now,
+	}
+
+	if !expiry.IsZero() {
+		token.Expiry = &expiry
+	}
+
+	secretStr := base64.RawURLEncoding.EncodeToString(secret)
+
+	if err := m.put(&tokenRecord{Token: token, Hash: hash(secretStr)}); err != nil {
+		return nil, "", err
+	}
+
+	logger.Infof("Created API token [%s] with name [%s] and scopes %s", token.ID, name, scopes)
+
+	return token, token.ID + separator + secretStr, nil
+}
+
+// List returns all tokens (including expired and revoked tokens).
+func (m *Manager) List() ([]*Token, error) {
+	iter, err := m.store.Query(tokenTag)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("failed to query API tokens: %w", err))
+	}
+
+	defer storage.Close(iter, logger)
+
+	var tokens []*Token
+
+	for {
+		ok, err := iter.Next()
+		if err != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("iterator error for API tokens: %w", err))
+		}
+
+		if !ok {
+			break
+		}
+
+		value, err := iter.Value()
+		if err != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("failed to get iterator value for API tokens: %w", err))
+		}
+
+		record := &tokenRecord{}
+
+		err = json.Unmarshal(value, record)
+		if err != nil {
+			logger.Errorf("Failed to unmarshal API token: %s", err)
+
+			continue
+		}
+
+		tokens = append(tokens, record.Token)
+	}
+
+	return tokens, nil
+}
+
+// Revoke revokes the token with the given ID and returns the updated token. ErrNotFound is returned
+// if the token doesn't exist.
+func (m *Manager) Revoke(id string) (*Token, error) {
+	record, err := m.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.Revoked != nil {
+		logger.Debugf("API token [%s] is already revoked", id)
+
+		return record.Token, nil
+	}
+
+	now := time.Now()
+	record.Revoked = &now
+
+	if err := m.put(record); err != nil {
+		return nil, err
+	}
+
+	m.cache.Remove(id)
+
+	logger.Infof("Revoked API token [%s]", id)
+
+	return record.Token, nil
+}
+
+// Scopes returns the scopes of the given token value. ErrInvalidToken is returned if the token is
+// malformed, unknown, expired or revoked.
+func (m *Manager) Scopes(value string) ([]string, error) {
+	parts := strings.Split(value, separator)
+	if len(parts) != 2 { //nolint:gomnd
+		return nil, ErrInvalidToken
+	}
+
+	id, secret := parts[0], parts[1]
+
+	v, err := m.cache.Get(id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			logger.Debugf("API token [%s] not found", id)
+
+			return nil, ErrInvalidToken
+		}
+
+		return nil, err
+	}
+
+	record := v.(*tokenRecord) //nolint:errcheck,forcetypeassert
+
+	if subtle.ConstantTimeCompare(hash(secret), record.Hash) != 1 {
+		logger.Debugf("Invalid secret for API token [%s]", id)
+
+		return nil, ErrInvalidToken
+	}
+
+	if !record.isValid(time.Now()) {
+		logger.Debugf("API token [%s] is expired or revoked", id)
+
+		return nil, ErrInvalidToken
+	}
+
+	return record.Scopes, nil
+}
+
+func (m *Manager) validateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return fmt.Errorf("%w: at least one scope must be specified", ErrInvalidScope)
+	}
+
+	if m.scopes == nil {
+		return nil
+	}
+
+	for _, scope := range scopes {
+		if _, ok := m.scopes[scope]; !ok {
+			return fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) get(id string) (*tokenRecord, error) {
+	value, err := m.store.Get(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("failed to get API token [%s]: %w", id, err))
+	}
+
+	record := &tokenRecord{}
+
+	err = json.Unmarshal(value, record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal API token [%s]: %w", id, err)
+	}
+
+	return record, nil
+}
+
+func (m *Manager) put(record *tokenRecord) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal API token: %w", err)
+	}
+
+	err = m.store.Put(record.ID, value, storage.Tag{Name: tokenTag})
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("failed to store API token [%s]: %w", record.ID, err))
+	}
+
+	return nil
+}
+
+func hash(secret string) []byte {
+	h := sha256.Sum256([]byte(secret))
+
+	return h[:]
+}
@@ -0,0 +1,243 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package apitoken
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		m, err := New(mem.NewProvider())
+		require.NoError(t, err)
+		require.NotNil(t, m)
+	})
+
+	t.Run("error - open store fails", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(nil, fmt.Errorf("open store error"))
+
+		m, err := New(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to open API token store: open store error")
+		require.Nil(t, m)
+	})
+
+	t.Run("error - set store config fails", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.SetStoreConfigReturns(fmt.Errorf("set store config error"))
+
+		m, err := New(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to set store configuration: set store config error")
+		require.Nil(t, m)
+	})
+}
+
+func TestManager(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		m, err := New(mem.NewProvider(), WithScopes("admin", "read"), WithCacheExpiry(time.Minute))
+		require.NoError(t, err)
+
+		expiry := time.Now().Add(time.Hour)
+
+		t1, value1, err := m.Create("client1", []string{"admin"}, expiry)
+		require.NoError(t, err)
+		require.NotEmpty(t, t1.ID)
+		require.Equal(t, "client1", t1.Name)
+		require.Equal(t, []string{"admin"}, t1.Scopes)
+		require.NotNil(t, t1.Expiry)
+		require.Nil(t, t1.Revoked)
+		require.True(t, len(value1) > len(t1.ID))
+
+		t2, value2, err := m.Create("client2", []string{"read"}, time.Time{})
+		require.NoError(t, err)
+		require.Nil(t, t2.Expiry)
+
+		scopes, err := m.Scopes(value1)
+		require.NoError(t, err)
+		require.Equal(t, []string{"admin"}, scopes)
+
+		scopes, err = m.Scopes(value2)
+		require.NoError(t, err)
+		require.Equal(t, []string{"read"}, scopes)
+
+		tokens, err := m.List()
+		require.NoError(t, err)
+		require.Len(t, tokens, 2)
+
+		revoked, err := m.Revoke(t1.ID)
+		require.NoError(t, err)
+		require.NotNil(t, revoked.Revoked)
+
+		_, err = m.Scopes(value1)
+		require.True(t, errors.Is(err, ErrInvalidToken))
+
+		// Revoking again has no effect.
+		revoked2, err := m.Revoke(t1.ID)
+		require.NoError(t, err)
+		require.Equal(t, revoked.Revoked.Unix(), revoked2.Revoked.Unix())
+
+		scopes, err = m.Scopes(value2)
+		require.NoError(t, err)
+		require.Equal(t, []string{"read"}, scopes)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		m, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		_, value, err := m.Create("client1", []string{"admin"}, time.Now().Add(50*time.Millisecond))
+		require.NoError(t, err)
+
+		_, err = m.Scopes(value)
+		require.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+
+		_, err = m.Scopes(value)
+		require.True(t, errors.Is(err, ErrInvalidToken))
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		m, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		tk, _, err := m.Create("client1", []string{"admin"}, time.Time{})
+		require.NoError(t, err)
+
+		_, err = m.Scopes("invalid")
+		require.True(t, errors.Is(err, ErrInvalidToken))
+
+		_, err = m.Scopes("unknown.secret")
+		require.True(t, errors.Is(err, ErrInvalidToken))
+
+		_, err = m.Scopes(tk.ID + ".invalidsecret")
+		require.True(t, errors.Is(err, ErrInvalidToken))
+	})
+
+	t.Run("invalid scope", func(t *testing.T) {
+		m, err := New(mem.NewProvider(), WithScopes("admin", "read"))
+		require.NoError(t, err)
+
+		_, _, err = m.Create("client1", nil, time.Time{})
+		require.True(t, errors.Is(err, ErrInvalidScope))
+
+		_, _, err = m.Create("client1", []string{"admin", "write"}, time.Time{})
+		require.True(t, errors.Is(err, ErrInvalidScope))
+		require.Contains(t, err.Error(), "write")
+	})
+
+	t.Run("expiry in the past", func(t *testing.T) {
+		m, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		_, _, err = m.Create("client1", []string{"admin"}, time.Now().Add(-time.Minute))
+		require.True(t, errors.Is(err, ErrInvalidExpiry))
+		require.Contains(t, err.Error(), "must be in the future")
+	})
+
+	t.Run("revoke - not found", func(t *testing.T) {
+		m, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		_, err = m.Revoke("unknown")
+		require.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("store errors", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		s := &mocks.Store{}
+		s.PutReturns(errExpected)
+		s.GetReturns(nil, errExpected)
+		s.QueryReturns(nil, errExpected)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(s, nil)
+
+		m, err := New(provider)
+		require.NoError(t, err)
+
+		_, _, err = m.Create("client1", []string{"admin"}, time.Time{})
+		require.True(t, orberrors.IsTransient(err))
+
+		_, err = m.List()
+		require.True(t, orberrors.IsTransient(err))
+
+		_, err = m.Revoke("id")
+		require.True(t, orberrors.IsTransient(err))
+
+		_, err = m.Scopes("id.secret")
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("iterator errors", func(t *testing.T) {
+		errExpected := errors.New("injected iterator error")
+
+		t.Run("next", func(t *testing.T) {
+			it := &mocks.Iterator{}
+			it.NextReturns(false, errExpected)
+
+			s := &mocks.Store{}
+			s.QueryReturns(it, nil)
+
+			provider := &mocks.Provider{}
+			provider.OpenStoreReturns(s, nil)
+
+			m, err := New(provider)
+			require.NoError(t, err)
+
+			_, err = m.List()
+			require.True(t, orberrors.IsTransient(err))
+			require.Contains(t, err.Error(), errExpected.Error())
+		})
+
+		t.Run("value", func(t *testing.T) {
+			it := &mocks.Iterator{}
+			it.NextReturns(true, nil)
+			it.ValueReturns(nil, errExpected)
+
+			s := &mocks.Store{}
+			s.QueryReturns(it, nil)
+
+			provider := &mocks.Provider{}
+			provider.OpenStoreReturns(s, nil)
+
+			m, err := New(provider)
+			require.NoError(t, err)
+
+			_, err = m.List()
+			require.True(t, orberrors.IsTransient(err))
+			require.Contains(t, err.Error(), errExpected.Error())
+		})
+	})
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		s := &mocks.Store{}
+		s.GetReturns([]byte("{"), nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(s, nil)
+
+		m, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = m.Revoke("id")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal API token")
+	})
+}
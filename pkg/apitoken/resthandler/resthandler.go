@@ -0,0 +1,241 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/apitoken"
+)
+
+const (
+	endpoint       = "/tokens"
+	revokeEndpoint = endpoint + "/{" + idPathVariable + "}/revoke"
+
+	idPathVariable = "id"
+)
+
+const (
+	badRequestResponse          = "Bad Request."
+	notFoundResponse            = "Not Found."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+var logger = log.New("api-token-rest-handler")
+
+type tokenManager interface {
+	Create(name string, scopes []string, expiry time.Time) (*apitoken.Token, string, error)
+	List() ([]*apitoken.Token, error)
+	Revoke(id string) (*apitoken.Token, error)
+}
+
+// CreateRequest contains the parameters of a new token.
+type CreateRequest struct {
+	// Name is a descriptive name of the token.
+	Name string `json:"name,omitempty"`
+
+	// Scopes contains the names of the endpoint groups to which the token grants access.
+	Scopes []string `json:"scopes"`
+
+	// Expiry is the time after which the token is no longer accepted. If not set then the token doesn't expire.
+	Expiry *time.Time `json:"expiry,omitempty"`
+}
+
+// CreateResponse contains the details of the new token along with the token value.
+type CreateResponse struct {
+	*apitoken.Token
+
+	// Value is the bearer token which is passed to the client. It can't be retrieved again.
+	Value string `json:"token"`
+}
+
+// Create issues a new API token.
+type Create struct {
+	manager tokenManager
+}
+
+// NewCreate returns a new handler that issues API tokens.
+func NewCreate(m tokenManager) *Create {
+	return &Create{manager: m}
+}
+
+// Path returns the HTTP REST endpoint for the create service.
+func (h *Create) Path() string {
+	return endpoint
+}
+
+// Method returns the HTTP REST method for the create service.
+func (h *Create) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the create service.
+func (h *Create) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Create) handle(w http.ResponseWriter, req *http.Request) {
+	request := &CreateRequest{}
+
+	if err := json.NewDecoder(req.Body).Decode(request); err != nil {
+		logger.Infof("[%s] Invalid request: %s", endpoint, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	var expiry time.Time
+	if request.Expiry != nil {
+		expiry = *request.Expiry
+	}
+
+	token, value, err := h.manager.Create(request.Name, request.Scopes, expiry)
+	if err != nil {
+		if errors.Is(err, apitoken.ErrInvalidScope) || errors.Is(err, apitoken.ErrInvalidExpiry) {
+			logger.Infof("[%s] Invalid request: %s", endpoint, err)
+
+			writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error creating API token: %s", endpoint, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeJSONResponse(w, &CreateResponse{Token: token, Value: value})
+}
+
+// List returns the issued API tokens. Token values are not included.
+type List struct {
+	manager tokenManager
+}
+
+// NewList returns a new handler that lists API tokens.
+func NewList(m tokenManager) *List {
+	return &List{manager: m}
+}
+
+// Path returns the HTTP REST endpoint for the list service.
+func (h *List) Path() string {
+	return endpoint
+}
+
+// Method returns the HTTP REST method for the list service.
+func (h *List) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the list service.
+func (h *List) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *List) handle(w http.ResponseWriter, _ *http.Request) {
+	tokens, err := h.manager.List()
+	if err != nil {
+		logger.Errorf("[%s] Error listing API tokens: %s", endpoint, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if tokens == nil {
+		tokens = []*apitoken.Token{}
+	}
+
+	writeJSONResponse(w, tokens)
+}
+
+// Revoke revokes the API token with the given ID.
+type Revoke struct {
+	manager tokenManager
+}
+
+// NewRevoke returns a new handler that revokes API tokens.
+func NewRevoke(m tokenManager) *Revoke {
+	return &Revoke{manager: m}
+}
+
+// Path returns the HTTP REST endpoint for the revoke service.
+func (h *Revoke) Path() string {
+	return revokeEndpoint
+}
+
+// Method returns the HTTP REST method for the revoke service.
+func (h *Revoke) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the revoke service.
+func (h *Revoke) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Revoke) handle(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)[idPathVariable]
+
+	token, err := h.manager.Revoke(id)
+	if err != nil {
+		if errors.Is(err, apitoken.ErrNotFound) {
+			logger.Debugf("[%s] API token [%s] not found", revokeEndpoint, id)
+
+			writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error revoking API token [%s]: %s", revokeEndpoint, id, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeJSONResponse(w, token)
+}
+
+func writeJSONResponse(w http.ResponseWriter, v interface{}) {
+	respBytes, err := json.Marshal(v)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling response: %s", endpoint, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", endpoint, err)
+
+			return
+		}
+
+		logger.Debugf("[%s] Wrote response: %s", endpoint, body)
+	}
+}
@@ -0,0 +1,273 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/apitoken"
+	"github.com/trustbloc/orb/pkg/httpserver/auth"
+)
+
+func TestNew(t *testing.T) {
+	m := &mockManager{}
+
+	create := NewCreate(m)
+	require.Equal(t, endpoint, create.Path())
+	require.Equal(t, http.MethodPost, create.Method())
+	require.NotNil(t, create.Handler())
+
+	list := NewList(m)
+	require.Equal(t, endpoint, list.Path())
+	require.Equal(t, http.MethodGet, list.Method())
+	require.NotNil(t, list.Handler())
+
+	revoke := NewRevoke(m)
+	require.Equal(t, "/tokens/{id}/revoke", revoke.Path())
+	require.Equal(t, http.MethodPost, revoke.Method())
+	require.NotNil(t, revoke.Handler())
+}
+
+func TestHandlers(t *testing.T) {
+	newManager := func(t *testing.T) *apitoken.Manager {
+		t.Helper()
+
+		m, err := apitoken.New(mem.NewProvider(), apitoken.WithScopes("admin", "read"))
+		require.NoError(t, err)
+
+		return m
+	}
+
+	create := func(t *testing.T, h *Create, request *CreateRequest) *httptest.ResponseRecorder {
+		t.Helper()
+
+		reqBytes, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		rw := httptest.NewRecorder()
+
+		h.handle(rw, httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(reqBytes)))
+
+		return rw
+	}
+
+	t.Run("Create, list and revoke", func(t *testing.T) {
+		m := newManager(t)
+
+		expiry := time.Now().Add(time.Hour)
+
+		rw := create(t, NewCreate(m), &CreateRequest{Name: "client1", Scopes: []string{"read"}, Expiry: &expiry})
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		resp := &CreateResponse{}
+		require.NoError(t, json.NewDecoder(result.Body).Decode(resp))
+		require.NoError(t, result.Body.Close())
+		require.NotEmpty(t, resp.ID)
+		require.Equal(t, "client1", resp.Name)
+		require.Equal(t, []string{"read"}, resp.Scopes)
+		require.NotNil(t, resp.Expiry)
+		require.NotEmpty(t, resp.Value)
+
+		scopes, err := m.Scopes(resp.Value)
+		require.NoError(t, err)
+		require.Equal(t, []string{"read"}, scopes)
+
+		rw = httptest.NewRecorder()
+
+		NewList(m).handle(rw, httptest.NewRequest(http.MethodGet, endpoint, nil))
+
+		result = rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		var tokens []*apitoken.Token
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&tokens))
+		require.NoError(t, result.Body.Close())
+		require.Len(t, tokens, 1)
+		require.Equal(t, resp.ID, tokens[0].ID)
+		require.NotContains(t, rw.Body.String(), resp.Value)
+
+		rw = httptest.NewRecorder()
+
+		req := httptest.NewRequest(http.MethodPost, endpoint+"/"+resp.ID+"/revoke", nil)
+		req = mux.SetURLVars(req, map[string]string{idPathVariable: resp.ID})
+
+		NewRevoke(m).handle(rw, req)
+
+		result = rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		token := &apitoken.Token{}
+		require.NoError(t, json.NewDecoder(result.Body).Decode(token))
+		require.NoError(t, result.Body.Close())
+		require.NotNil(t, token.Revoked)
+
+		_, err = m.Scopes(resp.Value)
+		require.True(t, errors.Is(err, apitoken.ErrInvalidToken))
+	})
+
+	t.Run("List - empty", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		NewList(newManager(t)).handle(rw, httptest.NewRequest(http.MethodGet, endpoint, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, "[]", rw.Body.String())
+	})
+
+	t.Run("Create - invalid request", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		NewCreate(newManager(t)).handle(rw, httptest.NewRequest(http.MethodPost, endpoint,
+			bytes.NewReader([]byte("{"))))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Create - invalid scope", func(t *testing.T) {
+		rw := create(t, NewCreate(newManager(t)), &CreateRequest{Scopes: []string{"write"}})
+
+		result := rw.Result()
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+		require.Contains(t, rw.Body.String(), "invalid scope: write")
+	})
+
+	t.Run("Create - invalid expiry", func(t *testing.T) {
+		expiry := time.Now().Add(-time.Hour)
+
+		rw := create(t, NewCreate(newManager(t)), &CreateRequest{Scopes: []string{"read"}, Expiry: &expiry})
+
+		result := rw.Result()
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Revoke - not found", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		req := httptest.NewRequest(http.MethodPost, endpoint+"/unknown/revoke", nil)
+		req = mux.SetURLVars(req, map[string]string{idPathVariable: "unknown"})
+
+		NewRevoke(newManager(t)).handle(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusNotFound, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Manager errors", func(t *testing.T) {
+		m := &mockManager{err: errors.New("injected error")}
+
+		for _, test := range []struct {
+			handle func(w http.ResponseWriter, req *http.Request)
+			req    *http.Request
+		}{
+			{NewCreate(m).handle, httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader([]byte("{}")))},
+			{NewList(m).handle, httptest.NewRequest(http.MethodGet, endpoint, nil)},
+			{NewRevoke(m).handle, httptest.NewRequest(http.MethodPost, endpoint+"/id/revoke", nil)},
+		} {
+			rw := httptest.NewRecorder()
+
+			test.handle(rw, test.req)
+
+			result := rw.Result()
+			require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+			require.NoError(t, result.Body.Close())
+		}
+	})
+}
+
+func TestAuthRequired(t *testing.T) {
+	m, err := apitoken.New(mem.NewProvider(), apitoken.WithScopes("admin", "read"))
+	require.NoError(t, err)
+
+	_, adminToken, err := m.Create("admin-client", []string{"admin"}, time.Time{})
+	require.NoError(t, err)
+
+	newRequest := func(token string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader([]byte(`{"scopes":["admin"]}`)))
+
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		return req
+	}
+
+	t.Run("No token definition for endpoint -> unauthorized", func(t *testing.T) {
+		h := auth.NewHandlerWrapper(auth.Config{TokenResolvers: []auth.TokenResolver{m}}, NewCreate(m),
+			auth.WithAuthRequired())
+
+		for _, token := range []string{"", adminToken} {
+			rw := httptest.NewRecorder()
+
+			h.Handler()(rw, newRequest(token))
+
+			result := rw.Result()
+			require.Equal(t, http.StatusUnauthorized, result.StatusCode)
+			require.NoError(t, result.Body.Close())
+		}
+	})
+
+	t.Run("Token definition for endpoint", func(t *testing.T) {
+		h := auth.NewHandlerWrapper(auth.Config{
+			AuthTokensDef: []*auth.TokenDef{
+				{EndpointExpression: "/tokens", ReadTokens: []string{"admin"}, WriteTokens: []string{"admin"}},
+			},
+			TokenResolvers: []auth.TokenResolver{m},
+			DynamicTokens:  []string{"admin"},
+		}, NewCreate(m), auth.WithAuthRequired())
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, newRequest(""))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusUnauthorized, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+
+		rw = httptest.NewRecorder()
+
+		h.Handler()(rw, newRequest(adminToken))
+
+		result = rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+}
+
+type mockManager struct {
+	err error
+}
+
+func (m *mockManager) Create(string, []string, time.Time) (*apitoken.Token, string, error) {
+	return nil, "", m.err
+}
+
+func (m *mockManager) List() ([]*apitoken.Token, error) {
+	return nil, m.err
+}
+
+func (m *mockManager) Revoke(string) (*apitoken.Token, error) {
+	return nil, m.err
+}
@@ -0,0 +1,603 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package filesystem implements a content-addressable storage provider which stores content as files
+// in a local directory.
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/bluele/gcache"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
+	"github.com/trustbloc/orb/pkg/cas/ipfs"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/multihash"
+)
+
+var logger = log.New("cas-filesystem")
+
+const (
+	defaultCacheSize = 1000
+	casType          = "disk"
+
+	// Content is sharded into sub-directories named after the next-to-last two characters of the CID
+	// (the same scheme used by the IPFS flatfs datastore) since the beginning of a CID is mostly constant.
+	shardLength = 2
+
+	tempFilePrefix = ".tmp-"
+
+	// Corrupt content is moved to this sub-directory so that it may be inspected later.
+	quarantineDirName = ".quarantine"
+
+	dirPerm  = 0o750
+	filePerm = 0o640
+)
+
+type metricsProvider interface {
+	CASIncrementCacheHitCount()
+	CASReadTime(casType string, value time.Duration)
+}
+
+type referenceChecker interface {
+	IsReferenced(resourceHash string) (bool, error)
+}
+
+type gcOptions struct {
+	maxSize int64
+	minAge  time.Duration
+	refs    referenceChecker
+}
+
+// Option is a filesystem CAS option.
+type Option func(c *CAS)
+
+// WithCacheSize sets the number of entries held in the in-memory content cache.
+func WithCacheSize(value int) Option {
+	return func(c *CAS) {
+		c.cacheSize = value
+	}
+}
+
+// WithCIDFormatOptions sets the default CID format options that are used for writes.
+func WithCIDFormatOptions(opts ...extendedcasclient.CIDFormatOption) Option {
+	return func(c *CAS) {
+		c.opts = opts
+	}
+}
+
+// WithIPFSReplication causes all writes to be replicated in IPFS using the given client.
+// Reads are always done from the local directory.
+func WithIPFSReplication(client *ipfs.Client) Option {
+	return func(c *CAS) {
+		c.ipfsClient = client
+	}
+}
+
+// WithGarbageCollection enables garbage collection. When the total size of the stored content exceeds maxSize (bytes)
+// then the least recently written content which is older than minAge and which the reference checker reports as
+// unreferenced is deleted until the total size is below maxSize.
+func WithGarbageCollection(maxSize int64, minAge time.Duration, refs referenceChecker) Option {
+	return func(c *CAS) {
+		c.gc = &gcOptions{
+			maxSize: maxSize,
+			minAge:  minAge,
+			refs:    refs,
+		}
+	}
+}
+
+// CAS is a content-addressable storage provider which stores each piece of content in a separate file.
+// The file name is the (v1) CID of the content. Writes are atomic (the content is written to a temporary file
+// which is then renamed) and the hash of the content is verified on each read. Corrupt files are moved
+// to the .quarantine sub-directory.
+type CAS struct {
+	size       int64 // Accessed atomically. Must be the first field for 64-bit alignment.
+	dir        string
+	casLink    string
+	ipfsClient *ipfs.Client
+	opts       []extendedcasclient.CIDFormatOption
+	cacheSize  int
+	cache      gcache.Cache
+	metrics    metricsProvider
+	hl         *hashlink.HashLink
+	gc         *gcOptions
+	gcRunning  uint32
+}
+
+// New returns a new filesystem CAS which stores content in the given directory. The directory is created
+// if it doesn't exist.
+func New(dir, casLink string, metrics metricsProvider, opts ...Option) (*CAS, error) {
+	c := &CAS{
+		dir:       dir,
+		casLink:   casLink,
+		metrics:   metrics,
+		hl:        hashlink.New(),
+		cacheSize: defaultCacheSize,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return nil, fmt.Errorf("create CAS directory [%s]: %w", dir, err)
+	}
+
+	if c.gc != nil {
+		entries, err := c.walk()
+		if err != nil {
+			return nil, fmt.Errorf("calculate size of CAS directory [%s]: %w", dir, err)
+		}
+
+		c.size = entries.totalSize()
+
+		logger.Infof("Garbage collection enabled for CAS directory [%s] - Current size: %d, Max size: %d",
+			dir, c.size, c.gc.maxSize)
+	}
+
+	if c.cacheSize <= 0 {
+		c.cacheSize = defaultCacheSize
+	}
+
+	c.cache = gcache.New(c.cacheSize).ARC().
+		LoaderFunc(func(key interface{}) (interface{}, error) {
+			content, err := c.get(key.(string))
+			if err != nil {
+				return nil, err
+			}
+
+			logger.Debugf("Cached content for resource hash [%s]", key)
+
+			return content, nil
+		},
+		).Build()
+
+	return c, nil
+}
+
+// Write writes the given content to the CAS directory (and IPFS if configured) using the default CID format.
+// Returns the hashlink of the content.
+func (c *CAS) Write(content []byte) (string, error) {
+	return c.WriteWithCIDFormat(content, c.opts...)
+}
+
+// WriteWithCIDFormat writes the given content to the CAS directory (and IPFS if configured) using the
+// CID format specified by opts. The CID format only applies to IPFS since content is always stored locally
+// using a v1 CID. Returns the hashlink of the content.
+func (c *CAS) WriteWithCIDFormat(content []byte, opts ...extendedcasclient.CIDFormatOption) (string, error) {
+	resourceHash, err := c.hl.CreateResourceHash(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to create resource hash from content: %w", err)
+	}
+
+	err = c.put(resourceHash, content)
+	if err != nil {
+		return "", err
+	}
+
+	links := []string{c.casLink + "/" + resourceHash}
+
+	if c.ipfsClient != nil {
+		cid, writeErr := c.ipfsClient.WriteWithCIDFormat(content, opts...)
+		if writeErr != nil {
+			return "", orberrors.NewTransient(fmt.Errorf("failed to put content into IPFS (but it was "+
+				"successfully stored in the local CAS directory): %w", writeErr))
+		}
+
+		links = append(links, "ipfs://"+cid)
+	}
+
+	if err = c.cache.Set(resourceHash, content); err != nil {
+		// This shouldn't be possible.
+		logger.Warnf("Error caching content for resource hash[%s]: %s", resourceHash, err)
+	}
+
+	metadata, err := c.hl.CreateMetadataFromLinks(links)
+	if err != nil {
+		return "", fmt.Errorf("failed to create metadata from links: %w", err)
+	}
+
+	return hashlink.GetHashLink(resourceHash, metadata), nil
+}
+
+// GetPrimaryWriterType returns primary writer type.
+func (c *CAS) GetPrimaryWriterType() string {
+	return "local"
+}
+
+// Read reads the content for the given resource hash (or CID) from the CAS directory.
+func (c *CAS) Read(address string) ([]byte, error) {
+	resourceHash := address
+
+	if multihash.IsValidCID(address) {
+		hash, err := multihash.CIDToMultihash(address)
+		if err != nil {
+			return nil, fmt.Errorf("convert CID [%s] to multihash: %w", address, err)
+		}
+
+		resourceHash = hash
+	}
+
+	if c.cache.Has(resourceHash) {
+		c.metrics.CASIncrementCacheHitCount()
+	}
+
+	content, err := c.cache.Get(resourceHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return content.([]byte), nil
+}
+
+// CollectGarbage deletes unreferenced content (oldest first) until the total size of the CAS directory
+// is below the configured maximum size. The number of deleted files is returned.
+// Garbage collection must be enabled using the WithGarbageCollection option.
+func (c *CAS) CollectGarbage() (int, error) {
+	if c.gc == nil {
+		return 0, errors.New("garbage collection is not enabled")
+	}
+
+	entries, err := c.walk()
+	if err != nil {
+		return 0, fmt.Errorf("walk CAS directory: %w", err)
+	}
+
+	size := entries.totalSize()
+
+	atomic.StoreInt64(&c.size, size)
+
+	if size <= c.gc.maxSize {
+		logger.Debugf("Size of CAS directory [%d] doesn't exceed the max size [%d]", size, c.gc.maxSize)
+
+		return 0, nil
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.Before(entries[j].modTime)
+	})
+
+	var deleted int
+
+	for _, e := range entries {
+		if size <= c.gc.maxSize || time.Since(e.modTime) < c.gc.minAge {
+			break
+		}
+
+		referenced, err := c.gc.refs.IsReferenced(e.resourceHash)
+		if err != nil {
+			return deleted, fmt.Errorf("check references for [%s]: %w", e.resourceHash, err)
+		}
+
+		if referenced {
+			continue
+		}
+
+		if err := os.Remove(e.path); err != nil {
+			return deleted, fmt.Errorf("remove [%s]: %w", e.path, err)
+		}
+
+		c.cache.Remove(e.resourceHash)
+
+		size -= e.size
+		atomic.AddInt64(&c.size, -e.size)
+		deleted++
+
+		logger.Debugf("Deleted unreferenced content [%s]", e.resourceHash)
+	}
+
+	logger.Infof("Garbage collection deleted %d files. Size of CAS directory: %d", deleted, size)
+
+	return deleted, nil
+}
+
+// ForEach invokes the given function for each piece of content in the CAS directory along with the time at which
+// the content was last written.
+func (c *CAS) ForEach(fn func(resourceHash string, created time.Time) error) error {
+	entries, err := c.walk()
+	if err != nil {
+		return fmt.Errorf("walk CAS directory: %w", err)
+	}
+
+	for _, e := range entries {
+		if err := fn(e.resourceHash, e.modTime); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete deletes the content with the given resource hash.
+func (c *CAS) Delete(resourceHash string) error {
+	path, err := c.pathFor(resourceHash)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("stat [%s]: %w", path, err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("remove [%s]: %w", path, err)
+	}
+
+	atomic.AddInt64(&c.size, -info.Size())
+
+	c.cache.Remove(resourceHash)
+
+	logger.Debugf("Deleted content for resource hash [%s]", resourceHash)
+
+	return nil
+}
+
+// ReadRaw reads the stored content for the given resource hash directly from the CAS directory without using
+// the cache or verifying the hash of the content.
+func (c *CAS) ReadRaw(resourceHash string) ([]byte, error) {
+	path, err := c.pathFor(resourceHash)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, orberrors.ErrContentNotFound
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("failed to read content from the CAS directory: %w", err))
+	}
+
+	return content, nil
+}
+
+// Quarantine moves the content with the given resource hash to the .quarantine sub-directory of the
+// CAS directory. Quarantined content is no longer served by the CAS.
+func (c *CAS) Quarantine(resourceHash string) error {
+	path, err := c.pathFor(resourceHash)
+	if err != nil {
+		return err
+	}
+
+	return c.quarantine(resourceHash, path)
+}
+
+func (c *CAS) quarantine(resourceHash, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("stat [%s]: %w", path, err)
+	}
+
+	quarantineDir := filepath.Join(c.dir, quarantineDirName)
+
+	if err := os.MkdirAll(quarantineDir, dirPerm); err != nil {
+		return fmt.Errorf("create quarantine directory: %w", err)
+	}
+
+	if err := os.Rename(path, filepath.Join(quarantineDir, filepath.Base(path))); err != nil {
+		return fmt.Errorf("move [%s] to quarantine: %w", path, err)
+	}
+
+	atomic.AddInt64(&c.size, -info.Size())
+
+	c.cache.Remove(resourceHash)
+
+	logger.Infof("Moved content for resource hash [%s] to quarantine", resourceHash)
+
+	return nil
+}
+
+func (c *CAS) put(resourceHash string, content []byte) error {
+	path, err := c.pathFor(resourceHash)
+	if err != nil {
+		return err
+	}
+
+	if _, err = os.Stat(path); err == nil {
+		// The content already exists. Update the modified time so that it's treated as recent by garbage collection.
+		now := time.Now()
+
+		if err = os.Chtimes(path, now, now); err != nil {
+			logger.Warnf("Unable to update modified time of [%s]: %s", path, err)
+		}
+
+		return nil
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), dirPerm); err != nil {
+		return orberrors.NewTransient(fmt.Errorf("create shard directory: %w", err))
+	}
+
+	if err = writeFileAtomic(path, content); err != nil {
+		return orberrors.NewTransient(fmt.Errorf("failed to write content to the CAS directory: %w", err))
+	}
+
+	if c.gc != nil && atomic.AddInt64(&c.size, int64(len(content))) > c.gc.maxSize {
+		c.triggerGC()
+	}
+
+	return nil
+}
+
+func (c *CAS) get(resourceHash string) ([]byte, error) {
+	startTime := time.Now()
+
+	defer func() {
+		c.metrics.CASReadTime(casType, time.Since(startTime))
+	}()
+
+	path, err := c.pathFor(resourceHash)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, orberrors.ErrContentNotFound
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("failed to read content from the CAS directory: %w", err))
+	}
+
+	if err = c.hl.VerifyResourceHash(resourceHash, content); err != nil {
+		// The content is corrupt. Quarantine it and return 'not found' so that a good copy may be retrieved
+		// from another source (e.g. WebCAS).
+		logger.Errorf("Corrupt content in CAS file [%s] - the file will be quarantined: %s", path, err)
+
+		if quarantineErr := c.quarantine(resourceHash, path); quarantineErr != nil {
+			logger.Warnf("Unable to quarantine corrupt CAS file [%s]: %s", path, quarantineErr)
+		}
+
+		return nil, fmt.Errorf("%s: %w", err, orberrors.ErrContentNotFound)
+	}
+
+	return content, nil
+}
+
+func (c *CAS) pathFor(resourceHash string) (string, error) {
+	cid, err := multihash.ToV1CID(resourceHash)
+	if err != nil {
+		return "", fmt.Errorf("invalid resource hash [%s]: %w", resourceHash, err)
+	}
+
+	shard := cid[len(cid)-shardLength-1 : len(cid)-1]
+
+	return filepath.Join(c.dir, shard, cid), nil
+}
+
+func (c *CAS) triggerGC() {
+	if !atomic.CompareAndSwapUint32(&c.gcRunning, 0, 1) {
+		return
+	}
+
+	go func() {
+		defer atomic.StoreUint32(&c.gcRunning, 0)
+
+		if _, err := c.CollectGarbage(); err != nil {
+			logger.Warnf("Error collecting garbage in CAS directory [%s]: %s", c.dir, err)
+		}
+	}()
+}
+
+type entry struct {
+	path         string
+	resourceHash string
+	size         int64
+	modTime      time.Time
+}
+
+type entries []*entry
+
+func (e entries) totalSize() int64 {
+	var size int64
+
+	for _, entry := range e {
+		size += entry.size
+	}
+
+	return size
+}
+
+func (c *CAS) walk() (entries, error) {
+	var result entries
+
+	err := filepath.Walk(c.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if info.Name() == quarantineDirName {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if strings.HasPrefix(info.Name(), tempFilePrefix) {
+			return nil
+		}
+
+		resourceHash, err := multihash.CIDToMultihash(info.Name())
+		if err != nil {
+			logger.Debugf("Ignoring file [%s] in CAS directory: %s", path, err)
+
+			return nil
+		}
+
+		result = append(result, &entry{
+			path:         path,
+			resourceHash: resourceHash,
+			size:         info.Size(),
+			modTime:      info.ModTime(),
+		})
+
+		return nil
+	})
+
+	return result, err
+}
+
+// writeFileAtomic writes the content to a temporary file in the same directory and then renames it so that
+// readers never see partially written content.
+func writeFileAtomic(path string, content []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), tempFilePrefix)
+	if err != nil {
+		return err
+	}
+
+	tempPath := f.Name()
+
+	defer func() {
+		// The temporary file no longer exists if the rename succeeded.
+		_ = os.Remove(tempPath) //nolint:errcheck
+	}()
+
+	if _, err = f.Write(content); err != nil {
+		_ = f.Close() //nolint:errcheck
+
+		return err
+	}
+
+	if err = f.Sync(); err != nil {
+		_ = f.Close() //nolint:errcheck
+
+		return err
+	}
+
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Chmod(tempPath, filePerm); err != nil {
+		return err
+	}
+
+	return os.Rename(tempPath, path)
+}
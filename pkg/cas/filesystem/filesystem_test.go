@@ -0,0 +1,341 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+	"github.com/trustbloc/orb/pkg/multihash"
+)
+
+const (
+	casLink = "https://domain.com/cas"
+
+	contentHash = "uEiDtcAK0OemshF8iNX2CK6wURHMPvbYBbT7JQyKXueyfcw"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "cas")
+
+		c, err := New(dir, casLink, &orbmocks.MetricsProvider{})
+		require.NoError(t, err)
+		require.NotNil(t, c)
+		require.DirExists(t, dir)
+		require.Equal(t, "local", c.GetPrimaryWriterType())
+	})
+
+	t.Run("Invalid directory", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, ioutil.WriteFile(file, []byte("content"), filePerm))
+
+		c, err := New(filepath.Join(file, "cas"), casLink, &orbmocks.MetricsProvider{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create CAS directory")
+		require.Nil(t, c)
+	})
+}
+
+func TestCAS_WriteRead(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		dir := t.TempDir()
+
+		c, err := New(dir, casLink, &orbmocks.MetricsProvider{}, WithCacheSize(10))
+		require.NoError(t, err)
+
+		hl, err := c.Write([]byte("content"))
+		require.NoError(t, err)
+
+		info, err := hashlink.New().ParseHashLink(hl)
+		require.NoError(t, err)
+		require.Equal(t, contentHash, info.ResourceHash)
+		require.Equal(t, []string{casLink + "/" + contentHash}, info.Links)
+
+		cid, err := multihash.ToV1CID(contentHash)
+		require.NoError(t, err)
+
+		require.FileExists(t, filepath.Join(dir, cid[len(cid)-3:len(cid)-1], cid))
+
+		// Write the same content again.
+		hl2, err := c.Write([]byte("content"))
+		require.NoError(t, err)
+		require.Equal(t, hl, hl2)
+
+		// Read from a new instance so that the content isn't cached.
+		c2, err := New(dir, casLink, &orbmocks.MetricsProvider{})
+		require.NoError(t, err)
+
+		content, err := c2.Read(contentHash)
+		require.NoError(t, err)
+		require.Equal(t, "content", string(content))
+
+		content, err = c2.Read(cid)
+		require.NoError(t, err)
+		require.Equal(t, "content", string(content))
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		c, err := New(t.TempDir(), casLink, &orbmocks.MetricsProvider{})
+		require.NoError(t, err)
+
+		content, err := c.Read(contentHash)
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+		require.Nil(t, content)
+	})
+
+	t.Run("Invalid resource hash", func(t *testing.T) {
+		c, err := New(t.TempDir(), casLink, &orbmocks.MetricsProvider{})
+		require.NoError(t, err)
+
+		content, err := c.Read("invalid")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid resource hash")
+		require.Nil(t, content)
+	})
+
+	t.Run("Corrupt content", func(t *testing.T) {
+		dir := t.TempDir()
+
+		c, err := New(dir, casLink, &orbmocks.MetricsProvider{})
+		require.NoError(t, err)
+
+		_, err = c.Write([]byte("content"))
+		require.NoError(t, err)
+
+		path, err := c.pathFor(contentHash)
+		require.NoError(t, err)
+
+		require.NoError(t, ioutil.WriteFile(path, []byte("corrupt content"), filePerm))
+
+		c2, err := New(dir, casLink, &orbmocks.MetricsProvider{})
+		require.NoError(t, err)
+
+		content, err := c2.Read(contentHash)
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+		require.Contains(t, err.Error(), "hash of content doesn't match resource hash")
+		require.Nil(t, content)
+
+		// The corrupt file should have been quarantined.
+		require.NoFileExists(t, path)
+		require.FileExists(t, filepath.Join(dir, quarantineDirName, filepath.Base(path)))
+	})
+}
+
+func TestCAS_ReadRaw_Quarantine(t *testing.T) {
+	dir := t.TempDir()
+
+	c, err := New(dir, casLink, &orbmocks.MetricsProvider{})
+	require.NoError(t, err)
+
+	_, err = c.Write([]byte("content"))
+	require.NoError(t, err)
+
+	path, err := c.pathFor(contentHash)
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("corrupt content"), filePerm))
+
+	// ReadRaw doesn't verify the hash.
+	content, err := c.ReadRaw(contentHash)
+	require.NoError(t, err)
+	require.Equal(t, []byte("corrupt content"), content)
+
+	require.NoError(t, c.Quarantine(contentHash))
+	require.NoFileExists(t, path)
+	require.FileExists(t, filepath.Join(dir, quarantineDirName, filepath.Base(path)))
+
+	// Quarantined content isn't served or enumerated.
+	_, err = c.Read(contentHash)
+	require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+
+	_, err = c.ReadRaw(contentHash)
+	require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+
+	require.NoError(t, c.ForEach(func(resourceHash string, _ time.Time) error {
+		return fmt.Errorf("unexpected content [%s]", resourceHash)
+	}))
+
+	// Quarantining content that doesn't exist is not an error.
+	require.NoError(t, c.Quarantine(contentHash))
+
+	require.Error(t, c.Quarantine("invalid"))
+
+	_, err = c.ReadRaw("invalid")
+	require.Error(t, err)
+}
+
+func TestCAS_ForEach_Delete(t *testing.T) {
+	c, err := New(t.TempDir(), casLink, &orbmocks.MetricsProvider{})
+	require.NoError(t, err)
+
+	_, err = c.Write([]byte("content"))
+	require.NoError(t, err)
+
+	var hashes []string
+
+	require.NoError(t, c.ForEach(func(resourceHash string, created time.Time) error {
+		require.WithinDuration(t, time.Now(), created, 2*time.Second)
+
+		hashes = append(hashes, resourceHash)
+
+		return nil
+	}))
+	require.Equal(t, []string{contentHash}, hashes)
+
+	err = c.ForEach(func(string, time.Time) error {
+		return errors.New("injected error")
+	})
+	require.EqualError(t, err, "injected error")
+
+	require.NoError(t, c.Delete(contentHash))
+
+	_, err = c.Read(contentHash)
+	require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+
+	// Deleting content that doesn't exist isn't an error.
+	require.NoError(t, c.Delete(contentHash))
+
+	require.Error(t, c.Delete("invalid"))
+}
+
+func TestCAS_CollectGarbage(t *testing.T) {
+	t.Run("Not enabled", func(t *testing.T) {
+		c, err := New(t.TempDir(), casLink, &orbmocks.MetricsProvider{})
+		require.NoError(t, err)
+
+		_, err = c.CollectGarbage()
+		require.EqualError(t, err, "garbage collection is not enabled")
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		dir := t.TempDir()
+
+		refs := &mockReferenceChecker{referenced: make(map[string]bool)}
+
+		c, err := New(dir, casLink, &orbmocks.MetricsProvider{}, WithGarbageCollection(1000, 0, refs))
+		require.NoError(t, err)
+
+		// Don't let writes trigger garbage collection in the background.
+		c.gcRunning = 1
+
+		hashes := make([]string, 3)
+
+		for i := range hashes {
+			content := make([]byte, 400)
+			content[0] = byte(i)
+
+			hl, e := c.Write(content)
+			require.NoError(t, e)
+
+			hashes[i], e = hashlink.GetResourceHashFromHashLink(hl)
+			require.NoError(t, e)
+
+			path, e := c.pathFor(hashes[i])
+			require.NoError(t, e)
+
+			modTime := time.Now().Add(time.Duration(i-len(hashes)) * time.Minute)
+			require.NoError(t, os.Chtimes(path, modTime, modTime))
+		}
+
+		// The oldest content is referenced so the second oldest should be deleted.
+		refs.referenced[hashes[0]] = true
+
+		deleted, err := c.CollectGarbage()
+		require.NoError(t, err)
+		require.Equal(t, 1, deleted)
+
+		_, err = c.Read(hashes[0])
+		require.NoError(t, err)
+
+		_, err = c.Read(hashes[1])
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+
+		_, err = c.Read(hashes[2])
+		require.NoError(t, err)
+
+		// The size is now below the maximum.
+		deleted, err = c.CollectGarbage()
+		require.NoError(t, err)
+		require.Zero(t, deleted)
+	})
+
+	t.Run("Min age", func(t *testing.T) {
+		refs := &mockReferenceChecker{referenced: make(map[string]bool)}
+
+		c, err := New(t.TempDir(), casLink, &orbmocks.MetricsProvider{},
+			WithGarbageCollection(10, time.Hour, refs))
+		require.NoError(t, err)
+
+		c.gcRunning = 1
+
+		_, err = c.Write([]byte("content"))
+		require.NoError(t, err)
+
+		deleted, err := c.CollectGarbage()
+		require.NoError(t, err)
+		require.Zero(t, deleted)
+	})
+
+	t.Run("Reference checker error", func(t *testing.T) {
+		refs := &mockReferenceChecker{err: errors.New("injected reference error")}
+
+		c, err := New(t.TempDir(), casLink, &orbmocks.MetricsProvider{}, WithGarbageCollection(1, 0, refs))
+		require.NoError(t, err)
+
+		c.gcRunning = 1
+
+		_, err = c.Write([]byte("content"))
+		require.NoError(t, err)
+
+		_, err = c.CollectGarbage()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected reference error")
+	})
+
+	t.Run("Triggered by write", func(t *testing.T) {
+		refs := &mockReferenceChecker{referenced: make(map[string]bool)}
+
+		c, err := New(t.TempDir(), casLink, &orbmocks.MetricsProvider{}, WithGarbageCollection(1, 0, refs))
+		require.NoError(t, err)
+
+		_, err = c.Write([]byte("content"))
+		require.NoError(t, err)
+
+		path, err := c.pathFor(contentHash)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			_, e := os.Stat(path)
+
+			return os.IsNotExist(e)
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+type mockReferenceChecker struct {
+	referenced map[string]bool
+	err        error
+}
+
+func (m *mockReferenceChecker) IsReferenced(resourceHash string) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+
+	return m.referenced[resourceHash], nil
+}
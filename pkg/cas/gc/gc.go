@@ -0,0 +1,219 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package gc implements garbage collection of CAS content. References from anchors to CAS content are added
+// to a reference index (mark) as anchors are processed and the collector deletes content that isn't in the index
+// and is older than a grace period (sweep).
+package gc
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/orb/pkg/lifecycle"
+)
+
+var logger = log.New("cas-gc")
+
+const defaultGracePeriod = 24 * time.Hour
+
+// ErrCollectionInProgress is returned if a garbage collection is requested while another one is in progress.
+var ErrCollectionInProgress = errors.New("garbage collection is already in progress")
+
+type contentStore interface {
+	// ForEach invokes the given function for each piece of content in the CAS along with the time at which
+	// the content was added.
+	ForEach(fn func(resourceHash string, created time.Time) error) error
+	Delete(resourceHash string) error
+}
+
+type referenceChecker interface {
+	IsReferenced(resourceHash string) (bool, error)
+}
+
+// Report contains the results of a garbage collection.
+type Report struct {
+	// DryRun is true if unreferenced content was only reported and not deleted.
+	DryRun bool `json:"dryRun"`
+
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+
+	// Scanned is the number of pieces of content in the CAS.
+	Scanned int `json:"scanned"`
+
+	// WithinGracePeriod is the number of pieces of content that were skipped since they are newer
+	// than the grace period.
+	WithinGracePeriod int `json:"withinGracePeriod"`
+
+	// Referenced is the number of pieces of content that are used by at least one anchor.
+	Referenced int `json:"referenced"`
+
+	// Unreferenced contains the resource hashes of the content that isn't used by any anchor.
+	Unreferenced []string `json:"unreferenced,omitempty"`
+
+	// Deleted is the number of pieces of unreferenced content that were deleted.
+	Deleted int `json:"deleted"`
+
+	// Failed is the number of pieces of unreferenced content that could not be deleted.
+	Failed int `json:"failed"`
+}
+
+// Option is a collector option.
+type Option func(c *Collector)
+
+// WithGracePeriod sets the minimum age of content before it may be deleted. The grace period gives the anchor
+// that uses newly written content time to be processed (and the references to be added).
+func WithGracePeriod(value time.Duration) Option {
+	return func(c *Collector) {
+		c.gracePeriod = value
+	}
+}
+
+// WithInterval sets the interval at which garbage collection is run in the background. If not set (or zero)
+// then garbage collection is only run on demand.
+func WithInterval(value time.Duration) Option {
+	return func(c *Collector) {
+		c.interval = value
+	}
+}
+
+// Collector deletes CAS content which isn't used by any anchor.
+type Collector struct {
+	*lifecycle.Lifecycle
+
+	store       contentStore
+	refs        referenceChecker
+	gracePeriod time.Duration
+	interval    time.Duration
+	running     uint32
+	done        chan struct{}
+}
+
+// New returns a new garbage collector.
+func New(store contentStore, refs referenceChecker, opts ...Option) *Collector {
+	c := &Collector{
+		store:       store,
+		refs:        refs,
+		gracePeriod: defaultGracePeriod,
+		done:        make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.Lifecycle = lifecycle.New("cas-gc", lifecycle.WithStart(c.start), lifecycle.WithStop(c.stop))
+
+	return c
+}
+
+// Collect finds all CAS content which is older than the grace period and which isn't used by any anchor.
+// If dryRun is false then the unreferenced content is deleted.
+func (c *Collector) Collect(dryRun bool) (*Report, error) {
+	if !atomic.CompareAndSwapUint32(&c.running, 0, 1) {
+		return nil, ErrCollectionInProgress
+	}
+
+	defer atomic.StoreUint32(&c.running, 0)
+
+	report := &Report{DryRun: dryRun, StartTime: time.Now()}
+
+	var candidates []string
+
+	err := c.store.ForEach(func(resourceHash string, created time.Time) error {
+		report.Scanned++
+
+		if time.Since(created) < c.gracePeriod {
+			report.WithinGracePeriod++
+
+			return nil
+		}
+
+		candidates = append(candidates, resourceHash)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan CAS content: %w", err)
+	}
+
+	for _, resourceHash := range candidates {
+		referenced, err := c.refs.IsReferenced(resourceHash)
+		if err != nil {
+			return nil, fmt.Errorf("check references for [%s]: %w", resourceHash, err)
+		}
+
+		if referenced {
+			report.Referenced++
+
+			continue
+		}
+
+		report.Unreferenced = append(report.Unreferenced, resourceHash)
+
+		if dryRun {
+			continue
+		}
+
+		if err := c.store.Delete(resourceHash); err != nil {
+			logger.Warnf("Error deleting unreferenced CAS content [%s]: %s", resourceHash, err)
+
+			report.Failed++
+
+			continue
+		}
+
+		report.Deleted++
+	}
+
+	report.EndTime = time.Now()
+
+	logger.Infof("CAS garbage collection completed (dry run: %t) - Scanned: %d, Within grace period: %d, "+
+		"Referenced: %d, Unreferenced: %d, Deleted: %d, Failed: %d", dryRun, report.Scanned,
+		report.WithinGracePeriod, report.Referenced, len(report.Unreferenced), report.Deleted, report.Failed)
+
+	return report, nil
+}
+
+func (c *Collector) start() {
+	if c.interval <= 0 {
+		logger.Infof("Periodic CAS garbage collection is disabled")
+
+		return
+	}
+
+	logger.Infof("Starting periodic CAS garbage collection - Interval: %s, Grace period: %s",
+		c.interval, c.gracePeriod)
+
+	go c.run()
+}
+
+func (c *Collector) stop() {
+	close(c.done)
+}
+
+func (c *Collector) run() {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := c.Collect(false); err != nil {
+				logger.Warnf("Error collecting CAS garbage: %s", err)
+			}
+		case <-c.done:
+			logger.Debugf("Periodic CAS garbage collection stopped")
+
+			return
+		}
+	}
+}
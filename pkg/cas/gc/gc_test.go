@@ -0,0 +1,170 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gc
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	hash1 = "uEiD1X_FvZvQzYCZrldtvj-wB12AxBUMGrkpLOAWY9s_RFA"
+	hash2 = "uEiAsOkJJ13BwBYZJ29gi3K95V1hvzkKM-yyoi5R0HtqLBw"
+	hash3 = "uEiDtcAK0OemshF8iNX2CK6wURHMPvbYBbT7JQyKXueyfcw"
+	hash4 = "uEiB_g7Flf_H8U7ktwYFIodZd_C1LH6PWdyhK3dIAEm2QaQ"
+)
+
+func TestCollector_Collect(t *testing.T) {
+	newStore := func() *mockContentStore {
+		return newMockContentStore().
+			withContent(hash1, time.Now().Add(-48*time.Hour)).
+			withContent(hash2, time.Now().Add(-48*time.Hour)).
+			withContent(hash3, time.Now().Add(-48*time.Hour)).
+			withContent(hash4, time.Now())
+	}
+
+	refs := &mockReferenceChecker{referenced: map[string]bool{hash1: true}}
+
+	t.Run("Dry run", func(t *testing.T) {
+		store := newStore()
+
+		c := New(store, refs, WithGracePeriod(time.Hour))
+
+		report, err := c.Collect(true)
+		require.NoError(t, err)
+		require.True(t, report.DryRun)
+		require.Equal(t, 4, report.Scanned)
+		require.Equal(t, 1, report.WithinGracePeriod)
+		require.Equal(t, 1, report.Referenced)
+		require.ElementsMatch(t, []string{hash2, hash3}, report.Unreferenced)
+		require.Zero(t, report.Deleted)
+		require.Len(t, store.content, 4)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		store := newStore()
+		store.deleteErr = map[string]error{hash3: errors.New("injected delete error")}
+
+		c := New(store, refs, WithGracePeriod(time.Hour))
+
+		report, err := c.Collect(false)
+		require.NoError(t, err)
+		require.False(t, report.DryRun)
+		require.ElementsMatch(t, []string{hash2, hash3}, report.Unreferenced)
+		require.Equal(t, 1, report.Deleted)
+		require.Equal(t, 1, report.Failed)
+		require.Len(t, store.content, 3)
+		require.NotContains(t, store.content, hash2)
+	})
+
+	t.Run("Already running", func(t *testing.T) {
+		c := New(newStore(), refs)
+		c.running = 1
+
+		_, err := c.Collect(true)
+		require.True(t, errors.Is(err, ErrCollectionInProgress))
+	})
+
+	t.Run("Scan error", func(t *testing.T) {
+		store := newStore()
+		store.forEachErr = errors.New("injected scan error")
+
+		_, err := New(store, refs).Collect(true)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected scan error")
+	})
+
+	t.Run("Reference checker error", func(t *testing.T) {
+		_, err := New(newStore(), &mockReferenceChecker{err: errors.New("injected reference error")}).Collect(true)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected reference error")
+	})
+}
+
+func TestCollector_Periodic(t *testing.T) {
+	store := newMockContentStore().withContent(hash1, time.Now().Add(-time.Hour))
+
+	c := New(store, &mockReferenceChecker{}, WithGracePeriod(time.Minute), WithInterval(10*time.Millisecond))
+
+	c.Start()
+	defer c.Stop()
+
+	require.Eventually(t, func() bool {
+		return store.size() == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+type mockContentStore struct {
+	mutex      sync.Mutex
+	content    map[string]time.Time
+	forEachErr error
+	deleteErr  map[string]error
+}
+
+func newMockContentStore() *mockContentStore {
+	return &mockContentStore{content: make(map[string]time.Time)}
+}
+
+func (m *mockContentStore) withContent(resourceHash string, created time.Time) *mockContentStore {
+	m.content[resourceHash] = created
+
+	return m
+}
+
+func (m *mockContentStore) ForEach(fn func(resourceHash string, created time.Time) error) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.forEachErr != nil {
+		return m.forEachErr
+	}
+
+	for resourceHash, created := range m.content {
+		if err := fn(resourceHash, created); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *mockContentStore) Delete(resourceHash string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if err := m.deleteErr[resourceHash]; err != nil {
+		return err
+	}
+
+	delete(m.content, resourceHash)
+
+	return nil
+}
+
+func (m *mockContentStore) size() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return len(m.content)
+}
+
+type mockReferenceChecker struct {
+	referenced map[string]bool
+	err        error
+}
+
+func (m *mockReferenceChecker) IsReferenced(resourceHash string) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+
+	return m.referenced[resourceHash], nil
+}
@@ -0,0 +1,36 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"github.com/trustbloc/orb/pkg/cas/gc"
+	"github.com/trustbloc/orb/pkg/cas/internal/dryrunhandler"
+)
+
+const endpoint = "/cas/gc"
+
+type collector interface {
+	Collect(dryRun bool) (*gc.Report, error)
+}
+
+// Collect runs CAS garbage collection and returns the report. Unreferenced content is only deleted if the 'dryrun'
+// parameter is set to false; otherwise it is reported but not deleted.
+type Collect struct {
+	*dryrunhandler.Operation
+}
+
+// NewCollect returns a new handler that runs CAS garbage collection.
+func NewCollect(c collector) *Collect {
+	return &Collect{
+		Operation: dryrunhandler.NewOperation(endpoint, "collecting CAS garbage",
+			func(dryRun bool) (interface{}, error) {
+				return c.Collect(dryRun)
+			},
+			gc.ErrCollectionInProgress,
+		),
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/cas/gc"
+)
+
+func TestNewCollect(t *testing.T) {
+	h := NewCollect(&mockCollector{})
+	require.Equal(t, endpoint, h.Path())
+	require.Equal(t, http.MethodPost, h.Method())
+	require.NotNil(t, h.Handler())
+}
+
+func TestCollect_Handler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c := &mockCollector{report: &gc.Report{Scanned: 3, Unreferenced: []string{"hash1"}, Deleted: 1}}
+
+		rw := httptest.NewRecorder()
+
+		NewCollect(c).Handler()(rw, httptest.NewRequest(http.MethodPost, endpoint+"?dryrun=false", nil))
+
+		result := rw.Result()
+		defer result.Body.Close() //nolint: errcheck
+
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.False(t, c.dryRun)
+
+		report := &gc.Report{}
+		require.NoError(t, json.NewDecoder(result.Body).Decode(report))
+		require.Equal(t, 3, report.Scanned)
+		require.Equal(t, []string{"hash1"}, report.Unreferenced)
+		require.Equal(t, 1, report.Deleted)
+	})
+
+	t.Run("Dry run", func(t *testing.T) {
+		c := &mockCollector{report: &gc.Report{DryRun: true}}
+
+		rw := httptest.NewRecorder()
+
+		NewCollect(c).Handler()(rw, httptest.NewRequest(http.MethodPost, endpoint+"?dryrun=true", nil))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.True(t, c.dryRun)
+	})
+
+	t.Run("Dry run by default", func(t *testing.T) {
+		c := &mockCollector{}
+
+		rw := httptest.NewRecorder()
+
+		NewCollect(c).Handler()(rw, httptest.NewRequest(http.MethodPost, endpoint, nil))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.True(t, c.dryRun)
+	})
+
+	t.Run("Invalid dry run parameter", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		NewCollect(&mockCollector{}).Handler()(rw, httptest.NewRequest(http.MethodPost, endpoint+"?dryrun=xxx", nil))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+	})
+
+	t.Run("Collection in progress", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		NewCollect(&mockCollector{err: gc.ErrCollectionInProgress}).Handler()(rw,
+			httptest.NewRequest(http.MethodPost, endpoint, nil))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, http.StatusConflict, result.StatusCode)
+	})
+
+	t.Run("Collector error", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		NewCollect(&mockCollector{err: fmt.Errorf("scan: %w", errors.New("injected error"))}).Handler()(rw,
+			httptest.NewRequest(http.MethodPost, endpoint, nil))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+	})
+}
+
+type mockCollector struct {
+	report *gc.Report
+	err    error
+	dryRun bool
+}
+
+func (m *mockCollector) Collect(dryRun bool) (*gc.Report, error) {
+	m.dryRun = dryRun
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return m.report, nil
+}
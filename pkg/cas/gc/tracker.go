@@ -0,0 +1,131 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gc
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/compression"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/txnprovider/models"
+
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/store/casref"
+)
+
+type casResolver interface {
+	Resolve(webCASURL *url.URL, cid string, data []byte) ([]byte, error)
+}
+
+type referenceStore interface {
+	Put(refs ...*casref.Reference) error
+}
+
+// ReferenceTracker adds the CAS content that is used by an anchor to the reference index. The content consists of
+// the anchor credential itself along with all of the Sidetree batch files that are (directly or indirectly)
+// referenced by the core index file of the anchor.
+type ReferenceTracker struct {
+	casResolver casResolver
+	refStore    referenceStore
+	compression *compression.Registry
+}
+
+// NewReferenceTracker returns a new reference tracker.
+func NewReferenceTracker(casResolver casResolver, refStore referenceStore) *ReferenceTracker {
+	return &ReferenceTracker{
+		casResolver: casResolver,
+		refStore:    refStore,
+		compression: compression.New(compression.WithDefaultAlgorithms()),
+	}
+}
+
+// AddAnchorReferences adds references for the given anchor, the given core index file and all of the files that
+// are referenced by the core index file. The batch files are decompressed using the given compression algorithm.
+// suffixes contains the suffixes of the DIDs whose operations are included in the anchor.
+func (t *ReferenceTracker) AddAnchorReferences(anchorHL, coreIndexURI, compressionAlg string,
+	suffixes []string) error {
+	anchor := toResourceHash(anchorHL)
+
+	refs := []*casref.Reference{
+		{ResourceHash: anchor, Anchor: anchor, Type: casref.TypeAnchor, Suffixes: suffixes},
+	}
+
+	addRef := func(uri string, refType casref.Type) {
+		if uri != "" {
+			refs = append(refs, &casref.Reference{ResourceHash: toResourceHash(uri), Anchor: anchor, Type: refType})
+		}
+	}
+
+	addRef(coreIndexURI, casref.TypeCoreIndex)
+
+	coreIndexBytes, err := t.read(coreIndexURI, compressionAlg)
+	if err != nil {
+		return fmt.Errorf("read core index file [%s]: %w", coreIndexURI, err)
+	}
+
+	coreIndex, err := models.ParseCoreIndexFile(coreIndexBytes)
+	if err != nil {
+		return fmt.Errorf("parse core index file [%s]: %w", coreIndexURI, err)
+	}
+
+	addRef(coreIndex.CoreProofFileURI, casref.TypeCoreProof)
+
+	if coreIndex.ProvisionalIndexFileURI != "" {
+		addRef(coreIndex.ProvisionalIndexFileURI, casref.TypeProvisionalIndex)
+
+		provisionalIndexBytes, err := t.read(coreIndex.ProvisionalIndexFileURI, compressionAlg)
+		if err != nil {
+			return fmt.Errorf("read provisional index file [%s]: %w", coreIndex.ProvisionalIndexFileURI, err)
+		}
+
+		provisionalIndex, err := models.ParseProvisionalIndexFile(provisionalIndexBytes)
+		if err != nil {
+			return fmt.Errorf("parse provisional index file [%s]: %w", coreIndex.ProvisionalIndexFileURI, err)
+		}
+
+		addRef(provisionalIndex.ProvisionalProofFileURI, casref.TypeProvisionalProof)
+
+		for _, chunk := range provisionalIndex.Chunks {
+			addRef(chunk.ChunkFileURI, casref.TypeChunk)
+		}
+	}
+
+	err = t.refStore.Put(refs...)
+	if err != nil {
+		return fmt.Errorf("store references for anchor [%s]: %w", anchorHL, err)
+	}
+
+	logger.Debugf("Added %d CAS references for anchor [%s]", len(refs), anchorHL)
+
+	return nil
+}
+
+func (t *ReferenceTracker) read(uri, compressionAlg string) ([]byte, error) {
+	content, err := t.casResolver.Resolve(nil, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.compression.Decompress(compressionAlg, content)
+}
+
+// toResourceHash returns the resource hash of the given hashlink. If the URI is not a hashlink then it is
+// returned as is.
+func toResourceHash(uri string) string {
+	if !strings.HasPrefix(uri, hashlink.HLPrefix) {
+		return uri
+	}
+
+	resourceHash, err := hashlink.GetResourceHashFromHashLink(uri)
+	if err != nil {
+		// Shouldn't happen since the prefix was checked above.
+		return uri
+	}
+
+	return resourceHash
+}
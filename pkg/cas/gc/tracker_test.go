@@ -0,0 +1,166 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gc
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/compression"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/txnprovider/models"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/casref"
+)
+
+const (
+	compressionAlg = "GZIP"
+
+	anchorHL              = "hl:uEiD1X_FvZvQzYCZrldtvj-wB12AxBUMGrkpLOAWY9s_RFA:metadata"
+	coreIndexHL           = "hl:uEiAsOkJJ13BwBYZJ29gi3K95V1hvzkKM-yyoi5R0HtqLBw"
+	coreProofHash         = "uEiDtcAK0OemshF8iNX2CK6wURHMPvbYBbT7JQyKXueyfcw"
+	provisionalIndexHL    = "hl:uEiB_g7Flf_H8U7ktwYFIodZd_C1LH6PWdyhK3dIAEm2QaQ"
+	provisionalProofHash  = "uEiDat0G2KJ59zMHtQjMMrhrMwrdVzoB5ws1dS1Nmyfdppg"
+	chunkHL               = "hl:uEiBWVQ8R1-4XE-M2_VfKBHPUmpBpd2fAMh0sPHVMg0xFaQ"
+	anchorResourceHash    = "uEiD1X_FvZvQzYCZrldtvj-wB12AxBUMGrkpLOAWY9s_RFA"
+	coreIndexResourceHash = "uEiAsOkJJ13BwBYZJ29gi3K95V1hvzkKM-yyoi5R0HtqLBw"
+)
+
+func TestReferenceTracker_AddAnchorReferences(t *testing.T) {
+	cp := compression.New(compression.WithDefaultAlgorithms())
+
+	compress := func(v interface{}) []byte {
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+
+		c, err := cp.Compress(compressionAlg, b)
+		require.NoError(t, err)
+
+		return c
+	}
+
+	files := map[string][]byte{
+		coreIndexHL: compress(&models.CoreIndexFile{
+			CoreProofFileURI:        coreProofHash,
+			ProvisionalIndexFileURI: provisionalIndexHL,
+		}),
+		provisionalIndexHL: compress(&models.ProvisionalIndexFile{
+			ProvisionalProofFileURI: provisionalProofHash,
+			Chunks:                  []models.Chunk{{ChunkFileURI: chunkHL}},
+		}),
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		refStore := &mockReferenceStore{}
+
+		tracker := NewReferenceTracker(&mockCASResolver{files: files}, refStore)
+
+		require.NoError(t, tracker.AddAnchorReferences(anchorHL, coreIndexHL, compressionAlg, []string{"suffix1"}))
+
+		require.Len(t, refStore.refs, 6)
+
+		for _, ref := range refStore.refs {
+			require.Equal(t, anchorResourceHash, ref.Anchor)
+		}
+
+		require.Equal(t, anchorResourceHash, refStore.refs[0].ResourceHash)
+		require.Equal(t, casref.TypeAnchor, refStore.refs[0].Type)
+		require.Equal(t, []string{"suffix1"}, refStore.refs[0].Suffixes)
+
+		require.Equal(t, coreIndexResourceHash, refStore.refs[1].ResourceHash)
+		require.Equal(t, casref.TypeCoreIndex, refStore.refs[1].Type)
+
+		require.Equal(t, coreProofHash, refStore.refs[2].ResourceHash)
+		require.Equal(t, casref.TypeCoreProof, refStore.refs[2].Type)
+
+		require.Equal(t, casref.TypeProvisionalIndex, refStore.refs[3].Type)
+		require.Equal(t, provisionalProofHash, refStore.refs[4].ResourceHash)
+		require.Equal(t, casref.TypeProvisionalProof, refStore.refs[4].Type)
+		require.Equal(t, casref.TypeChunk, refStore.refs[5].Type)
+	})
+
+	t.Run("Core index only", func(t *testing.T) {
+		refStore := &mockReferenceStore{}
+
+		tracker := NewReferenceTracker(&mockCASResolver{files: map[string][]byte{
+			coreIndexHL: compress(&models.CoreIndexFile{CoreProofFileURI: coreProofHash}),
+		}}, refStore)
+
+		require.NoError(t, tracker.AddAnchorReferences(anchorHL, coreIndexHL, compressionAlg, nil))
+		require.Len(t, refStore.refs, 3)
+	})
+
+	t.Run("Read core index error", func(t *testing.T) {
+		tracker := NewReferenceTracker(&mockCASResolver{}, &mockReferenceStore{})
+
+		err := tracker.AddAnchorReferences(anchorHL, coreIndexHL, compressionAlg, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read core index file")
+	})
+
+	t.Run("Parse core index error", func(t *testing.T) {
+		c, err := cp.Compress(compressionAlg, []byte("{"))
+		require.NoError(t, err)
+
+		tracker := NewReferenceTracker(&mockCASResolver{files: map[string][]byte{coreIndexHL: c}},
+			&mockReferenceStore{})
+
+		err = tracker.AddAnchorReferences(anchorHL, coreIndexHL, compressionAlg, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse core index file")
+	})
+
+	t.Run("Read provisional index error", func(t *testing.T) {
+		tracker := NewReferenceTracker(&mockCASResolver{files: map[string][]byte{coreIndexHL: files[coreIndexHL]}},
+			&mockReferenceStore{})
+
+		err := tracker.AddAnchorReferences(anchorHL, coreIndexHL, compressionAlg, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read provisional index file")
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		tracker := NewReferenceTracker(&mockCASResolver{files: files},
+			&mockReferenceStore{err: orberrors.NewTransient(errors.New("injected store error"))})
+
+		err := tracker.AddAnchorReferences(anchorHL, coreIndexHL, compressionAlg, nil)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected store error")
+	})
+}
+
+type mockCASResolver struct {
+	files map[string][]byte
+}
+
+func (m *mockCASResolver) Resolve(_ *url.URL, cid string, _ []byte) ([]byte, error) {
+	content, ok := m.files[cid]
+	if !ok {
+		return nil, orberrors.ErrContentNotFound
+	}
+
+	return content, nil
+}
+
+type mockReferenceStore struct {
+	refs []*casref.Reference
+	err  error
+}
+
+func (m *mockReferenceStore) Put(refs ...*casref.Reference) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.refs = append(m.refs, refs...)
+
+	return nil
+}
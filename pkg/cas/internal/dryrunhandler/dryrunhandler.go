@@ -0,0 +1,140 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dryrunhandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const dryRunParam = "dryrun"
+
+const (
+	badRequestResponse          = "Bad Request."
+	conflictResponse            = "Conflict."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+var logger = log.New("cas-rest-handler")
+
+// RunFunc runs the CAS operation and returns its report. If dryRun is true then the operation should only
+// report what it would have done.
+type RunFunc func(dryRun bool) (interface{}, error)
+
+// Operation is an HTTP handler that runs a CAS maintenance operation (such as garbage collection or scrubbing)
+// and returns the report. The operation is run in dry-run mode unless the 'dryrun' parameter is explicitly set
+// to false. A 409 (Conflict) is returned if the operation fails with the given 'in progress' error.
+type Operation struct {
+	endpoint      string
+	description   string
+	run           RunFunc
+	errInProgress error
+}
+
+// NewOperation returns a new handler for the given endpoint. The description is used in log messages.
+func NewOperation(endpoint, description string, run RunFunc, errInProgress error) *Operation {
+	return &Operation{
+		endpoint:      endpoint,
+		description:   description,
+		run:           run,
+		errInProgress: errInProgress,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the operation.
+func (h *Operation) Path() string {
+	return h.endpoint
+}
+
+// Method returns the HTTP REST method for the operation.
+func (h *Operation) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the operation.
+func (h *Operation) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Operation) handle(w http.ResponseWriter, req *http.Request) {
+	dryRun := true
+
+	if value := getParam(req, dryRunParam); value != "" {
+		var err error
+
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			logger.Infof("[%s] Invalid value for parameter '%s': %s", h.endpoint, dryRunParam, value)
+
+			h.writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+			return
+		}
+	}
+
+	report, err := h.run(dryRun)
+	if err != nil {
+		if h.errInProgress != nil && errors.Is(err, h.errInProgress) {
+			logger.Infof("[%s] %s", h.endpoint, err)
+
+			h.writeResponse(w, http.StatusConflict, []byte(conflictResponse))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error %s: %s", h.endpoint, h.description, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	h.writeJSONResponse(w, report)
+}
+
+func (h *Operation) writeJSONResponse(w http.ResponseWriter, v interface{}) {
+	respBytes, err := json.Marshal(v)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling response: %s", h.endpoint, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	h.writeResponse(w, http.StatusOK, respBytes)
+}
+
+func (h *Operation) writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", h.endpoint, err)
+
+			return
+		}
+
+		logger.Debugf("[%s] Wrote response: %s", h.endpoint, body)
+	}
+}
+
+func getParam(req *http.Request, name string) string {
+	values := req.URL.Query()[name]
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
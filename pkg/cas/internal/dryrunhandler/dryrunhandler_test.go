@@ -0,0 +1,84 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dryrunhandler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const endpoint = "/cas/op"
+
+func TestOperation(t *testing.T) {
+	errInProgress := errors.New("in progress")
+
+	t.Run("Success", func(t *testing.T) {
+		var dryRun bool
+
+		h := NewOperation(endpoint, "running operation", func(d bool) (interface{}, error) {
+			dryRun = d
+
+			return map[string]int{"count": 1}, nil
+		}, errInProgress)
+
+		require.Equal(t, endpoint, h.Path())
+		require.Equal(t, http.MethodPost, h.Method())
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodPost, endpoint+"?dryrun=true", nil))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.Equal(t, "application/json", result.Header.Get("Content-Type"))
+		require.Equal(t, `{"count":1}`, rw.Body.String())
+		require.True(t, dryRun)
+
+		rw = httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodPost, endpoint+"?dryrun=false", nil))
+
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.False(t, dryRun)
+	})
+
+	t.Run("In progress", func(t *testing.T) {
+		h := NewOperation(endpoint, "running operation", func(bool) (interface{}, error) {
+			return nil, errInProgress
+		}, errInProgress)
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodPost, endpoint, nil))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, http.StatusConflict, result.StatusCode)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewOperation(endpoint, "running operation", func(bool) (interface{}, error) {
+			return make(chan int), nil
+		}, errInProgress)
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodPost, endpoint, nil))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+	})
+}
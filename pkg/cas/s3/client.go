@@ -0,0 +1,120 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package s3
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+)
+
+// emptyPayloadHash is the hex-encoded SHA-256 hash of an empty payload.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+var errObjectNotFound = errors.New("object not found")
+
+// httpError is returned if the object storage service responds with an unexpected status code.
+type httpError struct {
+	status int
+	body   string
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("status code %d: %s", e.status, e.body)
+}
+
+func (e *httpError) isTransient() bool {
+	return e.status >= http.StatusInternalServerError || e.status == http.StatusTooManyRequests
+}
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// objectClient is a minimal client for an S3-compatible object storage service. Path-style URLs are used,
+// i.e. {endpoint}/{bucket}/{key}, since they are supported by all S3-compatible services.
+type objectClient struct {
+	endpoint   *url.URL
+	bucket     string
+	httpClient httpClient
+	signer     *signer
+	now        func() time.Time
+}
+
+func (c *objectClient) putObject(key string, content []byte) error {
+	req, err := c.newRequest(http.MethodPut, key, content)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	c.signer.sign(req, hashHex(content), c.now())
+
+	_, err = c.do(req)
+
+	return err
+}
+
+func (c *objectClient) getObject(key string) ([]byte, error) {
+	req, err := c.newRequest(http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c.signer.sign(req, emptyPayloadHash, c.now())
+
+	return c.do(req)
+}
+
+func (c *objectClient) newRequest(method, key string, content []byte) (*http.Request, error) {
+	u := *c.endpoint
+	u.Path = path.Join("/", u.Path, c.bucket, key)
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+
+	if content == nil {
+		req.Body = http.NoBody
+	}
+
+	return req, nil
+}
+
+func (c *objectClient) do(req *http.Request) ([]byte, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL, err)
+	}
+
+	defer func() {
+		if errClose := resp.Body.Close(); errClose != nil {
+			logger.Warnf("Error closing response body: %s", errClose)
+		}
+	}()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return body, nil
+	case http.StatusNotFound:
+		return nil, errObjectNotFound
+	default:
+		return nil, &httpError{status: resp.StatusCode, body: string(body)}
+	}
+}
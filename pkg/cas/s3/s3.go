@@ -0,0 +1,268 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package s3 implements a content-addressable storage provider which is backed by an S3-compatible
+// object storage bucket.
+package s3
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/bluele/gcache"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/multihash"
+)
+
+var logger = log.New("cas-s3")
+
+const (
+	defaultCacheSize = 1000
+	defaultRegion    = "us-east-1"
+	defaultTimeout   = time.Minute
+
+	// WriterType is the primary writer type of the S3 CAS.
+	WriterType = "s3"
+)
+
+type metricsProvider interface {
+	CASIncrementCacheHitCount()
+	CASReadTime(casType string, value time.Duration)
+}
+
+// Config contains the settings of the object storage bucket.
+type Config struct {
+	// Endpoint is the URL of the S3-compatible service, e.g. https://s3.us-east-1.amazonaws.com
+	// or http://localhost:9000 for MinIO.
+	Endpoint string
+	// Region is the region of the bucket. Defaults to us-east-1 (which is also what MinIO expects by default).
+	Region string
+	// Bucket is the name of the bucket. The bucket must already exist.
+	Bucket string
+	// KeyPrefix is an optional prefix which is prepended to all object keys.
+	KeyPrefix string
+	// AccessKeyID is the access key ID used to sign requests.
+	AccessKeyID string
+	// SecretAccessKey is the secret access key used to sign requests.
+	SecretAccessKey string
+}
+
+// Option is an S3 CAS option.
+type Option func(c *CAS)
+
+// WithHTTPClient sets the HTTP client used to communicate with the object storage service.
+func WithHTTPClient(client httpClient) Option {
+	return func(c *CAS) {
+		c.client.httpClient = client
+	}
+}
+
+// WithCacheSize sets the number of entries held in the in-memory content cache.
+func WithCacheSize(value int) Option {
+	return func(c *CAS) {
+		c.cacheSize = value
+	}
+}
+
+// WithCIDFormatOptions sets the default CID format options that are used for writes.
+func WithCIDFormatOptions(opts ...extendedcasclient.CIDFormatOption) Option {
+	return func(c *CAS) {
+		c.opts = opts
+	}
+}
+
+// CAS is a content-addressable storage provider which stores each piece of content as an object in an
+// S3-compatible bucket. The object key is the resource hash (multibase-encoded multihash) of the content,
+// so the same content results in the same hashlink as the local CAS. The hash of the content is verified on each read.
+type CAS struct {
+	client    *objectClient
+	keyPrefix string
+	casLink   string
+	opts      []extendedcasclient.CIDFormatOption
+	cacheSize int
+	cache     gcache.Cache
+	metrics   metricsProvider
+	hl        *hashlink.HashLink
+}
+
+// New returns a new S3 CAS.
+func New(cfg *Config, casLink string, metrics metricsProvider, opts ...Option) (*CAS, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("bucket is required")
+	}
+
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint [%s]: %w", cfg.Endpoint, err)
+	}
+
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint [%s]: scheme and host are required", cfg.Endpoint)
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = defaultRegion
+	}
+
+	c := &CAS{
+		client: &objectClient{
+			endpoint:   endpoint,
+			bucket:     cfg.Bucket,
+			httpClient: &http.Client{Timeout: defaultTimeout},
+			signer: &signer{
+				accessKeyID:     cfg.AccessKeyID,
+				secretAccessKey: cfg.SecretAccessKey,
+				region:          region,
+			},
+			now: time.Now,
+		},
+		keyPrefix: cfg.KeyPrefix,
+		casLink:   casLink,
+		metrics:   metrics,
+		hl:        hashlink.New(),
+		cacheSize: defaultCacheSize,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.cacheSize <= 0 {
+		c.cacheSize = defaultCacheSize
+	}
+
+	c.cache = gcache.New(c.cacheSize).ARC().
+		LoaderFunc(func(key interface{}) (interface{}, error) {
+			content, err := c.get(key.(string))
+			if err != nil {
+				return nil, err
+			}
+
+			logger.Debugf("Cached content for resource hash [%s]", key)
+
+			return content, nil
+		},
+		).Build()
+
+	return c, nil
+}
+
+// Write writes the given content to the bucket using the default CID format.
+// Returns the hashlink of the content.
+func (c *CAS) Write(content []byte) (string, error) {
+	return c.WriteWithCIDFormat(content, c.opts...)
+}
+
+// WriteWithCIDFormat writes the given content to the bucket. The content is keyed by resource hash so the CID format
+// options have no effect on how the content is stored. Returns the hashlink of the content, which contains a link to
+// the WebCAS endpoint of this server.
+func (c *CAS) WriteWithCIDFormat(content []byte, _ ...extendedcasclient.CIDFormatOption) (string, error) {
+	resourceHash, err := c.hl.CreateResourceHash(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to create resource hash from content: %w", err)
+	}
+
+	err = c.client.putObject(c.keyPrefix+resourceHash, content)
+	if err != nil {
+		return "", toOrbError(fmt.Errorf("failed to put content into the bucket: %w", err))
+	}
+
+	if err = c.cache.Set(resourceHash, content); err != nil {
+		// This shouldn't be possible.
+		logger.Warnf("Error caching content for resource hash[%s]: %s", resourceHash, err)
+	}
+
+	metadata, err := c.hl.CreateMetadataFromLinks([]string{c.casLink + "/" + resourceHash})
+	if err != nil {
+		return "", fmt.Errorf("failed to create metadata from links: %w", err)
+	}
+
+	return hashlink.GetHashLink(resourceHash, metadata), nil
+}
+
+// GetPrimaryWriterType returns primary writer type.
+func (c *CAS) GetPrimaryWriterType() string {
+	return WriterType
+}
+
+// Read reads the content for the given resource hash (or CID) from the bucket.
+func (c *CAS) Read(address string) ([]byte, error) {
+	resourceHash := address
+
+	if multihash.IsValidCID(address) {
+		hash, err := multihash.CIDToMultihash(address)
+		if err != nil {
+			return nil, fmt.Errorf("convert CID [%s] to multihash: %w", address, err)
+		}
+
+		resourceHash = hash
+	}
+
+	if c.cache.Has(resourceHash) {
+		c.metrics.CASIncrementCacheHitCount()
+	}
+
+	content, err := c.cache.Get(resourceHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return content.([]byte), nil
+}
+
+func (c *CAS) get(resourceHash string) ([]byte, error) {
+	startTime := time.Now()
+
+	defer func() {
+		c.metrics.CASReadTime(WriterType, time.Since(startTime))
+	}()
+
+	content, err := c.client.getObject(c.keyPrefix + resourceHash)
+	if err != nil {
+		if errors.Is(err, errObjectNotFound) {
+			return nil, orberrors.ErrContentNotFound
+		}
+
+		return nil, toOrbError(fmt.Errorf("failed to get content from the bucket: %w", err))
+	}
+
+	if err = c.hl.VerifyResourceHash(resourceHash, content); err != nil {
+		// Return 'not found' so that a good copy may be retrieved from another source (e.g. WebCAS).
+		logger.Errorf("Corrupt content in bucket for resource hash [%s]: %s", resourceHash, err)
+
+		return nil, fmt.Errorf("%s: %w", err, orberrors.ErrContentNotFound)
+	}
+
+	return content, nil
+}
+
+// toOrbError returns a transient error if the error is due to a network failure or a server error
+// (which may succeed on retry).
+func toOrbError(err error) error {
+	var (
+		httpErr *httpError
+		netErr  net.Error
+	)
+
+	if errors.As(err, &httpErr) && !httpErr.isTransient() {
+		return err
+	}
+
+	if errors.As(err, &httpErr) || errors.As(err, &netErr) {
+		return orberrors.NewTransient(err)
+	}
+
+	return err
+}
@@ -0,0 +1,151 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	serviceName      = "s3"
+	amzDateFormat    = "20060102T150405Z"
+	shortDateFormat  = "20060102"
+
+	headerAuthorization = "Authorization"
+	headerAmzDate       = "X-Amz-Date"
+	headerContentSHA256 = "X-Amz-Content-Sha256"
+)
+
+// signer signs requests using AWS Signature Version 4.
+type signer struct {
+	accessKeyID     string
+	secretAccessKey string
+	region          string
+}
+
+// sign adds the AWS Signature Version 4 authorization header to the request. The host header along with all
+// headers that are already set on the request are signed. payloadHash is the hex-encoded SHA-256 hash of the body.
+func (s *signer) sign(req *http.Request, payloadHash string, t time.Time) {
+	t = t.UTC()
+
+	req.Header.Set(headerAmzDate, t.Format(amzDateFormat))
+	req.Header.Set(headerContentSHA256, payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	names := make([]string, 0, len(headers))
+
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{t.Format(shortDateFormat), s.region, serviceName, "aws4_request"}, "/")
+
+	stringToSign := strings.Join([]string{
+		signingAlgorithm,
+		t.Format(amzDateFormat),
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretAccessKey), t.Format(shortDateFormat))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, serviceName)
+	key = hmacSHA256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set(headerAuthorization, fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signingAlgorithm, s.accessKeyID, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(req *http.Request) string {
+	query := req.URL.Query()
+
+	keys := make([]string, 0, len(query))
+
+	for k := range query {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	var params []string
+
+	for _, k := range keys {
+		values := query[k]
+
+		sort.Strings(values)
+
+		for _, v := range values {
+			params = append(params, awsURIEncode(k)+"="+awsURIEncode(v))
+		}
+	}
+
+	return strings.Join(params, "&")
+}
+
+// awsURIEncode encodes all characters except for the unreserved characters (as required by AWS).
+func awsURIEncode(value string) string {
+	var b strings.Builder
+
+	for _, c := range []byte(value) {
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
+
+func hashHex(data []byte) string {
+	h := sha256.Sum256(data)
+
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+
+	_, _ = h.Write([]byte(data)) //nolint:errcheck
+
+	return h.Sum(nil)
+}
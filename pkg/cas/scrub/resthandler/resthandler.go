@@ -0,0 +1,36 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"github.com/trustbloc/orb/pkg/cas/internal/dryrunhandler"
+	"github.com/trustbloc/orb/pkg/cas/scrub"
+)
+
+const endpoint = "/cas/scrub"
+
+type scrubber interface {
+	Scrub(dryRun bool) (*scrub.Report, error)
+}
+
+// Scrub verifies the integrity of the local CAS and returns the report. Corrupt content is only quarantined if the
+// 'dryrun' parameter is set to false; otherwise it is reported but not quarantined.
+type Scrub struct {
+	*dryrunhandler.Operation
+}
+
+// NewScrub returns a new handler that scrubs the local CAS.
+func NewScrub(s scrubber) *Scrub {
+	return &Scrub{
+		Operation: dryrunhandler.NewOperation(endpoint, "scrubbing CAS",
+			func(dryRun bool) (interface{}, error) {
+				return s.Scrub(dryRun)
+			},
+			scrub.ErrScrubInProgress,
+		),
+	}
+}
@@ -0,0 +1,293 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package scrub implements an integrity scrubber for the local CAS. The scrubber recomputes the multihash of each
+// entry, flags (and optionally quarantines) the entries whose content doesn't match their hash and retrieves good
+// copies from WebCAS peers or IPFS.
+package scrub
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/lifecycle"
+)
+
+var logger = log.New("cas-scrub")
+
+const ipfsPrefix = "ipfs://"
+
+// ErrScrubInProgress is returned if a scrub is requested while another one is in progress.
+var ErrScrubInProgress = errors.New("CAS scrub is already in progress")
+
+type contentStore interface {
+	ForEach(fn func(resourceHash string, created time.Time) error) error
+	// ReadRaw returns the stored content without using a cache or verifying the hash.
+	ReadRaw(resourceHash string) ([]byte, error)
+	Quarantine(resourceHash string) error
+}
+
+type casResolver interface {
+	Resolve(webCASURL *url.URL, cid string, data []byte) ([]byte, error)
+}
+
+type metricsProvider interface {
+	CASIncrementCorruptCount()
+}
+
+// Report contains the results of a scrub.
+type Report struct {
+	// DryRun is true if corrupt content was only reported and not quarantined.
+	DryRun bool `json:"dryRun"`
+
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+
+	// Scanned is the number of pieces of content that were verified.
+	Scanned int `json:"scanned"`
+
+	// Corrupt contains the resource hashes of the content that doesn't match its hash.
+	Corrupt []string `json:"corrupt,omitempty"`
+
+	// Quarantined is the number of corrupt pieces of content that were moved to quarantine.
+	Quarantined int `json:"quarantined"`
+
+	// Repaired is the number of corrupt pieces of content that were replaced with a good copy.
+	Repaired int `json:"repaired"`
+
+	// Failed is the number of pieces of content that could not be read, quarantined or repaired.
+	Failed int `json:"failed"`
+}
+
+// Option is a scrubber option.
+type Option func(s *Scrubber)
+
+// WithInterval sets the interval at which the scrubber is run in the background. If not set (or zero)
+// then the scrubber is only run on demand.
+func WithInterval(value time.Duration) Option {
+	return func(s *Scrubber) {
+		s.interval = value
+	}
+}
+
+// WithRepair enables the repair of corrupt content. After a corrupt entry is quarantined, a good copy is
+// retrieved with the given CAS resolver from the WebCAS endpoints of the given domains (in order) and then,
+// if ipfsEnabled is true, from IPFS. The resolver verifies the hash of the retrieved content and stores it
+// in the local CAS.
+func WithRepair(resolver casResolver, domains []string, ipfsEnabled bool) Option {
+	return func(s *Scrubber) {
+		s.resolver = resolver
+		s.domains = domains
+		s.ipfsEnabled = ipfsEnabled
+	}
+}
+
+// Scrubber verifies the integrity of the content in the local CAS.
+type Scrubber struct {
+	*lifecycle.Lifecycle
+
+	store       contentStore
+	metrics     metricsProvider
+	hl          *hashlink.HashLink
+	interval    time.Duration
+	resolver    casResolver
+	domains     []string
+	ipfsEnabled bool
+	running     uint32
+	done        chan struct{}
+}
+
+// New returns a new CAS scrubber.
+func New(store contentStore, metrics metricsProvider, opts ...Option) *Scrubber {
+	s := &Scrubber{
+		store:   store,
+		metrics: metrics,
+		hl:      hashlink.New(),
+		done:    make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.Lifecycle = lifecycle.New("cas-scrub", lifecycle.WithStart(s.start), lifecycle.WithStop(s.stop))
+
+	return s
+}
+
+// Scrub recomputes the hash of each piece of content in the CAS and reports the content which doesn't match
+// its hash. If dryRun is false then the corrupt content is quarantined and, if repair is enabled, a good copy
+// is retrieved from another source.
+func (s *Scrubber) Scrub(dryRun bool) (*Report, error) {
+	if !atomic.CompareAndSwapUint32(&s.running, 0, 1) {
+		return nil, ErrScrubInProgress
+	}
+
+	defer atomic.StoreUint32(&s.running, 0)
+
+	report := &Report{DryRun: dryRun, StartTime: time.Now()}
+
+	err := s.store.ForEach(func(resourceHash string, _ time.Time) error {
+		s.verify(resourceHash, report)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan CAS content: %w", err)
+	}
+
+	if !dryRun {
+		for _, resourceHash := range report.Corrupt {
+			s.fix(resourceHash, report)
+		}
+	}
+
+	report.EndTime = time.Now()
+
+	logger.Infof("CAS scrub completed (dry run: %t) - Scanned: %d, Corrupt: %d, Quarantined: %d, Repaired: %d, "+
+		"Failed: %d", dryRun, report.Scanned, len(report.Corrupt), report.Quarantined, report.Repaired, report.Failed)
+
+	return report, nil
+}
+
+func (s *Scrubber) verify(resourceHash string, report *Report) {
+	content, err := s.store.ReadRaw(resourceHash)
+	if err != nil {
+		if errors.Is(err, orberrors.ErrContentNotFound) {
+			// The content was deleted after the scan started.
+			return
+		}
+
+		logger.Warnf("Error reading CAS content [%s]: %s", resourceHash, err)
+
+		report.Failed++
+
+		return
+	}
+
+	report.Scanned++
+
+	if err := s.hl.VerifyResourceHash(resourceHash, content); err != nil {
+		logger.Errorf("Corrupt CAS content [%s]: %s", resourceHash, err)
+
+		s.metrics.CASIncrementCorruptCount()
+
+		report.Corrupt = append(report.Corrupt, resourceHash)
+	}
+}
+
+func (s *Scrubber) fix(resourceHash string, report *Report) {
+	if err := s.store.Quarantine(resourceHash); err != nil {
+		logger.Warnf("Error quarantining corrupt CAS content [%s]: %s", resourceHash, err)
+
+		report.Failed++
+
+		return
+	}
+
+	report.Quarantined++
+
+	if s.resolver == nil {
+		return
+	}
+
+	if err := s.repair(resourceHash); err != nil {
+		logger.Warnf("Error repairing corrupt CAS content [%s]: %s", resourceHash, err)
+
+		report.Failed++
+
+		return
+	}
+
+	logger.Infof("Repaired corrupt CAS content [%s]", resourceHash)
+
+	report.Repaired++
+}
+
+func (s *Scrubber) repair(resourceHash string) error {
+	var errs []string
+
+	for _, source := range s.repairSources(resourceHash) {
+		_, err := s.resolver.Resolve(nil, source, nil)
+		if err == nil {
+			return nil
+		}
+
+		logger.Debugf("Unable to retrieve CAS content from [%s]: %s", source, err)
+
+		errs = append(errs, err.Error())
+	}
+
+	if len(errs) == 0 {
+		return errors.New("no repair sources are configured")
+	}
+
+	return fmt.Errorf("content not retrieved from any source: %s", strings.Join(errs, "; "))
+}
+
+// repairSources returns the hints with which the CAS resolver retrieves the content from the remote sources.
+func (s *Scrubber) repairSources(resourceHash string) []string {
+	var sources []string
+
+	for _, domain := range s.domains {
+		sources = append(sources, "https:"+domain+":"+resourceHash)
+	}
+
+	if s.ipfsEnabled {
+		// The IPFS reader converts the resource hash to a CID.
+		metadata, err := s.hl.CreateMetadataFromLinks([]string{ipfsPrefix + resourceHash})
+		if err != nil {
+			// Shouldn't happen since a link was provided.
+			logger.Warnf("Error creating hashlink metadata for [%s]: %s", resourceHash, err)
+		} else {
+			sources = append(sources, hashlink.GetHashLink(resourceHash, metadata))
+		}
+	}
+
+	return sources
+}
+
+func (s *Scrubber) start() {
+	if s.interval <= 0 {
+		logger.Infof("Periodic CAS scrub is disabled")
+
+		return
+	}
+
+	logger.Infof("Starting periodic CAS scrub - Interval: %s", s.interval)
+
+	go s.run()
+}
+
+func (s *Scrubber) stop() {
+	close(s.done)
+}
+
+func (s *Scrubber) run() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := s.Scrub(false); err != nil {
+				logger.Warnf("Error scrubbing CAS: %s", err)
+			}
+		case <-s.done:
+			logger.Debugf("Periodic CAS scrub stopped")
+
+			return
+		}
+	}
+}
@@ -0,0 +1,334 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package scrub
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/cas/filesystem"
+	"github.com/trustbloc/orb/pkg/cas/resolver"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+	"github.com/trustbloc/orb/pkg/multihash"
+)
+
+const casLink = "https://orb.domain1.com/cas"
+
+func TestScrubber_Scrub(t *testing.T) {
+	hl := hashlink.New()
+
+	hash1, err := hl.CreateResourceHash([]byte("content1"))
+	require.NoError(t, err)
+
+	hash2, err := hl.CreateResourceHash([]byte("content2"))
+	require.NoError(t, err)
+
+	hash3, err := hl.CreateResourceHash([]byte("content3"))
+	require.NoError(t, err)
+
+	newStore := func() *mockContentStore {
+		return newMockContentStore().
+			withContent(hash1, []byte("content1")).
+			withContent(hash2, []byte("corrupt content2")).
+			withContent(hash3, []byte("corrupt content3"))
+	}
+
+	t.Run("Dry run", func(t *testing.T) {
+		store := newStore()
+
+		report, err := New(store, &orbmocks.MetricsProvider{}).Scrub(true)
+		require.NoError(t, err)
+		require.True(t, report.DryRun)
+		require.Equal(t, 3, report.Scanned)
+		require.ElementsMatch(t, []string{hash2, hash3}, report.Corrupt)
+		require.Zero(t, report.Quarantined)
+		require.Empty(t, store.quarantined)
+	})
+
+	t.Run("Quarantine", func(t *testing.T) {
+		store := newStore()
+		store.quarantineErr = map[string]error{hash3: errors.New("injected quarantine error")}
+
+		report, err := New(store, &orbmocks.MetricsProvider{}).Scrub(false)
+		require.NoError(t, err)
+		require.False(t, report.DryRun)
+		require.ElementsMatch(t, []string{hash2, hash3}, report.Corrupt)
+		require.Equal(t, 1, report.Quarantined)
+		require.Equal(t, 1, report.Failed)
+		require.Zero(t, report.Repaired)
+		require.Equal(t, []string{hash2}, store.quarantined)
+	})
+
+	t.Run("Repair", func(t *testing.T) {
+		store := newStore()
+
+		r := &mockCASResolver{
+			store: store,
+			content: map[string][]byte{
+				"https:orb.domain2.com:" + hash2: []byte("content2"),
+			},
+		}
+
+		s := New(store, &orbmocks.MetricsProvider{}, WithRepair(r, []string{"orb.domain2.com"}, true))
+
+		report, err := s.Scrub(false)
+		require.NoError(t, err)
+		require.Equal(t, 2, report.Quarantined)
+		require.Equal(t, 1, report.Repaired)
+		require.Equal(t, 1, report.Failed)
+		require.Equal(t, []byte("content2"), store.content[hash2])
+
+		// The content of hash3 wasn't found at the domain or in IPFS.
+		// The corrupt content may be repaired in any order.
+		require.Len(t, r.requested, 3)
+		require.Contains(t, r.requested, "https:orb.domain2.com:"+hash2)
+		require.Contains(t, r.requested, "https:orb.domain2.com:"+hash3)
+
+		var ipfsRequested bool
+
+		for _, request := range r.requested {
+			if strings.HasPrefix(request, "hl:"+hash3+":") {
+				ipfsRequested = true
+			}
+		}
+
+		require.True(t, ipfsRequested)
+	})
+
+	t.Run("No repair sources", func(t *testing.T) {
+		store := newStore()
+
+		report, err := New(store, &orbmocks.MetricsProvider{},
+			WithRepair(&mockCASResolver{store: store}, nil, false)).Scrub(false)
+		require.NoError(t, err)
+		require.Equal(t, 2, report.Quarantined)
+		require.Equal(t, 2, report.Failed)
+	})
+
+	t.Run("Read error", func(t *testing.T) {
+		store := newStore()
+		store.readErr = map[string]error{
+			hash1: errors.New("injected read error"),
+			hash2: orberrors.ErrContentNotFound,
+		}
+
+		report, err := New(store, &orbmocks.MetricsProvider{}).Scrub(true)
+		require.NoError(t, err)
+		require.Equal(t, 1, report.Scanned)
+		require.Equal(t, 1, report.Failed)
+		require.Equal(t, []string{hash3}, report.Corrupt)
+	})
+
+	t.Run("Scan error", func(t *testing.T) {
+		store := newStore()
+		store.forEachErr = errors.New("injected scan error")
+
+		_, err := New(store, &orbmocks.MetricsProvider{}).Scrub(true)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected scan error")
+	})
+
+	t.Run("Already running", func(t *testing.T) {
+		s := New(newStore(), &orbmocks.MetricsProvider{})
+		s.running = 1
+
+		_, err := s.Scrub(true)
+		require.True(t, errors.Is(err, ErrScrubInProgress))
+	})
+}
+
+func TestScrubber_FilesystemCAS(t *testing.T) {
+	dir := t.TempDir()
+
+	casClient, err := filesystem.New(dir, casLink, &orbmocks.MetricsProvider{})
+	require.NoError(t, err)
+
+	hl, err := casClient.Write([]byte("content"))
+	require.NoError(t, err)
+
+	resourceHash, err := hashlink.GetResourceHashFromHashLink(hl)
+	require.NoError(t, err)
+
+	cid, err := multihash.ToV1CID(resourceHash)
+	require.NoError(t, err)
+
+	var path string
+
+	require.NoError(t, filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err == nil && info.Name() == cid {
+			path = p
+		}
+
+		return err
+	}))
+	require.NotEmpty(t, path)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("corrupt content"), 0o600))
+
+	ipfsReader := &mockIPFSReader{content: map[string][]byte{resourceHash: []byte("content")}}
+
+	s := New(casClient, &orbmocks.MetricsProvider{},
+		WithRepair(resolver.New(casClient, ipfsReader, resolver.WebCASResolver{}, &orbmocks.MetricsProvider{}),
+			nil, true),
+	)
+
+	report, err := s.Scrub(false)
+	require.NoError(t, err)
+	require.Equal(t, []string{resourceHash}, report.Corrupt)
+	require.Equal(t, 1, report.Quarantined)
+	require.Equal(t, 1, report.Repaired)
+
+	content, err := casClient.Read(resourceHash)
+	require.NoError(t, err)
+	require.Equal(t, []byte("content"), content)
+
+	report, err = s.Scrub(true)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Scanned)
+	require.Empty(t, report.Corrupt)
+}
+
+func TestScrubber_Periodic(t *testing.T) {
+	hash, err := hashlink.New().CreateResourceHash([]byte("content"))
+	require.NoError(t, err)
+
+	store := newMockContentStore().withContent(hash, []byte("corrupt content"))
+
+	s := New(store, &orbmocks.MetricsProvider{}, WithInterval(10*time.Millisecond))
+
+	s.Start()
+	defer s.Stop()
+
+	require.Eventually(t, func() bool {
+		return store.numQuarantined() == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+type mockContentStore struct {
+	mutex         sync.Mutex
+	content       map[string][]byte
+	quarantined   []string
+	forEachErr    error
+	readErr       map[string]error
+	quarantineErr map[string]error
+}
+
+func newMockContentStore() *mockContentStore {
+	return &mockContentStore{content: make(map[string][]byte)}
+}
+
+func (m *mockContentStore) withContent(resourceHash string, content []byte) *mockContentStore {
+	m.content[resourceHash] = content
+
+	return m
+}
+
+func (m *mockContentStore) ForEach(fn func(resourceHash string, created time.Time) error) error {
+	if m.forEachErr != nil {
+		return m.forEachErr
+	}
+
+	m.mutex.Lock()
+
+	var hashes []string
+
+	for resourceHash := range m.content {
+		hashes = append(hashes, resourceHash)
+	}
+
+	m.mutex.Unlock()
+
+	for _, resourceHash := range hashes {
+		if err := fn(resourceHash, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *mockContentStore) ReadRaw(resourceHash string) ([]byte, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if err := m.readErr[resourceHash]; err != nil {
+		return nil, err
+	}
+
+	content, ok := m.content[resourceHash]
+	if !ok {
+		return nil, orberrors.ErrContentNotFound
+	}
+
+	return content, nil
+}
+
+func (m *mockContentStore) Quarantine(resourceHash string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if err := m.quarantineErr[resourceHash]; err != nil {
+		return err
+	}
+
+	delete(m.content, resourceHash)
+
+	m.quarantined = append(m.quarantined, resourceHash)
+
+	return nil
+}
+
+func (m *mockContentStore) numQuarantined() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return len(m.quarantined)
+}
+
+type mockCASResolver struct {
+	store     *mockContentStore
+	content   map[string][]byte
+	requested []string
+}
+
+func (m *mockCASResolver) Resolve(_ *url.URL, hashWithPossibleHint string, _ []byte) ([]byte, error) {
+	m.requested = append(m.requested, hashWithPossibleHint)
+
+	content, ok := m.content[hashWithPossibleHint]
+	if !ok {
+		return nil, orberrors.ErrContentNotFound
+	}
+
+	// The hint is in the form https:<domain>:<resource hash>.
+	m.store.withContent(hashWithPossibleHint[strings.LastIndex(hashWithPossibleHint, ":")+1:], content)
+
+	return content, nil
+}
+
+type mockIPFSReader struct {
+	content map[string][]byte
+}
+
+func (m *mockIPFSReader) Read(address string) ([]byte, error) {
+	content, ok := m.content[address]
+	if !ok {
+		return nil, orberrors.ErrContentNotFound
+	}
+
+	return content, nil
+}
@@ -9,6 +9,7 @@ package client
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/trustbloc/edge-core/pkg/log"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
@@ -19,6 +20,7 @@ var logger = log.New("protocol-client")
 // Client implements protocol client.
 type Client struct {
 	protocols []protocol.Version
+	now       func() time.Time
 }
 
 // New creates new protocol client.
@@ -35,14 +37,23 @@ func New(protocolVersions []protocol.Version) *Client {
 
 	return &Client{
 		protocols: protocols,
+		now:       time.Now,
 	}
 }
 
-// Current returns the latest version of protocol.
+// Current returns the latest version of protocol that has been activated, i.e. the latest version
+// whose genesis time (in seconds since the Unix epoch) is not in the future. This allows a new version
+// to be deployed to all nodes ahead of time so that the nodes switch to the new version at the same time.
 func (c *Client) Current() (protocol.Version, error) {
-	latest := len(c.protocols) - 1
+	now := uint64(c.now().Unix())
 
-	return c.protocols[latest], nil
+	for i := len(c.protocols) - 1; i >= 0; i-- {
+		if c.protocols[i].Protocol().GenesisTime <= now {
+			return c.protocols[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("no protocol version is active at time %d", now)
 }
 
 // Get gets protocol version based on blockchain(transaction) time.
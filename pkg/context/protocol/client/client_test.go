@@ -8,6 +8,7 @@ package client
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
@@ -46,6 +47,47 @@ func TestClient_Current(t *testing.T) {
 	require.Equal(t, uint(10000), p.Protocol().MaxOperationCount)
 }
 
+func TestClient_CurrentSwitchover(t *testing.T) {
+	const genesisTime = 1000
+
+	v1_0 := &coremocks.ProtocolVersion{}
+	v1_0.VersionReturns("1.0")
+	v1_0.ProtocolReturns(protocol.Protocol{GenesisTime: 0})
+
+	v1_1 := &coremocks.ProtocolVersion{}
+	v1_1.VersionReturns("1.1")
+	v1_1.ProtocolReturns(protocol.Protocol{GenesisTime: genesisTime})
+
+	c := New([]protocol.Version{v1_1, v1_0})
+
+	c.now = func() time.Time { return time.Unix(genesisTime-1, 0) }
+
+	v, err := c.Current()
+	require.NoError(t, err)
+	require.Equal(t, "1.0", v.Version())
+
+	c.now = func() time.Time { return time.Unix(genesisTime, 0) }
+
+	v, err = c.Current()
+	require.NoError(t, err)
+	require.Equal(t, "1.1", v.Version())
+
+	// Anchors created with the previous version must still be processed by that version.
+	v, err = c.Get(0)
+	require.NoError(t, err)
+	require.Equal(t, "1.0", v.Version())
+
+	t.Run("no active version", func(t *testing.T) {
+		c := New([]protocol.Version{v1_1})
+		c.now = func() time.Time { return time.Unix(genesisTime-1, 0) }
+
+		v, err := c.Current()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is active at time")
+		require.Nil(t, v)
+	})
+}
+
 func TestClient_Get(t *testing.T) {
 	v1_0 := &coremocks.ProtocolVersion{}
 	v1_0.VersionReturns("1.0")
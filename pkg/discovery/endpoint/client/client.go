@@ -69,6 +69,7 @@ type Client struct {
 	disableProofCheck          bool
 	docLoader                  ld.DocumentLoader
 	orbClient                  orbClient
+	orbClientOpts              []orbclient.Option
 }
 
 type req struct {
@@ -112,6 +113,8 @@ func New(docLoader ld.DocumentLoader, casReader casReader, opts ...Option) (*Cli
 			)).PublicKeyFetcher()))
 	}
 
+	orbClientOpts = append(orbClientOpts, configService.orbClientOpts...)
+
 	orbClient, err := orbclient.New(configService.namespace, configService.casReader, orbClientOpts...)
 	if err != nil {
 		return nil, err
@@ -546,6 +549,14 @@ func WithDisableProofCheck(disable bool) Option {
 	}
 }
 
+// WithOrbClientOptions adds options (e.g. the genesis times of the protocol versions) for the Orb client
+// which retrieves anchor origins.
+func WithOrbClientOptions(orbClientOpts ...orbclient.Option) Option {
+	return func(opts *Client) {
+		opts.orbClientOpts = append(opts.orbClientOpts, orbClientOpts...)
+	}
+}
+
 // WithNamespace option is for custom namespace.
 func WithNamespace(namespace string) Option {
 	return func(opts *Client) {
@@ -19,6 +19,7 @@ import (
 
 	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
 	"github.com/trustbloc/orb/pkg/discovery/endpoint/restapi"
+	"github.com/trustbloc/orb/pkg/orbclient"
 	"github.com/trustbloc/orb/pkg/protocolversion/mocks"
 )
 
@@ -600,6 +601,14 @@ func TestConfigService_GetEndpoint(t *testing.T) { //nolint: gocyclo,gocognit,cy
 	})
 }
 
+func TestNew_WithOrbClientOptions(t *testing.T) {
+	cs, err := New(nil, &referenceCASReaderImplementation{},
+		WithOrbClientOptions(orbclient.WithGenesisTime("1.1", 1767225600)))
+	require.NoError(t, err)
+	require.Len(t, cs.orbClientOpts, 1)
+	require.NotNil(t, cs.orbClient)
+}
+
 func TestDefaultCASReader(t *testing.T) {
 	t.Run("success - no hint", func(t *testing.T) {
 		cs, err := New(nil, &referenceCASReaderImplementation{}, WithAuthToken("t1"), WithHTTPClient(
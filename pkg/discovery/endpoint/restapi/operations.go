@@ -7,6 +7,7 @@ package restapi
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -16,6 +17,7 @@ import (
 	"github.com/trustbloc/edge-core/pkg/log"
 	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
 
+	"github.com/trustbloc/orb/pkg/keyrotation"
 	"github.com/trustbloc/orb/pkg/resolver/resource/registry"
 )
 
@@ -49,6 +51,8 @@ const (
 const (
 	minResolvers = "https://trustbloc.dev/ns/min-resolvers"
 	context      = "https://w3id.org/did/v1"
+
+	ed25519VerificationKey2020 = "Ed25519VerificationKey2020"
 )
 
 // New returns discovery operations.
@@ -77,6 +81,8 @@ func New(c *Config) (*Operation, error) {
 		discoveryDomains:          c.DiscoveryDomains,
 		discoveryVctDomains:       c.DiscoveryVctDomains,
 		resourceRegistry:          c.ResourceRegistry,
+		signingKeys:               c.SigningKeys,
+		assertionKeys:             c.AssertionKeys,
 	}, nil
 }
 
@@ -95,6 +101,12 @@ type Operation struct {
 	discoveryVctDomains       []string
 	discoveryMinimumResolvers int
 	resourceRegistry          *registry.Registry
+	signingKeys               signingKeyProvider
+	assertionKeys             []*AssertionKey
+}
+
+type signingKeyProvider interface {
+	Keys() ([]*keyrotation.Key, error)
 }
 
 // Config defines configuration for discovery operations.
@@ -111,6 +123,21 @@ type Config struct {
 	DiscoveryVctDomains       []string
 	DiscoveryMinimumResolvers int
 	ResourceRegistry          *registry.Registry
+
+	// SigningKeys is optional. If set, the published signing keys (i.e. the current key and the keys of a
+	// rotation which haven't been retired yet) are included in the DID document instead of PubKey and KID.
+	SigningKeys signingKeyProvider
+
+	// AssertionKeys is optional. These are additional keys which are only used to sign anchor credentials
+	// (e.g. if the configured signature suite requires a key type other than the signing key's).
+	AssertionKeys []*AssertionKey
+}
+
+// AssertionKey is a key that is published in the DID document as an assertion method.
+type AssertionKey struct {
+	ID        string
+	Type      string
+	PublicKey []byte
 }
 
 // GetRESTHandlers get all controller API handler available for this service.
@@ -149,20 +176,78 @@ func (o *Operation) wellKnownHandler(rw http.ResponseWriter, r *http.Request) {
 func (o *Operation) webDIDHandler(rw http.ResponseWriter, r *http.Request) {
 	ID := "did:web:" + o.host
 
-	writeResponse(rw, &RawDoc{
-		Context: context,
-		ID:      ID,
-		VerificationMethod: []verificationMethod{{
-			ID:              ID + "#" + o.kid,
-			Controller:      ID,
-			Type:            o.verificationMethodType,
-			PublicKeyBase58: base58.Encode(o.pubKey),
-		}},
-		Authentication:       []string{ID + "#" + o.kid},
-		AssertionMethod:      []string{ID + "#" + o.kid},
-		CapabilityDelegation: []string{ID + "#" + o.kid},
-		CapabilityInvocation: []string{ID + "#" + o.kid},
-	}, http.StatusOK)
+	keys, err := o.getSigningKeys()
+	if err != nil {
+		logger.Errorf("Error getting signing keys: %s", err)
+
+		writeErrorResponse(rw, http.StatusInternalServerError, "error getting signing keys")
+
+		return
+	}
+
+	currentKID := ID + "#" + keys[0].ID
+
+	doc := &RawDoc{
+		Context:              context,
+		ID:                   ID,
+		Authentication:       []string{currentKID},
+		CapabilityDelegation: []string{currentKID},
+		CapabilityInvocation: []string{currentKID},
+	}
+
+	// Keys which are being retired may still be used to verify existing proofs.
+	for _, key := range keys {
+		doc.VerificationMethod = append(doc.VerificationMethod,
+			newVerificationMethod(ID, key.ID, o.verificationMethodType, key.PublicKey))
+
+		doc.AssertionMethod = append(doc.AssertionMethod, ID+"#"+key.ID)
+	}
+
+	for _, key := range o.assertionKeys {
+		doc.VerificationMethod = append(doc.VerificationMethod,
+			newVerificationMethod(ID, key.ID, key.Type, key.PublicKey))
+
+		doc.AssertionMethod = append(doc.AssertionMethod, ID+"#"+key.ID)
+	}
+
+	writeResponse(rw, doc, http.StatusOK)
+}
+
+// newVerificationMethod returns the verification method under which the given public key is published.
+// An Ed25519VerificationKey2020 key is published as publicKeyMultibase. The key is also published as
+// publicKeyBase58 since that's the only encoding which is understood by older DID document parsers.
+func newVerificationMethod(did, keyID, vmType string, pubKey []byte) verificationMethod {
+	vm := verificationMethod{
+		ID:              did + "#" + keyID,
+		Controller:      did,
+		Type:            vmType,
+		PublicKeyBase58: base58.Encode(pubKey),
+	}
+
+	if vmType == ed25519VerificationKey2020 {
+		// The multicodec prefix of an Ed25519 public key (0xed) is followed by the key bytes
+		// and encoded as base58btc.
+		vm.PublicKeyMultibase = "z" + base58.Encode(append([]byte{0xed, 0x01}, pubKey...))
+	}
+
+	return vm
+}
+
+func (o *Operation) getSigningKeys() ([]*keyrotation.Key, error) {
+	if o.signingKeys == nil {
+		return []*keyrotation.Key{{ID: o.kid, PublicKey: o.pubKey}}, nil
+	}
+
+	keys, err := o.signingKeys.Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no signing keys")
+	}
+
+	return keys, nil
 }
 
 // webFingerHandler swagger:route Get /.well-known/webfinger discovery webFingerReq
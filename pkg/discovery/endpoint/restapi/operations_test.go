@@ -8,15 +8,20 @@ package restapi_test
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/mr-tron/base58"
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
 
 	"github.com/trustbloc/orb/pkg/discovery/endpoint/restapi"
+	"github.com/trustbloc/orb/pkg/keyrotation"
 	"github.com/trustbloc/orb/pkg/resolver/resource/registry"
 )
 
@@ -40,6 +45,15 @@ func (m *mockResourceInfoProvider) Accept(string) bool {
 	return true
 }
 
+type mockSigningKeyProvider struct {
+	keys []*keyrotation.Key
+	err  error
+}
+
+func (m *mockSigningKeyProvider) Keys() ([]*keyrotation.Key, error) {
+	return m.keys, m.err
+}
+
 func TestGetRESTHandlers(t *testing.T) {
 	t.Run("Error - invalid base URL", func(t *testing.T) {
 		c, err := restapi.New(&restapi.Config{BaseURL: "://"})
@@ -359,6 +373,122 @@ func TestWellKnownDID(t *testing.T) {
 	require.Len(t, w.VerificationMethod, 1)
 }
 
+func TestWellKnownDID_SigningKeys(t *testing.T) {
+	retireTime := time.Now().Add(time.Hour)
+
+	t.Run("Success", func(t *testing.T) {
+		c, err := restapi.New(&restapi.Config{
+			BaseURL:    "https://example.com",
+			WebCASPath: "/cas",
+			SigningKeys: &mockSigningKeyProvider{keys: []*keyrotation.Key{
+				{ID: "key2", PublicKey: []byte("public key 2")},
+				{ID: "key1", PublicKey: []byte("public key 1"), RetireTime: &retireTime},
+			}},
+		})
+		require.NoError(t, err)
+
+		handler := getHandler(t, c, webDIDEndpoint)
+
+		rr := serveHTTP(t, handler.Handler(), http.MethodGet, webDIDEndpoint, nil, nil, false)
+
+		var w restapi.RawDoc
+
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &w))
+		require.Equal(t, w.ID, "did:web:example.com")
+		require.Len(t, w.VerificationMethod, 2)
+		require.Equal(t, []string{"did:web:example.com#key2", "did:web:example.com#key1"}, w.AssertionMethod)
+		require.Equal(t, []string{"did:web:example.com#key2"}, w.Authentication)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		c, err := restapi.New(&restapi.Config{
+			BaseURL:     "https://example.com",
+			WebCASPath:  "/cas",
+			SigningKeys: &mockSigningKeyProvider{err: errors.New("injected error")},
+		})
+		require.NoError(t, err)
+
+		handler := getHandler(t, c, webDIDEndpoint)
+
+		rr := serveHTTP(t, handler.Handler(), http.MethodGet, webDIDEndpoint, nil, nil, false)
+		require.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+}
+
+func TestWellKnownDID_AssertionKeys(t *testing.T) {
+	c, err := restapi.New(&restapi.Config{
+		BaseURL:                "https://example.com",
+		WebCASPath:             "/cas",
+		KID:                    "key1",
+		PubKey:                 []byte("public key 1"),
+		VerificationMethodType: "Ed25519VerificationKey2018",
+		AssertionKeys: []*restapi.AssertionKey{
+			{ID: "anchor-key", Type: "EcdsaSecp256k1VerificationKey2019", PublicKey: []byte("public key 2")},
+		},
+	})
+	require.NoError(t, err)
+
+	handler := getHandler(t, c, webDIDEndpoint)
+
+	rr := serveHTTP(t, handler.Handler(), http.MethodGet, webDIDEndpoint, nil, nil, false)
+
+	var w restapi.RawDoc
+
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &w))
+	require.Len(t, w.VerificationMethod, 2)
+	require.Equal(t, []string{"did:web:example.com#key1", "did:web:example.com#anchor-key"}, w.AssertionMethod)
+	require.Equal(t, []string{"did:web:example.com#key1"}, w.Authentication)
+	require.Contains(t, rr.Body.String(), "EcdsaSecp256k1VerificationKey2019")
+}
+
+func TestWellKnownDID_Ed25519VerificationKey2020(t *testing.T) {
+	// Ed25519 public key and its multibase encoding from the did:key test vectors.
+	const (
+		pubKeyBase58       = "B12NYF8RrR3h41TDCTJojY59usg3mbtbjnFs7Eud1Y6u"
+		publicKeyMultibase = "z6MkpTHR8VNsBxYAAWHut2Geadd9jSwuBV8xRoAnwWsdvktH"
+	)
+
+	pubKey, err := base58.Decode(pubKeyBase58)
+	require.NoError(t, err)
+
+	c, err := restapi.New(&restapi.Config{
+		BaseURL:                "https://example.com",
+		WebCASPath:             "/cas",
+		KID:                    "key1",
+		PubKey:                 pubKey,
+		VerificationMethodType: "Ed25519VerificationKey2020",
+	})
+	require.NoError(t, err)
+
+	handler := getHandler(t, c, webDIDEndpoint)
+
+	rr := serveHTTP(t, handler.Handler(), http.MethodGet, webDIDEndpoint, nil, nil, false)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var w map[string]interface{}
+
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &w))
+
+	vms, ok := w["verificationMethod"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, vms, 1)
+
+	vm, ok := vms[0].(map[string]interface{})
+	require.True(t, ok)
+	require.Equal(t, "Ed25519VerificationKey2020", vm["type"])
+	require.Equal(t, publicKeyMultibase, vm["publicKeyMultibase"])
+
+	// The document may still be parsed by DID document parsers which don't understand publicKeyMultibase.
+	doc, err := did.ParseDocument(rr.Body.Bytes())
+	require.NoError(t, err)
+	require.Len(t, doc.VerificationMethod, 1)
+	require.Equal(t, pubKey, doc.VerificationMethod[0].Value)
+}
+
 func TestWellKnown(t *testing.T) {
 	c, err := restapi.New(&restapi.Config{
 		OperationPath:  "/op",
@@ -0,0 +1,144 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolvehandler
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/docutil"
+)
+
+const defaultMaxConcurrentResolutions = 10
+
+// BatchResult contains the result of resolving a single document in a batch.
+type BatchResult struct {
+	ID     string
+	Result *document.ResolutionResult
+	Err    error
+}
+
+type didAnchors interface {
+	GetBulk(suffixes []string) ([]string, error)
+}
+
+// WithDIDAnchors sets the DID anchor store which is used in batch resolution to determine (with a single bulk
+// read) which of the requested published DIDs are unknown to this server, so that the operation store isn't
+// queried for those DIDs.
+func WithDIDAnchors(store didAnchors) Option {
+	return func(opts *ResolveHandler) {
+		opts.didAnchors = store
+	}
+}
+
+// WithMaxConcurrentResolutions sets the maximum number of documents that are resolved concurrently
+// in batch resolution.
+func WithMaxConcurrentResolutions(value int) Option {
+	return func(opts *ResolveHandler) {
+		opts.maxConcurrentResolutions = value
+	}
+}
+
+// ResolveDocuments resolves the documents with the given IDs concurrently (limited by the maximum number of
+// concurrent resolutions) and returns a result for each ID in the same order as the given IDs.
+func (r *ResolveHandler) ResolveDocuments(ids []string) []*BatchResult {
+	results := make([]*BatchResult, len(ids))
+
+	for i, id := range ids {
+		results[i] = &BatchResult{ID: id}
+	}
+
+	pending := r.resolveUnknown(results)
+
+	maxConcurrent := r.maxConcurrentResolutions
+	if maxConcurrent <= 0 {
+		maxConcurrent = defaultMaxConcurrentResolutions
+	}
+
+	sem := make(chan struct{}, maxConcurrent)
+
+	var wg sync.WaitGroup
+
+	for _, result := range pending {
+		wg.Add(1)
+
+		sem <- struct{}{}
+
+		go func(result *BatchResult) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			result.Result, result.Err = r.ResolveDocument(result.ID)
+		}(result)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// resolveUnknown sets a 'not found' error on the results of published DIDs that have no anchor in the DID anchor
+// store (and requests discovery for those DIDs if enabled). The remaining results (which need to be resolved)
+// are returned.
+func (r *ResolveHandler) resolveUnknown(results []*BatchResult) []*BatchResult {
+	if r.didAnchors == nil {
+		return results
+	}
+
+	var (
+		pending   []*BatchResult
+		published []*BatchResult
+		suffixes  []string
+	)
+
+	for _, result := range results {
+		if r.unpublishedDIDLabel != "" && strings.Contains(result.ID, r.unpublishedDIDLabel) {
+			// Unpublished (or long-form) DIDs may be resolved without an anchor.
+			pending = append(pending, result)
+
+			continue
+		}
+
+		parts := strings.Split(result.ID, docutil.NamespaceDelimiter)
+
+		published = append(published, result)
+		suffixes = append(suffixes, parts[len(parts)-1])
+	}
+
+	if len(suffixes) == 0 {
+		return pending
+	}
+
+	anchors, err := r.didAnchors.GetBulk(suffixes)
+	if err != nil {
+		logger.Warnf("Error retrieving anchors for %d suffixes - all documents will be resolved: %s", len(suffixes), err)
+
+		return results
+	}
+
+	for i, result := range published {
+		if anchors[i] != "" {
+			pending = append(pending, result)
+
+			continue
+		}
+
+		logger.Debugf("No anchor found for suffix [%s] of id [%s]", suffixes[i], result.ID)
+
+		if r.enableDidDiscovery {
+			r.requestDiscovery(result.ID)
+		}
+
+		result.Err = fmt.Errorf("%s: %w", result.ID, ErrDocumentNotFound)
+	}
+
+	return pending
+}
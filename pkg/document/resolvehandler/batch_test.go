@@ -0,0 +1,167 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolvehandler
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+
+	"github.com/trustbloc/orb/pkg/document/resolvehandler/mocks"
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+)
+
+func TestResolveHandler_ResolveDocuments(t *testing.T) {
+	const (
+		knownDID   = "did:orb:cid:known"
+		unknownDID = "did:orb:cid:unknown"
+		errorDID   = "did:orb:cid:error"
+	)
+
+	newCoreHandler := func() *mocks.Resolver {
+		coreHandler := &mocks.Resolver{}
+		coreHandler.ResolveDocumentCalls(func(id string) (*document.ResolutionResult, error) {
+			switch id {
+			case errorDID:
+				return nil, errors.New("injected resolve error")
+			case unknownDID:
+				return nil, errors.New("not found")
+			default:
+				return &document.ResolutionResult{Document: document.Document{"id": id}}, nil
+			}
+		})
+
+		return coreHandler
+	}
+
+	t.Run("success - without DID anchors", func(t *testing.T) {
+		coreHandler := newCoreHandler()
+
+		handler := NewResolveHandler(testNS, coreHandler, &mocks.Discovery{}, &orbmocks.AnchorGraph{},
+			&orbmocks.MetricsProvider{}, WithUnpublishedDIDLabel(testLabel))
+
+		results := handler.ResolveDocuments([]string{knownDID, unknownDID, errorDID, testInterimDID})
+		require.Len(t, results, 4)
+
+		require.Equal(t, knownDID, results[0].ID)
+		require.NoError(t, results[0].Err)
+		require.Equal(t, knownDID, results[0].Result.Document.ID())
+
+		require.Equal(t, unknownDID, results[1].ID)
+		require.Error(t, results[1].Err)
+		require.Contains(t, results[1].Err.Error(), "not found")
+
+		require.Equal(t, errorDID, results[2].ID)
+		require.Error(t, results[2].Err)
+		require.Contains(t, results[2].Err.Error(), "injected resolve error")
+
+		require.Equal(t, testInterimDID, results[3].ID)
+		require.NoError(t, results[3].Err)
+
+		require.Equal(t, 4, coreHandler.ResolveDocumentCallCount())
+	})
+
+	t.Run("success - with DID anchors", func(t *testing.T) {
+		coreHandler := newCoreHandler()
+		discovery := &mocks.Discovery{}
+
+		handler := NewResolveHandler(testNS, coreHandler, discovery, &orbmocks.AnchorGraph{},
+			&orbmocks.MetricsProvider{}, WithUnpublishedDIDLabel(testLabel), WithEnableDIDDiscovery(true),
+			WithDIDAnchors(&mockDIDAnchors{anchors: map[string]string{"known": "cid", "error": "cid"}}))
+
+		results := handler.ResolveDocuments([]string{knownDID, unknownDID, errorDID, testInterimDID})
+		require.Len(t, results, 4)
+
+		require.NoError(t, results[0].Err)
+		require.True(t, errors.Is(results[1].Err, ErrDocumentNotFound))
+		require.Error(t, results[2].Err)
+		require.NoError(t, results[3].Err)
+
+		// The unknown DID shouldn't have been resolved but discovery should have been requested.
+		require.Equal(t, 3, coreHandler.ResolveDocumentCallCount())
+		require.Equal(t, 1, discovery.RequestDiscoveryCallCount())
+	})
+
+	t.Run("DID anchors error", func(t *testing.T) {
+		coreHandler := newCoreHandler()
+
+		handler := NewResolveHandler(testNS, coreHandler, &mocks.Discovery{}, &orbmocks.AnchorGraph{},
+			&orbmocks.MetricsProvider{}, WithUnpublishedDIDLabel(testLabel),
+			WithDIDAnchors(&mockDIDAnchors{err: errors.New("injected GetBulk error")}))
+
+		results := handler.ResolveDocuments([]string{knownDID, unknownDID})
+		require.Len(t, results, 2)
+		require.NoError(t, results[0].Err)
+		require.Error(t, results[1].Err)
+		require.Equal(t, 2, coreHandler.ResolveDocumentCallCount())
+	})
+
+	t.Run("bounded concurrency", func(t *testing.T) {
+		const maxConcurrent = 3
+
+		var current, max int32
+
+		coreHandler := &mocks.Resolver{}
+		coreHandler.ResolveDocumentCalls(func(id string) (*document.ResolutionResult, error) {
+			n := atomic.AddInt32(&current, 1)
+			defer atomic.AddInt32(&current, -1)
+
+			for {
+				m := atomic.LoadInt32(&max)
+				if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+					break
+				}
+			}
+
+			time.Sleep(5 * time.Millisecond)
+
+			return &document.ResolutionResult{}, nil
+		})
+
+		handler := NewResolveHandler(testNS, coreHandler, &mocks.Discovery{}, &orbmocks.AnchorGraph{},
+			&orbmocks.MetricsProvider{}, WithMaxConcurrentResolutions(maxConcurrent))
+
+		ids := make([]string, 20)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("did:orb:uAAA:suffix%d", i)
+		}
+
+		results := handler.ResolveDocuments(ids)
+		require.Len(t, results, len(ids))
+
+		for i, r := range results {
+			require.Equal(t, ids[i], r.ID)
+			require.NoError(t, r.Err)
+		}
+
+		require.LessOrEqual(t, atomic.LoadInt32(&max), int32(maxConcurrent))
+	})
+}
+
+type mockDIDAnchors struct {
+	anchors map[string]string
+	err     error
+}
+
+func (m *mockDIDAnchors) GetBulk(suffixes []string) ([]string, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	anchors := make([]string, len(suffixes))
+
+	for i, suffix := range suffixes {
+		anchors[i] = m.anchors[suffix]
+	}
+
+	return anchors, nil
+}
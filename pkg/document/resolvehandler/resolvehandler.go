@@ -42,6 +42,9 @@ type ResolveHandler struct {
 
 	enableCreateDocumentStore bool
 
+	didAnchors               didAnchors
+	maxConcurrentResolutions int
+
 	hl *hashlink.HashLink
 }
 
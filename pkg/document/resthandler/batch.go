@@ -0,0 +1,165 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/document/resolvehandler"
+)
+
+// DefaultMaxBatchSize is the default maximum number of IDs that may be resolved in a single request.
+const DefaultMaxBatchSize = 500
+
+// Error codes returned for IDs that could not be resolved.
+const (
+	ErrCodeInvalidDID = "invalidDid"
+	ErrCodeNotFound   = "notFound"
+	ErrCodeInternal   = "internalError"
+)
+
+// BatchResolveRequest contains the IDs to resolve.
+type BatchResolveRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// BatchResolveResponse contains a result for each of the requested IDs (in the same order as the request).
+type BatchResolveResponse struct {
+	Results []*BatchResolveResult `json:"results"`
+}
+
+// BatchResolveResult contains either the resolution result or the error for a single ID.
+type BatchResolveResult struct {
+	ID               string                     `json:"id"`
+	ResolutionResult *document.ResolutionResult `json:"resolutionResult,omitempty"`
+	Error            string                     `json:"error,omitempty"`
+	Message          string                     `json:"message,omitempty"`
+}
+
+type batchResolver interface {
+	ResolveDocuments(ids []string) []*resolvehandler.BatchResult
+}
+
+// BatchResolveHandler resolves multiple DID documents in a single request.
+type BatchResolveHandler struct {
+	path         string
+	resolver     batchResolver
+	maxBatchSize int
+	marshal      func(v interface{}) ([]byte, error)
+}
+
+// NewBatchResolveHandler returns a new batch resolve handler. If maxBatchSize is not positive then
+// DefaultMaxBatchSize is used.
+func NewBatchResolveHandler(path string, resolver batchResolver, maxBatchSize int) *BatchResolveHandler {
+	if maxBatchSize <= 0 {
+		maxBatchSize = DefaultMaxBatchSize
+	}
+
+	return &BatchResolveHandler{
+		path:         path,
+		resolver:     resolver,
+		maxBatchSize: maxBatchSize,
+		marshal:      json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the batch resolve service.
+func (h *BatchResolveHandler) Path() string {
+	return h.path
+}
+
+// Method returns the HTTP REST method for the batch resolve service.
+func (h *BatchResolveHandler) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the batch resolve service.
+func (h *BatchResolveHandler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *BatchResolveHandler) handle(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, fmt.Errorf("read request: %w", err))
+
+		return
+	}
+
+	request := &BatchResolveRequest{}
+
+	err = json.Unmarshal(reqBytes, request)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+
+		return
+	}
+
+	if len(request.IDs) == 0 {
+		common.WriteError(w, http.StatusBadRequest, fmt.Errorf("at least one ID must be specified"))
+
+		return
+	}
+
+	if len(request.IDs) > h.maxBatchSize {
+		common.WriteError(w, http.StatusBadRequest,
+			fmt.Errorf("number of IDs [%d] exceeds the maximum batch size [%d]", len(request.IDs), h.maxBatchSize))
+
+		return
+	}
+
+	logger.Debugf("Resolving %d documents", len(request.IDs))
+
+	results := h.resolver.ResolveDocuments(request.IDs)
+
+	resp := &BatchResolveResponse{Results: make([]*BatchResolveResult, len(results))}
+
+	for i, r := range results {
+		resp.Results[i] = toBatchResolveResult(r)
+	}
+
+	respBytes, err := h.marshal(resp)
+	if err != nil {
+		logger.Errorf("Error marshalling batch resolve response: %s", err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+func toBatchResolveResult(r *resolvehandler.BatchResult) *BatchResolveResult {
+	if r.Err == nil {
+		return &BatchResolveResult{ID: r.ID, ResolutionResult: r.Result}
+	}
+
+	result := &BatchResolveResult{ID: r.ID, Message: r.Err.Error()}
+
+	switch {
+	case strings.Contains(r.Err.Error(), "bad request"):
+		result.Error = ErrCodeInvalidDID
+	case strings.Contains(r.Err.Error(), "not found"):
+		result.Error = ErrCodeNotFound
+	default:
+		logger.Warnf("Error resolving document [%s]: %s", r.ID, r.Err)
+
+		result.Error = ErrCodeInternal
+	}
+
+	return result
+}
@@ -0,0 +1,125 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+
+	"github.com/trustbloc/orb/pkg/document/resolvehandler"
+)
+
+const batchPath = "/sidetree/v1/identifiers"
+
+func TestNewBatchResolveHandler(t *testing.T) {
+	h := NewBatchResolveHandler(batchPath, &mockBatchResolver{}, 0)
+	require.NotNil(t, h)
+	require.Equal(t, batchPath, h.Path())
+	require.Equal(t, http.MethodPost, h.Method())
+	require.NotNil(t, h.Handler())
+	require.Equal(t, DefaultMaxBatchSize, h.maxBatchSize)
+}
+
+func TestBatchResolveHandler(t *testing.T) {
+	const (
+		did1 = "did:orb:cid:suffix1"
+		did2 = "did:orb:cid:suffix2"
+		did3 = "did:orb:cid:suffix3"
+		did4 = "did:orb"
+	)
+
+	resolver := &mockBatchResolver{
+		results: map[string]*resolvehandler.BatchResult{
+			did1: {ID: did1, Result: &document.ResolutionResult{Document: document.Document{"id": did1}}},
+			did2: {ID: did2, Err: fmt.Errorf("%s: %w", did2, resolvehandler.ErrDocumentNotFound)},
+			did3: {ID: did3, Err: errors.New("injected error")},
+			did4: {ID: did4, Err: errors.New("bad request: invalid DID")},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		rw := batchResolve(t, NewBatchResolveHandler(batchPath, resolver, 10),
+			&BatchResolveRequest{IDs: []string{did1, did2, did3, did4}})
+		require.Equal(t, http.StatusOK, rw.Code)
+
+		resp := &BatchResolveResponse{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), resp))
+		require.Len(t, resp.Results, 4)
+
+		require.Equal(t, did1, resp.Results[0].ID)
+		require.NotNil(t, resp.Results[0].ResolutionResult)
+		require.Empty(t, resp.Results[0].Error)
+
+		require.Equal(t, ErrCodeNotFound, resp.Results[1].Error)
+		require.Nil(t, resp.Results[1].ResolutionResult)
+
+		require.Equal(t, ErrCodeInternal, resp.Results[2].Error)
+		require.Equal(t, "injected error", resp.Results[2].Message)
+
+		require.Equal(t, ErrCodeInvalidDID, resp.Results[3].Error)
+	})
+
+	t.Run("bad request", func(t *testing.T) {
+		h := NewBatchResolveHandler(batchPath, resolver, 2)
+
+		rw := httptest.NewRecorder()
+		h.Handler()(rw, httptest.NewRequest(http.MethodPost, batchPath, bytes.NewBufferString("{")))
+		require.Equal(t, http.StatusBadRequest, rw.Code)
+
+		rw = batchResolve(t, h, &BatchResolveRequest{})
+		require.Equal(t, http.StatusBadRequest, rw.Code)
+
+		rw = batchResolve(t, h, &BatchResolveRequest{IDs: []string{did1, did2, did3}})
+		require.Equal(t, http.StatusBadRequest, rw.Code)
+		require.Contains(t, rw.Body.String(), "exceeds the maximum batch size")
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		h := NewBatchResolveHandler(batchPath, resolver, 10)
+		h.marshal = func(v interface{}) ([]byte, error) {
+			return nil, errors.New("injected marshal error")
+		}
+
+		rw := batchResolve(t, h, &BatchResolveRequest{IDs: []string{did1}})
+		require.Equal(t, http.StatusInternalServerError, rw.Code)
+	})
+}
+
+func batchResolve(t *testing.T, h *BatchResolveHandler, request *BatchResolveRequest) *httptest.ResponseRecorder {
+	t.Helper()
+
+	reqBytes, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+
+	h.Handler()(rw, httptest.NewRequest(http.MethodPost, batchPath, bytes.NewBuffer(reqBytes)))
+
+	return rw
+}
+
+type mockBatchResolver struct {
+	results map[string]*resolvehandler.BatchResult
+}
+
+func (m *mockBatchResolver) ResolveDocuments(ids []string) []*resolvehandler.BatchResult {
+	results := make([]*resolvehandler.BatchResult, len(ids))
+
+	for i, id := range ids {
+		results[i] = m.results[id]
+	}
+
+	return results
+}
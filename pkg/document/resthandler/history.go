@@ -0,0 +1,234 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/docutil"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/document/versionresolver"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/hashlink"
+)
+
+const (
+	historyEndpoint = "/history"
+
+	suffixPathVariable = "suffix"
+)
+
+const (
+	notFoundResponse            = "Not Found."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+// OperationEntry contains an entry in the operation history of a DID.
+type OperationEntry struct {
+	VersionID           string         `json:"versionId"`
+	Type                operation.Type `json:"type"`
+	AnchorHashlink      string         `json:"anchorHashlink,omitempty"`
+	AnchorOrigin        interface{}    `json:"anchorOrigin,omitempty"`
+	AnchorCredentialID  string         `json:"anchorCredentialId,omitempty"`
+	AnchorTime          time.Time      `json:"anchorTime"`
+	ProtocolGenesisTime uint64         `json:"protocolGenesisTime"`
+	Witnesses           []*Witness     `json:"witnesses,omitempty"`
+}
+
+// Witness contains the details of a proof in the anchor credential.
+type Witness struct {
+	VerificationMethod string     `json:"verificationMethod,omitempty"`
+	Domain             string     `json:"domain,omitempty"`
+	Created            *time.Time `json:"created,omitempty"`
+}
+
+type operationStore interface {
+	Get(suffix string) ([]*operation.AnchoredOperation, error)
+}
+
+type anchorGraph interface {
+	Read(hl string) (*verifiable.Credential, error)
+}
+
+// HistoryHandler returns the ordered operation history of a DID along with the details of the anchor
+// that included each operation.
+type HistoryHandler struct {
+	opStore     operationStore
+	anchorGraph anchorGraph
+	marshal     func(v interface{}) ([]byte, error)
+}
+
+// NewHistoryHandler returns a new DID operation history handler.
+func NewHistoryHandler(opStore operationStore, anchorGraph anchorGraph) *HistoryHandler {
+	return &HistoryHandler{
+		opStore:     opStore,
+		anchorGraph: anchorGraph,
+		marshal:     json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the DID operation history service.
+func (h *HistoryHandler) Path() string {
+	return fmt.Sprintf("%s/{%s}", historyEndpoint, suffixPathVariable)
+}
+
+// Method returns the HTTP REST method for the DID operation history service.
+func (h *HistoryHandler) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the DID operation history service.
+func (h *HistoryHandler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *HistoryHandler) handle(w http.ResponseWriter, req *http.Request) {
+	suffix := getSuffix(mux.Vars(req)[suffixPathVariable])
+
+	ops, err := h.opStore.Get(suffix)
+	if err != nil {
+		if orberrors.IsTransient(err) {
+			logger.Errorf("[%s] Error retrieving operations for suffix [%s]: %s", historyEndpoint, suffix, err)
+
+			writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+		} else {
+			logger.Debugf("[%s] Operations not found for suffix [%s]: %s", historyEndpoint, suffix, err)
+
+			writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+		}
+
+		return
+	}
+
+	entries, err := h.getHistory(ops)
+	if err != nil {
+		logger.Errorf("[%s] Error getting history for suffix [%s]: %s", historyEndpoint, suffix, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	respBytes, err := h.marshal(entries)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling history for suffix [%s]: %s", historyEndpoint, suffix, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+func (h *HistoryHandler) getHistory(ops []*operation.AnchoredOperation) ([]*OperationEntry, error) {
+	versionresolver.SortOperations(ops)
+
+	// More than one operation for the same DID may be included in an anchor, so cache the anchor credentials.
+	anchors := make(map[string]*verifiable.Credential)
+
+	entries := make([]*OperationEntry, len(ops))
+
+	for i, op := range ops {
+		versionID, err := versionresolver.GetVersionID(op)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := &OperationEntry{
+			VersionID:           versionID,
+			Type:                op.Type,
+			AnchorOrigin:        op.AnchorOrigin,
+			AnchorTime:          time.Unix(int64(op.TransactionTime), 0).UTC(),
+			ProtocolGenesisTime: op.ProtocolGenesisTime,
+		}
+
+		if op.CanonicalReference != "" {
+			entry.AnchorHashlink = hashlink.GetHashLinkFromResourceHash(op.CanonicalReference)
+
+			vc, ok := anchors[entry.AnchorHashlink]
+			if !ok {
+				vc = h.readAnchor(entry.AnchorHashlink)
+				anchors[entry.AnchorHashlink] = vc
+			}
+
+			if vc != nil {
+				entry.AnchorCredentialID = vc.ID
+				entry.Witnesses = toWitnesses(vc.Proofs)
+			}
+		}
+
+		entries[i] = entry
+	}
+
+	return entries, nil
+}
+
+func (h *HistoryHandler) readAnchor(hl string) *verifiable.Credential {
+	vc, err := h.anchorGraph.Read(hl)
+	if err != nil {
+		// The history is still useful without the witness details so don't fail the request.
+		logger.Warnf("[%s] Unable to read anchor credential [%s]: %s", historyEndpoint, hl, err)
+
+		return nil
+	}
+
+	return vc
+}
+
+func toWitnesses(proofs []verifiable.Proof) []*Witness {
+	witnesses := make([]*Witness, len(proofs))
+
+	for i, p := range proofs {
+		w := &Witness{}
+
+		if vm, ok := p["verificationMethod"].(string); ok {
+			w.VerificationMethod = vm
+		}
+
+		if domain, ok := p["domain"].(string); ok {
+			w.Domain = domain
+		}
+
+		if created, ok := p["created"].(string); ok {
+			if t, err := time.Parse(time.RFC3339, created); err == nil {
+				w.Created = &t
+			}
+		}
+
+		witnesses[i] = w
+	}
+
+	return witnesses
+}
+
+// getSuffix returns the suffix of the given DID. If a suffix is provided then it is returned as is.
+func getSuffix(didOrSuffix string) string {
+	parts := strings.Split(didOrSuffix, docutil.NamespaceDelimiter)
+
+	return parts[len(parts)-1]
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", historyEndpoint, err)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const (
+	suffix = "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+
+	anchorCID1 = "uEiD1X_FvZvQzYCZrldtvj-wB12AxBUMGrkpLOAWY9s_RFA"
+	anchorCID2 = "uEiAsOkJJ13BwBYZJ29gi3K95V1hvzkKM-yyoi5R0HtqLBw"
+
+	witnessVM = "did:web:orb.domain2.com#key1"
+)
+
+func TestNewHistoryHandler(t *testing.T) {
+	h := NewHistoryHandler(&mockOperationStore{}, &mockAnchorGraph{})
+	require.NotNil(t, h)
+	require.Equal(t, "/history/{suffix}", h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+}
+
+func TestHistoryHandler(t *testing.T) {
+	ops := []*operation.AnchoredOperation{
+		{
+			Type:               operation.TypeUpdate,
+			UniqueSuffix:       suffix,
+			OperationBuffer:    []byte(`{"type":"update"}`),
+			TransactionTime:    2000,
+			CanonicalReference: anchorCID2,
+			AnchorOrigin:       "https://orb.domain1.com",
+		},
+		{
+			Type:               operation.TypeCreate,
+			UniqueSuffix:       suffix,
+			OperationBuffer:    []byte(`{"type":"create"}`),
+			TransactionTime:    1000,
+			CanonicalReference: anchorCID1,
+			AnchorOrigin:       "https://orb.domain1.com",
+		},
+	}
+
+	ag := &mockAnchorGraph{
+		vcs: map[string]*verifiable.Credential{
+			"hl:" + anchorCID1: {
+				ID: "https://orb.domain1.com/vc/1",
+				Proofs: []verifiable.Proof{
+					{"verificationMethod": witnessVM, "domain": "https://vct.com/log", "created": "2021-08-01T10:00:00Z"},
+				},
+			},
+		},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		h := NewHistoryHandler(&mockOperationStore{ops: ops}, ag)
+
+		rw := getHistory(h, "did:orb:"+anchorCID2+":"+suffix)
+		require.Equal(t, http.StatusOK, rw.Code)
+
+		var entries []*OperationEntry
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &entries))
+		require.Len(t, entries, 2)
+
+		require.Equal(t, operation.TypeCreate, entries[0].Type)
+		require.Equal(t, "hl:"+anchorCID1, entries[0].AnchorHashlink)
+		require.Equal(t, "https://orb.domain1.com", entries[0].AnchorOrigin)
+		require.Equal(t, "https://orb.domain1.com/vc/1", entries[0].AnchorCredentialID)
+		require.Equal(t, int64(1000), entries[0].AnchorTime.Unix())
+		require.NotEmpty(t, entries[0].VersionID)
+		require.Len(t, entries[0].Witnesses, 1)
+		require.Equal(t, witnessVM, entries[0].Witnesses[0].VerificationMethod)
+		require.Equal(t, "https://vct.com/log", entries[0].Witnesses[0].Domain)
+		require.NotNil(t, entries[0].Witnesses[0].Created)
+
+		// The anchor credential for the second operation isn't available.
+		require.Equal(t, operation.TypeUpdate, entries[1].Type)
+		require.Equal(t, "hl:"+anchorCID2, entries[1].AnchorHashlink)
+		require.Empty(t, entries[1].AnchorCredentialID)
+		require.Empty(t, entries[1].Witnesses)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		h := NewHistoryHandler(&mockOperationStore{err: fmt.Errorf("suffix[%s] not found in the store", suffix)}, ag)
+
+		rw := getHistory(h, suffix)
+		require.Equal(t, http.StatusNotFound, rw.Code)
+	})
+
+	t.Run("Transient error", func(t *testing.T) {
+		h := NewHistoryHandler(&mockOperationStore{err: orberrors.NewTransient(errors.New("injected error"))}, ag)
+
+		rw := getHistory(h, suffix)
+		require.Equal(t, http.StatusInternalServerError, rw.Code)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewHistoryHandler(&mockOperationStore{ops: ops}, ag)
+		h.marshal = func(v interface{}) ([]byte, error) {
+			return nil, errors.New("injected marshal error")
+		}
+
+		rw := getHistory(h, suffix)
+		require.Equal(t, http.StatusInternalServerError, rw.Code)
+	})
+}
+
+func getHistory(h *HistoryHandler, didOrSuffix string) *httptest.ResponseRecorder {
+	rw := httptest.NewRecorder()
+
+	req := httptest.NewRequest(http.MethodGet, historyEndpoint+"/"+didOrSuffix, nil)
+
+	h.Handler()(rw, mux.SetURLVars(req, map[string]string{suffixPathVariable: didOrSuffix}))
+
+	return rw
+}
+
+type mockOperationStore struct {
+	ops []*operation.AnchoredOperation
+	err error
+}
+
+func (m *mockOperationStore) Get(string) ([]*operation.AnchoredOperation, error) {
+	return m.ops, m.err
+}
+
+type mockAnchorGraph struct {
+	vcs map[string]*verifiable.Credential
+}
+
+func (m *mockAnchorGraph) Read(hl string) (*verifiable.Credential, error) {
+	vc, ok := m.vcs[hl]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+
+	return vc, nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/dochandler"
+
+	"github.com/trustbloc/orb/pkg/document/versionresolver"
+)
+
+const (
+	idPathVariable = "id"
+
+	versionIDParam   = "versionId"
+	versionTimeParam = "versionTime"
+)
+
+var logger = log.New("document-rest-handler")
+
+type versionResolver interface {
+	ResolveVersion(id, versionID string) (*document.ResolutionResult, error)
+	ResolveTime(id string, versionTime time.Time) (*document.ResolutionResult, error)
+}
+
+// ResolveHandler resolves DID documents. If the versionId or versionTime query parameter is specified then
+// the document is resolved at the given version/time, otherwise the latest version of the document
+// is resolved.
+type ResolveHandler struct {
+	path            string
+	latest          common.HTTPRequestHandler
+	versionResolver versionResolver
+}
+
+// NewResolveHandler returns a new DID document resolve handler.
+func NewResolveHandler(basePath string, resolver dochandler.Resolver, versionResolver versionResolver) *ResolveHandler {
+	return &ResolveHandler{
+		path:            fmt.Sprintf("%s/{%s}", basePath, idPathVariable),
+		latest:          dochandler.NewResolveHandler(resolver).Resolve,
+		versionResolver: versionResolver,
+	}
+}
+
+// Path returns the context path.
+func (h *ResolveHandler) Path() string {
+	return h.path
+}
+
+// Method returns the HTTP method.
+func (h *ResolveHandler) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the handler.
+func (h *ResolveHandler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *ResolveHandler) handle(w http.ResponseWriter, req *http.Request) {
+	versionID := req.URL.Query().Get(versionIDParam)
+	versionTime := req.URL.Query().Get(versionTimeParam)
+
+	if versionID == "" && versionTime == "" {
+		h.latest(w, req)
+
+		return
+	}
+
+	if versionID != "" && versionTime != "" {
+		common.WriteError(w, http.StatusBadRequest,
+			fmt.Errorf("only one of %s or %s may be specified", versionIDParam, versionTimeParam))
+
+		return
+	}
+
+	id := mux.Vars(req)[idPathVariable]
+
+	var (
+		result *document.ResolutionResult
+		err    error
+	)
+
+	if versionID != "" {
+		result, err = h.versionResolver.ResolveVersion(id, versionID)
+	} else {
+		t, e := time.Parse(time.RFC3339, versionTime)
+		if e != nil {
+			common.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid %s: %w", versionTimeParam, e))
+
+			return
+		}
+
+		result, err = h.versionResolver.ResolveTime(id, t)
+	}
+
+	if err != nil {
+		writeResolveError(w, id, err)
+
+		return
+	}
+
+	common.WriteResponse(w, http.StatusOK, result)
+}
+
+func writeResolveError(w http.ResponseWriter, id string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "bad request"):
+		common.WriteError(w, http.StatusBadRequest, err)
+	case errors.Is(err, versionresolver.ErrVersionNotFound), strings.Contains(err.Error(), "not found"):
+		logger.Debugf("Version of document [%s] not found: %s", id, err)
+
+		common.WriteError(w, http.StatusNotFound, errors.New("document not found"))
+	default:
+		logger.Errorf("Error resolving version of document [%s]: %s", id, err)
+
+		common.WriteError(w, http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+
+	"github.com/trustbloc/orb/pkg/document/versionresolver"
+)
+
+const (
+	basePath = "/sidetree/v1/identifiers"
+	did      = "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+)
+
+func TestNewResolveHandler(t *testing.T) {
+	h := NewResolveHandler(basePath, &mockResolver{}, &mockVersionResolver{})
+	require.NotNil(t, h)
+	require.Equal(t, basePath+"/{id}", h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+}
+
+func TestResolveHandler(t *testing.T) {
+	latest := &document.ResolutionResult{Document: document.Document{"id": did}}
+	version := &document.ResolutionResult{
+		Document:         document.Document{"id": did},
+		DocumentMetadata: document.Metadata{versionresolver.VersionIDProperty: "v1"},
+	}
+
+	t.Run("latest", func(t *testing.T) {
+		h := NewResolveHandler(basePath, &mockResolver{result: latest}, &mockVersionResolver{})
+
+		rw := resolve(h, "")
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.NotContains(t, rw.Body.String(), "versionId")
+	})
+
+	t.Run("versionId", func(t *testing.T) {
+		vr := &mockVersionResolver{result: version}
+
+		rw := resolve(NewResolveHandler(basePath, &mockResolver{}, vr), "?versionId=v1")
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Contains(t, rw.Body.String(), `"versionId":"v1"`)
+		require.Equal(t, "v1", vr.versionID)
+	})
+
+	t.Run("versionTime", func(t *testing.T) {
+		vr := &mockVersionResolver{result: version}
+
+		rw := resolve(NewResolveHandler(basePath, &mockResolver{}, vr), "?versionTime=2021-08-01T10:00:00Z")
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, time.Date(2021, 8, 1, 10, 0, 0, 0, time.UTC), vr.versionTime.UTC())
+	})
+
+	t.Run("bad request", func(t *testing.T) {
+		h := NewResolveHandler(basePath, &mockResolver{}, &mockVersionResolver{result: version})
+
+		rw := resolve(h, "?versionTime=yesterday")
+		require.Equal(t, http.StatusBadRequest, rw.Code)
+
+		rw = resolve(h, "?versionId=v1&versionTime=2021-08-01T10:00:00Z")
+		require.Equal(t, http.StatusBadRequest, rw.Code)
+	})
+
+	t.Run("resolver errors", func(t *testing.T) {
+		for _, test := range []struct {
+			err    error
+			status int
+		}{
+			{fmt.Errorf("bad request: invalid namespace"), http.StatusBadRequest},
+			{fmt.Errorf("versionId [v2]: %w", versionresolver.ErrVersionNotFound), http.StatusNotFound},
+			{errors.New("suffix not found in the store"), http.StatusNotFound},
+			{errors.New("injected error"), http.StatusInternalServerError},
+		} {
+			h := NewResolveHandler(basePath, &mockResolver{}, &mockVersionResolver{err: test.err})
+
+			rw := resolve(h, "?versionId=v2")
+			require.Equal(t, test.status, rw.Code)
+		}
+	})
+}
+
+func resolve(h *ResolveHandler, query string) *httptest.ResponseRecorder {
+	rw := httptest.NewRecorder()
+
+	req := httptest.NewRequest(http.MethodGet, basePath+"/"+did+query, nil)
+
+	h.Handler()(rw, mux.SetURLVars(req, map[string]string{idPathVariable: did}))
+
+	return rw
+}
+
+type mockResolver struct {
+	result *document.ResolutionResult
+	err    error
+}
+
+func (m *mockResolver) ResolveDocument(string) (*document.ResolutionResult, error) {
+	return m.result, m.err
+}
+
+type mockVersionResolver struct {
+	result      *document.ResolutionResult
+	err         error
+	versionID   string
+	versionTime time.Time
+}
+
+func (m *mockVersionResolver) ResolveVersion(_, versionID string) (*document.ResolutionResult, error) {
+	m.versionID = versionID
+
+	return m.result, m.err
+}
+
+func (m *mockVersionResolver) ResolveTime(_ string, versionTime time.Time) (*document.ResolutionResult, error) {
+	m.versionTime = versionTime
+
+	return m.result, m.err
+}
@@ -0,0 +1,291 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package versionresolver
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/docutil"
+	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
+	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
+	"github.com/trustbloc/sidetree-core-go/pkg/processor"
+
+	"github.com/trustbloc/orb/pkg/hashlink"
+)
+
+var logger = log.New("version-resolver")
+
+const (
+	// VersionIDProperty is the document metadata property that contains the version ID of the resolved document.
+	VersionIDProperty = "versionId"
+	// PreviousVersionIDProperty is the document metadata property that contains the ID of the previous version.
+	PreviousVersionIDProperty = "previousVersionId"
+	// NextVersionIDProperty is the document metadata property that contains the ID of the next version.
+	NextVersionIDProperty = "nextVersionId"
+
+	// sha2-256 is always used for version IDs so that the ID of an operation doesn't change across
+	// protocol versions.
+	versionIDMultihashCode = 18
+)
+
+// ErrVersionNotFound is returned when the requested version of the document is not found.
+var ErrVersionNotFound = errors.New("version not found")
+
+type operationStore interface {
+	Get(suffix string) ([]*operation.AnchoredOperation, error)
+}
+
+// Resolver resolves a DID document as it was at a specific version (operation) or at a given point in time.
+type Resolver struct {
+	namespace string
+	pc        protocol.Client
+	opStore   operationStore
+	hl        *hashlink.HashLink
+}
+
+// New returns a new version resolver.
+func New(namespace string, pc protocol.Client, opStore operationStore) *Resolver {
+	return &Resolver{
+		namespace: namespace,
+		pc:        pc,
+		opStore:   opStore,
+		hl:        hashlink.New(),
+	}
+}
+
+// ResolveVersion resolves the document with the given ID as it was after the operation with the given version ID
+// was applied. The version ID is either the version ID of an operation (as returned in the document metadata) or
+// the hashlink (or CID) of the anchor that included the operation. If more than one operation for the document was
+// included in the anchor then the last one is used.
+func (r *Resolver) ResolveVersion(id, versionID string) (*document.ResolutionResult, error) {
+	return r.resolve(id, func(ops []*version) (int, error) {
+		anchorHash := r.getAnchorHash(versionID)
+
+		idx := -1
+
+		for i, v := range ops {
+			if v.id == versionID || v.op.CanonicalReference == anchorHash {
+				idx = i
+			}
+		}
+
+		if idx < 0 {
+			return 0, fmt.Errorf("versionId [%s]: %w", versionID, ErrVersionNotFound)
+		}
+
+		return idx, nil
+	})
+}
+
+// ResolveTime resolves the document with the given ID as it was at the given time. The transaction time of an
+// operation (i.e. the issuance date of the anchor credential) is used to determine which operations were
+// in effect at the given time.
+func (r *Resolver) ResolveTime(id string, versionTime time.Time) (*document.ResolutionResult, error) {
+	return r.resolve(id, func(ops []*version) (int, error) {
+		idx := -1
+
+		for i, v := range ops {
+			if v.op.TransactionTime > uint64(versionTime.Unix()) {
+				break
+			}
+
+			idx = i
+		}
+
+		if idx < 0 {
+			return 0, fmt.Errorf("versionTime [%s]: %w", versionTime.Format(time.RFC3339), ErrVersionNotFound)
+		}
+
+		return idx, nil
+	})
+}
+
+type version struct {
+	id string
+	op *operation.AnchoredOperation
+}
+
+func (r *Resolver) resolve(id string,
+	selectVersion func(ops []*version) (int, error)) (*document.ResolutionResult, error) {
+	suffix, err := r.getSuffix(id)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := r.getVersions(suffix)
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := selectVersion(versions)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("Resolving suffix [%s] at version [%s] (%d of %d)", suffix, versions[idx].id, idx+1, len(versions))
+
+	ops := make([]*operation.AnchoredOperation, idx+1)
+
+	for i := 0; i <= idx; i++ {
+		ops[i] = versions[i].op
+	}
+
+	rm, err := processor.New(r.namespace, &opStoreSnapshot{ops: ops}, r.pc).Resolve(suffix)
+	if err != nil {
+		return nil, fmt.Errorf("resolve suffix [%s] at version [%s]: %w", suffix, versions[idx].id, err)
+	}
+
+	result, err := r.transform(id, suffix, rm)
+	if err != nil {
+		return nil, err
+	}
+
+	result.DocumentMetadata[VersionIDProperty] = versions[idx].id
+
+	if idx > 0 {
+		result.DocumentMetadata[PreviousVersionIDProperty] = versions[idx-1].id
+	}
+
+	if idx < len(versions)-1 {
+		result.DocumentMetadata[NextVersionIDProperty] = versions[idx+1].id
+	}
+
+	return result, nil
+}
+
+// getVersions returns the anchored operations for the given suffix (sorted by anchoring time) along
+// with their version IDs.
+func (r *Resolver) getVersions(suffix string) ([]*version, error) {
+	ops, err := r.opStore.Get(suffix)
+	if err != nil {
+		return nil, err
+	}
+
+	SortOperations(ops)
+
+	versions := make([]*version, len(ops))
+
+	for i, op := range ops {
+		versionID, err := GetVersionID(op)
+		if err != nil {
+			return nil, err
+		}
+
+		versions[i] = &version{id: versionID, op: op}
+	}
+
+	return versions, nil
+}
+
+// transform transforms the internal resolution model into an external document in the same way as
+// the Sidetree document handler does for the latest version of the document.
+func (r *Resolver) transform(id, suffix string, rm *protocol.ResolutionModel) (*document.ResolutionResult, error) {
+	pv, err := r.pc.Current()
+	if err != nil {
+		return nil, err
+	}
+
+	ti := make(protocol.TransformationInfo)
+	ti[document.IDProperty] = id
+	ti[document.PublishedProperty] = true
+
+	canonicalRef := ""
+	if rm.CanonicalReference != "" {
+		canonicalRef = docutil.NamespaceDelimiter + rm.CanonicalReference
+	}
+
+	canonicalID := r.namespace + canonicalRef + docutil.NamespaceDelimiter + suffix
+
+	ti[document.CanonicalIDProperty] = canonicalID
+
+	equivalentIDs := []string{canonicalID}
+
+	for _, eqRef := range rm.EquivalentReferences {
+		equivalentIDs = append(equivalentIDs,
+			r.namespace+docutil.NamespaceDelimiter+eqRef+docutil.NamespaceDelimiter+suffix)
+	}
+
+	ti[document.EquivalentIDProperty] = equivalentIDs
+
+	result, err := pv.DocumentTransformer().TransformDocument(rm, ti)
+	if err != nil {
+		return nil, fmt.Errorf("transform document: %w", err)
+	}
+
+	if result.DocumentMetadata == nil {
+		result.DocumentMetadata = make(document.Metadata)
+	}
+
+	return result, nil
+}
+
+func (r *Resolver) getSuffix(id string) (string, error) {
+	if !strings.HasPrefix(id, r.namespace+docutil.NamespaceDelimiter) {
+		return "", fmt.Errorf("bad request: did must start with configured namespace[%s]", r.namespace)
+	}
+
+	parts := strings.Split(id, docutil.NamespaceDelimiter)
+
+	return parts[len(parts)-1], nil
+}
+
+// getAnchorHash returns the resource hash of the given hashlink. If the given value is not a hashlink
+// then the value itself is returned.
+func (r *Resolver) getAnchorHash(versionID string) string {
+	if !strings.HasPrefix(versionID, hashlink.HLPrefix) {
+		return versionID
+	}
+
+	hlInfo, err := r.hl.ParseHashLink(versionID)
+	if err != nil {
+		logger.Debugf("Invalid hashlink for versionId [%s]: %s", versionID, err)
+
+		return versionID
+	}
+
+	return hlInfo.ResourceHash
+}
+
+// SortOperations sorts the given operations by anchoring time.
+func SortOperations(ops []*operation.AnchoredOperation) {
+	sort.SliceStable(ops, func(i, j int) bool {
+		if ops[i].TransactionTime == ops[j].TransactionTime {
+			return ops[i].TransactionNumber < ops[j].TransactionNumber
+		}
+
+		return ops[i].TransactionTime < ops[j].TransactionTime
+	})
+}
+
+// GetVersionID returns the version ID of the given operation, which is the encoded multihash of
+// the operation request.
+func GetVersionID(op *operation.AnchoredOperation) (string, error) {
+	mh, err := hashing.ComputeMultihash(versionIDMultihashCode, op.OperationBuffer)
+	if err != nil {
+		return "", fmt.Errorf("compute version ID: %w", err)
+	}
+
+	return encoder.EncodeToString(mh), nil
+}
+
+// opStoreSnapshot is an operation store that contains only the operations up to (and including)
+// the requested version.
+type opStoreSnapshot struct {
+	ops []*operation.AnchoredOperation
+}
+
+func (s *opStoreSnapshot) Get(string) ([]*operation.AnchoredOperation, error) {
+	return s.ops, nil
+}
@@ -0,0 +1,244 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package versionresolver
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/patch"
+	"github.com/trustbloc/sidetree-core-go/pkg/util/ecsigner"
+	"github.com/trustbloc/sidetree-core-go/pkg/util/pubkey"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/client"
+
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+)
+
+const (
+	namespace = "did:orb"
+
+	anchorCID1 = "uEiD1X_FvZvQzYCZrldtvj-wB12AxBUMGrkpLOAWY9s_RFA"
+	anchorCID2 = "uEiAsOkJJ13BwBYZJ29gi3K95V1hvzkKM-yyoi5R0HtqLBw"
+	anchorCID3 = "uEiD0bdKKVJnY7-8Lj7juHsHFpeQHyTgXQdV2uo3rT1nsPw"
+
+	multihashCode = 18
+)
+
+func TestResolver(t *testing.T) {
+	pc, err := orbmocks.NewMockProtocolClientProvider().WithAllowedOrigins([]string{"*"}).ForNamespace(namespace)
+	require.NoError(t, err)
+
+	ops, suffix := newOperations(t, pc)
+
+	time1 := time.Unix(int64(ops[0].TransactionTime), 0)
+	time2 := time.Unix(int64(ops[1].TransactionTime), 0)
+	time3 := time.Unix(int64(ops[2].TransactionTime), 0)
+
+	versionID1, err := GetVersionID(ops[0])
+	require.NoError(t, err)
+
+	versionID2, err := GetVersionID(ops[1])
+	require.NoError(t, err)
+
+	versionID3, err := GetVersionID(ops[2])
+	require.NoError(t, err)
+
+	did := fmt.Sprintf("%s:%s:%s", namespace, anchorCID3, suffix)
+
+	// Store the operations out of order to ensure that the resolver sorts them.
+	r := New(namespace, pc, &mockOperationStore{ops: []*operation.AnchoredOperation{ops[2], ops[0], ops[1]}})
+
+	t.Run("ResolveVersion", func(t *testing.T) {
+		t.Run("first version", func(t *testing.T) {
+			result, err := r.ResolveVersion(did, versionID1)
+			require.NoError(t, err)
+			require.Len(t, getServices(result), 1)
+			require.Equal(t, versionID1, result.DocumentMetadata[VersionIDProperty])
+			require.Nil(t, result.DocumentMetadata[PreviousVersionIDProperty])
+			require.Equal(t, versionID2, result.DocumentMetadata[NextVersionIDProperty])
+			require.Equal(t, fmt.Sprintf("%s:%s:%s", namespace, anchorCID1, suffix),
+				result.DocumentMetadata[document.CanonicalIDProperty])
+		})
+
+		t.Run("middle version", func(t *testing.T) {
+			result, err := r.ResolveVersion(did, versionID2)
+			require.NoError(t, err)
+			require.Len(t, getServices(result), 2)
+			require.Equal(t, versionID2, result.DocumentMetadata[VersionIDProperty])
+			require.Equal(t, versionID1, result.DocumentMetadata[PreviousVersionIDProperty])
+			require.Equal(t, versionID3, result.DocumentMetadata[NextVersionIDProperty])
+		})
+
+		t.Run("anchor hashlink", func(t *testing.T) {
+			result, err := r.ResolveVersion(did, "hl:"+anchorCID3)
+			require.NoError(t, err)
+			require.Len(t, getServices(result), 3)
+			require.Equal(t, versionID3, result.DocumentMetadata[VersionIDProperty])
+			require.Equal(t, versionID2, result.DocumentMetadata[PreviousVersionIDProperty])
+			require.Nil(t, result.DocumentMetadata[NextVersionIDProperty])
+		})
+
+		t.Run("anchor CID", func(t *testing.T) {
+			result, err := r.ResolveVersion(did, anchorCID2)
+			require.NoError(t, err)
+			require.Equal(t, versionID2, result.DocumentMetadata[VersionIDProperty])
+		})
+
+		t.Run("version not found", func(t *testing.T) {
+			_, err := r.ResolveVersion(did, "unknown")
+			require.Error(t, err)
+			require.True(t, errors.Is(err, ErrVersionNotFound))
+		})
+	})
+
+	t.Run("ResolveTime", func(t *testing.T) {
+		result, err := r.ResolveTime(did, time1.Add(time.Second))
+		require.NoError(t, err)
+		require.Equal(t, versionID1, result.DocumentMetadata[VersionIDProperty])
+
+		result, err = r.ResolveTime(did, time2)
+		require.NoError(t, err)
+		require.Equal(t, versionID2, result.DocumentMetadata[VersionIDProperty])
+
+		result, err = r.ResolveTime(did, time3.Add(time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, versionID3, result.DocumentMetadata[VersionIDProperty])
+
+		_, err = r.ResolveTime(did, time1.Add(-time.Second))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrVersionNotFound))
+	})
+
+	t.Run("invalid namespace", func(t *testing.T) {
+		_, err := r.ResolveVersion("did:other:"+suffix, versionID1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "bad request")
+	})
+
+	t.Run("operation store error", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		_, err := New(namespace, pc, &mockOperationStore{err: errExpected}).ResolveVersion(did, versionID1)
+		require.True(t, errors.Is(err, errExpected))
+	})
+}
+
+func getServices(result *document.ResolutionResult) []document.Service {
+	services, ok := result.Document[document.ServiceProperty].([]document.Service)
+	if !ok {
+		return nil
+	}
+
+	return services
+}
+
+// newOperations returns a create operation and two update operations (each of which adds a service),
+// anchored in three different anchors.
+func newOperations(t *testing.T, pc protocol.Client) ([]*operation.AnchoredOperation, string) {
+	t.Helper()
+
+	pv, err := pc.Current()
+	require.NoError(t, err)
+
+	_, recoveryCommitment := newKey(t)
+	updateKey, updateCommitment := newKey(t)
+
+	createReq, err := client.NewCreateRequest(&client.CreateRequestInfo{
+		OpaqueDocument:     `{"service":[{"id":"svc1","type":"type","serviceEndpoint":"https://example.com/svc1"}]}`,
+		RecoveryCommitment: recoveryCommitment,
+		UpdateCommitment:   updateCommitment,
+		AnchorOrigin:       "https://orb.domain1.com",
+		MultihashCode:      multihashCode,
+	})
+	require.NoError(t, err)
+
+	createOp, err := pv.OperationParser().Parse(namespace, createReq)
+	require.NoError(t, err)
+
+	now := time.Now()
+
+	ops := []*operation.AnchoredOperation{
+		newAnchoredOperation(operation.TypeCreate, createOp.UniqueSuffix, createReq, now.Add(-2*time.Hour), anchorCID1),
+	}
+
+	for i, anchorCID := range []string{anchorCID2, anchorCID3} {
+		nextUpdateKey, nextUpdateCommitment := newKey(t)
+
+		updateJWK, err := pubkey.GetPublicKeyJWK(&updateKey.PublicKey)
+		require.NoError(t, err)
+
+		revealValue, err := commitment.GetRevealValue(updateJWK, multihashCode)
+		require.NoError(t, err)
+
+		p, err := patch.NewAddServiceEndpointsPatch(fmt.Sprintf(
+			`[{"id":"svc%d","type":"type","serviceEndpoint":"https://example.com/svc%d"}]`, i+2, i+2))
+		require.NoError(t, err)
+
+		updateReq, err := client.NewUpdateRequest(&client.UpdateRequestInfo{
+			DidSuffix:        createOp.UniqueSuffix,
+			Patches:          []patch.Patch{p},
+			UpdateCommitment: nextUpdateCommitment,
+			UpdateKey:        updateJWK,
+			MultihashCode:    multihashCode,
+			Signer:           ecsigner.New(updateKey, "ES256", ""),
+			RevealValue:      revealValue,
+		})
+		require.NoError(t, err)
+
+		ops = append(ops, newAnchoredOperation(operation.TypeUpdate, createOp.UniqueSuffix, updateReq,
+			now.Add(time.Duration(i-1)*time.Hour), anchorCID))
+
+		updateKey = nextUpdateKey
+	}
+
+	return ops, createOp.UniqueSuffix
+}
+
+func newAnchoredOperation(opType operation.Type, suffix string, buf []byte, txnTime time.Time,
+	anchorCID string) *operation.AnchoredOperation {
+	return &operation.AnchoredOperation{
+		Type:               opType,
+		UniqueSuffix:       suffix,
+		OperationBuffer:    buf,
+		TransactionTime:    uint64(txnTime.Unix()),
+		CanonicalReference: anchorCID,
+	}
+}
+
+func newKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwk, err := pubkey.GetPublicKeyJWK(&key.PublicKey)
+	require.NoError(t, err)
+
+	c, err := commitment.GetCommitment(jwk, multihashCode)
+	require.NoError(t, err)
+
+	return key, c
+}
+
+type mockOperationStore struct {
+	ops []*operation.AnchoredOperation
+	err error
+}
+
+func (m *mockOperationStore) Get(string) ([]*operation.AnchoredOperation, error) {
+	return m.ops, m.err
+}
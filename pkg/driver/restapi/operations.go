@@ -6,17 +6,54 @@ SPDX-License-Identifier: Apache-2.0
 package restapi
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"mime"
 	"net/http"
+	"net/url"
+	"strings"
 
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
 	"github.com/trustbloc/edge-core/pkg/log"
 	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
 )
 
 const (
-	resolveDIDEndpoint = "/resolveDID"
-	didLDJson          = "application/did+ld+json"
+	resolveDIDEndpoint  = "/resolveDID"
+	identifiersEndpoint = "/1.0/identifiers/{" + didPathVariable + "}"
+	didPathVariable     = "did"
+
+	didLDJson           = "application/did+ld+json"
+	didJSON             = "application/did+json"
+	ldJSON              = "application/ld+json"
+	resolutionProfile   = "https://w3id.org/did-resolution"
+	resolutionResultCtx = "https://w3id.org/did-resolution/v1"
+	resolutionLDJSON    = ldJSON + `;profile="` + resolutionProfile + `"`
+
+	didMethodPrefix = "did:orb:"
+)
+
+// Resolution options (query parameters) defined by the DID Resolution specification that request a specific
+// version of the DID document. The options are passed to the resolution endpoint of the Orb domain. The VDR
+// doesn't support resolution options (they would be silently ignored and the latest version returned), so if
+// the domain isn't configured then a request that includes any of these options is rejected.
+const (
+	versionIDOpt   = "versionId"
+	versionTimeOpt = "versionTime"
+)
+
+// Resolution error codes as defined by the DID Resolution specification.
+const (
+	errInvalidDID                 = "invalidDid"
+	errInvalidOptions             = "invalidOptions"
+	errNotFound                   = "notFound"
+	errMethodNotSupported         = "methodNotSupported"
+	errRepresentationNotSupported = "representationNotSupported"
+	errDeactivated                = "deactivated"
+	errInternal                   = "internalError"
 )
 
 var logger = log.New("driver")
@@ -30,17 +67,30 @@ type Handler interface {
 
 // Operation defines handlers.
 type Operation struct {
-	orbVDR vdr.VDR
+	orbVDR   vdr.VDR
+	resolver *orbResolver
 }
 
 // Config defines configuration for driver operations.
 type Config struct {
 	OrbVDR vdr.VDR
+
+	// Domain is the Orb domain (e.g. https://orb.domain1.com) whose resolution endpoint is used to resolve
+	// DIDs with the DID Resolution HTTP(S) binding. If not set then the DIDs are resolved with OrbVDR.
+	Domain     string
+	HTTPClient httpClient
+	AuthToken  string
 }
 
 // New returns driver operation instance.
 func New(config *Config) *Operation {
-	return &Operation{orbVDR: config.OrbVDR}
+	op := &Operation{orbVDR: config.OrbVDR}
+
+	if config.Domain != "" {
+		op.resolver = newOrbResolver(config.Domain, config.HTTPClient, config.AuthToken)
+	}
+
+	return op
 }
 
 func (o *Operation) resolveDIDHandler(rw http.ResponseWriter, req *http.Request) {
@@ -76,6 +126,154 @@ func (o *Operation) resolveDIDHandler(rw http.ResponseWriter, req *http.Request)
 	}
 }
 
+// resolutionResult is the DID resolution result as defined by the DID Resolution HTTP(S) binding.
+type resolutionResult struct {
+	Context            string              `json:"@context"`
+	DIDDocument        json.RawMessage     `json:"didDocument,omitempty"`
+	ResolutionMetadata *resolutionMetadata `json:"didResolutionMetadata"`
+	DocumentMetadata   interface{}         `json:"didDocumentMetadata"`
+}
+
+type resolutionMetadata struct {
+	ContentType string `json:"contentType,omitempty"`
+	Error       string `json:"error,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+// resolveIdentifierHandler implements the DID Resolution HTTP(S) binding (GET /1.0/identifiers/{did}).
+// If the Accept header requests a DID document representation (application/did+ld+json or application/did+json)
+// then only the DID document is returned, otherwise the full resolution result is returned.
+func (o *Operation) resolveIdentifierHandler(rw http.ResponseWriter, req *http.Request) {
+	contentType, ok := negotiate(req.Header.Get("Accept"))
+	if !ok {
+		o.writeResolutionError(rw, resolutionLDJSON, http.StatusNotAcceptable, errRepresentationNotSupported,
+			fmt.Sprintf("unsupported representation: %s", req.Header.Get("Accept")))
+
+		return
+	}
+
+	didID := mux.Vars(req)[didPathVariable]
+
+	if !strings.HasPrefix(didID, "did:") || len(strings.SplitN(didID, ":", 3)) < 3 { //nolint:gomnd
+		o.writeResolutionError(rw, contentType, http.StatusBadRequest, errInvalidDID,
+			fmt.Sprintf("invalid DID: %s", didID))
+
+		return
+	}
+
+	if !strings.HasPrefix(didID, didMethodPrefix) {
+		o.writeResolutionError(rw, contentType, http.StatusNotImplemented, errMethodNotSupported,
+			fmt.Sprintf("DID method not supported: %s", didID))
+
+		return
+	}
+
+	options := getResolutionOptions(req)
+
+	if o.resolver == nil && len(options) > 0 {
+		o.writeResolutionError(rw, contentType, http.StatusBadRequest, errInvalidOptions,
+			fmt.Sprintf("resolution options not supported without a domain: %s", options.Encode()))
+
+		return
+	}
+
+	docResolution, rawMetadata, err := o.read(didID, options)
+	if err != nil {
+		if errors.Is(err, vdr.ErrNotFound) {
+			o.writeResolutionError(rw, contentType, http.StatusNotFound, errNotFound, err.Error())
+
+			return
+		}
+
+		if errors.Is(err, errBadRequest) {
+			o.writeResolutionError(rw, contentType, http.StatusBadRequest, errInvalidOptions, err.Error())
+
+			return
+		}
+
+		o.writeResolutionError(rw, contentType, http.StatusInternalServerError, errInternal,
+			fmt.Sprintf("failed to resolve did: %s", err.Error()))
+
+		return
+	}
+
+	status := http.StatusOK
+	resMetadata := &resolutionMetadata{ContentType: didLDJson}
+
+	if docResolution.DocumentMetadata != nil && docResolution.DocumentMetadata.Deactivated {
+		status = http.StatusGone
+		resMetadata.Error = errDeactivated
+	}
+
+	docBytes, err := docResolution.DIDDocument.JSONBytes()
+	if err != nil {
+		o.writeResolutionError(rw, contentType, http.StatusInternalServerError, errInternal,
+			fmt.Sprintf("failed to marshal DID document: %s", err.Error()))
+
+		return
+	}
+
+	if contentType != resolutionLDJSON {
+		o.writeResponse(rw, contentType, status, docBytes)
+
+		return
+	}
+
+	o.writeResolutionResult(rw, status, &resolutionResult{
+		Context:            resolutionResultCtx,
+		DIDDocument:        docBytes,
+		ResolutionMetadata: resMetadata,
+		DocumentMetadata:   getDocumentMetadata(docResolution, rawMetadata),
+	})
+}
+
+// read resolves the DID. The raw document metadata is also returned (if available) since it may contain fields
+// that aren't supported by did.DocumentMetadata (e.g. the versionId of a versioned resolution).
+func (o *Operation) read(didID string, options url.Values) (*did.DocResolution, json.RawMessage, error) {
+	if o.resolver != nil {
+		return o.resolver.resolve(didID, options)
+	}
+
+	docResolution, err := o.orbVDR.Read(didID)
+
+	return docResolution, nil, err
+}
+
+func (o *Operation) writeResolutionError(rw http.ResponseWriter, contentType string, status int, code, msg string) {
+	if contentType != resolutionLDJSON {
+		o.writeErrorResponse(rw, status, msg)
+
+		return
+	}
+
+	o.writeResolutionResult(rw, status, &resolutionResult{
+		Context:            resolutionResultCtx,
+		ResolutionMetadata: &resolutionMetadata{Error: code, Message: msg},
+		DocumentMetadata:   struct{}{},
+	})
+}
+
+func (o *Operation) writeResolutionResult(rw http.ResponseWriter, status int, result *resolutionResult) {
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		o.writeErrorResponse(rw, http.StatusInternalServerError,
+			fmt.Sprintf("failed to marshal resolution result: %s", err.Error()))
+
+		return
+	}
+
+	o.writeResponse(rw, resolutionLDJSON, status, resultBytes)
+}
+
+func (o *Operation) writeResponse(rw http.ResponseWriter, contentType string, status int, body []byte) {
+	rw.Header().Set("Content-type", contentType)
+	rw.WriteHeader(status)
+
+	if _, err := rw.Write(body); err != nil {
+		logger.Errorf("Unable to send response, %s", err)
+	}
+}
+
 // writeErrorResponse writes interface value to response.
 func (o *Operation) writeErrorResponse(rw http.ResponseWriter, status int, msg string) {
 	rw.WriteHeader(status)
@@ -89,7 +287,61 @@ func (o *Operation) writeErrorResponse(rw http.ResponseWriter, status int, msg s
 func (o *Operation) GetRESTHandlers() []common.HTTPHandler {
 	return []common.HTTPHandler{
 		newHTTPHandler(resolveDIDEndpoint, http.MethodGet, o.resolveDIDHandler),
+		newHTTPHandler(identifiersEndpoint, http.MethodGet, o.resolveIdentifierHandler),
+	}
+}
+
+// negotiate returns the content type of the response for the given Accept header. False is returned if none of
+// the accepted media types are supported.
+func negotiate(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return resolutionLDJSON, true
+	}
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+
+		switch mediaType {
+		case didLDJson, didJSON:
+			return mediaType, true
+		case ldJSON:
+			if params["profile"] == "" || params["profile"] == resolutionProfile {
+				return resolutionLDJSON, true
+			}
+		case "application/json", "application/*", "*/*":
+			return resolutionLDJSON, true
+		}
 	}
+
+	return "", false
+}
+
+// getResolutionOptions returns the resolution options in the query string.
+func getResolutionOptions(req *http.Request) url.Values {
+	options := url.Values{}
+
+	for _, name := range []string{versionIDOpt, versionTimeOpt} {
+		if value := req.URL.Query().Get(name); value != "" {
+			options.Set(name, value)
+		}
+	}
+
+	return options
+}
+
+func getDocumentMetadata(docResolution *did.DocResolution, rawMetadata json.RawMessage) interface{} {
+	if len(rawMetadata) > 0 {
+		return rawMetadata
+	}
+
+	if docResolution.DocumentMetadata == nil {
+		return struct{}{}
+	}
+
+	return docResolution.DocumentMetadata
 }
 
 // newHTTPHandler returns instance of HTTPHandler which can be used to handle http requests.
@@ -7,9 +7,12 @@ package restapi_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"sync"
 	"testing"
 
 	"github.com/gorilla/mux"
@@ -23,7 +26,12 @@ import (
 )
 
 const (
-	resolveDIDEndpoint = "/resolveDID"
+	resolveDIDEndpoint  = "/resolveDID"
+	identifiersEndpoint = "/1.0/identifiers/{did}"
+
+	orbDID = "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+
+	resolutionLDJSON = `application/ld+json;profile="https://w3id.org/did-resolution"`
 )
 
 func TestDIDResolve(t *testing.T) {
@@ -69,6 +77,306 @@ func TestDIDResolve(t *testing.T) {
 	})
 }
 
+func TestResolveIdentifier(t *testing.T) {
+	newOperation := func(readFunc func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error),
+	) *restapi.Operation {
+		return restapi.New(&restapi.Config{OrbVDR: &mockvdr.MockVDR{ReadFunc: readFunc}})
+	}
+
+	resolve := func(t *testing.T, op *restapi.Operation, didID, query, accept string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		handler := getHandler(t, op, identifiersEndpoint)
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/1.0/identifiers/"+didID+query, nil)
+		require.NoError(t, err)
+
+		if accept != "" {
+			httpReq.Header.Set("Accept", accept)
+		}
+
+		rr := httptest.NewRecorder()
+
+		handler.Handler()(rr, mux.SetURLVars(httpReq, map[string]string{"did": didID}))
+
+		return rr
+	}
+
+	successFunc := func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+		return &did.DocResolution{
+			DIDDocument:      &did.Doc{Context: []string{did.ContextV1}, ID: didID},
+			DocumentMetadata: &did.DocumentMetadata{CanonicalID: didID},
+		}, nil
+	}
+
+	t.Run("resolution result", func(t *testing.T) {
+		for _, accept := range []string{"", resolutionLDJSON, "application/ld+json", "*/*"} {
+			rr := resolve(t, newOperation(successFunc), orbDID, "", accept)
+
+			require.Equal(t, http.StatusOK, rr.Code)
+			require.Equal(t, resolutionLDJSON, rr.Header().Get("Content-type"))
+
+			result := &resolutionResult{}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), result))
+			require.Equal(t, "https://w3id.org/did-resolution/v1", result.Context)
+			require.Equal(t, orbDID, result.DIDDocument["id"])
+			require.Equal(t, "application/did+ld+json", result.ResolutionMetadata["contentType"])
+			require.Empty(t, result.ResolutionMetadata["error"])
+			require.Equal(t, orbDID, result.DocumentMetadata["canonicalId"])
+		}
+	})
+
+	t.Run("DID document", func(t *testing.T) {
+		for _, accept := range []string{"application/did+ld+json", "text/html, application/did+json"} {
+			rr := resolve(t, newOperation(successFunc), orbDID, "", accept)
+
+			require.Equal(t, http.StatusOK, rr.Code)
+			require.Contains(t, accept, rr.Header().Get("Content-type"))
+
+			doc, err := did.ParseDocument(rr.Body.Bytes())
+			require.NoError(t, err)
+			require.Equal(t, orbDID, doc.ID)
+		}
+	})
+
+	t.Run("resolution options without domain", func(t *testing.T) {
+		op := newOperation(func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+			return nil, fmt.Errorf("should not be called")
+		})
+
+		for _, query := range []string{"?versionId=abc", "?versionTime=2021-08-01T10:00:00Z"} {
+			rr := resolve(t, op, orbDID, query, "")
+			require.Equal(t, http.StatusBadRequest, rr.Code)
+
+			result := &resolutionResult{}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), result))
+			require.Equal(t, "invalidOptions", result.ResolutionMetadata["error"])
+		}
+	})
+
+	t.Run("deactivated", func(t *testing.T) {
+		op := newOperation(func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+			return &did.DocResolution{
+				DIDDocument:      &did.Doc{ID: didID},
+				DocumentMetadata: &did.DocumentMetadata{Deactivated: true},
+			}, nil
+		})
+
+		rr := resolve(t, op, orbDID, "", "")
+		require.Equal(t, http.StatusGone, rr.Code)
+
+		result := &resolutionResult{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), result))
+		require.Equal(t, "deactivated", result.ResolutionMetadata["error"])
+		require.Equal(t, true, result.DocumentMetadata["deactivated"])
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, test := range []struct {
+			name   string
+			did    string
+			accept string
+			err    error
+			status int
+			code   string
+		}{
+			{"invalid DID", "orb:123", "", nil, http.StatusBadRequest, "invalidDid"},
+			{"method not supported", "did:web:example.com", "", nil, http.StatusNotImplemented, "methodNotSupported"},
+			{"not found", orbDID, "", fmt.Errorf("resolve: %w", vdrapi.ErrNotFound), http.StatusNotFound, "notFound"},
+			{"internal error", orbDID, "", fmt.Errorf("injected error"), http.StatusInternalServerError, "internalError"},
+			{
+				"representation not supported", orbDID, "text/html", nil, http.StatusNotAcceptable,
+				"representationNotSupported",
+			},
+		} {
+			t.Run(test.name, func(t *testing.T) {
+				readErr := test.err
+
+				op := newOperation(func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+					if readErr != nil {
+						return nil, readErr
+					}
+
+					return successFunc(didID)
+				})
+
+				rr := resolve(t, op, test.did, "", test.accept)
+				require.Equal(t, test.status, rr.Code)
+				require.Equal(t, resolutionLDJSON, rr.Header().Get("Content-type"))
+
+				result := &resolutionResult{}
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), result))
+				require.Equal(t, test.code, result.ResolutionMetadata["error"])
+				require.Nil(t, result.DIDDocument)
+			})
+		}
+	})
+
+	t.Run("error with DID document representation", func(t *testing.T) {
+		op := newOperation(func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+			return nil, vdrapi.ErrNotFound
+		})
+
+		rr := resolve(t, op, orbDID, "", "application/did+ld+json")
+		require.Equal(t, http.StatusNotFound, rr.Code)
+		require.Contains(t, rr.Body.String(), vdrapi.ErrNotFound.Error())
+	})
+}
+
+func TestResolveIdentifier_Domain(t *testing.T) {
+	const (
+		unknownDID = "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3B"
+		authToken  = "READ_TOKEN"
+	)
+
+	var (
+		mutex         sync.Mutex
+		wellKnownReqs int
+		query         url.Values
+		authHeader    string
+	)
+
+	var srv *httptest.Server
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		authHeader = r.Header.Get("Authorization")
+
+		if r.URL.Path == "/.well-known/did-orb" {
+			wellKnownReqs++
+
+			_, err := fmt.Fprintf(w, `{"resolutionEndpoint":"%s/sidetree/v1/identifiers",`+
+				`"operationEndpoint":"%s/sidetree/v1/operations"}`, srv.URL, srv.URL)
+			require.NoError(t, err)
+
+			return
+		}
+
+		query = r.URL.Query()
+
+		if r.URL.Path != "/sidetree/v1/identifiers/"+orbDID {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		switch query.Get("versionId") {
+		case "":
+		case "unknown":
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		case "invalid":
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		w.Header().Set("Content-type", "application/did+ld+json")
+
+		_, err := fmt.Fprintf(w, `{"@context":"https://w3id.org/did-resolution/v1",`+
+			`"didDocument":{"@context":["https://www.w3.org/ns/did/v1"],"id":"%s"},`+
+			`"didDocumentMetadata":{"canonicalId":"%s","versionId":"%s"}}`, orbDID, orbDID, query.Get("versionId"))
+		require.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	op := restapi.New(&restapi.Config{
+		OrbVDR: &mockvdr.MockVDR{
+			ReadFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				return nil, fmt.Errorf("should not be called")
+			},
+		},
+		Domain:     srv.URL,
+		HTTPClient: srv.Client(),
+		AuthToken:  authToken,
+	})
+
+	resolve := func(t *testing.T, didID, query string) *resolutionResult {
+		t.Helper()
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/1.0/identifiers/"+didID+query, nil)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+
+		getHandler(t, op, identifiersEndpoint).Handler()(rr, mux.SetURLVars(httpReq, map[string]string{"did": didID}))
+
+		result := &resolutionResult{Status: rr.Code}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), result))
+
+		return result
+	}
+
+	t.Run("success", func(t *testing.T) {
+		result := resolve(t, orbDID, "")
+		require.Equal(t, http.StatusOK, result.Status)
+		require.Equal(t, orbDID, result.DIDDocument["id"])
+		require.Equal(t, orbDID, result.DocumentMetadata["canonicalId"])
+		require.Equal(t, "Bearer "+authToken, authHeader)
+		require.Empty(t, query)
+	})
+
+	t.Run("version ID", func(t *testing.T) {
+		result := resolve(t, orbDID, "?versionId=abc")
+		require.Equal(t, http.StatusOK, result.Status)
+		require.Equal(t, "abc", result.DocumentMetadata["versionId"])
+		require.Equal(t, "abc", query.Get("versionId"))
+	})
+
+	t.Run("version time", func(t *testing.T) {
+		result := resolve(t, orbDID, "?versionTime=2021-08-01T10:00:00Z")
+		require.Equal(t, http.StatusOK, result.Status)
+		require.Equal(t, "2021-08-01T10:00:00Z", query.Get("versionTime"))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		result := resolve(t, unknownDID, "")
+		require.Equal(t, http.StatusNotFound, result.Status)
+		require.Equal(t, "notFound", result.ResolutionMetadata["error"])
+
+		result = resolve(t, orbDID, "?versionId=unknown")
+		require.Equal(t, http.StatusNotFound, result.Status)
+		require.Equal(t, "notFound", result.ResolutionMetadata["error"])
+	})
+
+	t.Run("invalid option", func(t *testing.T) {
+		result := resolve(t, orbDID, "?versionId=invalid")
+		require.Equal(t, http.StatusBadRequest, result.Status)
+		require.Equal(t, "invalidOptions", result.ResolutionMetadata["error"])
+		require.Nil(t, result.DIDDocument)
+	})
+
+	// The resolution endpoint is only discovered once.
+	require.Equal(t, 1, wellKnownReqs)
+
+	t.Run("discovery error", func(t *testing.T) {
+		op := restapi.New(&restapi.Config{Domain: srv.URL + "/invalid", HTTPClient: srv.Client()})
+
+		httpReq, err := http.NewRequest(http.MethodGet, "/1.0/identifiers/"+orbDID, nil)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+
+		getHandler(t, op, identifiersEndpoint).Handler()(rr, mux.SetURLVars(httpReq, map[string]string{"did": orbDID}))
+
+		result := &resolutionResult{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), result))
+		require.Equal(t, http.StatusInternalServerError, rr.Code)
+		require.Equal(t, "internalError", result.ResolutionMetadata["error"])
+	})
+}
+
+type resolutionResult struct {
+	Status             int                    `json:"-"`
+	Context            string                 `json:"@context"`
+	DIDDocument        map[string]interface{} `json:"didDocument"`
+	ResolutionMetadata map[string]interface{} `json:"didResolutionMetadata"`
+	DocumentMetadata   map[string]interface{} `json:"didDocumentMetadata"`
+}
+
 func serveHTTP(t *testing.T, handler common.HTTPRequestHandler, method, path string,
 	req []byte, urlVars map[string]string) *httptest.ResponseRecorder {
 	t.Helper()
@@ -0,0 +1,166 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package restapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+)
+
+const wellKnownPath = "/.well-known/did-orb"
+
+// errBadRequest is returned when the resolution endpoint responds with 400 (Bad Request), e.g. if the value
+// of a resolution option is invalid.
+var errBadRequest = errors.New("bad request")
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// orbResolver resolves DIDs using the resolution endpoint of an Orb domain. Unlike the VDR, the resolution
+// options (versionId and versionTime) are passed to the resolution endpoint as query parameters. The resolution
+// endpoint is discovered (once) from the domain's /.well-known/did-orb document.
+type orbResolver struct {
+	domain     string
+	httpClient httpClient
+	authToken  string
+
+	mutex    sync.Mutex
+	endpoint string
+}
+
+func newOrbResolver(domain string, client httpClient, authToken string) *orbResolver {
+	if client == nil {
+		client = &http.Client{}
+	}
+
+	return &orbResolver{
+		domain:     strings.TrimSuffix(domain, "/"),
+		httpClient: client,
+		authToken:  authToken,
+	}
+}
+
+// resolve resolves the given DID with the given resolution options and returns the resolution along with the raw
+// document metadata. An error that wraps vdr.ErrNotFound is returned if the DID (or the requested version) doesn't
+// exist and an error that wraps errBadRequest is returned if the resolution endpoint rejected the request.
+func (r *orbResolver) resolve(didID string, options url.Values) (*did.DocResolution, json.RawMessage, error) {
+	endpoint, err := r.getEndpoint()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reqURL := fmt.Sprintf("%s/%s", endpoint, didID)
+	if len(options) > 0 {
+		reqURL += "?" + options.Encode()
+	}
+
+	status, body, err := r.get(reqURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch status {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil, fmt.Errorf("%w: %s", vdr.ErrNotFound, body)
+	case http.StatusBadRequest:
+		return nil, nil, fmt.Errorf("%w: %s", errBadRequest, body)
+	default:
+		return nil, nil, fmt.Errorf("unexpected response from [%s] - status [%d]: %s", reqURL, status, body)
+	}
+
+	docResolution, err := did.ParseDocumentResolution(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse document resolution from [%s]: %w", reqURL, err)
+	}
+
+	result := &struct {
+		DocumentMetadata json.RawMessage `json:"didDocumentMetadata"`
+	}{}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return nil, nil, fmt.Errorf("unmarshal document metadata from [%s]: %w", reqURL, err)
+	}
+
+	return docResolution, result.DocumentMetadata, nil
+}
+
+func (r *orbResolver) getEndpoint() (string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.endpoint != "" {
+		return r.endpoint, nil
+	}
+
+	reqURL := r.domain + wellKnownPath
+
+	status, body, err := r.get(reqURL)
+	if err != nil {
+		return "", err
+	}
+
+	if status != http.StatusOK {
+		return "", fmt.Errorf("unexpected response from [%s] - status [%d]: %s", reqURL, status, body)
+	}
+
+	wellKnown := &struct {
+		ResolutionEndpoint string `json:"resolutionEndpoint"`
+	}{}
+
+	if err := json.Unmarshal(body, wellKnown); err != nil {
+		return "", fmt.Errorf("unmarshal response from [%s]: %w", reqURL, err)
+	}
+
+	if wellKnown.ResolutionEndpoint == "" {
+		return "", fmt.Errorf("resolution endpoint not found in response from [%s]", reqURL)
+	}
+
+	r.endpoint = strings.TrimSuffix(wellKnown.ResolutionEndpoint, "/")
+
+	logger.Debugf("Discovered resolution endpoint [%s] for domain [%s]", r.endpoint, r.domain)
+
+	return r.endpoint, nil
+}
+
+func (r *orbResolver) get(reqURL string) (int, []byte, error) {
+	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("create request for [%s]: %w", reqURL, err)
+	}
+
+	if r.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+r.authToken)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("request to [%s]: %w", reqURL, err)
+	}
+
+	defer func() {
+		if e := resp.Body.Close(); e != nil {
+			logger.Warnf("Error closing response body from [%s]: %s", reqURL, e)
+		}
+	}()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("read response from [%s]: %w", reqURL, err)
+	}
+
+	return resp.StatusCode, body, nil
+}
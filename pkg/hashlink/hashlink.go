@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package hashlink
 
 import (
+	"bytes"
 	"encoding/base64"
 	"fmt"
 	"strings"
@@ -138,6 +139,31 @@ func (hl *HashLink) CreateResourceHash(content []byte) (string, error) {
 	return hl.encoder(mh), nil
 }
 
+// VerifyResourceHash will verify that the resource hash was computed from the supplied content
+// (using the multihash code of the resource hash).
+func (hl *HashLink) VerifyResourceHash(resourceHash string, content []byte) error {
+	multihashBytes, err := hl.decoder(resourceHash)
+	if err != nil {
+		return fmt.Errorf("failed to decode resource hash[%s]: %w", resourceHash, err)
+	}
+
+	mh, err := multihash.Decode(multihashBytes)
+	if err != nil {
+		return fmt.Errorf("failed to decode multihash: %w", err)
+	}
+
+	computed, err := hashing.ComputeMultihash(uint(mh.Code), content)
+	if err != nil {
+		return fmt.Errorf("failed to compute multihash for code[%d]: %w", mh.Code, err)
+	}
+
+	if !bytes.Equal(computed, multihashBytes) {
+		return fmt.Errorf("hash of content doesn't match resource hash[%s]", resourceHash)
+	}
+
+	return nil
+}
+
 // CreateMetadataFromLinks will create metadata for the supplied links.
 func (hl *HashLink) CreateMetadataFromLinks(links []string) (string, error) {
 	if len(links) == 0 {
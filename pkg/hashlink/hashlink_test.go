@@ -201,6 +201,32 @@ func TestHashLink_CreateResourceHash(t *testing.T) {
 	})
 }
 
+func TestHashLink_VerifyResourceHash(t *testing.T) {
+	hl := New()
+
+	t.Run("success", func(t *testing.T) {
+		require.NoError(t, hl.VerifyResourceHash("uEiB_g7Flf_H8U7ktwYFIodZd_C1LH6PWdyhK3dIAEm2QaQ", []byte(exampleContent)))
+	})
+
+	t.Run("error - hash mismatch", func(t *testing.T) {
+		err := hl.VerifyResourceHash("uEiB_g7Flf_H8U7ktwYFIodZd_C1LH6PWdyhK3dIAEm2QaQ", []byte("other content"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "hash of content doesn't match resource hash")
+	})
+
+	t.Run("error - invalid resource hash", func(t *testing.T) {
+		err := hl.VerifyResourceHash("u!!!", []byte(exampleContent))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to decode resource hash")
+	})
+
+	t.Run("error - invalid multihash", func(t *testing.T) {
+		err := hl.VerifyResourceHash("uAQID", []byte(exampleContent))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to decode multihash")
+	})
+}
+
 func TestHashLink_CreateMetadataFromLinks(t *testing.T) {
 	t.Run("success - with links", func(t *testing.T) {
 		links := []string{
@@ -27,9 +27,9 @@ type HandlerWrapper struct {
 
 // NewHandlerWrapper returns a handler that first performs bearer token authorization and, if authorized,
 // invokes the wrapped handler.
-func NewHandlerWrapper(cfg Config, handler common.HTTPHandler) *HandlerWrapper {
+func NewHandlerWrapper(cfg Config, handler common.HTTPHandler, opts ...Option) *HandlerWrapper {
 	return &HandlerWrapper{
-		verifier:      NewTokenVerifier(cfg, handler.Path(), handler.Method()),
+		verifier:      NewTokenVerifier(cfg, handler.Path(), handler.Method(), opts...),
 		HTTPHandler:   handler,
 		handler:       handler,
 		handleRequest: handler.Handler(),
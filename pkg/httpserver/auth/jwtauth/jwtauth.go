@@ -0,0 +1,412 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package jwtauth validates signed JWT (OAuth2) access tokens, e.g. those issued by an SSO provider, so that
+// they may be used as bearer tokens for Orb's endpoints. The token's signature is verified with a key from
+// a JSON Web Key Set (JWKS), the issuer, audience and validity period are checked and the scopes in the
+// 'scope' (or 'scp') claim are mapped to the token names of the endpoint definitions in the auth configuration.
+package jwtauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/square/go-jose/v3"
+	"github.com/square/go-jose/v3/jwt"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+var logger = log.New("jwt-auth")
+
+const (
+	defaultRefreshInterval    = 10 * time.Minute
+	defaultMinRefreshInterval = 30 * time.Second
+	defaultLeeway             = time.Minute
+
+	scopeClaim    = "scope"
+	scpClaim      = "scp"
+	keyUseSig     = "sig"
+	fileURLScheme = "file"
+)
+
+// ErrInvalidToken is returned if the token is malformed, its signature can't be verified or its claims are invalid.
+var ErrInvalidToken = errors.New("invalid JWT")
+
+//nolint:gochecknoglobals
+var supportedAlgorithms = map[string]struct{}{
+	string(jose.RS256): {}, string(jose.RS384): {}, string(jose.RS512): {},
+	string(jose.PS256): {}, string(jose.PS384): {}, string(jose.PS512): {},
+	string(jose.ES256): {}, string(jose.ES384): {}, string(jose.ES512): {},
+	string(jose.EdDSA): {},
+}
+
+// Config contains the JWT validation parameters.
+type Config struct {
+	// JWKSURL is the URL (http, https or file) or the file path of the JSON Web Key Set.
+	JWKSURL string
+
+	// Issuer is the required value of the 'iss' claim.
+	Issuer string
+
+	// Audience is the value that must be included in the 'aud' claim.
+	Audience string
+
+	// ScopeMapping maps a scope in the token to the token names (as given in the ReadTokens and WriteTokens
+	// of the auth configuration) to which it grants access. If empty then each scope maps to the token
+	// name of the same name. If not empty then scopes that aren't in the mapping are ignored.
+	ScopeMapping map[string][]string
+}
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Option is a verifier option.
+type Option func(v *Verifier)
+
+// WithHTTPClient sets the HTTP client that's used to retrieve the JWKS.
+func WithHTTPClient(client httpClient) Option {
+	return func(v *Verifier) {
+		v.httpClient = client
+	}
+}
+
+// WithRefreshInterval sets the interval at which the JWKS is reloaded. The JWKS is also reloaded (at most
+// every 30 seconds) if a token is signed with an unknown key.
+func WithRefreshInterval(value time.Duration) Option {
+	return func(v *Verifier) {
+		v.refreshInterval = value
+	}
+}
+
+// WithLeeway sets the allowed clock skew when validating the time-based claims.
+func WithLeeway(value time.Duration) Option {
+	return func(v *Verifier) {
+		v.leeway = value
+	}
+}
+
+// Verifier validates JWT access tokens and resolves them to the token names of the auth configuration.
+type Verifier struct {
+	*Config
+
+	httpClient         httpClient
+	refreshInterval    time.Duration
+	minRefreshInterval time.Duration
+	leeway             time.Duration
+
+	mutex    sync.Mutex
+	keys     *jose.JSONWebKeySet
+	loaded   time.Time
+	nextLoad time.Time
+	loading  bool
+}
+
+// New returns a new JWT verifier.
+func New(cfg *Config, opts ...Option) (*Verifier, error) {
+	if cfg.JWKSURL == "" {
+		return nil, errors.New("JWKS URL is required")
+	}
+
+	if cfg.Issuer == "" {
+		return nil, errors.New("issuer is required")
+	}
+
+	if cfg.Audience == "" {
+		return nil, errors.New("audience is required")
+	}
+
+	v := &Verifier{
+		Config:             cfg,
+		httpClient:         &http.Client{},
+		refreshInterval:    defaultRefreshInterval,
+		minRefreshInterval: defaultMinRefreshInterval,
+		leeway:             defaultLeeway,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	keys, err := v.loadKeys()
+	if err != nil {
+		if !orberrors.IsTransient(err) {
+			return nil, err
+		}
+
+		// The keys are loaded on the first request.
+		logger.Warnf("Unable to load JWKS from [%s]: %s", cfg.JWKSURL, err)
+	} else {
+		v.keys = keys
+		v.loaded = time.Now()
+	}
+
+	return v, nil
+}
+
+// Scopes validates the given JWT and returns the token names to which its scopes grant access.
+// ErrInvalidToken is returned if the token isn't valid.
+func (v *Verifier) Scopes(token string) ([]string, error) {
+	tok, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+
+	if len(tok.Headers) != 1 {
+		return nil, fmt.Errorf("%w: expecting exactly one signature", ErrInvalidToken)
+	}
+
+	header := tok.Headers[0]
+
+	if _, ok := supportedAlgorithms[header.Algorithm]; !ok {
+		return nil, fmt.Errorf("%w: unsupported algorithm [%s]", ErrInvalidToken, header.Algorithm)
+	}
+
+	key, err := v.key(header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.Algorithm != "" && key.Algorithm != header.Algorithm {
+		return nil, fmt.Errorf("%w: algorithm [%s] doesn't match the algorithm of key [%s]",
+			ErrInvalidToken, header.Algorithm, key.KeyID)
+	}
+
+	claims := &jwt.Claims{}
+	scopeClaims := make(map[string]interface{})
+
+	if err := tok.Claims(key.Key, claims, &scopeClaims); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+
+	if claims.Expiry == nil {
+		return nil, fmt.Errorf("%w: 'exp' claim is required", ErrInvalidToken)
+	}
+
+	err = claims.ValidateWithLeeway(jwt.Expected{
+		Issuer:   v.Issuer,
+		Audience: jwt.Audience{v.Audience},
+		Time:     time.Now(),
+	}, v.leeway)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+
+	scopes := v.mapScopes(getScopes(scopeClaims))
+
+	logger.Debugf("JWT for subject [%s] has scopes %s", claims.Subject, scopes)
+
+	return scopes, nil
+}
+
+func (v *Verifier) key(kid string) (*jose.JSONWebKey, error) {
+	keys, err := v.currentKeys(time.Now(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := findKey(keys, kid)
+	if err == nil {
+		return key, nil
+	}
+
+	// The key may have been rotated by the issuer.
+	logger.Debugf("Key [%s] not found. Reloading JWKS.", kid)
+
+	keys, e := v.currentKeys(time.Now(), true)
+	if e != nil {
+		return nil, e
+	}
+
+	return findKey(keys, kid)
+}
+
+// currentKeys returns the cached keys after reloading them if they're stale (or, if force is true, if they
+// were loaded at least the minimum refresh interval ago). The JWKS is only loaded by one caller at a time,
+// without holding the lock, and no more often than the minimum refresh interval. If the JWKS can't be
+// loaded then the cached keys continue to be used until the next attempt.
+func (v *Verifier) currentKeys(now time.Time, force bool) (*jose.JSONWebKeySet, error) {
+	v.mutex.Lock()
+
+	keys := v.keys
+
+	due := keys == nil || now.Sub(v.loaded) > v.refreshInterval ||
+		(force && now.Sub(v.loaded) >= v.minRefreshInterval)
+
+	if !due || v.loading || now.Before(v.nextLoad) {
+		v.mutex.Unlock()
+
+		if keys == nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("JWKS from [%s] is not loaded", v.JWKSURL))
+		}
+
+		return keys, nil
+	}
+
+	v.loading = true
+	v.nextLoad = now.Add(v.minRefreshInterval)
+
+	v.mutex.Unlock()
+
+	newKeys, err := v.loadKeys()
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	v.loading = false
+
+	if err != nil {
+		if keys == nil {
+			return nil, err
+		}
+
+		logger.Warnf("Unable to reload JWKS from [%s]. Using the cached keys until the next attempt: %s",
+			v.JWKSURL, err)
+
+		return keys, nil
+	}
+
+	v.keys = newKeys
+	v.loaded = now
+
+	return newKeys, nil
+}
+
+func (v *Verifier) loadKeys() (*jose.JSONWebKeySet, error) {
+	jwksBytes, err := v.readJWKS()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := &jose.JSONWebKeySet{}
+
+	if err := json.Unmarshal(jwksBytes, keys); err != nil {
+		return nil, fmt.Errorf("unmarshal JWKS: %w", err)
+	}
+
+	logger.Debugf("Loaded %d keys from JWKS [%s]", len(keys.Keys), v.JWKSURL)
+
+	return keys, nil
+}
+
+func (v *Verifier) readJWKS() ([]byte, error) {
+	u, err := url.Parse(v.JWKSURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		path := v.JWKSURL
+
+		if err == nil && u.Scheme == fileURLScheme {
+			path = u.Path
+		}
+
+		jwksBytes, err := ioutil.ReadFile(path) //nolint:gosec
+		if err != nil {
+			return nil, fmt.Errorf("read JWKS file: %w", err)
+		}
+
+		return jwksBytes, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, v.JWKSURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("get JWKS from [%s]: %w", v.JWKSURL, err))
+	}
+
+	defer func() {
+		if errClose := resp.Body.Close(); errClose != nil {
+			logger.Warnf("Error closing response body: %s", errClose)
+		}
+	}()
+
+	jwksBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("read JWKS response: %w", err))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, orberrors.NewTransient(fmt.Errorf("get JWKS from [%s]: status code %d: %s",
+			v.JWKSURL, resp.StatusCode, jwksBytes))
+	}
+
+	return jwksBytes, nil
+}
+
+func (v *Verifier) mapScopes(scopes []string) []string {
+	if len(v.ScopeMapping) == 0 {
+		return scopes
+	}
+
+	var names []string
+
+	for _, scope := range scopes {
+		names = append(names, v.ScopeMapping[scope]...)
+	}
+
+	return names
+}
+
+func findKey(keys *jose.JSONWebKeySet, kid string) (*jose.JSONWebKey, error) {
+	var candidates []jose.JSONWebKey
+
+	if kid == "" {
+		candidates = keys.Keys
+	} else {
+		candidates = keys.Key(kid)
+	}
+
+	var sigKeys []*jose.JSONWebKey
+
+	for i := range candidates {
+		key := &candidates[i]
+
+		if key.IsPublic() && (key.Use == "" || key.Use == keyUseSig) {
+			sigKeys = append(sigKeys, key)
+		}
+	}
+
+	switch {
+	case len(sigKeys) == 1:
+		return sigKeys[0], nil
+	case len(sigKeys) > 1 && kid == "":
+		return nil, fmt.Errorf("%w: 'kid' header is required since the JWKS contains multiple keys", ErrInvalidToken)
+	default:
+		return nil, fmt.Errorf("%w: signing key [%s] not found", ErrInvalidToken, kid)
+	}
+}
+
+func getScopes(claims map[string]interface{}) []string {
+	for _, claim := range []string{scopeClaim, scpClaim} {
+		switch value := claims[claim].(type) {
+		case string:
+			return strings.Fields(value)
+		case []interface{}:
+			var scopes []string
+
+			for _, s := range value {
+				if scope, ok := s.(string); ok {
+					scopes = append(scopes, scope)
+				}
+			}
+
+			return scopes
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,393 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwtauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/square/go-jose/v3"
+	"github.com/square/go-jose/v3/jwt"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const (
+	issuer   = "https://sso.example.com"
+	audience = "https://orb.domain1.com"
+)
+
+type scopeClaims struct {
+	Scope string   `json:"scope,omitempty"`
+	Scp   []string `json:"scp,omitempty"`
+}
+
+func TestNew(t *testing.T) {
+	jwksFile, _ := newJWKSFile(t)
+
+	t.Run("Success", func(t *testing.T) {
+		v, err := New(&Config{JWKSURL: jwksFile, Issuer: issuer, Audience: audience})
+		require.NoError(t, err)
+		require.NotNil(t, v)
+		require.NotNil(t, v.keys)
+	})
+
+	t.Run("Missing parameters", func(t *testing.T) {
+		_, err := New(&Config{Issuer: issuer, Audience: audience})
+		require.EqualError(t, err, "JWKS URL is required")
+
+		_, err = New(&Config{JWKSURL: jwksFile, Audience: audience})
+		require.EqualError(t, err, "issuer is required")
+
+		_, err = New(&Config{JWKSURL: jwksFile, Issuer: issuer})
+		require.EqualError(t, err, "audience is required")
+	})
+
+	t.Run("JWKS file not found", func(t *testing.T) {
+		_, err := New(&Config{JWKSURL: "file:///nonexistent/jwks.json", Issuer: issuer, Audience: audience})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read JWKS file")
+	})
+
+	t.Run("Invalid JWKS", func(t *testing.T) {
+		f := filepath.Join(t.TempDir(), "jwks.json")
+		require.NoError(t, ioutil.WriteFile(f, []byte("{"), 0o600))
+
+		_, err := New(&Config{JWKSURL: f, Issuer: issuer, Audience: audience})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal JWKS")
+	})
+
+	t.Run("JWKS server unavailable -> loaded later", func(t *testing.T) {
+		v, err := New(&Config{JWKSURL: "http://127.0.0.1:0/jwks", Issuer: issuer, Audience: audience})
+		require.NoError(t, err)
+		require.Nil(t, v.keys)
+	})
+}
+
+func TestVerifier_Scopes(t *testing.T) {
+	jwksFile, key := newJWKSFile(t)
+
+	v, err := New(&Config{JWKSURL: "file://" + jwksFile, Issuer: issuer, Audience: audience})
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		token := newToken(t, key, validClaims(), &scopeClaims{Scope: "admin read"})
+
+		scopes, err := v.Scopes(token)
+		require.NoError(t, err)
+		require.Equal(t, []string{"admin", "read"}, scopes)
+	})
+
+	t.Run("Success - scp claim", func(t *testing.T) {
+		token := newToken(t, key, validClaims(), &scopeClaims{Scp: []string{"read"}})
+
+		scopes, err := v.Scopes(token)
+		require.NoError(t, err)
+		require.Equal(t, []string{"read"}, scopes)
+	})
+
+	t.Run("Scope mapping", func(t *testing.T) {
+		v, err := New(&Config{
+			JWKSURL:  jwksFile,
+			Issuer:   issuer,
+			Audience: audience,
+			ScopeMapping: map[string][]string{
+				"orb:write": {"admin"},
+				"orb:read":  {"read", "public"},
+			},
+		})
+		require.NoError(t, err)
+
+		token := newToken(t, key, validClaims(), &scopeClaims{Scope: "openid orb:read"})
+
+		scopes, err := v.Scopes(token)
+		require.NoError(t, err)
+		require.Equal(t, []string{"read", "public"}, scopes)
+	})
+
+	t.Run("Invalid claims", func(t *testing.T) {
+		for name, c := range map[string]func(c *jwt.Claims){
+			"wrong issuer":   func(c *jwt.Claims) { c.Issuer = "https://other.example.com" },
+			"wrong audience": func(c *jwt.Claims) { c.Audience = jwt.Audience{"https://orb.domain2.com"} },
+			"expired":        func(c *jwt.Claims) { c.Expiry = jwt.NewNumericDate(time.Now().Add(-time.Hour)) },
+			"no expiry":      func(c *jwt.Claims) { c.Expiry = nil },
+			"not yet valid":  func(c *jwt.Claims) { c.NotBefore = jwt.NewNumericDate(time.Now().Add(time.Hour)) },
+		} {
+			claims := validClaims()
+			c(claims)
+
+			_, err := v.Scopes(newToken(t, key, claims, &scopeClaims{Scope: "admin"}))
+			require.Truef(t, errors.Is(err, ErrInvalidToken), "%s: %v", name, err)
+		}
+	})
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		_, otherKey := newKey(t, key.KeyID)
+
+		_, err := v.Scopes(newToken(t, otherKey, validClaims(), &scopeClaims{Scope: "admin"}))
+		require.True(t, errors.Is(err, ErrInvalidToken))
+	})
+
+	t.Run("Unknown key", func(t *testing.T) {
+		_, otherKey := newKey(t, "other-key")
+
+		_, err := v.Scopes(newToken(t, otherKey, validClaims(), &scopeClaims{Scope: "admin"}))
+		require.True(t, errors.Is(err, ErrInvalidToken))
+		require.Contains(t, err.Error(), "signing key [other-key] not found")
+	})
+
+	t.Run("Unsupported algorithm", func(t *testing.T) {
+		signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("secret")},
+			(&jose.SignerOptions{}).WithHeader(jose.HeaderKey("kid"), key.KeyID))
+		require.NoError(t, err)
+
+		token, err := jwt.Signed(signer).Claims(validClaims()).CompactSerialize()
+		require.NoError(t, err)
+
+		_, err = v.Scopes(token)
+		require.True(t, errors.Is(err, ErrInvalidToken))
+		require.Contains(t, err.Error(), "unsupported algorithm [HS256]")
+	})
+
+	t.Run("Malformed token", func(t *testing.T) {
+		_, err := v.Scopes("123.secret")
+		require.True(t, errors.Is(err, ErrInvalidToken))
+	})
+}
+
+func TestVerifier_JWKSURL(t *testing.T) {
+	pubKey1, key1 := newKey(t, "key1")
+	pubKey2, key2 := newKey(t, "key2")
+
+	var (
+		keys     atomic.Value
+		requests int32
+	)
+
+	keys.Store(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{pubKey1}})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		require.NoError(t, json.NewEncoder(w).Encode(keys.Load()))
+	}))
+	defer srv.Close()
+
+	v, err := New(&Config{JWKSURL: srv.URL, Issuer: issuer, Audience: audience}, WithHTTPClient(srv.Client()),
+		WithRefreshInterval(time.Hour), WithLeeway(time.Second))
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	_, err = v.Scopes(newToken(t, key1, validClaims(), &scopeClaims{Scope: "admin"}))
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// The issuer rotates its key. The JWKS isn't reloaded more often than the minimum refresh interval.
+	keys.Store(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{pubKey1, pubKey2}})
+
+	_, err = v.Scopes(newToken(t, key2, validClaims(), &scopeClaims{Scope: "admin"}))
+	require.True(t, errors.Is(err, ErrInvalidToken))
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	v.minRefreshInterval = 0
+
+	_, err = v.Scopes(newToken(t, key2, validClaims(), &scopeClaims{Scope: "admin"}))
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	t.Run("Server error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		v, err := New(&Config{JWKSURL: srv.URL, Issuer: issuer, Audience: audience}, WithHTTPClient(srv.Client()))
+		require.NoError(t, err)
+
+		_, err = v.Scopes(newToken(t, key1, validClaims(), &scopeClaims{Scope: "admin"}))
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "status code 503")
+
+		// No further attempt is made until the minimum refresh interval has elapsed.
+		_, err = v.Scopes(newToken(t, key1, validClaims(), &scopeClaims{Scope: "admin"}))
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "is not loaded")
+	})
+
+	t.Run("Reload error -> cached keys used", func(t *testing.T) {
+		var (
+			failed   int32
+			requests int32
+		)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+
+			if atomic.LoadInt32(&failed) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+
+				return
+			}
+
+			require.NoError(t, json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{pubKey1}}))
+		}))
+		defer srv.Close()
+
+		v, err := New(&Config{JWKSURL: srv.URL, Issuer: issuer, Audience: audience}, WithHTTPClient(srv.Client()),
+			WithRefreshInterval(time.Hour), WithLeeway(time.Second))
+		require.NoError(t, err)
+		require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+		atomic.StoreInt32(&failed, 1)
+
+		// Make the cached keys stale.
+		v.loaded = time.Now().Add(-2 * time.Hour)
+
+		_, err = v.Scopes(newToken(t, key1, validClaims(), &scopeClaims{Scope: "admin"}))
+		require.NoError(t, err)
+		require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+		// The reload isn't attempted again until the minimum refresh interval has elapsed.
+		_, err = v.Scopes(newToken(t, key1, validClaims(), &scopeClaims{Scope: "admin"}))
+		require.NoError(t, err)
+		require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+		atomic.StoreInt32(&failed, 0)
+		v.nextLoad = time.Time{}
+
+		_, err = v.Scopes(newToken(t, key1, validClaims(), &scopeClaims{Scope: "admin"}))
+		require.NoError(t, err)
+		require.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+		// The keys were refreshed so no further reload is required.
+		_, err = v.Scopes(newToken(t, key1, validClaims(), &scopeClaims{Scope: "admin"}))
+		require.NoError(t, err)
+		require.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	})
+}
+
+func TestFindKey(t *testing.T) {
+	pubKey1, _ := newKey(t, "key1")
+	pubKey2, _ := newKey(t, "key2")
+
+	t.Run("No kid with single key", func(t *testing.T) {
+		key, err := findKey(&jose.JSONWebKeySet{Keys: []jose.JSONWebKey{pubKey1}}, "")
+		require.NoError(t, err)
+		require.Equal(t, "key1", key.KeyID)
+	})
+
+	t.Run("No kid with multiple keys", func(t *testing.T) {
+		_, err := findKey(&jose.JSONWebKeySet{Keys: []jose.JSONWebKey{pubKey1, pubKey2}}, "")
+		require.True(t, errors.Is(err, ErrInvalidToken))
+		require.Contains(t, err.Error(), "'kid' header is required")
+	})
+
+	t.Run("Encryption key", func(t *testing.T) {
+		encKey := pubKey1
+		encKey.Use = "enc"
+
+		_, err := findKey(&jose.JSONWebKeySet{Keys: []jose.JSONWebKey{encKey}}, "key1")
+		require.True(t, errors.Is(err, ErrInvalidToken))
+	})
+}
+
+func TestVerifier_KeyAlgorithmMismatch(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	pubKey := jose.JSONWebKey{
+		Key: priv.Public(), KeyID: "key1", Algorithm: string(jose.ES256), Use: keyUseSig,
+	}
+
+	f := writeJWKS(t, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{pubKey}})
+
+	v, err := New(&Config{JWKSURL: f, Issuer: issuer, Audience: audience})
+	require.NoError(t, err)
+
+	key := jose.JSONWebKey{Key: priv, KeyID: "key1", Algorithm: string(jose.EdDSA)}
+
+	_, err = v.Scopes(newToken(t, key, validClaims(), &scopeClaims{Scope: "admin"}))
+	require.True(t, errors.Is(err, ErrInvalidToken))
+	require.Contains(t, err.Error(), "doesn't match the algorithm of key")
+}
+
+func validClaims() *jwt.Claims {
+	now := time.Now()
+
+	return &jwt.Claims{
+		Issuer:   issuer,
+		Subject:  "client1",
+		Audience: jwt.Audience{audience, "https://other.example.com"},
+		IssuedAt: jwt.NewNumericDate(now),
+		Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+	}
+}
+
+func newKey(t *testing.T, kid string) (pubKey, privKey jose.JSONWebKey) {
+	t.Helper()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return jose.JSONWebKey{Key: priv.Public(), KeyID: kid, Algorithm: string(jose.ES256), Use: keyUseSig},
+		jose.JSONWebKey{Key: priv, KeyID: kid, Algorithm: string(jose.ES256)}
+}
+
+func newJWKSFile(t *testing.T) (string, jose.JSONWebKey) {
+	t.Helper()
+
+	pubKey, privKey := newKey(t, "key1")
+
+	return writeJWKS(t, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{pubKey}}), privKey
+}
+
+func writeJWKS(t *testing.T, keys jose.JSONWebKeySet) string {
+	t.Helper()
+
+	jwksBytes, err := json.Marshal(keys)
+	require.NoError(t, err)
+
+	f, err := ioutil.TempFile(t.TempDir(), "jwks")
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, f.Close())
+	}()
+
+	_, err = f.Write(jwksBytes)
+	require.NoError(t, err)
+
+	return f.Name()
+}
+
+func newToken(t *testing.T, key jose.JSONWebKey, claims *jwt.Claims, scopes *scopeClaims) string {
+	t.Helper()
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.SignatureAlgorithm(key.Algorithm), Key: key},
+		(&jose.SignerOptions{}).WithType("JWT"))
+	require.NoError(t, err)
+
+	token, err := jwt.Signed(signer).Claims(claims).Claims(scopes).CompactSerialize()
+	require.NoError(t, err)
+
+	return token
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"strings"
 
 	"github.com/trustbloc/edge-core/pkg/log"
 )
@@ -29,45 +30,93 @@ type TokenDef struct {
 	WriteTokens        []string
 }
 
+// TokenResolver resolves an issued (i.e. not statically configured) bearer token to its scopes. A scope is
+// the name of a token in the ReadTokens or WriteTokens of a TokenDef.
+type TokenResolver interface {
+	Scopes(token string) ([]string, error)
+}
+
 // Config contains the authorization token configuration.
 type Config struct {
 	AuthTokensDef []*TokenDef
 	AuthTokens    map[string]string
+
+	// TokenResolvers is optional. If set then bearer tokens that don't match a static token are resolved
+	// to their scopes (by the first resolver that accepts the token), and access is granted if any of the
+	// scopes is a token name required by the endpoint.
+	TokenResolvers []TokenResolver
+
+	// DynamicTokens contains the token names in AuthTokensDef that have no static token in AuthTokens since
+	// they are only granted as scopes of the tokens resolved by TokenResolvers. Any other token name in
+	// AuthTokensDef must have a static token.
+	DynamicTokens []string
+}
+
+// Option is a token verifier option.
+type Option func(v *TokenVerifier)
+
+// WithAuthRequired denies all requests to the endpoint if it isn't covered by a token definition, rather than
+// allowing open access. This option should be used for administrative endpoints which must never be open.
+func WithAuthRequired() Option {
+	return func(v *TokenVerifier) {
+		v.authRequired = true
+	}
 }
 
 // TokenVerifier authorizes requests with bearer tokens.
 type TokenVerifier struct {
 	Config
 
-	endpoint   string
-	authTokens []string
+	endpoint     string
+	tokenNames   []string
+	authTokens   []string
+	authRequired bool
 }
 
 // NewTokenVerifier returns a verifier that performs bearer token authorization.
-func NewTokenVerifier(cfg Config, endpoint, method string) *TokenVerifier {
-	authTokens, err := resolveAuthTokens(endpoint, method, cfg.AuthTokensDef, cfg.AuthTokens)
+func NewTokenVerifier(cfg Config, endpoint, method string, opts ...Option) *TokenVerifier {
+	tokenNames, authTokens, err := resolveAuthTokens(endpoint, method, cfg.AuthTokensDef, cfg.AuthTokens,
+		cfg.DynamicTokens)
 	if err != nil {
 		// This would occur on startup due to bad configuration, so it's better to panic.
 		panic(fmt.Errorf("resolve authorization tokens: %w", err))
 	}
 
-	return &TokenVerifier{
+	v := &TokenVerifier{
 		Config:     cfg,
 		endpoint:   endpoint,
+		tokenNames: tokenNames,
 		authTokens: authTokens,
 	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	if v.authRequired && len(tokenNames) == 0 {
+		logger.Warnf("[%s - %s] Endpoint requires authorization but no token definition covers it. "+
+			"All requests to this endpoint will be denied.", method, endpoint)
+	}
+
+	return v
 }
 
 // Verify verifies that the request has the required bearer token. If not, false is returned.
 func (h *TokenVerifier) Verify(req *http.Request) bool {
-	if len(h.authTokens) == 0 {
+	if len(h.tokenNames) == 0 {
+		if h.authRequired {
+			logger.Debugf("[%s] No token definition for endpoint that requires authorization.", h.endpoint)
+
+			return false
+		}
+
 		// Open access.
 		logger.Debugf("[%s] No auth token required.", h.endpoint)
 
 		return true
 	}
 
-	logger.Debugf("[%s] Auth tokens required: %s", h.endpoint, h.authTokens)
+	logger.Debugf("[%s] Auth tokens required: %s", h.endpoint, h.tokenNames)
 
 	actHdr := req.Header.Get(authHeader)
 	if actHdr == "" {
@@ -78,26 +127,66 @@ func (h *TokenVerifier) Verify(req *http.Request) bool {
 
 	// Compare the header against all tokens. If any match then we allow the request.
 	for _, token := range h.authTokens {
-		logger.Debugf("[%s] Checking token %s", h.endpoint, token)
-
 		if subtle.ConstantTimeCompare([]byte(actHdr), []byte(tokenPrefix+token)) == 1 {
-			logger.Debugf("[%s] Found token %s", h.endpoint, token)
+			logger.Debugf("[%s] Found static token", h.endpoint)
 
 			return true
 		}
 	}
 
+	if len(h.TokenResolvers) == 0 || !strings.HasPrefix(actHdr, tokenPrefix) {
+		return false
+	}
+
+	return h.verifyScopes(strings.TrimPrefix(actHdr, tokenPrefix))
+}
+
+func (h *TokenVerifier) verifyScopes(token string) bool {
+	scopes, err := h.resolveScopes(token)
+	if err != nil {
+		logger.Debugf("[%s] Unable to resolve scopes of bearer token: %s", h.endpoint, err)
+
+		return false
+	}
+
+	for _, scope := range scopes {
+		for _, name := range h.tokenNames {
+			if scope == name {
+				logger.Debugf("[%s] Found token with scope %s", h.endpoint, scope)
+
+				return true
+			}
+		}
+	}
+
+	logger.Debugf("[%s] Bearer token scopes %s don't include any of %s", h.endpoint, scopes, h.tokenNames)
+
 	return false
 }
 
+func (h *TokenVerifier) resolveScopes(token string) ([]string, error) {
+	var errs []string
+
+	for _, r := range h.TokenResolvers {
+		scopes, err := r.Scopes(token)
+		if err == nil {
+			return scopes, nil
+		}
+
+		errs = append(errs, err.Error())
+	}
+
+	return nil, fmt.Errorf("%s", strings.Join(errs, "; "))
+}
+
 func resolveAuthTokens(endpoint, method string, authTokensDef []*TokenDef,
-	authTokenMap map[string]string) ([]string, error) {
-	var authTokens []string
+	authTokenMap map[string]string, dynamicTokens []string) ([]string, []string, error) {
+	var tokenNames, authTokens []string
 
 	for _, def := range authTokensDef {
 		ok, err := endpointMatches(endpoint, def.EndpointExpression)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		if !ok {
@@ -113,9 +202,16 @@ func resolveAuthTokens(endpoint, method string, authTokensDef []*TokenDef,
 		}
 
 		for _, tokenID := range tokens {
+			tokenNames = append(tokenNames, tokenID)
+
 			token, ok := authTokenMap[tokenID]
 			if !ok {
-				return nil, fmt.Errorf("token not found: %s", tokenID)
+				if contains(dynamicTokens, tokenID) {
+					// The token name may only be granted as a scope of an issued token.
+					continue
+				}
+
+				return nil, nil, fmt.Errorf("token not found: %s", tokenID)
 			}
 
 			authTokens = append(authTokens, token)
@@ -124,9 +220,19 @@ func resolveAuthTokens(endpoint, method string, authTokensDef []*TokenDef,
 		break
 	}
 
-	logger.Debugf("[%s] Authorization tokens: %s", endpoint, authTokens)
+	logger.Debugf("[%s] Authorization tokens: %s", endpoint, tokenNames)
+
+	return tokenNames, authTokens, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
 
-	return authTokens, nil
+	return false
 }
 
 func endpointMatches(endpoint, pattern string) (bool, error) {
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package auth
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -94,4 +95,134 @@ func TestTokenVerifier(t *testing.T) {
 
 		require.True(t, v.Verify(req))
 	})
+
+	t.Run("Auth required with no token definition -> unauthorized", func(t *testing.T) {
+		v := NewTokenVerifier(cfg, "/tokens", http.MethodPost, WithAuthRequired())
+		require.NotNil(t, v)
+
+		req := httptest.NewRequest(http.MethodPost, "/tokens", nil)
+
+		require.False(t, v.Verify(req))
+
+		req.Header[authHeader] = []string{tokenPrefix + "ADMIN_TOKEN"}
+
+		require.False(t, v.Verify(req))
+	})
+
+	t.Run("Auth required with token definition -> success", func(t *testing.T) {
+		v := NewTokenVerifier(cfg, "/services/orb/outbox", http.MethodPost, WithAuthRequired())
+		require.NotNil(t, v)
+
+		req := httptest.NewRequest(http.MethodPost, "/services/orb/outbox", nil)
+
+		require.False(t, v.Verify(req))
+
+		req.Header[authHeader] = []string{tokenPrefix + "ADMIN_TOKEN"}
+
+		require.True(t, v.Verify(req))
+	})
+}
+
+func TestTokenVerifier_TokenResolver(t *testing.T) {
+	cfg := Config{
+		AuthTokensDef: []*TokenDef{
+			{
+				EndpointExpression: "/services/orb/outbox",
+				ReadTokens:         []string{"admin", "read"},
+				WriteTokens:        []string{"admin"},
+			},
+		},
+		AuthTokens: map[string]string{
+			"admin": "ADMIN_TOKEN",
+		},
+		TokenResolvers: []TokenResolver{
+			&mockTokenResolver{
+				scopes: map[string][]string{
+					"ISSUED_READ_TOKEN": {"read"},
+				},
+			},
+			&mockTokenResolver{
+				scopes: map[string][]string{
+					"ISSUED_ADMIN_TOKEN": {"other", "admin"},
+				},
+			},
+		},
+		DynamicTokens: []string{"read"},
+	}
+
+	t.Run("Static token -> success", func(t *testing.T) {
+		v := NewTokenVerifier(cfg, "/services/orb/outbox", http.MethodPost)
+
+		req := httptest.NewRequest(http.MethodPost, "/services/orb/outbox", nil)
+		req.Header[authHeader] = []string{tokenPrefix + "ADMIN_TOKEN"}
+
+		require.True(t, v.Verify(req))
+	})
+
+	t.Run("Issued token with required scope -> success", func(t *testing.T) {
+		v := NewTokenVerifier(cfg, "/services/orb/outbox", http.MethodGet)
+
+		req := httptest.NewRequest(http.MethodGet, "/services/orb/outbox", nil)
+		req.Header[authHeader] = []string{tokenPrefix + "ISSUED_READ_TOKEN"}
+
+		require.True(t, v.Verify(req))
+
+		v = NewTokenVerifier(cfg, "/services/orb/outbox", http.MethodPost)
+
+		req = httptest.NewRequest(http.MethodPost, "/services/orb/outbox", nil)
+		req.Header[authHeader] = []string{tokenPrefix + "ISSUED_ADMIN_TOKEN"}
+
+		require.True(t, v.Verify(req))
+	})
+
+	t.Run("Issued token without required scope -> unauthorized", func(t *testing.T) {
+		v := NewTokenVerifier(cfg, "/services/orb/outbox", http.MethodPost)
+
+		req := httptest.NewRequest(http.MethodPost, "/services/orb/outbox", nil)
+		req.Header[authHeader] = []string{tokenPrefix + "ISSUED_READ_TOKEN"}
+
+		require.False(t, v.Verify(req))
+	})
+
+	t.Run("Token not found and not dynamic -> panic", func(t *testing.T) {
+		c := cfg
+		c.DynamicTokens = nil
+
+		require.Panics(t, func() {
+			NewTokenVerifier(c, "/services/orb/outbox", http.MethodGet)
+		})
+
+		c.DynamicTokens = []string{"admin", "reed"}
+
+		require.Panics(t, func() {
+			NewTokenVerifier(c, "/services/orb/outbox", http.MethodGet)
+		})
+	})
+
+	t.Run("Unknown token -> unauthorized", func(t *testing.T) {
+		v := NewTokenVerifier(cfg, "/services/orb/outbox", http.MethodGet)
+
+		req := httptest.NewRequest(http.MethodGet, "/services/orb/outbox", nil)
+		req.Header[authHeader] = []string{tokenPrefix + "UNKNOWN_TOKEN"}
+
+		require.False(t, v.Verify(req))
+
+		req = httptest.NewRequest(http.MethodGet, "/services/orb/outbox", nil)
+		req.Header[authHeader] = []string{"ISSUED_READ_TOKEN"}
+
+		require.False(t, v.Verify(req))
+	})
+}
+
+type mockTokenResolver struct {
+	scopes map[string][]string
+}
+
+func (m *mockTokenResolver) Scopes(token string) ([]string, error) {
+	scopes, ok := m.scopes[token]
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+
+	return scopes, nil
 }
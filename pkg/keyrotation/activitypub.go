@@ -0,0 +1,104 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyrotation
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+type keyProvider interface {
+	Keys() ([]*Key, error)
+}
+
+// ActivityPubKeys exposes the signing keys as the public keys of an ActivityPub service. The public key of
+// each signing key is published at <service IRI>/keys/<name> where the name of the initial key is the main key
+// name (for compatibility with existing followers) and the name of a rotated key is its KMS key ID.
+type ActivityPubKeys struct {
+	keys       keyProvider
+	serviceIRI *url.URL
+	mainKeyID  string
+	mainName   string
+}
+
+// NewActivityPubKeys returns a new ActivityPub public key provider. The key with the given mainKeyID is
+// published with the given mainName.
+func NewActivityPubKeys(keys keyProvider, serviceIRI *url.URL, mainKeyID, mainName string) *ActivityPubKeys {
+	return &ActivityPubKeys{
+		keys:       keys,
+		serviceIRI: serviceIRI,
+		mainKeyID:  mainKeyID,
+		mainName:   mainName,
+	}
+}
+
+// PublicKeys returns the published public keys of the service. The first key is the current signing key.
+func (p *ActivityPubKeys) PublicKeys() ([]*vocab.PublicKeyType, error) {
+	keys, err := p.keys.Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	publicKeys := make([]*vocab.PublicKeyType, len(keys))
+
+	for i, key := range keys {
+		publicKey, err := p.newPublicKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		publicKeys[i] = publicKey
+	}
+
+	return publicKeys, nil
+}
+
+// SigningKey returns the KMS ID of the current signing key along with the ID of the corresponding
+// public key.
+func (p *ActivityPubKeys) SigningKey() (keyID, publicKeyID string, err error) {
+	keys, err := p.keys.Keys()
+	if err != nil {
+		return "", "", err
+	}
+
+	return keys[0].ID, p.publicKeyIRI(keys[0].ID).String(), nil
+}
+
+func (p *ActivityPubKeys) newPublicKey(key *Key) (*vocab.PublicKeyType, error) {
+	pubDerKey, err := x509.MarshalPKIXPublicKey(ed25519.PublicKey(key.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("marshal public key [%s]: %w", key.ID, err)
+	}
+
+	pemBytes := pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: pubDerKey,
+	})
+
+	return vocab.NewPublicKey(
+		vocab.WithID(p.publicKeyIRI(key.ID)),
+		vocab.WithOwner(p.serviceIRI),
+		vocab.WithPublicKeyPem(string(pemBytes)),
+	), nil
+}
+
+func (p *ActivityPubKeys) publicKeyIRI(keyID string) *url.URL {
+	name := keyID
+	if keyID == p.mainKeyID {
+		name = p.mainName
+	}
+
+	keyIRI := *p.serviceIRI
+	keyIRI.Path += "/keys/" + name
+
+	return &keyIRI
+}
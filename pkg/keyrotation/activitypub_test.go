@@ -0,0 +1,84 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyrotation
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivityPubKeys(t *testing.T) {
+	serviceIRI, err := url.Parse("https://orb.domain1.com/services/orb")
+	require.NoError(t, err)
+
+	pubKey1, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	pubKey2, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	retireTime := time.Now().Add(time.Hour)
+
+	t.Run("Success", func(t *testing.T) {
+		p := NewActivityPubKeys(&mockKeyProvider{keys: []*Key{
+			{ID: "key2", PublicKey: pubKey2},
+			{ID: keyID1, PublicKey: pubKey1, RetireTime: &retireTime},
+		}}, serviceIRI, keyID1, "main-key")
+
+		publicKeys, err := p.PublicKeys()
+		require.NoError(t, err)
+		require.Len(t, publicKeys, 2)
+		require.Equal(t, "https://orb.domain1.com/services/orb/keys/key2", publicKeys[0].ID.String())
+		require.Equal(t, serviceIRI.String(), publicKeys[0].Owner.String())
+		require.Contains(t, publicKeys[0].PublicKeyPem, "BEGIN PUBLIC KEY")
+		require.Equal(t, "https://orb.domain1.com/services/orb/keys/main-key", publicKeys[1].ID.String())
+		require.NotEqual(t, publicKeys[0].PublicKeyPem, publicKeys[1].PublicKeyPem)
+
+		keyID, publicKeyID, err := p.SigningKey()
+		require.NoError(t, err)
+		require.Equal(t, "key2", keyID)
+		require.Equal(t, "https://orb.domain1.com/services/orb/keys/key2", publicKeyID)
+
+		// The service IRI isn't modified.
+		require.Equal(t, "https://orb.domain1.com/services/orb", serviceIRI.String())
+	})
+
+	t.Run("Initial key", func(t *testing.T) {
+		p := NewActivityPubKeys(&mockKeyProvider{keys: []*Key{{ID: keyID1, PublicKey: pubKey1}}},
+			serviceIRI, keyID1, "main-key")
+
+		keyID, publicKeyID, err := p.SigningKey()
+		require.NoError(t, err)
+		require.Equal(t, keyID1, keyID)
+		require.Equal(t, "https://orb.domain1.com/services/orb/keys/main-key", publicKeyID)
+	})
+
+	t.Run("Key provider error", func(t *testing.T) {
+		p := NewActivityPubKeys(&mockKeyProvider{err: errors.New("injected error")}, serviceIRI, keyID1, "main-key")
+
+		_, err := p.PublicKeys()
+		require.Error(t, err)
+
+		_, _, err = p.SigningKey()
+		require.Error(t, err)
+	})
+}
+
+type mockKeyProvider struct {
+	keys []*Key
+	err  error
+}
+
+func (m *mockKeyProvider) Keys() ([]*Key, error) {
+	return m.keys, m.err
+}
@@ -0,0 +1,300 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package keyrotation manages the keys with which the domain signs anchor credentials and HTTP requests. When the
+// signing key is rotated, a new key is created in the KMS and becomes the current signing key. The previous key
+// continues to be published (so that existing proofs and signatures may still be verified) until its retirement
+// time.
+package keyrotation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bluele/gcache"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+)
+
+var logger = log.New("key-rotation")
+
+const (
+	// SigningKeysKey is the key of the signing keys in the config store.
+	SigningKeysKey = "signing-keys"
+
+	defaultOverlapPeriod = 30 * 24 * time.Hour
+	defaultCacheExpiry   = time.Minute
+	defaultCacheSize     = 1
+)
+
+// ErrInvalidRetireTime is returned if the retirement time of the previous key isn't in the future.
+var ErrInvalidRetireTime = errors.New("invalid retire time")
+
+// Key contains the details of a signing key.
+type Key struct {
+	// ID is the ID of the key in the KMS. It's also the fragment of the key's verification method
+	// in the domain's DID document.
+	ID string `json:"id"`
+
+	// PublicKey contains the raw bytes of the public key.
+	PublicKey []byte `json:"publicKey"`
+
+	Created time.Time `json:"created"`
+
+	// RetireTime is the time at which the key is no longer published. It is nil for the current key.
+	RetireTime *time.Time `json:"retireTime,omitempty"`
+}
+
+func (k *Key) isRetired(t time.Time) bool {
+	return k.RetireTime != nil && !t.Before(*k.RetireTime)
+}
+
+type keyManager interface {
+	Create(kt kms.KeyType) (string, interface{}, error)
+	ExportPubKeyBytes(keyID string) ([]byte, error)
+}
+
+type gCache interface {
+	Get(key interface{}) (interface{}, error)
+	SetWithExpire(interface{}, interface{}, time.Duration) error
+}
+
+// Option is a key manager option.
+type Option func(m *Manager)
+
+// WithOverlapPeriod sets the default amount of time that the previous key continues to be published
+// after a rotation.
+func WithOverlapPeriod(value time.Duration) Option {
+	return func(m *Manager) {
+		m.overlapPeriod = value
+	}
+}
+
+// WithCacheExpiry sets the expiry of the cached keys. Since the keys are persisted in a (possibly shared)
+// config store, a rotation performed by another server instance is picked up when the cache expires.
+func WithCacheExpiry(value time.Duration) Option {
+	return func(m *Manager) {
+		m.cacheExpiry = value
+	}
+}
+
+// Manager manages the signing keys of the domain.
+type Manager struct {
+	configStore   storage.Store
+	km            keyManager
+	keyType       kms.KeyType
+	overlapPeriod time.Duration
+	cacheExpiry   time.Duration
+	cache         gCache
+	mutex         sync.Mutex
+}
+
+// New returns a new key manager. The keys are loaded from the config store. If no keys have been stored yet
+// then the given key ID (which must exist in the KMS) is stored as the current signing key.
+func New(configStore storage.Store, km keyManager, keyType kms.KeyType, keyID string,
+	opts ...Option) (*Manager, error) {
+	m := &Manager{
+		configStore:   configStore,
+		km:            km,
+		keyType:       keyType,
+		overlapPeriod: defaultOverlapPeriod,
+		cacheExpiry:   defaultCacheExpiry,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	keys, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		keys, err = m.init(keyID)
+		if err != nil {
+			return nil, err
+		}
+	} else if keys[0].ID != keyID {
+		logger.Infof("The current signing key [%s] is different from the configured key [%s] since the key "+
+			"has been rotated", keys[0].ID, keyID)
+	}
+
+	m.cache = gcache.New(defaultCacheSize).ARC().LoaderExpireFunc(
+		func(interface{}) (interface{}, *time.Duration, error) {
+			keys, err := m.load()
+			if err != nil {
+				return nil, nil, err
+			}
+
+			return keys, &m.cacheExpiry, nil
+		},
+	).Build()
+
+	if err := m.cache.SetWithExpire(SigningKeysKey, keys, m.cacheExpiry); err != nil {
+		return nil, fmt.Errorf("set signing keys in cache: %w", err)
+	}
+
+	return m, nil
+}
+
+// CurrentKeyID returns the KMS ID of the current signing key.
+func (m *Manager) CurrentKeyID() (string, error) {
+	keys, err := m.Keys()
+	if err != nil {
+		return "", err
+	}
+
+	return keys[0].ID, nil
+}
+
+// Keys returns the keys which are currently published, i.e. the current signing key followed by the previous
+// keys which haven't been retired yet.
+func (m *Manager) Keys() ([]*Key, error) {
+	value, err := m.cache.Get(SigningKeysKey)
+	if err != nil {
+		return nil, fmt.Errorf("get signing keys from cache: %w", err)
+	}
+
+	keys, ok := value.([]*Key)
+	if !ok || len(keys) == 0 {
+		return nil, fmt.Errorf("unexpected value for signing keys in cache: %v", value)
+	}
+
+	return activeKeys(keys, time.Now()), nil
+}
+
+// Rotate creates a new key in the KMS and makes it the current signing key. The previous key is retired at
+// the given time or, if the time is zero, after the configured overlap period. The published keys
+// are returned. ErrInvalidRetireTime is returned if the given time isn't in the future.
+func (m *Manager) Rotate(retireTime time.Time) ([]*Key, error) {
+	now := time.Now()
+
+	if !retireTime.IsZero() && !retireTime.After(now) {
+		return nil, fmt.Errorf("%w: [%s] must be in the future", ErrInvalidRetireTime,
+			retireTime.Format(time.RFC3339))
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Load the keys from the store (instead of the cache) in case they were rotated by another instance.
+	keys, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no signing keys found")
+	}
+
+	if retireTime.IsZero() {
+		retireTime = now.Add(m.overlapPeriod)
+	}
+
+	newKey, err := m.newKey(now)
+	if err != nil {
+		return nil, err
+	}
+
+	current := keys[0]
+	current.RetireTime = &retireTime
+
+	keys = activeKeys(append([]*Key{newKey}, keys...), now)
+
+	if err := m.store(keys); err != nil {
+		return nil, err
+	}
+
+	if err := m.cache.SetWithExpire(SigningKeysKey, keys, m.cacheExpiry); err != nil {
+		return nil, fmt.Errorf("set signing keys in cache: %w", err)
+	}
+
+	logger.Infof("Rotated signing key. Current key: [%s], Previous key [%s] retires at %s",
+		newKey.ID, current.ID, retireTime)
+
+	return keys, nil
+}
+
+func (m *Manager) init(keyID string) ([]*Key, error) {
+	pubKey, err := m.km.ExportPubKeyBytes(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("export public key [%s]: %w", keyID, err)
+	}
+
+	keys := []*Key{{ID: keyID, PublicKey: pubKey, Created: time.Now()}}
+
+	if err := m.store(keys); err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("Stored initial signing key [%s]", keyID)
+
+	return keys, nil
+}
+
+func (m *Manager) newKey(created time.Time) (*Key, error) {
+	keyID, _, err := m.km.Create(m.keyType)
+	if err != nil {
+		return nil, fmt.Errorf("create key: %w", err)
+	}
+
+	pubKey, err := m.km.ExportPubKeyBytes(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("export public key [%s]: %w", keyID, err)
+	}
+
+	return &Key{ID: keyID, PublicKey: pubKey, Created: created}, nil
+}
+
+func (m *Manager) load() ([]*Key, error) {
+	keysBytes, err := m.configStore.Get(SigningKeysKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get signing keys from config store: %w", err)
+	}
+
+	var keys []*Key
+
+	if err := json.Unmarshal(keysBytes, &keys); err != nil {
+		return nil, fmt.Errorf("unmarshal signing keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (m *Manager) store(keys []*Key) error {
+	keysBytes, err := json.Marshal(keys)
+	if err != nil {
+		return fmt.Errorf("marshal signing keys: %w", err)
+	}
+
+	if err := m.configStore.Put(SigningKeysKey, keysBytes); err != nil {
+		return fmt.Errorf("store signing keys: %w", err)
+	}
+
+	return nil
+}
+
+// activeKeys returns the keys which haven't been retired at the given time.
+func activeKeys(keys []*Key, t time.Time) []*Key {
+	var active []*Key
+
+	for _, key := range keys {
+		if !key.isRetired(t) {
+			active = append(active, key)
+		}
+	}
+
+	return active
+}
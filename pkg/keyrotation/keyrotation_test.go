@@ -0,0 +1,280 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyrotation
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	storemocks "github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	configStoreName = "orb-config"
+	keyID1          = "key1"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("Initial key", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		m, err := New(configStore, newMockKeyManager(), kms.ED25519Type, keyID1)
+		require.NoError(t, err)
+
+		keyID, err := m.CurrentKeyID()
+		require.NoError(t, err)
+		require.Equal(t, keyID1, keyID)
+
+		keys, err := m.Keys()
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, []byte("public-"+keyID1), keys[0].PublicKey)
+		require.Nil(t, keys[0].RetireTime)
+
+		_, err = configStore.Get(SigningKeysKey)
+		require.NoError(t, err)
+	})
+
+	t.Run("Stored keys", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		km := newMockKeyManager()
+
+		m, err := New(configStore, km, kms.ED25519Type, keyID1)
+		require.NoError(t, err)
+
+		_, err = m.Rotate(time.Time{})
+		require.NoError(t, err)
+
+		// The rotated key is used after a restart even though the configured key ID hasn't changed.
+		m, err = New(configStore, km, kms.ED25519Type, keyID1)
+		require.NoError(t, err)
+
+		keyID, err := m.CurrentKeyID()
+		require.NoError(t, err)
+		require.Equal(t, "key2", keyID)
+	})
+
+	t.Run("Export key error", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		km := newMockKeyManager()
+		km.exportErr = errors.New("injected export error")
+
+		_, err = New(configStore, km, kms.ED25519Type, keyID1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected export error")
+	})
+
+	t.Run("Config store error", func(t *testing.T) {
+		configStore := &storemocks.Store{}
+		configStore.GetReturns(nil, fmt.Errorf("injected get error"))
+
+		_, err := New(configStore, newMockKeyManager(), kms.ED25519Type, keyID1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected get error")
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		configStore := &storemocks.Store{}
+		configStore.GetReturns(nil, storage.ErrDataNotFound)
+		configStore.PutReturns(fmt.Errorf("injected put error"))
+
+		_, err := New(configStore, newMockKeyManager(), kms.ED25519Type, keyID1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected put error")
+	})
+
+	t.Run("Unmarshal error", func(t *testing.T) {
+		configStore := &storemocks.Store{}
+		configStore.GetReturns([]byte("{"), nil)
+
+		_, err := New(configStore, newMockKeyManager(), kms.ED25519Type, keyID1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal signing keys")
+	})
+}
+
+func TestManager_Rotate(t *testing.T) {
+	t.Run("Overlap period", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		m, err := New(configStore, newMockKeyManager(), kms.ED25519Type, keyID1, WithOverlapPeriod(time.Hour))
+		require.NoError(t, err)
+
+		keys, err := m.Rotate(time.Time{})
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		require.Equal(t, "key2", keys[0].ID)
+		require.Equal(t, []byte("public-key2"), keys[0].PublicKey)
+		require.Nil(t, keys[0].RetireTime)
+		require.Equal(t, keyID1, keys[1].ID)
+		require.NotNil(t, keys[1].RetireTime)
+		require.True(t, keys[1].RetireTime.After(time.Now().Add(59*time.Minute)))
+
+		keyID, err := m.CurrentKeyID()
+		require.NoError(t, err)
+		require.Equal(t, "key2", keyID)
+
+		keys, err = m.Keys()
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+	})
+
+	t.Run("Retire time", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		m, err := New(configStore, newMockKeyManager(), kms.ED25519Type, keyID1)
+		require.NoError(t, err)
+
+		_, err = m.Rotate(time.Now().Add(50 * time.Millisecond))
+		require.NoError(t, err)
+
+		keys, err := m.Keys()
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+
+		time.Sleep(100 * time.Millisecond)
+
+		// The previous key is no longer published.
+		keys, err = m.Keys()
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, "key2", keys[0].ID)
+
+		// The retired key is removed from the store on the next rotation.
+		keys, err = m.Rotate(time.Time{})
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		require.Equal(t, "key3", keys[0].ID)
+		require.Equal(t, "key2", keys[1].ID)
+	})
+
+	t.Run("Retire time in the past", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		m, err := New(configStore, newMockKeyManager(), kms.ED25519Type, keyID1)
+		require.NoError(t, err)
+
+		_, err = m.Rotate(time.Now().Add(-time.Minute))
+		require.True(t, errors.Is(err, ErrInvalidRetireTime))
+
+		keys, err := m.Keys()
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, keyID1, keys[0].ID)
+	})
+
+	t.Run("Rotated by another instance", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		km := newMockKeyManager()
+
+		m1, err := New(configStore, km, kms.ED25519Type, keyID1, WithCacheExpiry(50*time.Millisecond))
+		require.NoError(t, err)
+
+		m2, err := New(configStore, km, kms.ED25519Type, keyID1)
+		require.NoError(t, err)
+
+		_, err = m2.Rotate(time.Time{})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			keyID, err := m1.CurrentKeyID()
+
+			return err == nil && keyID == "key2"
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Create key error", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		km := newMockKeyManager()
+		km.createErr = errors.New("injected create error")
+
+		m, err := New(configStore, km, kms.ED25519Type, keyID1)
+		require.NoError(t, err)
+
+		_, err = m.Rotate(time.Time{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected create error")
+
+		keyID, err := m.CurrentKeyID()
+		require.NoError(t, err)
+		require.Equal(t, keyID1, keyID)
+	})
+
+	t.Run("Config store error", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		m, err := New(configStore, newMockKeyManager(), kms.ED25519Type, keyID1)
+		require.NoError(t, err)
+
+		keysBytes, err := configStore.Get(SigningKeysKey)
+		require.NoError(t, err)
+
+		errStore := &storemocks.Store{}
+		errStore.GetReturns(keysBytes, nil)
+		errStore.PutReturns(errors.New("injected put error"))
+
+		m.configStore = errStore
+
+		_, err = m.Rotate(time.Time{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected put error")
+
+		errStore.GetReturns(nil, storage.ErrDataNotFound)
+
+		_, err = m.Rotate(time.Time{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no signing keys found")
+	})
+}
+
+type mockKeyManager struct {
+	numKeys   int
+	createErr error
+	exportErr error
+}
+
+func newMockKeyManager() *mockKeyManager {
+	return &mockKeyManager{numKeys: 1}
+}
+
+func (m *mockKeyManager) Create(kms.KeyType) (string, interface{}, error) {
+	if m.createErr != nil {
+		return "", nil, m.createErr
+	}
+
+	m.numKeys++
+
+	return fmt.Sprintf("key%d", m.numKeys), nil, nil
+}
+
+func (m *mockKeyManager) ExportPubKeyBytes(keyID string) ([]byte, error) {
+	if m.exportErr != nil {
+		return nil, m.exportErr
+	}
+
+	return []byte("public-" + keyID), nil
+}
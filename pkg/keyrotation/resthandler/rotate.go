@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/keyrotation"
+)
+
+const (
+	endpoint = "/keys/rotate"
+
+	retireParam = "retire"
+)
+
+const (
+	badRequestResponse          = "Bad Request."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+var logger = log.New("key-rotation-rest-handler")
+
+type rotator interface {
+	Rotate(retireTime time.Time) ([]*keyrotation.Key, error)
+}
+
+// Rotate rotates the signing key and returns the published keys. The 'retire' parameter optionally specifies
+// the time (in RFC3339 format) at which the previous key is retired, which must be in the future. If not set then
+// the previous key is retired after the overlap period configured on the server.
+type Rotate struct {
+	rotator rotator
+}
+
+// NewRotate returns a new handler that rotates the signing key.
+func NewRotate(r rotator) *Rotate {
+	return &Rotate{rotator: r}
+}
+
+// Path returns the HTTP REST endpoint for the rotate service.
+func (h *Rotate) Path() string {
+	return endpoint
+}
+
+// Method returns the HTTP REST method for the rotate service.
+func (h *Rotate) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the rotate service.
+func (h *Rotate) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Rotate) handle(w http.ResponseWriter, req *http.Request) {
+	var retireTime time.Time
+
+	if value := getParam(req, retireParam); value != "" {
+		var err error
+
+		retireTime, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			logger.Infof("[%s] Invalid value for parameter '%s': %s", endpoint, retireParam, value)
+
+			writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+			return
+		}
+	}
+
+	keys, err := h.rotator.Rotate(retireTime)
+	if err != nil {
+		if errors.Is(err, keyrotation.ErrInvalidRetireTime) {
+			logger.Infof("[%s] Invalid value for parameter '%s': %s", endpoint, retireParam, err)
+
+			writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error rotating signing key: %s", endpoint, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeJSONResponse(w, keys)
+}
+
+func getParam(req *http.Request, name string) string {
+	values := req.URL.Query()[name]
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+func writeJSONResponse(w http.ResponseWriter, v interface{}) {
+	respBytes, err := json.Marshal(v)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling response: %s", endpoint, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", endpoint, err)
+
+			return
+		}
+
+		logger.Debugf("[%s] Wrote response: %s", endpoint, body)
+	}
+}
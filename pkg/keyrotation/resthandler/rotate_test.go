@@ -0,0 +1,125 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/keyrotation"
+)
+
+func TestNewRotate(t *testing.T) {
+	h := NewRotate(&mockRotator{})
+	require.Equal(t, endpoint, h.Path())
+	require.Equal(t, http.MethodPost, h.Method())
+	require.NotNil(t, h.Handler())
+}
+
+func TestRotate_Handler(t *testing.T) {
+	retireTime := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	keys := []*keyrotation.Key{
+		{ID: "key2", PublicKey: []byte("public key 2")},
+		{ID: "key1", PublicKey: []byte("public key 1"), RetireTime: &retireTime},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		r := &mockRotator{keys: keys}
+
+		rw := httptest.NewRecorder()
+
+		NewRotate(r).Handler()(rw, httptest.NewRequest(http.MethodPost, endpoint, nil))
+
+		result := rw.Result()
+		defer result.Body.Close() //nolint: errcheck
+
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.True(t, r.retireTime.IsZero())
+
+		var respKeys []*keyrotation.Key
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&respKeys))
+		require.Len(t, respKeys, 2)
+		require.Equal(t, "key2", respKeys[0].ID)
+		require.Nil(t, respKeys[0].RetireTime)
+		require.Equal(t, "key1", respKeys[1].ID)
+		require.True(t, retireTime.Equal(*respKeys[1].RetireTime))
+	})
+
+	t.Run("Retire time", func(t *testing.T) {
+		r := &mockRotator{keys: keys}
+
+		rw := httptest.NewRecorder()
+
+		NewRotate(r).Handler()(rw, httptest.NewRequest(http.MethodPost,
+			endpoint+"?retire="+retireTime.Format(time.RFC3339), nil))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.True(t, retireTime.Equal(r.retireTime))
+	})
+
+	t.Run("Invalid retire parameter", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		NewRotate(&mockRotator{}).Handler()(rw, httptest.NewRequest(http.MethodPost, endpoint+"?retire=xxx", nil))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+	})
+
+	t.Run("Retire time in the past", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		NewRotate(&mockRotator{err: fmt.Errorf("%w: in the past", keyrotation.ErrInvalidRetireTime)}).Handler()(rw,
+			httptest.NewRequest(http.MethodPost, endpoint+"?retire="+time.Now().Add(-time.Hour).Format(time.RFC3339), nil))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+	})
+
+	t.Run("Rotator error", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		NewRotate(&mockRotator{err: errors.New("injected error")}).Handler()(rw,
+			httptest.NewRequest(http.MethodPost, endpoint, nil))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+	})
+}
+
+type mockRotator struct {
+	keys       []*keyrotation.Key
+	err        error
+	retireTime time.Time
+}
+
+func (m *mockRotator) Rotate(retireTime time.Time) ([]*keyrotation.Key, error) {
+	m.retireTime = retireTime
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return m.keys, nil
+}
@@ -23,6 +23,7 @@ const (
 	apResolveInboxesTimeMetric    = "outbox_resolve_inboxes_seconds"
 	apInboxHandlerTimeMetric      = "inbox_handler_seconds"
 	apOutboxActivityCounterMetric = "outbox_count"
+	apDeadLetterDepthMetric       = "dead_letter_depth"
 
 	// Anchor.
 	anchor                                         = "anchor"
@@ -61,6 +62,7 @@ const (
 	casResolveTimeMetric   = "resolve_seconds"
 	casCacheHitCountMetric = "cache_hit_count"
 	casReadTimeMetric      = "read_seconds"
+	casCorruptCountMetric  = "corrupt_count"
 
 	// Document handler.
 	document                  = "document"
@@ -106,6 +108,7 @@ type Metrics struct {
 	apOutboxResolveInboxesTime prometheus.Histogram
 	apInboxHandlerTimes        map[string]prometheus.Histogram
 	apOutboxActivityCounts     map[string]prometheus.Counter
+	apDeadLetterDepth          prometheus.Gauge
 
 	anchorWriteTime                          prometheus.Histogram
 	anchorWitnessTime                        prometheus.Histogram
@@ -136,6 +139,7 @@ type Metrics struct {
 	casResolveTime   prometheus.Histogram
 	casCacheHitCount prometheus.Counter
 	casReadTimes     map[string]prometheus.Histogram
+	casCorruptCount  prometheus.Counter
 
 	docCreateUpdateTime prometheus.Histogram
 	docResolveTime      prometheus.Histogram
@@ -175,6 +179,7 @@ func newMetrics() *Metrics { //nolint:funlen
 	m := &Metrics{
 		apOutboxPostTime:                         newOutboxPostTime(),
 		apOutboxResolveInboxesTime:               newOutboxResolveInboxesTime(),
+		apDeadLetterDepth:                        newDeadLetterDepth(),
 		anchorWriteTime:                          newAnchorWriteTime(),
 		anchorWriteBuildCredTime:                 newAnchorWriteBuildCredTime(),
 		anchorWriteGetWitnessesTime:              newAnchorWriteGetWitnessesTime(),
@@ -201,6 +206,7 @@ func newMetrics() *Metrics { //nolint:funlen
 		casResolveTime:                           newCASResolveTime(),
 		casReadTimes:                             newCASReadTimes(),
 		casCacheHitCount:                         newCASCacheHitCount(),
+		casCorruptCount:                          newCASCorruptCount(),
 		docCreateUpdateTime:                      newDocCreateUpdateTime(),
 		docResolveTime:                           newDocResolveTime(),
 		apInboxHandlerTimes:                      newInboxHandlerTimes(activityTypes),
@@ -224,7 +230,7 @@ func newMetrics() *Metrics { //nolint:funlen
 	}
 
 	prometheus.MustRegister(
-		m.apOutboxPostTime, m.apOutboxResolveInboxesTime,
+		m.apOutboxPostTime, m.apOutboxResolveInboxesTime, m.apDeadLetterDepth,
 		m.anchorWriteTime, m.anchorWitnessTime, m.anchorProcessWitnessedTime, m.anchorWriteBuildCredTime,
 		m.anchorWriteGetWitnessesTime, m.anchorWriteSignCredTime, m.anchorWritePostOfferActivityTime,
 		m.anchorWriteGetPreviousAnchorsGetBulkTime, m.anchorWriteGetPreviousAnchorsTime,
@@ -233,7 +239,7 @@ func newMetrics() *Metrics { //nolint:funlen
 		m.opqueueAddOperationTime, m.opqueueBatchCutTime, m.opqueueBatchRollbackTime,
 		m.opqueueBatchAckTime, m.opqueueBatchNackTime, m.opqueueBatchSize,
 		m.observerProcessAnchorTime, m.observerProcessDIDTime,
-		m.casWriteTime, m.casResolveTime, m.casCacheHitCount,
+		m.casWriteTime, m.casResolveTime, m.casCacheHitCount, m.casCorruptCount,
 		m.docCreateUpdateTime, m.docResolveTime,
 		m.vctWitnessAddProofVCTNilTimes, m.vctWitnessAddVCTimes, m.vctWitnessAddProofTimes,
 		m.vctWitnessAddWebFingerTimes, m.vctWitnessVerifyVCTimes, m.vctAddProofParseCredentialTimes,
@@ -301,6 +307,13 @@ func (m *Metrics) OutboxIncrementActivityCount(activityType string) {
 	}
 }
 
+// DeadLetterDepth records the number of undeliverable activities in the dead-letter store.
+func (m *Metrics) DeadLetterDepth(value int) {
+	m.apDeadLetterDepth.Set(float64(value))
+
+	logger.Debugf("DeadLetterDepth: %d", value)
+}
+
 // WriteAnchorTime records the time it takes to write an anchor credential and post an 'Offer' activity.
 func (m *Metrics) WriteAnchorTime(value time.Duration) {
 	m.anchorWriteTime.Observe(value.Seconds())
@@ -487,6 +500,11 @@ func (m *Metrics) CASReadTime(casType string, value time.Duration) {
 	}
 }
 
+// CASIncrementCorruptCount increments the number of corrupt CAS entries found by the integrity scrubber.
+func (m *Metrics) CASIncrementCorruptCount() {
+	m.casCorruptCount.Inc()
+}
+
 // DocumentCreateUpdateTime records the time it takes the REST handler to process a create/update operation.
 func (m *Metrics) DocumentCreateUpdateTime(value time.Duration) {
 	m.docCreateUpdateTime.Observe(value.Seconds())
@@ -670,6 +688,13 @@ func newOutboxResolveInboxesTime() prometheus.Histogram {
 	)
 }
 
+func newDeadLetterDepth() prometheus.Gauge {
+	return newGauge(
+		activityPub, apDeadLetterDepthMetric,
+		"The number of undeliverable activities in the dead-letter store.",
+	)
+}
+
 func newInboxHandlerTimes(activityTypes []string) map[string]prometheus.Histogram {
 	counters := make(map[string]prometheus.Histogram)
 
@@ -902,10 +927,18 @@ func newCASCacheHitCount() prometheus.Counter {
 	)
 }
 
+func newCASCorruptCount() prometheus.Counter {
+	return newCounter(
+		cas, casCorruptCountMetric,
+		"The number of CAS entries whose content doesn't match their hash.",
+		nil,
+	)
+}
+
 func newCASReadTimes() map[string]prometheus.Histogram {
 	times := make(map[string]prometheus.Histogram)
 
-	for _, casType := range []string{"local", "ipfs"} {
+	for _, casType := range []string{"local", "ipfs", "disk", "s3"} {
 		times[casType] = newHistogram(
 			cas, casReadTimeMetric,
 			"The time (in seconds) that it takes to read a document from the CAS storage.",
@@ -48,10 +48,12 @@ func TestMetrics(t *testing.T) {
 		require.NotPanics(t, func() { m.CASWriteTime(time.Second) })
 		require.NotPanics(t, func() { m.CASResolveTime(time.Second) })
 		require.NotPanics(t, func() { m.CASIncrementCacheHitCount() })
+		require.NotPanics(t, func() { m.CASIncrementCorruptCount() })
 		require.NotPanics(t, func() { m.CASReadTime("local", time.Second) })
 		require.NotPanics(t, func() { m.DocumentCreateUpdateTime(time.Second) })
 		require.NotPanics(t, func() { m.DocumentResolveTime(time.Second) })
 		require.NotPanics(t, func() { m.OutboxIncrementActivityCount("Create") })
+		require.NotPanics(t, func() { m.DeadLetterDepth(5) })
 		require.NotPanics(t, func() { m.DBPutTime("CouchDB", time.Second) })
 		require.NotPanics(t, func() { m.DBGetTime("CouchDB", time.Second) })
 		require.NotPanics(t, func() { m.DBGetTagsTime("CouchDB", time.Second) })
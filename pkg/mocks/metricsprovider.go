@@ -130,6 +130,10 @@ func (m *MetricsProvider) DocumentResolveTime(value time.Duration) {
 func (m *MetricsProvider) OutboxIncrementActivityCount(activityType string) {
 }
 
+// DeadLetterDepth records the number of undeliverable activities in the dead-letter store.
+func (m *MetricsProvider) DeadLetterDepth(int) {
+}
+
 // CASIncrementCacheHitCount increments the number of CAS cache hits.
 func (m *MetricsProvider) CASIncrementCacheHitCount() {
 }
@@ -138,6 +142,10 @@ func (m *MetricsProvider) CASIncrementCacheHitCount() {
 func (m *MetricsProvider) CASReadTime(casType string, value time.Duration) {
 }
 
+// CASIncrementCorruptCount increments the number of corrupt CAS entries found by the integrity scrubber.
+func (m *MetricsProvider) CASIncrementCorruptCount() {
+}
+
 // BatchSize records the size of an operation batch.
 func (m *MetricsProvider) BatchSize(float64) {
 }
@@ -6,9 +6,15 @@ SPDX-License-Identifier: Apache-2.0
 
 package nodeinfo
 
+import (
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+)
+
 const (
 	activityPubProtocol = "activitypub"
 	orbRepository       = "https://github.com/trustbloc/orb"
+
+	protocolVersionsMetadataKey = "protocolVersions"
 )
 
 // Version specified the version of the NodeInfo data.
@@ -57,3 +63,10 @@ type Usage struct {
 type Users struct {
 	Total int `json:"total"`
 }
+
+// ProtocolVersion contains the parameters of a Sidetree protocol version that is supported by the node.
+// The protocol versions are advertised in the metadata of the NodeInfo data.
+type ProtocolVersion struct {
+	Version    string            `json:"version"`
+	Parameters protocol.Protocol `json:"parameters"`
+}
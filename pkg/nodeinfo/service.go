@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
 
 	apstore "github.com/trustbloc/orb/pkg/activitypub/store/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
@@ -42,10 +43,27 @@ type Service struct {
 	apStore    apstore.Store
 	stats      *stats
 	mutex      sync.RWMutex
+	protocols  []*ProtocolVersion
+}
+
+// Option is a NodeInfo service option.
+type Option func(s *Service)
+
+// WithProtocolVersions sets the Sidetree protocol versions (and their parameters) that are
+// advertised in the NodeInfo metadata.
+func WithProtocolVersions(versions []protocol.Version) Option {
+	return func(s *Service) {
+		for _, v := range versions {
+			s.protocols = append(s.protocols, &ProtocolVersion{
+				Version:    v.Version(),
+				Parameters: v.Protocol(),
+			})
+		}
+	}
 }
 
 // NewService returns a new NodeInfo service.
-func NewService(apStore apstore.Store, serviceIRI *url.URL, refreshInterval time.Duration) *Service {
+func NewService(apStore apstore.Store, serviceIRI *url.URL, refreshInterval time.Duration, opts ...Option) *Service {
 	r := &Service{
 		apStore:    apStore,
 		serviceIRI: serviceIRI,
@@ -54,6 +72,10 @@ func NewService(apStore apstore.Store, serviceIRI *url.URL, refreshInterval time
 		stats:      &stats{},
 	}
 
+	for _, opt := range opts {
+		opt(r)
+	}
+
 	r.Lifecycle = lifecycle.New("nodeinfo",
 		lifecycle.WithStart(r.start),
 		lifecycle.WithStop(r.stop))
@@ -75,6 +97,14 @@ func (r *Service) GetNodeInfo(version Version) *NodeInfo {
 
 	r.mutex.RUnlock()
 
+	var metadata map[string]interface{}
+
+	if len(r.protocols) > 0 {
+		metadata = map[string]interface{}{
+			protocolVersionsMetadataKey: r.protocols,
+		}
+	}
+
 	return &NodeInfo{
 		Version:   version,
 		Protocols: []string{activityPubProtocol},
@@ -95,6 +125,7 @@ func (r *Service) GetNodeInfo(version Version) *NodeInfo {
 			LocalPosts:    int(stats.Posts),
 			LocalComments: int(stats.Comments),
 		},
+		Metadata: metadata,
 	}
 }
 
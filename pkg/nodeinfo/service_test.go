@@ -12,6 +12,8 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	coremocks "github.com/trustbloc/sidetree-core-go/pkg/mocks"
 
 	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
 	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
@@ -78,3 +80,28 @@ func TestService(t *testing.T) {
 	require.Equal(t, numCreates, nodeInfo.Usage.LocalPosts)
 	require.Equal(t, numLikes, nodeInfo.Usage.LocalComments)
 }
+
+func TestService_WithProtocolVersions(t *testing.T) {
+	v1_0 := &coremocks.ProtocolVersion{}
+	v1_0.VersionReturns("1.0")
+	v1_0.ProtocolReturns(protocol.Protocol{GenesisTime: 0, MaxOperationCount: 100})
+
+	v1_1 := &coremocks.ProtocolVersion{}
+	v1_1.VersionReturns("1.1")
+	v1_1.ProtocolReturns(protocol.Protocol{GenesisTime: 1000, MaxOperationCount: 200})
+
+	s := NewService(memstore.New(""), testutil.MustParseURL("https://example.com/services/orb"), time.Second,
+		WithProtocolVersions([]protocol.Version{v1_0, v1_1}))
+	require.NotNil(t, s)
+
+	nodeInfo := s.GetNodeInfo(V2_1)
+	require.NotNil(t, nodeInfo)
+
+	protocols, ok := nodeInfo.Metadata[protocolVersionsMetadataKey].([]*ProtocolVersion)
+	require.True(t, ok)
+	require.Len(t, protocols, 2)
+	require.Equal(t, "1.0", protocols[0].Version)
+	require.Equal(t, uint(100), protocols[0].Parameters.MaxOperationCount)
+	require.Equal(t, "1.1", protocols[1].Version)
+	require.Equal(t, uint64(1000), protocols[1].Parameters.GenesisTime)
+}
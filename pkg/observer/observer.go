@@ -48,6 +48,10 @@ type didAnchors interface {
 	PutBulk(dids []string, cid string) error
 }
 
+type referenceTracker interface {
+	AddAnchorReferences(anchorHL, coreIndexURI, compressionAlg string, suffixes []string) error
+}
+
 // Publisher publishes anchors and DIDs to a message queue for processing.
 type Publisher interface {
 	PublishAnchor(anchor *anchorinfo.AnchorInfo) error
@@ -75,6 +79,14 @@ func WithDiscoveryDomain(domain string) Option {
 	}
 }
 
+// WithReferenceTracker sets the tracker which adds references from processed anchors to the CAS content that
+// they use (so that unreferenced CAS content may be garbage collected).
+func WithReferenceTracker(tracker referenceTracker) Option {
+	return func(opts *Observer) {
+		opts.refTracker = tracker
+	}
+}
+
 // Providers contains all of the providers required by the TxnProcessor.
 type Providers struct {
 	ProtocolClientProvider protocol.ClientProvider
@@ -90,6 +102,7 @@ type Observer struct {
 
 	pubSub          *PubSub
 	discoveryDomain string
+	refTracker      referenceTracker
 }
 
 // New returns a new observer.
@@ -266,6 +279,14 @@ func (o *Observer) processAnchor(anchor *anchorinfo.AnchorInfo, info *verifiable
 		return fmt.Errorf("failed updating did anchor references for anchor credential[%s]: %w", anchor.Hashlink, err)
 	}
 
+	if o.refTracker != nil {
+		err = o.refTracker.AddAnchorReferences(anchor.Hashlink, anchorPayload.CoreIndex,
+			v.Protocol().CompressionAlgorithm, acSuffixes)
+		if err != nil {
+			return fmt.Errorf("failed adding CAS references for anchor credential[%s]: %w", anchor.Hashlink, err)
+		}
+	}
+
 	logger.Infof("Successfully processed %d DIDs in anchor[%s], core index[%s]",
 		len(acSuffixes), anchor.Hashlink, anchorPayload.CoreIndex)
 
@@ -668,6 +668,62 @@ func TestStartObserver(t *testing.T) {
 	})
 }
 
+func TestObserver_ReferenceTracker(t *testing.T) {
+	const namespace = "did:orb"
+
+	pc := mocks.NewMockProtocolClient()
+	pc.Protocol.GenesisTime = 1
+	pc.Protocol.CompressionAlgorithm = "GZIP"
+	pc.Versions[0].TransactionProcessorReturns(&mocks.TxnProcessor{})
+	pc.Versions[0].ProtocolReturns(pc.Protocol)
+
+	payload := subject.Payload{
+		Namespace: namespace, Version: 1, CoreIndex: "hl:core1", PreviousAnchors: map[string]string{"did1": ""},
+	}
+
+	c, err := buildCredential(&payload)
+	require.NoError(t, err)
+
+	vcBytes, err := c.MarshalJSON()
+	require.NoError(t, err)
+
+	vc, err := verifiable.ParseCredential(vcBytes, verifiable.WithDisabledProofCheck(),
+		verifiable.WithJSONLDDocumentLoader(testutil.GetLoader(t)))
+	require.NoError(t, err)
+
+	anchor := &anchorinfo.AnchorInfo{Hashlink: "hl:uEiD1X_FvZvQzYCZrldtvj-wB12AxBUMGrkpLOAWY9s_RFA"}
+
+	providers := &Providers{
+		ProtocolClientProvider: mocks.NewMockProtocolClientProvider().WithProtocolClient(namespace, pc),
+		DidAnchors:             memdidanchor.New(),
+		PubSub:                 mempubsub.New(mempubsub.DefaultConfig()),
+		Metrics:                &orbmocks.MetricsProvider{},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		tracker := &mockReferenceTracker{}
+
+		o, err := New(providers, WithReferenceTracker(tracker))
+		require.NoError(t, err)
+
+		require.NoError(t, o.processAnchor(anchor, vc))
+
+		require.Equal(t, anchor.Hashlink, tracker.anchorHL)
+		require.Equal(t, "hl:core1", tracker.coreIndexURI)
+		require.Equal(t, "GZIP", tracker.compressionAlg)
+		require.Equal(t, []string{"did1"}, tracker.suffixes)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		o, err := New(providers, WithReferenceTracker(&mockReferenceTracker{err: errors.New("injected tracker error")}))
+		require.NoError(t, err)
+
+		err = o.processAnchor(anchor, vc)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected tracker error")
+	})
+}
+
 func buildCredential(payload *subject.Payload) (*verifiable.Credential, error) {
 	const defVCContext = "https://www.w3.org/2018/credentials/v1"
 
@@ -704,3 +760,25 @@ func (m *mockDidAnchor) PutBulk(_ []string, _ string) error {
 
 	return nil
 }
+
+type mockReferenceTracker struct {
+	anchorHL       string
+	coreIndexURI   string
+	compressionAlg string
+	suffixes       []string
+	err            error
+}
+
+func (m *mockReferenceTracker) AddAnchorReferences(anchorHL, coreIndexURI, compressionAlg string,
+	suffixes []string) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.anchorHL = anchorHL
+	m.coreIndexURI = coreIndexURI
+	m.compressionAlg = compressionAlg
+	m.suffixes = suffixes
+
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/piprate/json-gold/ld"
 	"github.com/trustbloc/edge-core/pkg/log"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
 	txnapi "github.com/trustbloc/sidetree-core-go/pkg/api/txn"
 
 	anchorinfo "github.com/trustbloc/orb/pkg/anchor/info"
@@ -21,6 +22,7 @@ import (
 	"github.com/trustbloc/orb/pkg/orbclient/nsprovider"
 	"github.com/trustbloc/orb/pkg/orbclient/verprovider"
 	"github.com/trustbloc/orb/pkg/protocolversion/clientregistry"
+	"github.com/trustbloc/orb/pkg/vcsigner"
 )
 
 var logger = log.New("orb-client")
@@ -32,6 +34,7 @@ type OrbClient struct {
 	docLoader         ld.DocumentLoader
 	casReader         common.CASReader
 	disableProofCheck bool
+	registryOpts      []clientregistry.Option
 }
 
 type namespaceProvider interface {
@@ -62,15 +65,39 @@ func WithDisableProofCheck(disableProofCheck bool) Option {
 	}
 }
 
+// WithGenesisTime overrides the activation genesis time (in seconds since the Unix epoch) of the given
+// protocol version. The client must be configured with the same genesis times as the nodes of the network
+// in order to parse operations that were anchored with a later version.
+func WithGenesisTime(version string, genesisTime uint64) Option {
+	return func(opts *OrbClient) {
+		opts.registryOpts = append(opts.registryOpts, clientregistry.WithGenesisTime(version, genesisTime))
+	}
+}
+
+// WithProtocolConfig overrides the default protocol parameters of the given protocol version. If a genesis time
+// is also specified for the version using WithGenesisTime then that genesis time takes precedence.
+func WithProtocolConfig(version string, p protocol.Protocol) Option {
+	return func(opts *OrbClient) {
+		opts.registryOpts = append(opts.registryOpts, clientregistry.WithProtocolConfig(version, p))
+	}
+}
+
 // New creates new Orb client.
 func New(namespace string, cas common.CASReader, opts ...Option) (*OrbClient, error) {
-	versions := []string{"1.0"}
+	orbClient := &OrbClient{
+		casReader: cas,
+	}
+
+	// apply options
+	for _, opt := range opts {
+		opt(orbClient)
+	}
 
-	registry := clientregistry.New()
+	registry := clientregistry.New(orbClient.registryOpts...)
 
 	var clientVersions []common.ClientVersion
 
-	for _, version := range versions {
+	for _, version := range registry.Versions() {
 		cv, err := registry.CreateClientVersion(version, cas)
 		if err != nil {
 			return nil, fmt.Errorf("error creating client version [%s]: %w", version, err)
@@ -82,15 +109,7 @@ func New(namespace string, cas common.CASReader, opts ...Option) (*OrbClient, er
 	nsProvider := nsprovider.New()
 	nsProvider.Add(namespace, verprovider.New(clientVersions))
 
-	orbClient := &OrbClient{
-		nsProvider: nsProvider,
-		casReader:  cas,
-	}
-
-	// apply options
-	for _, opt := range opts {
-		opt(orbClient)
-	}
+	orbClient.nsProvider = nsProvider
 
 	return orbClient, nil
 }
@@ -105,7 +124,7 @@ func (c *OrbClient) GetAnchorOrigin(cid, suffix string) (interface{}, error) {
 
 	logger.Debugf("read anchor[%s]: %s", cid, string(anchorBytes))
 
-	info, err := verifiable.ParseCredential(anchorBytes, c.getParseCredentialOpts()...)
+	info, err := c.parseCredential(anchorBytes)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse verifiable credential from CID[%s] from CAS: %w", cid, err)
 	}
@@ -122,21 +141,31 @@ func (c *OrbClient) GetAnchorOrigin(cid, suffix string) (interface{}, error) {
 	return suffixOp.AnchorOrigin, nil
 }
 
-func (c *OrbClient) getParseCredentialOpts() []verifiable.CredentialOpt {
-	var opts []verifiable.CredentialOpt
-	if c.publicKeyFetcher != nil {
-		opts = append(opts, verifiable.WithPublicKeyFetcher(c.publicKeyFetcher))
-	}
+// parseCredential parses the anchor credential and, unless the proof check is disabled, verifies its proofs.
+// The proofs are verified separately since the proof check of the verifiable package doesn't support
+// all of the signature suites that may be used to sign anchor credentials.
+func (c *OrbClient) parseCredential(anchorBytes []byte) (*verifiable.Credential, error) {
+	opts := []verifiable.CredentialOpt{verifiable.WithDisabledProofCheck()}
 
 	if c.docLoader != nil {
 		opts = append(opts, verifiable.WithJSONLDDocumentLoader(c.docLoader))
 	}
 
+	vc, err := verifiable.ParseCredential(anchorBytes, opts...)
+	if err != nil {
+		return nil, err
+	}
+
 	if c.disableProofCheck {
-		opts = append(opts, verifiable.WithDisabledProofCheck())
+		return vc, nil
+	}
+
+	err = vcsigner.VerifyProofs(anchorBytes, c.publicKeyFetcher, c.docLoader)
+	if err != nil {
+		return nil, err
 	}
 
-	return opts
+	return vc, nil
 }
 
 func (c *OrbClient) getAnchoredOperation(anchor anchorinfo.AnchorInfo, info *verifiable.Credential, suffix string) (*operation.AnchoredOperation, error) { //nolint:lll
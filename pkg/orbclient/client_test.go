@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package orbclient
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -23,10 +24,62 @@ import (
 	cvmocks "github.com/trustbloc/orb/pkg/mocks"
 	"github.com/trustbloc/orb/pkg/orbclient/mocks"
 	"github.com/trustbloc/orb/pkg/orbclient/nsprovider"
+	v1_1cfg "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_1/config"
 )
 
 const testDID = "did"
 
+func TestNew(t *testing.T) {
+	const genesisTime = 1767225600
+
+	t.Run("default genesis times", func(t *testing.T) {
+		client, err := New(testDID, coremocks.NewMockCasClient(nil))
+		require.NoError(t, err)
+
+		vp, err := client.nsProvider.ForNamespace(testDID)
+		require.NoError(t, err)
+
+		cv, err := vp.Get(genesisTime)
+		require.NoError(t, err)
+		require.Equal(t, "1.0", cv.Version())
+	})
+
+	t.Run("with genesis time", func(t *testing.T) {
+		client, err := New(testDID, coremocks.NewMockCasClient(nil), WithGenesisTime("1.1", genesisTime))
+		require.NoError(t, err)
+
+		vp, err := client.nsProvider.ForNamespace(testDID)
+		require.NoError(t, err)
+
+		cv, err := vp.Get(genesisTime - 1)
+		require.NoError(t, err)
+		require.Equal(t, "1.0", cv.Version())
+
+		cv, err = vp.Get(genesisTime)
+		require.NoError(t, err)
+		require.Equal(t, "1.1", cv.Version())
+	})
+}
+
+func TestNew_WithProtocolConfig(t *testing.T) {
+	const genesisTime = 1767225600
+
+	p := v1_1cfg.GetProtocolConfig()
+	p.GenesisTime = genesisTime
+	p.MaxOperationCount = 100
+
+	client, err := New(testDID, coremocks.NewMockCasClient(nil), WithProtocolConfig("1.1", p))
+	require.NoError(t, err)
+
+	vp, err := client.nsProvider.ForNamespace(testDID)
+	require.NoError(t, err)
+
+	cv, err := vp.Get(genesisTime)
+	require.NoError(t, err)
+	require.Equal(t, "1.1", cv.Version())
+	require.Equal(t, uint(100), cv.Protocol().MaxOperationCount)
+}
+
 func TestGetAnchorOrigin(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		previousDIDTxns := make(map[string]string)
@@ -253,6 +306,46 @@ func TestGetAnchorOrigin(t *testing.T) {
 		require.Contains(t, err.Error(), "failed to get client versions for namespace [did:test]")
 	})
 
+	t.Run("error - proof verification failed", func(t *testing.T) {
+		c, err := buildCredential(&subject.Payload{
+			OperationCount:  1,
+			CoreIndex:       "coreIndex",
+			Namespace:       "did:orb",
+			Version:         1,
+			PreviousAnchors: map[string]string{testDID: ""},
+		})
+		require.NoError(t, err)
+
+		c.Proofs = []verifiable.Proof{{
+			"type":               "Ed25519Signature2020",
+			"created":            "2021-01-27T09:30:10Z",
+			"proofPurpose":       "assertionMethod",
+			"proofValue":         "zabc",
+			"verificationMethod": "did:web:orb.domain.com#key1",
+		}}
+
+		vcBytes, err := c.MarshalJSON()
+		require.NoError(t, err)
+
+		casClient := coremocks.NewMockCasClient(nil)
+
+		cid, err := casClient.Write(vcBytes)
+		require.NoError(t, err)
+
+		client, err := New("did:orb", casClient,
+			WithPublicKeyFetcher(func(issuerID, keyID string) (*verifier.PublicKey, error) {
+				return nil, fmt.Errorf("injected fetcher error")
+			}),
+			WithJSONLDDocumentLoader(testutil.GetLoader(t)))
+		require.NoError(t, err)
+
+		origin, err := client.GetAnchorOrigin(cid, testDID)
+		require.Error(t, err)
+		require.Empty(t, origin)
+		require.Contains(t, err.Error(), "unable to parse verifiable credential")
+		require.Contains(t, err.Error(), "injected fetcher error")
+	})
+
 	t.Run("error - anchor (cid) not found", func(t *testing.T) {
 		casClient := coremocks.NewMockCasClient(nil)
 
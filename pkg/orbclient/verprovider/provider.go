@@ -9,6 +9,7 @@ package verprovider
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/trustbloc/edge-core/pkg/log"
 
@@ -20,6 +21,7 @@ var logger = log.New("client-version-provider")
 // ClientVersionProvider implements client versions.
 type ClientVersionProvider struct {
 	versions []common.ClientVersion
+	now      func() time.Time
 }
 
 // New creates new client version provider.
@@ -36,14 +38,22 @@ func New(clientVersions []common.ClientVersion) *ClientVersionProvider {
 
 	return &ClientVersionProvider{
 		versions: versions,
+		now:      time.Now,
 	}
 }
 
-// Current returns the latest version of client.
+// Current returns the latest version of client that has been activated, i.e. the latest version
+// whose genesis time (in seconds since the Unix epoch) is not in the future.
 func (c *ClientVersionProvider) Current() (common.ClientVersion, error) {
-	latest := len(c.versions) - 1
+	now := uint64(c.now().Unix())
 
-	return c.versions[latest], nil
+	for i := len(c.versions) - 1; i >= 0; i-- {
+		if c.versions[i].Protocol().GenesisTime <= now {
+			return c.versions[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("no client version is active at time %d", now)
 }
 
 // Get gets client version based on version time.
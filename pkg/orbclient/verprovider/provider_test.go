@@ -8,6 +8,7 @@ package verprovider
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
@@ -48,6 +49,47 @@ func TestClientVersionProvider_Current(t *testing.T) {
 	require.Equal(t, uint(10000), v.Protocol().MaxOperationCount)
 }
 
+func TestClientVersionProvider_CurrentSwitchover(t *testing.T) {
+	const genesisTime = 1000
+
+	v1_0 := &mocks.ClientVersion{}
+	v1_0.VersionReturns("1.0")
+	v1_0.ProtocolReturns(protocol.Protocol{GenesisTime: 0})
+
+	v1_1 := &mocks.ClientVersion{}
+	v1_1.VersionReturns("1.1")
+	v1_1.ProtocolReturns(protocol.Protocol{GenesisTime: genesisTime})
+
+	c := New([]common.ClientVersion{v1_1, v1_0})
+
+	c.now = func() time.Time { return time.Unix(genesisTime-1, 0) }
+
+	v, err := c.Current()
+	require.NoError(t, err)
+	require.Equal(t, "1.0", v.Version())
+
+	c.now = func() time.Time { return time.Unix(genesisTime, 0) }
+
+	v, err = c.Current()
+	require.NoError(t, err)
+	require.Equal(t, "1.1", v.Version())
+
+	// Anchors created with the previous version must still be processed by that version.
+	v, err = c.Get(0)
+	require.NoError(t, err)
+	require.Equal(t, "1.0", v.Version())
+
+	t.Run("no active version", func(t *testing.T) {
+		c := New([]common.ClientVersion{v1_1})
+		c.now = func() time.Time { return time.Unix(genesisTime-1, 0) }
+
+		v, err := c.Current()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is active at time")
+		require.Nil(t, v)
+	})
+}
+
 func TestClientVersionProvider_Get(t *testing.T) {
 	v1_0 := &mocks.ClientVersion{}
 	v1_0.VersionReturns("1.0")
@@ -11,10 +11,14 @@ import (
 	"sync"
 
 	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
 
 	"github.com/trustbloc/orb/pkg/context/common"
 	versioncommon "github.com/trustbloc/orb/pkg/protocolversion/common"
 	v1_0 "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_0/client"
+	v1_0cfg "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_0/config"
+	v1_1 "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_1/client"
+	v1_1cfg "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_1/config"
 )
 
 var logger = log.New("client-factory-registry")
@@ -26,22 +30,57 @@ type factory interface {
 const (
 	// V1_0 ...
 	V1_0 = "1.0"
+	// V1_1 ...
+	V1_1 = "1.1"
 )
 
 // Registry implements a client version factory registry.
 type Registry struct {
-	factories map[string]factory
-	mutex     sync.RWMutex
+	factories    map[string]factory
+	protocols    map[string]protocol.Protocol
+	genesisTimes map[string]uint64
+	mutex        sync.RWMutex
+}
+
+// Option is a registry option.
+type Option func(r *Registry)
+
+// WithGenesisTime overrides the default activation genesis time (in seconds since the Unix epoch) of the given
+// built-in version. The genesis time of version 1.0 may not be overridden since it's the initial version.
+func WithGenesisTime(version string, genesisTime uint64) Option {
+	return func(r *Registry) {
+		r.genesisTimes[version] = genesisTime
+	}
+}
+
+// WithProtocolConfig overrides the default protocol parameters of the given built-in version. If a genesis
+// time is also specified for the version using WithGenesisTime then that genesis time takes precedence.
+func WithProtocolConfig(version string, p protocol.Protocol) Option {
+	return func(r *Registry) {
+		r.protocols[version] = p
+	}
 }
 
 // New returns a new client version factory Registry.
-func New() *Registry {
+func New(opts ...Option) *Registry {
 	logger.Debugf("Creating client version factory Registry")
 
-	registry := &Registry{factories: make(map[string]factory)}
+	registry := &Registry{
+		factories:    make(map[string]factory),
+		protocols:    make(map[string]protocol.Protocol),
+		genesisTimes: make(map[string]uint64),
+	}
+
+	for _, opt := range opts {
+		opt(registry)
+	}
 
 	// register supported versions
-	registry.Register(V1_0, v1_0.New())
+	v1_0Config := registry.getProtocolConfig(V1_0, v1_0cfg.GetProtocolConfig())
+	v1_1Config := registry.getProtocolConfig(V1_1, v1_1cfg.GetProtocolConfig())
+
+	registry.Register(V1_0, v1_0.New(v1_0.WithProtocolConfig(v1_0Config)))
+	registry.Register(V1_1, v1_1.New(v1_0.WithProtocolConfig(v1_1Config)))
 
 	return registry
 }
@@ -72,6 +111,47 @@ func (r *Registry) Register(version string, factory factory) {
 	r.factories[version] = factory
 }
 
+// Versions returns all registered versions.
+func (r *Registry) Versions() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	versions := make([]string, 0, len(r.factories))
+
+	for v := range r.factories {
+		versions = append(versions, v)
+	}
+
+	versioncommon.SortVersions(versions)
+
+	return versions
+}
+
+func (r *Registry) getProtocolConfig(version string, defaultConfig protocol.Protocol) protocol.Protocol {
+	p := defaultConfig
+
+	if customConfig, ok := r.protocols[version]; ok {
+		p = customConfig
+
+		logger.Debugf("Using custom protocol parameters for client version factory [%s]", version)
+	}
+
+	// The genesis time of the initial version may not be changed.
+	if version == V1_0 {
+		p.GenesisTime = 0
+
+		return p
+	}
+
+	if genesisTime, ok := r.genesisTimes[version]; ok {
+		logger.Debugf("Using genesis time %d for client version factory [%s]", genesisTime, version)
+
+		p.GenesisTime = genesisTime
+	}
+
+	return p
+}
+
 func (r *Registry) resolveFactory(version string) (factory, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
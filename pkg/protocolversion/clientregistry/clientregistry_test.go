@@ -14,6 +14,7 @@ import (
 	cvmocks "github.com/trustbloc/orb/pkg/mocks"
 	crmocks "github.com/trustbloc/orb/pkg/protocolversion/clientregistry/mocks"
 	"github.com/trustbloc/orb/pkg/protocolversion/mocks"
+	v1_1cfg "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_1/config"
 )
 
 //nolint:lll
@@ -41,3 +42,41 @@ func TestRegistry(t *testing.T) {
 	require.EqualError(t, err, "client version factory for version [99] not found")
 	require.Nil(t, pv)
 }
+
+func TestRegistry_Versions(t *testing.T) {
+	casClient := &mocks.CasClient{}
+
+	t.Run("default genesis times", func(t *testing.T) {
+		r := New()
+
+		require.Equal(t, []string{V1_0, V1_1}, r.Versions())
+
+		cv, err := r.CreateClientVersion(V1_0, casClient)
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), cv.Protocol().GenesisTime)
+
+		cv, err = r.CreateClientVersion(V1_1, casClient)
+		require.NoError(t, err)
+		require.Equal(t, v1_1cfg.GenesisTime, cv.Protocol().GenesisTime)
+	})
+
+	t.Run("with genesis time", func(t *testing.T) {
+		r := New(WithGenesisTime(V1_1, 1000))
+
+		cv, err := r.CreateClientVersion(V1_1, casClient)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1000), cv.Protocol().GenesisTime)
+	})
+
+	t.Run("with protocol config", func(t *testing.T) {
+		p := v1_1cfg.GetProtocolConfig()
+		p.MaxOperationCount = 20
+
+		r := New(WithProtocolConfig(V1_1, p))
+
+		cv, err := r.CreateClientVersion(V1_1, casClient)
+		require.NoError(t, err)
+		require.Equal(t, uint(20), cv.Protocol().MaxOperationCount)
+		require.Equal(t, v1_1cfg.GenesisTime, cv.Protocol().GenesisTime)
+	})
+}
@@ -8,6 +8,10 @@ package common
 
 import (
 	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -69,3 +73,102 @@ func (v Version) Validate() error {
 
 	return nil
 }
+
+// Compare compares the major and minor parts of the version numerically with those of the given version
+// (so that 1.10 is greater than 1.2). The result is -1 if the version is less than the given version,
+// 0 if they're equal and 1 if the version is greater than the given version.
+func (v Version) Compare(other Version) (int, error) {
+	n1, err := v.parse()
+	if err != nil {
+		return 0, err
+	}
+
+	n2, err := other.parse()
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range n1 {
+		if n1[i] < n2[i] {
+			return -1, nil
+		}
+
+		if n1[i] > n2[i] {
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// SortVersions sorts the given versions in ascending order (so that 1.2 comes before 1.10). Versions that
+// can't be compared are sorted lexically.
+func SortVersions(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		result, err := Version(versions[i]).Compare(Version(versions[j]))
+		if err != nil {
+			return versions[i] < versions[j]
+		}
+
+		return result < 0
+	})
+}
+
+// parse returns the numeric major and minor parts of the version. The version may have a 'v' prefix
+// and the minor part defaults to 0.
+func (v Version) parse() ([2]uint64, error) {
+	var n [2]uint64
+
+	if err := v.Validate(); err != nil {
+		return n, fmt.Errorf("invalid version [%s]: %w", v, err)
+	}
+
+	for i, part := range strings.Split(strings.TrimPrefix(string(v), "v"), ".") {
+		value, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return n, fmt.Errorf("invalid version [%s]: %w", v, err)
+		}
+
+		n[i] = value
+	}
+
+	return n, nil
+}
+
+// ValidateGenesisTimes ensures that the given genesis times (keyed by version) strictly increase in version
+// order, i.e. that only the initial version has a genesis time of 0. A genesis time of math.MaxUint64 means
+// that the version is never activated so such versions aren't checked.
+func ValidateGenesisTimes(genesisTimes map[string]uint64) error {
+	versions := make([]Version, 0, len(genesisTimes))
+
+	for v, genesisTime := range genesisTimes {
+		if _, err := Version(v).parse(); err != nil {
+			return err
+		}
+
+		if genesisTime != math.MaxUint64 {
+			versions = append(versions, Version(v))
+		}
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		// The versions were parsed above so there's no error.
+		result, _ := versions[i].Compare(versions[j]) //nolint:errcheck
+
+		return result < 0
+	})
+
+	for i := 1; i < len(versions); i++ {
+		if genesisTimes[string(versions[i])] == 0 {
+			return fmt.Errorf("the genesis time of version [%s] must be greater than 0 since it isn't "+
+				"the initial version", versions[i])
+		}
+
+		if genesisTimes[string(versions[i])] <= genesisTimes[string(versions[i-1])] {
+			return fmt.Errorf("the genesis time of version [%s] must be greater than the genesis time "+
+				"of version [%s]", versions[i], versions[i-1])
+		}
+	}
+
+	return nil
+}
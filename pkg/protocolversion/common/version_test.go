@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package common
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -37,3 +38,78 @@ func TestCcVersion_Validate(t *testing.T) {
 	require.NoError(t, v1_0.Validate())
 	require.EqualError(t, v1_0_0.Validate(), "version must only have a major and optional minor part (e.g. v1 or v1.1)")
 }
+
+func TestVersion_Compare(t *testing.T) {
+	for _, tc := range []struct {
+		v1, v2   string
+		expected int
+	}{
+		{v1: "1.0", v2: "1.0", expected: 0},
+		{v1: "1", v2: "1.0", expected: 0},
+		{v1: "v1.1", v2: "1.1", expected: 0},
+		{v1: "1.0", v2: "1.1", expected: -1},
+		{v1: "1.2", v2: "1.10", expected: -1},
+		{v1: "1.10", v2: "1.2", expected: 1},
+		{v1: "2.0", v2: "1.10", expected: 1},
+	} {
+		result, err := Version(tc.v1).Compare(Version(tc.v2))
+		require.NoError(t, err)
+		require.Equalf(t, tc.expected, result, "compare %s with %s", tc.v1, tc.v2)
+	}
+
+	_, err := Version("1.x").Compare("1.0")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid version [1.x]")
+
+	_, err = Version("1.0").Compare("1.0.0")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid version [1.0.0]")
+}
+
+func TestSortVersions(t *testing.T) {
+	versions := []string{"1.10", "1.2", "2.0", "1.0"}
+
+	SortVersions(versions)
+
+	require.Equal(t, []string{"1.0", "1.2", "1.10", "2.0"}, versions)
+}
+
+func TestValidateGenesisTimes(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		require.NoError(t, ValidateGenesisTimes(map[string]uint64{"1.0": 0}))
+		require.NoError(t, ValidateGenesisTimes(map[string]uint64{"1.0": 0, "1.1": 1000, "1.2": 2000}))
+		require.NoError(t, ValidateGenesisTimes(map[string]uint64{"1.0": 0, "1.2": 1000, "1.10": 2000}))
+		require.NoError(t, ValidateGenesisTimes(map[string]uint64{"1.0": 0, "1.1": math.MaxUint64, "1.2": math.MaxUint64}))
+	})
+
+	t.Run("not increasing", func(t *testing.T) {
+		err := ValidateGenesisTimes(map[string]uint64{"1.0": 0, "1.1": 2000, "1.2": 1000})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the genesis time of version [1.2] must be greater than the genesis "+
+			"time of version [1.1]")
+
+		err = ValidateGenesisTimes(map[string]uint64{"1.0": 0, "1.1": 1000, "1.2": 1000})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the genesis time of version [1.2] must be greater than the genesis "+
+			"time of version [1.1]")
+	})
+
+	t.Run("semantic version order", func(t *testing.T) {
+		err := ValidateGenesisTimes(map[string]uint64{"1.0": 0, "1.2": 2000, "1.10": 1000})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the genesis time of version [1.10] must be greater than the genesis "+
+			"time of version [1.2]")
+	})
+
+	t.Run("non-initial version at 0", func(t *testing.T) {
+		err := ValidateGenesisTimes(map[string]uint64{"1.0": 0, "1.1": 0})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the genesis time of version [1.1] must be greater than 0")
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		err := ValidateGenesisTimes(map[string]uint64{"1.0": 0, "x": 1000})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid version [x]")
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/trustbloc/orb/pkg/config"
 	frmocks "github.com/trustbloc/orb/pkg/protocolversion/factoryregistry/mocks"
 	mocks "github.com/trustbloc/orb/pkg/protocolversion/mocks"
+	v1_1cfg "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_1/config"
 )
 
 //nolint:lll
@@ -48,3 +49,59 @@ func TestRegistry(t *testing.T) {
 	require.EqualError(t, err, "protocol version factory for version [99] not found")
 	require.Nil(t, pv)
 }
+
+func TestRegistry_Versions(t *testing.T) {
+	casClient := &mocks.CasClient{}
+	opStore := &mocks.OperationStore{}
+	anchorGraph := &mocks.AnchorGraph{}
+	casResolver := &mocks.CASResolver{}
+
+	t.Run("default genesis times", func(t *testing.T) {
+		r := New()
+
+		require.Equal(t, []string{V1_0, V1_1}, r.Versions())
+
+		pv, err := r.CreateProtocolVersion(V1_0, casClient, casResolver, opStore, anchorGraph, config.Sidetree{})
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), pv.Protocol().GenesisTime)
+
+		pv, err = r.CreateProtocolVersion(V1_1, casClient, casResolver, opStore, anchorGraph, config.Sidetree{})
+		require.NoError(t, err)
+		require.Equal(t, v1_1cfg.GenesisTime, pv.Protocol().GenesisTime)
+	})
+
+	t.Run("with genesis time", func(t *testing.T) {
+		r := New(WithGenesisTime(V1_1, 1000))
+
+		pv, err := r.CreateProtocolVersion(V1_1, casClient, casResolver, opStore, anchorGraph, config.Sidetree{})
+		require.NoError(t, err)
+		require.Equal(t, V1_1, pv.Version())
+		require.Equal(t, uint64(1000), pv.Protocol().GenesisTime)
+	})
+
+	t.Run("with protocol config", func(t *testing.T) {
+		p1_0 := v1_1cfg.GetProtocolConfig()
+		p1_0.GenesisTime = 500
+		p1_0.MaxOperationCount = 10
+
+		p1_1 := v1_1cfg.GetProtocolConfig()
+		p1_1.GenesisTime = 2000
+		p1_1.MaxOperationCount = 20
+
+		r := New(
+			WithProtocolConfig(V1_0, p1_0),
+			WithProtocolConfig(V1_1, p1_1),
+			WithGenesisTime(V1_1, 1000),
+		)
+
+		pv, err := r.CreateProtocolVersion(V1_0, casClient, casResolver, opStore, anchorGraph, config.Sidetree{})
+		require.NoError(t, err)
+		require.Equal(t, uint(10), pv.Protocol().MaxOperationCount)
+		require.Equal(t, uint64(0), pv.Protocol().GenesisTime)
+
+		pv, err = r.CreateProtocolVersion(V1_1, casClient, casResolver, opStore, anchorGraph, config.Sidetree{})
+		require.NoError(t, err)
+		require.Equal(t, uint(20), pv.Protocol().MaxOperationCount)
+		require.Equal(t, uint64(1000), pv.Protocol().GenesisTime)
+	})
+}
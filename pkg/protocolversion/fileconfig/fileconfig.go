@@ -0,0 +1,256 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fileconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/compression"
+	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
+	"github.com/trustbloc/sidetree-core-go/pkg/patch"
+	"sigs.k8s.io/yaml"
+
+	versioncommon "github.com/trustbloc/orb/pkg/protocolversion/common"
+	"github.com/trustbloc/orb/pkg/protocolversion/factoryregistry"
+	v1_0cfg "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_0/config"
+	v1_1cfg "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_1/config"
+)
+
+var logger = log.New("protocol-file-config")
+
+// defaults contains the default protocol parameters of the supported versions. Parameters that
+// are not specified in the file are set to these values.
+//nolint:gochecknoglobals
+var defaults = map[string]func() protocol.Protocol{
+	factoryregistry.V1_0: v1_0cfg.GetProtocolConfig,
+	factoryregistry.V1_1: v1_1cfg.GetProtocolConfig,
+}
+
+//nolint:gochecknoglobals
+var (
+	supportedPatches = []string{
+		string(patch.Replace), string(patch.AddPublicKeys), string(patch.RemovePublicKeys),
+		string(patch.AddServiceEndpoints), string(patch.RemoveServiceEndpoints), string(patch.JSONPatch),
+	}
+
+	supportedSignatureAlgorithms = []string{"EdDSA", "ES256", "ES384", "ES512", "ES256K"}
+	supportedKeyAlgorithms       = []string{"Ed25519", "P-256", "P-384", "P-521", "secp256k1"}
+)
+
+// Config contains the protocol versions loaded from a file.
+type Config struct {
+	Versions []*Version `json:"versions"`
+}
+
+// Version contains the protocol parameters of a single protocol version.
+type Version struct {
+	// Version is the protocol version (e.g. 1.0).
+	Version string `json:"version"`
+
+	// Protocol contains the protocol parameters. Parameters that are not specified are
+	// set to the defaults of the version.
+	Protocol protocol.Protocol `json:"protocol"`
+}
+
+// versionDoc is used to unmarshal a version so that the protocol parameters can be applied over the defaults.
+type versionDoc struct {
+	Version  string          `json:"version"`
+	Protocol json.RawMessage `json:"protocol"`
+}
+
+type configDoc struct {
+	Versions []*versionDoc `json:"versions"`
+}
+
+// Load loads the protocol versions from the given JSON or YAML file (files with a .yaml or .yml extension
+// are parsed as YAML) and validates the parameters of each version.
+func Load(path string) (*Config, error) {
+	contents, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read protocol config file [%s]: %w", path, err)
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+
+	if ext == ".yaml" || ext == ".yml" {
+		contents, err = yaml.YAMLToJSON(contents)
+		if err != nil {
+			return nil, fmt.Errorf("convert YAML protocol config file [%s]: %w", path, err)
+		}
+	}
+
+	cfg, err := Parse(contents)
+	if err != nil {
+		return nil, fmt.Errorf("protocol config file [%s]: %w", path, err)
+	}
+
+	logger.Infof("Loaded %d protocol version(s) from [%s]", len(cfg.Versions), path)
+
+	return cfg, nil
+}
+
+// Parse parses the protocol versions from the given JSON document and validates the parameters of each version.
+func Parse(contents []byte) (*Config, error) {
+	doc := &configDoc{}
+
+	err := json.Unmarshal(contents, doc)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal protocol config: %w", err)
+	}
+
+	if len(doc.Versions) == 0 {
+		return nil, errors.New("no protocol versions specified")
+	}
+
+	cfg := &Config{}
+
+	for _, vd := range doc.Versions {
+		v, err := parseVersion(vd)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, existing := range cfg.Versions {
+			if existing.Version == v.Version {
+				return nil, fmt.Errorf("duplicate protocol version [%s]", v.Version)
+			}
+		}
+
+		cfg.Versions = append(cfg.Versions, v)
+	}
+
+	if err := validateGenesisTimes(cfg.Versions); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func parseVersion(vd *versionDoc) (*Version, error) {
+	getDefaults, ok := defaults[vd.Version]
+	if !ok {
+		return nil, fmt.Errorf("unsupported protocol version [%s]", vd.Version)
+	}
+
+	p := getDefaults()
+
+	if len(vd.Protocol) > 0 {
+		// Slices in the defaults are replaced (not merged) by the values in the file.
+		if err := json.Unmarshal(vd.Protocol, &p); err != nil {
+			return nil, fmt.Errorf("unmarshal parameters for protocol version [%s]: %w", vd.Version, err)
+		}
+	}
+
+	if err := Validate(&p); err != nil {
+		return nil, fmt.Errorf("invalid parameters for protocol version [%s]: %w", vd.Version, err)
+	}
+
+	if vd.Version == factoryregistry.V1_0 && p.GenesisTime != 0 {
+		return nil, fmt.Errorf("the genesis time of protocol version [%s] must be 0", vd.Version)
+	}
+
+	return &Version{
+		Version:  vd.Version,
+		Protocol: p,
+	}, nil
+}
+
+// validateGenesisTimes ensures that the genesis times increase with the version. The versions in the file are
+// checked against all of the supported versions, using the default genesis time of a version that's not in the file.
+func validateGenesisTimes(versions []*Version) error {
+	genesisTimes := make(map[string]uint64)
+
+	for version, getDefaults := range defaults {
+		genesisTimes[version] = getDefaults().GenesisTime
+	}
+
+	for _, v := range versions {
+		genesisTimes[v.Version] = v.Protocol.GenesisTime
+	}
+
+	return versioncommon.ValidateGenesisTimes(genesisTimes)
+}
+
+// Validate validates the given protocol parameters against the constraints of the Sidetree protocol.
+func Validate(p *protocol.Protocol) error { //nolint:gocyclo,cyclop
+	if len(p.MultihashAlgorithms) == 0 {
+		return errors.New("at least one multihash algorithm must be specified")
+	}
+
+	for _, alg := range p.MultihashAlgorithms {
+		if _, err := hashing.GetHashFromMultihash(alg); err != nil {
+			return fmt.Errorf("unsupported multihash algorithm [%d]", alg)
+		}
+	}
+
+	for name, value := range map[string]uint{
+		"maxOperationCount":            p.MaxOperationCount,
+		"maxOperationSize":             p.MaxOperationSize,
+		"maxOperationHashLength":       p.MaxOperationHashLength,
+		"maxDeltaSize":                 p.MaxDeltaSize,
+		"maxCasUriLength":              p.MaxCasURILength,
+		"maxCoreIndexFileSize":         p.MaxCoreIndexFileSize,
+		"maxProofFileSize":             p.MaxProofFileSize,
+		"maxProvisionalIndexFileSize":  p.MaxProvisionalIndexFileSize,
+		"maxChunkFileSize":             p.MaxChunkFileSize,
+		"maxMemoryDecompressionFactor": p.MaxMemoryDecompressionFactor,
+	} {
+		if value == 0 {
+			return fmt.Errorf("%s must be greater than 0", name)
+		}
+	}
+
+	if p.MaxOperationSize <= p.MaxDeltaSize {
+		return fmt.Errorf("maxOperationSize (%d) must be greater than maxDeltaSize (%d)",
+			p.MaxOperationSize, p.MaxDeltaSize)
+	}
+
+	if _, err := compression.New(compression.WithDefaultAlgorithms()).Compress(p.CompressionAlgorithm, nil); err != nil {
+		return fmt.Errorf("unsupported compression algorithm [%s]", p.CompressionAlgorithm)
+	}
+
+	if err := validateValues("patch", p.Patches, supportedPatches); err != nil {
+		return err
+	}
+
+	if err := validateValues("signature algorithm", p.SignatureAlgorithms, supportedSignatureAlgorithms); err != nil {
+		return err
+	}
+
+	return validateValues("key algorithm", p.KeyAlgorithms, supportedKeyAlgorithms)
+}
+
+func validateValues(name string, values, supported []string) error {
+	if len(values) == 0 {
+		return fmt.Errorf("at least one %s must be specified", name)
+	}
+
+	for _, value := range values {
+		if !contains(supported, value) {
+			return fmt.Errorf("unsupported %s [%s]", name, value)
+		}
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,207 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fileconfig
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+
+	"github.com/trustbloc/orb/pkg/protocolversion/factoryregistry"
+	v1_0cfg "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_0/config"
+)
+
+const jsonConfig = `{
+  "versions": [
+    {
+      "version": "1.0",
+      "protocol": {
+        "maxOperationSize": 2000,
+        "maxDeltaSize": 1000,
+        "keyAlgorithms": ["P-256"]
+      }
+    },
+    {
+      "version": "1.1",
+      "protocol": {
+        "genesisTime": 1767225600,
+        "maxOperationCount": 100
+      }
+    }
+  ]
+}`
+
+const yamlConfig = `
+versions:
+  - version: "1.0"
+    protocol:
+      maxOperationSize: 2000
+      maxDeltaSize: 1000
+      keyAlgorithms:
+        - P-256
+`
+
+func TestLoad(t *testing.T) {
+	t.Run("JSON", func(t *testing.T) {
+		cfg, err := Load(writeFile(t, "protocol.json", jsonConfig))
+		require.NoError(t, err)
+		require.Len(t, cfg.Versions, 2)
+
+		v := cfg.Versions[0]
+		require.Equal(t, factoryregistry.V1_0, v.Version)
+		require.Equal(t, uint(2000), v.Protocol.MaxOperationSize)
+		require.Equal(t, uint(1000), v.Protocol.MaxDeltaSize)
+		require.Equal(t, []string{"P-256"}, v.Protocol.KeyAlgorithms)
+
+		// Unspecified parameters are set to the defaults of the version.
+		require.Equal(t, v1_0cfg.GetProtocolConfig().MaxOperationCount, v.Protocol.MaxOperationCount)
+		require.Equal(t, v1_0cfg.GetProtocolConfig().SignatureAlgorithms, v.Protocol.SignatureAlgorithms)
+
+		v = cfg.Versions[1]
+		require.Equal(t, factoryregistry.V1_1, v.Version)
+		require.Equal(t, uint64(1767225600), v.Protocol.GenesisTime)
+		require.Equal(t, uint(100), v.Protocol.MaxOperationCount)
+	})
+
+	t.Run("YAML", func(t *testing.T) {
+		cfg, err := Load(writeFile(t, "protocol.yaml", yamlConfig))
+		require.NoError(t, err)
+		require.Len(t, cfg.Versions, 1)
+		require.Equal(t, uint(2000), cfg.Versions[0].Protocol.MaxOperationSize)
+		require.Equal(t, []string{"P-256"}, cfg.Versions[0].Protocol.KeyAlgorithms)
+	})
+
+	t.Run("File not found", func(t *testing.T) {
+		_, err := Load(filepath.Join(t.TempDir(), "protocol.json"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read protocol config file")
+	})
+
+	t.Run("Invalid YAML", func(t *testing.T) {
+		_, err := Load(writeFile(t, "protocol.yml", "versions: [\n"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "convert YAML protocol config file")
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		_, err := Load(writeFile(t, "protocol.json", "{"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal protocol config")
+	})
+}
+
+func TestParse(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name:   "No versions",
+			config: `{"versions":[]}`,
+			err:    "no protocol versions specified",
+		},
+		{
+			name:   "Unsupported version",
+			config: `{"versions":[{"version":"9.9"}]}`,
+			err:    "unsupported protocol version [9.9]",
+		},
+		{
+			name:   "Duplicate version",
+			config: `{"versions":[{"version":"1.0"},{"version":"1.0"}]}`,
+			err:    "duplicate protocol version [1.0]",
+		},
+		{
+			name:   "Invalid parameters",
+			config: `{"versions":[{"version":"1.0","protocol":{"maxOperationCount":"xxx"}}]}`,
+			err:    "unmarshal parameters for protocol version [1.0]",
+		},
+		{
+			name:   "Genesis time of 1.0",
+			config: `{"versions":[{"version":"1.0","protocol":{"genesisTime":100}}]}`,
+			err:    "the genesis time of protocol version [1.0] must be 0",
+		},
+		{
+			name: "Genesis times out of order",
+			config: `{"versions":[{"version":"1.1","protocol":{"genesisTime":0}},
+				{"version":"1.0"}]}`,
+			err: "the genesis time of version [1.1] must be greater than 0",
+		},
+		{
+			name:   "Genesis time of 1.1 not after default genesis time of 1.0",
+			config: `{"versions":[{"version":"1.1","protocol":{"genesisTime":0}}]}`,
+			err:    "the genesis time of version [1.1] must be greater than 0",
+		},
+		{
+			name:   "Constraint violation",
+			config: `{"versions":[{"version":"1.0","protocol":{"maxDeltaSize":5000}}]}`,
+			err:    "invalid parameters for protocol version [1.0]: maxOperationSize (2500) must be greater than",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse([]byte(test.config))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), test.err)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate(protocolConfig(nil)))
+
+	for _, test := range []struct {
+		name   string
+		modify func(p *protocol.Protocol)
+		err    string
+	}{
+		{"No multihash algorithms", func(p *protocol.Protocol) { p.MultihashAlgorithms = nil },
+			"at least one multihash algorithm must be specified"},
+		{"Unsupported multihash algorithm", func(p *protocol.Protocol) { p.MultihashAlgorithms = []uint{99} },
+			"unsupported multihash algorithm [99]"},
+		{"Zero limit", func(p *protocol.Protocol) { p.MaxChunkFileSize = 0 },
+			"maxChunkFileSize must be greater than 0"},
+		{"Unsupported compression", func(p *protocol.Protocol) { p.CompressionAlgorithm = "ZIP" },
+			"unsupported compression algorithm [ZIP]"},
+		{"No patches", func(p *protocol.Protocol) { p.Patches = nil },
+			"at least one patch must be specified"},
+		{"Unsupported patch", func(p *protocol.Protocol) { p.Patches = []string{"xxx"} },
+			"unsupported patch [xxx]"},
+		{"Unsupported signature algorithm", func(p *protocol.Protocol) { p.SignatureAlgorithms = []string{"RS256"} },
+			"unsupported signature algorithm [RS256]"},
+		{"Unsupported key algorithm", func(p *protocol.Protocol) { p.KeyAlgorithms = []string{"RSA"} },
+			"unsupported key algorithm [RSA]"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate(protocolConfig(test.modify))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), test.err)
+		})
+	}
+}
+
+func writeFile(t *testing.T, name, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(contents), 0o600))
+
+	return path
+}
+
+func protocolConfig(modify func(p *protocol.Protocol)) *protocol.Protocol {
+	p := v1_0cfg.GetProtocolConfig()
+
+	if modify != nil {
+		modify(&p)
+	}
+
+	return &p
+}
@@ -24,16 +24,36 @@ import (
 )
 
 // Factory implements version 0.1 of the client factory.
-type Factory struct{}
+type Factory struct {
+	protocol protocol.Protocol
+}
+
+// Option is a factory option.
+type Option func(f *Factory)
+
+// WithProtocolConfig sets the protocol parameters. If not set then the default version 1.0 parameters are used.
+func WithProtocolConfig(p protocol.Protocol) Option {
+	return func(f *Factory) {
+		f.protocol = p
+	}
+}
 
 // New returns a version 1.0 implementation of the Sidetree protocol.
-func New() *Factory {
-	return &Factory{}
+func New(opts ...Option) *Factory {
+	f := &Factory{
+		protocol: protocolcfg.GetProtocolConfig(),
+	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
 }
 
 // Create returns a 1.0 client version.
 func (v *Factory) Create(version string, casClient common.CASReader) (common.ClientVersion, error) {
-	p := protocolcfg.GetProtocolConfig()
+	p := v.protocol
 
 	opParser := operationparser.New(p, operationparser.WithAnchorTimeValidator(anchortime.New(p.MaxOperationTimeDelta)))
 
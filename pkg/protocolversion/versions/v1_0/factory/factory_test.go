@@ -41,6 +41,17 @@ func TestFactory_Create(t *testing.T) {
 		pv, err := f.Create("1.0", casClient, casResolver, opStore, anchorGraph, config.Sidetree{})
 		require.NoError(t, err)
 		require.NotNil(t, pv)
+		require.Equal(t, uint(5000), pv.Protocol().MaxOperationCount)
+	})
+
+	t.Run("with protocol config", func(t *testing.T) {
+		p := protocol.Protocol{GenesisTime: 1000, MaxOperationCount: 100}
+
+		pv, err := New(WithProtocolConfig(p)).Create("1.1", casClient, casResolver, opStore, anchorGraph,
+			config.Sidetree{})
+		require.NoError(t, err)
+		require.NotNil(t, pv)
+		require.Equal(t, p, pv.Protocol())
 	})
 }
 
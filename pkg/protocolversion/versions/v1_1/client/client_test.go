@@ -0,0 +1,38 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/protocolversion/mocks"
+	v1_0 "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_0/client"
+	protocolcfg "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_1/config"
+)
+
+func TestFactory_Create(t *testing.T) {
+	f := New()
+	require.NotNil(t, f)
+
+	cv, err := f.Create("1.1", &mocks.CasClient{})
+	require.NoError(t, err)
+	require.NotNil(t, cv)
+	require.Equal(t, "1.1", cv.Version())
+	require.Equal(t, protocolcfg.GenesisTime, cv.Protocol().GenesisTime)
+	require.Equal(t, uint(10000), cv.Protocol().MaxOperationCount)
+
+	t.Run("with protocol config", func(t *testing.T) {
+		p := protocolcfg.GetProtocolConfig()
+		p.GenesisTime = 1000
+
+		cv, err := New(v1_0.WithProtocolConfig(p)).Create("1.1", &mocks.CasClient{})
+		require.NoError(t, err)
+		require.Equal(t, uint64(1000), cv.Protocol().GenesisTime)
+	})
+}
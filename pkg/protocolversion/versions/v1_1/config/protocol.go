@@ -0,0 +1,43 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"math"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+)
+
+// GenesisTime is the default activation time of this version (in seconds since the Unix epoch). Version 1.1
+// is not activated by default. All nodes in a network must be configured with the same activation time.
+const GenesisTime uint64 = math.MaxUint64
+
+// GetProtocolConfig returns protocol config for this version.
+func GetProtocolConfig() protocol.Protocol {
+	//nolint:gomnd
+	p := protocol.Protocol{
+		GenesisTime:                  GenesisTime,
+		MultihashAlgorithms:          []uint{18, 19},
+		MaxOperationCount:            10000,
+		MaxOperationSize:             2500,
+		MaxOperationHashLength:       200,
+		MaxDeltaSize:                 1700,
+		MaxCasURILength:              500,
+		CompressionAlgorithm:         "GZIP",
+		MaxChunkFileSize:             20000000,
+		MaxProvisionalIndexFileSize:  2000000,
+		MaxCoreIndexFileSize:         2000000,
+		MaxProofFileSize:             5000000,
+		Patches:                      []string{"add-public-keys", "remove-public-keys", "add-services", "remove-services", "ietf-json-patch", "replace"}, //nolint:lll
+		SignatureAlgorithms:          []string{"EdDSA", "ES256", "ES256K"},
+		KeyAlgorithms:                []string{"Ed25519", "P-256", "secp256k1"},
+		MaxMemoryDecompressionFactor: 3,
+		NonceSize:                    16,
+	}
+
+	return p
+}
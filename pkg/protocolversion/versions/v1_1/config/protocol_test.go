@@ -0,0 +1,21 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProtocolConfig(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cfg := GetProtocolConfig()
+		require.Equal(t, uint(10000), cfg.MaxOperationCount)
+		require.Equal(t, GenesisTime, cfg.GenesisTime)
+	})
+}
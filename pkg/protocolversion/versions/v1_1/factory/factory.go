@@ -0,0 +1,19 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package factory
+
+import (
+	v1_0 "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_0/factory"
+	protocolcfg "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_1/config"
+)
+
+// New returns a version 1.1 implementation of the Sidetree protocol. Version 1.1 uses the same processing rules
+// as version 1.0 but with different protocol parameters. The default version 1.1 parameters may be overridden
+// using the given options.
+func New(opts ...v1_0.Option) *v1_0.Factory {
+	return v1_0.New(append([]v1_0.Option{v1_0.WithProtocolConfig(protocolcfg.GetProtocolConfig())}, opts...)...)
+}
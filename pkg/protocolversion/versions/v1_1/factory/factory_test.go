@@ -0,0 +1,41 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package factory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/config"
+	"github.com/trustbloc/orb/pkg/protocolversion/mocks"
+	v1_0 "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_0/factory"
+	protocolcfg "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_1/config"
+)
+
+func TestFactory_Create(t *testing.T) {
+	f := New()
+	require.NotNil(t, f)
+
+	pv, err := f.Create("1.1", &mocks.CasClient{}, &mocks.CASResolver{}, &mocks.OperationStore{},
+		&mocks.AnchorGraph{}, config.Sidetree{})
+	require.NoError(t, err)
+	require.NotNil(t, pv)
+	require.Equal(t, "1.1", pv.Version())
+	require.Equal(t, protocolcfg.GenesisTime, pv.Protocol().GenesisTime)
+	require.Equal(t, uint(10000), pv.Protocol().MaxOperationCount)
+
+	t.Run("with protocol config", func(t *testing.T) {
+		p := protocolcfg.GetProtocolConfig()
+		p.GenesisTime = 1000
+
+		pv, err := New(v1_0.WithProtocolConfig(p)).Create("1.1", &mocks.CasClient{}, &mocks.CASResolver{},
+			&mocks.OperationStore{}, &mocks.AnchorGraph{}, config.Sidetree{})
+		require.NoError(t, err)
+		require.Equal(t, uint64(1000), pv.Protocol().GenesisTime)
+	})
+}
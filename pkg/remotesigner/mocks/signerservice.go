@@ -0,0 +1,160 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocks
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+const keysPath = "/keys"
+
+// SignerService is an in-memory implementation of a remote signing service. Only Ed25519 keys are supported.
+type SignerService struct {
+	mutex     sync.RWMutex
+	keys      map[string]ed25519.PrivateKey
+	authToken string
+	signErr   error
+}
+
+// NewSignerService returns a new mock signer service.
+func NewSignerService() *SignerService {
+	return &SignerService{keys: make(map[string]ed25519.PrivateKey)}
+}
+
+// WithAuthToken sets the bearer token that's required for all requests.
+func (s *SignerService) WithAuthToken(token string) *SignerService {
+	s.authToken = token
+
+	return s
+}
+
+// WithSignError injects an error which is returned (with status 500) from the sign endpoint.
+func (s *SignerService) WithSignError(err error) *SignerService {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.signErr = err
+
+	return s
+}
+
+// ServeHTTP handles requests to the signing service.
+func (s *SignerService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.authToken != "" && r.Header.Get("Authorization") != "Bearer "+s.authToken {
+		w.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, keysPath), "/")
+
+	switch {
+	case r.Method == http.MethodPost && len(parts) == 1 && parts[0] == "":
+		s.create(w, r)
+	case r.Method == http.MethodGet && len(parts) == 2:
+		s.publicKey(w, parts[1])
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "sign":
+		s.sign(w, r, parts[1])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *SignerService) create(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		KeyType kms.KeyType `json:"keyType"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.KeyType != kms.ED25519Type {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	s.mutex.Lock()
+	keyID := fmt.Sprintf("key%d", len(s.keys)+1)
+	s.keys[keyID] = privKey
+	s.mutex.Unlock()
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"keyID": keyID, "publicKey": []byte(pubKey)})
+}
+
+func (s *SignerService) publicKey(w http.ResponseWriter, keyID string) {
+	privKey, ok := s.get(keyID)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keyID":     keyID,
+		"publicKey": []byte(privKey.Public().(ed25519.PublicKey)),
+	})
+}
+
+func (s *SignerService) sign(w http.ResponseWriter, r *http.Request, keyID string) {
+	s.mutex.RLock()
+	signErr := s.signErr
+	s.mutex.RUnlock()
+
+	if signErr != nil {
+		http.Error(w, signErr.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	privKey, ok := s.get(keyID)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	var req struct {
+		Message []byte `json:"message"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"signature": ed25519.Sign(privKey, req.Message)})
+}
+
+func (s *SignerService) get(keyID string) (ed25519.PrivateKey, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	privKey, ok := s.keys[keyID]
+
+	return privKey, ok
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v) //nolint:errcheck
+}
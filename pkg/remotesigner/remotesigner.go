@@ -0,0 +1,211 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package remotesigner implements a client for an external signing service (e.g. one that's backed by an HSM)
+// so that anchor credentials and HTTP signatures may be produced without the private keys ever leaving
+// the service. The service exposes the following endpoints:
+//
+//	POST {endpoint}/keys                - creates a key. Request: {"keyType":"ED25519"}
+//	                                      Response: {"keyID":"...","publicKey":"<base64>"}
+//	GET  {endpoint}/keys/{keyID}        - returns the public key. Response: {"keyID":"...","publicKey":"<base64>"}
+//	POST {endpoint}/keys/{keyID}/sign   - signs a message. Request: {"message":"<base64>"}
+//	                                      Response: {"signature":"<base64>"}
+//
+// The public key is in the same format as the one exported by the aries KMS for the given key type.
+package remotesigner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+var logger = log.New("remote-signer")
+
+const keysPath = "/keys"
+
+// ErrKeyNotFound is returned if the key doesn't exist in the signing service.
+var ErrKeyNotFound = errors.New("key not found")
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client is a client for a remote signing service.
+type Client struct {
+	endpoint   string
+	httpClient httpClient
+	authToken  string
+}
+
+// Option is a client option.
+type Option func(c *Client)
+
+// WithHTTPClient sets the HTTP client.
+func WithHTTPClient(httpClient httpClient) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithAuthToken sets the bearer token which is included in each request to the signing service.
+func WithAuthToken(token string) Option {
+	return func(c *Client) {
+		c.authToken = token
+	}
+}
+
+// New returns a new remote signer client for the given service endpoint.
+func New(endpoint string, opts ...Option) *Client {
+	c := &Client{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		httpClient: &http.Client{},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// KeyRequest contains the parameters for creating a key.
+type KeyRequest struct {
+	KeyType kms.KeyType `json:"keyType"`
+}
+
+// KeyResponse contains the ID and public key of a key.
+type KeyResponse struct {
+	KeyID     string `json:"keyID"`
+	PublicKey []byte `json:"publicKey"`
+}
+
+// SignRequest contains the message to sign.
+type SignRequest struct {
+	Message []byte `json:"message"`
+}
+
+// SignResponse contains the signature.
+type SignResponse struct {
+	Signature []byte `json:"signature"`
+}
+
+// Create creates a key of the given type in the signing service and returns its ID. The second return
+// value is always nil since the key never leaves the service (it's only there to be compatible with KMS).
+func (c *Client) Create(keyType kms.KeyType) (string, interface{}, error) {
+	resp := &KeyResponse{}
+
+	err := c.post(c.endpoint+keysPath, &KeyRequest{KeyType: keyType}, resp)
+	if err != nil {
+		return "", nil, fmt.Errorf("create key of type [%s]: %w", keyType, err)
+	}
+
+	if resp.KeyID == "" {
+		return "", nil, fmt.Errorf("create key of type [%s]: key ID is empty", keyType)
+	}
+
+	logger.Debugf("Created key [%s] of type [%s]", resp.KeyID, keyType)
+
+	return resp.KeyID, nil, nil
+}
+
+// ExportPubKeyBytes returns the public key of the given key.
+func (c *Client) ExportPubKeyBytes(keyID string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, c.keyURL(keyID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+
+	resp := &KeyResponse{}
+
+	err = c.do(req, resp)
+	if err != nil {
+		return nil, fmt.Errorf("get public key [%s]: %w", keyID, err)
+	}
+
+	return resp.PublicKey, nil
+}
+
+// Sign signs the given message with the given key.
+func (c *Client) Sign(keyID string, msg []byte) ([]byte, error) {
+	resp := &SignResponse{}
+
+	err := c.post(c.keyURL(keyID)+"/sign", &SignRequest{Message: msg}, resp)
+	if err != nil {
+		return nil, fmt.Errorf("sign with key [%s]: %w", keyID, err)
+	}
+
+	return resp.Signature, nil
+}
+
+func (c *Client) keyURL(keyID string) string {
+	return c.endpoint + keysPath + "/" + url.PathEscape(keyID)
+}
+
+func (c *Client) post(u string, request, response interface{}) error {
+	reqBytes, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(reqBytes))
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.do(req, response)
+}
+
+func (c *Client) do(req *http.Request, response interface{}) error {
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("%s %s: %w", req.Method, req.URL, err))
+	}
+
+	defer func() {
+		if errClose := resp.Body.Close(); errClose != nil {
+			logger.Warnf("Error closing response body: %s", errClose)
+		}
+	}()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("read response body: %w", err))
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated:
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrKeyNotFound
+	case resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests:
+		return orberrors.NewTransient(fmt.Errorf("status code %d: %s", resp.StatusCode, body))
+	default:
+		return fmt.Errorf("status code %d: %s", resp.StatusCode, body)
+	}
+
+	err = json.Unmarshal(body, response)
+	if err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	return nil
+}
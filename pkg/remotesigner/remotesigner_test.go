@@ -0,0 +1,106 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remotesigner
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/remotesigner/mocks"
+)
+
+func TestClient(t *testing.T) {
+	const authToken = "ADMIN_TOKEN"
+
+	svc := httptest.NewServer(mocks.NewSignerService().WithAuthToken(authToken))
+	defer svc.Close()
+
+	c := New(svc.URL+"/", WithHTTPClient(svc.Client()), WithAuthToken(authToken))
+
+	t.Run("Success", func(t *testing.T) {
+		keyID, kh, err := c.Create(kms.ED25519Type)
+		require.NoError(t, err)
+		require.NotEmpty(t, keyID)
+		require.Nil(t, kh)
+
+		pubKey, err := c.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+		require.Len(t, pubKey, ed25519.PublicKeySize)
+
+		msg := []byte("message")
+
+		sig, err := c.Sign(keyID, msg)
+		require.NoError(t, err)
+		require.True(t, ed25519.Verify(pubKey, msg, sig))
+	})
+
+	t.Run("Key not found", func(t *testing.T) {
+		_, err := c.ExportPubKeyBytes("unknown")
+		require.True(t, errors.Is(err, ErrKeyNotFound))
+
+		_, err = c.Sign("unknown", []byte("message"))
+		require.True(t, errors.Is(err, ErrKeyNotFound))
+	})
+
+	t.Run("Unsupported key type", func(t *testing.T) {
+		_, _, err := c.Create(kms.BLS12381G2Type)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "status code 400")
+		require.False(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		_, _, err := New(svc.URL, WithHTTPClient(svc.Client())).Create(kms.ED25519Type)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "status code 401")
+	})
+
+	t.Run("Service error", func(t *testing.T) {
+		s := mocks.NewSignerService().WithSignError(errors.New("injected sign error"))
+
+		svc := httptest.NewServer(s)
+		defer svc.Close()
+
+		c := New(svc.URL, WithHTTPClient(svc.Client()))
+
+		keyID, _, err := c.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		_, err = c.Sign(keyID, []byte("message"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected sign error")
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("Connection error", func(t *testing.T) {
+		_, err := New("http://127.0.0.1:0").Sign("key1", []byte("message"))
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("Invalid response", func(t *testing.T) {
+		svc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte("{"))
+			require.NoError(t, err)
+		}))
+		defer svc.Close()
+
+		_, err := New(svc.URL, WithHTTPClient(svc.Client())).ExportPubKeyBytes("key1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal response")
+
+		_, _, err = New(svc.URL, WithHTTPClient(svc.Client())).Create(kms.ED25519Type)
+		require.Error(t, err)
+	})
+}
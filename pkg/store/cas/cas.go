@@ -9,6 +9,7 @@ package cas
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/bluele/gcache"
@@ -26,6 +27,16 @@ var logger = log.New("cas-store")
 const (
 	defaultCacheSize = 1000
 	casType          = "local"
+	storeName        = "cas_store"
+
+	// Corrupt content is moved to this store so that it may be inspected later.
+	quarantineStoreName = "cas_quarantine"
+
+	// createdTag holds the time (in seconds since the epoch) at which the content was last written.
+	createdTag = "created"
+
+	// untrackedTag is added to content that was written before the creation time was tracked.
+	untrackedTag = "untracked"
 )
 
 type metricsProvider interface {
@@ -36,6 +47,7 @@ type metricsProvider interface {
 // CAS represents a content-addressable storage provider.
 type CAS struct {
 	cas        ariesstorage.Store
+	quarantine ariesstorage.Store
 	ipfsClient *ipfs.Client
 	opts       []extendedcasclient.CIDFormatOption
 	cache      gcache.Cache
@@ -50,17 +62,28 @@ type CAS struct {
 // If no CID version is specified, then v1 will be used by default.
 func New(provider ariesstorage.Provider, casLink string, ipfsClient *ipfs.Client,
 	metrics metricsProvider, cacheSize int, opts ...extendedcasclient.CIDFormatOption) (*CAS, error) {
-	cas, err := provider.OpenStore("cas_store")
+	cas, err := provider.OpenStore(storeName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open store in underlying storage provider: %w", err)
 	}
 
+	err = provider.SetStoreConfig(storeName, ariesstorage.StoreConfiguration{TagNames: []string{createdTag, untrackedTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	quarantine, err := provider.OpenStore(quarantineStoreName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open quarantine store in underlying storage provider: %w", err)
+	}
+
 	if cacheSize == 0 {
 		cacheSize = defaultCacheSize
 	}
 
 	c := &CAS{
 		cas:        cas,
+		quarantine: quarantine,
 		ipfsClient: ipfsClient,
 		opts:       opts,
 		metrics:    metrics,
@@ -102,7 +125,8 @@ func (p *CAS) WriteWithCIDFormat(content []byte, opts ...extendedcasclient.CIDFo
 		return "", fmt.Errorf("failed to create resource hash from content: %w", err)
 	}
 
-	err = p.cas.Put(resourceHash, content)
+	err = p.cas.Put(resourceHash, content,
+		ariesstorage.Tag{Name: createdTag, Value: strconv.FormatInt(time.Now().Unix(), 10)})
 	if err != nil {
 		return "", orberrors.NewTransient(fmt.Errorf("failed to put content into underlying storage provider: %w", err))
 	}
@@ -156,6 +180,119 @@ func (p *CAS) Read(address string) ([]byte, error) {
 	return content.([]byte), nil
 }
 
+// ForEach invokes the given function for each piece of content in the store along with the time at which it was
+// last written. The underlying store can only enumerate tagged content, so content that was written before the
+// creation time was tracked is included once it has been read (see tagUntracked). The creation time of such
+// content is unknown so it's reported as the current time.
+func (p *CAS) ForEach(fn func(resourceHash string, created time.Time) error) error {
+	if err := p.forEach(createdTag, fn); err != nil {
+		return err
+	}
+
+	return p.forEach(untrackedTag, fn)
+}
+
+func (p *CAS) forEach(tagName string, fn func(resourceHash string, created time.Time) error) error {
+	iter, err := p.cas.Query(tagName)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("failed to query CAS store: %w", err))
+	}
+
+	defer ariesstorage.Close(iter, logger)
+
+	for {
+		ok, err := iter.Next()
+		if err != nil {
+			return orberrors.NewTransient(fmt.Errorf("iterator error for CAS store: %w", err))
+		}
+
+		if !ok {
+			return nil
+		}
+
+		key, err := iter.Key()
+		if err != nil {
+			return orberrors.NewTransient(fmt.Errorf("failed to get iterator key for CAS store: %w", err))
+		}
+
+		tags, err := iter.Tags()
+		if err != nil {
+			return orberrors.NewTransient(fmt.Errorf("failed to get iterator tags for CAS store: %w", err))
+		}
+
+		if err := fn(key, getCreatedTime(tags)); err != nil {
+			return err
+		}
+	}
+}
+
+// Delete deletes the content with the given resource hash.
+func (p *CAS) Delete(resourceHash string) error {
+	err := p.cas.Delete(resourceHash)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("failed to delete content from the local CAS provider: %w", err))
+	}
+
+	p.cache.Remove(resourceHash)
+
+	logger.Debugf("Deleted content for resource hash [%s]", resourceHash)
+
+	return nil
+}
+
+// ReadRaw reads the stored content for the given resource hash directly from the underlying storage provider
+// without using the cache.
+func (p *CAS) ReadRaw(resourceHash string) ([]byte, error) {
+	return p.get(resourceHash)
+}
+
+// Quarantine moves the content with the given resource hash to the quarantine store. Quarantined content
+// is no longer served by the CAS.
+func (p *CAS) Quarantine(resourceHash string) error {
+	content, err := p.cas.Get(resourceHash)
+	if err != nil {
+		if errors.Is(err, ariesstorage.ErrDataNotFound) {
+			return nil
+		}
+
+		return orberrors.NewTransient(fmt.Errorf("failed to get content from the local CAS provider: %w", err))
+	}
+
+	err = p.quarantine.Put(resourceHash, content)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("failed to put content into the quarantine store: %w", err))
+	}
+
+	err = p.Delete(resourceHash)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Moved content for resource hash [%s] to quarantine", resourceHash)
+
+	return nil
+}
+
+func getCreatedTime(tags []ariesstorage.Tag) time.Time {
+	for _, tag := range tags {
+		if tag.Name != createdTag {
+			continue
+		}
+
+		secs, err := strconv.ParseInt(tag.Value, 10, 64)
+		if err != nil {
+			logger.Warnf("Invalid value for tag [%s]: %s", createdTag, tag.Value)
+
+			break
+		}
+
+		return time.Unix(secs, 0)
+	}
+
+	// The time is unknown so treat the content as new so that it isn't deleted.
+	return time.Now()
+}
+
 func (p *CAS) get(address string) ([]byte, error) {
 	startTime := time.Now()
 
@@ -172,5 +309,31 @@ func (p *CAS) get(address string) ([]byte, error) {
 		return nil, orberrors.NewTransient(fmt.Errorf("failed to get content from the local CAS provider: %w", err))
 	}
 
+	p.tagUntracked(address, content)
+
 	return content, nil
 }
+
+// tagUntracked tags content that was written before the creation time was tracked so that the content is
+// included by ForEach.
+func (p *CAS) tagUntracked(resourceHash string, content []byte) {
+	tags, err := p.cas.GetTags(resourceHash)
+	if err != nil {
+		logger.Warnf("Unable to get tags for resource hash [%s]: %s", resourceHash, err)
+
+		return
+	}
+
+	if len(tags) > 0 {
+		return
+	}
+
+	err = p.cas.Put(resourceHash, content, ariesstorage.Tag{Name: untrackedTag, Value: "true"})
+	if err != nil {
+		logger.Warnf("Unable to add tag [%s] to resource hash [%s]: %s", untrackedTag, resourceHash, err)
+
+		return
+	}
+
+	logger.Debugf("Added tag [%s] to resource hash [%s]", untrackedTag, resourceHash)
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/trustbloc/orb/pkg/hashlink"
 	orbmocks "github.com/trustbloc/orb/pkg/mocks"
 	localcas "github.com/trustbloc/orb/pkg/store/cas"
+	storemocks "github.com/trustbloc/orb/pkg/store/mocks"
 )
 
 const casLink = "https://domain.com/cas"
@@ -44,6 +45,201 @@ func TestNew(t *testing.T) {
 		require.EqualError(t, err, "failed to open store in underlying storage provider: open store error")
 		require.Nil(t, provider)
 	})
+	t.Run("Fail to set store configuration", func(t *testing.T) {
+		provider, err := localcas.New(&ariesmockstorage.Provider{ErrSetStoreConfig: errors.New("set config error")},
+			casLink, nil, &orbmocks.MetricsProvider{}, 0)
+
+		require.EqualError(t, err, "failed to set store configuration: set config error")
+		require.Nil(t, provider)
+	})
+	t.Run("Fail to open quarantine store", func(t *testing.T) {
+		p := &storemocks.Provider{}
+		p.OpenStoreReturnsOnCall(1, nil, errors.New("open store error"))
+
+		provider, err := localcas.New(p, casLink, nil, &orbmocks.MetricsProvider{}, 0)
+
+		require.EqualError(t, err, "failed to open quarantine store in underlying storage provider: open store error")
+		require.Nil(t, provider)
+	})
+}
+
+func TestProvider_ForEach_Delete(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		provider, err := localcas.New(ariesmemstorage.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		hl1, err := provider.Write([]byte("content1"))
+		require.NoError(t, err)
+
+		hl2, err := provider.Write([]byte("content2"))
+		require.NoError(t, err)
+
+		rh1, err := hashlink.GetResourceHashFromHashLink(hl1)
+		require.NoError(t, err)
+
+		rh2, err := hashlink.GetResourceHashFromHashLink(hl2)
+		require.NoError(t, err)
+
+		created := make(map[string]time.Time)
+
+		require.NoError(t, provider.ForEach(func(resourceHash string, t time.Time) error {
+			created[resourceHash] = t
+
+			return nil
+		}))
+
+		require.Len(t, created, 2)
+		require.WithinDuration(t, time.Now(), created[rh1], 2*time.Second)
+		require.WithinDuration(t, time.Now(), created[rh2], 2*time.Second)
+
+		require.NoError(t, provider.Delete(rh1))
+
+		_, err = provider.Read(rh1)
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+
+		content, err := provider.Read(rh2)
+		require.NoError(t, err)
+		require.Equal(t, "content2", string(content))
+	})
+	t.Run("Content without creation time", func(t *testing.T) {
+		storeProvider := ariesmemstorage.NewProvider()
+
+		provider, err := localcas.New(storeProvider, casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		hl, err := provider.Write([]byte("content1"))
+		require.NoError(t, err)
+
+		rh, err := hashlink.GetResourceHashFromHashLink(hl)
+		require.NoError(t, err)
+
+		// Simulate content that was written before the creation time was tracked.
+		s, err := storeProvider.OpenStore("cas_store")
+		require.NoError(t, err)
+		require.NoError(t, s.Put(rh, []byte("content1")))
+
+		created := make(map[string]time.Time)
+
+		forEach := func(resourceHash string, t time.Time) error {
+			created[resourceHash] = t
+
+			return nil
+		}
+
+		require.NoError(t, provider.ForEach(forEach))
+		require.Empty(t, created)
+
+		// The content is tagged when it's read.
+		content, err := provider.ReadRaw(rh)
+		require.NoError(t, err)
+		require.Equal(t, "content1", string(content))
+
+		// The creation time is unknown so the current time is reported (which prevents the content from being
+		// garbage collected).
+		before := time.Now()
+
+		require.NoError(t, provider.ForEach(forEach))
+		require.Len(t, created, 1)
+		require.False(t, created[rh].Before(before))
+	})
+	t.Run("Callback error", func(t *testing.T) {
+		provider, err := localcas.New(ariesmemstorage.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		_, err = provider.Write([]byte("content1"))
+		require.NoError(t, err)
+
+		err = provider.ForEach(func(string, time.Time) error {
+			return errors.New("injected error")
+		})
+		require.EqualError(t, err, "injected error")
+	})
+	t.Run("Query error", func(t *testing.T) {
+		provider, err := localcas.New(&ariesmockstorage.Provider{
+			OpenStoreReturn: &ariesmockstorage.Store{
+				ErrQuery: errors.New("query error"),
+			},
+		}, casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		err = provider.ForEach(func(string, time.Time) error { return nil })
+		require.EqualError(t, err, "failed to query CAS store: query error")
+		require.True(t, orberrors.IsTransient(err))
+	})
+	t.Run("Delete error", func(t *testing.T) {
+		provider, err := localcas.New(&ariesmockstorage.Provider{
+			OpenStoreReturn: &ariesmockstorage.Store{
+				ErrDelete: errors.New("delete error"),
+			},
+		}, casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		err = provider.Delete("uEiDat0G2KJ59zMHtQjMMrhrMwrdVzoB5ws1dS1Nmyfdppg")
+		require.EqualError(t, err, "failed to delete content from the local CAS provider: delete error")
+		require.True(t, orberrors.IsTransient(err))
+	})
+}
+
+func TestProvider_ReadRaw_Quarantine(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		storeProvider := ariesmemstorage.NewProvider()
+
+		provider, err := localcas.New(storeProvider, casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		hl, err := provider.Write([]byte("content1"))
+		require.NoError(t, err)
+
+		rh, err := hashlink.GetResourceHashFromHashLink(hl)
+		require.NoError(t, err)
+
+		content, err := provider.ReadRaw(rh)
+		require.NoError(t, err)
+		require.Equal(t, "content1", string(content))
+
+		require.NoError(t, provider.Quarantine(rh))
+
+		_, err = provider.Read(rh)
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+
+		_, err = provider.ReadRaw(rh)
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+
+		quarantine, err := storeProvider.OpenStore("cas_quarantine")
+		require.NoError(t, err)
+
+		content, err = quarantine.Get(rh)
+		require.NoError(t, err)
+		require.Equal(t, "content1", string(content))
+
+		// Quarantining content that doesn't exist is not an error.
+		require.NoError(t, provider.Quarantine(rh))
+	})
+	t.Run("Get error", func(t *testing.T) {
+		provider, err := localcas.New(&ariesmockstorage.Provider{
+			OpenStoreReturn: &ariesmockstorage.Store{
+				ErrGet: errors.New("get error"),
+			},
+		}, casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		err = provider.Quarantine("uEiDat0G2KJ59zMHtQjMMrhrMwrdVzoB5ws1dS1Nmyfdppg")
+		require.EqualError(t, err, "failed to get content from the local CAS provider: get error")
+		require.True(t, orberrors.IsTransient(err))
+	})
+	t.Run("Put error", func(t *testing.T) {
+		provider, err := localcas.New(&ariesmockstorage.Provider{
+			OpenStoreReturn: &ariesmockstorage.Store{
+				GetReturn: []byte("content"),
+				ErrPut:    errors.New("put error"),
+			},
+		}, casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		err = provider.Quarantine("uEiDat0G2KJ59zMHtQjMMrhrMwrdVzoB5ws1dS1Nmyfdppg")
+		require.EqualError(t, err, "failed to put content into the quarantine store: put error")
+		require.True(t, orberrors.IsTransient(err))
+	})
 }
 
 func TestProvider_Write_Read(t *testing.T) {
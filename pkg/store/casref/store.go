@@ -0,0 +1,165 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package casref
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const (
+	namespace = "casref"
+
+	resourceHashTag = "resourceHash"
+	anchorTag       = "anchor"
+)
+
+var logger = log.New("cas-reference-store")
+
+// Type is the type of CAS content that is referenced by an anchor.
+type Type string
+
+// Types of referenced content.
+const (
+	TypeAnchor           Type = "anchor"
+	TypeCoreIndex        Type = "coreIndex"
+	TypeCoreProof        Type = "coreProof"
+	TypeProvisionalIndex Type = "provisionalIndex"
+	TypeProvisionalProof Type = "provisionalProof"
+	TypeChunk            Type = "chunk"
+)
+
+// Reference indicates that the CAS content with the given resource hash is used by an anchor.
+type Reference struct {
+	// ResourceHash is the resource hash (multibase-encoded multihash) of the referenced content.
+	ResourceHash string `json:"resourceHash"`
+
+	// Anchor is the resource hash of the anchor credential that uses the content.
+	Anchor string `json:"anchor"`
+
+	// Type is the type of the referenced content.
+	Type Type `json:"type"`
+
+	// Suffixes contains the suffixes of the DIDs whose operations are included in the anchor. This field is only
+	// set in the reference of type 'anchor'.
+	Suffixes []string `json:"suffixes,omitempty"`
+}
+
+// Store is the reference index which maps CAS content to the anchors (and the operations within the anchors)
+// that use the content.
+type Store struct {
+	store storage.Store
+}
+
+// New returns a new reference index.
+func New(provider storage.Provider) (*Store, error) {
+	store, err := provider.OpenStore(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CAS reference store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(namespace,
+		storage.StoreConfiguration{TagNames: []string{resourceHashTag, anchorTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	return &Store{
+		store: store,
+	}, nil
+}
+
+// Put adds the given references to the index.
+func (s *Store) Put(refs ...*Reference) error {
+	operations := make([]storage.Operation, len(refs))
+
+	for i, ref := range refs {
+		value, err := json.Marshal(ref)
+		if err != nil {
+			return fmt.Errorf("failed to marshal reference: %w", err)
+		}
+
+		operations[i] = storage.Operation{
+			Key:   ref.ResourceHash + "_" + ref.Anchor,
+			Value: value,
+			Tags: []storage.Tag{
+				{Name: resourceHashTag, Value: ref.ResourceHash},
+				{Name: anchorTag, Value: ref.Anchor},
+			},
+		}
+	}
+
+	err := s.store.Batch(operations)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("failed to store CAS references: %w", err))
+	}
+
+	logger.Debugf("Stored %d CAS references", len(refs))
+
+	return nil
+}
+
+// Get returns the references to the content with the given resource hash.
+func (s *Store) Get(resourceHash string) ([]*Reference, error) {
+	iter, err := s.store.Query(fmt.Sprintf("%s:%s", resourceHashTag, resourceHash))
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("failed to query CAS references: %w", err))
+	}
+
+	defer storage.Close(iter, logger)
+
+	var refs []*Reference
+
+	for {
+		ok, err := iter.Next()
+		if err != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("iterator error for CAS references: %w", err))
+		}
+
+		if !ok {
+			break
+		}
+
+		value, err := iter.Value()
+		if err != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("failed to get iterator value for CAS references: %w", err))
+		}
+
+		ref := &Reference{}
+
+		err = json.Unmarshal(value, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal CAS reference: %w", err)
+		}
+
+		refs = append(refs, ref)
+	}
+
+	return refs, nil
+}
+
+// IsReferenced returns true if the content with the given resource hash is used by at least one anchor.
+func (s *Store) IsReferenced(resourceHash string) (bool, error) {
+	iter, err := s.store.Query(fmt.Sprintf("%s:%s", resourceHashTag, resourceHash))
+	if err != nil {
+		return false, orberrors.NewTransient(fmt.Errorf("failed to query CAS references: %w", err))
+	}
+
+	defer storage.Close(iter, logger)
+
+	ok, err := iter.Next()
+	if err != nil {
+		return false, orberrors.NewTransient(fmt.Errorf("iterator error for CAS references: %w", err))
+	}
+
+	return ok, nil
+}
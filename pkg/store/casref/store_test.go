@@ -0,0 +1,148 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package casref
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	anchor1    = "uEiD1X_FvZvQzYCZrldtvj-wB12AxBUMGrkpLOAWY9s_RFA"
+	anchor2    = "uEiAsOkJJ13BwBYZJ29gi3K95V1hvzkKM-yyoi5R0HtqLBw"
+	coreIndex1 = "uEiDtcAK0OemshF8iNX2CK6wURHMPvbYBbT7JQyKXueyfcw"
+	chunk      = "uEiB_g7Flf_H8U7ktwYFIodZd_C1LH6PWdyhK3dIAEm2QaQ"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+		require.NotNil(t, s)
+	})
+
+	t.Run("error - open store fails", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(nil, fmt.Errorf("open store error"))
+
+		s, err := New(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to open CAS reference store: open store error")
+		require.Nil(t, s)
+	})
+
+	t.Run("error - set store config fails", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.SetStoreConfigReturns(fmt.Errorf("set store config error"))
+
+		s, err := New(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to set store configuration: set store config error")
+		require.Nil(t, s)
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put(
+			&Reference{ResourceHash: anchor1, Anchor: anchor1, Type: TypeAnchor, Suffixes: []string{"suffix1"}},
+			&Reference{ResourceHash: coreIndex1, Anchor: anchor1, Type: TypeCoreIndex},
+			&Reference{ResourceHash: chunk, Anchor: anchor1, Type: TypeChunk},
+		))
+
+		// The same chunk is used by another anchor.
+		require.NoError(t, s.Put(&Reference{ResourceHash: chunk, Anchor: anchor2, Type: TypeChunk}))
+
+		refs, err := s.Get(anchor1)
+		require.NoError(t, err)
+		require.Len(t, refs, 1)
+		require.Equal(t, TypeAnchor, refs[0].Type)
+		require.Equal(t, []string{"suffix1"}, refs[0].Suffixes)
+
+		refs, err = s.Get(chunk)
+		require.NoError(t, err)
+		require.Len(t, refs, 2)
+
+		refs, err = s.Get(anchor2)
+		require.NoError(t, err)
+		require.Empty(t, refs)
+
+		ok, err := s.IsReferenced(coreIndex1)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = s.IsReferenced(anchor2)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("error - batch fails", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.BatchReturns(fmt.Errorf("batch error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		err = s.Put(&Reference{ResourceHash: anchor1, Anchor: anchor1, Type: TypeAnchor})
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "batch error")
+	})
+
+	t.Run("error - query fails", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(nil, fmt.Errorf("query error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = s.Get(anchor1)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+
+		_, err = s.IsReferenced(anchor1)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("error - iterator fails", func(t *testing.T) {
+		iter := &mocks.Iterator{}
+		iter.NextReturns(false, fmt.Errorf("next error"))
+
+		store := &mocks.Store{}
+		store.QueryReturns(iter, nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = s.Get(anchor1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "next error")
+
+		_, err = s.IsReferenced(anchor1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "next error")
+	})
+}
@@ -0,0 +1,247 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deadletter
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const (
+	namespace = "deadletter"
+
+	entryTag        = "entry"
+	targetTag       = "target"
+	activityTypeTag = "activityType"
+)
+
+var logger = log.New("dead-letter-store")
+
+// ErrNotFound is returned if the dead-letter entry is not found in the store.
+var ErrNotFound = errors.New("dead-letter entry not found")
+
+// Entry contains an activity that could not be delivered to the target inbox.
+type Entry struct {
+	// ID is the unique ID of the entry.
+	ID string `json:"id"`
+
+	// ActivityID is the ID of the undeliverable activity.
+	ActivityID string `json:"activityId"`
+
+	// ActivityType is the type of the undeliverable activity.
+	ActivityType string `json:"activityType"`
+
+	// Target is the URL of the inbox to which the activity could not be delivered.
+	Target string `json:"target"`
+
+	// Activity contains the undeliverable activity.
+	Activity json.RawMessage `json:"activity"`
+
+	// Time is the time at which the activity was added to the store.
+	Time time.Time `json:"time"`
+}
+
+// Criteria contains the criteria used to query entries. Empty fields match all entries.
+type Criteria struct {
+	// Target is the URL of the target inbox.
+	Target string
+
+	// ActivityType is the type of activity.
+	ActivityType string
+}
+
+// Store is the db implementation of the dead-letter store.
+type Store struct {
+	store storage.Store
+}
+
+// New creates a new dead-letter store.
+func New(provider storage.Provider) (*Store, error) {
+	store, err := provider.OpenStore(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dead-letter store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(namespace,
+		storage.StoreConfiguration{TagNames: []string{entryTag, targetTag, activityTypeTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	return &Store{
+		store: store,
+	}, nil
+}
+
+// Put adds the given activity, which could not be delivered to the given target inbox, to the store.
+func (s *Store) Put(activity *vocab.ActivityType, target string) (*Entry, error) {
+	activityBytes, err := json.Marshal(activity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal activity: %w", err)
+	}
+
+	entry := &Entry{
+		ID:           uuid.New().String(),
+		ActivityType: activityType(activity),
+		Target:       target,
+		Activity:     activityBytes,
+		Time:         time.Now(),
+	}
+
+	if activity.ID() != nil {
+		entry.ActivityID = activity.ID().String()
+	}
+
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal dead-letter entry: %w", err)
+	}
+
+	err = s.store.Put(entry.ID, value,
+		storage.Tag{Name: entryTag},
+		storage.Tag{Name: targetTag, Value: encode(target)},
+		storage.Tag{Name: activityTypeTag, Value: entry.ActivityType},
+	)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("failed to store dead-letter entry for activity [%s]: %w",
+			entry.ActivityID, err))
+	}
+
+	logger.Debugf("Stored dead-letter entry [%s] for activity [%s] to [%s]", entry.ID, entry.ActivityID, target)
+
+	return entry, nil
+}
+
+// Get returns the entry for the given ID.
+func (s *Store) Get(id string) (*Entry, error) {
+	value, err := s.store.Get(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("failed to get dead-letter entry [%s]: %w", id, err))
+	}
+
+	entry := &Entry{}
+
+	err = json.Unmarshal(value, entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal dead-letter entry [%s]: %w", id, err)
+	}
+
+	return entry, nil
+}
+
+// Query returns the entries that match the given criteria.
+func (s *Store) Query(criteria *Criteria) ([]*Entry, error) {
+	iter, err := s.store.Query(getQuery(criteria))
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("failed to query dead-letter entries: %w", err))
+	}
+
+	defer storage.Close(iter, logger)
+
+	var entries []*Entry
+
+	for {
+		ok, err := iter.Next()
+		if err != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("iterator error for dead-letter entries: %w", err))
+		}
+
+		if !ok {
+			break
+		}
+
+		value, err := iter.Value()
+		if err != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("failed to get iterator value for dead-letter entries: %w", err))
+		}
+
+		entry := &Entry{}
+
+		err = json.Unmarshal(value, entry)
+		if err != nil {
+			logger.Errorf("Failed to unmarshal dead-letter entry: %s", err)
+
+			continue
+		}
+
+		// Only one tag may be queried at a time so the activity type is filtered here.
+		if criteria.ActivityType != "" && entry.ActivityType != criteria.ActivityType {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// Delete deletes the entry with the given ID.
+func (s *Store) Delete(id string) error {
+	err := s.store.Delete(id)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("failed to delete dead-letter entry [%s]: %w", id, err))
+	}
+
+	logger.Debugf("Deleted dead-letter entry [%s]", id)
+
+	return nil
+}
+
+// Count returns the total number of entries in the store.
+func (s *Store) Count() (int, error) {
+	iter, err := s.store.Query(entryTag)
+	if err != nil {
+		return 0, orberrors.NewTransient(fmt.Errorf("failed to query dead-letter entries: %w", err))
+	}
+
+	defer storage.Close(iter, logger)
+
+	count, err := iter.TotalItems()
+	if err != nil {
+		return 0, orberrors.NewTransient(fmt.Errorf("failed to get total dead-letter entries: %w", err))
+	}
+
+	return count, nil
+}
+
+func getQuery(criteria *Criteria) string {
+	switch {
+	case criteria.Target != "":
+		return fmt.Sprintf("%s:%s", targetTag, encode(criteria.Target))
+	case criteria.ActivityType != "":
+		return fmt.Sprintf("%s:%s", activityTypeTag, criteria.ActivityType)
+	default:
+		return entryTag
+	}
+}
+
+func activityType(activity *vocab.ActivityType) string {
+	types := activity.Type().Types()
+	if len(types) == 0 {
+		return ""
+	}
+
+	return string(types[0])
+}
+
+func encode(value string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
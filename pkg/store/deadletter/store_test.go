@@ -0,0 +1,218 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deadletter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	inbox1 = "https://orb.domain2.com/services/orb/inbox"
+	inbox2 = "https://orb.domain3.com/services/orb/inbox"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+		require.NotNil(t, s)
+	})
+
+	t.Run("error - open store fails", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(nil, fmt.Errorf("open store error"))
+
+		s, err := New(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to open dead-letter store: open store error")
+		require.Nil(t, s)
+	})
+
+	t.Run("error - set store config fails", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.SetStoreConfigReturns(fmt.Errorf("set store config error"))
+
+		s, err := New(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to set store configuration: set store config error")
+		require.Nil(t, s)
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		follow := newFollow(t)
+		announce := newAnnounce(t)
+
+		e1, err := s.Put(follow, inbox1)
+		require.NoError(t, err)
+		require.NotEmpty(t, e1.ID)
+		require.Equal(t, follow.ID().String(), e1.ActivityID)
+		require.Equal(t, string(vocab.TypeFollow), e1.ActivityType)
+		require.Equal(t, inbox1, e1.Target)
+
+		_, err = s.Put(announce, inbox1)
+		require.NoError(t, err)
+
+		_, err = s.Put(announce, inbox2)
+		require.NoError(t, err)
+
+		entry, err := s.Get(e1.ID)
+		require.NoError(t, err)
+		require.Equal(t, e1.ActivityID, entry.ActivityID)
+
+		a := &vocab.ActivityType{}
+		require.NoError(t, json.Unmarshal(entry.Activity, a))
+		require.Equal(t, follow.ID().String(), a.ID().String())
+
+		count, err := s.Count()
+		require.NoError(t, err)
+		require.Equal(t, 3, count)
+
+		entries, err := s.Query(&Criteria{})
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+
+		entries, err = s.Query(&Criteria{Target: inbox1})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+
+		entries, err = s.Query(&Criteria{ActivityType: string(vocab.TypeAnnounce)})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+
+		entries, err = s.Query(&Criteria{Target: inbox1, ActivityType: string(vocab.TypeAnnounce)})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+
+		require.NoError(t, s.Delete(e1.ID))
+
+		_, err = s.Get(e1.ID)
+		require.True(t, errors.Is(err, ErrNotFound))
+
+		count, err = s.Count()
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+	})
+
+	t.Run("store errors", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		store := &mocks.Store{}
+		store.PutReturns(errExpected)
+		store.GetReturns(nil, errExpected)
+		store.QueryReturns(nil, errExpected)
+		store.DeleteReturns(errExpected)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = s.Put(newFollow(t), inbox1)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+
+		_, err = s.Get("id")
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+
+		_, err = s.Query(&Criteria{})
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+
+		_, err = s.Count()
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+
+		err = s.Delete("id")
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("iterator errors", func(t *testing.T) {
+		errExpected := errors.New("injected iterator error")
+
+		iter := &mocks.Iterator{}
+		iter.NextReturns(false, errExpected)
+		iter.TotalItemsReturns(0, errExpected)
+
+		store := &mocks.Store{}
+		store.QueryReturns(iter, nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = s.Query(&Criteria{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+
+		_, err = s.Count()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+
+		iter.NextReturns(true, nil)
+		iter.ValueReturns(nil, errExpected)
+
+		_, err = s.Query(&Criteria{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns([]byte("{"), nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = s.Get("id")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal dead-letter entry")
+	})
+}
+
+func newFollow(t *testing.T) *vocab.ActivityType {
+	t.Helper()
+
+	return vocab.NewFollowActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("https://orb.domain2.com/services/orb"))),
+		vocab.WithID(testutil.MustParseURL("https://orb.domain1.com/services/orb/activities/follow")),
+		vocab.WithActor(testutil.MustParseURL("https://orb.domain1.com/services/orb")),
+	)
+}
+
+func newAnnounce(t *testing.T) *vocab.ActivityType {
+	t.Helper()
+
+	return vocab.NewAnnounceActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("https://orb.domain1.com/vc/1"))),
+		vocab.WithID(testutil.MustParseURL("https://orb.domain1.com/services/orb/activities/announce")),
+		vocab.WithActor(testutil.MustParseURL("https://orb.domain1.com/services/orb")),
+	)
+}
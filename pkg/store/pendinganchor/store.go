@@ -0,0 +1,342 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pendinganchor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const (
+	namespace   = "pendinganchor"
+	pendingTag  = "pending"
+	deadlineTag = "deadline"
+
+	suffixKeyPrefix = "suffix_"
+
+	// deadlineBucketSize is the granularity of the deadline index. Entries are tagged with the bucket of their
+	// deadline so that expired entries may be queried without scanning the whole store.
+	deadlineBucketSize = time.Minute
+
+	// maxDeadlineBuckets is the maximum number of buckets that are queried by GetExpired. If the time range
+	// spans more buckets then all entries are scanned.
+	maxDeadlineBuckets = 60
+)
+
+var logger = log.New("pending-anchor-store")
+
+var (
+	// ErrNotFound is returned if the pending anchor is not found in the store.
+	ErrNotFound = errors.New("pending anchor not found")
+
+	// ErrClaimed is returned by Claim if the entry is owned by another server instance or if a time-out
+	// action was taken on the entry by another instance.
+	ErrClaimed = errors.New("pending anchor claimed by another instance")
+)
+
+// Entry contains the information required to act on an anchor credential whose witness
+// policy has not been satisfied by the deadline.
+type Entry struct {
+	// VCID is the ID of the anchor credential.
+	VCID string `json:"vcID"`
+
+	// AnchorString is the Sidetree anchor string (operation count and core index URI) of the batch.
+	AnchorString string `json:"anchorString"`
+
+	// ProtocolGenesisTime is the genesis time of the protocol version used to create the batch.
+	ProtocolGenesisTime uint64 `json:"protocolGenesisTime"`
+
+	// BatchWitnesses contains the batch witnesses that the offer was sent to.
+	BatchWitnesses []string `json:"batchWitnesses,omitempty"`
+
+	// Suffixes contains the unique suffixes of the DIDs whose operations are included in the batch.
+	Suffixes []string `json:"suffixes,omitempty"`
+
+	// Deadline is the time by which the witness policy must be satisfied.
+	Deadline time.Time `json:"deadline"`
+
+	// Attempt is the number of time-out actions that have been taken.
+	Attempt int `json:"attempt"`
+
+	// Owner is the ID of the server instance that is responsible for taking the time-out actions.
+	Owner string `json:"owner,omitempty"`
+}
+
+// New creates new pending anchor store.
+func New(provider storage.Provider) (*Store, error) {
+	store, err := provider.OpenStore(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pending anchor store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(namespace, storage.StoreConfiguration{TagNames: []string{pendingTag, deadlineTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	return &Store{
+		store: store,
+	}, nil
+}
+
+// Store is db implementation of pending anchor store.
+type Store struct {
+	store storage.Store
+}
+
+// Put saves the pending anchor entry. An existing entry for the same anchor credential is replaced.
+// The entry is also indexed by each of its DID suffixes.
+func (s *Store) Put(entry *Entry) error {
+	return s.put(entry,
+		storage.Tag{Name: pendingTag},
+		storage.Tag{Name: deadlineTag, Value: deadlineBucket(entry.Deadline)},
+	)
+}
+
+// Release stops tracking the deadline of the pending anchor entry for the given anchor credential ID so that
+// it is no longer returned by GetExpired. The entry and its suffix index are kept so that the anchor may still
+// be looked up by suffix until the entry is deleted, i.e. when the final status of the anchor is recorded.
+func (s *Store) Release(vcID string) error {
+	entry, err := s.Get(vcID)
+	if err != nil {
+		return err
+	}
+
+	return s.put(entry)
+}
+
+func (s *Store) put(entry *Entry, tags ...storage.Tag) error {
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pending anchor entry: %w", err)
+	}
+
+	operations := []storage.Operation{
+		{
+			Key:   encode(entry.VCID),
+			Value: value,
+			Tags:  tags,
+		},
+	}
+
+	for _, suffix := range entry.Suffixes {
+		operations = append(operations, storage.Operation{
+			Key:   suffixKeyPrefix + suffix,
+			Value: []byte(entry.VCID),
+		})
+	}
+
+	err = s.store.Batch(operations)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("failed to store pending anchor for vcID[%s]: %w",
+			entry.VCID, err))
+	}
+
+	logger.Debugf("stored pending anchor for vcID[%s] with deadline %s", entry.VCID, entry.Deadline)
+
+	return nil
+}
+
+// Get returns the pending anchor entry for the given anchor credential ID.
+func (s *Store) Get(vcID string) (*Entry, error) {
+	value, err := s.store.Get(encode(vcID))
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("failed to get pending anchor for vcID[%s]: %w", vcID, err))
+	}
+
+	entry := &Entry{}
+
+	err = json.Unmarshal(value, entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pending anchor for vcID[%s]: %w", vcID, err)
+	}
+
+	return entry, nil
+}
+
+// GetBySuffix returns the pending anchor entry that includes an operation for the given DID suffix.
+func (s *Store) GetBySuffix(suffix string) (*Entry, error) {
+	vcID, err := s.store.Get(suffixKeyPrefix + suffix)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("failed to get pending anchor for suffix[%s]: %w", suffix, err))
+	}
+
+	return s.Get(string(vcID))
+}
+
+// Delete deletes the pending anchor entry for the given anchor credential ID along with its suffix index.
+func (s *Store) Delete(vcID string) error {
+	entry, err := s.Get(vcID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	operations := []storage.Operation{{Key: encode(vcID)}}
+
+	for _, suffix := range entry.Suffixes {
+		indexedVCID, e := s.store.Get(suffixKeyPrefix + suffix)
+		if e != nil {
+			if errors.Is(e, storage.ErrDataNotFound) {
+				continue
+			}
+
+			return orberrors.NewTransient(fmt.Errorf("failed to get pending anchor for suffix[%s]: %w", suffix, e))
+		}
+
+		// The suffix may have been included in a more recent anchor, in which case the index is left alone.
+		if string(indexedVCID) == vcID {
+			operations = append(operations, storage.Operation{Key: suffixKeyPrefix + suffix})
+		}
+	}
+
+	err = s.store.Batch(operations)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("failed to delete pending anchor for vcID[%s]: %w", vcID, err))
+	}
+
+	logger.Debugf("deleted pending anchor for vcID[%s]", vcID)
+
+	return nil
+}
+
+// Claim claims the pending anchor entry for the given owner so that only one server instance takes the time-out
+// action for the given attempt. The entry may be claimed if it's already owned by the given owner, or if the
+// other owner's lease has expired, i.e. it didn't act on the entry within the lease period after the deadline.
+// ErrClaimed is returned if the entry may not be claimed or if the attempt of the entry doesn't match the given
+// attempt (i.e. another instance has already acted on the entry).
+//
+// The store doesn't support conditional updates, so the owner is written and then read back in order to detect
+// a concurrent claim by another instance.
+func (s *Store) Claim(vcID, owner string, attempt int, lease time.Duration) (*Entry, error) {
+	entry, err := s.Get(vcID)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Attempt != attempt {
+		return nil, fmt.Errorf("%w: attempt %d was already handled", ErrClaimed, attempt)
+	}
+
+	if entry.Owner == owner {
+		return entry, nil
+	}
+
+	if entry.Owner != "" && time.Now().Before(entry.Deadline.Add(lease)) {
+		return nil, fmt.Errorf("%w: owned by [%s]", ErrClaimed, entry.Owner)
+	}
+
+	logger.Infof("Claiming pending anchor for vcID[%s] from owner [%s]", vcID, entry.Owner)
+
+	entry.Owner = owner
+
+	if err := s.Put(entry); err != nil {
+		return nil, err
+	}
+
+	claimed, err := s.Get(vcID)
+	if err != nil {
+		return nil, err
+	}
+
+	if claimed.Owner != owner || claimed.Attempt != attempt {
+		return nil, fmt.Errorf("%w: concurrently claimed by [%s]", ErrClaimed, claimed.Owner)
+	}
+
+	return claimed, nil
+}
+
+// GetExpired returns the pending anchor entries whose deadline is in the range [from, to). Only the deadline
+// buckets in the given range are queried. If from is zero (or the range is too large) then all entries are scanned.
+func (s *Store) GetExpired(from, to time.Time) ([]*Entry, error) {
+	if from.IsZero() || to.Sub(from) > maxDeadlineBuckets*deadlineBucketSize {
+		return s.query(pendingTag, from, to)
+	}
+
+	var entries []*Entry
+
+	for b := from.Truncate(deadlineBucketSize); !b.After(to); b = b.Add(deadlineBucketSize) {
+		bucketEntries, err := s.query(fmt.Sprintf("%s:%s", deadlineTag, deadlineBucket(b)), from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, bucketEntries...)
+	}
+
+	return entries, nil
+}
+
+func (s *Store) query(query string, from, to time.Time) ([]*Entry, error) {
+	iter, err := s.store.Query(query)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("failed to query pending anchors: %w", err))
+	}
+
+	defer storage.Close(iter, logger)
+
+	var entries []*Entry
+
+	for {
+		ok, err := iter.Next()
+		if err != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("iterator error for pending anchors: %w", err))
+		}
+
+		if !ok {
+			break
+		}
+
+		value, err := iter.Value()
+		if err != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("failed to get iterator value for pending anchors: %w", err))
+		}
+
+		entry := &Entry{}
+
+		err = json.Unmarshal(value, entry)
+		if err != nil {
+			logger.Errorf("Failed to unmarshal pending anchor entry: %s", err)
+
+			continue
+		}
+
+		if !entry.Deadline.Before(from) && entry.Deadline.Before(to) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+func deadlineBucket(t time.Time) string {
+	return strconv.FormatInt(t.Unix()/int64(deadlineBucketSize/time.Second), 10)
+}
+
+func encode(vcID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(vcID))
+}
@@ -0,0 +1,302 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pendinganchor
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	vcID1 = "https://orb.domain1.com/vc/1"
+	vcID2 = "https://orb.domain1.com/vc/2"
+	vcID3 = "https://orb.domain1.com/vc/3"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+		require.NotNil(t, s)
+	})
+
+	t.Run("error - open store fails", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(nil, fmt.Errorf("open store error"))
+
+		s, err := New(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to open pending anchor store: open store error")
+		require.Nil(t, s)
+	})
+
+	t.Run("error - set store config fails", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.SetStoreConfigReturns(fmt.Errorf("set store config error"))
+
+		s, err := New(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to set store configuration: set store config error")
+		require.Nil(t, s)
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		now := time.Now()
+
+		require.NoError(t, s.Put(&Entry{
+			VCID:                vcID1,
+			AnchorString:        "1.hl:uEiA",
+			ProtocolGenesisTime: 100,
+			BatchWitnesses:      []string{"https://orb.domain2.com/services/orb"},
+			Deadline:            now.Add(-time.Second),
+		}))
+
+		require.NoError(t, s.Put(&Entry{VCID: vcID2, Deadline: now.Add(time.Minute)}))
+
+		entry, err := s.Get(vcID1)
+		require.NoError(t, err)
+		require.Equal(t, "1.hl:uEiA", entry.AnchorString)
+		require.Equal(t, uint64(100), entry.ProtocolGenesisTime)
+
+		expired, err := s.GetExpired(time.Time{}, now)
+		require.NoError(t, err)
+		require.Len(t, expired, 1)
+		require.Equal(t, vcID1, expired[0].VCID)
+
+		entry.Attempt++
+		entry.Deadline = now.Add(time.Minute)
+
+		require.NoError(t, s.Put(entry))
+
+		expired, err = s.GetExpired(time.Time{}, now)
+		require.NoError(t, err)
+		require.Empty(t, expired)
+
+		require.NoError(t, s.Delete(vcID1))
+
+		_, err = s.Get(vcID1)
+		require.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("success - get by suffix", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put(&Entry{VCID: vcID1, Suffixes: []string{"suffix1", "suffix2"}}))
+		require.NoError(t, s.Put(&Entry{VCID: vcID2, Suffixes: []string{"suffix2"}}))
+
+		entry, err := s.GetBySuffix("suffix1")
+		require.NoError(t, err)
+		require.Equal(t, vcID1, entry.VCID)
+
+		entry, err = s.GetBySuffix("suffix2")
+		require.NoError(t, err)
+		require.Equal(t, vcID2, entry.VCID)
+
+		expired, err := s.GetExpired(time.Time{}, time.Now())
+		require.NoError(t, err)
+		require.Len(t, expired, 2)
+
+		require.NoError(t, s.Delete(vcID1))
+
+		_, err = s.GetBySuffix("suffix1")
+		require.True(t, errors.Is(err, ErrNotFound))
+
+		// suffix2 was included in a more recent anchor so it's still indexed
+		entry, err = s.GetBySuffix("suffix2")
+		require.NoError(t, err)
+		require.Equal(t, vcID2, entry.VCID)
+
+		require.NoError(t, s.Delete(vcID2))
+		require.NoError(t, s.Delete(vcID2))
+
+		_, err = s.GetBySuffix("suffix2")
+		require.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("success - get expired by deadline index", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		now := time.Now()
+
+		require.NoError(t, s.Put(&Entry{VCID: vcID1, Deadline: now.Add(-time.Second)}))
+		require.NoError(t, s.Put(&Entry{VCID: vcID2, Deadline: now.Add(-3 * time.Hour)}))
+		require.NoError(t, s.Put(&Entry{VCID: vcID3, Deadline: now.Add(time.Minute)}))
+
+		expired, err := s.GetExpired(now.Add(-5*time.Minute), now)
+		require.NoError(t, err)
+		require.Len(t, expired, 1)
+		require.Equal(t, vcID1, expired[0].VCID)
+
+		// The range spans too many buckets so all entries are scanned.
+		expired, err = s.GetExpired(now.Add(-4*time.Hour), now)
+		require.NoError(t, err)
+		require.Len(t, expired, 2)
+
+		expired, err = s.GetExpired(time.Time{}, now.Add(2*time.Minute))
+		require.NoError(t, err)
+		require.Len(t, expired, 3)
+	})
+
+	t.Run("success - release", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put(&Entry{VCID: vcID1, Suffixes: []string{"suffix1"}, Deadline: time.Now()}))
+
+		require.NoError(t, s.Release(vcID1))
+
+		expired, err := s.GetExpired(time.Time{}, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.Empty(t, expired)
+
+		// The anchor may still be looked up by suffix until the entry is deleted.
+		entry, err := s.GetBySuffix("suffix1")
+		require.NoError(t, err)
+		require.Equal(t, vcID1, entry.VCID)
+
+		require.NoError(t, s.Delete(vcID1))
+
+		_, err = s.GetBySuffix("suffix1")
+		require.True(t, errors.Is(err, ErrNotFound))
+
+		require.True(t, errors.Is(s.Release(vcID1), ErrNotFound))
+	})
+
+	t.Run("claim", func(t *testing.T) {
+		const lease = time.Minute
+
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		now := time.Now()
+
+		require.NoError(t, s.Put(&Entry{VCID: vcID1, Deadline: now.Add(-time.Second), Owner: "instance1"}))
+
+		entry, err := s.Claim(vcID1, "instance1", 0, lease)
+		require.NoError(t, err)
+		require.Equal(t, "instance1", entry.Owner)
+
+		// The lease of instance1 hasn't expired.
+		_, err = s.Claim(vcID1, "instance2", 0, lease)
+		require.True(t, errors.Is(err, ErrClaimed))
+
+		// The attempt was already handled.
+		_, err = s.Claim(vcID1, "instance1", 1, lease)
+		require.True(t, errors.Is(err, ErrClaimed))
+
+		// The lease of instance1 has expired.
+		require.NoError(t, s.Put(&Entry{VCID: vcID1, Deadline: now.Add(-2 * lease), Owner: "instance1"}))
+
+		entry, err = s.Claim(vcID1, "instance2", 0, lease)
+		require.NoError(t, err)
+		require.Equal(t, "instance2", entry.Owner)
+
+		entry, err = s.Get(vcID1)
+		require.NoError(t, err)
+		require.Equal(t, "instance2", entry.Owner)
+
+		// An entry without an owner may be claimed by any instance.
+		require.NoError(t, s.Put(&Entry{VCID: vcID2, Deadline: now.Add(-time.Second)}))
+
+		entry, err = s.Claim(vcID2, "instance2", 0, lease)
+		require.NoError(t, err)
+		require.Equal(t, "instance2", entry.Owner)
+
+		_, err = s.Claim(vcID3, "instance1", 0, lease)
+		require.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("error - store errors", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.BatchReturns(fmt.Errorf("batch error"))
+		store.GetReturns(nil, fmt.Errorf("get error"))
+		store.QueryReturns(nil, fmt.Errorf("query error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		err = s.Put(&Entry{VCID: vcID1})
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "batch error")
+
+		_, err = s.Get(vcID1)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "get error")
+
+		_, err = s.GetBySuffix("suffix1")
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "get error")
+
+		err = s.Delete(vcID1)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "get error")
+
+		store.GetReturns([]byte(`{}`), nil)
+
+		err = s.Delete(vcID1)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "batch error")
+
+		_, err = s.GetExpired(time.Time{}, time.Now())
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "query error")
+	})
+
+	t.Run("error - iterator errors", func(t *testing.T) {
+		iterator := &mocks.Iterator{}
+		iterator.NextReturns(false, fmt.Errorf("iterator next() error"))
+
+		store := &mocks.Store{}
+		store.QueryReturns(iterator, nil)
+		store.GetReturns([]byte("{"), nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = s.GetExpired(time.Time{}, time.Now())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "iterator next() error")
+
+		iterator.NextReturns(true, nil)
+		iterator.ValueReturns(nil, fmt.Errorf("iterator value() error"))
+
+		_, err = s.GetExpired(time.Time{}, time.Now())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "iterator value() error")
+
+		_, err = s.Get(vcID1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal pending anchor")
+	})
+}
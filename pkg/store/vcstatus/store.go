@@ -8,7 +8,12 @@ package vcstatus
 
 import (
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hyperledger/aries-framework-go/spi/storage"
@@ -25,6 +30,10 @@ const (
 
 var logger = log.New("vc-status")
 
+// ErrOutcomeClaimed is returned by ClaimOutcome if a different outcome was already claimed for
+// the verifiable credential.
+var ErrOutcomeClaimed = errors.New("outcome already claimed")
+
 // New creates new vc status store.
 func New(provider storage.Provider) (*Store, error) {
 	store, err := provider.OpenStore(namespace)
@@ -38,17 +47,83 @@ func New(provider storage.Provider) (*Store, error) {
 	}
 
 	return &Store{
-		store: store,
+		store:   store,
+		marshal: json.Marshal,
 	}, nil
 }
 
 // Store is db implementation of vc status store.
 type Store struct {
-	store storage.Store
+	store   storage.Store
+	marshal func(v interface{}) ([]byte, error)
+	mutex   sync.Mutex
+}
+
+// StatusEntry contains a verifiable credential status along with the time that the status was added.
+type StatusEntry struct {
+	Status    proof.VCStatus `json:"status"`
+	Timestamp time.Time      `json:"timestamp"`
 }
 
 // AddStatus adds verifiable credential proof collecting status.
 func (s *Store) AddStatus(vcID string, status proof.VCStatus) error {
+	_, err := s.addStatus(vcID, status)
+
+	return err
+}
+
+// ClaimOutcome adds the given outcome status (witnessed or failed) for the given verifiable credential unless
+// a different outcome was already claimed, in which case an error that wraps ErrOutcomeClaimed is returned.
+// The proof handler and the witness time-out actions both claim the outcome before acting on the credential
+// so that a credential is never both published and abandoned. Claiming the same outcome again succeeds.
+//
+// The store doesn't support conditional updates, so the status is written and then the statuses are read back
+// in order to detect an outcome that was concurrently claimed by another instance (the earliest outcome wins).
+func (s *Store) ClaimOutcome(vcID string, status proof.VCStatus) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entries, err := s.getEntries(vcID)
+	if err != nil && !errors.Is(err, errNotFound) {
+		return err
+	}
+
+	if outcome := getOutcome(entries); outcome != nil {
+		if outcome.Status != status {
+			return fmt.Errorf("%w: vcID[%s] has outcome '%s'", ErrOutcomeClaimed, vcID, outcome.Status)
+		}
+
+		return nil
+	}
+
+	key, err := s.addStatus(vcID, status)
+	if err != nil {
+		return err
+	}
+
+	entries, err = s.getEntries(vcID)
+	if err != nil {
+		return err
+	}
+
+	outcome := getOutcome(entries)
+
+	if outcome.key != key && outcome.Status != status {
+		logger.Infof("outcome '%s' for vcID[%s] was concurrently claimed - removing outcome '%s'",
+			outcome.Status, vcID, status)
+
+		if err := s.store.Delete(key); err != nil {
+			return orberrors.NewTransient(fmt.Errorf("failed to delete vcID[%s] status '%s': %w",
+				vcID, status, err))
+		}
+
+		return fmt.Errorf("%w: vcID[%s] has outcome '%s'", ErrOutcomeClaimed, vcID, outcome.Status)
+	}
+
+	return nil
+}
+
+func (s *Store) addStatus(vcID string, status proof.VCStatus) (string, error) {
 	vcIDEncoded := base64.RawURLEncoding.EncodeToString([]byte(vcID))
 
 	tag := storage.Tag{
@@ -56,19 +131,75 @@ func (s *Store) AddStatus(vcID string, status proof.VCStatus) error {
 		Value: vcIDEncoded,
 	}
 
-	err := s.store.Put(uuid.New().String(), []byte(status), tag)
+	value, err := s.marshal(&StatusEntry{Status: status, Timestamp: time.Now()})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal vcID[%s] status '%s': %w", vcID, status, err)
+	}
+
+	key := uuid.New().String()
+
+	err = s.store.Put(key, value, tag)
 	if err != nil {
-		return orberrors.NewTransient(fmt.Errorf("failed to store vcID[%s] status '%s': %w",
+		return "", orberrors.NewTransient(fmt.Errorf("failed to store vcID[%s] status '%s': %w",
 			vcID, status, err))
 	}
 
 	logger.Debugf("stored vcID[%s] status '%s'", vcID, status)
 
-	return nil
+	return key, nil
 }
 
-// GetStatus retrieves proof collection status for the given verifiable credential.
+// GetStatus retrieves proof collection status for the given verifiable credential. If the credential
+// has been completed then the "completed" status is returned. Otherwise, if an outcome (witnessed or failed)
+// was claimed then the outcome is returned, otherwise the most recent status is returned.
 func (s *Store) GetStatus(vcID string) (proof.VCStatus, error) {
+	entries, err := s.getEntries(vcID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, entry := range entries {
+		if entry.Status == proof.VCStatusCompleted {
+			return proof.VCStatusCompleted, nil
+		}
+	}
+
+	status := entries[len(entries)-1].Status
+
+	if outcome := getOutcome(entries); outcome != nil {
+		status = outcome.Status
+	}
+
+	logger.Debugf("status for vcID[%s]: %s", vcID, status)
+
+	return status, nil
+}
+
+// GetStatusHistory retrieves all of the statuses for the given verifiable credential, ordered by the time
+// that they were added.
+func (s *Store) GetStatusHistory(vcID string) ([]*StatusEntry, error) {
+	entries, err := s.getEntries(vcID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*StatusEntry, len(entries))
+
+	for i, entry := range entries {
+		history[i] = entry.StatusEntry
+	}
+
+	return history, nil
+}
+
+var errNotFound = errors.New("status not found")
+
+type keyedEntry struct {
+	*StatusEntry
+	key string
+}
+
+func (s *Store) getEntries(vcID string) ([]*keyedEntry, error) {
 	var err error
 
 	vcIDEncoded := base64.RawURLEncoding.EncodeToString([]byte(vcID))
@@ -77,41 +208,81 @@ func (s *Store) GetStatus(vcID string) (proof.VCStatus, error) {
 
 	iter, err := s.store.Query(query)
 	if err != nil {
-		return "", orberrors.NewTransient(fmt.Errorf("failed to get statuses for vcID[%s] query[%s]: %w",
+		return nil, orberrors.NewTransient(fmt.Errorf("failed to get statuses for vcID[%s] query[%s]: %w",
 			vcID, query, err))
 	}
 
+	defer storage.Close(iter, logger)
+
 	ok, err := iter.Next()
 	if err != nil {
-		return "", orberrors.NewTransient(fmt.Errorf("iterator error for vcID[%s] statuses: %w", vcID, err))
+		return nil, orberrors.NewTransient(fmt.Errorf("iterator error for vcID[%s] statuses: %w", vcID, err))
 	}
 
 	if !ok {
-		return "", fmt.Errorf("status not found for vcID: %s", vcID)
+		return nil, fmt.Errorf("%w for vcID: %s", errNotFound, vcID)
 	}
 
-	var value []byte
+	var entries []*keyedEntry
 
 	for ok {
-		value, err = iter.Value()
+		var (
+			key   string
+			value []byte
+		)
+
+		key, err = iter.Key()
 		if err != nil {
-			return "", orberrors.NewTransient(fmt.Errorf("failed to get iterator value for vcID[%s]: %w",
+			return nil, orberrors.NewTransient(fmt.Errorf("failed to get iterator key for vcID[%s]: %w",
 				vcID, err))
 		}
 
-		if proof.VCStatus(value) == proof.VCStatusCompleted {
-			return proof.VCStatusCompleted, nil
+		value, err = iter.Value()
+		if err != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("failed to get iterator value for vcID[%s]: %w",
+				vcID, err))
 		}
 
+		entries = append(entries, &keyedEntry{StatusEntry: unmarshalStatusEntry(value), key: key})
+
 		ok, err = iter.Next()
 		if err != nil {
-			return "", orberrors.NewTransient(fmt.Errorf("iterator error for vcID[%s]: %w", vcID, err))
+			return nil, orberrors.NewTransient(fmt.Errorf("iterator error for vcID[%s]: %w", vcID, err))
 		}
 	}
 
-	status := proof.VCStatus(value)
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Timestamp.Equal(entries[j].Timestamp) {
+			return entries[i].key < entries[j].key
+		}
 
-	logger.Debugf("status for vcID[%s]: %s", vcID, status)
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
 
-	return status, nil
+	return entries, nil
+}
+
+// getOutcome returns the earliest outcome (completed, witnessed or failed) from the given
+// (ordered) entries or nil if no outcome was claimed.
+func getOutcome(entries []*keyedEntry) *keyedEntry {
+	for _, entry := range entries {
+		switch entry.Status {
+		case proof.VCStatusCompleted, proof.VCStatusWitnessed, proof.VCStatusFailed:
+			return entry
+		}
+	}
+
+	return nil
+}
+
+// unmarshalStatusEntry unmarshals the stored status entry. Statuses that were stored by previous versions
+// contain only the status (with no timestamp).
+func unmarshalStatusEntry(value []byte) *StatusEntry {
+	entry := &StatusEntry{}
+
+	if err := json.Unmarshal(value, entry); err != nil {
+		return &StatusEntry{Status: proof.VCStatus(value)}
+	}
+
+	return entry
 }
@@ -7,10 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package vcstatus
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
 	"github.com/stretchr/testify/require"
 
 	"github.com/trustbloc/orb/pkg/anchor/proof"
@@ -176,3 +182,202 @@ func TestStore_Get(t *testing.T) {
 		require.Contains(t, err.Error(), "iterator value() error")
 	})
 }
+
+func TestStore_GetStatusHistory(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		provider := mem.NewProvider()
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		require.NoError(t, s.AddStatus(vcID, proof.VCStatusInProcess))
+		require.NoError(t, s.AddStatus(vcID, proof.VCStatusTimedOut))
+		require.NoError(t, s.AddStatus(vcID, proof.VCStatusEscalated))
+
+		entries, err := s.GetStatusHistory(vcID)
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		require.Equal(t, proof.VCStatusInProcess, entries[0].Status)
+		require.Equal(t, proof.VCStatusTimedOut, entries[1].Status)
+		require.Equal(t, proof.VCStatusEscalated, entries[2].Status)
+		require.False(t, entries[2].Timestamp.Before(entries[0].Timestamp))
+
+		status, err := s.GetStatus(vcID)
+		require.NoError(t, err)
+		require.Equal(t, proof.VCStatusEscalated, status)
+	})
+
+	t.Run("success - status without timestamp", func(t *testing.T) {
+		provider := mem.NewProvider()
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		require.NoError(t, s.store.Put("key", []byte(proof.VCStatusInProcess),
+			storage.Tag{Name: index, Value: base64.RawURLEncoding.EncodeToString([]byte(vcID))}))
+
+		entries, err := s.GetStatusHistory(vcID)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, proof.VCStatusInProcess, entries[0].Status)
+		require.True(t, entries[0].Timestamp.IsZero())
+	})
+
+	t.Run("error - marshal error", func(t *testing.T) {
+		provider := mem.NewProvider()
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		s.marshal = func(v interface{}) ([]byte, error) {
+			return nil, fmt.Errorf("injected marshal error")
+		}
+
+		err = s.AddStatus(vcID, proof.VCStatusInProcess)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected marshal error")
+	})
+}
+
+func TestStore_ClaimOutcome(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		require.NoError(t, s.AddStatus(vcID, proof.VCStatusInProcess))
+		require.NoError(t, s.ClaimOutcome(vcID, proof.VCStatusWitnessed))
+
+		// Claiming the same outcome again succeeds.
+		require.NoError(t, s.ClaimOutcome(vcID, proof.VCStatusWitnessed))
+
+		err = s.ClaimOutcome(vcID, proof.VCStatusFailed)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrOutcomeClaimed))
+
+		// A status that is added after the outcome doesn't replace the outcome.
+		require.NoError(t, s.AddStatus(vcID, proof.VCStatusTimedOut))
+
+		status, err := s.GetStatus(vcID)
+		require.NoError(t, err)
+		require.Equal(t, proof.VCStatusWitnessed, status)
+
+		require.NoError(t, s.AddStatus(vcID, proof.VCStatusCompleted))
+
+		status, err = s.GetStatus(vcID)
+		require.NoError(t, err)
+		require.Equal(t, proof.VCStatusCompleted, status)
+
+		err = s.ClaimOutcome(vcID, proof.VCStatusFailed)
+		require.True(t, errors.Is(err, ErrOutcomeClaimed))
+	})
+
+	t.Run("success - no status", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		require.NoError(t, s.ClaimOutcome(vcID, proof.VCStatusFailed))
+
+		status, err := s.GetStatus(vcID)
+		require.NoError(t, err)
+		require.Equal(t, proof.VCStatusFailed, status)
+	})
+
+	t.Run("concurrent claims", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		require.NoError(t, s.AddStatus(vcID, proof.VCStatusInProcess))
+
+		statuses := []proof.VCStatus{proof.VCStatusWitnessed, proof.VCStatusFailed}
+		claimed := make([]bool, 10)
+
+		var wg sync.WaitGroup
+
+		for i := range claimed {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				claimed[i] = s.ClaimOutcome(vcID, statuses[i%2]) == nil
+			}(i)
+		}
+
+		wg.Wait()
+
+		outcome, err := s.GetStatus(vcID)
+		require.NoError(t, err)
+
+		for i, ok := range claimed {
+			require.Equal(t, statuses[i%2] == outcome, ok)
+		}
+	})
+
+	t.Run("outcome concurrently claimed by another instance", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		require.NoError(t, s.AddStatus(vcID, proof.VCStatusInProcess))
+
+		s.store = &concurrentClaimStore{Store: s.store, status: proof.VCStatusWitnessed}
+
+		err = s.ClaimOutcome(vcID, proof.VCStatusFailed)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrOutcomeClaimed))
+
+		entries, err := s.GetStatusHistory(vcID)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+
+		status, err := s.GetStatus(vcID)
+		require.NoError(t, err)
+		require.Equal(t, proof.VCStatusWitnessed, status)
+	})
+
+	t.Run("error - store error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(nil, fmt.Errorf("query error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		err = s.ClaimOutcome(vcID, proof.VCStatusFailed)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "query error")
+	})
+
+	t.Run("error - put error", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		s.marshal = func(v interface{}) ([]byte, error) {
+			return nil, fmt.Errorf("injected marshal error")
+		}
+
+		err = s.ClaimOutcome(vcID, proof.VCStatusFailed)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected marshal error")
+	})
+}
+
+// concurrentClaimStore simulates another instance that claims the given outcome just before the status is stored.
+type concurrentClaimStore struct {
+	storage.Store
+	status proof.VCStatus
+}
+
+func (s *concurrentClaimStore) Put(key string, value []byte, tags ...storage.Tag) error {
+	other, err := json.Marshal(&StatusEntry{Status: s.status, Timestamp: time.Now().Add(-time.Millisecond)})
+	if err != nil {
+		return err
+	}
+
+	if err := s.Store.Put("other-instance", other, tags...); err != nil {
+		return err
+	}
+
+	return s.Store.Put(key, value, tags...)
+}
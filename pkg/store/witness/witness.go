@@ -9,7 +9,9 @@ package witness
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hyperledger/aries-framework-go/spi/storage"
@@ -20,8 +22,13 @@ import (
 )
 
 const (
-	namespace = "witness"
-	vcIndex   = "vcID"
+	namespace    = "witness"
+	vcIndex      = "vcID"
+	witnessIndex = "witness"
+
+	// witnessIndexMigrationKey is the key of the entry that records that the entries which were stored before
+	// the witness index was introduced have been tagged with the witness.
+	witnessIndexMigrationKey = "migration_witness_index"
 )
 
 var logger = log.New("witness-store")
@@ -33,14 +40,21 @@ func New(provider storage.Provider) (*Store, error) {
 		return nil, fmt.Errorf("failed to open anchor credential witness store: %w", err)
 	}
 
-	err = provider.SetStoreConfig(namespace, storage.StoreConfiguration{TagNames: []string{vcIndex}})
+	err = provider.SetStoreConfig(namespace, storage.StoreConfiguration{TagNames: []string{vcIndex, witnessIndex}})
 	if err != nil {
 		return nil, fmt.Errorf("failed to set store configuration: %w", err)
 	}
 
-	return &Store{
+	s := &Store{
 		store: store,
-	}, nil
+	}
+
+	err = s.migrateWitnessIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate witness index: %w", err)
+	}
+
+	return s, nil
 }
 
 // Store is db implementation of anchor credential witness store.
@@ -65,12 +79,7 @@ func (s *Store) Put(vcID string, witnesses []*proof.WitnessProof) error {
 		op := storage.Operation{
 			Key:   uuid.New().String(),
 			Value: value,
-			Tags: []storage.Tag{
-				{
-					Name:  vcIndex,
-					Value: vcIDEncoded,
-				},
-			},
+			Tags:  getTags(vcIDEncoded, w.Witness),
 		}
 
 		operations[i] = op
@@ -262,13 +271,14 @@ func (s *Store) AddProof(vcID, witness string, p []byte) error { //nolint:funlen
 			}
 
 			w.Proof = p
+			w.Received = time.Now()
 
 			witnessProofBytes, marshalErr := json.Marshal(w)
 			if marshalErr != nil {
 				return fmt.Errorf("failed to marshal witness[%s] proof for vcID[%s]: %w", w.Witness, vcID, marshalErr)
 			}
 
-			err = s.store.Put(key, witnessProofBytes, storage.Tag{Name: vcIndex, Value: vcIDEncoded})
+			err = s.store.Put(key, witnessProofBytes, getTags(vcIDEncoded, witness)...)
 			if err != nil {
 				return orberrors.NewTransient(fmt.Errorf("failed to add proof for anchor credential vcID[%s] and witness[%s]: %w",
 					vcID, witness, err))
@@ -291,3 +301,277 @@ func (s *Store) AddProof(vcID, witness string, p []byte) error { //nolint:funlen
 
 	return nil
 }
+
+// DeletePending deletes all witness entries for the given witness that have not yet received a proof
+// (e.g. after the witness has been removed) and returns the IDs of the affected anchor credentials.
+func (s *Store) DeletePending(witness string) ([]string, error) {
+	keys, vcIDs, err := s.getPending(witness)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		logger.Debugf("no pending entries to delete for witness[%s]", witness)
+
+		return nil, nil
+	}
+
+	operations := make([]storage.Operation, len(keys))
+
+	for i, key := range keys {
+		operations[i] = storage.Operation{Key: key}
+	}
+
+	err = s.store.Batch(operations)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("failed to delete pending entries for witness[%s]: %w",
+			witness, err))
+	}
+
+	logger.Debugf("deleted %d pending entries for witness[%s]", len(keys), witness)
+
+	return vcIDs, nil
+}
+
+// getPending returns the keys and anchor credential IDs of the entries for the given witness
+// that have not yet received a proof.
+func (s *Store) getPending(witness string) ([]string, []string, error) {
+	query := fmt.Sprintf("%s:%s", witnessIndex, base64.RawURLEncoding.EncodeToString([]byte(witness)))
+
+	iter, err := s.store.Query(query)
+	if err != nil {
+		return nil, nil, orberrors.NewTransient(fmt.Errorf("failed to get entries for witness[%s]: %w", witness, err))
+	}
+
+	defer func() {
+		err = iter.Close()
+		if err != nil {
+			logger.Errorf("failed to close iterator: %s", err.Error())
+		}
+	}()
+
+	ok, err := iter.Next()
+	if err != nil {
+		return nil, nil, orberrors.NewTransient(fmt.Errorf("iterator error for witness[%s] : %w", witness, err))
+	}
+
+	var keys, vcIDs []string
+
+	for ok {
+		var value []byte
+
+		value, err = iter.Value()
+		if err != nil {
+			return nil, nil, orberrors.NewTransient(fmt.Errorf("failed to get iterator value for witness[%s]: %w",
+				witness, err))
+		}
+
+		var w proof.WitnessProof
+
+		err = json.Unmarshal(value, &w)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal anchor credential witness from store value for "+
+				"witness[%s]: %w", witness, err)
+		}
+
+		if w.Proof == nil {
+			var (
+				key  string
+				tags []storage.Tag
+			)
+
+			tags, err = iter.Tags()
+			if err != nil {
+				return nil, nil, orberrors.NewTransient(fmt.Errorf("failed to get iterator tags for witness[%s]: %w",
+					witness, err))
+			}
+
+			key, err = iter.Key()
+			if err != nil {
+				return nil, nil, orberrors.NewTransient(fmt.Errorf("failed to get iterator key for witness[%s]: %w",
+					witness, err))
+			}
+
+			keys = append(keys, key)
+
+			vcID, e := getVCID(tags)
+			if e != nil {
+				return nil, nil, fmt.Errorf("entry [%s] for witness[%s]: %w", key, witness, e)
+			}
+
+			vcIDs = appendUnique(vcIDs, vcID)
+		}
+
+		ok, err = iter.Next()
+		if err != nil {
+			return nil, nil, orberrors.NewTransient(fmt.Errorf("iterator error for witness[%s] : %w", witness, err))
+		}
+	}
+
+	return keys, vcIDs, nil
+}
+
+// migrateWitnessIndex tags the entries that were stored before the witness index was introduced with the
+// witness so that they may be queried by witness. The store is only scanned once, after which a marker entry
+// (which isn't returned by any of the queries) is stored.
+func (s *Store) migrateWitnessIndex() error { //nolint:cyclop
+	_, err := s.store.Get(witnessIndexMigrationKey)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, storage.ErrDataNotFound) {
+		return orberrors.NewTransient(fmt.Errorf("get migration marker: %w", err))
+	}
+
+	iter, err := s.store.Query(vcIndex)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("query entries: %w", err))
+	}
+
+	defer func() {
+		err = iter.Close()
+		if err != nil {
+			logger.Errorf("failed to close iterator: %s", err.Error())
+		}
+	}()
+
+	var operations []storage.Operation
+
+	ok, err := iter.Next()
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("iterator error: %w", err))
+	}
+
+	for ok {
+		var tags []storage.Tag
+
+		tags, err = iter.Tags()
+		if err != nil {
+			return orberrors.NewTransient(fmt.Errorf("failed to get iterator tags: %w", err))
+		}
+
+		if !hasTag(tags, witnessIndex) {
+			op, e := getMigrationOperation(iter, tags)
+			if e != nil {
+				return e
+			}
+
+			operations = append(operations, op)
+		}
+
+		ok, err = iter.Next()
+		if err != nil {
+			return orberrors.NewTransient(fmt.Errorf("iterator error: %w", err))
+		}
+	}
+
+	if len(operations) > 0 {
+		err = s.store.Batch(operations)
+		if err != nil {
+			return orberrors.NewTransient(fmt.Errorf("failed to tag entries with witness: %w", err))
+		}
+	}
+
+	err = s.store.Put(witnessIndexMigrationKey, []byte(time.Now().UTC().Format(time.RFC3339)))
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store migration marker: %w", err))
+	}
+
+	if len(operations) > 0 {
+		logger.Infof("Tagged %d witness entries with the witness index", len(operations))
+	}
+
+	return nil
+}
+
+func getMigrationOperation(iter storage.Iterator, tags []storage.Tag) (storage.Operation, error) {
+	key, err := iter.Key()
+	if err != nil {
+		return storage.Operation{}, orberrors.NewTransient(fmt.Errorf("failed to get iterator key: %w", err))
+	}
+
+	value, err := iter.Value()
+	if err != nil {
+		return storage.Operation{}, orberrors.NewTransient(fmt.Errorf("failed to get iterator value: %w", err))
+	}
+
+	var w proof.WitnessProof
+
+	err = json.Unmarshal(value, &w)
+	if err != nil {
+		return storage.Operation{}, fmt.Errorf("failed to unmarshal anchor credential witness [%s]: %w", key, err)
+	}
+
+	var vcIDEncoded string
+
+	for _, tag := range tags {
+		if tag.Name == vcIndex {
+			vcIDEncoded = tag.Value
+		}
+	}
+
+	return storage.Operation{
+		Key:   key,
+		Value: value,
+		Tags:  getTags(vcIDEncoded, w.Witness),
+	}, nil
+}
+
+func getVCID(tags []storage.Tag) (string, error) {
+	for _, tag := range tags {
+		if tag.Name == vcIndex {
+			vcID, err := base64.RawURLEncoding.DecodeString(tag.Value)
+			if err != nil {
+				return "", fmt.Errorf("decode vcID: %w", err)
+			}
+
+			return string(vcID), nil
+		}
+	}
+
+	return "", fmt.Errorf("tag [%s] not found", vcIndex)
+}
+
+func hasTag(tags []storage.Tag, name string) bool {
+	for _, tag := range tags {
+		if tag.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func appendUnique(values []string, newValues ...string) []string {
+	for _, v := range newValues {
+		if !contains(values, v) {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getTags(vcIDEncoded, witness string) []storage.Tag {
+	return []storage.Tag{
+		{
+			Name:  vcIndex,
+			Value: vcIDEncoded,
+		},
+		{
+			Name:  witnessIndex,
+			Value: base64.RawURLEncoding.EncodeToString([]byte(witness)),
+		},
+	}
+}
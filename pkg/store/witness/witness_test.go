@@ -8,11 +8,13 @@ package witness
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"testing"
 
 	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
 	"github.com/stretchr/testify/require"
 
 	"github.com/trustbloc/orb/pkg/anchor/proof"
@@ -54,6 +56,120 @@ func TestNew(t *testing.T) {
 	})
 }
 
+func TestNew_MigrateWitnessIndex(t *testing.T) {
+	t.Run("success - migrated once", func(t *testing.T) {
+		provider := mem.NewProvider()
+
+		putLegacyEntries(t, provider, getTestWitness())
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		store, err := provider.OpenStore(namespace)
+		require.NoError(t, err)
+
+		tags, err := store.GetTags("legacy-0")
+		require.NoError(t, err)
+		require.Len(t, tags, 2)
+
+		_, err = store.Get(witnessIndexMigrationKey)
+		require.NoError(t, err)
+
+		// Entries without the witness tag are no longer migrated since the store has already been migrated.
+		putLegacyEntries(t, provider, &proof.WitnessProof{Type: proof.WitnessTypeBatch, Witness: "witness2"})
+
+		_, err = New(provider)
+		require.NoError(t, err)
+
+		tags, err = store.GetTags("legacy-0")
+		require.NoError(t, err)
+		require.Len(t, tags, 1)
+
+		vcIDs, err := s.DeletePending("witness2")
+		require.NoError(t, err)
+		require.Empty(t, vcIDs)
+	})
+
+	t.Run("error - get marker error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns(nil, fmt.Errorf("get error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		_, err := New(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to migrate witness index: get migration marker: get error")
+	})
+
+	t.Run("error - query error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns(nil, storage.ErrDataNotFound)
+		store.QueryReturns(nil, fmt.Errorf("query error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		_, err := New(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "query error")
+	})
+
+	t.Run("error - iterator value() error", func(t *testing.T) {
+		iterator := &mocks.Iterator{}
+		iterator.NextReturns(true, nil)
+		iterator.ValueReturns(nil, fmt.Errorf("iterator value() error"))
+
+		store := &mocks.Store{}
+		store.GetReturns(nil, storage.ErrDataNotFound)
+		store.QueryReturns(iterator, nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		_, err := New(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "iterator value() error")
+	})
+
+	t.Run("error - batch error", func(t *testing.T) {
+		witnessBytes, err := json.Marshal(getTestWitness())
+		require.NoError(t, err)
+
+		iterator := &mocks.Iterator{}
+		iterator.NextReturnsOnCall(0, true, nil)
+		iterator.ValueReturns(witnessBytes, nil)
+
+		store := &mocks.Store{}
+		store.GetReturns(nil, storage.ErrDataNotFound)
+		store.QueryReturns(iterator, nil)
+		store.BatchReturns(fmt.Errorf("batch error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		_, err = New(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "batch error")
+	})
+
+	t.Run("error - put marker error", func(t *testing.T) {
+		iterator := &mocks.Iterator{}
+
+		store := &mocks.Store{}
+		store.GetReturns(nil, storage.ErrDataNotFound)
+		store.QueryReturns(iterator, nil)
+		store.PutReturns(fmt.Errorf("put error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		_, err := New(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "store migration marker: put error")
+	})
+}
+
 func TestStore_Put(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		provider := mem.NewProvider()
@@ -297,6 +413,217 @@ func TestStore_Delete(t *testing.T) {
 	})
 }
 
+func TestStore_DeletePending(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		provider := mem.NewProvider()
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		const (
+			vcID2    = "vcID2"
+			witness2 = "witness2"
+		)
+
+		err = s.Put(vcID, []*proof.WitnessProof{getTestWitness(), {Type: proof.WitnessTypeBatch, Witness: witness2}})
+		require.NoError(t, err)
+
+		err = s.Put(vcID2, []*proof.WitnessProof{getTestWitness()})
+		require.NoError(t, err)
+
+		require.NoError(t, s.AddProof(vcID2, witness, []byte(witnessProof)))
+
+		vcIDs, err := s.DeletePending(witness)
+		require.NoError(t, err)
+		require.Equal(t, []string{vcID}, vcIDs)
+
+		ops, err := s.Get(vcID)
+		require.NoError(t, err)
+		require.Len(t, ops, 1)
+		require.Equal(t, witness2, ops[0].Witness)
+
+		// The witness entry that already has a proof is not deleted.
+		ops, err = s.Get(vcID2)
+		require.NoError(t, err)
+		require.Len(t, ops, 1)
+		require.Equal(t, witness, ops[0].Witness)
+
+		vcIDs, err = s.DeletePending(witness)
+		require.NoError(t, err)
+		require.Empty(t, vcIDs)
+	})
+
+	t.Run("success - legacy entries without witness tag", func(t *testing.T) {
+		provider := mem.NewProvider()
+
+		putLegacyEntries(t, provider, getTestWitness(), &proof.WitnessProof{Type: proof.WitnessTypeBatch, Witness: "witness2"})
+
+		// The legacy entries are tagged with the witness when the store is created.
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		vcIDs, err := s.DeletePending(witness)
+		require.NoError(t, err)
+		require.Equal(t, []string{vcID}, vcIDs)
+
+		ops, err := s.Get(vcID)
+		require.NoError(t, err)
+		require.Len(t, ops, 1)
+		require.Equal(t, "witness2", ops[0].Witness)
+
+		vcIDs, err = s.DeletePending("witness2")
+		require.NoError(t, err)
+		require.Equal(t, []string{vcID}, vcIDs)
+	})
+
+	t.Run("error - query store error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(nil, fmt.Errorf("query error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = s.DeletePending(witness)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "query error")
+	})
+
+	t.Run("error - iterator next() error", func(t *testing.T) {
+		iterator := &mocks.Iterator{}
+		iterator.NextReturns(false, fmt.Errorf("iterator next() error"))
+
+		store := &mocks.Store{}
+		store.QueryReturns(iterator, nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = s.DeletePending(witness)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "iterator next() error")
+	})
+
+	t.Run("error - iterator value() error", func(t *testing.T) {
+		iterator := &mocks.Iterator{}
+		iterator.NextReturns(true, nil)
+		iterator.ValueReturns(nil, fmt.Errorf("iterator value() error"))
+
+		store := &mocks.Store{}
+		store.QueryReturns(iterator, nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = s.DeletePending(witness)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "iterator value() error")
+	})
+
+	t.Run("error - iterator key() error", func(t *testing.T) {
+		witnessBytes, err := json.Marshal(getTestWitness())
+		require.NoError(t, err)
+
+		iterator := &mocks.Iterator{}
+		iterator.NextReturns(true, nil)
+		iterator.ValueReturns(witnessBytes, nil)
+		iterator.KeyReturns("", fmt.Errorf("iterator key() error"))
+
+		store := &mocks.Store{}
+		store.QueryReturns(iterator, nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = s.DeletePending(witness)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "iterator key() error")
+	})
+
+	t.Run("error - iterator tags() error", func(t *testing.T) {
+		witnessBytes, err := json.Marshal(getTestWitness())
+		require.NoError(t, err)
+
+		iterator := &mocks.Iterator{}
+		iterator.NextReturns(true, nil)
+		iterator.ValueReturns(witnessBytes, nil)
+		iterator.TagsReturns(nil, fmt.Errorf("iterator tags() error"))
+
+		store := &mocks.Store{}
+		store.QueryReturns(iterator, nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = s.DeletePending(witness)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "iterator tags() error")
+	})
+
+	t.Run("error - missing vcID tag", func(t *testing.T) {
+		witnessBytes, err := json.Marshal(getTestWitness())
+		require.NoError(t, err)
+
+		iterator := &mocks.Iterator{}
+		iterator.NextReturns(true, nil)
+		iterator.ValueReturns(witnessBytes, nil)
+		iterator.KeyReturns("key", nil)
+
+		store := &mocks.Store{}
+		store.QueryReturns(iterator, nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = s.DeletePending(witness)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "tag [vcID] not found")
+	})
+
+	t.Run("error - batch error", func(t *testing.T) {
+		witnessBytes, err := json.Marshal(getTestWitness())
+		require.NoError(t, err)
+
+		iterator := &mocks.Iterator{}
+		iterator.NextReturnsOnCall(0, true, nil)
+		iterator.NextReturnsOnCall(1, false, nil)
+		iterator.ValueReturns(witnessBytes, nil)
+		iterator.KeyReturns("key", nil)
+		iterator.TagsReturns([]storage.Tag{{Name: vcIndex, Value: "dmNJRA"}}, nil)
+
+		store := &mocks.Store{}
+		store.QueryReturns(iterator, nil)
+		store.BatchReturns(fmt.Errorf("batch error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = s.DeletePending(witness)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "batch error")
+	})
+}
+
 func TestStore_AddProof(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		provider := mem.NewProvider()
@@ -321,6 +648,7 @@ func TestStore_AddProof(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, len(witnesses), 1)
 		bytes.Equal(wf, witnesses[0].Proof)
+		require.False(t, witnesses[0].Received.IsZero())
 	})
 
 	t.Run("success - multiple witnesses were recorded", func(t *testing.T) {
@@ -549,3 +877,21 @@ const witnessProof = `{
     "verificationMethod": "did:web:abc.com#2130bhDAK-2jKsOXJiEDG909Jux4rcYEpFsYzVlqdAY"
   }
 }`
+
+// putLegacyEntries stores the given witnesses for vcID without the witness tag, as stored by previous versions.
+func putLegacyEntries(t *testing.T, provider storage.Provider, witnesses ...*proof.WitnessProof) {
+	t.Helper()
+
+	store, err := provider.OpenStore(namespace)
+	require.NoError(t, err)
+
+	vcIDEncoded := base64.RawURLEncoding.EncodeToString([]byte(vcID))
+
+	for i, w := range witnesses {
+		witnessBytes, e := json.Marshal(w)
+		require.NoError(t, e)
+
+		require.NoError(t, store.Put(fmt.Sprintf("legacy-%d", i), witnessBytes,
+			storage.Tag{Name: vcIndex, Value: vcIDEncoded}))
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcsigner
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/multiformats/go-multibase"
+)
+
+const (
+	jsonldType       = "type"
+	jsonldProofValue = "proofValue"
+)
+
+// toMultibaseProofValue re-encodes the base64url proof value (as produced by the linked data signer)
+// of the given proof as a multibase (base58btc) string.
+func toMultibaseProofValue(proof map[string]interface{}) error {
+	value, ok := proof[jsonldProofValue].(string)
+	if !ok {
+		return fmt.Errorf("missing %s in proof", jsonldProofValue)
+	}
+
+	proofValue, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return fmt.Errorf("decode proof value: %w", err)
+	}
+
+	encoded, err := multibase.Encode(multibase.Base58BTC, proofValue)
+	if err != nil {
+		return fmt.Errorf("encode proof value: %w", err)
+	}
+
+	proof[jsonldProofValue] = encoded
+
+	return nil
+}
+
+// fromMultibaseProofValues re-encodes the multibase proof values of the given document's proofs as base64url
+// (which is the only encoding understood by the linked data verifier). Only the proofs of suites which use
+// multibase proof values are converted.
+func fromMultibaseProofValues(doc map[string]interface{}) error {
+	var proofs []interface{}
+
+	switch p := doc["proof"].(type) {
+	case map[string]interface{}:
+		proofs = []interface{}{p}
+	case []interface{}:
+		proofs = p
+	}
+
+	for _, p := range proofs {
+		proof, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		proofType, ok := proof[jsonldType].(string)
+		if !ok {
+			continue
+		}
+
+		info, ok := suites[proofType]
+		if !ok || !info.multibaseProofValue {
+			continue
+		}
+
+		value, ok := proof[jsonldProofValue].(string)
+		if !ok {
+			continue
+		}
+
+		encoding, proofValue, err := multibase.Decode(value)
+		if err != nil {
+			return fmt.Errorf("decode multibase proof value of %s proof: %w", proofType, err)
+		}
+
+		if encoding != multibase.Base58BTC {
+			return fmt.Errorf("proof value of %s proof is not base58btc encoded", proofType)
+		}
+
+		proof[jsonldProofValue] = base64.RawURLEncoding.EncodeToString(proofValue)
+	}
+
+	return nil
+}
@@ -14,21 +14,13 @@ import (
 
 	ariescrypto "github.com/hyperledger/aries-framework-go/pkg/crypto"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
-	ariessigner "github.com/hyperledger/aries-framework-go/pkg/doc/signature/signer"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
-	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/ed25519signature2018"
-	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/jsonwebsignature2020"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/piprate/json-gold/ld"
 )
 
 const (
-	// Ed25519Signature2018 ed25519 signature suite.
-	Ed25519Signature2018 = "Ed25519Signature2018"
-	// JSONWebSignature2020 json web signature suite.
-	JSONWebSignature2020 = "JsonWebSignature2020"
-
 	// AssertionMethod assertionMethod.
 	AssertionMethod = "assertionMethod"
 )
@@ -46,12 +38,29 @@ type SigningParams struct {
 	Domain             string
 }
 
+type keyIDProvider interface {
+	CurrentKeyID() (string, error)
+}
+
+type keySigner interface {
+	// Sign signs the given message with the key of the given ID.
+	Sign(keyID string, msg []byte) ([]byte, error)
+}
+
 // Providers contains all of the providers required by verifiable credential signer.
 type Providers struct {
 	DocLoader  ld.DocumentLoader
 	KeyManager kms.KeyManager
 	Crypto     ariescrypto.Crypto
 	Metrics    metricsProvider
+
+	// KeyIDProvider is optional. If set, the key ID (fragment) of the verification method is replaced
+	// with the provider's current key ID so that a rotated key is used without a restart.
+	KeyIDProvider keyIDProvider
+
+	// KeySigner is optional. If set, data is signed by the key signer (e.g. a remote signing service) instead
+	// of with a key handle from the key manager, so that the private key doesn't need to be available locally.
+	KeySigner keySigner
 }
 
 // New returns new instance of VC signer.
@@ -114,48 +123,68 @@ func WithDomain(domain string) Opt {
 
 // Sign will sign verifiable credential.
 func (s *Signer) Sign(vc *verifiable.Credential, opts ...Opt) (*verifiable.Credential, error) {
-	signingCtx, err := s.getLinkedDataProofContext(opts...)
+	info, err := getSuiteInfo(s.params.SignatureSuite)
+	if err != nil {
+		return nil, err
+	}
+
+	signingCtx, err := s.getLinkedDataProofContext(info, opts...)
 	if err != nil {
 		return nil, err
 	}
 
 	addLinkedDataProofStartTime := time.Now()
 
-	err = vc.AddLinkedDataProof(signingCtx, jsonld.WithDocumentLoader(s.Providers.DocLoader))
+	err = vc.AddLinkedDataProof(signingCtx, s.getProcessorOpts()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign vc: %w", err)
 	}
 
+	if info.multibaseProofValue {
+		// The new proof is the last one.
+		err = toMultibaseProofValue(vc.Proofs[len(vc.Proofs)-1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign vc: %w", err)
+		}
+	}
+
 	s.Providers.Metrics.SignerAddLinkedDataProof(time.Since(addLinkedDataProofStartTime))
 
 	return vc, nil
 }
 
-func (s *Signer) getLinkedDataProofContext(opts ...Opt) (*verifiable.LinkedDataProofContext, error) {
-	kmsSigner, err := s.getKMSSigner()
+func (s *Signer) getProcessorOpts() []jsonld.ProcessorOpts {
+	opts := []jsonld.ProcessorOpts{jsonld.WithDocumentLoader(s.Providers.DocLoader)}
+
+	if ctx := Context(s.params.SignatureSuite); ctx != "" {
+		// The terms of the suite aren't defined by the credential so the suite's context is added
+		// during canonicalization.
+		opts = append(opts, jsonld.WithExternalContext(ctx))
+	}
+
+	return opts
+}
+
+func (s *Signer) getLinkedDataProofContext(info *suiteInfo,
+	opts ...Opt) (*verifiable.LinkedDataProofContext, error) {
+	verificationMethod, err := s.getVerificationMethod()
 	if err != nil {
 		return nil, err
 	}
 
-	var signatureSuite ariessigner.SignatureSuite
-
-	switch s.params.SignatureSuite {
-	case Ed25519Signature2018:
-		signatureSuite = ed25519signature2018.New(suite.WithSigner(kmsSigner))
-	case JSONWebSignature2020:
-		signatureSuite = jsonwebsignature2020.New(suite.WithSigner(kmsSigner))
-	default:
-		return nil, fmt.Errorf("signature type not supported: %s", s.params.SignatureSuite)
+	kmsSigner, err := s.getKMSSigner(verificationMethod, info.multiMessage)
+	if err != nil {
+		return nil, err
 	}
 
 	now := time.Now()
 
 	signingCtx := &verifiable.LinkedDataProofContext{
 		Domain:                  s.params.Domain,
-		VerificationMethod:      s.params.VerificationMethod,
-		SignatureRepresentation: verifiable.SignatureJWS,
+		VerificationMethod:      verificationMethod,
+		SignatureRepresentation: info.representation,
 		SignatureType:           s.params.SignatureSuite,
-		Suite:                   signatureSuite,
+		Suite:                   info.newSuite(suite.WithSigner(kmsSigner)),
 		Purpose:                 AssertionMethod,
 		Created:                 &now,
 	}
@@ -167,10 +196,28 @@ func (s *Signer) getLinkedDataProofContext(opts ...Opt) (*verifiable.LinkedDataP
 	return signingCtx, nil
 }
 
+// getVerificationMethod returns the verification method with which to sign.
+func (s *Signer) getVerificationMethod() (string, error) {
+	if s.Providers.KeyIDProvider == nil {
+		return s.params.VerificationMethod, nil
+	}
+
+	keyID, err := s.Providers.KeyIDProvider.CurrentKeyID()
+	if err != nil {
+		return "", fmt.Errorf("get current key ID: %w", err)
+	}
+
+	return strings.Split(s.params.VerificationMethod, "#")[0] + "#" + keyID, nil
+}
+
 // getKMSSigner returns new KMS signer based on verification method.
-func (s *Signer) getKMSSigner() (signer, error) {
-	kmsSigner, err := newKMSSigner(s.Providers.KeyManager, s.Providers.Crypto, s.params.VerificationMethod,
-		s.Providers.Metrics)
+func (s *Signer) getKMSSigner(verificationMethod string, multiMessage bool) (signer, error) {
+	if s.Providers.KeySigner != nil {
+		return newExternalSigner(s.Providers.KeySigner, verificationMethod, s.Providers.Metrics, multiMessage)
+	}
+
+	kmsSigner, err := newKMSSigner(s.Providers.KeyManager, s.Providers.Crypto, verificationMethod,
+		s.Providers.Metrics, multiMessage)
 	if err != nil {
 		return nil, err
 	}
@@ -196,13 +243,14 @@ type signer interface {
 }
 
 type kmsSigner struct {
-	keyHandle interface{}
-	crypto    ariescrypto.Crypto
-	metrics   metricsProvider
+	keyHandle    interface{}
+	crypto       ariescrypto.Crypto
+	metrics      metricsProvider
+	multiMessage bool
 }
 
 func newKMSSigner(keyManager kms.KeyManager, c ariescrypto.Crypto, verificationMethod string,
-	metrics metricsProvider) (*kmsSigner, error) {
+	metrics metricsProvider, multiMessage bool) (*kmsSigner, error) {
 	// verification will contain did key ID
 	keyID, err := getKeyIDFromVerificationMethod(verificationMethod)
 	if err != nil {
@@ -218,7 +266,7 @@ func newKMSSigner(keyManager kms.KeyManager, c ariescrypto.Crypto, verificationM
 
 	metrics.SignerGetKey(time.Since(getKeyStartTime))
 
-	return &kmsSigner{keyHandle: keyHandler, crypto: c, metrics: metrics}, nil
+	return &kmsSigner{keyHandle: keyHandler, crypto: c, metrics: metrics, multiMessage: multiMessage}, nil
 }
 
 // Sign will sign bytes of data.
@@ -226,6 +274,11 @@ func (ks *kmsSigner) Sign(data []byte) ([]byte, error) {
 	startTime := time.Now()
 	defer func() { ks.metrics.SignerSign(time.Since(startTime)) }()
 
+	if ks.multiMessage {
+		// BBS+ signs each statement of the canonical document as a separate message.
+		return ks.crypto.SignMulti(splitMessages(data), ks.keyHandle)
+	}
+
 	v, err := ks.crypto.Sign(data, ks.keyHandle)
 	if err != nil {
 		return nil, err
@@ -233,3 +286,46 @@ func (ks *kmsSigner) Sign(data []byte) ([]byte, error) {
 
 	return v, nil
 }
+
+// externalSigner signs with a key signer which holds the private key.
+type externalSigner struct {
+	keySigner keySigner
+	keyID     string
+	metrics   metricsProvider
+}
+
+func newExternalSigner(ks keySigner, verificationMethod string, metrics metricsProvider,
+	multiMessage bool) (*externalSigner, error) {
+	if multiMessage {
+		return nil, errors.New("multi-message signatures are not supported by the key signer")
+	}
+
+	keyID, err := getKeyIDFromVerificationMethod(verificationMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return &externalSigner{keySigner: ks, keyID: keyID, metrics: metrics}, nil
+}
+
+// Sign will sign bytes of data.
+func (es *externalSigner) Sign(data []byte) ([]byte, error) {
+	startTime := time.Now()
+	defer func() { es.metrics.SignerSign(time.Since(startTime)) }()
+
+	return es.keySigner.Sign(es.keyID, data)
+}
+
+func splitMessages(data []byte) [][]byte {
+	lines := strings.Split(string(data), "\n")
+
+	messages := make([][]byte, 0, len(lines))
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			messages = append(messages, []byte(line))
+		}
+	}
+
+	return messages
+}
@@ -7,24 +7,29 @@ SPDX-License-Identifier: Apache-2.0
 package vcsigner
 
 import (
+	"errors"
 	"fmt"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	ariesverifier "github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	cryptomock "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
 	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	"github.com/stretchr/testify/require"
 
 	"github.com/trustbloc/orb/pkg/internal/testutil"
-	"github.com/trustbloc/orb/pkg/mocks"
+	"github.com/trustbloc/orb/pkg/remotesigner"
+	remotesignermocks "github.com/trustbloc/orb/pkg/remotesigner/mocks"
 )
 
 func TestSigner_New(t *testing.T) {
 	providers := &Providers{
 		KeyManager: &mockkms.KeyManager{},
 		Crypto:     &cryptomock.Crypto{},
-		Metrics:    &mocks.MetricsProvider{},
+		Metrics:    &mockMetrics{},
 	}
 
 	t.Run("success", func(t *testing.T) {
@@ -63,7 +68,7 @@ func TestSigner_Sign(t *testing.T) {
 		KeyManager: &mockkms.KeyManager{},
 		Crypto:     &cryptomock.Crypto{},
 		DocLoader:  testutil.GetLoader(t),
-		Metrics:    &mocks.MetricsProvider{},
+		Metrics:    &mockMetrics{},
 	}
 
 	t.Run("success - JSONWebSignature2020", func(t *testing.T) {
@@ -108,6 +113,143 @@ func TestSigner_Sign(t *testing.T) {
 		require.Equal(t, 1, len(signedVC.Proofs))
 	})
 
+	t.Run("success - BbsBlsSignature2020", func(t *testing.T) {
+		providersWithBBS := &Providers{
+			KeyManager: &mockkms.KeyManager{},
+			Crypto:     &cryptomock.Crypto{BBSSignValue: []byte("bbs signature")},
+			DocLoader:  testutil.GetLoader(t),
+			Metrics:    &mockMetrics{},
+		}
+
+		s, err := New(providersWithBBS, SigningParams{
+			VerificationMethod: "did:abc:123#key1",
+			SignatureSuite:     BbsBlsSignature2020,
+			Domain:             "domain",
+		})
+		require.NoError(t, err)
+
+		vc, err := verifiable.ParseCredential([]byte(testCredential), verifiable.WithDisabledProofCheck(),
+			verifiable.WithJSONLDDocumentLoader(testutil.GetLoader(t)))
+		require.NoError(t, err)
+
+		signedVC, err := s.Sign(vc)
+		require.NoError(t, err)
+		require.Len(t, signedVC.Proofs, 1)
+		require.Equal(t, BbsBlsSignature2020, signedVC.Proofs[0]["type"])
+		require.NotEmpty(t, signedVC.Proofs[0]["proofValue"])
+		require.Empty(t, signedVC.Proofs[0]["jws"])
+	})
+
+	t.Run("success - key signer", func(t *testing.T) {
+		svc := httptest.NewServer(remotesignermocks.NewSignerService())
+		defer svc.Close()
+
+		ks := remotesigner.New(svc.URL, remotesigner.WithHTTPClient(svc.Client()))
+
+		keyID, _, err := ks.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		pubKey, err := ks.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+
+		s, err := New(&Providers{
+			DocLoader: testutil.GetLoader(t),
+			Metrics:   &mockMetrics{},
+			KeySigner: ks,
+		}, SigningParams{
+			VerificationMethod: issuerDID + "#" + keyID,
+			SignatureSuite:     Ed25519Signature2018,
+			Domain:             "domain",
+		})
+		require.NoError(t, err)
+
+		vc, err := verifiable.ParseCredential([]byte(testCredential), verifiable.WithDisabledProofCheck(),
+			verifiable.WithJSONLDDocumentLoader(testutil.GetLoader(t)))
+		require.NoError(t, err)
+
+		signedVC, err := s.Sign(vc)
+		require.NoError(t, err)
+
+		vcBytes, err := signedVC.MarshalJSON()
+		require.NoError(t, err)
+
+		require.NoError(t, VerifyProofs(vcBytes,
+			func(issuerID, keyID string) (*ariesverifier.PublicKey, error) {
+				return &ariesverifier.PublicKey{Type: ed25519VerificationKey2018, Value: pubKey}, nil
+			}, testutil.GetLoader(t)))
+	})
+
+	t.Run("error - key signer", func(t *testing.T) {
+		svc := httptest.NewServer(remotesignermocks.NewSignerService().WithSignError(errors.New("injected error")))
+		defer svc.Close()
+
+		ks := remotesigner.New(svc.URL, remotesigner.WithHTTPClient(svc.Client()))
+
+		s, err := New(&Providers{
+			DocLoader: testutil.GetLoader(t),
+			Metrics:   &mockMetrics{},
+			KeySigner: ks,
+		}, signingParams)
+		require.NoError(t, err)
+
+		signedVC, err := s.Sign(&verifiable.Credential{ID: "http://example.edu/credentials/1872"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected error")
+		require.Nil(t, signedVC)
+
+		s, err = New(&Providers{
+			DocLoader: testutil.GetLoader(t),
+			Metrics:   &mockMetrics{},
+			KeySigner: ks,
+		}, SigningParams{
+			VerificationMethod: "did:abc:123#key1",
+			SignatureSuite:     BbsBlsSignature2020,
+			Domain:             "domain",
+		})
+		require.NoError(t, err)
+
+		signedVC, err = s.Sign(&verifiable.Credential{ID: "http://example.edu/credentials/1872"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "multi-message signatures are not supported by the key signer")
+		require.Nil(t, signedVC)
+	})
+
+	t.Run("success - key ID provider", func(t *testing.T) {
+		providersWithKeyID := &Providers{
+			KeyManager:    &mockkms.KeyManager{},
+			Crypto:        &cryptomock.Crypto{},
+			DocLoader:     testutil.GetLoader(t),
+			Metrics:       &mockMetrics{},
+			KeyIDProvider: &mockKeyIDProvider{keyID: "key2"},
+		}
+
+		s, err := New(providersWithKeyID, signingParams)
+		require.NoError(t, err)
+
+		signedVC, err := s.Sign(&verifiable.Credential{ID: "http://example.edu/credentials/1872"})
+		require.NoError(t, err)
+		require.Len(t, signedVC.Proofs, 1)
+		require.Equal(t, "did:abc:123#key2", signedVC.Proofs[0]["verificationMethod"])
+	})
+
+	t.Run("error - key ID provider", func(t *testing.T) {
+		providersWithKeyIDErr := &Providers{
+			KeyManager:    &mockkms.KeyManager{},
+			Crypto:        &cryptomock.Crypto{},
+			DocLoader:     testutil.GetLoader(t),
+			Metrics:       &mockMetrics{},
+			KeyIDProvider: &mockKeyIDProvider{err: fmt.Errorf("injected key ID error")},
+		}
+
+		s, err := New(providersWithKeyIDErr, signingParams)
+		require.NoError(t, err)
+
+		signedVC, err := s.Sign(&verifiable.Credential{ID: "http://example.edu/credentials/1872"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected key ID error")
+		require.Nil(t, signedVC)
+	})
+
 	t.Run("error - invalid verification method", func(t *testing.T) {
 		invalidSigningParams := SigningParams{
 			VerificationMethod: "key1",
@@ -145,7 +287,7 @@ func TestSigner_Sign(t *testing.T) {
 			KeyManager: &mockkms.KeyManager{},
 			Crypto:     &cryptomock.Crypto{SignErr: fmt.Errorf("failed to sign")},
 			DocLoader:  testutil.GetLoader(t),
-			Metrics:    &mocks.MetricsProvider{},
+			Metrics:    &mockMetrics{},
 		}
 
 		c, err := New(providersWithCryptoErr, signingParams)
@@ -203,3 +345,20 @@ func TestSigner_verifySigningParams(t *testing.T) {
 		require.Contains(t, err.Error(), "missing domain")
 	})
 }
+
+type mockKeyIDProvider struct {
+	keyID string
+	err   error
+}
+
+func (m *mockKeyIDProvider) CurrentKeyID() (string, error) {
+	return m.keyID, m.err
+}
+
+type mockMetrics struct{}
+
+func (m *mockMetrics) SignerSign(time.Duration) {}
+
+func (m *mockMetrics) SignerGetKey(time.Duration) {}
+
+func (m *mockMetrics) SignerAddLinkedDataProof(time.Duration) {}
@@ -0,0 +1,18 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ed25519signature2020
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+)
+
+// NewPublicKeyVerifier creates a signature verifier that verifies an Ed25519 signature taking Ed25519 public
+// key bytes as input. The type of the verification method isn't checked so that keys which are published
+// as either Ed25519VerificationKey2018 or Ed25519VerificationKey2020 may be used.
+func NewPublicKeyVerifier() *verifier.PublicKeyVerifier {
+	return verifier.NewPublicKeyVerifier(verifier.NewEd25519SignatureVerifier())
+}
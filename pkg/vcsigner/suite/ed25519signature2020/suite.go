@@ -0,0 +1,59 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package ed25519signature2020 implements the Ed25519Signature2020 signature suite
+// for the Linked Data Signatures specification. It uses the RDF Dataset Normalization Algorithm
+// to transform the input document into its canonical form, SHA-256 as the message digest algorithm
+// and Ed25519 as the signature algorithm.
+package ed25519signature2020
+
+import (
+	"crypto/sha256"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
+)
+
+// Suite implements the Ed25519Signature2020 signature suite.
+type Suite struct {
+	suite.SignatureSuite
+	jsonldProcessor *jsonld.Processor
+}
+
+const (
+	// SignatureType is the signature type for the Ed25519Signature2020 suite.
+	SignatureType = "Ed25519Signature2020"
+	// ContextURI is the JSON-LD context which defines the terms of the suite.
+	ContextURI = "https://w3id.org/security/suites/ed25519-2020/v1"
+
+	rdfDataSetAlg = "URDNA2015"
+)
+
+// New returns a new Ed25519Signature2020 signature suite.
+func New(opts ...suite.Opt) *Suite {
+	s := &Suite{jsonldProcessor: jsonld.NewProcessor(rdfDataSetAlg)}
+
+	suite.InitSuiteOptions(&s.SignatureSuite, opts...)
+
+	return s
+}
+
+// GetCanonicalDocument returns the normalized (URDNA2015) version of the document.
+func (s *Suite) GetCanonicalDocument(doc map[string]interface{}, opts ...jsonld.ProcessorOpts) ([]byte, error) {
+	return s.jsonldProcessor.GetCanonicalDocument(doc, opts...)
+}
+
+// GetDigest returns the SHA-256 digest of the document.
+func (s *Suite) GetDigest(doc []byte) []byte {
+	digest := sha256.Sum256(doc)
+
+	return digest[:]
+}
+
+// Accept accepts only the Ed25519Signature2020 signature type.
+func (s *Suite) Accept(t string) bool {
+	return t == SignatureType
+}
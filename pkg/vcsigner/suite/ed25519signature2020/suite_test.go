@@ -0,0 +1,63 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ed25519signature2020
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuite_GetCanonicalDocument(t *testing.T) {
+	doc, err := New().GetCanonicalDocument(map[string]interface{}{
+		"@context": map[string]interface{}{
+			"dc": "http://purl.org/dc/terms/",
+		},
+		"@id":      "http://example.org/fact1",
+		"dc:title": "Hello World!",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "<http://example.org/fact1> <http://purl.org/dc/terms/title> \"Hello World!\" .\n", string(doc))
+}
+
+func TestSuite_GetDigest(t *testing.T) {
+	digest := New().GetDigest([]byte("test doc"))
+	require.Len(t, digest, 32)
+}
+
+func TestSuite_Accept(t *testing.T) {
+	s := New()
+	require.True(t, s.Accept("Ed25519Signature2020"))
+	require.False(t, s.Accept("Ed25519Signature2018"))
+}
+
+func TestSuite_Verify(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	msg := []byte("test message")
+	sig := ed25519.Sign(privKey, msg)
+
+	s := New(suite.WithVerifier(NewPublicKeyVerifier()))
+
+	t.Run("Ed25519VerificationKey2020", func(t *testing.T) {
+		require.NoError(t, s.Verify(&verifier.PublicKey{Type: "Ed25519VerificationKey2020", Value: pubKey}, msg, sig))
+	})
+
+	t.Run("Ed25519VerificationKey2018", func(t *testing.T) {
+		require.NoError(t, s.Verify(&verifier.PublicKey{Type: "Ed25519VerificationKey2018", Value: pubKey}, msg, sig))
+	})
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		require.Error(t, s.Verify(&verifier.PublicKey{Type: "Ed25519VerificationKey2020", Value: pubKey},
+			[]byte("other message"), sig))
+	})
+}
@@ -0,0 +1,208 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcsigner
+
+import (
+	"fmt"
+	"sort"
+
+	ariessigner "github.com/hyperledger/aries-framework-go/pkg/doc/signature/signer"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/bbsblssignature2020"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/ecdsasecp256k1signature2019"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/ed25519signature2018"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/jsonwebsignature2020"
+	ariesverifier "github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+
+	"github.com/trustbloc/orb/pkg/vcsigner/suite/ed25519signature2020"
+)
+
+const (
+	// Ed25519Signature2018 ed25519 signature suite.
+	Ed25519Signature2018 = "Ed25519Signature2018"
+	// Ed25519Signature2020 ed25519 signature suite (2020 version).
+	Ed25519Signature2020 = ed25519signature2020.SignatureType
+	// JSONWebSignature2020 json web signature suite.
+	JSONWebSignature2020 = "JsonWebSignature2020"
+	// EcdsaSecp256k1Signature2019 secp256k1 signature suite.
+	EcdsaSecp256k1Signature2019 = "EcdsaSecp256k1Signature2019"
+	// BbsBlsSignature2020 BBS+ signature suite.
+	BbsBlsSignature2020 = "BbsBlsSignature2020"
+)
+
+const (
+	ed25519VerificationKey2018        = "Ed25519VerificationKey2018"
+	ed25519VerificationKey2020        = "Ed25519VerificationKey2020"
+	ecdsaSecp256k1VerificationKey2019 = "EcdsaSecp256k1VerificationKey2019"
+	bls12381G2Key2020                 = "Bls12381G2Key2020"
+	jsonWebKey2020                    = "JsonWebKey2020"
+
+	bbsContext = "https://w3id.org/security/bbs/v1"
+)
+
+type signatureSuite interface {
+	ariessigner.SignatureSuite
+	ariesverifier.SignatureSuite
+}
+
+// suiteInfo contains everything that's needed to sign and verify with a signature suite.
+type suiteInfo struct {
+	keyType                kms.KeyType
+	verificationMethodType string
+	// context is the JSON-LD context which defines the terms of the suite. It's only set if the terms
+	// aren't already defined by the contexts of an anchor credential.
+	context        string
+	representation verifiable.SignatureRepresentation
+	// multiMessage indicates that the canonical document is signed as a set of messages (one per statement).
+	multiMessage bool
+	// multibaseProofValue indicates that the proof value is encoded as a multibase (base58btc) string
+	// instead of base64url.
+	multibaseProofValue bool
+	newSuite            func(opts ...suite.Opt) signatureSuite
+	newVerifier         func() *ariesverifier.PublicKeyVerifier
+}
+
+//nolint:gochecknoglobals
+var suites = map[string]*suiteInfo{
+	Ed25519Signature2018: {
+		keyType:                kms.ED25519Type,
+		verificationMethodType: ed25519VerificationKey2018,
+		representation:         verifiable.SignatureJWS,
+		newSuite: func(opts ...suite.Opt) signatureSuite {
+			return ed25519signature2018.New(opts...)
+		},
+		newVerifier: func() *ariesverifier.PublicKeyVerifier {
+			return ed25519signature2018.NewPublicKeyVerifier()
+		},
+	},
+	Ed25519Signature2020: {
+		keyType:                kms.ED25519Type,
+		verificationMethodType: ed25519VerificationKey2020,
+		context:                ed25519signature2020.ContextURI,
+		representation:         verifiable.SignatureProofValue,
+		multibaseProofValue:    true,
+		newSuite: func(opts ...suite.Opt) signatureSuite {
+			return ed25519signature2020.New(opts...)
+		},
+		newVerifier: func() *ariesverifier.PublicKeyVerifier {
+			return ed25519signature2020.NewPublicKeyVerifier()
+		},
+	},
+	JSONWebSignature2020: {
+		keyType:                kms.ED25519Type,
+		verificationMethodType: jsonWebKey2020,
+		representation:         verifiable.SignatureJWS,
+		newSuite: func(opts ...suite.Opt) signatureSuite {
+			return jsonwebsignature2020.New(opts...)
+		},
+		newVerifier: func() *ariesverifier.PublicKeyVerifier {
+			return jsonwebsignature2020.NewPublicKeyVerifier()
+		},
+	},
+	EcdsaSecp256k1Signature2019: {
+		keyType:                kms.ECDSASecp256k1TypeIEEEP1363,
+		verificationMethodType: ecdsaSecp256k1VerificationKey2019,
+		representation:         verifiable.SignatureJWS,
+		newSuite: func(opts ...suite.Opt) signatureSuite {
+			return ecdsasecp256k1signature2019.New(opts...)
+		},
+		newVerifier: func() *ariesverifier.PublicKeyVerifier {
+			return ecdsasecp256k1signature2019.NewPublicKeyVerifier()
+		},
+	},
+	BbsBlsSignature2020: {
+		keyType:                kms.BLS12381G2Type,
+		verificationMethodType: bls12381G2Key2020,
+		context:                bbsContext,
+		representation:         verifiable.SignatureProofValue,
+		multiMessage:           true,
+		newSuite: func(opts ...suite.Opt) signatureSuite {
+			return bbsblssignature2020.New(opts...)
+		},
+		newVerifier: func() *ariesverifier.PublicKeyVerifier {
+			return bbsblssignature2020.NewG2PublicKeyVerifier()
+		},
+	},
+}
+
+func getSuiteInfo(signatureSuite string) (*suiteInfo, error) {
+	info, ok := suites[signatureSuite]
+	if !ok {
+		return nil, fmt.Errorf("signature type not supported: %s", signatureSuite)
+	}
+
+	return info, nil
+}
+
+// KeyType returns the type of KMS key that's required to sign with the given signature suite.
+func KeyType(signatureSuite string) (kms.KeyType, error) {
+	info, err := getSuiteInfo(signatureSuite)
+	if err != nil {
+		return "", err
+	}
+
+	return info.keyType, nil
+}
+
+// VerificationMethodType returns the type of verification method under which the public key of a
+// signing key for the given signature suite is published.
+func VerificationMethodType(signatureSuite string) (string, error) {
+	info, err := getSuiteInfo(signatureSuite)
+	if err != nil {
+		return "", err
+	}
+
+	return info.verificationMethodType, nil
+}
+
+// Context returns the JSON-LD context which defines the terms of the given signature suite or an empty string
+// if the terms are already defined by the contexts of an anchor credential (or the suite isn't supported).
+func Context(signatureSuite string) string {
+	info, ok := suites[signatureSuite]
+	if !ok {
+		return ""
+	}
+
+	return info.context
+}
+
+// IsSupported returns true if the given signature suite may be used to sign and verify anchor credentials.
+func IsSupported(signatureSuite string) bool {
+	_, ok := suites[signatureSuite]
+
+	return ok
+}
+
+// suiteContexts returns the contexts of all of the supported suites which aren't defined by the contexts
+// of an anchor credential. These contexts are added to the document during canonicalization so that the
+// proof terms are defined.
+func suiteContexts() []string {
+	var contexts []string
+
+	for _, info := range suites {
+		if info.context != "" {
+			contexts = append(contexts, info.context)
+		}
+	}
+
+	sort.Strings(contexts)
+
+	return contexts
+}
+
+// verificationSuites returns all of the supported signature suites initialized for verification.
+func verificationSuites() []ariesverifier.SignatureSuite {
+	verificationSuites := make([]ariesverifier.SignatureSuite, 0, len(suites))
+
+	for _, info := range suites {
+		verificationSuites = append(verificationSuites, info.newSuite(suite.WithVerifier(info.newVerifier())))
+	}
+
+	return verificationSuites
+}
@@ -0,0 +1,71 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcsigner
+
+import (
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyType(t *testing.T) {
+	keyType, err := KeyType(Ed25519Signature2020)
+	require.NoError(t, err)
+	require.Equal(t, kms.ED25519Type, keyType)
+
+	keyType, err = KeyType(EcdsaSecp256k1Signature2019)
+	require.NoError(t, err)
+	require.Equal(t, kms.ECDSASecp256k1TypeIEEEP1363, keyType)
+
+	keyType, err = KeyType(BbsBlsSignature2020)
+	require.NoError(t, err)
+	require.Equal(t, kms.BLS12381G2Type, keyType)
+
+	_, err = KeyType("invalid")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "signature type not supported: invalid")
+}
+
+func TestVerificationMethodType(t *testing.T) {
+	vmType, err := VerificationMethodType(Ed25519Signature2018)
+	require.NoError(t, err)
+	require.Equal(t, "Ed25519VerificationKey2018", vmType)
+
+	vmType, err = VerificationMethodType(Ed25519Signature2020)
+	require.NoError(t, err)
+	require.Equal(t, "Ed25519VerificationKey2020", vmType)
+
+	vmType, err = VerificationMethodType(EcdsaSecp256k1Signature2019)
+	require.NoError(t, err)
+	require.Equal(t, "EcdsaSecp256k1VerificationKey2019", vmType)
+
+	vmType, err = VerificationMethodType(BbsBlsSignature2020)
+	require.NoError(t, err)
+	require.Equal(t, "Bls12381G2Key2020", vmType)
+
+	_, err = VerificationMethodType("invalid")
+	require.Error(t, err)
+}
+
+func TestContext(t *testing.T) {
+	require.Empty(t, Context(Ed25519Signature2018))
+	require.Empty(t, Context(JSONWebSignature2020))
+	require.Empty(t, Context(EcdsaSecp256k1Signature2019))
+	require.Equal(t, "https://w3id.org/security/suites/ed25519-2020/v1", Context(Ed25519Signature2020))
+	require.Equal(t, "https://w3id.org/security/bbs/v1", Context(BbsBlsSignature2020))
+	require.Empty(t, Context("invalid"))
+}
+
+func TestIsSupported(t *testing.T) {
+	require.True(t, IsSupported(Ed25519Signature2018))
+	require.True(t, IsSupported(Ed25519Signature2020))
+	require.True(t, IsSupported(JSONWebSignature2020))
+	require.True(t, IsSupported(EcdsaSecp256k1Signature2019))
+	require.True(t, IsSupported(BbsBlsSignature2020))
+	require.False(t, IsSupported("RsaSignature2018"))
+}
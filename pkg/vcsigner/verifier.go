@@ -0,0 +1,85 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcsigner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
+	ariesverifier "github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/piprate/json-gold/ld"
+)
+
+// VerifyProofs verifies all of the linked data proofs (i.e. the proof of the issuer and the proofs of the
+// witnesses) of the given credential using any of the signature suites supported by the signer. This function
+// should be used instead of the proof check in verifiable.ParseCredential since the latter only supports
+// a fixed set of signature suites.
+func VerifyProofs(vcBytes []byte, pkf verifiable.PublicKeyFetcher, docLoader ld.DocumentLoader) error {
+	var doc map[string]interface{}
+
+	err := json.Unmarshal(vcBytes, &doc)
+	if err != nil {
+		return fmt.Errorf("unmarshal credential: %w", err)
+	}
+
+	if p, ok := doc["proof"]; !ok || p == nil {
+		// Proofs aren't mandatory.
+		return nil
+	}
+
+	if pkf == nil {
+		return errors.New("public key fetcher is not defined")
+	}
+
+	err = fromMultibaseProofValues(doc)
+	if err != nil {
+		return err
+	}
+
+	vcBytes, err = json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("marshal credential: %w", err)
+	}
+
+	v, err := ariesverifier.New(&keyResolver{pkf: pkf}, verificationSuites()...)
+	if err != nil {
+		return fmt.Errorf("create verifier: %w", err)
+	}
+
+	opts := []jsonld.ProcessorOpts{jsonld.WithExternalContext(suiteContexts()...)}
+
+	if docLoader != nil {
+		opts = append(opts, jsonld.WithDocumentLoader(docLoader))
+	}
+
+	err = v.Verify(vcBytes, opts...)
+	if err != nil {
+		return fmt.Errorf("check linked data proof: %w", err)
+	}
+
+	return nil
+}
+
+// keyResolver resolves a verification method (did#keyID) using a public key fetcher.
+type keyResolver struct {
+	pkf verifiable.PublicKeyFetcher
+}
+
+func (r *keyResolver) Resolve(id string) (*ariesverifier.PublicKey, error) {
+	const partNum = 2
+
+	parts := strings.Split(id, "#")
+	if len(parts) != partNum {
+		return nil, fmt.Errorf("invalid verification method format: %s", id)
+	}
+
+	return r.pkf(parts[0], "#"+parts[1])
+}
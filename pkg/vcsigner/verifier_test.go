@@ -0,0 +1,405 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vcsigner
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	ariescrypto "github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jose/jwk/jwksupport"
+	ariesverifier "github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/util/signature"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/multiformats/go-multibase"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+const (
+	issuerDID  = "did:web:orb.domain1.com"
+	witnessDID = "did:web:orb.domain2.com"
+
+	testCredential = `{
+  "@context": ["https://www.w3.org/2018/credentials/v1", "https://w3id.org/security/jws/v1"],
+  "id": "http://example.edu/credentials/1872",
+  "type": "VerifiableCredential",
+  "issuer": "did:web:orb.domain1.com",
+  "issuanceDate": "2021-01-27T09:30:10Z",
+  "credentialSubject": {"id": "did:example:ebfeb1f712ebc6f1c276e12ec21"}
+}`
+)
+
+func TestVerifyProofs(t *testing.T) {
+	km, cr := newTestKMSAndCrypto(t)
+
+	loader := testutil.GetLoader(t)
+
+	allSuites := []string{
+		Ed25519Signature2018, Ed25519Signature2020, JSONWebSignature2020, EcdsaSecp256k1Signature2019,
+		BbsBlsSignature2020,
+	}
+
+	for _, signatureSuite := range allSuites {
+		signatureSuite := signatureSuite
+
+		t.Run(signatureSuite, func(t *testing.T) {
+			keys := make(map[string]*ariesverifier.PublicKey)
+
+			pkf := func(issuerID, keyID string) (*ariesverifier.PublicKey, error) {
+				pubKey, ok := keys[issuerID+keyID]
+				if !ok {
+					return nil, fmt.Errorf("key not found: %s%s", issuerID, keyID)
+				}
+
+				return pubKey, nil
+			}
+
+			// The issuer signs with the suite under test and a witness adds its proof using a different suite.
+			issuerSigner := newTestSigner(t, km, cr, issuerDID, signatureSuite, keys)
+			witnessSigner := newTestSigner(t, km, cr, witnessDID, EcdsaSecp256k1Signature2019, keys)
+
+			vc, err := verifiable.ParseCredential([]byte(testCredential), verifiable.WithDisabledProofCheck(),
+				verifiable.WithJSONLDDocumentLoader(loader))
+			require.NoError(t, err)
+
+			vc, err = issuerSigner.Sign(vc)
+			require.NoError(t, err)
+			require.Len(t, vc.Proofs, 1)
+			require.Equal(t, signatureSuite, vc.Proofs[0]["type"])
+
+			if signatureSuite == Ed25519Signature2020 {
+				// The proof value is a multibase (base58btc) string.
+				require.True(t, strings.HasPrefix(vc.Proofs[0]["proofValue"].(string), "z"))
+			}
+
+			vc, err = witnessSigner.Sign(vc, WithDomain("https://witness.domain2.com"))
+			require.NoError(t, err)
+			require.Len(t, vc.Proofs, 2)
+
+			vcBytes, err := vc.MarshalJSON()
+			require.NoError(t, err)
+
+			require.NoError(t, VerifyProofs(vcBytes, pkf, loader))
+
+			// The credential may still be parsed without a proof check (e.g. by the stores and the monitoring service).
+			_, err = verifiable.ParseCredential(vcBytes, verifiable.WithDisabledProofCheck(),
+				verifiable.WithJSONLDDocumentLoader(loader))
+			require.NoError(t, err)
+
+			// Tamper with the credential.
+			vc.ID = "http://example.edu/credentials/1873"
+
+			vcBytes, err = vc.MarshalJSON()
+			require.NoError(t, err)
+
+			require.Error(t, VerifyProofs(vcBytes, pkf, loader))
+		})
+	}
+
+	t.Run("No proof", func(t *testing.T) {
+		require.NoError(t, VerifyProofs([]byte(testCredential), nil, loader))
+	})
+
+	t.Run("No public key fetcher", func(t *testing.T) {
+		vcBytes, err := signTestCredential(t, km, cr)
+		require.NoError(t, err)
+
+		err = VerifyProofs(vcBytes, nil, loader)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "public key fetcher is not defined")
+	})
+
+	t.Run("Public key fetcher error", func(t *testing.T) {
+		vcBytes, err := signTestCredential(t, km, cr)
+		require.NoError(t, err)
+
+		err = VerifyProofs(vcBytes, func(issuerID, keyID string) (*ariesverifier.PublicKey, error) {
+			return nil, errors.New("injected fetcher error")
+		}, loader)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected fetcher error")
+	})
+
+	t.Run("Unsupported proof type", func(t *testing.T) {
+		err := VerifyProofs([]byte(`{"id":"https://example.com/vc1","proof":{"type":"Unsupported","created":"2021-01-27T09:30:10Z","proofValue":"abc",`+
+			`"verificationMethod":"did:web:orb.domain1.com#key1"}}`),
+			func(issuerID, keyID string) (*ariesverifier.PublicKey, error) {
+				return &ariesverifier.PublicKey{}, nil
+			}, loader)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Unsupported")
+	})
+
+	t.Run("Invalid verification method", func(t *testing.T) {
+		err := VerifyProofs([]byte(`{"id":"https://example.com/vc1","proof":{"type":"Ed25519Signature2018","created":"2021-01-27T09:30:10Z","proofValue":"abc",`+
+			`"verificationMethod":"key1"}}`),
+			func(issuerID, keyID string) (*ariesverifier.PublicKey, error) {
+				return &ariesverifier.PublicKey{}, nil
+			}, loader)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid verification method format")
+	})
+
+	t.Run("Invalid credential", func(t *testing.T) {
+		err := VerifyProofs([]byte("{"), nil, loader)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal credential")
+	})
+}
+
+func TestVerifyProofs_Ed25519Signature2020(t *testing.T) {
+	// Key pair from RFC 8032, section 7.1, TEST 1.
+	const (
+		secretKeyHex = "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60"
+		publicKeyHex = "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"
+	)
+
+	seed, err := hex.DecodeString(secretKeyHex)
+	require.NoError(t, err)
+
+	privKey := ed25519.NewKeyFromSeed(seed)
+
+	pubKey, err := hex.DecodeString(publicKeyHex)
+	require.NoError(t, err)
+	require.Equal(t, pubKey, []byte(privKey.Public().(ed25519.PublicKey)))
+
+	loader := testutil.GetLoader(t)
+
+	pkf := func(issuerID, keyID string) (*ariesverifier.PublicKey, error) {
+		return &ariesverifier.PublicKey{Type: "Ed25519VerificationKey2020", Value: pubKey}, nil
+	}
+
+	s, err := New(&Providers{
+		DocLoader: loader,
+		Metrics:   &mockMetrics{},
+		KeySigner: &ed25519KeySigner{privKey: privKey},
+	}, SigningParams{
+		VerificationMethod: issuerDID + "#key1",
+		SignatureSuite:     Ed25519Signature2020,
+		Domain:             "https://witness.domain1.com",
+	})
+	require.NoError(t, err)
+
+	vc, err := verifiable.ParseCredential([]byte(testCredential), verifiable.WithDisabledProofCheck(),
+		verifiable.WithJSONLDDocumentLoader(loader))
+	require.NoError(t, err)
+
+	created, err := time.Parse(time.RFC3339, "2021-01-27T09:30:10Z")
+	require.NoError(t, err)
+
+	vc, err = s.Sign(vc, WithCreated(created))
+	require.NoError(t, err)
+	require.Len(t, vc.Proofs, 1)
+
+	proofValue, ok := vc.Proofs[0]["proofValue"].(string)
+	require.True(t, ok)
+
+	encoding, sig, err := multibase.Decode(proofValue)
+	require.NoError(t, err)
+	require.Equal(t, multibase.Encoding(multibase.Base58BTC), encoding)
+	require.Len(t, sig, ed25519.SignatureSize)
+
+	t.Run("Success", func(t *testing.T) {
+		vcBytes, err := vc.MarshalJSON()
+		require.NoError(t, err)
+
+		require.NoError(t, VerifyProofs(vcBytes, pkf, loader))
+	})
+
+	t.Run("base64url proof value", func(t *testing.T) {
+		vcBytes := withProofValue(t, vc, base64.RawURLEncoding.EncodeToString(sig))
+
+		err := VerifyProofs(vcBytes, pkf, loader)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode multibase proof value")
+	})
+
+	t.Run("Not base58btc", func(t *testing.T) {
+		encoded, err := multibase.Encode(multibase.Base64url, sig)
+		require.NoError(t, err)
+
+		err = VerifyProofs(withProofValue(t, vc, encoded), pkf, loader)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not base58btc encoded")
+	})
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		invalidSig := append([]byte{}, sig...)
+		invalidSig[0] ^= 0xff
+
+		encoded, err := multibase.Encode(multibase.Base58BTC, invalidSig)
+		require.NoError(t, err)
+
+		require.Error(t, VerifyProofs(withProofValue(t, vc, encoded), pkf, loader))
+	})
+}
+
+// withProofValue returns the given credential (marshalled) with the proof value of its first proof replaced.
+func withProofValue(t *testing.T, vc *verifiable.Credential, proofValue string) []byte {
+	t.Helper()
+
+	vcBytes, err := vc.MarshalJSON()
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(vcBytes, &doc))
+
+	doc["proof"].(map[string]interface{})["proofValue"] = proofValue
+
+	vcBytes, err = json.Marshal(doc)
+	require.NoError(t, err)
+
+	return vcBytes
+}
+
+// ed25519KeySigner signs with the given Ed25519 private key.
+type ed25519KeySigner struct {
+	privKey ed25519.PrivateKey
+}
+
+func (s *ed25519KeySigner) Sign(_ string, msg []byte) ([]byte, error) {
+	return ed25519.Sign(s.privKey, msg), nil
+}
+
+func signTestCredential(t *testing.T, km kms.KeyManager, cr ariescrypto.Crypto) ([]byte, error) {
+	t.Helper()
+
+	s := newTestSigner(t, km, cr, issuerDID, Ed25519Signature2018, make(map[string]*ariesverifier.PublicKey))
+
+	vc, err := verifiable.ParseCredential([]byte(testCredential), verifiable.WithDisabledProofCheck(),
+		verifiable.WithJSONLDDocumentLoader(testutil.GetLoader(t)))
+	require.NoError(t, err)
+
+	vc, err = s.Sign(vc)
+	require.NoError(t, err)
+
+	return vc.MarshalJSON()
+}
+
+// newTestSigner creates a key of the type required by the given suite and registers its public key
+// in the given map (keyed by verification method).
+func newTestSigner(t *testing.T, km kms.KeyManager, cr ariescrypto.Crypto, did, signatureSuite string,
+	keys map[string]*ariesverifier.PublicKey) *Signer {
+	t.Helper()
+
+	keyType, err := KeyType(signatureSuite)
+	require.NoError(t, err)
+
+	keyID, _, err := km.Create(keyType)
+	require.NoError(t, err)
+
+	pubKeyBytes, err := km.ExportPubKeyBytes(keyID)
+	require.NoError(t, err)
+
+	verificationMethodType, err := VerificationMethodType(signatureSuite)
+	require.NoError(t, err)
+
+	pubKey := &ariesverifier.PublicKey{
+		Type:  verificationMethodType,
+		Value: pubKeyBytes,
+	}
+
+	if signatureSuite == JSONWebSignature2020 {
+		pubKey.JWK, err = jwksupport.JWKFromKey(ed25519.PublicKey(pubKeyBytes))
+		require.NoError(t, err)
+	}
+
+	keys[did+"#"+keyID] = pubKey
+
+	s, err := New(&Providers{
+		KeyManager: km,
+		Crypto:     cr,
+		DocLoader:  testutil.GetLoader(t),
+		Metrics:    &mockMetrics{},
+	}, SigningParams{
+		VerificationMethod: did + "#" + keyID,
+		SignatureSuite:     signatureSuite,
+		Domain:             "https://witness.domain1.com",
+	})
+	require.NoError(t, err)
+
+	return s
+}
+
+func newTestKMSAndCrypto(t *testing.T) (*testKMS, *testCrypto) {
+	t.Helper()
+
+	km, err := localkms.New("local-lock://custom/master/key/",
+		mockkms.NewProviderForKMS(mockstorage.NewMockStoreProvider(), &noop.NoLock{}))
+	require.NoError(t, err)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	return &testKMS{KeyManager: km, secp256k1Keys: make(map[string]signature.Signer)}, &testCrypto{Crypto: cr}
+}
+
+// testKMS adds support for secp256k1 keys (which aren't supported by the local KMS) to a KMS.
+type testKMS struct {
+	kms.KeyManager
+	secp256k1Keys map[string]signature.Signer
+}
+
+func (m *testKMS) Create(kt kms.KeyType) (string, interface{}, error) {
+	if kt != kms.ECDSASecp256k1TypeIEEEP1363 {
+		return m.KeyManager.Create(kt)
+	}
+
+	s, err := signature.NewSigner(kt)
+	if err != nil {
+		return "", nil, err
+	}
+
+	keyID := fmt.Sprintf("secp256k1-%d", len(m.secp256k1Keys)+1)
+
+	m.secp256k1Keys[keyID] = s
+
+	return keyID, s, nil
+}
+
+func (m *testKMS) Get(keyID string) (interface{}, error) {
+	if s, ok := m.secp256k1Keys[keyID]; ok {
+		return s, nil
+	}
+
+	return m.KeyManager.Get(keyID)
+}
+
+func (m *testKMS) ExportPubKeyBytes(keyID string) ([]byte, error) {
+	if s, ok := m.secp256k1Keys[keyID]; ok {
+		return s.PublicKeyBytes(), nil
+	}
+
+	return m.KeyManager.ExportPubKeyBytes(keyID)
+}
+
+type testCrypto struct {
+	ariescrypto.Crypto
+}
+
+func (c *testCrypto) Sign(msg []byte, kh interface{}) ([]byte, error) {
+	if s, ok := kh.(signature.Signer); ok {
+		return s.Sign(msg)
+	}
+
+	return c.Crypto.Sign(msg, kh)
+}